@@ -29,7 +29,12 @@ var accountCMD = &cli.Command{
 		},
 	},
 	Action: func(ctx context.Context, command *cli.Command) error {
-		keys, err := save.CreateReadKeyMap()
+		settings, err := save.SettingsFromDisk()
+		if err != nil {
+			return fmt.Errorf("failed to read settings file: %w", err)
+		}
+
+		keys, err := save.CreateReadKeyMap(settings.KeymapProfile)
 		if err != nil {
 			return fmt.Errorf("failed to read keymap file: %w", err)
 		}
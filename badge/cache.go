@@ -6,6 +6,7 @@ import (
 
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/spf13/afero"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -22,41 +23,70 @@ type Cache struct {
 	l      *sync.RWMutex
 
 	fetcher BadgeFetcher
+
+	// fetched sets are stored in dir and used when Twitch can't be reached, disabled when fs is nil
+	fs  afero.Fs
+	dir string
+}
+
+type CacheOption func(*Cache)
+
+// WithDiskCache stores the fetched badge sets in dir. When Twitch can't be reached,
+// the badge sets stored on the last successful fetch are used instead.
+func WithDiskCache(fs afero.Fs, dir string) CacheOption {
+	return func(c *Cache) {
+		c.fs = fs
+		c.dir = dir
+	}
 }
 
-func NewCache(fetcher BadgeFetcher) *Cache {
-	return &Cache{
+func NewCache(fetcher BadgeFetcher, opts ...CacheOption) *Cache {
+	c := &Cache{
 		l:             &sync.RWMutex{},
 		fetcher:       fetcher,
 		single:        &singleflight.Group{},
 		channelBadges: make(map[string][]twitchapi.BadgeSet),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 func (c *Cache) RefreshGlobal(ctx context.Context) error {
-	badges, err := c.fetcher.GetGlobalChatBadges(ctx)
-	if err != nil {
-		return err
+	badges, err := c.fetchOrStored("global", func() ([]twitchapi.BadgeSet, error) {
+		return c.fetcher.GetGlobalChatBadges(ctx)
+	})
+
+	// stored badges are used even if the fetch failed, unless newer badges were already fetched
+	if badges != nil {
+		c.l.Lock()
+		if err == nil || c.globalBadges == nil {
+			c.globalBadges = badges
+		}
+		c.l.Unlock()
 	}
 
-	c.l.Lock()
-	c.globalBadges = badges
-	c.l.Unlock()
-	return nil
+	return err
 }
 
 func (c *Cache) RefreshChannel(ctx context.Context, broadcasterID string) error {
 	_, err, _ := c.single.Do(broadcasterID, func() (any, error) {
-		badges, err := c.fetcher.GetChannelChatBadges(ctx, broadcasterID)
-		if err != nil {
-			return nil, err
+		badges, err := c.fetchOrStored("channel_"+broadcasterID, func() ([]twitchapi.BadgeSet, error) {
+			return c.fetcher.GetChannelChatBadges(ctx, broadcasterID)
+		})
+
+		if badges != nil {
+			c.l.Lock()
+			if _, ok := c.channelBadges[broadcasterID]; err == nil || !ok {
+				c.channelBadges[broadcasterID] = badges
+			}
+			c.l.Unlock()
 		}
 
-		c.l.Lock()
-		c.channelBadges[broadcasterID] = badges
-		c.l.Unlock()
-
-		return nil, nil
+		return nil, err
 	})
 
 	return err
@@ -0,0 +1,63 @@
+package badge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFetcher struct {
+	global  []twitchapi.BadgeSet
+	channel []twitchapi.BadgeSet
+	err     error
+}
+
+func (f *fakeFetcher) GetGlobalChatBadges(context.Context) ([]twitchapi.BadgeSet, error) {
+	return f.global, f.err
+}
+
+func (f *fakeFetcher) GetChannelChatBadges(context.Context, string) ([]twitchapi.BadgeSet, error) {
+	return f.channel, f.err
+}
+
+func TestCache_StoredSets(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	fetcher := &fakeFetcher{
+		global:  []twitchapi.BadgeSet{{ID: "moderator", Versions: []twitchapi.BadgeVersion{{ID: "1", Title: "Moderator"}}}},
+		channel: []twitchapi.BadgeSet{{ID: "subscriber", Versions: []twitchapi.BadgeVersion{{ID: "6", Title: "6-Month Subscriber"}}}},
+	}
+
+	ircBadges := []twitchirc.Badge{{Name: "moderator", Version: "1"}, {Name: "subscriber", Version: "6"}}
+
+	cache := NewCache(fetcher, WithDiskCache(fs, "badge_sets"))
+	require.NoError(t, cache.RefreshGlobal(t.Context()))
+	require.NoError(t, cache.RefreshChannel(t.Context(), "1234"))
+	require.Len(t, cache.MatchBadgeSet("1234", ircBadges), 2)
+
+	// Twitch can't be reached, a new cache uses the stored sets but still reports the error
+	fetcher.err = errors.New("unreachable")
+	cache = NewCache(fetcher, WithDiskCache(fs, "badge_sets"))
+	require.ErrorIs(t, cache.RefreshGlobal(t.Context()), fetcher.err)
+	require.ErrorIs(t, cache.RefreshChannel(t.Context(), "1234"), fetcher.err)
+
+	matched := cache.MatchBadgeSet("1234", ircBadges)
+	require.Equal(t, "Moderator", matched["moderator"].Title)
+	require.Equal(t, "6-Month Subscriber", matched["subscriber"].Title)
+
+	// no sets were stored for other channels
+	require.ErrorIs(t, cache.RefreshChannel(t.Context(), "5678"), fetcher.err)
+	require.Len(t, cache.MatchBadgeSet("5678", ircBadges), 1)
+
+	// sets stored in another format version are ignored
+	require.NoError(t, afero.WriteFile(fs, "badge_sets/global.json", []byte(`{"version":0,"sets":[{"set_id":"moderator","versions":[{"id":"1"}]}]}`), 0o600))
+	cache = NewCache(fetcher, WithDiskCache(fs, "badge_sets"))
+	require.ErrorIs(t, cache.RefreshGlobal(t.Context()), fetcher.err)
+	require.Empty(t, cache.MatchBadgeSet("", ircBadges))
+}
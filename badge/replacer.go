@@ -9,11 +9,13 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/julez-dev/chatuino/contributor"
 	"github.com/julez-dev/chatuino/kittyimg"
 	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
 	"github.com/rs/zerolog/log"
@@ -22,6 +24,25 @@ import (
 // badgePadding is the number of transparent pixels added to the right of each badge.
 const badgePadding = 1
 
+// roleBadges are the badge sets shown when only role badges are enabled
+var roleBadges = []string{"broadcaster", "lead_moderator", "moderator", "vip"}
+
+// defaultGlyphs are the short text glyphs used instead of badge names when glyphs are enabled.
+// Badges without a glyph use the first letter of their title.
+var defaultGlyphs = map[string]string{
+	"broadcaster":    "◆",
+	"lead_moderator": "■",
+	"moderator":      "■",
+	"vip":            "♦",
+	"subscriber":     "●",
+	"Turbo":          "▲",
+	"admin":          "▣",
+	"staff":          "▣",
+	"no_audio":       "○",
+	"!chatuino":      "◇",
+	sevenTVBadgeKey:  "7",
+}
+
 type BadgeCache interface {
 	MatchBadgeSet(broadcasterID string, ircBadge []twitchirc.Badge) map[string]twitchapi.BadgeVersion
 }
@@ -35,10 +56,12 @@ type Replacer struct {
 	enableGraphics bool
 	cache          BadgeCache
 	displayManager DisplayManager
-	badeColorMap   map[string]string
+	settings       save.BadgeSettings
+	badgeNames     map[string]string
+	badgeStyles    map[string]lipgloss.Style
 }
 
-func NewReplacer(httpClient *http.Client, cache BadgeCache, enableGraphics bool, theme save.Theme, displayManager DisplayManager) *Replacer {
+func NewReplacer(httpClient *http.Client, cache BadgeCache, enableGraphics bool, theme save.Theme, settings save.BadgeSettings, displayManager DisplayManager) *Replacer {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
@@ -48,35 +71,79 @@ func NewReplacer(httpClient *http.Client, cache BadgeCache, enableGraphics bool,
 		cache:          cache,
 		httpClient:     httpClient,
 		displayManager: displayManager,
-		badeColorMap: map[string]string{
-			"broadcaster": lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatStreamerColor)).Render("Streamer"),
-			"no_audio":    "No Audio",
-			"vip":         lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatVIPColor)).Render("VIP"),
-			"subscriber":  lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatSubColor)).Render("Sub"),
-			"admin":       "Admin",
-			"staff":       "Staff",
-			"Turbo":       lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatTurboColor)).Render("Turbo"),
-			"moderator":   lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatModeratorColor)).Render("Mod"),
+		settings:       settings,
+		badgeNames: map[string]string{
+			"broadcaster":    "Streamer",
+			"no_audio":       "No Audio",
+			"vip":            "VIP",
+			"subscriber":     "Sub",
+			"admin":          "Admin",
+			"staff":          "Staff",
+			"Turbo":          "Turbo",
+			"moderator":      "Mod",
+			"lead_moderator": "Lead Mod",
+		},
+		badgeStyles: map[string]lipgloss.Style{
+			"broadcaster":    lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatStreamerColor)),
+			"vip":            lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatVIPColor)),
+			"subscriber":     lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatSubColor)),
+			"Turbo":          lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatTurboColor)),
+			"moderator":      lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatModeratorColor)),
+			"lead_moderator": lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatModeratorColor)),
 		},
 	}
 }
 
+// isVisible reports if badges of the set should be shown according to the settings
+func (r *Replacer) isVisible(setID string) bool {
+	switch r.settings.Show {
+	case save.BadgeShowNone:
+		return false
+	case save.BadgeShowRoles:
+		return slices.Contains(roleBadges, setID)
+	default:
+		return true
+	}
+}
+
+// textBadge returns the text shown for a badge when graphic badges are disabled
+func (r *Replacer) textBadge(setID, title string) string {
+	text := title
+	if name, ok := r.badgeNames[setID]; ok {
+		text = name
+	}
+
+	if r.settings.Glyphs {
+		if glyph, ok := defaultGlyphs[setID]; ok {
+			text = glyph
+		} else if first, _ := utf8.DecodeRuneInString(title); first != utf8.RuneError {
+			text = strings.ToUpper(string(first))
+		}
+	}
+
+	if custom, ok := r.settings.CustomGlyphs[setID]; ok {
+		text = custom
+	}
+
+	if style, ok := r.badgeStyles[setID]; ok {
+		return style.Render(text)
+	}
+
+	return text
+}
+
 func (r *Replacer) Replace(broadcasterID string, badgeList []twitchirc.Badge) (string, map[string]string, error) {
 	badgeMap := r.cache.MatchBadgeSet(broadcasterID, badgeList)
+	maps.DeleteFunc(badgeMap, func(setID string, _ twitchapi.BadgeVersion) bool {
+		return !r.isVisible(setID)
+	})
 	badgesSortedKeys := slices.Sorted(maps.Keys(badgeMap))
 
 	formattedBadges := make(map[string]string, len(badgeMap))
 
 	if !r.enableGraphics {
 		for _, k := range badgesSortedKeys {
-			b := badgeMap[k]
-
-			if colored, ok := r.badeColorMap[k]; ok {
-				formattedBadges[k] = colored
-				continue
-			}
-
-			formattedBadges[k] = b.Title
+			formattedBadges[k] = r.textBadge(k, badgeMap[k].Title)
 		}
 
 		return "", formattedBadges, nil
@@ -130,17 +197,28 @@ func (r *Replacer) fetch(ctx context.Context, reqURL string) (io.ReadCloser, str
 }
 
 // contributorBadgeText is the styled text badge for contributors in non-graphics mode.
-var contributorBadgeText = lipgloss.NewStyle().Foreground(lipgloss.Color("#88c0d0")).Render("Chatuino")
+var (
+	contributorBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#88c0d0"))
+	contributorBadgeText  = contributorBadgeStyle.Render("Chatuino")
+)
 
 // InjectContributorBadge adds a Chatuino contributor badge to the badge map if the user is a contributor.
 // The badge key "!chatuino" sorts before all other badges (ASCII '!' < letters).
 func (r *Replacer) InjectContributorBadge(loginName string, badges map[string]string) (string, error) {
-	if !contributor.IsContributor(loginName) {
+	if !contributor.IsContributor(loginName) || !r.isVisible("!chatuino") {
 		return "", nil
 	}
 
 	if !r.enableGraphics {
 		badges["!chatuino"] = contributorBadgeText
+		if r.settings.Glyphs {
+			badges["!chatuino"] = contributorBadgeStyle.Render(defaultGlyphs["!chatuino"])
+		}
+
+		if custom, ok := r.settings.CustomGlyphs["!chatuino"]; ok {
+			badges["!chatuino"] = contributorBadgeStyle.Render(custom)
+		}
+
 		return "", nil
 	}
 
@@ -159,3 +237,65 @@ func (r *Replacer) InjectContributorBadge(loginName string, badges map[string]st
 	badges["!chatuino"] = u.ReplacementText
 	return u.PrepareCommand, nil
 }
+
+// sevenTVBadgeKey is the badge map key of 7TV badges, it sorts after all Twitch badges ('~' > letters)
+const sevenTVBadgeKey = "~7tv"
+
+var sevenTVBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#29b6f6"))
+
+// InjectSevenTVBadge adds the 7TV badge selected by the user to the badge map.
+// Without graphic badges all 7TV badges are shown as the same text, the custom glyph is read from the key 7tv.
+func (r *Replacer) InjectSevenTVBadge(badge seventv.Badge, badges map[string]string) (string, error) {
+	if !r.isVisible(sevenTVBadgeKey) {
+		return "", nil
+	}
+
+	if !r.enableGraphics {
+		text := "7TV"
+		if r.settings.Glyphs {
+			text = defaultGlyphs[sevenTVBadgeKey]
+		}
+
+		if custom, ok := r.settings.CustomGlyphs["7tv"]; ok {
+			text = custom
+		}
+
+		badges[sevenTVBadgeKey] = sevenTVBadgeStyle.Render(text)
+		return "", nil
+	}
+
+	file := sevenTVBadgeFile(badge.Host.Files)
+	if badge.Host.URL == "" || file == "" {
+		return "", nil
+	}
+
+	url := "https://" + strings.TrimPrefix(badge.Host.URL, "//") + "/" + file
+
+	u, err := r.displayManager.Convert(kittyimg.DisplayUnit{
+		ID:           "7tv-badge-" + badge.ID,
+		Directory:    "badge",
+		RightPadding: badgePadding,
+		Load: func() (io.ReadCloser, string, error) {
+			log.Logger.Info().Str("id", badge.ID).Str("url", url).Msg("fetching 7TV badge")
+
+			return r.fetch(context.Background(), url)
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to convert 7TV badge %s: %w", badge.ID, err)
+	}
+
+	badges[sevenTVBadgeKey] = u.ReplacementText
+	return u.PrepareCommand, nil
+}
+
+// sevenTVBadgeFile picks the 1x file of a 7TV badge, badges are static so png is preferred
+func sevenTVBadgeFile(files []seventv.Files) string {
+	for _, name := range []string{"1x.png", "1x.avif", "1x.webp"} {
+		if slices.ContainsFunc(files, func(f seventv.Files) bool { return f.Name == name }) {
+			return name
+		}
+	}
+
+	return ""
+}
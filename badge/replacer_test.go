@@ -9,6 +9,8 @@ import (
 
 	"github.com/julez-dev/chatuino/httputil"
 	"github.com/julez-dev/chatuino/kittyimg"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
 	"github.com/stretchr/testify/require"
@@ -369,3 +371,151 @@ func TestReplacer_Replace(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestReplacer_Replace_TextBadges(t *testing.T) {
+	t.Parallel()
+
+	cache := &mockBadgeCache{
+		matchBadgeSetFunc: func(broadcasterID string, ircBadge []twitchirc.Badge) map[string]twitchapi.BadgeVersion {
+			return map[string]twitchapi.BadgeVersion{
+				"subscriber":  {ID: "1", Title: "Subscriber"},
+				"moderator":   {ID: "1", Title: "Moderator"},
+				"hype-train":  {ID: "1", Title: "hype train conductor"},
+				"broadcaster": {ID: "1", Title: "Broadcaster"},
+			}
+		},
+	}
+
+	tests := []struct {
+		name     string
+		settings save.BadgeSettings
+		want     map[string]string
+	}{
+		{
+			name:     "all-badges-as-names",
+			settings: save.BadgeSettings{Show: save.BadgeShowAll},
+			want: map[string]string{
+				"subscriber":  "Sub",
+				"moderator":   "Mod",
+				"hype-train":  "hype train conductor",
+				"broadcaster": "Streamer",
+			},
+		},
+		{
+			name:     "only-roles",
+			settings: save.BadgeSettings{Show: save.BadgeShowRoles},
+			want: map[string]string{
+				"moderator":   "Mod",
+				"broadcaster": "Streamer",
+			},
+		},
+		{
+			name:     "none",
+			settings: save.BadgeSettings{Show: save.BadgeShowNone},
+			want:     map[string]string{},
+		},
+		{
+			name: "glyphs-with-custom-glyph",
+			settings: save.BadgeSettings{
+				Glyphs:       true,
+				CustomGlyphs: map[string]string{"subscriber": "$"},
+			},
+			want: map[string]string{
+				"subscriber":  "$",
+				"moderator":   "■",
+				"hype-train":  "H",
+				"broadcaster": "◆",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			replacer := NewReplacer(nil, cache, false, save.Theme{}, tt.settings, nil)
+
+			prepare, formatted, err := replacer.Replace("broadcaster123", nil)
+
+			require.NoError(t, err)
+			require.Empty(t, prepare)
+			require.Equal(t, tt.want, formatted)
+		})
+	}
+}
+
+func TestReplacer_InjectSevenTVBadge(t *testing.T) {
+	t.Parallel()
+
+	badge := seventv.Badge{
+		ID:      "badge1",
+		Tooltip: "7TV Subscriber",
+		Host: seventv.Host{
+			URL:   "//cdn.7tv.app/badge/badge1",
+			Files: []seventv.Files{{Name: "1x.webp"}, {Name: "1x.png"}, {Name: "2x.png"}},
+		},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			settings save.BadgeSettings
+			want     map[string]string
+		}{
+			{name: "name", settings: save.BadgeSettings{Show: save.BadgeShowAll}, want: map[string]string{"~7tv": "7TV"}},
+			{name: "glyph", settings: save.BadgeSettings{Glyphs: true}, want: map[string]string{"~7tv": "7"}},
+			{name: "custom-glyph", settings: save.BadgeSettings{CustomGlyphs: map[string]string{"7tv": "S"}}, want: map[string]string{"~7tv": "S"}},
+			{name: "only-roles", settings: save.BadgeSettings{Show: save.BadgeShowRoles}, want: map[string]string{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				replacer := NewReplacer(nil, &mockBadgeCache{}, false, save.Theme{}, tt.settings, nil)
+				badges := map[string]string{}
+
+				prepare, err := replacer.InjectSevenTVBadge(badge, badges)
+				require.NoError(t, err)
+				require.Empty(t, prepare)
+				require.Equal(t, tt.want, badges)
+			})
+		}
+	})
+
+	t.Run("graphics", func(t *testing.T) {
+		t.Parallel()
+
+		var unit kittyimg.DisplayUnit
+		displayManager := &mockDisplayManager{
+			convertFunc: func(u kittyimg.DisplayUnit) (kittyimg.KittyDisplayUnit, error) {
+				unit = u
+				return kittyimg.KittyDisplayUnit{PrepareCommand: "prepare", ReplacementText: "image"}, nil
+			},
+		}
+
+		var requested string
+		client := &http.Client{
+			Transport: httputil.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				requested = r.URL.String()
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(nil)), Header: http.Header{}}, nil
+			}),
+		}
+
+		replacer := NewReplacer(client, &mockBadgeCache{}, true, save.Theme{}, save.BadgeSettings{}, displayManager)
+		badges := map[string]string{"subscriber": "sub"}
+
+		prepare, err := replacer.InjectSevenTVBadge(badge, badges)
+		require.NoError(t, err)
+		require.Equal(t, "prepare", prepare)
+		require.Equal(t, map[string]string{"subscriber": "sub", "~7tv": "image"}, badges)
+		require.Equal(t, "7tv-badge-badge1", unit.ID)
+
+		body, _, err := unit.Load()
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+		require.Equal(t, "https://cdn.7tv.app/badge/badge1/1x.png", requested)
+	})
+}
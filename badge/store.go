@@ -0,0 +1,113 @@
+package badge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/rs/zerolog/log"
+)
+
+// storedBadgeVersion is the format version of stored badge sets, files of other versions are ignored and replaced on the next fetch
+const storedBadgeVersion = 1
+
+// storedBadgeSets are the badge sets of the last successful fetch
+type storedBadgeSets struct {
+	Version   int                  `json:"version"`
+	FetchedAt time.Time            `json:"fetched_at"`
+	Sets      []twitchapi.BadgeSet `json:"sets"`
+}
+
+func (c *Cache) storedSetsFile(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// loadStoredSets reads the badge sets stored for key, false if none of the current version are stored
+func (c *Cache) loadStoredSets(key string) ([]twitchapi.BadgeSet, bool, error) {
+	if c.fs == nil {
+		return nil, false, nil
+	}
+
+	f, err := c.fs.Open(c.storedSetsFile(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer f.Close()
+
+	var stored storedBadgeSets
+	if err := json.NewDecoder(f).Decode(&stored); err != nil {
+		return nil, false, fmt.Errorf("could not decode stored badge sets %s: %w", key, err)
+	}
+
+	if stored.Version != storedBadgeVersion {
+		return nil, false, nil
+	}
+
+	return stored.Sets, true, nil
+}
+
+// storeSets writes the sets to a temporary file first, so a crash never leaves broken sets behind
+func (c *Cache) storeSets(key string, sets []twitchapi.BadgeSet) error {
+	if c.fs == nil {
+		return nil
+	}
+
+	if err := c.fs.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(storedBadgeSets{Version: storedBadgeVersion, FetchedAt: time.Now(), Sets: sets})
+	if err != nil {
+		return err
+	}
+
+	tmp := c.storedSetsFile(key) + ".tmp"
+	f, err := c.fs.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return c.fs.Rename(tmp, c.storedSetsFile(key))
+}
+
+// fetchOrStored fetches the badge sets and stores them. If the fetch fails, the sets of the last successful fetch are returned
+// together with the error, so the badges are still shown while the caller retries.
+func (c *Cache) fetchOrStored(key string, fetch func() ([]twitchapi.BadgeSet, error)) ([]twitchapi.BadgeSet, error) {
+	sets, err := fetch()
+	if err != nil {
+		stored, ok, loadErr := c.loadStoredSets(key)
+		if loadErr != nil {
+			log.Logger.Error().Err(loadErr).Str("set", key).Msg("could not load stored badge sets")
+		}
+
+		if !ok {
+			return nil, err
+		}
+
+		return stored, err
+	}
+
+	if err := c.storeSets(key, sets); err != nil {
+		log.Logger.Error().Err(err).Str("set", key).Msg("could not store badge sets")
+	}
+
+	return sets, nil
+}
@@ -0,0 +1,108 @@
+// Package blocklist knows the users the accounts blocked on Twitch.
+package blocklist
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+)
+
+// Client blocks and unblocks users for the account it was created for
+type Client interface {
+	GetUserBlockList(ctx context.Context, userID string) ([]twitchapi.BlockedUser, error)
+	BlockUser(ctx context.Context, targetUserID string) error
+	UnblockUser(ctx context.Context, targetUserID string) error
+}
+
+type User struct {
+	ID          string
+	Login       string
+	DisplayName string
+}
+
+// List keeps the blocked users of all accounts. Changes made through the list are applied locally
+// as soon as Twitch accepted them, so messages of blocked users can be hidden right away.
+type List struct {
+	l     *sync.RWMutex
+	users map[string]map[string]User // account ID, user ID
+}
+
+func New() *List {
+	return &List{
+		l:     &sync.RWMutex{},
+		users: map[string]map[string]User{},
+	}
+}
+
+// Refresh fetches all users blocked by the account
+func (b *List) Refresh(ctx context.Context, client Client, accountID string) error {
+	blocked, err := client.GetUserBlockList(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	users := make(map[string]User, len(blocked))
+	for _, u := range blocked {
+		users[u.UserID] = User{ID: u.UserID, Login: u.UserLogin, DisplayName: u.DisplayName}
+	}
+
+	b.l.Lock()
+	b.users[accountID] = users
+	b.l.Unlock()
+
+	return nil
+}
+
+// Block blocks the user on Twitch and adds it to the blocked users of the account
+func (b *List) Block(ctx context.Context, client Client, accountID string, user User) error {
+	if err := client.BlockUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if b.users[accountID] == nil {
+		b.users[accountID] = map[string]User{}
+	}
+
+	b.users[accountID][user.ID] = user
+
+	return nil
+}
+
+// Unblock unblocks the user on Twitch and removes it from the blocked users of the account
+func (b *List) Unblock(ctx context.Context, client Client, accountID string, userID string) error {
+	if err := client.UnblockUser(ctx, userID); err != nil {
+		return err
+	}
+
+	b.l.Lock()
+	delete(b.users[accountID], userID)
+	b.l.Unlock()
+
+	return nil
+}
+
+// IsBlocked reports if the account blocked the user
+func (b *List) IsBlocked(accountID, userID string) bool {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	_, ok := b.users[accountID][userID]
+	return ok && userID != ""
+}
+
+// Users returns the users blocked by the account, sorted by login
+func (b *List) Users(accountID string) []User {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	return slices.SortedFunc(maps.Values(b.users[accountID]), func(a, b User) int {
+		return cmp.Compare(a.Login, b.Login)
+	})
+}
@@ -0,0 +1,79 @@
+package blocklist
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	blocked []twitchapi.BlockedUser
+	err     error
+
+	calls []string
+}
+
+func (f *fakeClient) GetUserBlockList(context.Context, string) ([]twitchapi.BlockedUser, error) {
+	return f.blocked, f.err
+}
+
+func (f *fakeClient) BlockUser(_ context.Context, targetUserID string) error {
+	f.calls = append(f.calls, "block "+targetUserID)
+	return f.err
+}
+
+func (f *fakeClient) UnblockUser(_ context.Context, targetUserID string) error {
+	f.calls = append(f.calls, "unblock "+targetUserID)
+	return f.err
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{blocked: []twitchapi.BlockedUser{
+		{UserID: "2", UserLogin: "spammer", DisplayName: "Spammer"},
+		{UserID: "1", UserLogin: "annoying", DisplayName: "Annoying"},
+	}}
+
+	list := New()
+	require.NoError(t, list.Refresh(context.Background(), client, "acc"))
+
+	require.True(t, list.IsBlocked("acc", "1"))
+	require.False(t, list.IsBlocked("other", "1"), "blocks only apply to the account which blocked the user")
+	require.False(t, list.IsBlocked("acc", ""))
+
+	require.NoError(t, list.Block(context.Background(), client, "acc", User{ID: "3", Login: "troll", DisplayName: "Troll"}))
+	require.True(t, list.IsBlocked("acc", "3"))
+
+	require.NoError(t, list.Unblock(context.Background(), client, "acc", "1"))
+	require.False(t, list.IsBlocked("acc", "1"))
+
+	require.Equal(t, []User{
+		{ID: "2", Login: "spammer", DisplayName: "Spammer"},
+		{ID: "3", Login: "troll", DisplayName: "Troll"},
+	}, list.Users("acc"))
+	require.Equal(t, []string{"block 3", "unblock 1"}, client.calls)
+}
+
+func TestList_Failed(t *testing.T) {
+	t.Parallel()
+
+	list := New()
+
+	ok := &fakeClient{blocked: []twitchapi.BlockedUser{{UserID: "1", UserLogin: "annoying"}}}
+	require.NoError(t, list.Refresh(context.Background(), ok, "acc"))
+
+	failing := &fakeClient{err: errors.New("missing scope")}
+
+	require.EqualError(t, list.Block(context.Background(), failing, "acc", User{ID: "2", Login: "spammer"}), "missing scope")
+	require.False(t, list.IsBlocked("acc", "2"), "users are only blocked locally once Twitch blocked them")
+
+	require.EqualError(t, list.Unblock(context.Background(), failing, "acc", "1"), "missing scope")
+	require.True(t, list.IsBlocked("acc", "1"))
+
+	require.Error(t, list.Refresh(context.Background(), failing, "acc"))
+	require.True(t, list.IsBlocked("acc", "1"), "failed refreshes keep the known blocked users")
+}
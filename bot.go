@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/julez-dev/chatuino/bot"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/save/messagelog"
+	"github.com/julez-dev/chatuino/server"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/wspool"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v3"
+	"github.com/zalando/go-keyring"
+)
+
+var botCMD = &cli.Command{
+	Name:        "bot",
+	Usage:       "Run a headless bot without the UI",
+	Description: "Join the channels of the bot settings, answer messages with the configured replies and log the chat. Chat logs are stored in the database like in the UI when moderation.store_chat_logs is enabled.",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "channel",
+			Usage: "Channel to join, replaces bot.channels of the settings",
+		},
+		&cli.StringFlag{
+			Name:  "account",
+			Usage: "Display name of the account the bot runs as, replaces bot.account of the settings",
+		},
+	},
+	Action: func(ctx context.Context, command *cli.Command) error {
+		settings, err := save.SettingsFromDisk()
+		if err != nil {
+			return fmt.Errorf("failed to read settings file: %w\nrun \"chatuino config validate\" for details", err)
+		}
+
+		httpClient, err := newHTTPClient(settings.Proxy, false)
+		if err != nil {
+			return err
+		}
+
+		if channels := command.StringSlice("channel"); len(channels) > 0 {
+			settings.Bot.Channels = channels
+		}
+
+		if command.IsSet("account") {
+			settings.Bot.Account = command.String("account")
+		}
+
+		if len(settings.Bot.Channels) == 0 {
+			return errors.New("no channels to join, set bot.channels in the settings or use --channel")
+		}
+
+		var keyringBackend keyring.Keyring
+
+		if command.Bool("plain-auth-storage") {
+			keyringBackend = save.NewPlainKeyringFallback(afero.NewOsFs())
+		} else {
+			keyringBackend = save.NewKeyringWrapper()
+		}
+
+		accountProvider := save.NewAccountProvider(keyringBackend)
+
+		account, err := botAccount(accountProvider, settings.Bot.Account)
+		if err != nil {
+			return err
+		}
+
+		var sender bot.ChatSender
+		if !account.IsAnonymous {
+			serverAPI := server.NewClient(command.String("api-host"), httpClient)
+
+			api, err := twitchapi.NewAPI(command.String("client-id"), twitchapi.WithUserAuthentication(accountProvider, serverAPI, account.ID), twitchapi.WithTransport(httpClient.Transport))
+			if err != nil {
+				return fmt.Errorf("failed to build api client for %s: %w", account.DisplayName, err)
+			}
+
+			sender = api
+		} else if len(settings.Bot.Replies) > 0 {
+			fmt.Println("Running without an account, replies are disabled. Add an account with \"chatuino account\".")
+		}
+
+		var chatLog chan *twitchirc.PrivateMessage
+		loggerWaitSync := make(chan struct{})
+
+		if settings.Moderation.StoreChatLogs {
+			db, err := openDB(false)
+			if err != nil {
+				return fmt.Errorf("failed to open sqlite db: %w", err)
+			}
+
+			roDB, err := openDB(true)
+			if err != nil {
+				return fmt.Errorf("failed to open readonly sqlite db: %w", err)
+			}
+
+			defer func() {
+				if err := db.Close(); err != nil {
+					log.Logger.Err(err).Msg("failed to close db connection")
+				}
+
+				if err := roDB.Close(); err != nil {
+					log.Logger.Err(err).Msg("failed to close db connection")
+				}
+			}()
+
+			messageLogger := messagelog.NewBatchedMessageLogger(log.Logger, db, roDB, settings.Moderation.LogsChannelInclude, settings.Moderation.LogsChannelExclude)
+			if err := messageLogger.PrepareDatabase(); err != nil {
+				return fmt.Errorf("failed to migrate db: %w", err)
+			}
+
+			chatLog = make(chan *twitchirc.PrivateMessage)
+			go runChatLogger(messageLogger, chatLog, loggerWaitSync, true)
+		}
+
+		pool := wspool.NewPool(accountProvider, log.Logger)
+		pool.SetHTTPClient(httpClient)
+
+		fmt.Printf("Running as %s in %s, press ctrl+c to stop\n", account.DisplayName, strings.Join(settings.Bot.Channels, ", "))
+
+		runErr := bot.New(log.Logger, settings.Bot, account, pool, sender, chatLog, os.Stdout).Run(ctx)
+
+		if err := pool.Close(); err != nil {
+			log.Logger.Err(err).Msg("failed to close connection pool")
+		}
+
+		// wait until all messages are stored
+		if chatLog != nil {
+			close(chatLog)
+			<-loggerWaitSync
+		}
+
+		return runErr
+	},
+}
+
+// botAccount returns the account with the display name or the main account if name is empty.
+// Without any added account the anonymous account is used.
+func botAccount(accountProvider save.AccountProvider, name string) (save.Account, error) {
+	accounts, err := accountProvider.GetAllAccounts()
+	if err != nil {
+		return save.Account{}, fmt.Errorf("failed to open accounts: %w", err)
+	}
+
+	for _, acc := range accounts {
+		if name != "" && strings.EqualFold(acc.DisplayName, name) {
+			return acc, nil
+		}
+
+		if name == "" && acc.IsMain {
+			return acc, nil
+		}
+	}
+
+	if name != "" {
+		return save.Account{}, fmt.Errorf("account %q not found", name)
+	}
+
+	i := slices.IndexFunc(accounts, func(a save.Account) bool { return a.IsAnonymous })
+	if i == -1 {
+		return save.Account{}, save.ErrAccountNotFound
+	}
+
+	return accounts[i], nil
+}
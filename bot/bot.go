@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/wspool"
+	"github.com/rs/zerolog"
+)
+
+// sendTimeout is the maximum time a reply may take to be sent
+const sendTimeout = time.Second * 5
+
+// Pool connects to Twitch chat, implemented by wspool.Pool
+type Pool interface {
+	SetSend(send func(tea.Msg))
+	ConnectIRC(accountID string) error
+	JoinChannel(accountID, channel string) error
+}
+
+// ChatSender sends chat messages using the Twitch API
+type ChatSender interface {
+	SendChatMessage(ctx context.Context, data twitchapi.SendChatMessageRequest) (twitchapi.SendChatMessageResponse, error)
+}
+
+// Bot runs without the UI. It joins the configured channels, answers messages matching a configured reply
+// and optionally prints and stores the chat.
+type Bot struct {
+	logger   zerolog.Logger
+	settings save.BotSettings
+	account  save.Account
+	pool     Pool
+	sender   ChatSender                       // nil for anonymous accounts, which can't send messages
+	chatLog  chan<- *twitchirc.PrivateMessage // nil if chat logs are not stored
+	out      io.Writer
+
+	now       func() time.Time
+	lastReply map[string]time.Time // reply index and channel to the time of the last response
+}
+
+func New(logger zerolog.Logger, settings save.BotSettings, account save.Account, pool Pool, sender ChatSender, chatLog chan<- *twitchirc.PrivateMessage, out io.Writer) *Bot {
+	return &Bot{
+		logger:    logger.With().Str("component", "bot").Logger(),
+		settings:  settings,
+		account:   account,
+		pool:      pool,
+		sender:    sender,
+		chatLog:   chatLog,
+		out:       out,
+		now:       time.Now,
+		lastReply: map[string]time.Time{},
+	}
+}
+
+// Run connects to chat and handles messages until ctx is done
+func (b *Bot) Run(ctx context.Context) error {
+	events := make(chan wspool.IRCEvent, 128)
+
+	b.pool.SetSend(func(msg tea.Msg) {
+		event, ok := msg.(wspool.IRCEvent)
+		if !ok {
+			return
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	})
+
+	if err := b.pool.ConnectIRC(b.account.ID); err != nil {
+		return fmt.Errorf("failed to connect to chat: %w", err)
+	}
+
+	for _, channel := range b.settings.Channels {
+		if err := b.pool.JoinChannel(b.account.ID, normalizeChannel(channel)); err != nil {
+			return fmt.Errorf("failed to join %s: %w", channel, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-events:
+			if event.Error != nil {
+				b.logger.Err(event.Error).Msg("chat connection error, reconnecting")
+				_, _ = fmt.Fprintf(b.out, "Chat connection error, reconnecting: %s\n", event.Error)
+				continue
+			}
+
+			if msg, ok := event.Message.(*twitchirc.PrivateMessage); ok {
+				b.handleMessage(ctx, msg)
+			}
+		}
+	}
+}
+
+func (b *Bot) handleMessage(ctx context.Context, msg *twitchirc.PrivateMessage) {
+	if b.settings.LogChat {
+		// messages are printed to the terminal, control sequences in them must not reach it
+		_, _ = fmt.Fprintf(b.out, "%s #%s %s: %s\n", msg.TMISentTS.Local().Format("15:04:05"), msg.ChannelUserName, termtext.Sanitize(msg.DisplayName), termtext.Sanitize(msg.Message))
+	}
+
+	if b.chatLog != nil {
+		b.chatLog <- msg
+	}
+
+	// never answer own messages, a response could trigger itself
+	if b.sender == nil || msg.UserID == b.account.ID {
+		return
+	}
+
+	response, ok := b.response(msg)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	resp, err := b.sender.SendChatMessage(ctx, twitchapi.SendChatMessageRequest{
+		BroadcasterID: msg.RoomID,
+		SenderID:      b.account.ID,
+		Message:       response,
+	})
+
+	if err == nil && len(resp.Data) > 0 && !resp.Data[0].IsSent {
+		err = fmt.Errorf("message dropped: %s", resp.Data[0].DropReason.Message)
+	}
+
+	if err != nil {
+		b.logger.Err(err).Str("channel", msg.ChannelUserName).Msg("failed to send reply")
+		_, _ = fmt.Fprintf(b.out, "Failed to reply in #%s: %s\n", msg.ChannelUserName, err)
+	}
+}
+
+// response returns the response of the first reply matching the message. A matching reply on cooldown
+// suppresses the response, so a single message is never answered by a less specific reply.
+func (b *Bot) response(msg *twitchirc.PrivateMessage) (string, bool) {
+	for i, reply := range b.settings.Replies {
+		if len(reply.Channels) > 0 && !slices.ContainsFunc(reply.Channels, func(c string) bool {
+			return strings.EqualFold(normalizeChannel(c), msg.ChannelUserName)
+		}) {
+			continue
+		}
+
+		args, ok := matchReply(reply, msg.Message)
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%s", i, msg.ChannelUserName)
+		now := b.now()
+
+		if last, ok := b.lastReply[key]; ok && now.Sub(last) < reply.Cooldown {
+			return "", false
+		}
+
+		b.lastReply[key] = now
+
+		return strings.NewReplacer(
+			"{user}", msg.DisplayName,
+			"{channel}", msg.ChannelUserName,
+			"{args}", args,
+		).Replace(reply.Response), true
+	}
+
+	return "", false
+}
+
+// matchReply reports if the message matches the reply and returns the text following a command trigger
+func matchReply(reply save.BotReply, message string) (string, bool) {
+	if reply.Match == save.BotMatchContains {
+		return "", strings.Contains(strings.ToLower(message), strings.ToLower(reply.Trigger))
+	}
+
+	first, rest, _ := strings.Cut(strings.TrimSpace(message), " ")
+	if !strings.EqualFold(first, reply.Trigger) {
+		return "", false
+	}
+
+	return strings.TrimSpace(rest), true
+}
+
+func normalizeChannel(channel string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(channel), "#"))
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	sent []twitchapi.SendChatMessageRequest
+}
+
+func (f *fakeSender) SendChatMessage(_ context.Context, data twitchapi.SendChatMessageRequest) (twitchapi.SendChatMessageResponse, error) {
+	f.sent = append(f.sent, data)
+	return twitchapi.SendChatMessageResponse{}, nil
+}
+
+func TestBot_Response(t *testing.T) {
+	t.Parallel()
+
+	settings := save.BotSettings{
+		Replies: []save.BotReply{
+			{Trigger: "!discord", Response: "{user}, join at example.com"},
+			{Trigger: "!so", Response: "Check out {args} in #{channel}", Channels: []string{"#Streamer"}},
+			{Trigger: "hello bot", Match: save.BotMatchContains, Response: "Hi {user}"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		channel  string
+		message  string
+		response string
+		matched  bool
+	}{
+		{name: "command", channel: "streamer", message: "!DISCORD please", response: "Viewer, join at example.com", matched: true},
+		{name: "command needs to be the first word", channel: "streamer", message: "what is !discord", matched: false},
+		{name: "arguments", channel: "streamer", message: "!so  other_streamer ", response: "Check out other_streamer in #streamer", matched: true},
+		{name: "channel filter", channel: "someone_else", message: "!so other_streamer", matched: false},
+		{name: "contains", channel: "streamer", message: "well Hello Bot!", response: "Hi Viewer", matched: true},
+		{name: "no match", channel: "streamer", message: "hello", matched: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := New(zerolog.Nop(), settings, save.Account{ID: "bot"}, nil, nil, nil, &bytes.Buffer{})
+
+			response, ok := b.response(&twitchirc.PrivateMessage{
+				ChannelUserName: tc.channel,
+				DisplayName:     "Viewer",
+				Message:         tc.message,
+			})
+
+			require.Equal(t, tc.matched, ok)
+			require.Equal(t, tc.response, response)
+		})
+	}
+}
+
+func TestBot_HandleMessage(t *testing.T) {
+	t.Parallel()
+
+	settings := save.BotSettings{
+		LogChat: true,
+		Replies: []save.BotReply{
+			{Trigger: "!ping", Response: "pong", Cooldown: time.Minute},
+		},
+	}
+
+	sender := &fakeSender{}
+	out := &bytes.Buffer{}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	b := New(zerolog.Nop(), settings, save.Account{ID: "bot"}, nil, sender, nil, out)
+	b.now = func() time.Time { return now }
+
+	msg := &twitchirc.PrivateMessage{
+		RoomID:          "123",
+		ChannelUserName: "streamer",
+		UserID:          "456",
+		DisplayName:     "Viewer",
+		Message:         "!ping",
+	}
+
+	b.handleMessage(t.Context(), msg)
+	require.Equal(t, []twitchapi.SendChatMessageRequest{{BroadcasterID: "123", SenderID: "bot", Message: "pong"}}, sender.sent)
+	require.Contains(t, out.String(), "#streamer Viewer: !ping")
+
+	// on cooldown
+	now = now.Add(time.Second * 30)
+	b.handleMessage(t.Context(), msg)
+	require.Len(t, sender.sent, 1)
+
+	now = now.Add(time.Minute)
+	b.handleMessage(t.Context(), msg)
+	require.Len(t, sender.sent, 2)
+
+	// own messages are never answered
+	now = now.Add(time.Hour)
+	own := *msg
+	own.UserID = "bot"
+	b.handleMessage(t.Context(), &own)
+	require.Len(t, sender.sent, 2)
+
+	// control sequences in messages don't reach the terminal
+	out.Reset()
+	spoofed := *msg
+	spoofed.Message = "hi\x1b]0;title\x07\x1b[2J there"
+	b.handleMessage(t.Context(), &spoofed)
+	require.Contains(t, out.String(), "#streamer Viewer: hi there")
+	require.NotContains(t, out.String(), "\x1b")
+}
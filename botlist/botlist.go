@@ -0,0 +1,142 @@
+// Package botlist knows the bot accounts marked on FFZ and BTTV, globally and by channels.
+package botlist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"golang.org/x/sync/singleflight"
+)
+
+type FFZBotFetcher interface {
+	GetBotUserIDs(ctx context.Context) ([]string, error)
+	GetChannelBotUserIDs(ctx context.Context, channelID string) ([]string, error)
+}
+
+type BTTVBotFetcher interface {
+	GetChannelBots(ctx context.Context, channelID string) ([]string, error)
+}
+
+type channelBots struct {
+	userIDs map[string]struct{}
+	logins  map[string]struct{} // lower case, BTTV only lists logins
+}
+
+type Cache struct {
+	ffz  FFZBotFetcher
+	bttv BTTVBotFetcher
+
+	single *singleflight.Group
+	l      *sync.RWMutex
+
+	global   map[string]struct{}    // user IDs
+	channels map[string]channelBots // channel ID
+}
+
+func NewCache(ffz FFZBotFetcher, bttv BTTVBotFetcher) *Cache {
+	return &Cache{
+		ffz:      ffz,
+		bttv:     bttv,
+		single:   &singleflight.Group{},
+		l:        &sync.RWMutex{},
+		global:   map[string]struct{}{},
+		channels: map[string]channelBots{},
+	}
+}
+
+// RefreshGlobal fetches the users with the FFZ bot badge
+func (c *Cache) RefreshGlobal(ctx context.Context) error {
+	ids, err := c.ffz.GetBotUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	global := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		global[id] = struct{}{}
+	}
+
+	c.l.Lock()
+	c.global = global
+	c.l.Unlock()
+
+	return nil
+}
+
+// RefreshChannel fetches the bots the channel marked on FFZ and BTTV. Channels without FFZ or BTTV account have no bots.
+// If one of the services fails, the bots of the other service are still used.
+func (c *Cache) RefreshChannel(ctx context.Context, channelID string) error {
+	_, err, _ := c.single.Do(channelID, func() (any, error) {
+		bots := channelBots{
+			userIDs: map[string]struct{}{},
+			logins:  map[string]struct{}{},
+		}
+
+		ffzIDs, ffzErr := c.ffz.GetChannelBotUserIDs(ctx, channelID)
+		if isNotFound(ffzErr) {
+			ffzErr = nil
+		}
+
+		for _, id := range ffzIDs {
+			bots.userIDs[id] = struct{}{}
+		}
+
+		bttvLogins, bttvErr := c.bttv.GetChannelBots(ctx, channelID)
+		if isNotFound(bttvErr) {
+			bttvErr = nil
+		}
+
+		for _, login := range bttvLogins {
+			bots.logins[strings.ToLower(login)] = struct{}{}
+		}
+
+		c.l.Lock()
+		c.channels[channelID] = bots
+		c.l.Unlock()
+
+		return nil, errors.Join(ffzErr, bttvErr)
+	})
+
+	return err
+}
+
+// IsBot reports if the user has the global FFZ bot badge or was marked as bot by the channel
+func (c *Cache) IsBot(channelID, userID, login string) bool {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	if _, ok := c.global[userID]; ok && userID != "" {
+		return true
+	}
+
+	bots, ok := c.channels[channelID]
+	if !ok {
+		return false
+	}
+
+	if _, ok := bots.userIDs[userID]; ok && userID != "" {
+		return true
+	}
+
+	_, ok = bots.logins[strings.ToLower(login)]
+	return ok && login != ""
+}
+
+func isNotFound(err error) bool {
+	var ffzErr ffz.APIError
+	if errors.As(err, &ffzErr) {
+		return ffzErr.StatusCode == http.StatusNotFound
+	}
+
+	var bttvErr bttv.APIError
+	if errors.As(err, &bttvErr) {
+		return bttvErr.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package botlist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFFZ struct {
+	global   []string
+	channels map[string][]string
+	err      error
+}
+
+func (f fakeFFZ) GetBotUserIDs(context.Context) ([]string, error) {
+	return f.global, f.err
+}
+
+func (f fakeFFZ) GetChannelBotUserIDs(_ context.Context, channelID string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	ids, ok := f.channels[channelID]
+	if !ok {
+		return nil, ffz.APIError{StatusCode: http.StatusNotFound}
+	}
+
+	return ids, nil
+}
+
+type fakeBTTV struct {
+	channels map[string][]string
+}
+
+func (f fakeBTTV) GetChannelBots(_ context.Context, channelID string) ([]string, error) {
+	logins, ok := f.channels[channelID]
+	if !ok {
+		return nil, bttv.APIError{StatusCode: http.StatusNotFound}
+	}
+
+	return logins, nil
+}
+
+func TestCache_IsBot(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(
+		fakeFFZ{global: []string{"100"}, channels: map[string][]string{"1": {"200"}}},
+		fakeBTTV{channels: map[string][]string{"1": {"StreamHelper"}, "2": {"otherbot"}}},
+	)
+
+	require.NoError(t, cache.RefreshGlobal(context.Background()))
+	require.NoError(t, cache.RefreshChannel(context.Background(), "1"))
+	require.NoError(t, cache.RefreshChannel(context.Background(), "3"), "channels without FFZ and BTTV have no bots")
+
+	tests := []struct {
+		name      string
+		channelID string
+		userID    string
+		login     string
+		want      bool
+	}{
+		{name: "global ffz bot", channelID: "3", userID: "100", login: "nightbot", want: true},
+		{name: "channel ffz bot", channelID: "1", userID: "200", login: "modbot", want: true},
+		{name: "ffz bot of other channel", channelID: "3", userID: "200", login: "modbot"},
+		{name: "channel bttv bot ignoring case", channelID: "1", userID: "300", login: "streamhelper", want: true},
+		{name: "bttv bot of channel not refreshed", channelID: "2", userID: "400", login: "otherbot"},
+		{name: "viewer", channelID: "1", userID: "500", login: "viewer"},
+		{name: "empty user", channelID: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, cache.IsBot(tt.channelID, tt.userID, tt.login))
+		})
+	}
+}
+
+func TestCache_RefreshChannel_Error(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(
+		fakeFFZ{err: errors.New("ffz is down")},
+		fakeBTTV{channels: map[string][]string{"1": {"streamhelper"}}},
+	)
+
+	require.EqualError(t, cache.RefreshChannel(context.Background(), "1"), "ffz is down")
+	require.True(t, cache.IsBot("1", "300", "streamhelper"), "the bots of BTTV are used if FFZ failed")
+}
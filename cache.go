@@ -66,9 +66,10 @@ var cacheCMD = &cli.Command{
 			Usage:       "Manage deletion of cached data",
 			Description: "Delete specified cached data",
 			Flags: []cli.Flag{
-				&cli.BoolFlag{Name: "emotes", Usage: "Delete emote image cache"},
+				&cli.BoolFlag{Name: "emotes", Usage: "Delete emote image cache and the stored emote sets"},
 				&cli.BoolFlag{Name: "database", Usage: "Delete database cache"},
-				&cli.BoolFlag{Name: "badges", Usage: "Delete badge image cache"},
+				&cli.BoolFlag{Name: "badges", Usage: "Delete badge image cache and the stored badge sets"},
+				&cli.BoolFlag{Name: "api", Usage: "Delete cached Twitch API responses"},
 			},
 			Action: func(ctx context.Context, c *cli.Command) error {
 				checkmark := cacheSuccessStyle.Render("✓")
@@ -77,6 +78,9 @@ var cacheCMD = &cli.Command{
 					if err := os.RemoveAll(filepath.Join(kittyimg.BaseImageDirectory, "emote")); err != nil && !errors.Is(err, os.ErrNotExist) {
 						return fmt.Errorf("failed to delete emote cache: %w", err)
 					}
+					if err := os.RemoveAll(appPaths.EmoteSetDir()); err != nil && !errors.Is(err, os.ErrNotExist) {
+						return fmt.Errorf("failed to delete stored emote sets: %w", err)
+					}
 					fmt.Println(checkmark + " " + cacheEmoteStyle.Render("Emote cache") + cacheTextStyle.Render(" deleted"))
 				}
 
@@ -84,11 +88,21 @@ var cacheCMD = &cli.Command{
 					if err := os.RemoveAll(filepath.Join(kittyimg.BaseImageDirectory, "badge")); err != nil && !errors.Is(err, os.ErrNotExist) {
 						return fmt.Errorf("failed to delete badge cache: %w", err)
 					}
+					if err := os.RemoveAll(appPaths.BadgeSetDir()); err != nil && !errors.Is(err, os.ErrNotExist) {
+						return fmt.Errorf("failed to delete stored badge sets: %w", err)
+					}
 					fmt.Println(checkmark + " " + cacheBadgeStyle.Render("Badge cache") + cacheTextStyle.Render(" deleted"))
 				}
 
+				if c.Bool("api") {
+					if err := os.RemoveAll(appPaths.APICacheDir()); err != nil && !errors.Is(err, os.ErrNotExist) {
+						return fmt.Errorf("failed to delete API cache: %w", err)
+					}
+					fmt.Println(checkmark + " " + cacheHeaderStyle.Render("API cache") + cacheTextStyle.Render(" deleted"))
+				}
+
 				if c.Bool("database") {
-					if err := os.Remove(dbFileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+					if err := os.Remove(appPaths.DatabaseFile()); err != nil && !errors.Is(err, os.ErrNotExist) {
 						return fmt.Errorf("failed to delete database cache: %w", err)
 					}
 					fmt.Println(checkmark + " " + cacheHeaderStyle.Render("Database") + cacheTextStyle.Render(" deleted"))
@@ -0,0 +1,152 @@
+// Package chatexport writes chat messages as plain text, JSON or CSV, used by the export command and the /export chat command.
+package chatexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// Format is the file format of an export
+type Format string
+
+const (
+	FormatText Format = "text" // one line per message
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// ParseFormat returns the format for its name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatText, FormatJSON, FormatCSV:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unknown export format %q, must be one of text, json or csv", name)
+}
+
+// Extension returns the file extension for exports in the format, without the dot
+func (f Format) Extension() string {
+	if f == FormatText {
+		return "txt"
+	}
+
+	return string(f)
+}
+
+// Message is a single exported chat message
+type Message struct {
+	ID          string    `json:"id"`
+	SentAt      time.Time `json:"sent_at"`
+	Channel     string    `json:"channel"`
+	UserID      string    `json:"user_id"`
+	Login       string    `json:"login"`
+	DisplayName string    `json:"display_name"`
+	Text        string    `json:"text"`
+}
+
+func FromPrivateMessage(msg *twitchirc.PrivateMessage) Message {
+	return Message{
+		ID:          msg.ID,
+		SentAt:      msg.TMISentTS,
+		Channel:     msg.ChannelUserName,
+		UserID:      msg.UserID,
+		Login:       msg.LoginName,
+		DisplayName: msg.DisplayName,
+		Text:        msg.Message,
+	}
+}
+
+// Range limits the exported messages by the time they were sent, a zero time is unbounded
+type Range struct {
+	Since time.Time
+	Until time.Time
+}
+
+func (r Range) Contains(t time.Time) bool {
+	if !r.Since.IsZero() && t.Before(r.Since) {
+		return false
+	}
+
+	if !r.Until.IsZero() && t.After(r.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Filter returns the messages sent in the range
+func (r Range) Filter(messages []Message) []Message {
+	filtered := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		if r.Contains(msg.SentAt) {
+			filtered = append(filtered, msg)
+		}
+	}
+
+	return filtered
+}
+
+// ParseTime parses the bound of a time range. Accepts a duration before now (30m, 2h), a date (2006-01-02) in the local time zone
+// or an RFC 3339 timestamp.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration %q must not be negative", value)
+		}
+
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, expected a duration like 2h, a date like 2006-01-02 or an RFC 3339 timestamp", value)
+}
+
+// Write writes the messages in the format
+func Write(w io.Writer, format Format, messages []Message) error {
+	switch format {
+	case FormatText:
+		for _, msg := range messages {
+			if _, err := fmt.Fprintf(w, "%s #%s %s: %s\n", msg.SentAt.Format("2006-01-02 15:04:05 -0700"), msg.Channel, msg.DisplayName, msg.Text); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case FormatJSON:
+		if messages == nil {
+			messages = []Message{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(messages)
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"id", "sent_at", "channel", "user_id", "login", "display_name", "text"})
+
+		for _, msg := range messages {
+			_ = writer.Write([]string{msg.ID, msg.SentAt.Format(time.RFC3339), msg.Channel, msg.UserID, msg.Login, msg.DisplayName, msg.Text})
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+	}
+
+	return fmt.Errorf("unknown export format %q", format)
+}
@@ -0,0 +1,85 @@
+package chatexport
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "duration", value: "90m", want: time.Date(2026, 3, 10, 10, 30, 0, 0, time.UTC)},
+		{name: "date", value: "2026-03-01", want: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "timestamp", value: "2026-03-01T08:15:00Z", want: time.Date(2026, 3, 1, 8, 15, 0, 0, time.UTC)},
+		{name: "negative duration", value: "-1h", wantErr: true},
+		{name: "invalid", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTime(tt.value, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	messages := []Message{
+		{ID: "1", SentAt: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), Channel: "lirik", UserID: "10", Login: "viewer", DisplayName: "Viewer", Text: `hello, "chat"`},
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{format: FormatText, want: "2026-03-10 12:00:00 +0000 #lirik Viewer: hello, \"chat\"\n"},
+		{format: FormatCSV, want: "id,sent_at,channel,user_id,login,display_name,text\n1,2026-03-10T12:00:00Z,lirik,10,viewer,Viewer,\"hello, \"\"chat\"\"\"\n"},
+		{format: FormatJSON, want: "[\n  {\n    \"id\": \"1\",\n    \"sent_at\": \"2026-03-10T12:00:00Z\",\n    \"channel\": \"lirik\",\n    \"user_id\": \"10\",\n    \"login\": \"viewer\",\n    \"display_name\": \"Viewer\",\n    \"text\": \"hello, \\\"chat\\\"\"\n  }\n]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, tt.format, messages))
+			require.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJSON, nil))
+	require.Equal(t, "[]\n", buf.String())
+}
+
+func TestRange_Filter(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	messages := []Message{{ID: "1", SentAt: base}, {ID: "2", SentAt: base.Add(time.Hour)}, {ID: "3", SentAt: base.Add(2 * time.Hour)}}
+
+	require.Len(t, Range{}.Filter(messages), 3)
+	require.Equal(t, []Message{messages[1], messages[2]}, Range{Since: base.Add(time.Hour)}.Filter(messages))
+	require.Equal(t, []Message{messages[1]}, Range{Since: base.Add(30 * time.Minute), Until: base.Add(90 * time.Minute)}.Filter(messages))
+}
@@ -0,0 +1,41 @@
+// Package chatprovider describes live chats of other platforms than Twitch, which are opened as tabs next to Twitch channels.
+// Messages of providers are mapped onto the twitchirc message types, so they are rendered like Twitch chat.
+package chatprovider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// ErrReadOnly is returned by Send of chats messages can't be sent to
+var ErrReadOnly = errors.New("sending messages is not supported")
+
+// Provider joins the live chats of a platform
+type Provider interface {
+	// Name is the platform shown in the join prompt and tab header, like YouTube
+	Name() string
+
+	// Join connects to the live chat of channel, the chat is left when ctx is done
+	Join(ctx context.Context, channel string) (Chat, error)
+}
+
+// Chat is a joined live chat
+type Chat interface {
+	// Title describes the chat, like the title of the stream
+	Title() string
+
+	// Messages receives the messages of the chat as *twitchirc.PrivateMessage, *twitchirc.Notice, *twitchirc.ClearChat
+	// and *twitchirc.ClearMessage. It is closed when the chat ended or was left.
+	Messages() <-chan twitchirc.IRCer
+
+	// Err is the reason Messages was closed, nil if the chat was left
+	Err() error
+
+	// ReadOnly reports whether Send returns ErrReadOnly
+	ReadOnly() bool
+
+	// Send sends a message to the chat as the user, it is received through Messages like other messages
+	Send(ctx context.Context, text string) error
+}
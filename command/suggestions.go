@@ -16,6 +16,10 @@ var ModeratorSuggestions = [...]string{
 	"/announcement purple <message>",
 	"/announcement primary <message>",
 	"/marker [description]",
+	"/chatsettings",
+	"/redemptions",
+	`/poll "<title>" <choice> <choice> [duration]`,
+	`/prediction "<title>" <outcome> <outcome> [duration]`,
 }
 
 var CommandSuggestions = [...]string{
@@ -30,4 +34,21 @@ var CommandSuggestions = [...]string{
 	"/createclip",
 	"/emotes",
 	"/refreshemotes",
+	"/watch",
+	"/chatstats",
+	"/presence",
+	"/theme <name>",
+	"/obs <status|scenes|startstream|stopstream|startrecord|stoprecord>",
+	"/obs scene <name>",
+	"/block <username>",
+	"/unblock <username>",
+	"/export [text|json|csv] [logs] [since]",
+	"/note [@user] <text>",
+	"/notes [@user]",
+	"/removenote [@user] <number>",
+	"/timer <name|all> <on|off>",
+	"/quote [random|number]",
+	"/quote add <text>",
+	"/quote remove <number>",
+	"/count <name> [+n|-n|number|reset]",
 }
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v3"
+	"github.com/zalando/go-keyring"
+)
+
+var (
+	configErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#bf616a")).Bold(true) // red
+	configWarningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ebcb8b")).Bold(true) // yellow/gold
+	configPathStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#4c566a"))            // gray
+)
+
+// defaultProfileArchive is the file written by config export without a file argument
+const defaultProfileArchive = "chatuino-profile.tar.gz"
+
+var configCMD = &cli.Command{
+	Name:  "config",
+	Usage: "Manage the Chatuino configuration",
+	Commands: []*cli.Command{
+		{
+			Name:        "validate",
+			Usage:       "Check the settings file for errors",
+			Description: "Check the settings file for syntax errors, invalid values and unknown settings. Settings of older versions are migrated to the current version before they are checked.",
+			ArgsUsage:   "[settings file]",
+			Action: func(_ context.Context, c *cli.Command) error {
+				path := c.Args().First()
+				if path == "" {
+					var err error
+					if path, err = save.SettingsFilePath(); err != nil {
+						return fmt.Errorf("failed to find settings file: %w", err)
+					}
+				}
+
+				b, err := os.ReadFile(path)
+				if errors.Is(err, os.ErrNotExist) {
+					fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(" No settings file found at "+path+", the default settings are used"))
+					return nil
+				}
+
+				if err != nil {
+					return fmt.Errorf("failed to read settings file: %w", err)
+				}
+
+				_, report := save.CheckSettings(b)
+
+				var errorCount int
+				for _, issue := range report.Issues {
+					location := path
+					if issue.Line > 0 {
+						location = fmt.Sprintf("%s:%d", path, issue.Line)
+					}
+
+					level := configWarningStyle.Render("warning")
+					if !issue.Warning {
+						level = configErrorStyle.Render("error")
+						errorCount++
+					}
+
+					fmt.Printf("%s %s %s\n", configPathStyle.Render(location+":"), level, issue.Message)
+				}
+
+				if len(report.Migrations) > 0 {
+					fmt.Printf("\nThe settings file uses version %d, the current version is %d. These changes are applied when loading it:\n", report.Version, save.CurrentSettingsVersion)
+					for _, m := range report.Migrations {
+						fmt.Println("  - " + m)
+					}
+					fmt.Printf("Update the file accordingly and set \"version: %d\".\n", save.CurrentSettingsVersion)
+				}
+
+				if errorCount > 0 {
+					return fmt.Errorf("settings file contains %d error(s)", errorCount)
+				}
+
+				fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(" Settings are valid"))
+
+				return nil
+			},
+		},
+		{
+			Name:        "export",
+			Usage:       "Write the configuration into an archive",
+			Description: "Write the settings, theme, keymap, spellcheck dictionary and scripts into a single archive, which can be imported on another machine. API keys and passwords in the settings and the accounts are left out unless --secrets and --accounts are set.",
+			ArgsUsage:   "[archive file]",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "secrets",
+					Usage: "Include API keys and passwords of the settings",
+				},
+				&cli.BoolFlag{
+					Name:  "accounts",
+					Usage: "Include the accounts with their tokens, keep the archive private",
+				},
+			},
+			Action: func(_ context.Context, c *cli.Command) error {
+				path := c.Args().First()
+				if path == "" {
+					path = defaultProfileArchive
+				}
+
+				opts := save.ProfileExportOptions{IncludeSecrets: c.Bool("secrets")}
+
+				if c.Bool("accounts") {
+					accounts, err := profileAccountProvider(c).GetAllAccounts()
+					if err != nil {
+						return fmt.Errorf("failed to read accounts: %w", err)
+					}
+
+					opts.Accounts = accounts
+				}
+
+				// the archive may contain tokens and API keys
+				f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+				if err != nil {
+					return fmt.Errorf("failed to create archive: %w", err)
+				}
+
+				defer f.Close()
+
+				files, err := save.ExportProfile(afero.NewOsFs(), appPaths, f, opts)
+				if err != nil {
+					return fmt.Errorf("failed to export configuration: %w", err)
+				}
+
+				for _, file := range files {
+					fmt.Println("  " + configPathStyle.Render(file))
+				}
+
+				fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(" Exported configuration to "+path))
+
+				return f.Close()
+			},
+		},
+		{
+			Name:        "import",
+			Usage:       "Apply the configuration of an archive",
+			Description: "Write the files of an archive created by chatuino config export into the config directory and add its accounts. Existing files are only replaced with --force. API keys and passwords missing in the archive are kept from the current settings.",
+			ArgsUsage:   "<archive file>",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "Replace existing files",
+				},
+			},
+			Action: func(_ context.Context, c *cli.Command) error {
+				path := c.Args().First()
+				if path == "" {
+					return errors.New("missing archive file, usage: chatuino config import <archive file>")
+				}
+
+				f, err := os.Open(path)
+				if err != nil {
+					return fmt.Errorf("failed to open archive: %w", err)
+				}
+
+				defer f.Close()
+
+				profile, err := save.ReadProfile(f)
+				if err != nil {
+					return err
+				}
+
+				files, err := profile.Install(afero.NewOsFs(), appPaths, c.Bool("force"))
+				if errors.Is(err, save.ErrProfileFilesExist) {
+					return fmt.Errorf("%w, use --force to replace them", err)
+				}
+
+				if err != nil {
+					return fmt.Errorf("failed to import configuration: %w", err)
+				}
+
+				for _, file := range files {
+					fmt.Println("  " + configPathStyle.Render(file))
+				}
+
+				if len(profile.Accounts) > 0 {
+					provider := profileAccountProvider(c)
+					for _, account := range profile.Accounts {
+						// accounts already logged in on this machine keep their tokens
+						if _, err := provider.GetAccountBy(account.ID); err == nil {
+							continue
+						}
+
+						if err := provider.Add(account); err != nil {
+							return fmt.Errorf("failed to add account %s: %w", account.DisplayName, err)
+						}
+
+						fmt.Println("  " + configPathStyle.Render("account "+account.DisplayName))
+					}
+				}
+
+				fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(" Imported configuration from "+path))
+
+				return nil
+			},
+		},
+	},
+}
+
+// profileAccountProvider returns the accounts of the system keyring or of the plain text file with --plain-auth-storage
+func profileAccountProvider(c *cli.Command) save.AccountProvider {
+	var keyringBackend keyring.Keyring
+
+	if c.Bool("plain-auth-storage") {
+		keyringBackend = save.NewPlainKeyringFallback(afero.NewOsFs())
+	} else {
+		keyringBackend = save.NewKeyringWrapper()
+	}
+
+	return save.NewAccountProvider(keyringBackend)
+}
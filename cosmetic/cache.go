@@ -0,0 +1,134 @@
+// Package cosmetic caches the 7TV cosmetics (name paints and badges) of chatters.
+package cosmetic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultTTL is the time after which the cosmetics of a user are fetched again
+	DefaultTTL = time.Minute * 30
+
+	// maxConcurrentFetches limits the requests to 7TV, users seen while all fetches are in use are fetched with their next message
+	maxConcurrentFetches = 4
+
+	// maxUsers is the number of cached users after which outdated users are removed
+	maxUsers = 10_000
+
+	fetchTimeout = time.Second * 10
+)
+
+type UserFetcher interface {
+	GetTwitchUser(ctx context.Context, twitchUserID string) (seventv.TwitchUserResponse, error)
+}
+
+// Cosmetics are the paint and badge selected by a user, both are nil for users without 7TV account or cosmetics
+type Cosmetics struct {
+	Paint *seventv.Paint
+	Badge *seventv.Badge
+}
+
+type entry struct {
+	cosmetics Cosmetics
+	fetchedAt time.Time
+	pending   bool
+}
+
+// Cache fetches the cosmetics of users in the background, so rendering messages never waits for 7TV
+type Cache struct {
+	logger  zerolog.Logger
+	fetcher UserFetcher
+	ttl     time.Duration
+	now     func() time.Time
+	fetches chan struct{}
+
+	m     sync.Mutex
+	users map[string]entry // twitch user ID
+}
+
+func NewCache(logger zerolog.Logger, fetcher UserFetcher, ttl time.Duration) *Cache {
+	return &Cache{
+		logger:  logger,
+		fetcher: fetcher,
+		ttl:     ttl,
+		now:     time.Now,
+		fetches: make(chan struct{}, maxConcurrentFetches),
+		users:   map[string]entry{},
+	}
+}
+
+// Lookup returns the cached cosmetics of the user. Unknown and outdated users are fetched in the background,
+// until then the empty or outdated cosmetics are returned.
+func (c *Cache) Lookup(twitchUserID string) Cosmetics {
+	if twitchUserID == "" {
+		return Cosmetics{}
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e, ok := c.users[twitchUserID]
+	if e.pending || (ok && c.now().Sub(e.fetchedAt) < c.ttl) {
+		return e.cosmetics
+	}
+
+	select {
+	case c.fetches <- struct{}{}:
+	default:
+		// all fetches are in use, try again with the next message of the user
+		return e.cosmetics
+	}
+
+	e.pending = true
+	c.users[twitchUserID] = e
+
+	go c.fetch(twitchUserID)
+
+	return e.cosmetics
+}
+
+func (c *Cache) fetch(twitchUserID string) {
+	defer func() { <-c.fetches }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	var cosmetics Cosmetics
+
+	resp, err := c.fetcher.GetTwitchUser(ctx, twitchUserID)
+
+	var apiErr seventv.APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		// no 7TV account
+	case err != nil:
+		// cache the failure like a user without cosmetics, so 7TV isn't asked with every message
+		c.logger.Debug().Err(err).Str("user-id", twitchUserID).Msg("failed to fetch 7TV cosmetics")
+	default:
+		cosmetics = Cosmetics{
+			Paint: resp.User.Style.Paint,
+			Badge: resp.User.Style.Badge,
+		}
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := c.now()
+	if len(c.users) >= maxUsers {
+		for id, e := range c.users {
+			if !e.pending && now.Sub(e.fetchedAt) >= c.ttl {
+				delete(c.users, id)
+			}
+		}
+	}
+
+	c.users[twitchUserID] = entry{cosmetics: cosmetics, fetchedAt: now}
+}
@@ -0,0 +1,108 @@
+package cosmetic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFetcher struct {
+	m     sync.Mutex
+	calls map[string]int
+	users map[string]seventv.TwitchUserResponse
+	err   error
+}
+
+func (f *fakeFetcher) GetTwitchUser(_ context.Context, twitchUserID string) (seventv.TwitchUserResponse, error) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	f.calls[twitchUserID]++
+
+	if f.err != nil {
+		return seventv.TwitchUserResponse{}, f.err
+	}
+
+	user, ok := f.users[twitchUserID]
+	if !ok {
+		return seventv.TwitchUserResponse{}, seventv.APIError{StatusCode: http.StatusNotFound}
+	}
+
+	return user, nil
+}
+
+func (f *fakeFetcher) callsFor(twitchUserID string) int {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	return f.calls[twitchUserID]
+}
+
+func TestCache_Lookup(t *testing.T) {
+	t.Parallel()
+
+	paint := &seventv.Paint{ID: "paint", Function: seventv.PaintLinearGradient}
+	badge := &seventv.Badge{ID: "badge"}
+
+	fetcher := &fakeFetcher{
+		calls: map[string]int{},
+		users: map[string]seventv.TwitchUserResponse{
+			"1": {User: seventv.User{Style: seventv.UserStyle{Paint: paint, Badge: badge}}},
+		},
+	}
+
+	now := time.Now()
+	cache := NewCache(zerolog.Nop(), fetcher, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	// the first lookup starts the fetch in the background
+	require.Equal(t, Cosmetics{}, cache.Lookup("1"))
+	require.Eventually(t, func() bool { return cache.Lookup("1") == Cosmetics{Paint: paint, Badge: badge} }, time.Second, time.Millisecond*5)
+
+	// users without 7TV account are cached as well
+	require.Equal(t, Cosmetics{}, cache.Lookup("2"))
+	require.Eventually(t, func() bool {
+		cache.m.Lock()
+		defer cache.m.Unlock()
+		return !cache.users["2"].pending
+	}, time.Second, time.Millisecond*5)
+
+	require.Equal(t, Cosmetics{}, cache.Lookup("2"))
+	require.Equal(t, Cosmetics{}, cache.Lookup(""))
+	require.Equal(t, 1, fetcher.callsFor("1"))
+	require.Equal(t, 1, fetcher.callsFor("2"))
+
+	// outdated users are fetched again, the old cosmetics are used until then
+	cache.m.Lock()
+	now = now.Add(time.Minute)
+	cache.m.Unlock()
+
+	require.Equal(t, Cosmetics{Paint: paint, Badge: badge}, cache.Lookup("1"))
+	require.Eventually(t, func() bool { return fetcher.callsFor("1") == 2 }, time.Second, time.Millisecond*5)
+}
+
+func TestCache_Lookup_Error(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &fakeFetcher{calls: map[string]int{}, err: errors.New("7tv is down")}
+	cache := NewCache(zerolog.Nop(), fetcher, time.Minute)
+
+	require.Equal(t, Cosmetics{}, cache.Lookup("1"))
+	require.Eventually(t, func() bool {
+		cache.m.Lock()
+		defer cache.m.Unlock()
+		e, ok := cache.users["1"]
+		return ok && !e.pending
+	}, time.Second, time.Millisecond*5)
+
+	// failures aren't retried with every message
+	require.Equal(t, Cosmetics{}, cache.Lookup("1"))
+	require.Equal(t, 1, fetcher.callsFor("1"))
+}
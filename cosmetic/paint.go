@@ -0,0 +1,109 @@
+package cosmetic
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/julez-dev/chatuino/twitch/seventv"
+)
+
+// PaintColors approximates the paint as one hex color per character of a name with n characters.
+// The terminal can't draw gradients, so linear gradients are sampled along the name and radial gradients from its center.
+// Paints which can't be shown as colors, like images, return nil.
+func PaintColors(paint seventv.Paint, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	if len(paint.Stops) == 0 {
+		if paint.Color == nil {
+			return nil
+		}
+
+		colors := make([]string, n)
+		for i := range colors {
+			colors[i] = hexColor(*paint.Color)
+		}
+
+		return colors
+	}
+
+	if paint.Function != seventv.PaintLinearGradient && paint.Function != seventv.PaintRadialGradient {
+		return nil
+	}
+
+	stops := slices.Clone(paint.Stops)
+	slices.SortStableFunc(stops, func(a, b seventv.PaintStop) int {
+		switch {
+		case a.At < b.At:
+			return -1
+		case a.At > b.At:
+			return 1
+		}
+
+		return 0
+	})
+
+	// the name is a single line, so only the horizontal part of the angle matters.
+	// 90° runs left to right, 270° right to left and vertical gradients show their middle on every character.
+	horizontal := math.Sin(float64(paint.Angle) * math.Pi / 180)
+
+	colors := make([]string, n)
+	for i := range colors {
+		t := 0.5
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+
+		if paint.Function == seventv.PaintRadialGradient {
+			t = math.Abs(t-0.5) * 2
+		} else {
+			t = 0.5 + (t-0.5)*horizontal
+		}
+
+		if last := stops[len(stops)-1].At; paint.Repeat && last > 0 && last < 1 {
+			t = math.Mod(t, last)
+		}
+
+		colors[i] = colorAt(stops, t)
+	}
+
+	return colors
+}
+
+// colorAt interpolates the color of the sorted stops at t
+func colorAt(stops []seventv.PaintStop, t float64) string {
+	if t <= stops[0].At {
+		return hexColor(stops[0].Color)
+	}
+
+	for i := 1; i < len(stops); i++ {
+		if t > stops[i].At {
+			continue
+		}
+
+		from, to := stops[i-1], stops[i]
+		if to.At == from.At {
+			return hexColor(to.Color)
+		}
+
+		f := (t - from.At) / (to.At - from.At)
+		fr, fg, fb, _ := seventv.RGBA(from.Color)
+		tr, tg, tb, _ := seventv.RGBA(to.Color)
+
+		mix := func(a, b uint8) uint8 {
+			return uint8(math.Round(float64(a) + (float64(b)-float64(a))*f))
+		}
+
+		return fmt.Sprintf("#%02x%02x%02x", mix(fr, tr), mix(fg, tg), mix(fb, tb))
+	}
+
+	return hexColor(stops[len(stops)-1].Color)
+}
+
+// hexColor formats a 7TV color as hex color, the alpha channel is dropped
+func hexColor(color int32) string {
+	r, g, b, _ := seventv.RGBA(color)
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
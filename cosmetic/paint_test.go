@@ -0,0 +1,89 @@
+package cosmetic
+
+import (
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/stretchr/testify/require"
+)
+
+// color packs a RGBA color like the 7TV API
+func color(rgba uint32) int32 {
+	return int32(rgba)
+}
+
+func TestPaintColors(t *testing.T) {
+	t.Parallel()
+
+	red, blue := color(0xff0000ff), color(0x0000ffff)
+	redToBlue := []seventv.PaintStop{{At: 1, Color: blue}, {At: 0, Color: red}}
+
+	tests := []struct {
+		name  string
+		paint seventv.Paint
+		n     int
+		want  []string
+	}{
+		{
+			name:  "left to right",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Angle: 90, Stops: redToBlue},
+			n:     3,
+			want:  []string{"#ff0000", "#800080", "#0000ff"},
+		},
+		{
+			name:  "right to left",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Angle: 270, Stops: redToBlue},
+			n:     3,
+			want:  []string{"#0000ff", "#800080", "#ff0000"},
+		},
+		{
+			name:  "vertical uses the middle",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Angle: 0, Stops: redToBlue},
+			n:     2,
+			want:  []string{"#800080", "#800080"},
+		},
+		{
+			name:  "radial from the center",
+			paint: seventv.Paint{Function: seventv.PaintRadialGradient, Stops: redToBlue},
+			n:     3,
+			want:  []string{"#0000ff", "#ff0000", "#0000ff"},
+		},
+		{
+			name: "repeating",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Angle: 90, Repeat: true, Stops: []seventv.PaintStop{
+				{At: 0, Color: red},
+				{At: 0.5, Color: blue},
+			}},
+			n:    5,
+			want: []string{"#ff0000", "#800080", "#ff0000", "#800080", "#ff0000"},
+		},
+		{
+			name:  "single character",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Angle: 90, Stops: redToBlue},
+			n:     1,
+			want:  []string{"#800080"},
+		},
+		{
+			name:  "solid color",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Color: &red},
+			n:     2,
+			want:  []string{"#ff0000", "#ff0000"},
+		},
+		{
+			name:  "image",
+			paint: seventv.Paint{Function: seventv.PaintURL, ImageURL: "https://cdn.7tv.app/paint/1/layer.webp"},
+			n:     2,
+		},
+		{
+			name:  "empty name",
+			paint: seventv.Paint{Function: seventv.PaintLinearGradient, Angle: 90, Stops: redToBlue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, PaintColors(tt.paint, tt.n))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/rs/zerolog/log"
+)
+
+// maxReportedLogEvents is the number of recent log events added to panic reports
+const maxReportedLogEvents = 200
+
+// panicReport describes a crash for bug reports: the panic and its stack, the build and system, the settings without secrets and the recent log events
+func panicReport(value any, stack []byte, at time.Time, settings save.Settings) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "Chatuino crashed: %v\n\n", value)
+	_, _ = b.WriteString(bundleInfo(at))
+
+	_, _ = b.WriteString("\nstack:\n")
+	if len(stack) == 0 {
+		_, _ = b.WriteString("not available, the panic happened in a background command of the UI and its stack was printed to the terminal\n")
+	} else {
+		_, _ = b.Write(stack)
+	}
+
+	_, _ = b.WriteString("\nsettings:\n")
+	_, _ = b.WriteString(settings.Summary())
+
+	_, _ = b.WriteString("\nrecent log events:\n")
+	entries := logRing.Entries()
+	for _, entry := range entries[max(len(entries)-maxReportedLogEvents, 0):] {
+		_, _ = b.WriteString(logbuffer.Redact(entry.Raw))
+		_, _ = b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// writePanicReport writes the report to the state directory and marks the session as crashed,
+// so its tabs are restored on the next start with session.restore_after_crash
+func writePanicReport(value any, stack []byte, settings save.Settings) (string, error) {
+	at := time.Now()
+	path := appPaths.PanicReportFile(at)
+
+	if err := os.WriteFile(path, []byte(panicReport(value, stack, at, settings)), 0o600); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(appPaths.CrashMarkerFile(), []byte(path), 0o600); err != nil {
+		return path, fmt.Errorf("failed to mark session as crashed: %w", err)
+	}
+
+	return path, nil
+}
+
+// takeCrashMarker reports whether the previous session crashed, with the path of its panic report, and removes the marker
+func takeCrashMarker() (string, bool) {
+	b, err := os.ReadFile(appPaths.CrashMarkerFile())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Logger.Err(err).Msg("failed to read crash marker")
+		}
+
+		return "", false
+	}
+
+	if err := os.Remove(appPaths.CrashMarkerFile()); err != nil {
+		log.Logger.Err(err).Msg("failed to remove crash marker")
+	}
+
+	return string(b), true
+}
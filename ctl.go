@@ -0,0 +1,92 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/julez-dev/chatuino/ipc"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/urfave/cli/v3"
+)
+
+var ctlCMD = &cli.Command{
+	Name:        "ctl",
+	Usage:       "Control a running Chatuino through its control socket",
+	ArgsUsage:   "<state|open_tab|close_tab|switch_tab|send_message|logs>",
+	Description: "Send a command to the Chatuino instance running with ipc.enabled and print the JSON response. Tabs are selected by --tab, --index or --channel, without any the focused tab is used.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "channel",
+			Usage: "Channel of the opened or selected tab",
+		},
+		&cli.StringFlag{
+			Name:  "account",
+			Usage: "Display name of the account of the opened or selected tab, empty uses the main account",
+		},
+		&cli.StringFlag{
+			Name:  "tab",
+			Usage: "ID of the selected tab, as listed by state",
+		},
+		&cli.IntFlag{
+			Name:  "index",
+			Usage: "Position of the selected tab, starting at 1",
+		},
+		&cli.StringFlag{
+			Name:  "kind",
+			Usage: "Kind of the opened tab: channel, mention, live_notification, youtube, kick or irc_inspector",
+		},
+		&cli.StringFlag{
+			Name:  "text",
+			Usage: "Chat message sent with send_message",
+		},
+		&cli.StringFlag{
+			Name:  "socket",
+			Usage: "Path of the control socket, replaces ipc.socket of the settings",
+		},
+	},
+	Action: func(_ context.Context, command *cli.Command) error {
+		if command.Args().Len() != 1 {
+			return errors.New("expected exactly one command, e.g. chatuino ctl state")
+		}
+
+		socket := command.String("socket")
+		if socket == "" {
+			settings, err := save.SettingsFromDisk()
+			if err != nil {
+				return fmt.Errorf("failed to read settings file: %w\nrun \"chatuino config validate\" for details", err)
+			}
+
+			socket = cmp.Or(settings.IPC.Socket, appPaths.SocketFile())
+		}
+
+		resp, err := ipc.Send(socket, ipc.Request{
+			Command: command.Args().First(),
+			Tab:     command.String("tab"),
+			Index:   command.Int("index"),
+			Channel: command.String("channel"),
+			Account: command.String("account"),
+			Kind:    command.String("kind"),
+			Text:    command.String("text"),
+		})
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+
+		if !resp.OK {
+			return errors.New(resp.Error)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"archive/zip"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/julez-dev/chatuino/ipc"
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/urfave/cli/v3"
+)
+
+// maxBundledLogSize is the size of the end of the log file added to support bundles
+const maxBundledLogSize = 1024 * 1024
+
+var debugCMD = &cli.Command{
+	Name:  "debug",
+	Usage: "Troubleshoot Chatuino",
+	Commands: []*cli.Command{
+		{
+			Name:  "dump",
+			Usage: "Write a support bundle for bug reports",
+			Description: "Write a zip archive with the version and system info, the resolved paths, the settings, theme and keymap files with the result of the settings check, " +
+				"the end of the log file and the recent log events of a running Chatuino with ipc.enabled. Account tokens, API keys and passwords are never included, but review the archive before sharing it.",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "output",
+					Aliases: []string{"o"},
+					Usage:   "Path of the written archive, defaults to chatuino-debug-<time>.zip in the current directory",
+				},
+				&cli.StringFlag{
+					Name:  "socket",
+					Usage: "Path of the control socket, replaces ipc.socket of the settings",
+				},
+			},
+			Action: func(_ context.Context, command *cli.Command) error {
+				output := cmp.Or(command.String("output"), fmt.Sprintf("chatuino-debug-%s.zip", time.Now().Format("2006-01-02_15_04_05")))
+
+				f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+				if err != nil {
+					return fmt.Errorf("failed to create support bundle: %w", err)
+				}
+
+				if err := writeSupportBundle(f, command.String("socket")); err != nil {
+					_ = f.Close()
+					_ = os.Remove(output)
+					return fmt.Errorf("failed to write support bundle: %w", err)
+				}
+
+				if err := f.Close(); err != nil {
+					return fmt.Errorf("failed to write support bundle: %w", err)
+				}
+
+				fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(" Wrote support bundle to "+output))
+
+				return nil
+			},
+		},
+	},
+}
+
+func writeSupportBundle(w io.Writer, socket string) error {
+	archive := zip.NewWriter(w)
+
+	created := time.Now()
+	add := func(name, content string) error {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: created})
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(f, content)
+		return err
+	}
+
+	files := []struct {
+		name string
+		path string
+	}{
+		{"settings.yaml", appPaths.SettingsFile()},
+		{"theme.yaml", appPaths.ThemeFile()},
+		{"keymap.yaml", appPaths.KeymapFile()},
+	}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file.path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			b = []byte(fmt.Sprintf("failed to read %s: %s\n", file.path, err))
+		} else if file.name == "settings.yaml" {
+			// a settings file which can't be parsed may still contain secrets, it's left out and the settings check shows why it's broken
+			stripped, err := save.StripSecretSettings(b)
+			if err != nil {
+				stripped = []byte("settings file could not be parsed, left out, see settings-check.txt\n")
+			}
+
+			b = stripped
+		}
+
+		if err := add(file.name, string(b)); err != nil {
+			return err
+		}
+	}
+
+	settings, settingsCheck := checkBundledSettings()
+
+	if err := add("info.txt", bundleInfo(created)); err != nil {
+		return err
+	}
+
+	if err := add("settings-check.txt", settingsCheck); err != nil {
+		return err
+	}
+
+	if logFile, err := readFileEnd(appPaths.LogFile(), maxBundledLogSize); err == nil {
+		if err := add("chatuino.log", logbuffer.Redact(logFile)); err != nil {
+			return err
+		}
+	}
+
+	socket = cmp.Or(socket, settings.IPC.Socket, appPaths.SocketFile())
+
+	recent := &strings.Builder{}
+	resp, err := ipc.Send(socket, ipc.Request{Command: ipc.CommandLogs})
+
+	switch {
+	case err != nil:
+		fmt.Fprintf(recent, "no recent log events: %s\n", err)
+	case !resp.OK:
+		fmt.Fprintf(recent, "no recent log events: %s\n", resp.Error)
+	default:
+		for _, event := range resp.Logs {
+			_, _ = recent.WriteString(logbuffer.Redact(string(event)))
+			_, _ = recent.WriteString("\n")
+		}
+	}
+
+	if err := add("recent.log", recent.String()); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// bundleInfo describes the build, system and paths
+func bundleInfo(created time.Time) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "version: %s\ncommit: %s\nbuilt at: %s\ngo version: %s\ngoos: %s\ngoarch: %s\ncreated at: %s\n\n",
+		Version, Commit, Date, runtime.Version(), runtime.GOOS, runtime.GOARCH, created.Format(time.RFC3339))
+
+	for _, env := range []string{"TERM", "TERM_PROGRAM", "TERM_PROGRAM_VERSION", "COLORTERM", "NO_COLOR", "TMUX"} {
+		fmt.Fprintf(b, "%s=%s\n", env, os.Getenv(env))
+	}
+
+	fmt.Fprintf(b, "\nconfig: %s\ndata: %s\nstate: %s\nruntime: %s\n", appPaths.Config, appPaths.Data, appPaths.State, appPaths.Runtime)
+
+	return b.String()
+}
+
+// checkBundledSettings returns the settings used by Chatuino and the issues of the settings file
+func checkBundledSettings() (save.Settings, string) {
+	b, err := os.ReadFile(appPaths.SettingsFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return save.BuildDefaultSettings(), "no settings file, the default settings are used\n"
+	}
+
+	if err != nil {
+		return save.BuildDefaultSettings(), fmt.Sprintf("failed to read settings file: %s\n", err)
+	}
+
+	settings, report := save.CheckSettings(b)
+
+	text := &strings.Builder{}
+	fmt.Fprintf(text, "settings version: %d, current version: %d\n", report.Version, save.CurrentSettingsVersion)
+
+	for _, m := range report.Migrations {
+		fmt.Fprintf(text, "migration: %s\n", m)
+	}
+
+	for _, issue := range report.Issues {
+		level := "error"
+		if issue.Warning {
+			level = "warning"
+		}
+
+		fmt.Fprintf(text, "%s: %s\n", level, issue)
+	}
+
+	if len(report.Issues) == 0 {
+		_, _ = text.WriteString("no issues\n")
+	}
+
+	return settings, text.String()
+}
+
+// readFileEnd returns the last size bytes of the file
+func readFileEnd(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if stat.Size() > size {
+		if _, err := f.Seek(-size, io.SeekEnd); err != nil {
+			return "", err
+		}
+	}
+
+	b, err := io.ReadAll(f)
+	return string(b), err
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
 )
 
 var ErrPartialFetch = errors.New("emote data could only be partially fetched")
@@ -64,10 +65,28 @@ type Cache struct {
 	single          *singleflight.Group
 	channelsFetched map[string]struct{}
 	globalFetched   bool
+
+	// platforms which could not be fetched on the last refresh, per stored set key
+	degraded map[string][]Platform
+
+	// fetched sets are stored in dir and used when a platform can't be reached, disabled when fs is nil
+	fs  afero.Fs
+	dir string
 }
 
-func NewCache(logger zerolog.Logger, twitchEmotes TwitchEmoteFetcher, sevenTVEmotes SevenTVEmoteFetcher, bttvEmotes BTTVEmoteFetcher, ffzEmotes FFZEmoteFetcher) *Cache {
-	return &Cache{
+type CacheOption func(*Cache)
+
+// WithDiskCache stores the fetched emote sets in dir. When a platform can't be reached,
+// the emotes stored for it on the last successful fetch are used instead.
+func WithDiskCache(fs afero.Fs, dir string) CacheOption {
+	return func(c *Cache) {
+		c.fs = fs
+		c.dir = dir
+	}
+}
+
+func NewCache(logger zerolog.Logger, twitchEmotes TwitchEmoteFetcher, sevenTVEmotes SevenTVEmoteFetcher, bttvEmotes BTTVEmoteFetcher, ffzEmotes FFZEmoteFetcher, opts ...CacheOption) *Cache {
+	c := &Cache{
 		logger:          logger,
 		m:               &sync.RWMutex{},
 		channel:         map[string]EmoteSet{},
@@ -77,13 +96,27 @@ func NewCache(logger zerolog.Logger, twitchEmotes TwitchEmoteFetcher, sevenTVEmo
 		ffzEmotes:       ffzEmotes,
 		single:          &singleflight.Group{},
 		channelsFetched: map[string]struct{}{},
+		degraded:        map[string][]Platform{},
 		user:            map[string]EmoteSet{},
 		foreignEmotes:   map[string]Emote{},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// refreshResult is the outcome of a fetch shared by singleflight
+type refreshResult struct {
+	set    EmoteSet
+	failed []Platform
 }
 
 // RefreshLocal refreshes the local emote cache for a specific channel.
-// When a 3rd party API fails, the cache will still be refreshed but a ErrPartialFetch will be returned.
+// When an API fails, the emotes stored on disk for it are used and a ErrPartialFetch is returned.
+// The channel is fetched again on the next call until all APIs succeeded.
 func (s *Cache) RefreshLocal(ctx context.Context, channelID string) error {
 	s.m.RLock()
 	if _, isCached := s.channelsFetched[channelID]; isCached {
@@ -92,7 +125,9 @@ func (s *Cache) RefreshLocal(ctx context.Context, channelID string) error {
 	}
 	s.m.RUnlock()
 
-	set, err, _ := s.single.Do("channel"+channelID, func() (any, error) {
+	key := "channel_" + channelID
+
+	result, err, _ := s.single.Do(key, func() (any, error) {
 		var (
 			ttvResp  twitchapi.EmoteResponse
 			stvResp  seventv.ChannelEmoteResponse
@@ -100,7 +135,8 @@ func (s *Cache) RefreshLocal(ctx context.Context, channelID string) error {
 			ffzResp  []ffz.Emote
 
 			fetchErrs  error
-			errSevenTV error // routine will not cancel when 3rd party fails
+			errTwitch  error // routines will not cancel when an API fails, the stored emotes are used instead
+			errSevenTV error
 			errBTTV    error
 			errFFZ     error
 		)
@@ -110,7 +146,9 @@ func (s *Cache) RefreshLocal(ctx context.Context, channelID string) error {
 		group.Go(func() error {
 			resp, err := s.twitchEmotes.GetChannelEmotes(ctx, channelID)
 			if err != nil {
-				return err
+				s.logger.Error().Str("channel_id", channelID).Err(err).Msg("could not fetch Twitch emotes")
+				errTwitch = fmt.Errorf("could not fetch Twitch emotes: %w", err)
+				return nil
 			}
 
 			ttvResp = resp
@@ -161,7 +199,7 @@ func (s *Cache) RefreshLocal(ctx context.Context, channelID string) error {
 			return nil, err
 		}
 
-		fetchErrs = errors.Join(errSevenTV, errBTTV, errFFZ)
+		fetchErrs = errors.Join(errTwitch, errSevenTV, errBTTV, errFFZ)
 
 		emoteSet := make(EmoteSet, 0, len(ttvResp.Data)+len(stvResp.EmoteSet.Emotes)+len(bttvResp.ChannelEmotes)+len(ffzResp))
 
@@ -227,21 +265,41 @@ func (s *Cache) RefreshLocal(ctx context.Context, channelID string) error {
 			})
 		}
 
+		failed := failedPlatforms(map[Platform]error{Twitch: errTwitch, SevenTV: errSevenTV, BTTV: errBTTV, FFZ: errFFZ})
+		result := refreshResult{set: s.withStoredEmotes(key, emoteSet, failed), failed: failed}
+
 		if fetchErrs != nil {
-			return emoteSet, fmt.Errorf("%w: %w", ErrPartialFetch, fetchErrs)
+			return result, fmt.Errorf("%w: %w", ErrPartialFetch, fetchErrs)
 		}
 
-		return emoteSet, nil
+		return result, nil
 	})
 
+	fetched := result.(refreshResult)
+
 	s.m.Lock()
 	defer s.m.Unlock()
-	s.channelsFetched[channelID] = struct{}{}
-	s.channel[channelID] = set.(EmoteSet)
+	if err == nil {
+		s.channelsFetched[channelID] = struct{}{}
+	}
+	s.channel[channelID] = fetched.set
+	s.degraded[key] = fetched.failed
 
 	return err
 }
 
+// Reload fetches the emotes of a channel again, like RefreshLocal. The current emotes are used until the fetch finished.
+func (s *Cache) Reload(ctx context.Context, channelID string) error {
+	s.m.Lock()
+	delete(s.channelsFetched, channelID)
+	s.m.Unlock()
+
+	return s.RefreshLocal(ctx, channelID)
+}
+
+// RefreshGlobal refreshes the global emotes. When a 3rd party API fails, only the failure is logged.
+// Failed APIs are replaced with the emotes stored on disk, if the Twitch API fails a ErrPartialFetch is returned.
+// The global emotes are fetched again on the next call until all APIs succeeded.
 func (s *Cache) RefreshGlobal(ctx context.Context) error {
 	s.m.RLock()
 	if s.globalFetched {
@@ -250,7 +308,7 @@ func (s *Cache) RefreshGlobal(ctx context.Context) error {
 	}
 	s.m.RUnlock()
 
-	set, err, shared := s.single.Do("global", func() (any, error) {
+	result, err, shared := s.single.Do("global", func() (any, error) {
 		group, ctx := errgroup.WithContext(ctx)
 
 		var (
@@ -258,12 +316,19 @@ func (s *Cache) RefreshGlobal(ctx context.Context) error {
 			stvResp  seventv.EmoteResponse
 			bttvResp bttv.GlobalEmoteResponse
 			ffzResp  []ffz.Emote
+
+			errTwitch  error
+			errSevenTV error
+			errBTTV    error
+			errFFZ     error
 		)
 
 		group.Go(func() error {
 			resp, err := s.twitchEmotes.GetGlobalEmotes(ctx)
 			if err != nil {
-				return err
+				s.logger.Error().Err(err).Msg("could not fetch Twitch global emotes")
+				errTwitch = err
+				return nil
 			}
 
 			ttvResp = resp
@@ -274,6 +339,7 @@ func (s *Cache) RefreshGlobal(ctx context.Context) error {
 			resp, err := s.sevenTVEmotes.GetGlobalEmotes(ctx)
 			if err != nil {
 				s.logger.Error().Err(err).Msg("could not fetch 7TV global emotes")
+				errSevenTV = err
 				return nil
 			}
 
@@ -285,6 +351,7 @@ func (s *Cache) RefreshGlobal(ctx context.Context) error {
 			resp, err := s.bttvEmotes.GetGlobalEmotes(ctx)
 			if err != nil {
 				s.logger.Error().Err(err).Msg("could not fetch BTTV global emotes")
+				errBTTV = err
 				return nil
 			}
 
@@ -296,6 +363,7 @@ func (s *Cache) RefreshGlobal(ctx context.Context) error {
 			resp, err := s.ffzEmotes.GetGlobalEmotes(ctx)
 			if err != nil {
 				s.logger.Error().Err(err).Msg("could not fetch FFZ global emotes")
+				errFFZ = err
 				return nil
 			}
 
@@ -359,21 +427,39 @@ func (s *Cache) RefreshGlobal(ctx context.Context) error {
 			})
 		}
 
-		return emoteSet, nil
+		failed := failedPlatforms(map[Platform]error{Twitch: errTwitch, SevenTV: errSevenTV, BTTV: errBTTV, FFZ: errFFZ})
+		result := refreshResult{set: s.withStoredEmotes("global", emoteSet, failed), failed: failed}
+
+		if errTwitch != nil {
+			return result, fmt.Errorf("%w: could not fetch Twitch global emotes: %w", ErrPartialFetch, errTwitch)
+		}
+
+		return result, nil
 	})
 
-	if err != nil {
-		return err
-	}
+	fetched := result.(refreshResult)
 
-	log.Logger.Info().Bool("shared", shared).Msg("refreshed global emote set channel")
+	log.Logger.Info().Bool("shared", shared).Int("failed", len(fetched.failed)).Msg("refreshed global emote set channel")
 
 	s.m.Lock()
 	defer s.m.Unlock()
-	s.globalFetched = true
-	s.global = set.(EmoteSet)
+	s.globalFetched = len(fetched.failed) == 0
+	s.global = fetched.set
+	s.degraded["global"] = fetched.failed
+
+	return err
+}
+
+// Degraded returns the platforms whose global or channel emotes could not be fetched on the last refresh.
+// Their emotes are the ones stored on disk, if there are any.
+func (s *Cache) Degraded(channelID string) []Platform {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	platforms := slices.Concat(s.degraded["global"], s.degraded["channel_"+channelID])
+	slices.Sort(platforms)
 
-	return nil
+	return slices.Compact(platforms)
 }
 
 // GetAllForChannel retrieves all emotes for a specific user.
@@ -499,6 +585,7 @@ func (s *Cache) RemoveEmoteSetForChannel(channelID string) {
 
 	delete(s.channel, channelID)
 	delete(s.channelsFetched, channelID)
+	delete(s.degraded, "channel_"+channelID)
 }
 
 func (s *Cache) AddUserEmotes(userID string, emotes []Emote) {
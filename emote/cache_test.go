@@ -92,6 +92,43 @@ func TestRefreshLocal(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestReload(t *testing.T) {
+	ttv := mocks.NewMockTwitchEmoteFetcher(t)
+	seven := mocks.NewMockSevenTVEmoteFetcher(t)
+	bttvService := mocks.NewMockBTTVEmoteFetcher(t)
+	ffzService := mocks.NewMockFFZEmoteFetcher(t)
+
+	sevenTVSet := func(names ...string) seventv.ChannelEmoteResponse {
+		var resp seventv.ChannelEmoteResponse
+		for _, name := range names {
+			resp.EmoteSet.Emotes = append(resp.EmoteSet.Emotes, seventv.Emote{ID: name + "-id", Name: name})
+		}
+
+		return resp
+	}
+
+	ttv.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Times(2).Return(twitchapi.EmoteResponse{}, nil)
+	bttvService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Times(2).Return(bttv.UserResponse{}, nil)
+	ffzService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Times(2).Return(nil, nil)
+	seven.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(sevenTVSet("OMEGALUL", "Clap"), nil)
+	seven.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(sevenTVSet("Clap", "peepoSnow"), nil)
+
+	store := emote.NewCache(zerolog.Nop(), ttv, seven, bttvService, ffzService)
+
+	require.NoError(t, store.RefreshLocal(context.Background(), "test-channel"))
+	before := store.GetAllForChannel("test-channel")
+
+	// fetched channels are only fetched again by Reload
+	require.NoError(t, store.RefreshLocal(context.Background(), "test-channel"))
+	require.NoError(t, store.Reload(context.Background(), "test-channel"))
+
+	added, removed := emote.Diff(before, store.GetAllForChannel("test-channel"))
+	require.Len(t, added, 1)
+	require.Equal(t, "peepoSnow", added[0].Text)
+	require.Len(t, removed, 1)
+	require.Equal(t, "OMEGALUL", removed[0].Text)
+}
+
 func TestRefreshGlobal_3rdPartyFailureNonBlocking(t *testing.T) {
 	t.Parallel()
 
@@ -51,3 +51,37 @@ func (set EmoteSet) GetByText(text string) (Emote, bool) {
 
 	return Emote{}, false
 }
+
+// Diff returns the emotes only in after and the emotes only in before. Emotes are compared by platform, ID and name,
+// so a renamed emote is removed with its old and added with its new name.
+func Diff(before, after EmoteSet) (added, removed EmoteSet) {
+	type key struct {
+		platform Platform
+		id, text string
+	}
+
+	keys := func(set EmoteSet) map[key]struct{} {
+		m := make(map[key]struct{}, len(set))
+		for _, e := range set {
+			m[key{e.Platform, e.ID, e.Text}] = struct{}{}
+		}
+
+		return m
+	}
+
+	beforeKeys, afterKeys := keys(before), keys(after)
+
+	for _, e := range after {
+		if _, ok := beforeKeys[key{e.Platform, e.ID, e.Text}]; !ok {
+			added = append(added, e)
+		}
+	}
+
+	for _, e := range before {
+		if _, ok := afterKeys[key{e.Platform, e.ID, e.Text}]; !ok {
+			removed = append(removed, e)
+		}
+	}
+
+	return added, removed
+}
@@ -60,10 +60,20 @@ func (i *Replacer) Replace(channelID, content string, emoteList []twitchirc.Emot
 	// parse the emote text with the index and replace it from the global store, since its guaranteed
 	// the user has access to the emote
 	emotesFromIRCTag := map[string]string{} // emoteText:emoteID
+
+	r := []rune(strings.TrimPrefix(content, "\x01ACTION ")) // convert to runes for multi byte handling
 	for _, e := range emoteList {
-		c := strings.TrimPrefix(content, "\x01ACTION ")
-		r := []rune(c) // convert to runes for multi byte handling
-		emoteText := string(r[e.Positions[0].Start : e.Positions[0].End+1])
+		// the tag comes from the server, skip positions outside of the message instead of trusting them
+		if len(e.Positions) == 0 {
+			continue
+		}
+
+		start, end := e.Positions[0].Start, e.Positions[0].End
+		if start < 0 || start > end || end >= len(r) {
+			continue
+		}
+
+		emoteText := string(r[start : end+1])
 
 		emotesFromIRCTag[emoteText] = e.ID
 	}
@@ -341,6 +341,41 @@ func TestReplacer_Replace_MultipleEmotes(t *testing.T) {
 	require.Equal(t, 2, callCount, "should convert 2 emotes")
 }
 
+func Fuzz_Replacer_Replace(f *testing.F) {
+	f.Add("25:0-4", "Kappa")
+	f.Add("25:0-4,12-16/1902:6-10", "Kappa Keepo Kappa")
+	f.Add("25:8-12", "\x01ACTION Kappa\x01")
+	f.Add("25:2-6", "日本 Kappa")
+	f.Add("25:10-4", "Kappa")
+	f.Add("25:-1-4", "Kappa")
+	f.Add("25:0-400", "Kappa")
+	f.Add("", "Kappa and PogChamp")
+
+	store := &mockEmoteStore{
+		emotes: map[string]Emote{
+			"Kappa":    {ID: "kappa-id", Text: "Kappa", Platform: Twitch},
+			"PogChamp": {ID: "pogchamp-id", Text: "PogChamp", Platform: Twitch},
+		},
+	}
+	replacer := NewReplacer(nil, store, false, save.Theme{}, nil)
+
+	// the emotes tag and the message come from the chat, positions outside of the message must not panic
+	f.Fuzz(func(t *testing.T, emotes, content string) {
+		msg, err := twitchirc.ParseIRC("@emotes=" + emotes + " :a!a@a.tmi.twitch.tv PRIVMSG #a :" + content)
+		if err != nil {
+			t.Skip()
+		}
+
+		pm, ok := msg.(*twitchirc.PrivateMessage)
+		if !ok {
+			t.Skip()
+		}
+
+		_, _, err = replacer.Replace("channel", pm.Message, pm.Emotes)
+		require.NoError(t, err)
+	})
+}
+
 type mockEmoteStore struct {
 	emotes        map[string]Emote
 	foreignEmotes map[string]Emote
@@ -0,0 +1,105 @@
+package emote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// failedPlatforms returns the platforms whose fetch returned an error, ordered by platform
+func failedPlatforms(errs map[Platform]error) []Platform {
+	var failed []Platform
+	for platform, err := range errs {
+		if err != nil {
+			failed = append(failed, platform)
+		}
+	}
+
+	slices.Sort(failed)
+
+	return failed
+}
+
+// withStoredEmotes adds the emotes stored on disk for the failed platforms to a freshly fetched set
+// and stores the result, so the next failing fetch can use it.
+func (s *Cache) withStoredEmotes(key string, set EmoteSet, failed []Platform) EmoteSet {
+	if s.fs == nil {
+		return set
+	}
+
+	if len(failed) > 0 {
+		stored, err := s.loadStoredSet(key)
+		if err != nil {
+			s.logger.Error().Err(err).Str("set", key).Msg("could not load stored emote set")
+		}
+
+		for _, e := range stored {
+			if slices.Contains(failed, e.Platform) {
+				set = append(set, e)
+			}
+		}
+	}
+
+	if err := s.storeSet(key, set); err != nil {
+		s.logger.Error().Err(err).Str("set", key).Msg("could not store emote set")
+	}
+
+	return set
+}
+
+func (s *Cache) storedSetFile(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// loadStoredSet reads the emote set stored for key, a set which was never stored is empty
+func (s *Cache) loadStoredSet(key string) (EmoteSet, error) {
+	f, err := s.fs.Open(s.storedSetFile(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var set EmoteSet
+	if err := json.NewDecoder(f).Decode(&set); err != nil {
+		return nil, fmt.Errorf("could not decode stored emote set %s: %w", key, err)
+	}
+
+	return set, nil
+}
+
+// storeSet writes the set to a temporary file first, so a crash never leaves a broken set behind
+func (s *Cache) storeSet(key string, set EmoteSet) error {
+	if err := s.fs.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.storedSetFile(key) + ".tmp"
+	f, err := s.fs.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return s.fs.Rename(tmp, s.storedSetFile(key))
+}
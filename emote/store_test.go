@@ -0,0 +1,95 @@
+package emote_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/mocks"
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/rs/zerolog"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshLocal_StoredFallback(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	ttv := mocks.NewMockTwitchEmoteFetcher(t)
+	seven := mocks.NewMockSevenTVEmoteFetcher(t)
+	bttvService := mocks.NewMockBTTVEmoteFetcher(t)
+	ffzService := mocks.NewMockFFZEmoteFetcher(t)
+
+	// first session, every platform is reachable
+	ttv.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(twitchapi.EmoteResponse{
+		Data: []twitchapi.EmoteData{{ID: "ttv", Name: "Kappa"}},
+	}, nil)
+	seven.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(seventv.ChannelEmoteResponse{}, nil)
+	bttvService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(bttv.UserResponse{
+		ChannelEmotes: []bttv.Emote{{ID: "bttv", Code: "catJAM"}},
+	}, nil)
+	ffzService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(nil, nil)
+
+	store := emote.NewCache(zerolog.Nop(), ttv, seven, bttvService, ffzService, emote.WithDiskCache(fs, "/data/emote_sets"))
+	require.NoError(t, store.RefreshLocal(context.Background(), "test-channel"))
+	require.Empty(t, store.Degraded("test-channel"))
+
+	// second session, Twitch and BTTV are down
+	ttv.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(twitchapi.EmoteResponse{}, twitchapi.APIError{Status: 503})
+	seven.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Twice().Return(seventv.ChannelEmoteResponse{}, nil)
+	bttvService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(bttv.UserResponse{}, bttv.APIError{StatusCode: 503})
+	ffzService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Twice().Return(nil, nil)
+
+	restarted := emote.NewCache(zerolog.Nop(), ttv, seven, bttvService, ffzService, emote.WithDiskCache(fs, "/data/emote_sets"))
+	err := restarted.RefreshLocal(context.Background(), "test-channel")
+	require.ErrorIs(t, err, emote.ErrPartialFetch)
+	require.Equal(t, []emote.Platform{emote.Twitch, emote.BTTV}, restarted.Degraded("test-channel"))
+
+	set := restarted.GetAllForChannel("test-channel")
+	_, ok := set.GetByText("Kappa")
+	require.True(t, ok)
+	_, ok = set.GetByText("catJAM")
+	require.True(t, ok)
+
+	// the failed channel is fetched again on the next refresh
+	ttv.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(twitchapi.EmoteResponse{
+		Data: []twitchapi.EmoteData{{ID: "ttv", Name: "Kappa"}},
+	}, nil)
+	bttvService.EXPECT().GetChannelEmotes(mock.Anything, "test-channel").Once().Return(bttv.UserResponse{}, nil)
+
+	require.NoError(t, restarted.RefreshLocal(context.Background(), "test-channel"))
+	require.Empty(t, restarted.Degraded("test-channel"))
+
+	_, ok = restarted.GetAllForChannel("test-channel").GetByText("catJAM")
+	require.False(t, ok, "emotes removed from a reachable platform are not kept")
+}
+
+func TestRefreshGlobal_StoredFallback(t *testing.T) {
+	t.Parallel()
+
+	ttv := mocks.NewMockTwitchEmoteFetcher(t)
+	seven := mocks.NewMockSevenTVEmoteFetcher(t)
+	bttvService := mocks.NewMockBTTVEmoteFetcher(t)
+	ffzService := mocks.NewMockFFZEmoteFetcher(t)
+
+	ttv.EXPECT().GetGlobalEmotes(mock.Anything).Once().Return(twitchapi.EmoteResponse{}, twitchapi.APIError{Status: 500})
+	seven.EXPECT().GetGlobalEmotes(mock.Anything).Once().Return(seventv.EmoteResponse{}, nil)
+	bttvService.EXPECT().GetGlobalEmotes(mock.Anything).Once().Return(bttv.GlobalEmoteResponse{}, nil)
+	ffzService.EXPECT().GetGlobalEmotes(mock.Anything).Once().Return([]ffz.Emote{{ID: 1, Name: "GlobalFFZEmote"}}, nil)
+
+	// without a stored set the global emotes of the other platforms are still used
+	store := emote.NewCache(zerolog.Nop(), ttv, seven, bttvService, ffzService, emote.WithDiskCache(afero.NewMemMapFs(), "/data/emote_sets"))
+
+	err := store.RefreshGlobal(context.Background())
+	require.ErrorIs(t, err, emote.ErrPartialFetch)
+	require.Equal(t, []emote.Platform{emote.Twitch}, store.Degraded(""))
+
+	_, ok := store.GetAllForChannel("").GetByText("GlobalFFZEmote")
+	require.True(t, ok)
+}
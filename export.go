@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/julez-dev/chatuino/chatexport"
+	"github.com/julez-dev/chatuino/save/messagelog"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+)
+
+var exportCMD = &cli.Command{
+	Name:  "export",
+	Usage: "Export logged chat messages of a channel",
+	Description: "Write the messages of a channel stored with moderation.store_chat_logs as plain text, JSON or CSV, oldest first. " +
+		"--since and --until accept a duration before now (2h), a date (2006-01-02) or an RFC 3339 timestamp.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "channel",
+			Usage:    "Channel of the exported messages",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "user",
+			Usage: "Only export messages of this user",
+		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output format: text, json or csv",
+			Value:   string(chatexport.FormatText),
+		},
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "Only export messages sent after this time",
+		},
+		&cli.StringFlag{
+			Name:  "until",
+			Usage: "Only export messages sent before this time",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Path of the written file, defaults to stdout",
+		},
+	},
+	Action: func(_ context.Context, command *cli.Command) error {
+		format, err := chatexport.ParseFormat(command.String("format"))
+		if err != nil {
+			return err
+		}
+
+		var timeRange chatexport.Range
+		now := time.Now()
+
+		if since := command.String("since"); since != "" {
+			if timeRange.Since, err = chatexport.ParseTime(since, now); err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+		}
+
+		if until := command.String("until"); until != "" {
+			if timeRange.Until, err = chatexport.ParseTime(until, now); err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+		}
+
+		db, err := openDB(true)
+		if err != nil {
+			return fmt.Errorf("failed to open sqlite db: %w", err)
+		}
+
+		defer func() {
+			if err := db.Close(); err != nil {
+				log.Logger.Err(err).Msg("failed to close db connection")
+			}
+		}()
+
+		messageLogger := messagelog.NewBatchedMessageLogger(log.Logger, nil, db, nil, nil)
+
+		channel := strings.TrimPrefix(command.String("channel"), "#")
+		entries, err := messageLogger.MessagesInChannel(channel)
+		if err != nil {
+			return fmt.Errorf("failed to read chat logs: %w", err)
+		}
+
+		user := command.String("user")
+		messages := make([]chatexport.Message, 0, len(entries))
+		for _, entry := range entries {
+			if user != "" && !strings.EqualFold(entry.PrivateMessage.LoginName, user) && !strings.EqualFold(entry.SenderDisplay, user) {
+				continue
+			}
+
+			msg := chatexport.FromPrivateMessage(entry.PrivateMessage)
+			msg.SentAt = entry.SentAt
+			messages = append(messages, msg)
+		}
+
+		messages = timeRange.Filter(messages)
+
+		output := command.String("output")
+		if output == "" {
+			return chatexport.Write(os.Stdout, format, messages)
+		}
+
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create export: %w", err)
+		}
+
+		if err := chatexport.Write(f, format, messages); err != nil {
+			_ = f.Close()
+			_ = os.Remove(output)
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+
+		fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Exported %d messages to %s", len(messages), output)))
+
+		return nil
+	},
+}
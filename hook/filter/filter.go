@@ -0,0 +1,372 @@
+// Package filter implements the small expression language used to filter the events passed to hooks.
+//
+// An expression compares fields with values and combines comparisons with &&, || and !, e.g.
+//
+//	channel == "lirik" && !(user == "nightbot" || message contains "http")
+//
+// Supported operators are == and != (case-insensitive), contains (case-insensitive), matches (regular expression)
+// and the numeric comparisons <, <=, > and >=. A field without operator is true if it is not empty, false or 0.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed expression
+type Filter struct {
+	root node
+}
+
+// Parse parses expr. Only the given fields may be used, nil allows all fields.
+// An empty expression matches every event.
+func Parse(expr string, fields []string) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return &Filter{}, nil
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos+1)
+	}
+
+	return &Filter{root: root}, nil
+}
+
+// Match evaluates the filter with the field values, missing fields are empty
+func (f *Filter) Match(fields map[string]string) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+
+	return f.root.eval(fields)
+}
+
+type node interface {
+	eval(fields map[string]string) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(fields map[string]string) bool {
+	return n.left.eval(fields) && n.right.eval(fields)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(fields map[string]string) bool {
+	return n.left.eval(fields) || n.right.eval(fields)
+}
+
+type notNode struct{ inner node }
+
+func (n notNode) eval(fields map[string]string) bool {
+	return !n.inner.eval(fields)
+}
+
+type truthyNode struct{ field string }
+
+func (n truthyNode) eval(fields map[string]string) bool {
+	switch v := fields[n.field]; strings.ToLower(v) {
+	case "", "false", "0":
+		return false
+	}
+
+	return true
+}
+
+type compareNode struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp // set for matches
+}
+
+func (n compareNode) eval(fields map[string]string) bool {
+	v := fields[n.field]
+
+	switch n.op {
+	case "==":
+		return strings.EqualFold(v, n.value)
+	case "!=":
+		return !strings.EqualFold(v, n.value)
+	case "contains":
+		return strings.Contains(strings.ToLower(v), strings.ToLower(n.value))
+	case "matches":
+		return n.re.MatchString(v)
+	}
+
+	a, errA := strconv.ParseFloat(v, 64)
+	b, errB := strconv.ParseFloat(n.value, 64)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	switch n.op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return strconv.Quote(t.value)
+	}
+
+	return fmt.Sprintf("%q", t.value)
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, value: ")", pos: i})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("expected %c%c at position %d", r, r, i+1)
+			}
+
+			kind := tokenAnd
+			if r == '|' {
+				kind = tokenOr
+			}
+
+			tokens = append(tokens, token{kind: kind, value: string([]rune{r, r}), pos: i})
+			i += 2
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+
+			switch op {
+			case "=":
+				return nil, fmt.Errorf("expected == at position %d", i+1)
+			case "!":
+				tokens = append(tokens, token{kind: tokenNot, value: op, pos: i})
+			default:
+				tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+			}
+
+			i += len(op)
+		case r == '"' || r == '\'':
+			start := i
+			var b strings.Builder
+
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: b.String(), pos: start})
+			i++
+		case unicode.IsDigit(r) || r == '-':
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for ; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_'); i++ {
+			}
+
+			word := string(runes[start:i])
+			kind := tokenIdent
+			if word == "contains" || word == "matches" {
+				kind = tokenOperator
+			}
+
+			tokens = append(tokens, token{kind: kind, value: word, pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i+1)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	fields []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNot:
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notNode{inner: inner}, nil
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenClose {
+			return nil, fmt.Errorf("expected ) at position %d, got %s", closing.pos+1, closing)
+		}
+
+		return inner, nil
+	case tokenIdent:
+		return p.parseComparison(t)
+	}
+
+	return nil, fmt.Errorf("expected field at position %d, got %s", t.pos+1, t)
+}
+
+func (p *parser) parseComparison(field token) (node, error) {
+	if p.fields != nil && !slices.Contains(p.fields, field.value) {
+		return nil, fmt.Errorf("unknown field %q, available fields are %s", field.value, strings.Join(p.fields, ", "))
+	}
+
+	if p.peek().kind != tokenOperator {
+		return truthyNode{field: field.value}, nil
+	}
+
+	op := p.next()
+
+	value := p.next()
+	if value.kind != tokenString && value.kind != tokenNumber && value.kind != tokenIdent {
+		return nil, fmt.Errorf("expected value after %s at position %d, got %s", op.value, value.pos+1, value)
+	}
+
+	n := compareNode{field: field.value, op: op.value, value: value.value}
+
+	switch op.value {
+	case "matches":
+		re, err := regexp.Compile(value.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", value.value, err)
+		}
+		n.re = re
+	case "<", "<=", ">", ">=":
+		if _, err := strconv.ParseFloat(value.value, 64); err != nil {
+			return nil, fmt.Errorf("expected number after %s at position %d, got %s", op.value, value.pos+1, value)
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	fields := map[string]string{
+		"channel": "lirik",
+		"user":    "NightBot",
+		"message": "check https://example.com",
+		"viewers": "120",
+		"mod":     "false",
+	}
+
+	cases := []struct {
+		expr  string
+		match bool
+	}{
+		{expr: "", match: true},
+		{expr: `channel == "LIRIK"`, match: true},
+		{expr: `channel != 'lirik'`, match: false},
+		{expr: `user == nightbot && message contains "HTTPS"`, match: true},
+		{expr: `user == nightbot && !(message contains "http")`, match: false},
+		{expr: `channel == "other" || viewers >= 100`, match: true},
+		{expr: `viewers < 100`, match: false},
+		{expr: `message matches "^check\\s+https?://"`, match: true},
+		{expr: `mod`, match: false},
+		{expr: `!mod && channel`, match: true},
+		{expr: `months > 3`, match: false}, // missing fields are empty
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := Parse(tc.expr, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.match, f.Match(fields))
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr string
+		err  string
+	}{
+		{expr: `channel = "lirik"`, err: "expected == at position 9"},
+		{expr: `channel == "lirik`, err: "unterminated string at position 12"},
+		{expr: `(channel == "lirik"`, err: `expected ) at position 20, got end of filter`},
+		{expr: `channel ==`, err: "expected value after == at position 11, got end of filter"},
+		{expr: `viewers > many`, err: `expected number after > at position 11, got "many"`},
+		{expr: `message matches "("`, err: `invalid regular expression "("`},
+		{expr: `chanel == "lirik"`, err: `unknown field "chanel", available fields are channel, user`},
+		{expr: `channel & user`, err: "expected && at position 9"},
+		{expr: `channel user`, err: `unexpected "user" at position 9`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tc.expr, []string{"channel", "user", "message", "viewers"})
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,298 @@
+// Package hook runs user configured external commands on chat events.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julez-dev/chatuino/hook/filter"
+	"github.com/julez-dev/chatuino/internal/cmdout"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxRunning is the maximum number of hook commands running at the same time, further events are dropped
+	maxRunning = 16
+	// seenLimit is the number of event IDs remembered to skip events received by multiple connections
+	seenLimit = 512
+	// outputLimit is the amount of bytes kept from the error output of a command
+	outputLimit = 4096
+)
+
+// Event is written as JSON to the stdin of hook commands
+type Event struct {
+	Type        string    `json:"type"`
+	ID          string    `json:"id"`
+	Channel     string    `json:"channel"`
+	ChannelID   string    `json:"channel_id"`
+	User        string    `json:"user"`
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	Message     string    `json:"message,omitempty"`
+	Mod         bool      `json:"mod"`
+	Subscriber  bool      `json:"subscriber"`
+	Bot         bool      `json:"bot"` // known bot of FFZ or BTTV
+	Timestamp   time.Time `json:"timestamp"`
+	Viewers     int       `json:"viewers,omitempty"` // raid
+	Months      int       `json:"months,omitempty"`  // sub
+	Plan        string    `json:"plan,omitempty"`    // sub
+	Gifter      string    `json:"gifter,omitempty"`  // gifted sub, the user is the recipient
+}
+
+// Fields returns the values available in filters, see save.HookFilterFields
+func (e Event) Fields() map[string]string {
+	return map[string]string{
+		"event":        e.Type,
+		"channel":      e.Channel,
+		"user":         e.User,
+		"display_name": e.DisplayName,
+		"message":      e.Message,
+		"mod":          strconv.FormatBool(e.Mod),
+		"subscriber":   strconv.FormatBool(e.Subscriber),
+		"bot":          strconv.FormatBool(e.Bot),
+		"viewers":      strconv.Itoa(e.Viewers),
+		"months":       strconv.Itoa(e.Months),
+		"plan":         e.Plan,
+		"gifter":       e.Gifter,
+	}
+}
+
+// EventsFromIRC returns the hook events of an IRC message. Chat messages containing one of the mention
+// names are a message and a mention event.
+func EventsFromIRC(msg twitchirc.IRCer, mentionNames []string) []Event {
+	switch msg := msg.(type) {
+	case *twitchirc.PrivateMessage:
+		event := Event{
+			Type:        save.HookEventMessage,
+			ID:          msg.ID,
+			Channel:     msg.ChannelUserName,
+			ChannelID:   msg.RoomID,
+			User:        msg.LoginName,
+			UserID:      msg.UserID,
+			DisplayName: msg.DisplayName,
+			Message:     msg.Message,
+			Mod:         msg.Mod,
+			Subscriber:  msg.Subscriber,
+			Timestamp:   msg.TMISentTS,
+		}
+
+		events := []Event{event}
+
+		for _, name := range mentionNames {
+			if strings.Contains(strings.ToLower(msg.Message), strings.ToLower(name)) {
+				event.Type = save.HookEventMention
+				events = append(events, event)
+				break
+			}
+		}
+
+		return events
+	case *twitchirc.RaidMessage:
+		event := eventFromUserNotice(save.HookEventRaid, msg.UserNotice)
+		event.Viewers = msg.ViewerCount
+		return []Event{event}
+	case *twitchirc.SubMessage:
+		event := eventFromUserNotice(save.HookEventSub, msg.UserNotice)
+		event.Message = msg.Message
+		event.Months = msg.CumulativeMonths
+		event.Plan = string(msg.SubPlan)
+		return []Event{event}
+	case *twitchirc.SubGiftMessage:
+		event := eventFromUserNotice(save.HookEventSub, msg.UserNotice)
+		event.User = msg.RecipientUserName
+		event.UserID = msg.RecipientID
+		event.DisplayName = msg.ReceiptDisplayName
+		event.Gifter = msg.DisplayName
+		event.Months = msg.Months
+		event.Plan = string(msg.SubPlan)
+		return []Event{event}
+	case *twitchirc.Whisper:
+		// whispers don't carry a timestamp, they are received right after they were sent
+		return []Event{{
+			Type:        save.HookEventWhisper,
+			ID:          msg.ID,
+			User:        msg.LoginName,
+			UserID:      msg.UserID,
+			DisplayName: msg.DisplayName,
+			Message:     msg.Message,
+			Timestamp:   time.Now(),
+		}}
+	}
+
+	return nil
+}
+
+func eventFromUserNotice(eventType string, n twitchirc.UserNotice) Event {
+	return Event{
+		Type:        eventType,
+		ID:          n.ID,
+		Channel:     n.ChannelUserName,
+		ChannelID:   n.RoomID,
+		User:        n.Login,
+		UserID:      n.UserID,
+		DisplayName: n.DisplayName,
+		Mod:         n.Mod,
+		Subscriber:  n.Subscriber,
+		Timestamp:   n.TMISentTS,
+	}
+}
+
+type compiledHook struct {
+	save.Hook
+	filter *filter.Filter
+}
+
+// Runner runs the hooks matching events in the background
+type Runner struct {
+	logger  zerolog.Logger
+	running chan struct{}
+
+	m        sync.Mutex
+	hooks    []compiledHook
+	seen     map[string]struct{}
+	seenList []string // order of seen, to forget the oldest IDs
+}
+
+func NewRunner(logger zerolog.Logger, hooks []save.Hook) (*Runner, error) {
+	r := &Runner{
+		logger:  logger.With().Str("component", "hook").Logger(),
+		running: make(chan struct{}, maxRunning),
+		seen:    map[string]struct{}{},
+	}
+
+	if err := r.SetHooks(hooks); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// SetHooks replaces the hooks, used when the settings are reloaded. The hooks are kept on error.
+func (r *Runner) SetHooks(hooks []save.Hook) error {
+	compiled := make([]compiledHook, 0, len(hooks))
+
+	for i, h := range hooks {
+		f, err := filter.Parse(h.Filter, save.HookFilterFields)
+		if err != nil {
+			return fmt.Errorf("invalid filter of hook %d: %w", i+1, err)
+		}
+
+		compiled = append(compiled, compiledHook{Hook: h, filter: f})
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.hooks = compiled
+
+	return nil
+}
+
+// Fire starts the commands of all hooks matching the events without waiting for them
+func (r *Runner) Fire(events ...Event) {
+	for _, event := range events {
+		for _, h := range r.matching(event) {
+			select {
+			case r.running <- struct{}{}:
+			default:
+				r.logger.Warn().Str("event", event.Type).Str("command", h.Command).Msg("too many hooks running, skipping event")
+				continue
+			}
+
+			go func() {
+				defer func() { <-r.running }()
+
+				if err := run(context.Background(), h.Hook, event); err != nil {
+					r.logger.Err(err).Str("event", event.Type).Str("command", h.Command).Msg("hook failed")
+				}
+			}()
+		}
+	}
+}
+
+// matching returns the hooks matching the event, events already seen are skipped
+func (r *Runner) matching(event Event) []compiledHook {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if event.ID != "" {
+		key := event.Type + "/" + event.ID
+		if _, ok := r.seen[key]; ok {
+			return nil
+		}
+
+		r.seen[key] = struct{}{}
+		r.seenList = append(r.seenList, key)
+
+		if len(r.seenList) > seenLimit {
+			delete(r.seen, r.seenList[0])
+			r.seenList = r.seenList[1:]
+		}
+	}
+
+	var matching []compiledHook
+	fields := event.Fields()
+
+	for _, h := range r.hooks {
+		if h.Event == event.Type && h.filter.Match(fields) {
+			matching = append(matching, h)
+		}
+	}
+
+	return matching
+}
+
+// run runs the command of the hook with the event as JSON on stdin and waits until it exits or times out
+func run(ctx context.Context, h save.Hook, event Event) error {
+	args := strings.Fields(h.Command)
+	if len(args) == 0 {
+		return errors.New("no command configured")
+	}
+
+	input, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = save.DefaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := cmdout.NewTailWriter(outputLimit)
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = output
+	cmd.Env = append(os.Environ(), "CHATUINO_EVENT="+event.Type)
+	// don't wait for child processes keeping the output open after the command was killed
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+
+		if line := output.LastLine(); line != "" {
+			return fmt.Errorf("%w: %s", err, line)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package hook
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsFromIRC(t *testing.T) {
+	t.Parallel()
+
+	msg := &twitchirc.PrivateMessage{
+		ID:              "1",
+		ChannelUserName: "lirik",
+		LoginName:       "viewer",
+		DisplayName:     "Viewer",
+		Message:         "hey @JulezDev",
+	}
+
+	events := EventsFromIRC(msg, []string{"julezdev"})
+	require.Len(t, events, 2)
+	require.Equal(t, save.HookEventMessage, events[0].Type)
+	require.Equal(t, save.HookEventMention, events[1].Type)
+	require.Equal(t, "viewer", events[1].User)
+
+	events = EventsFromIRC(msg, []string{"someone"})
+	require.Len(t, events, 1)
+
+	raid := &twitchirc.RaidMessage{UserNotice: twitchirc.UserNotice{ID: "2", ChannelUserName: "lirik", Login: "raider"}, ViewerCount: 42}
+	require.Equal(t, []Event{{Type: save.HookEventRaid, ID: "2", Channel: "lirik", User: "raider", Viewers: 42}}, EventsFromIRC(raid, nil))
+
+	whisper := EventsFromIRC(&twitchirc.Whisper{ID: "3", LoginName: "friend", Message: "hi"}, nil)
+	require.Len(t, whisper, 1)
+	require.Equal(t, save.HookEventWhisper, whisper[0].Type)
+	require.Equal(t, "friend", whisper[0].User)
+
+	require.Empty(t, EventsFromIRC(&twitchirc.Notice{}, nil))
+}
+
+func TestEvent_Fields(t *testing.T) {
+	t.Parallel()
+
+	// every documented filter field has to be set
+	require.ElementsMatch(t, save.HookFilterFields, slices.Collect(maps.Keys(Event{}.Fields())))
+}
+
+func TestRunner_Matching(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRunner(zerolog.Nop(), []save.Hook{
+		{Event: save.HookEventMessage, Command: "first", Filter: `channel == "lirik"`},
+		{Event: save.HookEventMessage, Command: "second"},
+		{Event: save.HookEventRaid, Command: "third", Filter: "viewers >= 10"},
+	})
+	require.NoError(t, err)
+
+	commands := func(event Event) []string {
+		var commands []string
+		for _, h := range r.matching(event) {
+			commands = append(commands, h.Command)
+		}
+		return commands
+	}
+
+	require.Equal(t, []string{"first", "second"}, commands(Event{Type: save.HookEventMessage, ID: "1", Channel: "lirik"}))
+	require.Equal(t, []string{"second"}, commands(Event{Type: save.HookEventMessage, ID: "2", Channel: "other"}))
+
+	// the same message received by another connection is skipped
+	require.Empty(t, commands(Event{Type: save.HookEventMessage, ID: "1", Channel: "lirik"}))
+
+	require.Empty(t, commands(Event{Type: save.HookEventRaid, ID: "3", Viewers: 5}))
+	require.Equal(t, []string{"third"}, commands(Event{Type: save.HookEventRaid, ID: "4", Viewers: 10}))
+
+	// invalid hooks keep the previous hooks
+	require.Error(t, r.SetHooks([]save.Hook{{Event: save.HookEventMessage, Command: "x", Filter: "unknown"}}))
+	require.Equal(t, []string{"second"}, commands(Event{Type: save.HookEventMessage, ID: "5"}))
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses a shell script")
+	}
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "event.json")
+	script := filepath.Join(dir, "hook.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$CHATUINO_EVENT\" > "+out+".type\ncat > "+out+"\n"), 0o700))
+
+	event := Event{Type: save.HookEventMention, Channel: "lirik", User: "viewer", Message: "hi"}
+	require.NoError(t, run(t.Context(), save.Hook{Command: script}, event))
+
+	b, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	var got Event
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.Equal(t, event, got)
+
+	b, err = os.ReadFile(out + ".type")
+	require.NoError(t, err)
+	require.Equal(t, "mention\n", string(b))
+
+	// errors contain the last line of the error output
+	failing := filepath.Join(dir, "failing.sh")
+	require.NoError(t, os.WriteFile(failing, []byte("#!/bin/sh\necho first >&2\necho 'went wrong' >&2\nexit 3\n"), 0o700))
+	require.ErrorContains(t, run(t.Context(), save.Hook{Command: failing}, event), "exit status 3: went wrong")
+
+	slow := filepath.Join(dir, "slow.sh")
+	require.NoError(t, os.WriteFile(slow, []byte("#!/bin/sh\nsleep 5\n"), 0o700))
+	require.ErrorContains(t, run(t.Context(), save.Hook{Command: slow, Timeout: time.Millisecond * 100}, event), "timed out after 100ms")
+}
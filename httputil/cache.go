@@ -0,0 +1,172 @@
+package httputil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+)
+
+// CacheRule caches the responses of endpoints whose path ends with PathSuffix for TTL
+type CacheRule struct {
+	PathSuffix string
+	TTL        time.Duration
+
+	// RequireQuery only caches requests with query parameters, responses without them may depend on the token, like /users
+	RequireQuery bool
+}
+
+// cachedResponse is a successful response stored on disk
+type cachedResponse struct {
+	StoredAt    time.Time `json:"stored_at"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+}
+
+// response answers req with the stored response
+func (c cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{c.ContentType}},
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// CacheTransport is an http.RoundTripper that stores successful GET responses of read-only endpoints on disk
+// and answers the same requests from the store until the TTL of the endpoint's rule expired.
+// Expired responses are still used when the request fails with ErrOffline.
+// The store is shared by all clients using the same directory, responses are keyed by the full URL.
+type CacheTransport struct {
+	// Transport is the underlying http.RoundTripper
+	Transport http.RoundTripper
+
+	FS    afero.Fs
+	Dir   string
+	Rules []CacheRule
+
+	now func() time.Time
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := t.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	ttl := t.ttl(req)
+	if ttl <= 0 {
+		return rt.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	now := time.Now()
+	if t.now != nil {
+		now = t.now()
+	}
+
+	cached, hasCached := t.load(path)
+	if hasCached && now.Sub(cached.StoredAt) < ttl {
+		return cached.response(req), nil
+	}
+
+	resp, err := rt.RoundTrip(req)
+
+	// while offline an expired response is better than none
+	if errors.Is(err, ErrOffline) && hasCached {
+		return cached.response(req), nil
+	}
+
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// a response which can't be stored is still returned, it is fetched again next time
+	if err := t.store(path, cachedResponse{StoredAt: now, ContentType: resp.Header.Get("Content-Type"), Body: body}); err != nil {
+		log.Logger.Warn().Err(err).Str("url", req.URL.String()).Msg("failed to store API response")
+	}
+
+	return resp, nil
+}
+
+// ttl returns the TTL of the first rule matching the request, zero if the request is not cached
+func (t *CacheTransport) ttl(req *http.Request) time.Duration {
+	if req.Method != http.MethodGet {
+		return 0
+	}
+
+	for _, rule := range t.Rules {
+		if !strings.HasSuffix(req.URL.Path, rule.PathSuffix) {
+			continue
+		}
+
+		if rule.RequireQuery && req.URL.RawQuery == "" {
+			return 0
+		}
+
+		return rule.TTL
+	}
+
+	return 0
+}
+
+func (t *CacheTransport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (t *CacheTransport) load(path string) (cachedResponse, bool) {
+	data, err := afero.ReadFile(t.FS, path)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cachedResponse{}, false
+	}
+
+	return cached, true
+}
+
+func (t *CacheTransport) store(path string, cached cachedResponse) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	if err := t.FS.MkdirAll(t.Dir, 0o700); err != nil {
+		return err
+	}
+
+	// written to a temporary file first, so other instances never read a partial response
+	tmp := path + ".tmp"
+	if err := afero.WriteFile(t.FS, tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return t.FS.Rename(tmp, path)
+}
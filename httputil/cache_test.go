@@ -0,0 +1,99 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTransport(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	status := http.StatusOK
+	backend := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"data":[]}`)),
+		}, nil
+	})
+
+	now := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	fs := afero.NewMemMapFs()
+
+	newTransport := func() *CacheTransport {
+		return &CacheTransport{
+			Transport: backend,
+			FS:        fs,
+			Dir:       "/cache/api",
+			Rules:     []CacheRule{{PathSuffix: "/users", TTL: time.Hour, RequireQuery: true}, {PathSuffix: "/chat/emotes", TTL: 0}},
+			now:       func() time.Time { return now },
+		}
+	}
+
+	get := func(transport *CacheTransport, method, url string) string {
+		resp, err := transport.RoundTrip(httptest.NewRequest(method, url, nil))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	transport := newTransport()
+	require.JSONEq(t, `{"data":[]}`, get(transport, http.MethodGet, "https://api.twitch.tv/helix/users?login=lirik"))
+	require.JSONEq(t, `{"data":[]}`, get(transport, http.MethodGet, "https://api.twitch.tv/helix/users?login=lirik"))
+	require.Equal(t, 1, calls, "answered from the cache")
+
+	// cached responses may contain user data, only the user can read them
+	info, err := fs.Stat("/cache/api")
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+	// the store is shared by transports using the same directory
+	get(newTransport(), http.MethodGet, "https://api.twitch.tv/helix/users?login=lirik")
+	require.Equal(t, 1, calls)
+
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/users?login=xqc")
+	require.Equal(t, 2, calls, "other URLs are cached separately")
+
+	get(transport, http.MethodPost, "https://api.twitch.tv/helix/users?login=lirik")
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/chat/emotes?broadcaster_id=1")
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/chat/emotes?broadcaster_id=1")
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/streams?user_id=1")
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/users")
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/users")
+	require.Equal(t, 8, calls, "only GET requests of endpoints with a TTL are cached")
+
+	now = now.Add(time.Hour)
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/users?login=lirik")
+	require.Equal(t, 9, calls, "expired")
+
+	// failed requests are not cached
+	status = http.StatusInternalServerError
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/users?login=forsen")
+	status = http.StatusOK
+	get(transport, http.MethodGet, "https://api.twitch.tv/helix/users?login=forsen")
+	require.Equal(t, 11, calls)
+
+	// offline expired responses are used, uncached requests fail
+	offline := newTransport()
+	offline.Transport = OfflineTransport(backend)
+	now = now.Add(24 * time.Hour)
+	require.JSONEq(t, `{"data":[]}`, get(offline, http.MethodGet, "https://api.twitch.tv/helix/users?login=lirik"))
+
+	_, err = offline.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.twitch.tv/helix/users?login=sodapoppin", nil))
+	require.ErrorIs(t, err, ErrOffline)
+	require.Equal(t, 11, calls)
+}
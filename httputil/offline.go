@@ -0,0 +1,31 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ErrOffline is returned for requests made while Chatuino runs offline
+var ErrOffline = errors.New("chatuino runs offline")
+
+// OfflineTransport refuses all requests except to loopback addresses, so a local API server still works during development
+func OfflineTransport(loopback http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !isLoopback(req.URL.Hostname()) {
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), ErrOffline)
+		}
+
+		return loopback.RoundTrip(req)
+	})
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,46 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineTransport(t *testing.T) {
+	t.Parallel()
+
+	backend := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	transport := OfflineTransport(backend)
+
+	tests := []struct {
+		name    string
+		target  string
+		offline bool
+	}{
+		{name: "api", target: "https://api.twitch.tv/helix/users", offline: true},
+		{name: "websocket", target: "https://irc-ws.chat.twitch.tv:443", offline: true},
+		{name: "localhost", target: "http://localhost:8080/ttv/users"},
+		{name: "loopback ip", target: "http://127.0.0.1:8080/ttv/users"},
+		{name: "loopback ipv6", target: "http://[::1]:8080/ttv/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if tt.offline {
+				require.ErrorIs(t, err, ErrOffline)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
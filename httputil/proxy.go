@@ -0,0 +1,61 @@
+package httputil
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFunc returns the proxy selection of an http.Transport.
+// Without a proxy the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used, like http.ProxyFromEnvironment.
+// Hosts in noProxy and their subdomains as well as loopback addresses are connected to directly, "*" disables the proxy for all hosts.
+func ProxyFunc(proxy *url.URL, noProxy []string) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+
+		return proxy, nil
+	}
+}
+
+func bypassProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+
+	if host == "localhost" {
+		return true
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(entry), "."))
+
+		if entry == "*" || host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewProxyTransport returns a copy of http.DefaultTransport which connects through proxy, see ProxyFunc.
+// http.DefaultTransport itself is left unchanged.
+func NewProxyTransport(proxy *url.URL, noProxy []string) (*http.Transport, error) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("default transport was replaced, proxy can't be set")
+	}
+
+	transport = transport.Clone()
+	transport.Proxy = ProxyFunc(proxy, noProxy)
+	return transport, nil
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/julez-dev/chatuino/logbuffer"
 	"github.com/rs/zerolog"
 )
 
@@ -56,7 +57,7 @@ func (t *ChatuinoRoundTrip) RoundTrip(req *http.Request) (*http.Response, error)
 	dur := time.Since(now)
 	t.logger.Info().
 		Str("method", req.Method).
-		Str("url", req.URL.String()).
+		Str("url", logbuffer.Redact(req.URL.String())).
 		Dur("took", dur).
 		Int("status", resp.StatusCode).Send()
 
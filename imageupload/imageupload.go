@@ -0,0 +1,279 @@
+// Package imageupload uploads images pasted into the message input to an image host, which answers with the link of the image.
+// Built-in hosts are catbox.moe and kappa.lol, any other host is called with the image in the file field of a multipart form.
+package imageupload
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	HostCatbox = "catbox"
+	HostKappa  = "kappa"
+)
+
+// MaxSize is the size of the largest image uploaded, larger images are rejected before uploading
+const MaxSize = 20 << 20
+
+const (
+	catboxURL = "https://catbox.moe/user/api.php"
+	kappaURL  = "https://kappa.lol/api/upload"
+)
+
+// imageExtensions are the file extensions of images image hosts accept
+var imageExtensions = map[string]struct{}{
+	".png":  {},
+	".jpg":  {},
+	".jpeg": {},
+	".gif":  {},
+	".webp": {},
+}
+
+// Image is an image found in pasted text, either the path of an image file or the content of a data URI
+type Image struct {
+	Path string
+	Name string
+	Data []byte // content of a data URI, nil for files
+}
+
+// Parse returns the image pasted as text. Pasted are the path of an image file, optionally quoted, with escaped spaces
+// or as file:// URI like terminals paste dropped files, or a base64 data URI of an image.
+// Parse does not check if the file exists.
+func Parse(text string) (Image, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.ContainsAny(text, "\r\n") {
+		return Image{}, false
+	}
+
+	if rest, ok := strings.CutPrefix(text, "data:image/"); ok {
+		return parseDataURI(rest)
+	}
+
+	if len(text) > 1 && (text[0] == '\'' || text[0] == '"') && text[len(text)-1] == text[0] {
+		text = text[1 : len(text)-1]
+	}
+
+	if strings.HasPrefix(text, "file://") {
+		u, err := url.Parse(text)
+		if err != nil || u.Host != "" && u.Host != "localhost" {
+			return Image{}, false
+		}
+
+		text = u.Path
+	}
+
+	text = strings.ReplaceAll(text, `\ `, " ")
+
+	if home, ok := strings.CutPrefix(text, "~/"); ok {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return Image{}, false
+		}
+
+		text = filepath.Join(dir, home)
+	}
+
+	if !filepath.IsAbs(text) {
+		return Image{}, false
+	}
+
+	if _, ok := imageExtensions[strings.ToLower(filepath.Ext(text))]; !ok {
+		return Image{}, false
+	}
+
+	return Image{Path: text, Name: filepath.Base(text)}, true
+}
+
+// parseDataURI parses the rest of a data URI after data:image/
+func parseDataURI(rest string) (Image, bool) {
+	meta, data, ok := strings.Cut(rest, ",")
+	if !ok {
+		return Image{}, false
+	}
+
+	format, ok := strings.CutSuffix(meta, ";base64")
+	if !ok {
+		return Image{}, false
+	}
+
+	ext := "." + format
+	if _, ok := imageExtensions[ext]; !ok {
+		return Image{}, false
+	}
+
+	content, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(content) == 0 {
+		return Image{}, false
+	}
+
+	return Image{Name: "pasted" + ext, Data: content}, true
+}
+
+// Read returns the content of the image, files larger than MaxSize are rejected
+func (i Image) Read() ([]byte, error) {
+	if i.Data != nil {
+		return i.Data, nil
+	}
+
+	f, err := os.Open(i.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("image is larger than %d MB", MaxSize>>20)
+	}
+
+	return data, nil
+}
+
+type Uploader struct {
+	client *http.Client
+	host   string
+	apiKey string
+}
+
+// New returns an uploader to the host, one of the built-in hosts or the URL of a host
+func New(client *http.Client, host, apiKey string) (*Uploader, error) {
+	if err := Validate(host); err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Uploader{client: client, host: host, apiKey: apiKey}, nil
+}
+
+// Validate checks the host, which is a built-in host or an http or https URL
+func Validate(host string) error {
+	if host == HostCatbox || host == HostKappa {
+		return nil
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("invalid image host: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("image host must be %s, %s or an http or https URL", HostCatbox, HostKappa)
+	}
+
+	return nil
+}
+
+// Upload uploads the image and returns its link
+func (u *Uploader) Upload(ctx context.Context, name string, data []byte) (string, error) {
+	var (
+		body   bytes.Buffer
+		target = u.host
+		field  = "file"
+	)
+
+	form := multipart.NewWriter(&body)
+
+	switch u.host {
+	case HostCatbox:
+		target, field = catboxURL, "fileToUpload"
+
+		if err := form.WriteField("reqtype", "fileupload"); err != nil {
+			return "", err
+		}
+
+		// uploads with the user hash are listed in the catbox account
+		if u.apiKey != "" {
+			if err := form.WriteField("userhash", u.apiKey); err != nil {
+				return "", err
+			}
+		}
+	case HostKappa:
+		target = kappaURL
+	}
+
+	part, err := form.CreateFormFile(field, name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, &body)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if u.apiKey != "" && u.host != HostCatbox {
+		req.Header.Set("Authorization", u.apiKey)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("image host responded with %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	return linkFromResponse(respBody)
+}
+
+// linkFromResponse returns the link of the uploaded image, hosts answer with the link as plain text or in a JSON object
+func linkFromResponse(body []byte) (string, error) {
+	link := strings.TrimSpace(string(body))
+
+	if strings.HasPrefix(link, "{") {
+		var resp struct {
+			Link string `json:"link"`
+			URL  string `json:"url"`
+		}
+
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return "", fmt.Errorf("failed to decode response of image host: %w", err)
+		}
+
+		link = resp.Link
+		if link == "" {
+			link = resp.URL
+		}
+	}
+
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		return "", fmt.Errorf("image host returned no link: %q", strings.TrimSpace(string(body)))
+	}
+
+	return link, nil
+}
@@ -0,0 +1,189 @@
+package imageupload
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julez-dev/chatuino/httputil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	png := []byte("\x89PNG")
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+
+	tests := map[string]struct {
+		text   string
+		want   Image
+		wantOK bool
+	}{
+		"path":             {text: "/tmp/cat.png", want: Image{Path: "/tmp/cat.png", Name: "cat.png"}, wantOK: true},
+		"upper case":       {text: "/tmp/CAT.JPG", want: Image{Path: "/tmp/CAT.JPG", Name: "CAT.JPG"}, wantOK: true},
+		"quoted":           {text: "'/tmp/my cat.gif'", want: Image{Path: "/tmp/my cat.gif", Name: "my cat.gif"}, wantOK: true},
+		"escaped spaces":   {text: `/tmp/my\ cat.webp `, want: Image{Path: "/tmp/my cat.webp", Name: "my cat.webp"}, wantOK: true},
+		"file uri":         {text: "file:///tmp/my%20cat.jpeg", want: Image{Path: "/tmp/my cat.jpeg", Name: "my cat.jpeg"}, wantOK: true},
+		"home":             {text: "~/cat.png", want: Image{Path: filepath.Join(home, "cat.png"), Name: "cat.png"}, wantOK: true},
+		"data uri":         {text: dataURI, want: Image{Name: "pasted.png", Data: png}, wantOK: true},
+		"relative path":    {text: "cat.png"},
+		"no image":         {text: "/tmp/notes.txt"},
+		"text":             {text: "look at /tmp/cat.png"},
+		"multiple lines":   {text: "/tmp/cat.png\n/tmp/dog.png"},
+		"remote file uri":  {text: "file://server/cat.png"},
+		"svg data uri":     {text: "data:image/svg+xml;base64,PHN2Zz4="},
+		"invalid data uri": {text: "data:image/png;base64,!!!"},
+		"link":             {text: "https://example.com/cat.png"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Parse(tt.text)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestImage_Read(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cat.png")
+	require.NoError(t, os.WriteFile(path, []byte("image"), 0o600))
+
+	data, err := Image{Path: path}.Read()
+	require.NoError(t, err)
+	require.Equal(t, []byte("image"), data)
+
+	require.NoError(t, os.WriteFile(path, make([]byte, MaxSize+1), 0o600))
+	_, err = Image{Path: path}.Read()
+	require.ErrorContains(t, err, "larger than")
+
+	_, err = Image{Path: filepath.Join(t.TempDir(), "missing.png")}.Read()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Validate(HostCatbox))
+	require.NoError(t, Validate(HostKappa))
+	require.NoError(t, Validate("https://images.example.com/upload"))
+	require.Error(t, Validate("imgur"))
+	require.Error(t, Validate("ftp://example.com/upload"))
+}
+
+func TestUploader_Upload(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		url, auth, field, name, content string
+		values                          url.Values
+	}
+
+	requests := make(chan request, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+
+		for field, files := range r.MultipartForm.File {
+			f, err := files[0].Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(f)
+			require.NoError(t, err)
+
+			requests <- request{
+				url:     r.Header.Get("X-Original-URL"),
+				auth:    r.Header.Get("Authorization"),
+				field:   field,
+				name:    files[0].Filename,
+				content: string(content),
+				values:  r.MultipartForm.Value,
+			}
+		}
+
+		switch r.Header.Get("X-Original-URL") {
+		case catboxURL:
+			_, _ = io.WriteString(w, "https://files.catbox.moe/abc.png")
+		case kappaURL:
+			_, _ = io.WriteString(w, `{"key":"abc","link":"https://kappa.lol/abc"}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, "invalid key")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	// all requests are sent to the test server, the requested URL is kept in a header
+	client := &http.Client{Transport: httputil.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		target, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+
+		req.Header.Set("X-Original-URL", req.URL.String())
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+
+	catbox, err := New(client, HostCatbox, "userhash")
+	require.NoError(t, err)
+
+	link, err := catbox.Upload(t.Context(), "cat.png", []byte("image"))
+	require.NoError(t, err)
+	require.Equal(t, "https://files.catbox.moe/abc.png", link)
+
+	req := <-requests
+	require.Equal(t, "fileToUpload", req.field)
+	require.Equal(t, "cat.png", req.name)
+	require.Equal(t, "image", req.content)
+	require.Equal(t, []string{"fileupload"}, req.values["reqtype"])
+	require.Equal(t, []string{"userhash"}, req.values["userhash"])
+	require.Empty(t, req.auth)
+
+	kappa, err := New(client, HostKappa, "")
+	require.NoError(t, err)
+
+	link, err = kappa.Upload(t.Context(), "cat.png", []byte("image"))
+	require.NoError(t, err)
+	require.Equal(t, "https://kappa.lol/abc", link)
+	require.Equal(t, "file", (<-requests).field)
+
+	custom, err := New(client, "https://images.example.com/upload", "secret")
+	require.NoError(t, err)
+
+	_, err = custom.Upload(t.Context(), "cat.png", []byte("image"))
+	require.ErrorContains(t, err, "invalid key")
+
+	req = <-requests
+	require.Equal(t, "https://images.example.com/upload", req.url)
+	require.Equal(t, "secret", req.auth)
+}
+
+func Test_linkFromResponse(t *testing.T) {
+	t.Parallel()
+
+	link, err := linkFromResponse([]byte("https://example.com/a.png\n"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/a.png", link)
+
+	link, err = linkFromResponse([]byte(`{"url":"https://example.com/a.png"}`))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/a.png", link)
+
+	_, err = linkFromResponse([]byte("Error: file too large"))
+	require.ErrorContains(t, err, "no link")
+
+	_, err = linkFromResponse([]byte(`{"error":"rate limited"}`))
+	require.ErrorContains(t, err, "no link")
+}
@@ -0,0 +1,37 @@
+// Package cmdout keeps the output of external commands, like players and hooks, to explain why they failed.
+package cmdout
+
+import (
+	"bytes"
+	"strings"
+)
+
+// TailWriter keeps the last limit bytes written to it
+type TailWriter struct {
+	buf   []byte
+	limit int
+}
+
+func NewTailWriter(limit int) *TailWriter {
+	return &TailWriter{limit: limit}
+}
+
+func (w *TailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > w.limit {
+		w.buf = w.buf[len(w.buf)-w.limit:]
+	}
+
+	return len(p), nil
+}
+
+// LastLine returns the last non-empty line of the kept output
+func (w *TailWriter) LastLine() string {
+	return LastLine(w.buf)
+}
+
+// LastLine returns the last non-empty line of the output
+func LastLine(out []byte) string {
+	lines := bytes.Split(bytes.TrimSpace(out), []byte("\n"))
+	return strings.TrimSpace(string(lines[len(lines)-1]))
+}
@@ -0,0 +1,25 @@
+package cmdout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailWriter(t *testing.T) {
+	t.Parallel()
+
+	w := NewTailWriter(16)
+	_, _ = w.Write([]byte("[cli][info] Found matching plugin twitch\n"))
+	_, _ = w.Write([]byte("error: offline\n"))
+
+	require.Len(t, w.buf, 16)
+	require.Equal(t, "error: offline", w.LastLine())
+}
+
+func TestLastLine(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "error: device busy", LastLine([]byte("playing sound\n  error: device busy  \n\n")))
+	require.Empty(t, LastLine(nil))
+}
@@ -0,0 +1,50 @@
+// Package termtext makes text received from chat or Twitch safe to print to a terminal.
+package termtext
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Sanitize removes terminal control characters and escape sequences from text received from chat or Twitch,
+// so messages can't move the cursor, clear the screen, change the window title, write to the clipboard or
+// draw images. Tabs are replaced with a space, line feeds are kept and invalid UTF-8 is replaced.
+func Sanitize(s string) string {
+	if utf8.ValidString(s) && !strings.ContainsFunc(s, isUnsafeRune) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == ansi.ESC:
+			// drop the whole sequence, a sequence without ESC would be shown as text
+			_, _, n, _ := ansi.DecodeSequence(s[i:], ansi.NormalState, nil)
+			i += max(n, size)
+			continue
+		case r == '\t':
+			b.WriteByte(' ')
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		case isUnsafeRune(r):
+		default:
+			b.WriteString(s[i : i+size])
+		}
+
+		i += size
+	}
+
+	return b.String()
+}
+
+// isUnsafeRune reports if the terminal interprets the rune instead of printing it.
+// C1 controls (U+0080 - U+009F) include single character introducers like CSI, which some terminals accept.
+func isUnsafeRune(r rune) bool {
+	return (r < 0x20 && r != '\n') || (r >= 0x7f && r <= 0x9f)
+}
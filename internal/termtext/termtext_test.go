@@ -0,0 +1,49 @@
+package termtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "hello chat Kappa", want: "hello chat Kappa"},
+		{name: "unicode", in: "日本語 👨‍👩‍👧 é �", want: "日本語 👨‍👩‍👧 é �"},
+		{name: "line feed is kept", in: "a\nb", want: "a\nb"},
+		{name: "tab", in: "a\tb", want: "a b"},
+		{name: "clear screen", in: "hi\x1b[2J\x1b[Hbye", want: "hibye"},
+		{name: "colors", in: "\x1b[31mred\x1b[0m", want: "red"},
+		{name: "cursor movement", in: "a\x1b[10Ab\x1b[5;5Hc", want: "abc"},
+		{name: "device status report", in: "\x1b[6n", want: ""},
+		{name: "window title with bell", in: "\x1b]0;pwned\x07text", want: "text"},
+		{name: "window title with string terminator", in: "\x1b]2;pwned\x1b\\text", want: "text"},
+		{name: "clipboard write", in: "\x1b]52;c;cm0gLXJmIH4=\x07text", want: "text"},
+		{name: "hyperlink", in: "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", want: "click"},
+		{name: "kitty graphics", in: "\x1b_Ga=T,f=100;AAAA\x1b\\text", want: "text"},
+		{name: "device control string", in: "\x1bP+q544e\x1b\\text", want: "text"},
+		{name: "reset terminal", in: "\x1bctext", want: "text"},
+		{name: "escape at the end", in: "text\x1b", want: "text"},
+		{name: "carriage return", in: "innocent\rspoofed", want: "innocentspoofed"},
+		{name: "backspace", in: "abc\x08\x08\x08xyz", want: "abcxyz"},
+		{name: "bell and null", in: "a\x07\x00b", want: "ab"},
+		{name: "action", in: "\x01ACTION waves\x01", want: "ACTION waves"},
+		{name: "delete", in: "a\x7fb", want: "ab"},
+		{name: "c1 control sequence introducer", in: "a\u009b2Jb", want: "a2Jb"},
+		{name: "c1 operating system command", in: "a\u009d0;title\u009cb", want: "a0;titleb"},
+		{name: "invalid utf-8", in: "a\x9b2Jb\xff", want: "a�2Jb�"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Sanitize(tt.in))
+		})
+	}
+}
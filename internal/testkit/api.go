@@ -0,0 +1,356 @@
+package testkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/httputil"
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"github.com/julez-dev/chatuino/twitch/ivr"
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+)
+
+// ServerURL is the base URL of the fake Chatuino server, pass it to server.NewClient together with APIServer.Client
+const ServerURL = "https://chatuino.test"
+
+// cdnHosts serve the fixture images of emotes and badges
+var cdnHosts = []string{
+	"static-cdn.jtvnw.net",
+	"cdn.betterttv.net",
+	"cdn.frankerfacez.com",
+	"cdn.7tv.app",
+}
+
+// APIServer is a fake of the HTTP APIs used by Chatuino. Requests made with Client are routed to it by their host,
+// so the API clients keep their real base URLs. Channels, streams, badges and emotes are added by the test,
+// requests without a route are answered with 404 and reported by Unhandled.
+type APIServer struct {
+	server *httptest.Server
+
+	mu            sync.Mutex
+	users         []twitchapi.UserData
+	streams       []twitchapi.StreamData
+	globalBadges  []twitchapi.BadgeSet
+	channelBadges map[string][]twitchapi.BadgeSet
+	twitchEmotes  map[string][]twitchapi.EmoteData // keyed by channel ID, global emotes by ""
+	bttvEmotes    map[string][]bttv.Emote
+	ffzEmotes     map[string][]ffz.Emote
+	sevenTVEmotes map[string][]seventv.Emote
+	mods          map[string][]ivr.PrivilegedUser // keyed by channel login
+	recent        map[string][]string             // keyed by channel login
+	handlers      map[string]http.HandlerFunc     // keyed by host and path
+	requests      []string
+	unhandled     []string
+}
+
+// NewAPIServer starts a fake API server, it is closed when the test finishes
+func NewAPIServer(t testing.TB) *APIServer {
+	t.Helper()
+
+	s := &APIServer{
+		channelBadges: map[string][]twitchapi.BadgeSet{},
+		twitchEmotes:  map[string][]twitchapi.EmoteData{},
+		bttvEmotes:    map[string][]bttv.Emote{},
+		ffzEmotes:     map[string][]ffz.Emote{},
+		sevenTVEmotes: map[string][]seventv.Emote{},
+		mods:          map[string][]ivr.PrivilegedUser{},
+		recent:        map[string][]string{},
+		handlers:      map[string]http.HandlerFunc{},
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.route))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// Client returns an HTTP client sending all requests to the fake server
+func (s *APIServer) Client() *http.Client {
+	return &http.Client{Transport: s.Transport()}
+}
+
+// Transport returns a round tripper sending all requests to the fake server, the original host is kept in the Host header
+func (s *APIServer) Transport() http.RoundTripper {
+	return httputil.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.Host = req.URL.Host
+		req.URL.Scheme = "http"
+		req.URL.Host = strings.TrimPrefix(s.server.URL, "http://")
+
+		return s.server.Client().Transport.RoundTrip(req)
+	})
+}
+
+// AddChannel adds a Twitch user, the ID is derived from the login if empty. The added user is returned.
+func (s *APIServer) AddChannel(user twitchapi.UserData) twitchapi.UserData {
+	if user.ID == "" {
+		user.ID = fmt.Sprint(idOf(user.Login))
+	}
+
+	if user.DisplayName == "" {
+		user.DisplayName = user.Login
+	}
+
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Date(2015, time.March, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = append(s.users, user)
+	return user
+}
+
+// SetStream marks the channel of the stream as live, the stream replaces a previous one of the same user
+func (s *APIServer) SetStream(stream twitchapi.StreamData) {
+	if stream.Type == "" {
+		stream.Type = "live"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streams = slices.DeleteFunc(s.streams, func(d twitchapi.StreamData) bool { return d.UserID == stream.UserID })
+	s.streams = append(s.streams, stream)
+}
+
+// SetBadges sets the badge sets of the channel, the global badge sets for an empty channel ID
+func (s *APIServer) SetBadges(channelID string, sets ...twitchapi.BadgeSet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if channelID == "" {
+		s.globalBadges = sets
+		return
+	}
+
+	s.channelBadges[channelID] = sets
+}
+
+// SetTwitchEmotes sets the Twitch emotes of the channel, the global emotes for an empty channel ID
+func (s *APIServer) SetTwitchEmotes(channelID string, emotes ...twitchapi.EmoteData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.twitchEmotes[channelID] = emotes
+}
+
+// SetBTTVEmotes sets the BTTV emotes of the channel, the global emotes for an empty channel ID
+func (s *APIServer) SetBTTVEmotes(channelID string, emotes ...bttv.Emote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bttvEmotes[channelID] = emotes
+}
+
+// SetFFZEmotes sets the FFZ emotes of the channel, the global emotes for an empty channel ID
+func (s *APIServer) SetFFZEmotes(channelID string, emotes ...ffz.Emote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ffzEmotes[channelID] = emotes
+}
+
+// SetSevenTVEmotes sets the 7TV emotes of the channel, the global emotes for an empty channel ID
+func (s *APIServer) SetSevenTVEmotes(channelID string, emotes ...seventv.Emote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sevenTVEmotes[channelID] = emotes
+}
+
+// SetMods sets the moderators of the channel returned by IVR
+func (s *APIServer) SetMods(channel string, mods ...ivr.PrivilegedUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mods[strings.ToLower(channel)] = mods
+}
+
+// SetRecentMessages sets the raw IRC lines returned by the recent-messages service for the channel
+func (s *APIServer) SetRecentMessages(channel string, lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recent[strings.ToLower(channel)] = lines
+}
+
+// Handle answers requests to the host and path with handler instead of the built-in routes
+func (s *APIServer) Handle(host, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[host+path] = handler
+}
+
+// Requests returns the host and path of all requests received
+func (s *APIServer) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Unhandled returns the host and path of the requests no route existed for
+func (s *APIServer) Unhandled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.unhandled)
+}
+
+func (s *APIServer) route(w http.ResponseWriter, r *http.Request) {
+	host, path := r.Host, r.URL.Path
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, host+path)
+
+	if handler, ok := s.handlers[host+path]; ok {
+		// custom handlers may call the setters, so they run without the lock
+		s.mu.Unlock()
+		handler(w, r)
+		s.mu.Lock()
+		return
+	}
+
+	var (
+		resp any
+		ok   bool
+	)
+
+	switch {
+	case host == "api.twitch.tv" && strings.HasPrefix(path, "/helix/"):
+		resp, ok = s.helix(strings.TrimPrefix(path, "/helix"), r)
+	case "https://"+host == ServerURL && strings.HasPrefix(path, "/ttv/"):
+		resp, ok = s.helix(strings.TrimPrefix(path, "/ttv"), r)
+	case host == "api.ivr.fi" && strings.HasPrefix(path, "/v2/twitch/modvip/"):
+		resp, ok = ivr.ModVIPResponse{Mods: s.mods[strings.ToLower(strings.TrimPrefix(path, "/v2/twitch/modvip/"))], VIPs: []ivr.PrivilegedUser{}}, true
+	case host == "recent-messages.robotty.de" && strings.HasPrefix(path, "/api/v2/recent-messages/"):
+		resp, ok = map[string][]string{"messages": nonNil(s.recent[strings.ToLower(strings.TrimPrefix(path, "/api/v2/recent-messages/"))])}, true
+	case host == "api.betterttv.net":
+		resp, ok = s.bttv(path)
+	case host == "api.frankerfacez.com":
+		resp, ok = s.ffz(path)
+	case host == "7tv.io":
+		resp, ok = s.sevenTV(path)
+	case slices.Contains(cdnHosts, host):
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(Image(host + path))
+		return
+	}
+
+	if !ok {
+		s.unhandled = append(s.unhandled, host+path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprintf(w, `{"error":"Not Found","status":404,"message":"testkit has no route for %s%s"}`, host, path)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// helix answers the Helix endpoints, also proxied by the Chatuino server below /ttv
+func (s *APIServer) helix(path string, r *http.Request) (any, bool) {
+	query := r.URL.Query()
+
+	switch path {
+	case "/users":
+		users := []twitchapi.UserData{}
+		for _, u := range s.users {
+			if slices.ContainsFunc(query["login"], func(login string) bool { return strings.EqualFold(login, u.Login) }) || slices.Contains(query["id"], u.ID) {
+				users = append(users, u)
+			}
+		}
+
+		return twitchapi.UserResponse{Data: users}, true
+	case "/streams":
+		streams := []twitchapi.StreamData{}
+		for _, stream := range s.streams {
+			if slices.Contains(query["user_id"], stream.UserID) || slices.ContainsFunc(query["user_login"], func(login string) bool { return strings.EqualFold(login, stream.UserLogin) }) {
+				streams = append(streams, stream)
+			}
+		}
+
+		return twitchapi.GetStreamsResponse{Data: streams}, true
+	case "/chat/badges/global":
+		return twitchapi.GetGlobalBadgesResp{Data: nonNil(s.globalBadges)}, true
+	case "/chat/badges":
+		return twitchapi.GetChannelChatBadgesResp{Data: nonNil(s.channelBadges[query.Get("broadcaster_id")])}, true
+	case "/chat/emotes/global":
+		return twitchapi.EmoteResponse{Data: nonNil(s.twitchEmotes[""])}, true
+	case "/chat/emotes":
+		return twitchapi.EmoteResponse{Data: nonNil(s.twitchEmotes[query.Get("broadcaster_id")])}, true
+	case "/chat/settings":
+		return twitchapi.GetChatSettingsResponse{Data: []twitchapi.ChatSettingData{{BroadcasterID: query.Get("broadcaster_id")}}}, true
+	}
+
+	return nil, false
+}
+
+func (s *APIServer) bttv(path string) (any, bool) {
+	if path == "/3/cached/emotes/global" {
+		return bttv.GlobalEmoteResponse(nonNil(s.bttvEmotes[""])), true
+	}
+
+	if id, ok := strings.CutPrefix(path, "/3/cached/users/twitch/"); ok {
+		return bttv.UserResponse{ID: id, Bots: []string{}, ChannelEmotes: nonNil(s.bttvEmotes[id]), SharedEmotes: []bttv.SharedEmote{}}, true
+	}
+
+	return nil, false
+}
+
+func (s *APIServer) ffz(path string) (any, bool) {
+	type emoteSet struct {
+		ID        int         `json:"id"`
+		Emoticons []ffz.Emote `json:"emoticons"`
+	}
+
+	if path == "/v1/set/global" {
+		return map[string]any{
+			"default_sets": []int{1},
+			"sets":         map[string]emoteSet{"1": {ID: 1, Emoticons: nonNil(s.ffzEmotes[""])}},
+		}, true
+	}
+
+	if path == "/v1/badges/ids" {
+		return map[string]any{"badges": []any{}, "users": map[string][]int{}}, true
+	}
+
+	if id, ok := strings.CutPrefix(path, "/v1/room/id/"); ok {
+		set := int(idOf(id) % 100000)
+		return map[string]any{
+			"room": ffz.Room{TwitchID: int(idOf(id)), Set: set, UserBadgeIDs: map[string][]int{}},
+			"sets": map[string]emoteSet{fmt.Sprint(set): {ID: set, Emoticons: nonNil(s.ffzEmotes[id])}},
+		}, true
+	}
+
+	return nil, false
+}
+
+func (s *APIServer) sevenTV(path string) (any, bool) {
+	if path == "/v3/emote-sets/global" {
+		return seventv.EmoteResponse{Emotes: nonNil(s.sevenTVEmotes[""])}, true
+	}
+
+	if id, ok := strings.CutPrefix(path, "/v3/users/twitch/"); ok {
+		resp := seventv.ChannelEmoteResponse{}
+		resp.EmoteSet.Emotes = nonNil(s.sevenTVEmotes[id])
+		return resp, true
+	}
+
+	return nil, false
+}
+
+// nonNil makes empty lists encode as [] like the real APIs do
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+
+	return s
+}
@@ -0,0 +1,30 @@
+package testkit
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of the tests instead of comparing against them")
+
+// RequireGolden compares got with the golden file testdata/<name>.golden of the package under test.
+// Run the tests with -update to write got to the golden file instead, review the diff before committing it.
+func RequireGolden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing, run the test with -update to create it")
+	require.Equal(t, string(want), got, "output differs from %s, run the test with -update if the change is intended", path)
+}
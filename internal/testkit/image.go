@@ -0,0 +1,32 @@
+package testkit
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// ImageSize is the width and height in pixels of the fixture images
+const ImageSize = 28
+
+// Image returns a PNG of a single color derived from name. The same name always results in the same bytes,
+// so image caches and golden files stay stable.
+func Image(name string) []byte {
+	id := idOf(name)
+	fill := color.NRGBA{R: uint8(id >> 16), G: uint8(id >> 8), B: uint8(id), A: 0xff}
+
+	img := image.NewNRGBA(image.Rect(0, 0, ImageSize, ImageSize))
+	for y := range ImageSize {
+		for x := range ImageSize {
+			img.SetNRGBA(x, y, fill)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		panic(err) // encoding an in-memory image can't fail
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,213 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// IRCServer is a fake Twitch IRC WebSocket server. It welcomes clients after authentication, confirms joins
+// and records all lines sent by the clients, lines of chat are sent to the clients with Send.
+type IRCServer struct {
+	t      testing.TB
+	server *httptest.Server
+
+	mu       sync.Mutex
+	conns    []*websocket.Conn
+	received []string
+	changed  chan struct{} // closed and replaced whenever a client connected or sent a line
+}
+
+// NewIRCServer starts a fake IRC server, it is closed when the test finishes
+func NewIRCServer(t testing.TB) *IRCServer {
+	t.Helper()
+
+	s := &IRCServer{
+		t:       t,
+		changed: make(chan struct{}),
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// URL returns the WebSocket URL of the server, used in place of twitchirc.DefaultIRCWSURL
+func (s *IRCServer) URL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// Close disconnects all clients and stops the server
+func (s *IRCServer) Close() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+
+	for _, ws := range conns {
+		_ = ws.Close(websocket.StatusGoingAway, "server closed")
+	}
+
+	s.server.Close()
+}
+
+// Send sends the lines to all connected clients, it waits for a client to connect first
+func (s *IRCServer) Send(lines ...string) {
+	s.t.Helper()
+
+	var conns []*websocket.Conn
+	s.waitUntil("a client connected", func() bool {
+		conns = append(conns[:0], s.conns...)
+		return len(conns) > 0
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), WaitTimeout)
+	defer cancel()
+
+	for _, ws := range conns {
+		if err := ws.Write(ctx, websocket.MessageText, []byte(strings.Join(lines, "\r\n"))); err != nil {
+			s.t.Errorf("testkit: could not send IRC lines: %v", err)
+		}
+	}
+}
+
+// WaitFor waits until a client sent a line starting with prefix and returns it
+func (s *IRCServer) WaitFor(prefix string) string {
+	s.t.Helper()
+
+	var found string
+	s.waitUntil(fmt.Sprintf("a line starting with %q", prefix), func() bool {
+		for _, line := range s.received {
+			if strings.HasPrefix(line, prefix) {
+				found = line
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return found
+}
+
+// Received returns all lines sent by the clients
+func (s *IRCServer) Received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.received...)
+}
+
+// waitUntil calls cond with the lock held until it returns true, the test fails after WaitTimeout
+func (s *IRCServer) waitUntil(what string, cond func() bool) {
+	s.t.Helper()
+
+	timeout := time.After(WaitTimeout)
+
+	for {
+		s.mu.Lock()
+		ok, changed := cond(), s.changed
+		s.mu.Unlock()
+
+		if ok {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+			s.t.Fatalf("testkit: IRC server timed out waiting for %s, received %q", what, s.Received())
+		}
+	}
+}
+
+// notify wakes up waiting tests, the lock must be held
+func (s *IRCServer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *IRCServer) handle(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.CloseNow()
+
+	s.mu.Lock()
+	s.conns = append(s.conns, ws)
+	s.notify()
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		for i, c := range s.conns {
+			if c == ws {
+				s.conns = append(s.conns[:i], s.conns[i+1:]...)
+				break
+			}
+		}
+		s.mu.Unlock()
+	}()
+
+	ctx := r.Context()
+	var nick string
+
+	for {
+		_, data, err := ws.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		for line := range strings.SplitSeq(string(data), "\r\n") {
+			if line == "" {
+				continue
+			}
+
+			s.mu.Lock()
+			s.received = append(s.received, line)
+			s.notify()
+			s.mu.Unlock()
+
+			var reply string
+			switch {
+			case strings.HasPrefix(line, "NICK "):
+				nick = strings.ToLower(strings.TrimPrefix(line, "NICK "))
+				reply = fmt.Sprintf(":tmi.twitch.tv 001 %s :Welcome, GLHF!", nick)
+			case strings.HasPrefix(line, "JOIN "):
+				reply = fmt.Sprintf(":%s!%s@%s.tmi.twitch.tv %s", nick, nick, nick, line)
+			case strings.HasPrefix(line, "PING"):
+				reply = "PONG :tmi.twitch.tv"
+			}
+
+			if reply == "" {
+				continue
+			}
+
+			if err := ws.Write(ctx, websocket.MessageText, []byte(reply)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// PrivateMessage builds a PRIVMSG line of login in channel with the tags Twitch sends, the user ID is derived from the login
+func PrivateMessage(channel, login, text string) string {
+	return fmt.Sprintf("@badge-info=;badges=;color=#1E90FF;display-name=%s;emotes=;first-msg=0;flags=;id=%s;mod=0;returning-chatter=0;room-id=;subscriber=0;tmi-sent-ts=%d;turbo=0;user-id=%s;user-type= :%s!%s@%s.tmi.twitch.tv PRIVMSG #%s :%s",
+		login,
+		fmt.Sprintf("%08x-0000-4000-8000-000000000000", idOf(login+text)),
+		time.Now().UnixMilli(),
+		fmt.Sprint(idOf(login)),
+		login, login, login,
+		strings.ToLower(channel),
+		text,
+	)
+}
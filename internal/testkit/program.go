@@ -0,0 +1,161 @@
+package testkit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Program runs a Bubble Tea model like tea.Program does, without a terminal. Update and View are only called
+// from the test goroutine in WaitFor and Update, commands run in the background and their messages are queued.
+type Program struct {
+	t     testing.TB
+	model tea.Model
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	queue  chan tea.Msg
+}
+
+// NewProgram initializes the model with the window size, the commands of Init run in the background
+func NewProgram(t testing.TB, model tea.Model, width, height int) *Program {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Program{
+		t:      t,
+		model:  model,
+		ctx:    ctx,
+		cancel: cancel,
+		queue:  make(chan tea.Msg, 1024),
+	}
+	t.Cleanup(cancel)
+
+	p.run(model.Init())
+	p.Update(tea.WindowSizeMsg{Width: width, Height: height})
+
+	return p
+}
+
+// Send queues msg, it is safe to call from any goroutine, like tea.Program.Send
+func (p *Program) Send(msg tea.Msg) {
+	select {
+	case p.queue <- msg:
+	case <-p.ctx.Done():
+	}
+}
+
+// Update passes msg to the model right away and runs the returned command in the background
+func (p *Program) Update(msg tea.Msg) {
+	var cmd tea.Cmd
+	p.model, cmd = p.model.Update(msg)
+	p.run(cmd)
+}
+
+// Type sends the text as key presses, followed by enter if the text ends with a newline
+func (p *Program) Type(text string) {
+	line, enter := strings.CutSuffix(text, "\n")
+
+	for _, r := range line {
+		p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	if enter {
+		p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+}
+
+// Model returns the current model
+func (p *Program) Model() tea.Model {
+	return p.model
+}
+
+// View returns the view of the model without ANSI escape sequences
+func (p *Program) View() string {
+	return ansi.Strip(p.model.View())
+}
+
+// WaitFor applies queued messages until the view satisfies cond, the test fails after WaitTimeout
+func (p *Program) WaitFor(cond func(view string) bool) {
+	p.t.Helper()
+
+	timeout := time.After(WaitTimeout)
+
+	for {
+		if cond(p.View()) {
+			return
+		}
+
+		select {
+		case msg := <-p.queue:
+			p.Update(msg)
+		case <-timeout:
+			p.t.Fatalf("testkit: timed out waiting for the view, last view:\n%s", p.View())
+		}
+	}
+}
+
+// WaitForText waits until the view contains text
+func (p *Program) WaitForText(text string) {
+	p.t.Helper()
+
+	p.WaitFor(func(view string) bool {
+		return strings.Contains(view, text)
+	})
+}
+
+// run executes cmd in the background. Batches run concurrently, sequences one after the other.
+func (p *Program) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+
+	go func() {
+		p.dispatch(cmd())
+	}()
+}
+
+func (p *Program) dispatch(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil, tea.QuitMsg:
+		return
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			p.run(cmd)
+		}
+
+		return
+	}
+
+	// tea.Sequence returns an unexported slice of commands
+	if cmds, ok := sequence(msg); ok {
+		for _, cmd := range cmds {
+			if cmd != nil {
+				p.dispatch(cmd())
+			}
+		}
+
+		return
+	}
+
+	p.Send(msg)
+}
+
+func sequence(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != reflect.TypeFor[tea.Cmd]() {
+		return nil, false
+	}
+
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+
+	return cmds, true
+}
@@ -0,0 +1,20 @@
+// Package testkit provides fake Twitch services for integration tests: an IRC WebSocket server, an HTTP server
+// answering Helix, the Chatuino server, IVR, recent-messages, FFZ, BTTV and 7TV requests, deterministic images
+// for the emote and badge CDNs and a driver running Bubble Tea models without a terminal.
+// No request made through the fakes reaches the real services.
+package testkit
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// WaitTimeout is how long the fakes and the driver wait for an expected event before failing the test
+var WaitTimeout = 5 * time.Second
+
+// idOf derives a stable numeric ID from name, so fixtures need no hand-picked IDs
+func idOf(name string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return h.Sum32()
+}
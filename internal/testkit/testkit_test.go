@@ -0,0 +1,100 @@
+package testkit
+
+import (
+	"bytes"
+	"image/png"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type accountProvider struct{}
+
+func (accountProvider) GetAccountBy(id string) (save.Account, error) {
+	return save.Account{ID: id, DisplayName: "justinfan123", AccessToken: "oauth:123"}, nil
+}
+
+func TestImage(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, Image("Kappa"), Image("Kappa"))
+	require.NotEqual(t, Image("Kappa"), Image("LUL"))
+
+	img, err := png.Decode(bytes.NewReader(Image("Kappa")))
+	require.NoError(t, err)
+	require.Equal(t, ImageSize, img.Bounds().Dx())
+}
+
+func TestAPIServer(t *testing.T) {
+	t.Parallel()
+
+	api := NewAPIServer(t)
+	user := api.AddChannel(twitchapi.UserData{Login: "lirik"})
+	api.SetBTTVEmotes(user.ID, bttv.Emote{ID: "1", Code: "catJAM"})
+	api.SetFFZEmotes("", ffz.Emote{ID: 2, Name: "OMEGALUL"})
+
+	channel, err := bttv.NewAPI(api.Client()).GetChannelEmotes(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, "catJAM", channel.ChannelEmotes[0].Code)
+
+	global, err := ffz.NewAPI(api.Client()).GetGlobalEmotes(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "OMEGALUL", global[0].Name)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://cdn.betterttv.net/emote/1/1x.png", nil)
+	require.NoError(t, err)
+	resp, err := api.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, Image("cdn.betterttv.net/emote/1/1x.png"), body)
+
+	// unknown routes never reach the real service
+	req, err = http.NewRequestWithContext(t.Context(), http.MethodGet, "https://api.twitch.tv/helix/unknown", nil)
+	require.NoError(t, err)
+	resp, err = api.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Equal(t, []string{"api.twitch.tv/helix/unknown"}, api.Unhandled())
+}
+
+func TestIRCServer(t *testing.T) {
+	t.Parallel()
+
+	irc := NewIRCServer(t)
+
+	received := make(chan twitchirc.IRCer, 10)
+	conn := twitchirc.NewConn("1", accountProvider{}, zerolog.Nop(), func(msg twitchirc.IRCer, err error) {
+		if msg != nil {
+			received <- msg
+		}
+	})
+	conn.WSURL = irc.URL()
+	go conn.Run()
+	t.Cleanup(conn.Close)
+
+	require.NoError(t, conn.JoinChannel("lirik"))
+	irc.WaitFor("JOIN #lirik")
+
+	irc.Send(PrivateMessage("lirik", "viewer", "hello chat"))
+
+	for msg := range received {
+		if pm, ok := msg.(*twitchirc.PrivateMessage); ok {
+			require.Equal(t, "viewer", pm.LoginName)
+			require.Equal(t, "hello chat", pm.Message)
+			return
+		}
+	}
+}
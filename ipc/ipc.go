@@ -0,0 +1,97 @@
+// Package ipc implements the control socket other programs can use to control a running Chatuino,
+// e.g. to open a tab from a window manager key binding.
+//
+// Clients connect to a Unix socket and write one JSON request per line, each request is answered with one JSON response line:
+//
+//	{"command":"open_tab","channel":"lirik"}
+//	{"ok":true,"tab":{"id":"…","kind":"channel","channel":"lirik","account":"julezdev","focused":true}}
+//
+// Windows supports Unix sockets since Windows 10 version 1803.
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Commands accepted by the socket
+const (
+	CommandState       = "state"        // list the open tabs
+	CommandOpenTab     = "open_tab"     // open a tab for channel, optionally as account and of another kind
+	CommandCloseTab    = "close_tab"    // close the tab selected by tab, index or channel
+	CommandSwitchTab   = "switch_tab"   // focus the tab selected by tab, index or channel
+	CommandSendMessage = "send_message" // send text to the chat of the tab selected by tab, index or channel
+	CommandLogs        = "logs"         // recent log events of the running instance, oldest first
+)
+
+// Tab kinds used in requests and responses
+const (
+	TabKindChannel          = "channel"
+	TabKindMention          = "mention"
+	TabKindLiveNotification = "live_notification"
+	TabKindYouTube          = "youtube"
+	TabKindKick             = "kick"
+	TabKindIRCInspector     = "irc_inspector"
+	TabKindReplay           = "replay"
+)
+
+// Request is a command sent by a client
+type Request struct {
+	Command string `json:"command"`
+	Tab     string `json:"tab,omitempty"`     // tab ID
+	Index   int    `json:"index,omitempty"`   // position of the tab, starting at 1
+	Channel string `json:"channel,omitempty"` // channel login
+	Account string `json:"account,omitempty"` // display name of the account, empty uses the main account
+	Kind    string `json:"kind,omitempty"`    // kind of the opened tab, empty opens a channel tab
+	Text    string `json:"text,omitempty"`    // chat message
+}
+
+// Tab describes an open tab
+type Tab struct {
+	ID      string `json:"id"`
+	Index   int    `json:"index"`
+	Kind    string `json:"kind"`
+	Channel string `json:"channel,omitempty"`
+	Account string `json:"account,omitempty"`
+	Focused bool   `json:"focused"`
+}
+
+// Response answers a request
+type Response struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+	Tab   *Tab   `json:"tab,omitempty"`  // the opened, switched to or messaged tab
+	Tabs  []Tab  `json:"tabs,omitempty"` // all tabs, for state
+
+	Logs []json.RawMessage `json:"logs,omitempty"` // log events as written by the logger, for logs
+}
+
+// Errorf returns a failed response
+func Errorf(format string, args ...any) Response {
+	return Response{Error: fmt.Sprintf(format, args...)}
+}
+
+// Send connects to the socket at path, sends the request and returns the response
+func Send(path string, req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second*5)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to connect to %s, is Chatuino running with ipc.enabled? %w", path, err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(RequestTimeout + time.Second*5))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return resp, nil
+}
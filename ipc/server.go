@@ -0,0 +1,220 @@
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// RequestTimeout is how long a handler may take to answer a request
+	RequestTimeout = time.Second * 10
+
+	// maxRequestSize is the maximum length of a request line
+	maxRequestSize = 64 * 1024
+)
+
+// Handler answers requests, it is called from the goroutine of the connection
+type Handler func(Request) Response
+
+// Server accepts connections on the control socket
+type Server struct {
+	logger   zerolog.Logger
+	path     string
+	listener net.Listener
+	handler  Handler
+
+	m      sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Listen creates the socket at path and answers requests with handler until the server is closed.
+// A socket left behind by a crashed instance is replaced, a socket of a running instance is an error.
+func Listen(logger zerolog.Logger, path string, handler Handler) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		logger:   logger.With().Str("component", "ipc").Logger(),
+		path:     path,
+		listener: listener,
+		handler:  handler,
+		conns:    map[net.Conn]struct{}{},
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	s.logger.Info().Str("socket", path).Msg("listening for control commands")
+
+	return s, nil
+}
+
+// listenPrivate creates the socket in a new directory only the current user can access, restricts its permissions
+// and moves it to path, so the socket is never accessible by other users
+func listenPrivate(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".sock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	// the socket is moved, the server removes it from path when it's closed
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	// only the current user may control Chatuino
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	return listener, nil
+}
+
+// removeStaleSocket removes a socket at path nobody is listening on anymore, anything else at path is never removed
+func removeStaleSocket(path string) error {
+	stat, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if stat.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("socket path %s exists and is no socket", path)
+	}
+
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("socket %s is used by another Chatuino instance", path)
+	}
+
+	return os.Remove(path)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.logger.Err(err).Msg("failed to accept connection")
+			}
+			return
+		}
+
+		s.m.Lock()
+		if s.closed {
+			s.m.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.m.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.forget(conn)
+
+			s.handleConn(conn)
+		}()
+	}
+}
+
+func (s *Server) forget(conn net.Conn) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	delete(s.conns, conn)
+	_ = conn.Close()
+}
+
+// handleConn answers requests until the client closes the connection
+func (s *Server) handleConn(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var (
+			req  Request
+			resp Response
+		)
+
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Errorf("invalid request: %s", err)
+		} else {
+			resp = s.handler(req)
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Err(err).Msg("failed to read request")
+		_ = encoder.Encode(Errorf("invalid request: %s", err))
+	}
+}
+
+// Close stops accepting connections, closes open connections and removes the socket
+func (s *Server) Close() error {
+	s.m.Lock()
+	s.closed = true
+	err := s.listener.Close()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.m.Unlock()
+
+	s.wg.Wait()
+
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+		err = errors.Join(err, rmErr)
+	}
+
+	return err
+}
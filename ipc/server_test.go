@@ -0,0 +1,153 @@
+package ipc
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// socketPath returns a short socket path, since socket paths are limited to about 100 characters
+func socketPath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "chatuino")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "test.sock")
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	path := socketPath(t)
+
+	s, err := Listen(zerolog.Nop(), path, func(req Request) Response {
+		if req.Command != CommandState {
+			return Errorf("unknown command %q", req.Command)
+		}
+
+		return Response{OK: true, Tabs: []Tab{{ID: "1", Index: 1, Kind: TabKindChannel, Channel: "lirik", Focused: true}}}
+	})
+	require.NoError(t, err)
+
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+	}
+
+	resp, err := Send(path, Request{Command: CommandState})
+	require.NoError(t, err)
+	require.Equal(t, Response{OK: true, Tabs: []Tab{{ID: "1", Index: 1, Kind: TabKindChannel, Channel: "lirik", Focused: true}}}, resp)
+
+	resp, err = Send(path, Request{Command: "quit"})
+	require.NoError(t, err)
+	require.Equal(t, Response{Error: `unknown command "quit"`}, resp)
+
+	// multiple requests on one connection, invalid lines are answered with an error
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("{\"command\":\"state\"}\nnot json\n"))
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Contains(t, line, `"ok":true`)
+
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Contains(t, line, `"error":"invalid request`)
+
+	// a running instance keeps its socket
+	_, err = Listen(zerolog.Nop(), path, nil)
+	require.ErrorContains(t, err, "is used by another Chatuino instance")
+
+	// closing the server closes open connections and removes the socket
+	require.NoError(t, s.Close())
+
+	_, err = reader.ReadString('\n')
+	require.Error(t, err)
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = Send(path, Request{Command: CommandState})
+	require.ErrorContains(t, err, "is Chatuino running")
+}
+
+func TestListen_StaleSocket(t *testing.T) {
+	t.Parallel()
+
+	path := socketPath(t)
+
+	// left behind by a crashed instance
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	s, err := Listen(zerolog.Nop(), path, func(Request) Response { return Response{OK: true} })
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	resp, err := Send(path, Request{Command: CommandState})
+	require.NoError(t, err)
+	require.True(t, resp.OK)
+}
+
+func TestListen_NoSocket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		create func(t *testing.T, path string)
+	}{
+		{
+			name: "regular file",
+			create: func(t *testing.T, path string) {
+				require.NoError(t, os.WriteFile(path, []byte("data"), 0o600))
+			},
+		},
+		{
+			name: "symlink",
+			create: func(t *testing.T, path string) {
+				if runtime.GOOS == "windows" {
+					t.Skip("symlinks need extra privileges on windows")
+				}
+
+				require.NoError(t, os.Symlink(filepath.Join(filepath.Dir(path), "target"), path))
+			},
+		},
+		{
+			name: "directory",
+			create: func(t *testing.T, path string) {
+				require.NoError(t, os.Mkdir(path, 0o700))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := socketPath(t)
+			tt.create(t, path)
+
+			_, err := Listen(zerolog.Nop(), path, nil)
+			require.ErrorContains(t, err, "is no socket")
+
+			_, err = os.Lstat(path)
+			require.NoError(t, err, "only sockets are removed")
+		})
+	}
+}
@@ -0,0 +1,492 @@
+// Package kick reads the chats of Kick channels (experimental) from the public Pusher WebSocket used by the Kick website.
+// The chats are read only, sending messages requires an OAuth app of the Kick developer API which is not supported.
+package kick
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const (
+	apiURL    = "https://kick.com/api/v2"
+	pusherURL = "wss://ws-us2.pusher.com/app/32cbd69e4b950bf97679?protocol=7&client=js&version=8.4.0&flash=false"
+)
+
+const (
+	dialTimeout       = time.Second * 10
+	reconnectDelay    = time.Second * 5
+	pingInterval      = time.Minute
+	pingTimeout       = time.Second * 10
+	maxConnectFailure = 5       // failed connections in a row until the chat is given up
+	maxMessageSize    = 1 << 20 // chat events are small, the limit only guards against broken frames
+)
+
+var (
+	slugPattern  = regexp.MustCompile(`^[a-z0-9_-]{1,40}$`)
+	emotePattern = regexp.MustCompile(`\[emote:\d+:([^\]]+)\]`)
+)
+
+// Client joins Kick chats, it implements chatprovider.Provider
+type Client struct {
+	client *http.Client
+	apiURL string
+	wsURL  string
+}
+
+func New(client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Client{client: client, apiURL: apiURL, wsURL: pusherURL}
+}
+
+func (c *Client) Name() string {
+	return "Kick"
+}
+
+// Join connects to the chat of a channel name or a link to the channel, the channel doesn't need to be live
+func (c *Client) Join(ctx context.Context, channel string) (chatprovider.Chat, error) {
+	slug, err := parseSlug(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/channels/"+url.PathEscape(slug), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("Kick channel %s not found", slug)
+	default:
+		// the Kick API is behind a bot protection, which sometimes blocks requests not coming from a browser
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("Kick: unexpected status %d while looking up channel %s", resp.StatusCode, slug)
+	}
+
+	var info struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+		Chatroom struct {
+			ID int `json:"id"`
+		} `json:"chatroom"`
+		Livestream *struct {
+			SessionTitle string `json:"session_title"`
+		} `json:"livestream"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxMessageSize)).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode Kick channel %s: %w", slug, err)
+	}
+
+	if info.Chatroom.ID == 0 {
+		return nil, fmt.Errorf("Kick channel %s has no chat room", slug)
+	}
+
+	title := info.User.Username
+	if info.Livestream != nil && info.Livestream.SessionTitle != "" {
+		title += ": " + info.Livestream.SessionTitle
+	}
+
+	chat := &chatroom{
+		client:   c,
+		id:       info.Chatroom.ID,
+		channel:  channel,
+		title:    title,
+		messages: make(chan twitchirc.IRCer, 256),
+	}
+
+	go chat.run(ctx)
+
+	return chat, nil
+}
+
+// parseSlug returns the slug of a channel name or a link like kick.com/name
+func parseSlug(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	s = strings.TrimPrefix(s, "www.")
+
+	if rest, ok := strings.CutPrefix(s, "kick.com/"); ok {
+		s, _, _ = strings.Cut(rest, "/")
+		s, _, _ = strings.Cut(s, "?")
+	}
+
+	// slugs are the lower case user names with dashes instead of underscores
+	slug := strings.ReplaceAll(strings.ToLower(s), "_", "-")
+	if !slugPattern.MatchString(slug) {
+		return "", fmt.Errorf("%q is not a Kick channel name or link", s)
+	}
+
+	return slug, nil
+}
+
+// chatroom is a joined Kick chat, it implements chatprovider.Chat
+type chatroom struct {
+	client  *Client
+	id      int
+	channel string
+	title   string
+
+	messages chan twitchirc.IRCer
+	err      error // set before messages is closed
+}
+
+func (c *chatroom) Title() string {
+	return c.title
+}
+
+func (c *chatroom) Messages() <-chan twitchirc.IRCer {
+	return c.messages
+}
+
+func (c *chatroom) Err() error {
+	return c.err
+}
+
+func (c *chatroom) ReadOnly() bool {
+	return true
+}
+
+func (c *chatroom) Send(context.Context, string) error {
+	return chatprovider.ErrReadOnly
+}
+
+// run receives the messages of the chat and reconnects after the connection is lost, until ctx is done
+func (c *chatroom) run(ctx context.Context) {
+	defer close(c.messages)
+
+	var failures int
+
+	for {
+		subscribed, err := c.connectOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if subscribed {
+			failures = 0
+		}
+
+		failures++
+		if failures >= maxConnectFailure {
+			c.err = err
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// pusherEvent is a message of the Pusher protocol, https://pusher.com/docs/channels/library_auth_reference/pusher-websockets-protocol/
+type pusherEvent struct {
+	Event   string          `json:"event"`
+	Channel string          `json:"channel,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// payload returns the data of the event, which is sent as a JSON encoded string for most events
+func (e pusherEvent) payload() []byte {
+	var s string
+	if err := json.Unmarshal(e.Data, &s); err == nil {
+		return []byte(s)
+	}
+
+	return e.Data
+}
+
+// connectOnce subscribes to the chat room and sends its messages until the connection is lost, it reports whether the subscription succeeded
+func (c *chatroom) connectOnce(ctx context.Context) (bool, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(dialCtx, c.client.wsURL, &websocket.DialOptions{HTTPClient: c.client.client})
+	if err != nil {
+		return false, fmt.Errorf("dial failed: %w", err)
+	}
+	defer ws.CloseNow()
+
+	ws.SetReadLimit(maxMessageSize)
+
+	connCtx, cancelConn := context.WithCancel(ctx)
+	defer cancelConn()
+
+	go keepAlive(connCtx, ws)
+
+	var subscribed bool
+
+	for {
+		_, b, err := ws.Read(connCtx)
+		if err != nil {
+			return subscribed, err
+		}
+
+		var event pusherEvent
+		if err := json.Unmarshal(b, &event); err != nil {
+			continue
+		}
+
+		switch event.Event {
+		case "pusher:connection_established":
+			data, _ := json.Marshal(map[string]string{"auth": "", "channel": fmt.Sprintf("chatrooms.%d.v2", c.id)})
+			if err := writeEvent(connCtx, ws, pusherEvent{Event: "pusher:subscribe", Data: data}); err != nil {
+				return false, err
+			}
+		case "pusher_internal:subscription_succeeded":
+			subscribed = true
+		case "pusher:ping":
+			if err := writeEvent(connCtx, ws, pusherEvent{Event: "pusher:pong", Data: json.RawMessage("{}")}); err != nil {
+				return subscribed, err
+			}
+		case "pusher:error":
+			var data struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			_ = json.Unmarshal(event.payload(), &data)
+
+			return subscribed, fmt.Errorf("Kick: %s (%d)", data.Message, data.Code)
+		default:
+			msg := convert(c.channel, event.Event, event.payload())
+			if msg == nil {
+				continue
+			}
+
+			select {
+			case c.messages <- msg:
+			case <-ctx.Done():
+				return subscribed, ctx.Err()
+			}
+		}
+	}
+}
+
+// keepAlive pings the server and closes the connection when it doesn't answer, which ends the read of the connection
+func keepAlive(ctx context.Context, ws *websocket.Conn) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pingInterval):
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err := ws.Ping(pingCtx)
+		cancel()
+
+		if err != nil {
+			_ = ws.CloseNow()
+			return
+		}
+	}
+}
+
+func writeEvent(ctx context.Context, ws *websocket.Conn, event pusherEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return ws.Write(ctx, websocket.MessageText, b)
+}
+
+type user struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Slug     string `json:"slug"`
+}
+
+type chatMessage struct {
+	ID        string `json:"id"`
+	Content   string `json:"content"`
+	Type      string `json:"type"` // message or reply
+	CreatedAt string `json:"created_at"`
+	Sender    struct {
+		user
+		Identity struct {
+			Color  string `json:"color"`
+			Badges []struct {
+				Type  string `json:"type"`
+				Count int    `json:"count"` // months of subscriber badges
+			} `json:"badges"`
+		} `json:"identity"`
+	} `json:"sender"`
+	Metadata struct {
+		OriginalSender struct {
+			Username string `json:"username"`
+		} `json:"original_sender"`
+		OriginalMessage struct {
+			ID      string `json:"id"`
+			Content string `json:"content"`
+		} `json:"original_message"`
+	} `json:"metadata"`
+}
+
+// convert maps a Pusher event of the chat room onto the twitchirc message types, nil if it is not shown
+func convert(channel, event string, data []byte) twitchirc.IRCer {
+	switch event {
+	case `App\Events\ChatMessageEvent`:
+		var m chatMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil
+		}
+
+		sentAt, err := time.Parse(time.RFC3339, m.CreatedAt)
+		if err != nil {
+			sentAt = time.Now()
+		}
+
+		msg := &twitchirc.PrivateMessage{
+			ID:              m.ID,
+			ChannelUserName: channel,
+			DisplayName:     m.Sender.Username,
+			LoginName:       m.Sender.Username,
+			UserID:          strconv.Itoa(m.Sender.ID),
+			Color:           m.Sender.Identity.Color,
+			TMISentTS:       sentAt,
+			Message:         emotePattern.ReplaceAllString(m.Content, "$1"),
+		}
+
+		for _, badge := range m.Sender.Identity.Badges {
+			msg.Badges = append(msg.Badges, twitchirc.Badge{Name: badge.Type, Version: strconv.Itoa(max(badge.Count, 1))})
+
+			switch badge.Type {
+			case "moderator":
+				msg.Mod = true
+			case "vip":
+				msg.VIP = true
+			case "subscriber":
+				msg.Subscriber = true
+			}
+		}
+
+		if m.Type == "reply" {
+			msg.ParentMsgID = m.Metadata.OriginalMessage.ID
+			msg.ParentMsgBody = emotePattern.ReplaceAllString(m.Metadata.OriginalMessage.Content, "$1")
+			msg.ParentDisplayName = m.Metadata.OriginalSender.Username
+			msg.ParentUserLogin = m.Metadata.OriginalSender.Username
+		}
+
+		return msg
+	case `App\Events\MessageDeletedEvent`:
+		var deleted struct {
+			Message struct {
+				ID string `json:"id"`
+			} `json:"message"`
+		}
+
+		if err := json.Unmarshal(data, &deleted); err != nil || deleted.Message.ID == "" {
+			return nil
+		}
+
+		return &twitchirc.ClearMessage{
+			ChannelUserName: channel,
+			TargetMsgID:     deleted.Message.ID,
+			TMISentTS:       time.Now(),
+		}
+	case `App\Events\UserBannedEvent`:
+		var ban struct {
+			User      user `json:"user"`
+			Permanent bool `json:"permanent"`
+			Duration  int  `json:"duration"` // minutes
+		}
+
+		if err := json.Unmarshal(data, &ban); err != nil || ban.User.Username == "" {
+			return nil
+		}
+
+		userID := strconv.Itoa(ban.User.ID)
+		clear := &twitchirc.ClearChat{
+			ChannelUserName: channel,
+			TargetUserID:    &userID,
+			UserName:        &ban.User.Username,
+			TMISentTS:       time.Now(),
+		}
+
+		if !ban.Permanent && ban.Duration > 0 {
+			seconds := ban.Duration * 60
+			clear.BanDuration = &seconds
+		}
+
+		return clear
+	case `App\Events\ChatroomClearEvent`:
+		return &twitchirc.ClearChat{
+			ChannelUserName: channel,
+			TMISentTS:       time.Now(),
+		}
+	case `App\Events\SubscriptionEvent`:
+		var sub struct {
+			Username string `json:"username"`
+			Months   int    `json:"months"`
+		}
+
+		if err := json.Unmarshal(data, &sub); err != nil || sub.Username == "" {
+			return nil
+		}
+
+		return notice(channel, fmt.Sprintf("%s subscribed for %d months", sub.Username, max(sub.Months, 1)))
+	case `App\Events\GiftedSubscriptionsEvent`:
+		var gift struct {
+			GifterUsername  string   `json:"gifter_username"`
+			GiftedUsernames []string `json:"gifted_usernames"`
+		}
+
+		if err := json.Unmarshal(data, &gift); err != nil || len(gift.GiftedUsernames) == 0 {
+			return nil
+		}
+
+		return notice(channel, fmt.Sprintf("%s gifted %d subscriptions", cmp.Or(gift.GifterUsername, "An anonymous user"), len(gift.GiftedUsernames)))
+	case `App\Events\StreamHostEvent`:
+		var host struct {
+			HostUsername  string `json:"host_username"`
+			NumberViewers int    `json:"number_viewers"`
+		}
+
+		if err := json.Unmarshal(data, &host); err != nil || host.HostUsername == "" {
+			return nil
+		}
+
+		return notice(channel, fmt.Sprintf("%s is hosting with %d viewers", host.HostUsername, host.NumberViewers))
+	}
+
+	return nil
+}
+
+func notice(channel, text string) *twitchirc.Notice {
+	return &twitchirc.Notice{
+		ChannelUserName: channel,
+		Message:         text,
+		FakeTimestamp:   time.Now(),
+	}
+}
@@ -0,0 +1,166 @@
+package kick
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSlug(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		"name":          {in: "xQc", want: "xqc"},
+		"underscore":    {in: "some_streamer", want: "some-streamer"},
+		"url":           {in: "https://kick.com/xqc", want: "xqc"},
+		"url-www":       {in: "www.kick.com/xqc/videos?page=2", want: "xqc"},
+		"url-no-scheme": {in: "kick.com/xqc", want: "xqc"},
+		"empty":         {in: " ", wantErr: true},
+		"other-url":     {in: "https://twitch.tv/xqc", wantErr: true},
+		"spaces":        {in: "two words", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSlug(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_convert(t *testing.T) {
+	t.Parallel()
+
+	privMsg := convert("xqc", `App\Events\ChatMessageEvent`, []byte(`{
+		"id": "a1", "chatroom_id": 1, "content": "hello [emote:37226:KEKW] chat", "type": "reply", "created_at": "2025-01-01T12:00:00+00:00",
+		"sender": {"id": 42, "username": "Viewer_1", "slug": "viewer-1", "identity": {"color": "#FF9D00", "badges": [{"type": "moderator", "text": "Moderator"}, {"type": "subscriber", "text": "Subscriber", "count": 3}]}},
+		"metadata": {"original_sender": {"id": 7, "username": "Other"}, "original_message": {"id": "a0", "content": "first [emote:1:Kappa]"}}
+	}`)).(*twitchirc.PrivateMessage)
+
+	require.Equal(t, "hello KEKW chat", privMsg.Message)
+	require.Equal(t, "Viewer_1", privMsg.DisplayName)
+	require.Equal(t, "42", privMsg.UserID)
+	require.Equal(t, "#FF9D00", privMsg.Color)
+	require.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), privMsg.TMISentTS.UTC())
+	require.Equal(t, []twitchirc.Badge{{Name: "moderator", Version: "1"}, {Name: "subscriber", Version: "3"}}, privMsg.Badges)
+	require.True(t, privMsg.Mod)
+	require.True(t, privMsg.Subscriber)
+	require.Equal(t, "a0", privMsg.ParentMsgID)
+	require.Equal(t, "first Kappa", privMsg.ParentMsgBody)
+	require.Equal(t, "Other", privMsg.ParentDisplayName)
+
+	require.Equal(t, "a1", convert("xqc", `App\Events\MessageDeletedEvent`, []byte(`{"id": "d1", "message": {"id": "a1"}}`)).(*twitchirc.ClearMessage).TargetMsgID)
+
+	clearChat := convert("xqc", `App\Events\UserBannedEvent`, []byte(`{"id": "b1", "user": {"id": 42, "username": "Viewer_1", "slug": "viewer-1"}, "permanent": false, "duration": 5}`)).(*twitchirc.ClearChat)
+	require.Equal(t, "Viewer_1", *clearChat.UserName)
+	require.Equal(t, 300, *clearChat.BanDuration)
+
+	permanent := convert("xqc", `App\Events\UserBannedEvent`, []byte(`{"id": "b2", "user": {"id": 42, "username": "Viewer_1"}, "permanent": true}`)).(*twitchirc.ClearChat)
+	require.Nil(t, permanent.BanDuration)
+
+	require.Nil(t, convert("xqc", `App\Events\ChatroomClearEvent`, []byte(`{"id": "c1"}`)).(*twitchirc.ClearChat).UserName)
+	require.Equal(t, "An anonymous user gifted 2 subscriptions", convert("xqc", `App\Events\GiftedSubscriptionsEvent`, []byte(`{"gifted_usernames": ["a", "b"], "gifter_username": ""}`)).(*twitchirc.Notice).Message)
+	require.Nil(t, convert("xqc", `App\Events\PinnedMessageCreatedEvent`, []byte(`{}`)))
+}
+
+// fakePusher accepts one connection, answers the subscription and sends the events
+type fakePusher struct {
+	t      *testing.T
+	events []string
+}
+
+func (f *fakePusher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, nil)
+	require.NoError(f.t, err)
+	defer ws.CloseNow()
+
+	ctx := r.Context()
+
+	require.NoError(f.t, ws.Write(ctx, websocket.MessageText, []byte(`{"event": "pusher:connection_established", "data": "{\"socket_id\":\"1.2\",\"activity_timeout\":120}"}`)))
+
+	_, b, err := ws.Read(ctx)
+	require.NoError(f.t, err)
+
+	var subscribe struct {
+		Event string `json:"event"`
+		Data  struct {
+			Channel string `json:"channel"`
+		} `json:"data"`
+	}
+	require.NoError(f.t, json.Unmarshal(b, &subscribe))
+	require.Equal(f.t, "pusher:subscribe", subscribe.Event)
+	require.Equal(f.t, "chatrooms.1234.v2", subscribe.Data.Channel)
+
+	require.NoError(f.t, ws.Write(ctx, websocket.MessageText, []byte(`{"event": "pusher_internal:subscription_succeeded", "data": "{}", "channel": "chatrooms.1234.v2"}`)))
+
+	for _, event := range f.events {
+		require.NoError(f.t, ws.Write(ctx, websocket.MessageText, []byte(event)))
+	}
+
+	// Pusher ends connections with an error, the chat reconnects after it
+	_ = ws.Write(ctx, websocket.MessageText, []byte(`{"event": "pusher:error", "data": {"code": 4200, "message": "Please reconnect immediately"}}`))
+}
+
+func TestClient_Join(t *testing.T) {
+	t.Parallel()
+
+	var connected atomic.Bool
+
+	pusher := &fakePusher{t: t, events: []string{
+		`{"event": "pusher:ping", "data": {}}`,
+		`{"event": "App\\Events\\ChatMessageEvent", "channel": "chatrooms.1234.v2", "data": "{\"id\":\"a1\",\"content\":\"hello\",\"type\":\"message\",\"created_at\":\"2025-01-01T12:00:00+00:00\",\"sender\":{\"id\":42,\"username\":\"Viewer_1\",\"identity\":{\"badges\":[]}}}"}`,
+	}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/channels/xqc":
+			_, _ = io.WriteString(w, `{"id": 1, "slug": "xqc", "user": {"username": "xQc"}, "chatroom": {"id": 1234}, "livestream": {"session_title": "Just Chatting"}}`)
+		case r.URL.Path == "/channels/unknown":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/ws" && connected.CompareAndSwap(false, true):
+			pusher.ServeHTTP(w, r)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := New(srv.Client())
+	client.apiURL = srv.URL
+	client.wsURL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	_, err := client.Join(t.Context(), "unknown")
+	require.ErrorContains(t, err, "not found")
+
+	chat, err := client.Join(t.Context(), "https://kick.com/xqc")
+	require.NoError(t, err)
+	require.Equal(t, "xQc: Just Chatting", chat.Title())
+	require.True(t, chat.ReadOnly())
+	require.ErrorIs(t, chat.Send(t.Context(), "hi"), chatprovider.ErrReadOnly)
+
+	msg := (<-chat.Messages()).(*twitchirc.PrivateMessage)
+	require.Equal(t, "hello", msg.Message)
+	require.Equal(t, "https://kick.com/xqc", msg.ChannelUserName)
+}
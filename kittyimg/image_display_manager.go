@@ -27,6 +27,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/adrg/xdg"
+	"github.com/julez-dev/chatuino/metrics"
 	easyjson "github.com/mailru/easyjson"
 	"github.com/spf13/afero"
 	"golang.org/x/sync/syncmap"
@@ -43,6 +44,37 @@ var (
 	globalPlacedImages                         = &syncmap.Map{}
 )
 
+func init() {
+	metrics.Default.GaugeFunc("chatuino_image_placed", "Images placed in the terminal in this session", func() float64 {
+		images, _ := PlacedImagesStats()
+		return float64(images)
+	})
+
+	metrics.Default.GaugeFunc("chatuino_image_placed_bytes", "Pixel data of the images placed in the terminal, held in the memory of the terminal", func() float64 {
+		_, size := PlacedImagesStats()
+		return float64(size)
+	})
+}
+
+// PlacedImagesStats returns the number of images placed in the terminal and the size of their pixel data
+func PlacedImagesStats() (images int, size int64) {
+	globalPlacedImages.Range(func(_, value any) bool {
+		i, ok := value.(DecodedImage)
+		if !ok {
+			return true
+		}
+
+		images++
+		for _, frame := range i.Images {
+			size += int64(frame.Width) * int64(frame.Height) * 4 // RGBA
+		}
+
+		return true
+	})
+
+	return images, size
+}
+
 //easyjson:json
 type DecodedImage struct {
 	ID     int32               `json:"-"`
@@ -92,6 +124,56 @@ func (i DecodedImage) PrepareCommand() string {
 	return b.String()
 }
 
+// DirectPrepareCommand is like PrepareCommand, but sends the zlib compressed pixel data of each frame with the command instead of the path of the file.
+// The data is split into chunks of chunkSize base64 bytes, a multiple of 4, so no single escape sequence gets too large.
+func (i DecodedImage) DirectPrepareCommand(frames [][]byte, chunkSize int) string {
+	var b strings.Builder
+
+	// transmit first image
+	writeChunked(&b, fmt.Sprintf("f=32,i=%d,q=2,s=%d,v=%d,o=z", i.ID, i.Images[0].Width, i.Images[0].Height), "q=2", frames[0], chunkSize)
+
+	// is animated
+	if len(i.Images) > 1 {
+		// send first frame
+		fmt.Fprintf(&b, "\033_Ga=a,i=%d,r=1,z=%d,q=2;\033\\", i.ID, i.Images[0].DelayInMS)
+
+		// send each frame after first image, every chunk of a frame needs a=f
+		for n, img := range i.Images[1:] {
+			writeChunked(&b, fmt.Sprintf("a=f,i=%d,f=32,s=%d,v=%d,z=%d,q=2,o=z", i.ID, img.Width, img.Height, img.DelayInMS), "a=f,q=2", frames[n+1], chunkSize)
+		}
+
+		// start animation
+		fmt.Fprintf(&b, "\033_Ga=a,i=%d,s=3,v=1,q=2;\033\\", i.ID)
+	}
+
+	// create virtual placement
+	fmt.Fprintf(&b, "\x1b_Ga=p,i=%d,p=%d,q=2,U=1,r=1,c=%d\x1b\\", i.ID, i.ID, i.Cols)
+
+	return b.String()
+}
+
+// writeChunked writes data base64 encoded in chunks, the first chunk carries all keys, the following ones only the continuation keys
+func writeChunked(b *strings.Builder, keys, continuationKeys string, data []byte, chunkSize int) {
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(chunkSize, len(payload))]
+		payload = payload[len(chunk):]
+
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+
+		if first {
+			fmt.Fprintf(b, "\x1b_G%s,m=%d;%s\x1b\\", keys, more, chunk)
+			continue
+		}
+
+		fmt.Fprintf(b, "\x1b_G%s,m=%d;%s\x1b\\", continuationKeys, more, chunk)
+	}
+}
+
 func (i DecodedImage) DisplayUnicodePlaceholder() string {
 	r, g, b := intToRGB(i.ID)
 	return fmt.Sprintf("\033[38;2;%d;%d;%dm%s\033[39m", r, g, b, strings.Repeat("\U0010EEEE", i.Cols))
@@ -117,6 +199,9 @@ type KittyDisplayUnit struct {
 type DisplayManager struct {
 	fs                    afero.Fs
 	cellWidth, cellHeight float32
+	passthrough           func(string) string // wraps the graphics commands for terminal multiplexers, nil sends them as they are
+	chunkSize             int                 // base64 bytes of pixel data per command when sending the data directly, 0 sends the paths of the cached files
+	reducedMotion         atomic.Bool         // animated images are stopped on their first frame
 }
 
 func NewDisplayManager(fs afero.Fs, cellWidth, cellHeight float32) *DisplayManager {
@@ -127,6 +212,90 @@ func NewDisplayManager(fs afero.Fs, cellWidth, cellHeight float32) *DisplayManag
 	}
 }
 
+// SetPassthrough wraps all graphics commands with wrap, so a terminal multiplexer like tmux forwards them to the terminal.
+// The unicode placeholders are regular text and need no wrapping.
+func (d *DisplayManager) SetPassthrough(wrap func(string) string) {
+	d.passthrough = wrap
+}
+
+// SetDirectTransmission sends the pixel data with the graphics commands in chunks of chunkSize base64 bytes, instead of the paths of the cached files.
+// The terminal of an SSH session runs on another machine and can't read the cached files. chunkSize is rounded down to a multiple of 4, 0 sends the paths again.
+func (d *DisplayManager) SetDirectTransmission(chunkSize int) {
+	d.chunkSize = max(chunkSize-chunkSize%4, 0)
+}
+
+// SetReducedMotion stops all animated images on their first frame, or starts them again.
+// The returned command applies the change to the images already placed in the terminal, it is empty if nothing changed.
+func (d *DisplayManager) SetReducedMotion(reduced bool) string {
+	if d.reducedMotion.Swap(reduced) == reduced {
+		return ""
+	}
+
+	var cmd strings.Builder
+
+	globalPlacedImages.Range(func(_, value any) bool {
+		if i, ok := value.(DecodedImage); ok {
+			cmd.WriteString(i.animationCommand(!reduced))
+		}
+
+		return true
+	})
+
+	return d.command(cmd.String())
+}
+
+// animationCommand starts or stops the animation of the image, empty for static images
+func (i DecodedImage) animationCommand(play bool) string {
+	if len(i.Images) < 2 {
+		return ""
+	}
+
+	if play {
+		return fmt.Sprintf("\x1b_Ga=a,i=%d,s=3,v=1,q=2;\x1b\\", i.ID)
+	}
+
+	// c=1 shows the first frame while the animation is stopped
+	return fmt.Sprintf("\x1b_Ga=a,i=%d,c=1,s=1,q=2;\x1b\\", i.ID)
+}
+
+// prepareCommand returns the command which transmits and places the image, with the paths or the pixel data of the cached files
+func (d *DisplayManager) prepareCommand(i DecodedImage) (string, error) {
+	// the animation is stopped right after it was started, before the terminal shows the next frame
+	var stop string
+	if d.reducedMotion.Load() {
+		stop = i.animationCommand(false)
+	}
+
+	if d.chunkSize == 0 {
+		return d.command(i.PrepareCommand() + stop), nil
+	}
+
+	frames := make([][]byte, 0, len(i.Images))
+	for _, frame := range i.Images {
+		path, err := base64.StdEncoding.DecodeString(frame.EncodedPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode image path: %w", err)
+		}
+
+		data, err := afero.ReadFile(d.fs, string(path))
+		if err != nil {
+			return "", fmt.Errorf("failed to read image: %w", err)
+		}
+
+		frames = append(frames, data)
+	}
+
+	return d.command(i.DirectPrepareCommand(frames, d.chunkSize) + stop), nil
+}
+
+func (d *DisplayManager) command(cmd string) string {
+	if d.passthrough == nil || cmd == "" {
+		return cmd
+	}
+
+	return d.passthrough(cmd)
+}
+
 func (d *DisplayManager) Convert(unit DisplayUnit) (KittyDisplayUnit, error) {
 	// 1st: image was already placed in this session, reusing placement
 	if cached, ok := globalPlacedImages.Load(unit.ID); ok {
@@ -137,6 +306,7 @@ func (d *DisplayManager) Convert(unit DisplayUnit) (KittyDisplayUnit, error) {
 		} else {
 			i.lastUsed = time.Now()
 			globalPlacedImages.Swap(unit.ID, i)
+			metrics.ImageCacheHits.With("session").Inc()
 
 			return KittyDisplayUnit{
 				// don't resend placement command
@@ -159,11 +329,18 @@ func (d *DisplayManager) Convert(unit DisplayUnit) (KittyDisplayUnit, error) {
 
 		//log.Logger.Info().Str("id", unit.ID).Int32("placement-id", cachedDecoded.ID).Msg("load image from storage cache")
 
-		globalPlacedImages.Store(unit.ID, cachedDecoded)
-		return KittyDisplayUnit{
-			PrepareCommand:  cachedDecoded.PrepareCommand(),
-			ReplacementText: cachedDecoded.DisplayUnicodePlaceholder(),
-		}, nil
+		prepare, err := d.prepareCommand(cachedDecoded)
+		if err == nil {
+			globalPlacedImages.Store(unit.ID, cachedDecoded)
+			metrics.ImageCacheHits.With("disk").Inc()
+
+			return KittyDisplayUnit{
+				PrepareCommand:  prepare,
+				ReplacementText: cachedDecoded.DisplayUnicodePlaceholder(),
+			}, nil
+		}
+
+		log.Logger.Warn().Err(err).Str("id", unit.ID).Msg("failed to read cached image, will re-download")
 	}
 
 	// 3rd: image was not downloaded yet, download and convert and save
@@ -176,21 +353,31 @@ func (d *DisplayManager) Convert(unit DisplayUnit) (KittyDisplayUnit, error) {
 
 	defer imageBody.Close()
 
+	metrics.ImageCacheMisses.Inc()
+
+	start := time.Now()
 	decoded, err := d.convertImageBytes(imageBody, unit, contentType)
 	if err != nil {
 		log.Logger.Err(err).Any("unit", unit).Send()
 		return KittyDisplayUnit{}, err
 	}
+	metrics.ImageEncode.Since(start)
+
+	decoded.ID = incrementID      // set id
+	decoded.lastUsed = time.Now() // last used for clean up
+
+	prepare, err := d.prepareCommand(decoded)
+	if err != nil {
+		return KittyDisplayUnit{}, err
+	}
 
-	decoded.ID = incrementID                                   // set id
-	decoded.lastUsed = time.Now()                              // last used for clean up
 	globalPlacedImages.Store(unit.ID, decoded)                 // store placement
 	if err := d.cacheDecodedImage(decoded, unit); err != nil { // cache decoded image
 		log.Logger.Warn().Err(err).Str("id", unit.ID).Msg("failed to cache decoded image")
 	}
 
 	return KittyDisplayUnit{
-		PrepareCommand:  decoded.PrepareCommand(),
+		PrepareCommand:  prepare,
 		ReplacementText: decoded.DisplayUnicodePlaceholder(),
 	}, nil
 }
@@ -211,11 +398,11 @@ func (d *DisplayManager) CleanupOldImagesCommand(maxAge time.Duration) string {
 		return true
 	})
 
-	return cmd.String()
+	return d.command(cmd.String())
 }
 
 func (d *DisplayManager) CleanupAllImagesCommand() string {
-	return "\x1b_Ga=D\x1b\\"
+	return d.command("\x1b_Ga=D\x1b\\")
 }
 
 func (d *DisplayManager) convertImageBytes(r io.Reader, unit DisplayUnit, contentType string) (DecodedImage, error) {
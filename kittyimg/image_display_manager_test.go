@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -273,3 +275,108 @@ func TestIntToRGB(t *testing.T) {
 		})
 	}
 }
+
+func TestDisplayManager_SetPassthrough(t *testing.T) {
+	dm := NewDisplayManager(afero.NewMemMapFs(), 10, 10)
+	require.Equal(t, "\x1b_Ga=D\x1b\\", dm.CleanupAllImagesCommand())
+
+	dm.SetPassthrough(func(cmd string) string { return "<" + cmd + ">" })
+	require.Equal(t, "<\x1b_Ga=D\x1b\\>", dm.CleanupAllImagesCommand())
+	require.Empty(t, dm.CleanupOldImagesCommand(time.Hour), "nothing to send is not wrapped")
+}
+
+func TestDecodedImage_DirectPrepareCommand(t *testing.T) {
+	t.Parallel()
+
+	static := DecodedImage{ID: 7, Cols: 2, Images: []DecodedImageFrame{{Width: 2, Height: 1}}}
+	require.Equal(t,
+		"\x1b_Gf=32,i=7,q=2,s=2,v=1,o=z,m=1;YWJj\x1b\\"+
+			"\x1b_Gq=2,m=0;ZGVm\x1b\\"+
+			"\x1b_Ga=p,i=7,p=7,q=2,U=1,r=1,c=2\x1b\\",
+		static.DirectPrepareCommand([][]byte{[]byte("abcdef")}, 4),
+	)
+
+	animated := DecodedImage{ID: 8, Cols: 1, Images: []DecodedImageFrame{{Width: 1, Height: 1, DelayInMS: 50}, {Width: 1, Height: 1, DelayInMS: 60}}}
+	require.Equal(t,
+		"\x1b_Gf=32,i=8,q=2,s=1,v=1,o=z,m=0;YWJj\x1b\\"+
+			"\x1b_Ga=a,i=8,r=1,z=50,q=2;\x1b\\"+
+			"\x1b_Ga=f,i=8,f=32,s=1,v=1,z=60,q=2,o=z,m=1;ZGVm\x1b\\"+
+			"\x1b_Ga=f,q=2,m=0;Zw==\x1b\\"+
+			"\x1b_Ga=a,i=8,s=3,v=1,q=2;\x1b\\"+
+			"\x1b_Ga=p,i=8,p=8,q=2,U=1,r=1,c=1\x1b\\",
+		animated.DirectPrepareCommand([][]byte{[]byte("abc"), []byte("defg")}, 4),
+	)
+}
+
+func TestDisplayManager_SetDirectTransmission(t *testing.T) {
+	// Reset global state for this test
+	globalImagePlacementIDCounter.Store(0)
+	globalPlacedImages = &syncmap.Map{}
+
+	fs := afero.NewMemMapFs()
+	dm := NewDisplayManager(fs, 400, 400)
+	dm.SetDirectTransmission(1026)
+
+	emoteData, err := os.ReadFile("../emote/testdata/pepeLaugh.webp")
+	require.NoError(t, err)
+
+	unit := DisplayUnit{
+		ID:        "ssh-emote",
+		Directory: "emote",
+		Load: func() (io.ReadCloser, string, error) {
+			return io.NopCloser(bytes.NewReader(emoteData)), "image/webp", nil
+		},
+	}
+
+	result, err := dm.Convert(unit)
+	require.NoError(t, err)
+	require.Contains(t, result.PrepareCommand, "\x1b_Gf=32,i=1,q=2")
+	require.NotContains(t, result.PrepareCommand, "t=f", "the terminal can't read the cached file")
+	require.Contains(t, result.PrepareCommand, "\x1b_Gq=2,m=1;", "the data is sent in chunks")
+
+	for seq := range strings.SplitAfterSeq(result.PrepareCommand, "\x1b\\") {
+		_, payload, _ := strings.Cut(seq, ";")
+		require.LessOrEqual(t, len(payload)-len("\x1b\\"), 1024, "chunks are rounded down to a multiple of 4")
+	}
+
+	// the cached frames are read again when the image is loaded from disk
+	globalPlacedImages = &syncmap.Map{}
+
+	cached, err := dm.Convert(unit)
+	require.NoError(t, err)
+	require.Equal(t, strings.Count(result.PrepareCommand, "\x1b\\"), strings.Count(cached.PrepareCommand, "\x1b\\"))
+}
+
+func TestDisplayManager_SetReducedMotion(t *testing.T) {
+	// Reset global state for this test
+	globalImagePlacementIDCounter.Store(0)
+	globalPlacedImages = &syncmap.Map{}
+
+	globalPlacedImages.Store("static", DecodedImage{ID: 10, Images: []DecodedImageFrame{{Width: 1, Height: 1}}})
+	globalPlacedImages.Store("animated", DecodedImage{ID: 11, Images: []DecodedImageFrame{{Width: 1, Height: 1}, {Width: 1, Height: 1}}})
+
+	dm := NewDisplayManager(afero.NewMemMapFs(), 400, 400)
+	require.Empty(t, dm.SetReducedMotion(false), "animations play by default")
+
+	require.Equal(t, "\x1b_Ga=a,i=11,c=1,s=1,q=2;\x1b\\", dm.SetReducedMotion(true))
+	require.Empty(t, dm.SetReducedMotion(true))
+
+	emoteData, err := os.ReadFile("../emote/testdata/animated.webp")
+	require.NoError(t, err)
+
+	result, err := dm.Convert(DisplayUnit{
+		ID:         "animated-emote",
+		Directory:  "emote",
+		IsAnimated: true,
+		Load: func() (io.ReadCloser, string, error) {
+			return io.NopCloser(bytes.NewReader(emoteData)), "image/webp", nil
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(result.PrepareCommand, "\x1b_Ga=a,i=1,c=1,s=1,q=2;\x1b\\"), "new images are stopped right away")
+
+	start := dm.SetReducedMotion(false)
+	require.Contains(t, start, "\x1b_Ga=a,i=1,s=3,v=1,q=2;\x1b\\")
+	require.Contains(t, start, "\x1b_Ga=a,i=11,s=3,v=1,q=2;\x1b\\")
+	require.NotContains(t, start, "i=10,", "static images have no animation")
+}
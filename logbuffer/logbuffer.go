@@ -0,0 +1,125 @@
+// Package logbuffer keeps the most recent log events in memory, so they can be viewed inside Chatuino
+// and added to support bundles, even if logging to a file is disabled.
+package logbuffer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultSize is the number of log events kept by Chatuino
+const DefaultSize = 2000
+
+// Entry is a single log event
+type Entry struct {
+	Time    time.Time
+	Level   zerolog.Level
+	Message string
+	Fields  string // remaining fields as key=value pairs, sorted by key
+	Raw     string // event as written by zerolog, without trailing newline
+}
+
+// Ring is an io.Writer for zerolog, which keeps the last written events. It is safe for concurrent use.
+type Ring struct {
+	m       sync.Mutex
+	entries []Entry
+	next    int    // index the next entry is written to
+	written uint64 // number of entries ever written
+}
+
+func New(size int) *Ring {
+	return &Ring{entries: make([]Entry, 0, size)}
+}
+
+// Write adds one event, zerolog calls Write once per event
+func (r *Ring) Write(p []byte) (int, error) {
+	entry := parseEntry(strings.TrimRight(string(p), "\n"))
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if len(r.entries) < cap(r.entries) {
+		r.entries = append(r.entries, entry)
+	} else if cap(r.entries) > 0 {
+		r.entries[r.next] = entry
+	}
+
+	if cap(r.entries) > 0 {
+		r.next = (r.next + 1) % cap(r.entries)
+	}
+
+	r.written++
+
+	return len(p), nil
+}
+
+// Entries returns the kept events, oldest first
+func (r *Ring) Entries() []Entry {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if len(r.entries) < cap(r.entries) {
+		return slices.Clone(r.entries)
+	}
+
+	return slices.Concat(r.entries[r.next:], r.entries[:r.next])
+}
+
+// Written returns the number of events written so far, which changes whenever a new event is kept
+func (r *Ring) Written() uint64 {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.written
+}
+
+// parseEntry extracts the common fields of a JSON event, events which can't be parsed are kept as message
+func parseEntry(raw string) Entry {
+	entry := Entry{Time: time.Now(), Level: zerolog.NoLevel, Raw: raw}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		entry.Message = raw
+		return entry
+	}
+
+	if v, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if t, err := time.Parse(zerolog.TimeFieldFormat, v); err == nil {
+			entry.Time = t
+		}
+	}
+
+	if v, ok := fields[zerolog.LevelFieldName].(string); ok {
+		if level, err := zerolog.ParseLevel(v); err == nil {
+			entry.Level = level
+		}
+	}
+
+	entry.Message, _ = fields[zerolog.MessageFieldName].(string)
+
+	delete(fields, zerolog.TimestampFieldName)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.MessageFieldName)
+
+	pairs := make([]string, 0, len(fields))
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		value, ok := fields[key].(string)
+		if !ok {
+			b, _ := json.Marshal(fields[key])
+			value = string(b)
+		}
+
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	entry.Fields = strings.Join(pairs, " ")
+
+	return entry
+}
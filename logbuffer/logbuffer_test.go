@@ -0,0 +1,65 @@
+package logbuffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	t.Parallel()
+
+	r := New(3)
+	logger := zerolog.New(r)
+
+	require.Empty(t, r.Entries())
+
+	for i := range 5 {
+		logger.Info().Int("i", i).Msg("event")
+	}
+
+	entries := r.Entries()
+	require.Len(t, entries, 3)
+	require.Equal(t, uint64(5), r.Written())
+
+	for i, e := range entries {
+		require.Equal(t, "event", e.Message)
+		require.Equal(t, zerolog.InfoLevel, e.Level)
+		require.Equal(t, []string{"i=2", "i=3", "i=4"}[i], e.Fields)
+	}
+
+	// entries are copies, writing doesn't change them
+	logger.Warn().Msg("new")
+	require.Equal(t, "i=2", entries[0].Fields)
+	require.Equal(t, "new", r.Entries()[2].Message)
+}
+
+func TestRing_ZeroSize(t *testing.T) {
+	t.Parallel()
+
+	r := New(0)
+	_, err := r.Write([]byte(`{"message":"dropped"}`))
+	require.NoError(t, err)
+	require.Empty(t, r.Entries())
+	require.Equal(t, uint64(1), r.Written())
+}
+
+func Test_parseEntry(t *testing.T) {
+	t.Parallel()
+
+	e := parseEntry(`{"level":"error","component":"ipc","error":"broken pipe","refs":2,"ok":true,"time":"2026-01-02T15:04:05Z","message":"failed"}`)
+	require.Equal(t, Entry{
+		Time:    time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Level:   zerolog.ErrorLevel,
+		Message: "failed",
+		Fields:  "component=ipc error=broken pipe ok=true refs=2",
+		Raw:     `{"level":"error","component":"ipc","error":"broken pipe","refs":2,"ok":true,"time":"2026-01-02T15:04:05Z","message":"failed"}`,
+	}, e)
+
+	// lines which are not JSON are kept as message
+	e = parseEntry("plain text")
+	require.Equal(t, "plain text", e.Message)
+	require.Equal(t, zerolog.NoLevel, e.Level)
+}
@@ -0,0 +1,20 @@
+package logbuffer
+
+import "regexp"
+
+// redactedValue replaces secrets in redacted text, it is the same marker used by the settings summary
+const redactedValue = "<redacted>"
+
+var (
+	// secretQueryPattern matches the values of query parameters carrying API keys or tokens
+	secretQueryPattern = regexp.MustCompile(`(?i)([?&](?:key|api_key|access_token|refresh_token|client_secret|token|password)=)[^&\s"']+`)
+	// oauthTokenPattern matches Twitch chat tokens and bearer tokens of authorization headers
+	oauthTokenPattern = regexp.MustCompile(`(?i)\b(oauth:|bearer\s+)[a-z0-9._~+/=-]+`)
+)
+
+// Redact masks API keys and tokens in log text, like the key= and access_token= values of logged URLs.
+// Log events are redacted before they leave Chatuino, for example in support bundles and panic reports.
+func Redact(text string) string {
+	text = secretQueryPattern.ReplaceAllString(text, "${1}"+redactedValue)
+	return oauthTokenPattern.ReplaceAllString(text, "${1}"+redactedValue)
+}
@@ -0,0 +1,48 @@
+package logbuffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"api-key": {
+			in:   `{"url":"https://www.googleapis.com/youtube/v3/videos?id=abc&key=AIzaSecret&part=snippet"}`,
+			want: `{"url":"https://www.googleapis.com/youtube/v3/videos?id=abc&key=<redacted>&part=snippet"}`,
+		},
+		"access-token-first": {
+			in:   "GET https://example.com/auth?access_token=abc123&refresh_token=def456",
+			want: "GET https://example.com/auth?access_token=<redacted>&refresh_token=<redacted>",
+		},
+		"oauth": {
+			in:   "PASS oauth:abcdef123456",
+			want: "PASS oauth:<redacted>",
+		},
+		"bearer": {
+			in:   "Authorization: Bearer abc.def-123",
+			want: "Authorization: Bearer <redacted>",
+		},
+		"unrelated-query": {
+			in:   "https://example.com/search?q=monkey&page=2",
+			want: "https://example.com/search?q=monkey&page=2",
+		},
+		"no-secrets": {
+			in:   `{"level":"info","message":"joined channel"}`,
+			want: `{"level":"info","message":"joined channel"}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Redact(tt.in))
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,29 +15,50 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/adrg/xdg"
 	"github.com/julez-dev/chatuino/badge"
+	"github.com/julez-dev/chatuino/blocklist"
+	"github.com/julez-dev/chatuino/botlist"
+	"github.com/julez-dev/chatuino/cosmetic"
 	"github.com/julez-dev/chatuino/httputil"
+	"github.com/julez-dev/chatuino/imageupload"
+	"github.com/julez-dev/chatuino/ipc"
 	"github.com/julez-dev/chatuino/kittyimg"
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/julez-dev/chatuino/metrics"
+	"github.com/julez-dev/chatuino/obs"
+	"github.com/julez-dev/chatuino/profanity"
+	"github.com/julez-dev/chatuino/remotesync"
 	"github.com/julez-dev/chatuino/save/messagelog"
+	"github.com/julez-dev/chatuino/script"
+	"github.com/julez-dev/chatuino/tmux"
 	"github.com/julez-dev/chatuino/twitch/bttv"
 	"github.com/julez-dev/chatuino/twitch/ffz"
 	"github.com/julez-dev/chatuino/twitch/recentmessage"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
 	"github.com/julez-dev/chatuino/wspool"
+	"github.com/muesli/termenv"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/afero"
 	"github.com/zalando/go-keyring"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/cli/browser"
 
 	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/hook"
+	"github.com/julez-dev/chatuino/kick"
 	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/selfupdate"
 	"github.com/julez-dev/chatuino/server"
+	"github.com/julez-dev/chatuino/shortlink"
+	"github.com/julez-dev/chatuino/sound"
+	"github.com/julez-dev/chatuino/spellcheck"
+	"github.com/julez-dev/chatuino/translate"
 	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/julez-dev/chatuino/ui/mainui"
+	"github.com/julez-dev/chatuino/youtube"
 	_ "github.com/mailru/easyjson"
 	"github.com/rs/zerolog"
 	"github.com/urfave/cli/v3"
@@ -51,14 +74,14 @@ const (
 	defaultClientID = "jliqj1q6nmp0uh5ofangdx4iac7yd9"
 )
 
-var (
-	dataDir     = xdg.DataHome + "/chatuino"
-	logFileName = dataDir + "/chatuino.log"
-	dbFileName  = dataDir + "/chatuino.db"
-)
+// appPaths are the directories used by Chatuino, resolved from the flags before any command runs
+var appPaths save.Paths
 
 var maybeLogFile *os.File
 
+// logRing keeps the recent log events for the debug log and the logs command of the control socket, even if logging is disabled
+var logRing = logbuffer.New(logbuffer.DefaultSize)
+
 //go:generate go run github.com/mailru/easyjson/easyjson@latest -snake_case -no_std_marshalers -pkg ./kittyimg
 //go:generate go run github.com/mailru/easyjson/easyjson@latest -snake_case -no_std_marshalers -pkg ./twitch/twitchirc
 //go:generate go run github.com/mailru/easyjson/easyjson@latest -snake_case -no_std_marshalers -pkg ./emote
@@ -66,12 +89,7 @@ var maybeLogFile *os.File
 
 //go:generate go run github.com/vektra/mockery/v3@v3.6.3
 func main() {
-	defer func() {
-		if maybeLogFile != nil {
-			_ = maybeLogFile.Sync()
-			_ = maybeLogFile.Close()
-		}
-	}()
+	defer closeLogFile()
 
 	app := &cli.Command{
 		Name:        "Chatuino",
@@ -90,8 +108,28 @@ func main() {
 			serverCMD,
 			cacheCMD,
 			contributorsCMD,
+			configCMD,
+			pathsCMD,
+			botCMD,
+			ctlCMD,
+			debugCMD,
+			exportCMD,
+			replayCMD,
+			vodCMD,
+			updateCMD,
+			syncCMD,
 		},
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "Directory containing the settings, theme and keymap files",
+				Sources: cli.EnvVars(save.ConfigDirEnv),
+			},
+			&cli.StringFlag{
+				Name:    "data-dir",
+				Usage:   "Directory for the image cache, chat log database, log file and session state",
+				Sources: cli.EnvVars(save.DataDirEnv),
+			},
 			&cli.StringFlag{
 				Name:    "client-id",
 				Usage:   "OAuth Client-ID",
@@ -114,6 +152,15 @@ func main() {
 				Usage: "Host of the profiling http server",
 				Value: "0.0.0.0:6060",
 			},
+			&cli.BoolFlag{
+				Name:  "enable-metrics",
+				Usage: "If the metrics should be served in the Prometheus text format on /metrics",
+			},
+			&cli.StringFlag{
+				Name:  "metrics-host",
+				Usage: "Host of the metrics http server",
+				Value: "127.0.0.1:6061",
+			},
 			&cli.BoolFlag{
 				Name:  "log",
 				Usage: "If the application should log",
@@ -126,6 +173,15 @@ func main() {
 				Name:  "human-readable",
 				Usage: "If the log should be human readable",
 			},
+			&cli.BoolFlag{
+				Name:  "no-restore",
+				Usage: "If the tabs of the previous session should not be restored on startup",
+			},
+			&cli.BoolFlag{
+				Name:    "offline",
+				Usage:   "If Chatuino should start without connecting to any service, using the emotes, badges and API responses cached on disk. E.g. for development or replays.",
+				Sources: cli.EnvVars("CHATUINO_OFFLINE"),
+			},
 			&cli.BoolFlag{
 				Name:    "plain-auth-storage",
 				Usage:   "If your twitch authentication tokens should be stored in plain text. E.g. when no keyring is available on your system.",
@@ -135,217 +191,571 @@ func main() {
 		},
 		Before: beforeAction,
 		Action: func(ctx context.Context, command *cli.Command) error {
-			if command.Bool("enable-profiling") {
-				runProfilingServer(ctx, log.Logger, command.String("profiling-host"))
-			}
+			return runChatuino(ctx, command, nil)
+		},
+	}
 
-			settings, err := save.SettingsFromDisk()
-			if err != nil {
-				return fmt.Errorf("failed to read settings file: %w", err)
-			}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-			theme, err := save.ThemeFromDisk()
-			if err != nil {
-				return fmt.Errorf("failed to read theme file: %w", err)
-			}
+	if err := app.Run(ctx, os.Args); err != nil {
+		fmt.Printf("failed to run Chatuino: %v\n", err)
+		closeLogFile() // os.Exit skips the deferred close
+		os.Exit(1)
+	}
+}
 
-			keymap, err := save.CreateReadKeyMap()
-			if err != nil {
-				return fmt.Errorf("failed to read keymap file: %w", err)
+// runChatuino runs the UI until it is quit. A replay opens only the replay tab and neither restores nor saves the session.
+func runChatuino(ctx context.Context, command *cli.Command, replay *mainui.Replay) error {
+	if command.Bool("enable-profiling") {
+		runProfilingServer(ctx, log.Logger, command.String("profiling-host"))
+	}
+
+	if command.Bool("enable-metrics") {
+		runMetricsServer(ctx, log.Logger, command.String("metrics-host"))
+	}
+
+	config, err := save.ConfigFromDisk()
+	if err != nil {
+		return fmt.Errorf("%w\nrun \"chatuino config validate\" for details", err)
+	}
+
+	settings, themes, keymap := config.Settings, config.Themes, config.Keymap
+
+	// offline every request fails right away, the caches on disk answer instead
+	offline := command.Bool("offline")
+	if offline {
+		log.Logger.Info().Msg("running offline")
+	}
+
+	httpClient, err := newHTTPClient(settings.Proxy, offline)
+	if err != nil {
+		return err
+	}
+
+	// pulled changes apply to this start, so the config is read again and the client rebuilt for changed proxy settings
+	if settings.Sync.Auto && !offline && replay == nil {
+		if result, err := autoSync(ctx, httpClient, settings.Sync); err == nil && len(result.Pulled) > 0 {
+			if config, err = save.ConfigFromDisk(); err != nil {
+				return fmt.Errorf("%w\nrun \"chatuino config validate\" for details", err)
 			}
 
-			var keyringBackend keyring.Keyring
+			settings, themes, keymap = config.Settings, config.Themes, config.Keymap
 
-			if command.Bool("plain-auth-storage") {
-				keyringBackend = save.NewPlainKeyringFallback(afero.NewOsFs())
-			} else {
-				keyringBackend = save.NewKeyringWrapper()
+			if httpClient, err = newHTTPClient(settings.Proxy, offline); err != nil {
+				return err
 			}
+		}
+	}
 
-			accountProvider := save.NewAccountProvider(keyringBackend)
-			serverAPI := server.NewClient(command.String("api-host"), http.DefaultClient)
-			stvAPI := seventv.NewAPI(http.DefaultClient)
-			bttvAPI := bttv.NewAPI(http.DefaultClient)
-			ffzAPI := ffz.NewAPI(http.DefaultClient)
-			recentMessageService := recentmessage.NewAPI(http.DefaultClient)
-			pool := wspool.NewPool(accountProvider, log.Logger)
-			emoteCache := emote.NewCache(log.Logger, serverAPI, stvAPI, bttvAPI, ffzAPI)
-			badgeCache := badge.NewCache(serverAPI)
-			appStateManager := save.NewAppStateManager(afero.NewOsFs())
-
-			// message logger setup
-			db, err := openDB(false)
-			if err != nil {
-				return fmt.Errorf("failed to open sqlite db: %w", err)
+	if report, crashed := takeCrashMarker(); crashed {
+		log.Logger.Warn().Str("report", report).Msg("previous session crashed")
+
+		if settings.Session.RestoreAfterCrash {
+			settings.Session.RestoreTabs = true
+		}
+	}
+
+	if command.Bool("no-restore") {
+		settings.Session.RestoreTabs = false
+	}
+
+	// over SSH the terminal is on another machine, graphics may be disabled and the terminal takes longer to answer queries
+	sshSession := mainui.IsSSHSession(os.Getenv)
+	if sshSession {
+		log.Logger.Info().Msg("running in an SSH session")
+	}
+
+	settings = renderingProfile(settings, sshSession)
+
+	theme := themes.ActiveTheme()
+
+	configWatcher, err := save.NewConfigWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config files: %w", err)
+	}
+
+	configSource := profileConfigSource{ConfigSource: configWatcher, sshSession: sshSession}
+
+	var keyringBackend keyring.Keyring
+
+	if command.Bool("plain-auth-storage") {
+		keyringBackend = save.NewPlainKeyringFallback(afero.NewOsFs())
+	} else {
+		keyringBackend = save.NewKeyringWrapper()
+	}
+
+	accountProvider := save.NewAccountProvider(keyringBackend)
+
+	// responses of read-only Twitch endpoints are shared by all clients and instances
+	apiCache := &httputil.CacheTransport{
+		Transport: httpClient.Transport,
+		FS:        afero.NewOsFs(),
+		Dir:       appPaths.APICacheDir(),
+		Rules: []httputil.CacheRule{
+			{PathSuffix: "/users", TTL: settings.APICache.Users, RequireQuery: true},
+			{PathSuffix: "/chat/badges/global", TTL: settings.APICache.Badges},
+			{PathSuffix: "/chat/badges", TTL: settings.APICache.Badges},
+		},
+	}
+
+	serverAPI := server.NewClient(command.String("api-host"), &http.Client{Transport: apiCache})
+	stvAPI := seventv.NewAPI(httpClient)
+	bttvAPI := bttv.NewAPI(httpClient)
+	ffzAPI := ffz.NewAPI(httpClient)
+	recentMessageService := recentmessage.NewAPI(httpClient)
+	pool := wspool.NewPool(accountProvider, log.Logger)
+	pool.SetHTTPClient(httpClient)
+	ircTraffic := twitchirc.NewTraffic(twitchirc.DefaultTrafficSize)
+	pool.SetTraffic(ircTraffic)
+	pool.SetMembership(settings.Presence.Enabled)
+	emoteCache := emote.NewCache(log.Logger, serverAPI, stvAPI, bttvAPI, ffzAPI, emote.WithDiskCache(afero.NewOsFs(), appPaths.EmoteSetDir()))
+	badgeCache := badge.NewCache(serverAPI, badge.WithDiskCache(afero.NewOsFs(), appPaths.BadgeSetDir()))
+	appStateManager := save.NewAppStateManager(afero.NewOsFs())
+
+	// message logger setup
+	db, err := openDB(false)
+	if err != nil {
+		return fmt.Errorf("failed to open sqlite db: %w", err)
+	}
+
+	roDB, err := openDB(true)
+	if err != nil {
+		return fmt.Errorf("failed to open readonly sqlite db: %w", err)
+	}
+
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Logger.Err(err).Msg("failed to close db connection")
+		}
+
+		if err := roDB.Close(); err != nil {
+			log.Logger.Err(err).Msg("failed to close db connection")
+		}
+	}()
+
+	messageLogger := messagelog.NewBatchedMessageLogger(log.Logger, db, roDB, settings.Moderation.LogsChannelInclude, settings.Moderation.LogsChannelExclude)
+	messageLoggerChan := make(chan *twitchirc.PrivateMessage)
+	loggerWaitSync := make(chan struct{})
+
+	if err := messageLogger.PrepareDatabase(); err != nil {
+		log.Logger.Err(err).Msg("failed to run prepare queries")
+		return fmt.Errorf("failed to migrate db: %w", err)
+	}
+
+	// the display manager is created once graphics are enabled, either on startup or when the settings are reloaded
+	var displayManager *kittyimg.DisplayManager
+
+	guard := newTerminalGuard(os.Stdout, os.Stdin.Fd(), settings, func() string {
+		if displayManager == nil {
+			return ""
+		}
+
+		return displayManager.CleanupAllImagesCommand()
+	})
+	defer guard.handlePanic()
+
+	go func() {
+		defer guard.handlePanic()
+		runChatLogger(messageLogger, messageLoggerChan, loggerWaitSync, settings.Moderation.StoreChatLogs)
+	}()
+
+	// If the user has provided an account we can use the users local authentication
+	// Instead of using Chatuino's server to handle requests for emote/badge fetching.
+	// Offline the tokens can't be validated or refreshed, so all accounts use Chatuino's server like anonymous ones.
+	clients := make(map[string]mainui.APIClient)
+	if mainAccount, err := accountProvider.GetMainAccount(); err == nil && !offline {
+		ttvAPI, err := twitchapi.NewAPI(command.String("client-id"), twitchapi.WithUserAuthentication(accountProvider, serverAPI, mainAccount.ID), twitchapi.WithTransport(apiCache))
+		if err == nil {
+			clients[mainAccount.ID] = ttvAPI
+			emoteCache = emote.NewCache(log.Logger, ttvAPI, stvAPI, bttvAPI, ffzAPI, emote.WithDiskCache(afero.NewOsFs(), appPaths.EmoteSetDir()))
+			badgeCache = badge.NewCache(ttvAPI, badge.WithDiskCache(afero.NewOsFs(), appPaths.BadgeSetDir()))
+		}
+	}
+
+	// nil outside of tmux
+	tmuxClient, _ := tmux.FromEnv(os.Getenv)
+
+	buildReplacers := func(settings save.Settings, theme save.Theme) (mainui.Replacers, error) {
+		replacers := mainui.Replacers{
+			Emote: emote.NewReplacer(httpClient, emoteCache, false, theme, nil),
+			Badge: badge.NewReplacer(httpClient, badgeCache, false, theme, settings.Chat.Badges, nil),
+		}
+
+		if !settings.Chat.GraphicEmotes && !settings.Chat.GraphicBadges {
+			return replacers, nil
+		}
+
+		if displayManager == nil {
+			if !hasImageSupport() {
+				return mainui.Replacers{}, fmt.Errorf("graphical image support enabled but not available for this platform (unix & kitty terminal only)")
 			}
 
-			roDB, err := openDB(true)
+			cellWidth, cellHeight, err := getTermCellWidthHeight()
 			if err != nil {
-				return fmt.Errorf("failed to open readonly sqlite db: %w", err)
+				return mainui.Replacers{}, fmt.Errorf("failed to get terminal size: %w", err)
 			}
 
-			defer func() {
-				if err := db.Close(); err != nil {
-					log.Logger.Err(err).Msg("failed to close db connection")
-				}
+			manager := kittyimg.NewDisplayManager(afero.NewOsFs(), cellWidth, cellHeight)
 
-				if err := roDB.Close(); err != nil {
-					log.Logger.Err(err).Msg("failed to close db connection")
+			if tmuxClient != nil {
+				passthrough, err := tmuxClient.Passthrough()
+				if err != nil {
+					return mainui.Replacers{}, fmt.Errorf("failed to check tmux passthrough: %w", err)
 				}
-			}()
 
-			messageLogger := messagelog.NewBatchedMessageLogger(log.Logger, db, roDB, settings.Moderation.LogsChannelInclude, settings.Moderation.LogsChannelExclude)
-			messageLoggerChan := make(chan *twitchirc.PrivateMessage)
-			loggerWaitSync := make(chan struct{})
+				if !passthrough {
+					return mainui.Replacers{}, tmux.ErrPassthroughOff
+				}
 
-			if err := messageLogger.PrepareDatabase(); err != nil {
-				log.Logger.Err(err).Msg("failed to run prepare queries")
-				return fmt.Errorf("failed to migrate db: %w", err)
+				manager.SetPassthrough(tmux.Wrap)
 			}
 
-			go runChatLogger(messageLogger, messageLoggerChan, loggerWaitSync, settings.Moderation.StoreChatLogs)
-
-			// If the user has provided an account we can use the users local authentication
-			// Instead of using Chatuino's server to handle requests for emote/badge fetching.
-			clients := make(map[string]mainui.APIClient)
-			if mainAccount, err := accountProvider.GetMainAccount(); err == nil {
-				ttvAPI, err := twitchapi.NewAPI(command.String("client-id"), twitchapi.WithUserAuthentication(accountProvider, serverAPI, mainAccount.ID))
-				if err == nil {
-					clients[mainAccount.ID] = ttvAPI
-					emoteCache = emote.NewCache(log.Logger, ttvAPI, stvAPI, bttvAPI, ffzAPI)
-					badgeCache = badge.NewCache(ttvAPI)
-				}
+			// the terminal can't read the cached images of the remote machine
+			if sshSession {
+				manager.SetDirectTransmission(settings.SSH.ImageChunkSize)
 			}
 
-			var (
-				emoteReplacer  = emote.NewReplacer(http.DefaultClient, emoteCache, false, theme, nil)
-				badgeReplacer  = badge.NewReplacer(http.DefaultClient, badgeCache, false, theme, nil)
-				displayManager *kittyimg.DisplayManager
-			)
+			// no images are placed yet, so there is no command to send
+			manager.SetReducedMotion(settings.Accessibility.ReducedMotion)
 
-			if settings.Chat.GraphicEmotes || settings.Chat.GraphicBadges {
-				if !hasImageSupport() {
-					return fmt.Errorf("graphical image support enabled but not available for this platform (unix & kitty terminal only)")
-				}
+			displayManager = manager
+		}
 
-				cellWidth, cellHeight, err := getTermCellWidthHeight()
-				if err != nil {
-					return fmt.Errorf("failed to get terminal size: %w", err)
-				}
+		replacers.DisplayManager = displayManager
 
-				displayManager = kittyimg.NewDisplayManager(afero.NewOsFs(), cellWidth, cellHeight)
+		if settings.Chat.GraphicEmotes {
+			replacers.Emote = emote.NewReplacer(httpClient, emoteCache, true, theme, displayManager)
+		}
 
-				if settings.Chat.GraphicEmotes {
-					emoteReplacer = emote.NewReplacer(http.DefaultClient, emoteCache, true, theme, displayManager)
-				}
+		if settings.Chat.GraphicBadges {
+			replacers.Badge = badge.NewReplacer(httpClient, badgeCache, true, theme, settings.Chat.Badges, displayManager)
+		}
 
-				if settings.Chat.GraphicBadges {
-					badgeReplacer = badge.NewReplacer(http.DefaultClient, badgeCache, true, theme, displayManager)
-				}
+		return replacers, nil
+	}
 
-				defer func() {
-					io.WriteString(os.Stdout, displayManager.CleanupAllImagesCommand())
-				}()
-			}
+	// without passthrough tmux swallows the images, Chatuino still starts with text emotes and badges
+	var warnings []string
 
-			deps := &mainui.DependencyContainer{
-				UserConfig: mainui.UserConfiguration{
-					Settings: settings,
-					Theme:    theme,
-				},
-				AppStateManager:      appStateManager,
-				Keymap:               keymap,
-				ServerAPI:            serverAPI,
-				AccountProvider:      accountProvider,
-				EmoteCache:           emoteCache,
-				BadgeCache:           badgeCache,
-				EmoteReplacer:        emoteReplacer,
-				BadgeReplacer:        badgeReplacer,
-				ImageDisplayManager:  displayManager,
-				RecentMessageService: recentMessageService,
-				MessageLogger:        messageLogger,
-				Pool:                 pool,
-				APIUserClients:       clients,
-			}
+	replacers, err := buildReplacers(settings, theme)
+	if errors.Is(err, tmux.ErrPassthroughOff) {
+		log.Logger.Warn().Err(err).Msg("disabled graphic emotes and badges")
+		warnings = append(warnings, "Graphic emotes and badges are disabled: "+err.Error())
+
+		settings.Chat.GraphicEmotes, settings.Chat.GraphicBadges = false, false
+		replacers, err = buildReplacers(settings, theme)
+	}
 
-			// Fetch all Accounts
-			accounts, err := accountProvider.GetAllAccounts()
+	if err != nil {
+		return err
+	}
+
+	defer guard.restore()
+
+	hooks, err := hook.NewRunner(log.Logger, settings.Hooks)
+	if err != nil {
+		return err
+	}
+
+	sounds, err := sound.New(log.Logger, settings.Sounds)
+	if err != nil {
+		return err
+	}
+
+	// failing scripts are skipped, so Chatuino still starts with broken scripts
+	scripts, err := script.Load(log.Logger, appPaths.ScriptDir())
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to load scripts")
+	}
+
+	// querying the terminal background may take a moment, so only do it if the result is used
+	darkBackground := true
+	if settings.Chat.UsernameMinContrast > 0 {
+		probeTimeout := defaultProbeTimeout
+		if sshSession {
+			probeTimeout = settings.SSH.ProbeTimeout
+		}
+
+		darkBackground = queryDarkBackground(probeTimeout)
+	}
+
+	deps := &mainui.DependencyContainer{
+		UserConfig: mainui.UserConfiguration{
+			Settings:       settings,
+			Theme:          theme,
+			Themes:         themes,
+			DarkBackground: darkBackground,
+			TrueColor:      lipgloss.ColorProfile() == termenv.TrueColor,
+		},
+		AppStateManager:      appStateManager,
+		Warnings:             warnings,
+		Keymap:               keymap,
+		ChannelKeymaps:       config.ChannelKeymaps,
+		ServerAPI:            serverAPI,
+		AccountProvider:      accountProvider,
+		EmoteCache:           emoteCache,
+		BadgeCache:           badgeCache,
+		EmoteReplacer:        replacers.Emote,
+		BadgeReplacer:        replacers.Badge,
+		ImageDisplayManager:  replacers.DisplayManager,
+		RecentMessageService: recentMessageService,
+		MessageLogger:        messageLogger,
+		Pool:                 pool,
+		APIUserClients:       clients,
+		ConfigSource:         configSource,
+		BuildReplacers:       buildReplacers,
+		Hooks:                hooks,
+		Sounds:               sounds,
+		Scripts:              scripts,
+		Logs:                 logRing,
+		Traffic:              ircTraffic,
+		Bots:                 botlist.NewCache(ffzAPI, bttvAPI),
+		Blocks:               blocklist.New(),
+		Profanity:            profanity.New(settings.Profanity.MaskedWords()),
+		Notes:                save.NewNoteStore(afero.NewOsFs()),
+		Commands:             save.NewCommandStore(afero.NewOsFs()),
+		Watchtime:            save.NewWatchtimeStore(afero.NewOsFs()),
+		OnPanic:              guard.recordPanic,
+		HTTPClient:           httpClient,
+	}
+
+	// Fetch all Accounts
+	accounts, err := accountProvider.GetAllAccounts()
+	if err != nil {
+		return fmt.Errorf("failed to open accounts: %w", err)
+	}
+
+	for _, acc := range accounts {
+		if _, ok := clients[acc.ID]; ok {
+			continue
+		}
+
+		var api mainui.APIClient
+
+		if !acc.IsAnonymous && !offline {
+			api, err = twitchapi.NewAPI(command.String("client-id"), twitchapi.WithUserAuthentication(accountProvider, serverAPI, acc.ID), twitchapi.WithTransport(apiCache))
 			if err != nil {
-				return fmt.Errorf("failed to open accounts: %w", err)
+				return fmt.Errorf("failed to build api client for %s: %w", acc.DisplayName, err)
 			}
+		} else {
+			api = serverAPI
+		}
 
-			for _, acc := range accounts {
-				if _, ok := clients[acc.ID]; ok {
-					continue
-				}
+		clients[acc.ID] = api
+	}
 
-				var api mainui.APIClient
+	deps.Accounts = accounts
 
-				if !acc.IsAnonymous {
-					api, err = twitchapi.NewAPI(command.String("client-id"), twitchapi.WithUserAuthentication(accountProvider, serverAPI, acc.ID))
-					if err != nil {
-						return fmt.Errorf("failed to build api client for %s: %w", acc.DisplayName, err)
-					}
-				} else {
-					api = serverAPI
-				}
+	if settings.Chat.SevenTVCosmetics {
+		deps.Cosmetics = cosmetic.NewCache(log.Logger, stvAPI, cosmetic.DefaultTTL)
+	}
 
-				clients[acc.ID] = api
-			}
+	var obsClient *obs.Client
+	if settings.OBS.Enabled && !offline {
+		obsClient = obs.New(log.Logger, obs.Config{
+			Host:     settings.OBS.Host,
+			Port:     settings.OBS.Port,
+			Password: settings.OBS.Password,
+		})
+		deps.OBS = obsClient
+	}
 
-			deps.Accounts = accounts
+	// dev builds have no version to compare releases with
+	if settings.UpdateCheck.Enabled && Version != "dev" && !offline {
+		deps.Updates = selfupdate.NewChecker(selfupdate.New(httpClient, Version), afero.NewOsFs(), appPaths.UpdateCheckFile(), Version)
+	}
 
-			p := tea.NewProgram(
-				mainui.NewUI(
-					messageLoggerChan,
-					deps,
-				),
-				tea.WithContext(ctx),
-				tea.WithAltScreen(),
-				tea.WithFPS(120),
-			)
+	if settings.Translation.Backend != "" {
+		translator, err := translate.New(httpClient, translate.Config{
+			Backend:        settings.Translation.Backend,
+			URL:            settings.Translation.URL,
+			APIKey:         settings.Translation.APIKey,
+			TargetLanguage: settings.Translation.TargetLanguage,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build translator: %w", err)
+		}
 
-			// Connect the pool to the Bubble Tea program
-			pool.SetSend(p.Send)
+		deps.Translator = translator
+	}
 
-			final, err := p.Run()
+	if settings.Links.Shortener != "" {
+		shortener, err := shortlink.New(httpClient, settings.Links.Shortener)
+		if err != nil {
+			return fmt.Errorf("failed to build link shortener: %w", err)
+		}
 
-			// Close pool after UI exits (before checking error)
-			if closeErr := pool.Close(); closeErr != nil {
-				log.Logger.Err(closeErr).Msg("failed to close connection pool")
-			}
+		deps.Shortener = shortener
+	}
 
+	if settings.ImageUpload.Host != "" {
+		uploader, err := imageupload.New(httpClient, settings.ImageUpload.Host, settings.ImageUpload.APIKey)
+		if err != nil {
+			return fmt.Errorf("failed to build image uploader: %w", err)
+		}
+
+		deps.ImageUploader = uploader
+	}
+
+	if settings.YouTube.APIKey != "" {
+		// a client of its own with the shared transport, so a stuck request doesn't block polling forever
+		yt, err := youtube.New(&http.Client{Transport: httpClient.Transport, Timeout: 30 * time.Second}, youtube.Config{
+			APIKey:       settings.YouTube.APIKey,
+			ClientID:     settings.YouTube.ClientID,
+			ClientSecret: settings.YouTube.ClientSecret,
+			RefreshToken: settings.YouTube.RefreshToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build YouTube client: %w", err)
+		}
+
+		deps.YouTube = yt
+	}
+
+	if settings.Kick.Enabled {
+		deps.Kick = kick.New(httpClient)
+	}
+
+	deps.Replay = replay
+	deps.Offline = offline
+
+	if settings.Spellcheck.Enabled {
+		dictionary := settings.Spellcheck.Dictionary
+		if dictionary == "" {
+			dictionary, err = spellcheck.FindDictionary(afero.NewOsFs(), spellcheck.DictionaryDirs(), cmp.Or(settings.Spellcheck.Language, spellcheck.DefaultLanguage))
 			if err != nil {
 				return err
 			}
+		}
 
-			if final, ok := final.(*mainui.Root); ok {
+		checker, err := spellcheck.Load(afero.NewOsFs(), dictionary, appPaths.DictionaryFile())
+		if err != nil {
+			return fmt.Errorf("failed to load spellcheck dictionary %s: %w", dictionary, err)
+		}
 
-				// persist open tabs on disk when session was actually loaded
-				// to prevent saving empty state when Chatuino was closed while loading
-				if final.HasSessionLoaded() {
-					state := final.TakeStateSnapshot()
+		deps.SpellChecker = checker
+	}
 
-					if err := appStateManager.SaveAppState(state); err != nil {
-						return fmt.Errorf("error while saving state: %w", err)
-					}
+	if tmuxClient != nil {
+		window, err := newTmuxWindow(tmuxClient)
+		if err != nil {
+			log.Logger.Err(err).Msg("failed to read tmux window name")
+		} else {
+			deps.TmuxWindow = window
+			defer func() {
+				if err := window.restore(); err != nil {
+					log.Logger.Err(err).Msg("failed to restore tmux window name")
 				}
-			}
+			}()
+		}
+	}
 
-			close(messageLoggerChan)
-			<-loggerWaitSync
+	// Root has pointer receivers, so ui is the final model even when a panic leaves Run without one
+	ui := mainui.NewUI(messageLoggerChan, deps)
 
-			return nil
-		},
+	programOptions := []tea.ProgramOption{
+		tea.WithContext(ctx),
+		tea.WithAltScreen(),
+		tea.WithFPS(settings.Chat.RenderFPS),
 	}
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
+	if settings.Mouse.Enabled {
+		programOptions = append(programOptions, tea.WithMouseCellMotion())
+	}
 
-	if err := app.Run(ctx, os.Args); err != nil {
-		fmt.Printf("failed to run Chatuino: %v\n", err)
-		os.Exit(1)
+	p := tea.NewProgram(ui, programOptions...)
+
+	// Connect the pool to the Bubble Tea program, chat messages are applied once per frame
+	pool.SetSend(wspool.NewBatcher(p.Send, time.Second/time.Duration(settings.Chat.RenderFPS)).Send)
+
+	// OBS may be started after Chatuino, the client keeps reconnecting until the context is canceled
+	if obsClient != nil {
+		obsClient.SetOnChange(mainui.OBSStateHandler(p.Send))
+		go func() {
+			defer guard.handlePanic()
+			obsClient.Run(ctx)
+		}()
+	}
+
+	// failing to create the control socket, e.g. because another instance uses it, only disables remote control
+	if settings.IPC.Enabled {
+		socket := cmp.Or(settings.IPC.Socket, appPaths.SocketFile())
+
+		ipcServer, err := ipc.Listen(log.Logger, socket, mainui.IPCHandler(p.Send, logRing))
+		if err != nil {
+			log.Logger.Err(err).Msg("failed to start control socket")
+		} else {
+			defer func() {
+				if err := ipcServer.Close(); err != nil {
+					log.Logger.Err(err).Msg("failed to close control socket")
+				}
+			}()
+		}
+	}
+
+	runErr := guard.run(p)
+
+	// players started from Chatuino would keep running without it
+	ui.StopPlayers()
+
+	// Close pool after UI exits (before checking error)
+	if closeErr := pool.Close(); closeErr != nil {
+		log.Logger.Err(closeErr).Msg("failed to close connection pool")
+	}
+
+	guard.restore()
+
+	if errors.Is(runErr, tea.ErrProgramPanic) {
+		runErr = guard.reportUIPanic(runErr)
+	}
+
+	// quitting with SIGINT or SIGTERM is no error, the session and chat logs are saved like on a regular quit
+	if ctx.Err() != nil && errors.Is(runErr, tea.ErrProgramKilled) && !errors.Is(runErr, tea.ErrProgramPanic) {
+		log.Logger.Info().Msg("received signal, shutting down")
+		runErr = nil
+	}
+
+	// the replay tab is not part of the session, the tabs of the previous session are kept
+	if replay == nil {
+		if err := saveSession(ui, appStateManager); err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("error while saving state: %w", err))
+		}
+	}
+
+	close(messageLoggerChan)
+	<-loggerWaitSync
+
+	// settings changed while running decide about the sync on exit
+	if runErr == nil && !offline && replay == nil {
+		if current, err := save.SettingsFromDisk(); err == nil && current.Sync.Auto {
+			// the proxy settings may have changed as well
+			var result remotesync.Result
+			client, err := newHTTPClient(current.Proxy, offline)
+			if err == nil {
+				result, err = autoSync(context.Background(), client, current.Sync)
+			}
+
+			if err != nil {
+				fmt.Println(configErrorStyle.Render("failed to sync: ") + cacheTextStyle.Render(err.Error()))
+			} else if len(result.Conflicts) > 0 {
+				fmt.Println(configWarningStyle.Render(fmt.Sprintf("%d files changed locally and on the remote, run \"chatuino sync --prefer local\" or \"--prefer remote\"", len(result.Conflicts))))
+			}
+		}
 	}
+
+	return runErr
+}
+
+// closeLogFile writes the log file to disk and closes it, safe to call more than once
+func closeLogFile() {
+	if maybeLogFile == nil {
+		return
+	}
+
+	_ = maybeLogFile.Sync()
+	_ = maybeLogFile.Close()
+	maybeLogFile = nil
 }
 
 func openDB(readonly bool) (*sql.DB, error) {
@@ -355,9 +765,9 @@ func openDB(readonly bool) (*sql.DB, error) {
 	)
 
 	if readonly {
-		db, err = sql.Open("sqlite", "file:"+dbFileName+"?mode=ro&_time_format=sqlite")
+		db, err = sql.Open("sqlite", "file:"+appPaths.DatabaseFile()+"?mode=ro&_time_format=sqlite")
 	} else {
-		db, err = sql.Open("sqlite", "file:"+dbFileName+"?_time_format=sqlite")
+		db, err = sql.Open("sqlite", "file:"+appPaths.DatabaseFile()+"?_time_format=sqlite")
 	}
 
 	if err != nil {
@@ -388,9 +798,34 @@ func runChatLogger(messageLogger *messagelog.BatchedMessageLogger, messageLogger
 	}
 }
 
+// newHTTPClient returns the client shared by all API clients, image downloads and WebSocket connections.
+// It connects through the proxy of the settings, offline it refuses all requests except to loopback addresses.
+func newHTTPClient(settings save.ProxySettings, offline bool) (*http.Client, error) {
+	proxyURL, err := settings.ProxyURL()
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy settings: %w", err)
+	}
+
+	if proxyURL != nil {
+		log.Logger.Info().Str("proxy", proxyURL.Redacted()).Msg("using proxy")
+	}
+
+	transport, err := httputil.NewProxyTransport(proxyURL, settings.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	var rt http.RoundTripper = transport
+	if offline {
+		rt = httputil.OfflineTransport(rt)
+	}
+
+	return &http.Client{Transport: httputil.NewChatuinoRoundTrip(rt, log.Logger, Version)}, nil
+}
+
 func beforeAction(ctx context.Context, command *cli.Command) (context.Context, error) {
 	// Setup logging
-	//  - If logging not enabled, skip
+	//  - Events are always kept in the log buffer, if logging is not enabled only there
 	//  - If log-to-file is enabled, log to file, else stderr
 	//  - If human-readable is enabled, log in human readable format (disable colors if log-to-file is enabled)
 	// This action runs before any command is executed, including sub commands, but will run for all sub commands
@@ -402,12 +837,33 @@ func beforeAction(ctx context.Context, command *cli.Command) (context.Context, e
 		http.DefaultClient.Transport = httputil.NewChatuinoRoundTrip(transport, log.Logger, Version)
 	}()
 
-	if err := os.MkdirAll(dataDir, 0o700); err != nil {
+	paths, err := save.ResolvePaths(command.String("config"), command.String("data-dir"))
+	if err != nil {
+		return ctx, fmt.Errorf("failed to resolve directories: %w", err)
+	}
+
+	appPaths = paths
+	save.UsePaths(paths)
+	kittyimg.BaseImageDirectory = paths.Data
+
+	if err := os.MkdirAll(paths.Data, 0o700); err != nil {
 		return ctx, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
+	if err := os.MkdirAll(paths.State, 0o700); err != nil {
+		return ctx, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// logging is not set up yet, a failed move only means the files of older versions are not used
+	moveErr := save.MoveLegacyStateFiles(afero.NewOsFs(), paths)
+	defer func() {
+		if moveErr != nil {
+			log.Logger.Err(moveErr).Msg("failed to move files to the state directory")
+		}
+	}()
+
 	if !command.Bool("log") {
-		log.Logger = zerolog.Nop()
+		log.Logger = zerolog.New(logRing).With().Timestamp().Logger()
 		return ctx, nil
 	}
 
@@ -426,12 +882,13 @@ func beforeAction(ctx context.Context, command *cli.Command) (context.Context, e
 		logFile = os.Stderr
 	}
 
+	var out io.Writer = logFile
 	if command.Bool("human-readable") {
-		log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: logFile, NoColor: shouldLogToFile}).With().Timestamp().Logger()
-	} else {
-		log.Logger = zerolog.New(logFile).With().Timestamp().Logger()
+		out = zerolog.ConsoleWriter{Out: logFile, NoColor: shouldLogToFile}
 	}
 
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(out, logRing)).With().Timestamp().Logger()
+
 	return ctx, nil
 }
 
@@ -463,10 +920,40 @@ func runProfilingServer(ctx context.Context, logger zerolog.Logger, host string)
 }
 
 func setupLogFile() (*os.File, error) {
-	f, err := os.OpenFile(logFileName, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	f, err := os.OpenFile(appPaths.LogFile(), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
 	if err != nil {
 		return nil, err
 	}
 
 	return f, nil
 }
+
+// runMetricsServer serves the metrics until ctx is done. Unlike profiling, a failing metrics server doesn't stop Chatuino.
+func runMetricsServer(ctx context.Context, logger zerolog.Logger, host string) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Default.Handler())
+
+	srv := &http.Server{
+		Addr:              host,
+		Handler:           mux,
+		ReadHeaderTimeout: time.Second * 10,
+	}
+
+	go func() {
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		logger.Info().Msg("shutting down metrics server")
+		if err := srv.Shutdown(ctx); err != nil {
+			logger.Error().Err(err).Msg("error while shutting down metrics server")
+		}
+	}()
+
+	go func() {
+		logger.Info().Str("host", host).Msg("running metrics server")
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error().Err(err).Msg("error while running metrics server")
+		}
+	}()
+}
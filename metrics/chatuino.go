@@ -0,0 +1,37 @@
+package metrics
+
+import "runtime"
+
+// Default holds the metrics of Chatuino
+var Default = NewRegistry()
+
+var (
+	ChatMessages       = Default.CounterVec("chatuino_chat_messages_total", "Chat messages received, by channel. Channels joined by multiple accounts count each message once per account.", "channel")
+	IRCReconnects      = Default.Counter("chatuino_irc_reconnects_total", "Reconnects of IRC connections")
+	EventSubReconnects = Default.Counter("chatuino_eventsub_reconnects_total", "Reconnects of EventSub connections, including reconnects requested by Twitch")
+
+	ImageCacheHits   = Default.CounterVec("chatuino_image_cache_hits_total", "Emote and badge images served from a cache, by cache: session for images already placed in the terminal, disk for images encoded in a previous session", "cache")
+	ImageCacheMisses = Default.Counter("chatuino_image_cache_misses_total", "Emote and badge images, which had to be downloaded and encoded")
+	ImageEncode      = Default.Timer("chatuino_image_encode_seconds", "Time spent decoding downloaded images and encoding them for the terminal")
+)
+
+func init() {
+	Default.GaugeFunc("go_goroutines", "Number of goroutines", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+
+	Default.GaugeFunc("go_memstats_heap_alloc_bytes", "Bytes of allocated heap objects", func() float64 {
+		return float64(ReadMemStats().HeapAlloc)
+	})
+
+	Default.GaugeFunc("go_memstats_sys_bytes", "Bytes of memory obtained from the operating system", func() float64 {
+		return float64(ReadMemStats().Sys)
+	})
+}
+
+// ReadMemStats returns the memory statistics of the Go runtime
+func ReadMemStats() runtime.MemStats {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats
+}
@@ -0,0 +1,227 @@
+// Package metrics is a small registry of counters, gauges and timers used to observe a running Chatuino.
+// The values are shown in the stats overlay and can be scraped in the Prometheus text format:
+//
+//	# HELP chatuino_irc_reconnects_total Reconnects of IRC connections
+//	# TYPE chatuino_irc_reconnects_total counter
+//	chatuino_irc_reconnects_total 2
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counter is a value which only goes up
+type Counter struct {
+	v atomic.Uint64
+}
+
+func (c *Counter) Inc() {
+	c.v.Add(1)
+}
+
+func (c *Counter) Add(n uint64) {
+	c.v.Add(n)
+}
+
+func (c *Counter) Value() uint64 {
+	return c.v.Load()
+}
+
+// CounterVec is a set of counters, partitioned by the value of one label
+type CounterVec struct {
+	label string
+
+	m        sync.Mutex
+	counters map[string]*Counter
+}
+
+// With returns the counter of the label value, creating it on first use
+func (v *CounterVec) With(value string) *Counter {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	c, ok := v.counters[value]
+	if !ok {
+		c = &Counter{}
+		v.counters[value] = c
+	}
+
+	return c
+}
+
+// Values returns the current value of each counter by label value
+func (v *CounterVec) Values() map[string]uint64 {
+	v.m.Lock()
+	defer v.m.Unlock()
+
+	values := make(map[string]uint64, len(v.counters))
+	for label, c := range v.counters {
+		values[label] = c.Value()
+	}
+
+	return values
+}
+
+// Total returns the sum of all counters
+func (v *CounterVec) Total() uint64 {
+	var total uint64
+	for _, value := range v.Values() {
+		total += value
+	}
+
+	return total
+}
+
+// Timer records the number and total duration of an operation
+type Timer struct {
+	count atomic.Uint64
+	sum   atomic.Int64 // nanoseconds
+}
+
+func (t *Timer) Observe(d time.Duration) {
+	t.count.Add(1)
+	t.sum.Add(int64(d))
+}
+
+// Since observes the time passed since start
+func (t *Timer) Since(start time.Time) {
+	t.Observe(time.Since(start))
+}
+
+func (t *Timer) Count() uint64 {
+	return t.count.Load()
+}
+
+func (t *Timer) Sum() time.Duration {
+	return time.Duration(t.sum.Load())
+}
+
+// Mean returns the average duration, or zero if nothing was observed yet
+func (t *Timer) Mean() time.Duration {
+	count := t.Count()
+	if count == 0 {
+		return 0
+	}
+
+	return t.Sum() / time.Duration(count)
+}
+
+// GaugeFunc returns the current value of a gauge, it is called whenever the metrics are written
+type GaugeFunc func() float64
+
+type metric struct {
+	name  string
+	help  string
+	value any // *Counter, *CounterVec, *Timer or GaugeFunc
+}
+
+// Registry holds named metrics, metrics can't be removed once registered
+type Registry struct {
+	m       sync.Mutex
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// register adds the metric, the name must be unique. Metrics are registered at package initialization, so a duplicate name is a programming error.
+func (r *Registry) register(name, help string, value any) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if slices.ContainsFunc(r.metrics, func(m metric) bool { return m.name == name }) {
+		panic(fmt.Sprintf("metrics: %s is already registered", name))
+	}
+
+	r.metrics = append(r.metrics, metric{name: name, help: help, value: value})
+}
+
+func (r *Registry) Counter(name, help string) *Counter {
+	c := &Counter{}
+	r.register(name, help, c)
+	return c
+}
+
+func (r *Registry) CounterVec(name, help, label string) *CounterVec {
+	v := &CounterVec{label: label, counters: map[string]*Counter{}}
+	r.register(name, help, v)
+	return v
+}
+
+// Timer registers a timer, it is written as summary in seconds
+func (r *Registry) Timer(name, help string) *Timer {
+	t := &Timer{}
+	r.register(name, help, t)
+	return t
+}
+
+func (r *Registry) GaugeFunc(name, help string, fn GaugeFunc) {
+	r.register(name, help, fn)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format, sorted by name
+func (r *Registry) WriteText(w io.Writer) error {
+	r.m.Lock()
+	metrics := slices.Clone(r.metrics)
+	r.m.Unlock()
+
+	slices.SortFunc(metrics, func(a, b metric) int { return strings.Compare(a.name, b.name) })
+
+	b := &strings.Builder{}
+
+	for _, m := range metrics {
+		switch value := m.value.(type) {
+		case *Counter:
+			writeHeader(b, m, "counter")
+			fmt.Fprintf(b, "%s %d\n", m.name, value.Value())
+		case *CounterVec:
+			writeHeader(b, m, "counter")
+
+			values := value.Values()
+			for _, label := range slices.Sorted(maps.Keys(values)) {
+				fmt.Fprintf(b, "%s{%s=%s} %d\n", m.name, value.label, quoteLabel(label), values[label])
+			}
+		case *Timer:
+			writeHeader(b, m, "summary")
+			fmt.Fprintf(b, "%s_sum %s\n", m.name, formatFloat(value.Sum().Seconds()))
+			fmt.Fprintf(b, "%s_count %d\n", m.name, value.Count())
+		case GaugeFunc:
+			writeHeader(b, m, "gauge")
+			fmt.Fprintf(b, "%s %s\n", m.name, formatFloat(value()))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler serves the metrics for scraping
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+func writeHeader(b *strings.Builder, m metric, kind string) {
+	help := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(m.help)
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", m.name, help, m.name, kind)
+}
+
+func quoteLabel(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
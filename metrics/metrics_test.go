@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+
+	reconnects := r.Counter("test_reconnects_total", "Reconnects")
+	messages := r.CounterVec("test_messages_total", "Messages\nby channel", "channel")
+	encode := r.Timer("test_encode_seconds", "Encode time")
+	r.GaugeFunc("test_images", "Placed images", func() float64 { return 1.5 })
+
+	reconnects.Inc()
+	reconnects.Add(2)
+	messages.With("lirik").Inc()
+	messages.With("lirik").Inc()
+	messages.With(`a"b`).Inc()
+	encode.Observe(time.Millisecond * 250)
+	encode.Observe(time.Millisecond * 750)
+
+	require.Equal(t, uint64(3), reconnects.Value())
+	require.Equal(t, map[string]uint64{"lirik": 2, `a"b`: 1}, messages.Values())
+	require.Equal(t, uint64(3), messages.Total())
+	require.Equal(t, time.Millisecond*500, encode.Mean())
+
+	b := &strings.Builder{}
+	require.NoError(t, r.WriteText(b))
+
+	require.Equal(t, `# HELP test_encode_seconds Encode time
+# TYPE test_encode_seconds summary
+test_encode_seconds_sum 1
+test_encode_seconds_count 2
+# HELP test_images Placed images
+# TYPE test_images gauge
+test_images 1.5
+# HELP test_messages_total Messages\nby channel
+# TYPE test_messages_total counter
+test_messages_total{channel="a\"b"} 1
+test_messages_total{channel="lirik"} 2
+# HELP test_reconnects_total Reconnects
+# TYPE test_reconnects_total counter
+test_reconnects_total 3
+`, b.String())
+}
+
+func TestRegistry_DuplicateName(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Counter("test_total", "")
+
+	require.PanicsWithValue(t, "metrics: test_total is already registered", func() {
+		r.Timer("test_total", "")
+	})
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Counter("test_total", "Test").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Contains(t, string(body), "test_total 1\n")
+}
+
+func TestTimer_Mean(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, (&Timer{}).Mean())
+}
@@ -0,0 +1,486 @@
+// Package obs connects to OBS Studio through obs-websocket (protocol version 5), which is included in OBS since version 28.
+// The client keeps track of the current scene and the streaming and recording status, and switches scenes or starts and stops outputs on request.
+package obs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/rs/zerolog"
+)
+
+const (
+	dialTimeout     = 5 * time.Second
+	reconnectDelay  = 10 * time.Second
+	requestTimeout  = 10 * time.Second
+	maxMessageSize  = 4 * 1024 * 1024
+	rpcVersion      = 1
+	subprotocolJSON = "obswebsocket.json"
+)
+
+// Op codes of the obs-websocket protocol
+const (
+	opHello      = 0
+	opIdentify   = 1
+	opIdentified = 2
+	opEvent      = 5
+	opRequest    = 6
+	opResponse   = 7
+)
+
+// Event subscriptions, see the EventSubscription enum of obs-websocket
+const (
+	subscribeScenes  = 1 << 2
+	subscribeOutputs = 1 << 6
+)
+
+// ErrNotConnected is returned for requests while OBS is not connected
+var ErrNotConnected = errors.New("not connected to OBS")
+
+// Config is the address of obs-websocket, the password is empty if authentication is disabled in OBS
+type Config struct {
+	Host     string
+	Port     int
+	Password string
+}
+
+// State is the last known state of OBS
+type State struct {
+	Connected bool
+	Scene     string // current program scene
+	Streaming bool
+	Recording bool
+}
+
+type message struct {
+	Op   int             `json:"op"`
+	Data json.RawMessage `json:"d"`
+}
+
+type response struct {
+	RequestType   string `json:"requestType"`
+	RequestID     string `json:"requestId"`
+	RequestStatus struct {
+		Result  bool   `json:"result"`
+		Code    int    `json:"code"`
+		Comment string `json:"comment"`
+	} `json:"requestStatus"`
+	ResponseData json.RawMessage `json:"responseData"`
+}
+
+// Client maintains the connection to OBS, reconnecting until the context passed to Run is done
+type Client struct {
+	logger   zerolog.Logger
+	url      string
+	password string
+
+	m         sync.Mutex
+	state     State
+	onChange  func(State)
+	ws        *websocket.Conn // nil while disconnected
+	pending   map[string]chan response
+	requestID int
+}
+
+func New(logger zerolog.Logger, cfg Config) *Client {
+	return &Client{
+		logger:   logger.With().Str("component", "obs").Logger(),
+		url:      "ws://" + net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		password: cfg.Password,
+		pending:  map[string]chan response{},
+	}
+}
+
+// SetOnChange sets the function called with the new state whenever the state changes. It is called from the goroutine of the connection.
+func (c *Client) SetOnChange(fn func(State)) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.onChange = fn
+}
+
+// State returns the last known state
+func (c *Client) State() State {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.state
+}
+
+// Run connects to OBS and reconnects after the connection is lost, it blocks until ctx is done
+func (c *Client) Run(ctx context.Context) {
+	wasConnected := true // log the first failed attempt
+
+	for {
+		err := c.connectOnce(ctx)
+		connected := c.State().Connected
+		c.updateState(func(s *State) { *s = State{} })
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// OBS not running is expected, only log when a connection was lost
+		if connected || wasConnected {
+			c.logger.Warn().Err(err).Msg("OBS disconnected, will reconnect")
+		}
+		wasConnected = connected
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (c *Client) connectOnce(ctx context.Context) error {
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(dialCtx, c.url, &websocket.DialOptions{Subprotocols: []string{subprotocolJSON}})
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+	defer ws.CloseNow()
+
+	ws.SetReadLimit(maxMessageSize)
+
+	if err := c.identify(dialCtx, ws); err != nil {
+		return err
+	}
+
+	c.m.Lock()
+	c.ws = ws
+	c.m.Unlock()
+
+	defer c.disconnect()
+
+	c.logger.Info().Str("url", c.url).Msg("connected to OBS")
+	c.updateState(func(s *State) { s.Connected = true })
+
+	// the initial state is requested while the read loop receives the responses
+	go c.fetchState(ctx)
+
+	return c.readLoop(ctx, ws)
+}
+
+// identify answers the hello of OBS and waits until the client is identified
+func (c *Client) identify(ctx context.Context, ws *websocket.Conn) error {
+	var hello struct {
+		Authentication *struct {
+			Challenge string `json:"challenge"`
+			Salt      string `json:"salt"`
+		} `json:"authentication"`
+	}
+
+	if err := readMessage(ctx, ws, opHello, &hello); err != nil {
+		return fmt.Errorf("failed to read hello: %w", err)
+	}
+
+	identify := map[string]any{
+		"rpcVersion":         rpcVersion,
+		"eventSubscriptions": subscribeScenes | subscribeOutputs,
+	}
+
+	if hello.Authentication != nil {
+		if c.password == "" {
+			return errors.New("OBS requires a password, set obs.password in the settings")
+		}
+
+		identify["authentication"] = authenticate(c.password, hello.Authentication.Salt, hello.Authentication.Challenge)
+	}
+
+	if err := writeMessage(ctx, ws, opIdentify, identify); err != nil {
+		return fmt.Errorf("failed to identify: %w", err)
+	}
+
+	if err := readMessage(ctx, ws, opIdentified, nil); err != nil {
+		if websocket.CloseStatus(err) == 4009 {
+			return errors.New("authentication failed, check obs.password in the settings")
+		}
+
+		return fmt.Errorf("failed to identify: %w", err)
+	}
+
+	return nil
+}
+
+// authenticate builds the authentication string from the password and the challenge of the hello message
+func authenticate(password, salt, challenge string) string {
+	secret := sha256.Sum256([]byte(password + salt))
+	auth := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(secret[:]) + challenge))
+
+	return base64.StdEncoding.EncodeToString(auth[:])
+}
+
+func (c *Client) disconnect() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.ws = nil
+
+	// pending requests are answered with ErrNotConnected
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+func (c *Client) readLoop(ctx context.Context, ws *websocket.Conn) error {
+	for {
+		_, b, err := ws.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(b, &msg); err != nil {
+			c.logger.Warn().Err(err).Msg("failed to parse message")
+			continue
+		}
+
+		switch msg.Op {
+		case opEvent:
+			c.handleEvent(msg.Data)
+		case opResponse:
+			var resp response
+			if err := json.Unmarshal(msg.Data, &resp); err != nil {
+				c.logger.Warn().Err(err).Msg("failed to parse response")
+				continue
+			}
+
+			c.m.Lock()
+			ch, ok := c.pending[resp.RequestID]
+			delete(c.pending, resp.RequestID)
+			c.m.Unlock()
+
+			if ok {
+				ch <- resp
+			}
+		}
+	}
+}
+
+func (c *Client) handleEvent(data json.RawMessage) {
+	var event struct {
+		EventType string `json:"eventType"`
+		EventData struct {
+			SceneName    string `json:"sceneName"`
+			OutputActive bool   `json:"outputActive"`
+		} `json:"eventData"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		c.logger.Warn().Err(err).Msg("failed to parse event")
+		return
+	}
+
+	switch event.EventType {
+	case "CurrentProgramSceneChanged":
+		c.updateState(func(s *State) { s.Scene = event.EventData.SceneName })
+	case "StreamStateChanged":
+		c.updateState(func(s *State) { s.Streaming = event.EventData.OutputActive })
+	case "RecordStateChanged":
+		c.updateState(func(s *State) { s.Recording = event.EventData.OutputActive })
+	}
+}
+
+// fetchState requests the current scene and the output status after connecting
+func (c *Client) fetchState(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var (
+		scene struct {
+			CurrentProgramSceneName string `json:"currentProgramSceneName"`
+		}
+		stream, record struct {
+			OutputActive bool `json:"outputActive"`
+		}
+	)
+
+	err := errors.Join(
+		c.request(ctx, "GetCurrentProgramScene", nil, &scene),
+		c.request(ctx, "GetStreamStatus", nil, &stream),
+		c.request(ctx, "GetRecordStatus", nil, &record),
+	)
+	if err != nil {
+		c.logger.Err(err).Msg("failed to fetch OBS state")
+		return
+	}
+
+	c.updateState(func(s *State) {
+		s.Scene = scene.CurrentProgramSceneName
+		s.Streaming = stream.OutputActive
+		s.Recording = record.OutputActive
+	})
+}
+
+// updateState changes the state and calls the change function, if the state changed
+func (c *Client) updateState(update func(*State)) {
+	c.m.Lock()
+	old := c.state
+	update(&c.state)
+	state, onChange := c.state, c.onChange
+	c.m.Unlock()
+
+	if state != old && onChange != nil {
+		onChange(state)
+	}
+}
+
+// request sends a request and decodes the response data into out, if out is not nil
+func (c *Client) request(ctx context.Context, requestType string, data any, out any) error {
+	c.m.Lock()
+	ws := c.ws
+	if ws == nil {
+		c.m.Unlock()
+		return ErrNotConnected
+	}
+
+	c.requestID++
+	id := strconv.Itoa(c.requestID)
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.m.Unlock()
+
+	req := map[string]any{
+		"requestType": requestType,
+		"requestId":   id,
+	}
+
+	if data != nil {
+		req["requestData"] = data
+	}
+
+	if err := writeMessage(ctx, ws, opRequest, req); err != nil {
+		c.forget(id)
+		return fmt.Errorf("failed to send %s: %w", requestType, err)
+	}
+
+	var resp response
+
+	select {
+	case r, ok := <-ch:
+		if !ok {
+			return ErrNotConnected
+		}
+		resp = r
+	case <-ctx.Done():
+		c.forget(id)
+		return fmt.Errorf("%s: %w", requestType, ctx.Err())
+	}
+
+	if !resp.RequestStatus.Result {
+		if resp.RequestStatus.Comment != "" {
+			return fmt.Errorf("%s failed: %s", requestType, resp.RequestStatus.Comment)
+		}
+
+		return fmt.Errorf("%s failed with code %d", requestType, resp.RequestStatus.Code)
+	}
+
+	if out == nil || len(resp.ResponseData) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.ResponseData, out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", requestType, err)
+	}
+
+	return nil
+}
+
+func (c *Client) forget(id string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	delete(c.pending, id)
+}
+
+// Scenes returns the names of all scenes in the order shown in OBS
+func (c *Client) Scenes(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Scenes []struct {
+			SceneName  string `json:"sceneName"`
+			SceneIndex int    `json:"sceneIndex"`
+		} `json:"scenes"`
+	}
+
+	if err := c.request(ctx, "GetSceneList", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	// OBS lists the scenes bottom to top
+	names := make([]string, len(resp.Scenes))
+	for i, s := range resp.Scenes {
+		names[len(resp.Scenes)-1-i] = s.SceneName
+	}
+
+	return names, nil
+}
+
+func (c *Client) SetScene(ctx context.Context, name string) error {
+	return c.request(ctx, "SetCurrentProgramScene", map[string]string{"sceneName": name}, nil)
+}
+
+func (c *Client) StartStream(ctx context.Context) error {
+	return c.request(ctx, "StartStream", nil, nil)
+}
+
+func (c *Client) StopStream(ctx context.Context) error {
+	return c.request(ctx, "StopStream", nil, nil)
+}
+
+func (c *Client) StartRecord(ctx context.Context) error {
+	return c.request(ctx, "StartRecord", nil, nil)
+}
+
+func (c *Client) StopRecord(ctx context.Context) error {
+	return c.request(ctx, "StopRecord", nil, nil)
+}
+
+func readMessage(ctx context.Context, ws *websocket.Conn, op int, out any) error {
+	_, b, err := ws.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	var msg message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return err
+	}
+
+	if msg.Op != op {
+		return fmt.Errorf("expected op %d, got %d", op, msg.Op)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(msg.Data, out)
+}
+
+func writeMessage(ctx context.Context, ws *websocket.Conn, op int, data any) error {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(message{Op: op, Data: d})
+	if err != nil {
+		return err
+	}
+
+	return ws.Write(ctx, websocket.MessageText, b)
+}
@@ -0,0 +1,217 @@
+package obs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOBS implements the parts of obs-websocket used by the client
+type fakeOBS struct {
+	t        *testing.T
+	password string
+
+	m        sync.Mutex
+	requests []string
+	conn     *websocket.Conn
+}
+
+func (f *fakeOBS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{Subprotocols: []string{subprotocolJSON}})
+	require.NoError(f.t, err)
+	defer ws.CloseNow()
+
+	ctx := r.Context()
+
+	hello := map[string]any{"obsWebSocketVersion": "5.5.0", "rpcVersion": 1}
+	if f.password != "" {
+		hello["authentication"] = map[string]string{"challenge": "challenge", "salt": "salt"}
+	}
+	require.NoError(f.t, writeMessage(ctx, ws, opHello, hello))
+
+	var identify struct {
+		RPCVersion         int    `json:"rpcVersion"`
+		Authentication     string `json:"authentication"`
+		EventSubscriptions int    `json:"eventSubscriptions"`
+	}
+	require.NoError(f.t, readMessage(ctx, ws, opIdentify, &identify))
+	require.Equal(f.t, subscribeScenes|subscribeOutputs, identify.EventSubscriptions)
+
+	if f.password != "" && identify.Authentication != authenticate(f.password, "salt", "challenge") {
+		_ = ws.Close(4009, "Authentication failed.")
+		return
+	}
+
+	require.NoError(f.t, writeMessage(ctx, ws, opIdentified, map[string]int{"negotiatedRpcVersion": 1}))
+
+	f.m.Lock()
+	f.conn = ws
+	f.m.Unlock()
+
+	for {
+		var req struct {
+			RequestType string          `json:"requestType"`
+			RequestID   string          `json:"requestId"`
+			RequestData json.RawMessage `json:"requestData"`
+		}
+
+		if err := readMessage(ctx, ws, opRequest, &req); err != nil {
+			return
+		}
+
+		f.m.Lock()
+		f.requests = append(f.requests, req.RequestType)
+		f.m.Unlock()
+
+		status := map[string]any{"result": true, "code": 100}
+		var data any
+
+		switch req.RequestType {
+		case "GetCurrentProgramScene":
+			data = map[string]string{"currentProgramSceneName": "Starting"}
+		case "GetStreamStatus":
+			data = map[string]bool{"outputActive": true}
+		case "GetRecordStatus":
+			data = map[string]bool{"outputActive": false}
+		case "GetSceneList":
+			data = map[string]any{"scenes": []map[string]any{
+				{"sceneName": "Ending", "sceneIndex": 0},
+				{"sceneName": "Game", "sceneIndex": 1},
+				{"sceneName": "Starting", "sceneIndex": 2},
+			}}
+		case "SetCurrentProgramScene":
+			var scene struct {
+				SceneName string `json:"sceneName"`
+			}
+			require.NoError(f.t, json.Unmarshal(req.RequestData, &scene))
+
+			if scene.SceneName == "Missing" {
+				status = map[string]any{"result": false, "code": 600, "comment": "No source was found by the name of `Missing`."}
+			}
+		}
+
+		require.NoError(f.t, writeMessage(ctx, ws, opResponse, map[string]any{
+			"requestType":   req.RequestType,
+			"requestId":     req.RequestID,
+			"requestStatus": status,
+			"responseData":  data,
+		}))
+	}
+}
+
+func (f *fakeOBS) event(eventType string, data any) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	require.NoError(f.t, writeMessage(context.Background(), f.conn, opEvent, map[string]any{
+		"eventType":   eventType,
+		"eventIntent": subscribeScenes,
+		"eventData":   data,
+	}))
+}
+
+// newClient starts a fake OBS with the password "secret" and returns a client for it
+func newClient(t *testing.T, password string) (*Client, *fakeOBS) {
+	t.Helper()
+
+	fake := &fakeOBS{t: t, password: "secret"}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	return New(zerolog.Nop(), Config{Host: u.Hostname(), Port: port, Password: password}), fake
+}
+
+func nextState(t *testing.T, states <-chan State) State {
+	t.Helper()
+
+	select {
+	case s := <-states:
+		return s
+	case <-time.After(time.Second * 5):
+		t.Fatal("no state change")
+		return State{}
+	}
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	client, fake := newClient(t, "secret")
+
+	states := make(chan State, 10)
+	client.SetOnChange(func(s State) { states <- s })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	go func() {
+		defer close(done)
+		client.Run(ctx)
+	}()
+
+	require.Equal(t, State{Connected: true}, nextState(t, states))
+	require.Equal(t, State{Connected: true, Scene: "Starting", Streaming: true}, nextState(t, states))
+	require.Equal(t, State{Connected: true, Scene: "Starting", Streaming: true}, client.State())
+
+	fake.event("CurrentProgramSceneChanged", map[string]string{"sceneName": "Game"})
+	require.Equal(t, State{Connected: true, Scene: "Game", Streaming: true}, nextState(t, states))
+
+	fake.event("RecordStateChanged", map[string]any{"outputActive": true, "outputState": "OBS_WEBSOCKET_OUTPUT_STARTED"})
+	require.Equal(t, State{Connected: true, Scene: "Game", Streaming: true, Recording: true}, nextState(t, states))
+
+	reqCtx, reqCancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer reqCancel()
+
+	scenes, err := client.Scenes(reqCtx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Starting", "Game", "Ending"}, scenes)
+
+	require.NoError(t, client.SetScene(reqCtx, "Ending"))
+	require.EqualError(t, client.SetScene(reqCtx, "Missing"), "SetCurrentProgramScene failed: No source was found by the name of `Missing`.")
+	require.NoError(t, client.StopStream(reqCtx))
+
+	fake.m.Lock()
+	defer fake.m.Unlock()
+	require.Equal(t, []string{"GetCurrentProgramScene", "GetStreamStatus", "GetRecordStatus", "GetSceneList", "SetCurrentProgramScene", "SetCurrentProgramScene", "StopStream"}, fake.requests)
+}
+
+func TestClient_WrongPassword(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newClient(t, "wrong")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err := client.connectOnce(ctx)
+	require.EqualError(t, err, "authentication failed, check obs.password in the settings")
+	require.False(t, client.State().Connected)
+	require.ErrorIs(t, client.StartStream(ctx), ErrNotConnected)
+}
+
+func Test_authenticate(t *testing.T) {
+	t.Parallel()
+
+	// example of the obs-websocket protocol documentation
+	require.Equal(t, "1Ct943GAT+6YQUUX47Ia/ncufilbe6+oD6lY+5kaCu4=", authenticate("supersecretpassword", "lM1GncleQOaCu9lT1yeUZhFYnqhsLLP1G5lAGo3ixaI=", "+IxH4CnCiqpX1rM9scsNynZzbOe4KhDeYcTNS3PDaeY="))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+)
+
+var pathsCMD = &cli.Command{
+	Name:        "paths",
+	Usage:       "Print the locations of the files used by Chatuino",
+	Description: "Print the resolved directories and files, including overrides by the --config and --data-dir flags or their environment variables.",
+	Action: func(_ context.Context, _ *cli.Command) error {
+		locations := []struct {
+			name string
+			path string
+		}{
+			{"config", appPaths.Config},
+			{"settings", appPaths.SettingsFile()},
+			{"theme", appPaths.ThemeFile()},
+			{"keymap", appPaths.KeymapFile()},
+			{"scripts", appPaths.ScriptDir()},
+			{"dictionary", appPaths.DictionaryFile()},
+			{"data", appPaths.Data},
+			{"state", appPaths.State},
+			{"database", appPaths.DatabaseFile()},
+			{"log", appPaths.LogFile()},
+			{"session", appPaths.StateFile()},
+			{"notes", appPaths.NotesFile()},
+			{"commands", appPaths.CommandsFile()},
+			{"socket", appPaths.SocketFile()},
+		}
+
+		for _, l := range locations {
+			fmt.Printf("%-10s %s\n", l.name, l.path)
+		}
+
+		return nil
+	},
+}
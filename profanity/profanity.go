@@ -0,0 +1,150 @@
+// Package profanity masks profanity in chat messages. Matched words are replaced with asterisks, the message itself stays visible.
+package profanity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultWords are common English swear words, used unless the built-in list is disabled.
+// A trailing * matches all words starting with the word.
+var DefaultWords = []string{
+	"arse",
+	"arsehole*",
+	"ass",
+	"asses",
+	"asshat*",
+	"asshole*",
+	"bastard*",
+	"bitch*",
+	"bollock*",
+	"bullshit*",
+	"cock",
+	"cocks",
+	"cocksucker*",
+	"crap",
+	"crappy",
+	"cunt*",
+	"damn",
+	"damnit",
+	"dick",
+	"dickhead*",
+	"dicks",
+	"dipshit*",
+	"douche*",
+	"fuck*",
+	"goddamn*",
+	"horseshit*",
+	"jackass*",
+	"motherfuck*",
+	"piss",
+	"pissed",
+	"prick",
+	"pricks",
+	"shit*",
+	"slut*",
+	"twat*",
+	"wank*",
+	"whore*",
+}
+
+// Filter masks the words of a word list, words are matched case-insensitively as a whole
+type Filter struct {
+	words    map[string]struct{}
+	prefixes []string
+}
+
+// New creates a filter for the words, a trailing * matches all words starting with the word
+func New(words []string) *Filter {
+	f := &Filter{words: make(map[string]struct{}, len(words))}
+
+	for _, w := range words {
+		w = strings.ToLower(strings.TrimSpace(w))
+
+		if prefix, ok := strings.CutSuffix(w, "*"); ok {
+			if prefix != "" {
+				f.prefixes = append(f.prefixes, prefix)
+			}
+			continue
+		}
+
+		if w != "" {
+			f.words[w] = struct{}{}
+		}
+	}
+
+	return f
+}
+
+// Mask replaces every listed word in text with asterisks, one per character. Words are runs of letters and digits,
+// so punctuation around a word and other words containing it are not changed. A nil filter masks nothing.
+func (f *Filter) Mask(text string) string {
+	if f == nil || (len(f.words) == 0 && len(f.prefixes) == 0) {
+		return text
+	}
+
+	var (
+		b      strings.Builder
+		masked bool
+		last   int // end of the text already written to b
+		start  = -1
+	)
+
+	maskWord := func(end int) {
+		word := text[start:end]
+		start = -1
+
+		if !f.matches(word) {
+			return
+		}
+
+		if !masked {
+			b.Grow(len(text))
+			masked = true
+		}
+
+		b.WriteString(text[last : end-len(word)])
+		b.WriteString(strings.Repeat("*", utf8.RuneCountInString(word)))
+		last = end
+	}
+
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+
+		switch {
+		case isWordRune && start == -1:
+			start = i
+		case !isWordRune && start != -1:
+			maskWord(i)
+		}
+	}
+
+	if start != -1 {
+		maskWord(len(text))
+	}
+
+	if !masked {
+		return text
+	}
+
+	b.WriteString(text[last:])
+
+	return b.String()
+}
+
+func (f *Filter) matches(word string) bool {
+	word = strings.ToLower(word)
+
+	if _, ok := f.words[word]; ok {
+		return true
+	}
+
+	for _, p := range f.prefixes {
+		if strings.HasPrefix(word, p) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package profanity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Mask(t *testing.T) {
+	t.Parallel()
+
+	f := New([]string{"heck", "dang*", " Fudge ", "", "*"})
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "word", in: "what the heck", want: "what the ****"},
+		{name: "case insensitive", in: "HECK yes", want: "**** yes"},
+		{name: "punctuation is kept", in: "oh, heck! (heck)", want: "oh, ****! (****)"},
+		{name: "prefix", in: "dangit, that's dangerous", want: "******, that's *********"},
+		{name: "trimmed word", in: "fudge", want: "*****"},
+		{name: "part of another word", in: "checkmate heckler", want: "checkmate heckler"},
+		{name: "counts characters", in: "héck dangé", want: "héck *****"},
+		{name: "nothing to mask", in: "hello chat", want: "hello chat"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, f.Mask(tt.in))
+		})
+	}
+}
+
+func TestFilter_MaskNil(t *testing.T) {
+	t.Parallel()
+
+	var f *Filter
+	require.Equal(t, "heck", f.Mask("heck"))
+	require.Equal(t, "heck", New(nil).Mask("heck"))
+}
+
+func TestDefaultWords(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "****, the classic assassin is a pain in the ***", New(DefaultWords).Mask("Shit, the classic assassin is a pain in the ass"))
+}
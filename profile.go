@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/ui/mainui"
+)
+
+// screenReaderFPS limits the repaints with the screen reader mode, screen readers announce every changed line
+const screenReaderFPS = 4
+
+// renderingProfile adjusts the settings to the SSH session and the accessibility settings, the files on disk are left alone.
+// In an SSH session the terminal runs on another machine.
+func renderingProfile(settings save.Settings, sshSession bool) save.Settings {
+	if sshSession && !settings.SSH.Graphics {
+		settings.Chat.GraphicEmotes, settings.Chat.GraphicBadges = false, false
+	}
+
+	if settings.Accessibility.ScreenReader {
+		settings.Chat.GraphicEmotes, settings.Chat.GraphicBadges = false, false
+		settings.Chat.RenderFPS = min(settings.Chat.RenderFPS, screenReaderFPS)
+
+		// relative timestamps change every line of the chat while they tick
+		if settings.Timestamps.Format == save.TimestampFormatRelative {
+			settings.Timestamps.Format = save.TimestampFormatSeconds
+		}
+	}
+
+	return settings
+}
+
+// profileConfigSource applies the rendering profile to reloaded configs as well
+type profileConfigSource struct {
+	mainui.ConfigSource
+	sshSession bool
+}
+
+func (s profileConfigSource) Load() (save.Config, error) {
+	config, err := s.ConfigSource.Load()
+	if err != nil {
+		return save.Config{}, err
+	}
+
+	config.Settings = renderingProfile(config.Settings, s.sshSession)
+
+	return config, nil
+}
@@ -0,0 +1,338 @@
+package remotesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Git keeps the files in a branch of a git repository, using a local clone in dir. The git command must be installed,
+// authentication is left to git, like SSH keys or credential helpers. Concurrent syncs are detected by rejected pushes.
+type Git struct {
+	remote string
+	branch string
+	dir    string
+}
+
+// NewGit returns a backend syncing with the branch of the remote repository
+func NewGit(remote, branch, dir string) (*Git, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git sync requires git to be installed: %w", err)
+	}
+
+	if err := ValidateGitRemote(remote); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateGitBranch(branch); err != nil {
+		return nil, err
+	}
+
+	return &Git{remote: remote, branch: branch, dir: dir}, nil
+}
+
+// ValidateGitRemote checks the remote repository, which can't start with a dash since git would read it as an option
+func ValidateGitRemote(remote string) error {
+	if remote == "" {
+		return fmt.Errorf("git remote can't be empty")
+	}
+
+	if strings.HasPrefix(remote, "-") {
+		return fmt.Errorf("git remote %q can't start with a dash", remote)
+	}
+
+	return nil
+}
+
+// ValidateGitBranch checks the branch name with git check-ref-format if git is installed
+func ValidateGitBranch(branch string) error {
+	if branch == "" {
+		return fmt.Errorf("git branch can't be empty")
+	}
+
+	if strings.HasPrefix(branch, "-") {
+		return fmt.Errorf("git branch %q can't start with a dash", branch)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil
+	}
+
+	// run outside of any repository, so shorthands like @{-1} are not resolved
+	cmd := exec.Command("git", "check-ref-format", "--branch", branch)
+	cmd.Dir = os.TempDir()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git branch %q is not a valid branch name", branch)
+	}
+
+	return nil
+}
+
+func (g *Git) Fetch(ctx context.Context) (Snapshot, error) {
+	if err := g.init(ctx); err != nil {
+		return Snapshot{}, err
+	}
+
+	revision, err := g.fetchBranch(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if revision == "" {
+		return Snapshot{Files: map[string][]byte{}}, nil
+	}
+
+	if err := g.checkout(ctx, revision); err != nil {
+		return Snapshot{}, err
+	}
+
+	files, err := g.readFiles()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{Files: files, Revision: revision}, nil
+}
+
+func (g *Git) Store(ctx context.Context, files map[string][]byte, revision string) error {
+	if revision == "" {
+		// an empty remote starts a new history
+		if err := os.RemoveAll(g.dir); err != nil {
+			return err
+		}
+
+		if err := g.init(ctx); err != nil {
+			return err
+		}
+	} else if err := g.checkout(ctx, revision); err != nil {
+		return err
+	}
+
+	if err := g.writeFiles(files); err != nil {
+		return err
+	}
+
+	if _, err := g.git(ctx, "add", "--all"); err != nil {
+		return err
+	}
+
+	status, err := g.git(ctx, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+
+	if status == "" && revision != "" {
+		return nil
+	}
+
+	// the revision may be outdated, nothing has to be pushed if the remote already has the same files
+	if revision != "" {
+		unchanged, err := g.matchesRemote(ctx)
+		if err != nil {
+			return err
+		}
+
+		if unchanged {
+			return nil
+		}
+	}
+
+	host, _ := os.Hostname()
+	if _, err := g.git(ctx, "commit", "--allow-empty", "--message", "Sync from "+host); err != nil {
+		return err
+	}
+
+	_, err = g.git(ctx, "push", "--", "origin", "HEAD:refs/heads/"+g.branch)
+	if err != nil && isRejectedPush(err) {
+		return ErrRemoteChanged
+	}
+
+	return err
+}
+
+// fetchBranch fetches the branch and returns its revision, empty if the remote doesn't have the branch yet
+func (g *Git) fetchBranch(ctx context.Context) (string, error) {
+	heads, err := g.git(ctx, "ls-remote", "--heads", "--", "origin", "refs/heads/"+g.branch)
+	if err != nil {
+		return "", err
+	}
+
+	if heads == "" {
+		return "", nil
+	}
+
+	if _, err := g.git(ctx, "fetch", "--quiet", "--", "origin", "refs/heads/"+g.branch); err != nil {
+		return "", err
+	}
+
+	return g.git(ctx, "rev-parse", "--verify", "FETCH_HEAD^{commit}")
+}
+
+// matchesRemote reports whether the staged files are the same as the files of the branch on the remote
+func (g *Git) matchesRemote(ctx context.Context) (bool, error) {
+	revision, err := g.fetchBranch(ctx)
+	if err != nil || revision == "" {
+		return false, err
+	}
+
+	remoteTree, err := g.git(ctx, "rev-parse", "--verify", "FETCH_HEAD^{tree}")
+	if err != nil {
+		return false, err
+	}
+
+	tree, err := g.git(ctx, "write-tree")
+	if err != nil {
+		return false, err
+	}
+
+	return tree == remoteTree, nil
+}
+
+// init creates the local clone or points it to the configured remote
+func (g *Git) init(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(g.dir, ".git")); err == nil {
+		_, err := g.git(ctx, "remote", "set-url", "--", "origin", g.remote)
+		return err
+	}
+
+	if err := os.MkdirAll(g.dir, 0o700); err != nil {
+		return err
+	}
+
+	if _, err := g.git(ctx, "init", "--quiet"); err != nil {
+		return err
+	}
+
+	_, err := g.git(ctx, "remote", "add", "--", "origin", g.remote)
+	return err
+}
+
+func (g *Git) checkout(ctx context.Context, revision string) error {
+	if _, err := g.git(ctx, "checkout", "--quiet", "--force", "--detach", revision); err != nil {
+		return err
+	}
+
+	_, err := g.git(ctx, "clean", "-ffdx", "--quiet")
+	return err
+}
+
+func (g *Git) readFiles() (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	err := filepath.WalkDir(g.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(g.dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+
+	return files, err
+}
+
+// writeFiles replaces the work tree with the files
+func (g *Git) writeFiles(files map[string][]byte) error {
+	entries, err := os.ReadDir(g.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(g.dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	for name, data := range files {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("unexpected file %q", name)
+		}
+
+		path := filepath.Join(g.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type gitError struct {
+	args   []string
+	stderr string
+	err    error
+}
+
+func (e *gitError) Error() string {
+	if e.stderr == "" {
+		return fmt.Sprintf("git %s: %s", e.args[0], e.err)
+	}
+
+	return fmt.Sprintf("git %s: %s", e.args[0], e.stderr)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
+
+func isRejectedPush(err error) bool {
+	var gitErr *gitError
+	if !errors.As(err, &gitErr) {
+		return false
+	}
+
+	return strings.Contains(gitErr.stderr, "[rejected]") || strings.Contains(gitErr.stderr, "non-fast-forward") || strings.Contains(gitErr.stderr, "fetch first")
+}
+
+// git runs the git command in the local clone and returns its trimmed output
+func (g *Git) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.dir}, args...)...)
+	// never wait for a password prompt, authentication must work without input
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "LC_ALL=C",
+		"GIT_AUTHOR_NAME=Chatuino", "GIT_AUTHOR_EMAIL=chatuino@localhost",
+		"GIT_COMMITTER_NAME=Chatuino", "GIT_COMMITTER_EMAIL=chatuino@localhost",
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", &gitError{args: args, stderr: strings.TrimSpace(stderr.String()), err: err}
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,75 @@
+package remotesync
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	require.NoError(t, exec.Command("git", "init", "--quiet", "--bare", remote).Run())
+
+	laptop, err := NewGit(remote, "main", filepath.Join(dir, "laptop"))
+	require.NoError(t, err)
+
+	desktop, err := NewGit(remote, "main", filepath.Join(dir, "desktop"))
+	require.NoError(t, err)
+
+	snapshot, err := laptop.Fetch(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, snapshot.Files)
+
+	files := map[string][]byte{"settings.yaml": []byte("chat: {}\n"), "scripts/a/main.lua": []byte("-- a\n")}
+	require.NoError(t, laptop.Store(t.Context(), files, snapshot.Revision))
+
+	desktopSnapshot, err := desktop.Fetch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, files, desktopSnapshot.Files)
+	require.NotEmpty(t, desktopSnapshot.Revision)
+
+	require.NoError(t, desktop.Store(t.Context(), map[string][]byte{"settings.yaml": []byte("chat: {}\n")}, desktopSnapshot.Revision))
+	pushed, err := exec.Command("git", "-C", remote, "rev-parse", "main").Output()
+	require.NoError(t, err)
+
+	// the revision is outdated now, but the remote already has the same files
+	require.NoError(t, desktop.Store(t.Context(), map[string][]byte{"settings.yaml": []byte("chat: {}\n")}, desktopSnapshot.Revision), "nothing changed")
+
+	head, err := exec.Command("git", "-C", remote, "rev-parse", "main").Output()
+	require.NoError(t, err)
+	require.Equal(t, string(pushed), string(head), "nothing is pushed when nothing changed")
+
+	// the laptop pushes on top of the revision it fetched before the desktop pushed
+	require.ErrorIs(t, laptop.Store(t.Context(), map[string][]byte{"notes.json": []byte("{}")}, snapshot.Revision), ErrRemoteChanged)
+
+	snapshot, err = laptop.Fetch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"settings.yaml": []byte("chat: {}\n")}, snapshot.Files)
+}
+
+func TestValidateGit(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateGitRemote("git@github.com:user/config.git"))
+	require.Error(t, ValidateGitRemote(""))
+	require.Error(t, ValidateGitRemote("--upload-pack=touch /tmp/x"))
+
+	require.NoError(t, ValidateGitBranch("main"))
+	require.NoError(t, ValidateGitBranch("sync/laptop"))
+	require.Error(t, ValidateGitBranch(""))
+	require.Error(t, ValidateGitBranch("--upload-pack=touch /tmp/x"))
+
+	if _, err := exec.LookPath("git"); err == nil {
+		require.Error(t, ValidateGitBranch("a..b"))
+		require.Error(t, ValidateGitBranch("@{-1}"))
+	}
+}
@@ -0,0 +1,274 @@
+// Package remotesync keeps the configuration and notes of multiple machines in sync through a git repository or a WebDAV server.
+// Each sync is a three-way merge of the local files, the remote files and the files at the last sync: files changed on one side
+// are copied to the other, files changed differently on both sides are conflicts and are left alone until one side is preferred.
+package remotesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	BackendGit    = "git"
+	BackendWebDAV = "webdav"
+)
+
+// ErrRemoteChanged is returned when the remote changed between fetching and storing, syncing again merges the changes
+var ErrRemoteChanged = errors.New("remote changed during sync, sync again")
+
+// Snapshot is the content of the remote, files by their slash separated path
+type Snapshot struct {
+	Files    map[string][]byte
+	Revision string // version the files were fetched at, empty for an empty remote
+}
+
+// Backend reads and replaces all files of the remote
+type Backend interface {
+	Fetch(ctx context.Context) (Snapshot, error)
+	// Store replaces the files of the remote, ErrRemoteChanged is returned if it is no longer at revision
+	Store(ctx context.Context, files map[string][]byte, revision string) error
+}
+
+// Local reads and writes the synced files of this machine
+type Local interface {
+	Files() (map[string][]byte, error)
+	// Write writes the pulled files, nil content removes a file
+	Write(files map[string][]byte) error
+	// Accept reports if the remote file is synced, other files of the remote are kept but never pulled
+	Accept(name string) bool
+}
+
+// Prefer resolves conflicts
+type Prefer int
+
+const (
+	PreferNone   Prefer = iota // conflicts are reported and left alone
+	PreferLocal                // the local file replaces the remote one
+	PreferRemote               // the remote file replaces the local one
+)
+
+// ParsePrefer returns the side named local or remote, an empty name prefers none
+func ParsePrefer(name string) (Prefer, error) {
+	switch name {
+	case "":
+		return PreferNone, nil
+	case "local":
+		return PreferLocal, nil
+	case "remote":
+		return PreferRemote, nil
+	}
+
+	return PreferNone, fmt.Errorf("prefer must be local or remote, got %q", name)
+}
+
+// Options configure a single sync
+type Options struct {
+	Prefer Prefer
+	DryRun bool // only report the changes, nothing is written
+}
+
+// Result lists the files of a sync
+type Result struct {
+	Pulled    []string // changed on the remote, written locally
+	Pushed    []string // changed locally, written to the remote
+	Conflicts []string // changed on both sides, left alone
+}
+
+// State is kept locally after each sync: the hashes of the files both sides agreed on
+type State struct {
+	Files    map[string]string `json:"files"`
+	SyncedAt time.Time         `json:"synced_at"`
+}
+
+// Syncer syncs the local files with the backend and keeps the state in StateFile
+type Syncer struct {
+	Backend   Backend
+	Local     Local
+	FS        afero.Fs
+	StateFile string
+}
+
+// Sync merges the local and remote changes since the last sync. The remote is stored before the local files are written,
+// so a failed sync leaves the local files untouched.
+func (s *Syncer) Sync(ctx context.Context, opts Options) (Result, error) {
+	state, err := s.loadState()
+	if err != nil {
+		return Result{}, err
+	}
+
+	local, err := s.Local.Files()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read local files: %w", err)
+	}
+
+	snapshot, err := s.Backend.Fetch(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to fetch remote: %w", err)
+	}
+
+	remote := make(map[string][]byte, len(snapshot.Files))
+	for name, data := range snapshot.Files {
+		if s.Local.Accept(name) {
+			remote[name] = data
+		}
+	}
+
+	var (
+		result   Result
+		pulled   = map[string][]byte{}
+		stored   = maps.Clone(snapshot.Files)
+		newState = State{Files: map[string]string{}, SyncedAt: time.Now()}
+	)
+
+	if stored == nil {
+		stored = map[string][]byte{}
+	}
+
+	names := slices.Sorted(maps.Keys(union(local, remote, state.Files)))
+	for _, name := range names {
+		l, r, b := hashOf(local, name), hashOf(remote, name), state.Files[name]
+
+		side := PreferNone
+		switch {
+		case l == r:
+		case r == b:
+			side = PreferLocal
+		case l == b:
+			side = PreferRemote
+		default:
+			side = opts.Prefer
+		}
+
+		switch side {
+		case PreferLocal:
+			result.Pushed = append(result.Pushed, name)
+			if data, ok := local[name]; ok {
+				stored[name] = data
+			} else {
+				delete(stored, name)
+			}
+
+			b = l
+		case PreferRemote:
+			result.Pulled = append(result.Pulled, name)
+			pulled[name] = remote[name]
+			b = r
+		default:
+			if l != r {
+				result.Conflicts = append(result.Conflicts, name)
+			} else {
+				b = l
+			}
+		}
+
+		if b != "" {
+			newState.Files[name] = b
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if len(result.Pushed) > 0 {
+		if err := s.Backend.Store(ctx, stored, snapshot.Revision); err != nil {
+			if errors.Is(err, ErrRemoteChanged) {
+				return Result{}, err
+			}
+
+			return Result{}, fmt.Errorf("failed to store remote: %w", err)
+		}
+	}
+
+	if len(pulled) > 0 {
+		if err := s.Local.Write(pulled); err != nil {
+			return Result{}, fmt.Errorf("failed to write pulled files: %w", err)
+		}
+	}
+
+	if err := s.saveState(newState); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// LastSync returns when the files were last synced, zero if they never were
+func (s *Syncer) LastSync() (time.Time, error) {
+	state, err := s.loadState()
+	return state.SyncedAt, err
+}
+
+func (s *Syncer) loadState() (State, error) {
+	data, err := afero.ReadFile(s.FS, s.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return State{Files: map[string]string{}}, nil
+	}
+
+	if err != nil {
+		return State{}, err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("invalid sync state %s: %w", s.StateFile, err)
+	}
+
+	if state.Files == nil {
+		state.Files = map[string]string{}
+	}
+
+	return state, nil
+}
+
+func (s *Syncer) saveState(state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := s.FS.MkdirAll(filepath.Dir(s.StateFile), 0o700); err != nil {
+		return err
+	}
+
+	return afero.WriteFile(s.FS, s.StateFile, data, 0o600)
+}
+
+// hashOf returns the hash of the file content, empty if the file doesn't exist
+func hashOf(files map[string][]byte, name string) string {
+	data, ok := files[name]
+	if !ok {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func union(local, remote map[string][]byte, base map[string]string) map[string]struct{} {
+	names := make(map[string]struct{}, len(local)+len(remote)+len(base))
+	for name := range local {
+		names[name] = struct{}{}
+	}
+
+	for name := range remote {
+		names[name] = struct{}{}
+	}
+
+	for name := range base {
+		names[name] = struct{}{}
+	}
+
+	return names
+}
@@ -0,0 +1,190 @@
+package remotesync
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryBackend struct {
+	files    map[string][]byte
+	revision int
+	stores   int
+}
+
+func (b *memoryBackend) Fetch(context.Context) (Snapshot, error) {
+	return Snapshot{Files: maps.Clone(b.files), Revision: strings.Repeat("r", b.revision)}, nil
+}
+
+func (b *memoryBackend) Store(_ context.Context, files map[string][]byte, revision string) error {
+	if revision != strings.Repeat("r", b.revision) {
+		return ErrRemoteChanged
+	}
+
+	b.files = maps.Clone(files)
+	b.revision++
+	b.stores++
+
+	return nil
+}
+
+type memoryLocal map[string][]byte
+
+func (l memoryLocal) Files() (map[string][]byte, error) {
+	return maps.Clone(l), nil
+}
+
+func (l memoryLocal) Write(files map[string][]byte) error {
+	for name, data := range files {
+		if data == nil {
+			delete(l, name)
+			continue
+		}
+
+		l[name] = data
+	}
+
+	return nil
+}
+
+func (l memoryLocal) Accept(name string) bool {
+	return name != "README.md"
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	remote := &memoryBackend{files: map[string][]byte{"README.md": []byte("my config")}}
+
+	// two machines share the remote, each with its own state
+	laptop := memoryLocal{"settings.yaml": []byte("a"), "notes.json": []byte("{}")}
+	desktop := memoryLocal{"settings.yaml": []byte("a"), "theme.yaml": []byte("dark")}
+
+	laptopSyncer := &Syncer{Backend: remote, Local: laptop, FS: fs, StateFile: "/laptop/sync.json"}
+	desktopSyncer := &Syncer{Backend: remote, Local: desktop, FS: fs, StateFile: "/desktop/sync.json"}
+
+	result, err := laptopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pushed: []string{"notes.json", "settings.yaml"}}, result)
+	require.Equal(t, "my config", string(remote.files["README.md"]), "other files of the remote are kept")
+
+	result, err = desktopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pulled: []string{"notes.json"}, Pushed: []string{"theme.yaml"}}, result)
+	require.Equal(t, "{}", string(desktop["notes.json"]))
+	require.NotContains(t, desktop, "README.md")
+
+	result, err = laptopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pulled: []string{"theme.yaml"}}, result)
+
+	// a removed file is removed on the other machine
+	delete(laptop, "theme.yaml")
+	laptop["settings.yaml"] = []byte("b")
+
+	result, err = laptopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pushed: []string{"settings.yaml", "theme.yaml"}}, result)
+	require.NotContains(t, remote.files, "theme.yaml")
+
+	result, err = desktopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pulled: []string{"settings.yaml", "theme.yaml"}}, result)
+	require.NotContains(t, desktop, "theme.yaml")
+	require.Equal(t, "b", string(desktop["settings.yaml"]))
+
+	// changes on both sides are conflicts until a side is preferred
+	laptop["settings.yaml"] = []byte("laptop")
+	_, err = laptopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+
+	desktop["settings.yaml"] = []byte("desktop")
+	desktop["notes.json"] = []byte(`{"channels":{}}`)
+
+	stores := remote.stores
+	result, err = desktopSyncer.Sync(t.Context(), Options{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pushed: []string{"notes.json"}, Conflicts: []string{"settings.yaml"}}, result)
+	require.Equal(t, stores, remote.stores, "dry runs store nothing")
+
+	result, err = desktopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pushed: []string{"notes.json"}, Conflicts: []string{"settings.yaml"}}, result)
+	require.Equal(t, "desktop", string(desktop["settings.yaml"]))
+	require.Equal(t, "laptop", string(remote.files["settings.yaml"]))
+
+	result, err = desktopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{Conflicts: []string{"settings.yaml"}}, result, "conflicts stay until resolved")
+
+	result, err = desktopSyncer.Sync(t.Context(), Options{Prefer: PreferRemote})
+	require.NoError(t, err)
+	require.Equal(t, Result{Pulled: []string{"settings.yaml"}}, result)
+	require.Equal(t, "laptop", string(desktop["settings.yaml"]))
+
+	result, err = desktopSyncer.Sync(t.Context(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, Result{}, result)
+
+	last, err := desktopSyncer.LastSync()
+	require.NoError(t, err)
+	require.False(t, last.IsZero())
+}
+
+type racingBackend struct {
+	memoryBackend
+}
+
+func (b *racingBackend) Store(ctx context.Context, files map[string][]byte, revision string) error {
+	// another machine pushes between fetching and storing
+	b.revision++
+	return b.memoryBackend.Store(ctx, files, revision)
+}
+
+func TestSyncer_Sync_remoteChanged(t *testing.T) {
+	t.Parallel()
+
+	local := memoryLocal{"settings.yaml": []byte("a")}
+	syncer := &Syncer{Backend: &racingBackend{}, Local: local, FS: afero.NewMemMapFs(), StateFile: "/sync.json"}
+
+	_, err := syncer.Sync(t.Context(), Options{})
+	require.ErrorIs(t, err, ErrRemoteChanged)
+
+	exists, err := afero.Exists(syncer.FS, "/sync.json")
+	require.NoError(t, err)
+	require.False(t, exists, "the state is only saved after a successful sync")
+}
+
+func TestParsePrefer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		want Prefer
+		err  bool
+	}{
+		"":       {want: PreferNone},
+		"local":  {want: PreferLocal},
+		"remote": {want: PreferRemote},
+		"both":   {err: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePrefer(name)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
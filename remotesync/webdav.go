@@ -0,0 +1,205 @@
+package remotesync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// webDAVFileName is the file holding all synced files in the WebDAV directory
+const webDAVFileName = "chatuino-sync.json"
+
+// maxWebDAVFileSize is the size of the largest sync file read from the server
+const maxWebDAVFileSize = 50 << 20
+
+type webDAVDocument struct {
+	Files map[string][]byte `json:"files"`
+}
+
+// WebDAV keeps the files in a single JSON file in a directory of a WebDAV server. Concurrent syncs are detected with the ETag of the file.
+type WebDAV struct {
+	client   *http.Client
+	dir      string
+	file     string
+	username string
+	password string
+}
+
+// NewWebDAV returns a backend storing the files in the directory URL, which is created if it doesn't exist
+func NewWebDAV(client *http.Client, dir, username, password string) (*WebDAV, error) {
+	if err := ValidateWebDAV(dir); err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	file, err := url.JoinPath(dir, webDAVFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebDAV{client: client, dir: dir, file: file, username: username, password: password}, nil
+}
+
+// ValidateWebDAV checks the directory URL, which must be an http or https URL
+func ValidateWebDAV(dir string) error {
+	u, err := url.Parse(dir)
+	if err != nil {
+		return fmt.Errorf("invalid WebDAV URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("WebDAV URL must be an http or https URL")
+	}
+
+	return nil
+}
+
+func (w *WebDAV) Fetch(ctx context.Context) (Snapshot, error) {
+	resp, err := w.do(ctx, http.MethodGet, w.file, nil, nil)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Snapshot{Files: map[string][]byte{}}, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Snapshot{}, fmt.Errorf("WebDAV server answered with %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWebDAVFileSize+1))
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if len(body) > maxWebDAVFileSize {
+		return Snapshot{}, fmt.Errorf("%s is larger than %d MB", webDAVFileName, maxWebDAVFileSize>>20)
+	}
+
+	var doc webDAVDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid %s: %w", webDAVFileName, err)
+	}
+
+	if doc.Files == nil {
+		doc.Files = map[string][]byte{}
+	}
+
+	return Snapshot{Files: doc.Files, Revision: revisionOf(resp.Header.Get("ETag"), body)}, nil
+}
+
+func (w *WebDAV) Store(ctx context.Context, files map[string][]byte, revision string) error {
+	body, err := json.Marshal(webDAVDocument{Files: files})
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+
+	switch {
+	case revision == "":
+		header.Set("If-None-Match", "*")
+	case strings.HasPrefix(revision, contentRevisionPrefix):
+		// without an ETag the content is compared right before replacing it
+		current, err := w.Fetch(ctx)
+		if err != nil {
+			return err
+		}
+
+		if current.Revision != revision {
+			return ErrRemoteChanged
+		}
+	default:
+		header.Set("If-Match", revision)
+	}
+
+	resp, err := w.do(ctx, http.MethodPut, w.file, header, body)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	// the directory doesn't exist yet
+	if resp.StatusCode == http.StatusConflict {
+		if err := w.createDir(ctx); err != nil {
+			return err
+		}
+
+		if resp, err = w.do(ctx, http.MethodPut, w.file, header, body); err != nil {
+			return err
+		}
+
+		resp.Body.Close()
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return ErrRemoteChanged
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("WebDAV server answered with %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (w *WebDAV) createDir(ctx context.Context) error {
+	resp, err := w.do(ctx, "MKCOL", w.dir, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to create WebDAV directory: server answered with %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (w *WebDAV) do(ctx context.Context, method, target string, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if w.username != "" || w.password != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+
+	return w.client.Do(req)
+}
+
+// contentRevisionPrefix marks revisions of servers without ETags, which are the hash of the content
+const contentRevisionPrefix = "sha256:"
+
+func revisionOf(etag string, body []byte) string {
+	if etag != "" {
+		return etag
+	}
+
+	sum := sha256.Sum256(body)
+	return contentRevisionPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+package remotesync
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// webDAVServer keeps a single file in memory like a WebDAV server, the directory is created by MKCOL
+type webDAVServer struct {
+	m       sync.Mutex
+	dir     bool
+	content []byte
+	version int
+	etags   bool
+}
+
+func (s *webDAVServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if user, password, _ := r.BasicAuth(); user != "julez" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%d"`, s.version)
+
+	switch {
+	case r.Method == "MKCOL" && r.URL.Path == "/dav/chatuino":
+		s.dir = true
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && r.URL.Path == "/dav/chatuino/chatuino-sync.json":
+		if s.content == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if s.etags {
+			w.Header().Set("ETag", etag)
+		}
+
+		_, _ = w.Write(s.content)
+	case r.Method == http.MethodPut && r.URL.Path == "/dav/chatuino/chatuino-sync.json":
+		if !s.dir {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		if match := r.Header.Get("If-Match"); match != "" && (s.content == nil || match != etag) ||
+			r.Header.Get("If-None-Match") == "*" && s.content != nil {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		s.content, _ = io.ReadAll(r.Body)
+		s.version++
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *webDAVServer) change() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.content = []byte(`{"files":{"notes.json":""}}`)
+	s.version++
+}
+
+func TestWebDAV(t *testing.T) {
+	t.Parallel()
+
+	for _, etags := range []bool{true, false} {
+		t.Run(fmt.Sprintf("etags %t", etags), func(t *testing.T) {
+			t.Parallel()
+
+			server := &webDAVServer{etags: etags}
+			srv := httptest.NewServer(server)
+			t.Cleanup(srv.Close)
+
+			webDAV, err := NewWebDAV(srv.Client(), srv.URL+"/dav/chatuino", "julez", "secret")
+			require.NoError(t, err)
+
+			snapshot, err := webDAV.Fetch(t.Context())
+			require.NoError(t, err)
+			require.Empty(t, snapshot.Files)
+			require.Empty(t, snapshot.Revision)
+
+			files := map[string][]byte{"settings.yaml": []byte("chat: {}"), "scripts/a/main.lua": []byte("-- a")}
+			require.NoError(t, webDAV.Store(t.Context(), files, snapshot.Revision), "the directory is created")
+			require.ErrorIs(t, webDAV.Store(t.Context(), files, snapshot.Revision), ErrRemoteChanged, "the file was created in the meantime")
+
+			snapshot, err = webDAV.Fetch(t.Context())
+			require.NoError(t, err)
+			require.Equal(t, files, snapshot.Files)
+			require.NotEmpty(t, snapshot.Revision)
+
+			require.NoError(t, webDAV.Store(t.Context(), map[string][]byte{}, snapshot.Revision))
+			require.ErrorIs(t, webDAV.Store(t.Context(), files, snapshot.Revision), ErrRemoteChanged)
+
+			snapshot, err = webDAV.Fetch(t.Context())
+			require.NoError(t, err)
+
+			server.change()
+			require.ErrorIs(t, webDAV.Store(t.Context(), files, snapshot.Revision), ErrRemoteChanged)
+		})
+	}
+
+	_, err := NewWebDAV(nil, "ftp://example.com", "", "")
+	require.ErrorContains(t, err, "http or https URL")
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julez-dev/chatuino/replay"
+	"github.com/julez-dev/chatuino/ui/mainui"
+	"github.com/urfave/cli/v3"
+)
+
+var replayCMD = &cli.Command{
+	Name:      "replay",
+	Usage:     "Replay a recorded chat log in a read only tab",
+	ArgsUsage: "<logfile>",
+	Description: "Replay the messages of a JSON export (chatuino export --format json) or of raw IRC lines with the tmi-sent-ts tag " +
+		"at the pace they were sent. Messages are rendered like live chat, with emotes, badges and scripts. " +
+		"The session is neither restored nor saved.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "speed",
+			Usage: "Replay speed: 1x, 2x or max, any positive factor like 0.5x is accepted",
+			Value: "1x",
+		},
+		&cli.StringFlag{
+			Name:  "start",
+			Usage: "Position after the first message the replay starts at, like 10m or 1:02:03",
+		},
+	},
+	Action: func(ctx context.Context, command *cli.Command) error {
+		path := command.Args().First()
+		if path == "" {
+			return fmt.Errorf("the log file to replay is required")
+		}
+
+		speed, err := replay.ParseSpeed(command.String("speed"))
+		if err != nil {
+			return err
+		}
+
+		var offset time.Duration
+		if start := command.String("start"); start != "" {
+			if offset, err = replay.ParsePosition(start); err != nil {
+				return fmt.Errorf("invalid --start: %w", err)
+			}
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open log: %w", err)
+		}
+
+		messages, err := replay.Load(f)
+		_ = f.Close()
+
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		return runChatuino(ctx, command, &mainui.Replay{
+			Name:     filepath.Base(path),
+			Messages: messages,
+			Speed:    speed,
+			Offset:   offset,
+		})
+	},
+}
@@ -0,0 +1,219 @@
+// Package replay loads recorded chat logs and paces their messages, used by the replay command.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julez-dev/chatuino/chatexport"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// Speed is the factor messages are replayed faster than recorded, Max replays without delay
+type Speed float64
+
+const Max Speed = 0
+
+// ParseSpeed parses a speed like 1x, 2, 0.5x or max
+func ParseSpeed(value string) (Speed, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "max" {
+		return Max, nil
+	}
+
+	factor, err := strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+	if err != nil || factor <= 0 {
+		return 0, fmt.Errorf("invalid speed %q, must be a positive factor like 1x or 2x or max", value)
+	}
+
+	return Speed(factor), nil
+}
+
+func (s Speed) String() string {
+	if s == Max {
+		return "max"
+	}
+
+	return strconv.FormatFloat(float64(s), 'f', -1, 64) + "x"
+}
+
+// Delay returns the time to wait between two messages sent at prev and next
+func (s Speed) Delay(prev, next time.Time) time.Duration {
+	if s == Max || !next.After(prev) {
+		return 0
+	}
+
+	return time.Duration(float64(next.Sub(prev)) / float64(s))
+}
+
+// ParsePosition parses a position in a replay, like 1h2m3s, 1:02:03 or 62:03
+func ParsePosition(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid position %q, expected a duration like 1h2m3s or a time like 1:02:03", value)
+	}
+
+	var position time.Duration
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid position %q, expected a duration like 1h2m3s or a time like 1:02:03", value)
+		}
+
+		position = position*60 + time.Duration(n)
+	}
+
+	return position * time.Second, nil
+}
+
+// Message is a recorded chat message with the time it was sent
+type Message struct {
+	SentAt  time.Time
+	Message twitchirc.IRCer
+}
+
+// Load reads a chat log, either a JSON export of chatuino export or raw IRC lines with the tmi-sent-ts tag.
+// IRC lines without the tag, like PING or ROOMSTATE, are skipped. The messages are returned oldest first.
+func Load(r io.Reader) ([]Message, error) {
+	br := bufio.NewReader(r)
+
+	// JSON exports are an array, IRC lines start with their tags
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []Message
+	if first == '[' {
+		messages, err = loadJSON(br)
+	} else {
+		messages, err = loadIRC(br)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("the log contains no messages")
+	}
+
+	slices.SortStableFunc(messages, func(a, b Message) int {
+		return a.SentAt.Compare(b.SentAt)
+	})
+
+	return messages, nil
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return 0, fmt.Errorf("the log is empty")
+			}
+
+			return 0, err
+		}
+
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0], nil
+		}
+
+		_, _ = br.ReadByte()
+	}
+}
+
+func loadJSON(r io.Reader) ([]Message, error) {
+	var exported []chatexport.Message
+	if err := json.NewDecoder(r).Decode(&exported); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON export: %w", err)
+	}
+
+	messages := make([]Message, 0, len(exported))
+	for _, e := range exported {
+		messages = append(messages, Message{
+			SentAt: e.SentAt,
+			Message: &twitchirc.PrivateMessage{
+				ID:              e.ID,
+				TMISentTS:       e.SentAt,
+				ChannelUserName: e.Channel,
+				UserID:          e.UserID,
+				LoginName:       e.Login,
+				DisplayName:     e.DisplayName,
+				Message:         e.Text,
+			},
+		})
+	}
+
+	return messages, nil
+}
+
+func loadIRC(r io.Reader) ([]Message, error) {
+	var messages []Message
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // lines with many tags and emotes exceed the default limit
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		sentAt, ok := sentTime(line)
+		if !ok {
+			continue
+		}
+
+		msg, err := twitchirc.ParseIRC(line)
+		if errors.Is(err, twitchirc.ErrUnhandledCommand) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse line %d: %w", lineNum, err)
+		}
+
+		messages = append(messages, Message{SentAt: sentAt, Message: msg})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+
+	return messages, nil
+}
+
+// sentTime returns the time of the tmi-sent-ts tag of a raw line
+func sentTime(line string) (time.Time, bool) {
+	tags, _ := twitchirc.SplitTags(line)
+	for _, tag := range tags {
+		if tag.Key != "tmi-sent-ts" {
+			continue
+		}
+
+		ms, err := strconv.ParseInt(tag.Value, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.UnixMilli(ms), true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,149 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpeed(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in      string
+		want    Speed
+		wantErr bool
+	}{
+		"1x":       {in: "1x", want: 1},
+		"2x":       {in: "2X", want: 2},
+		"factor":   {in: "0.5", want: 0.5},
+		"max":      {in: " max ", want: Max},
+		"zero":     {in: "0x", wantErr: true},
+		"negative": {in: "-2x", wantErr: true},
+		"invalid":  {in: "fast", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSpeed(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSpeed_Delay(t *testing.T) {
+	t.Parallel()
+
+	prev := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	next := prev.Add(4 * time.Second)
+
+	require.Equal(t, 4*time.Second, Speed(1).Delay(prev, next))
+	require.Equal(t, 2*time.Second, Speed(2).Delay(prev, next))
+	require.Equal(t, time.Duration(0), Max.Delay(prev, next))
+	require.Equal(t, time.Duration(0), Speed(1).Delay(next, prev), "out of order messages are not delayed")
+	require.Equal(t, "2x", Speed(2).String())
+	require.Equal(t, "max", Max.String())
+}
+
+func TestParsePosition(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		"duration": {in: "1h2m3s", want: time.Hour + 2*time.Minute + 3*time.Second},
+		"clock":    {in: "1:02:03", want: time.Hour + 2*time.Minute + 3*time.Second},
+		"minutes":  {in: "62:03", want: 62*time.Minute + 3*time.Second},
+		"negative": {in: "-5m", wantErr: true},
+		"seconds":  {in: "90", wantErr: true},
+		"invalid":  {in: "1:xx", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePosition(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		messages, err := Load(strings.NewReader(`
+		[
+			{"id": "2", "sent_at": "2026-03-10T12:00:05Z", "channel": "lirik", "user_id": "11", "login": "other", "display_name": "Other", "text": "second"},
+			{"id": "1", "sent_at": "2026-03-10T12:00:00Z", "channel": "lirik", "user_id": "10", "login": "viewer", "display_name": "Viewer", "text": "first"}
+		]`))
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+
+		first := messages[0].Message.(*twitchirc.PrivateMessage)
+		require.Equal(t, "first", first.Message)
+		require.Equal(t, "lirik", first.ChannelUserName)
+		require.Equal(t, "Viewer", first.DisplayName)
+		require.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), messages[0].SentAt)
+	})
+
+	t.Run("irc", func(t *testing.T) {
+		t.Parallel()
+
+		messages, err := Load(strings.NewReader(strings.Join([]string{
+			"PING :tmi.twitch.tv",
+			"@emote-only=0;room-id=22484632 :tmi.twitch.tv ROOMSTATE #lirik",
+			"@badges=;color=#FF0000;display-name=Viewer;emotes=;id=a1;mod=0;room-id=22484632;subscriber=0;tmi-sent-ts=1773144000000;turbo=0;user-id=10;user-type= :viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #lirik :hello chat",
+			"",
+			"@login=viewer;room-id=22484632;target-msg-id=a1;tmi-sent-ts=1773144002000 :tmi.twitch.tv CLEARMSG #lirik :hello chat",
+		}, "\n")))
+		require.NoError(t, err)
+		require.Len(t, messages, 2, "lines without tmi-sent-ts are skipped")
+
+		privMsg := messages[0].Message.(*twitchirc.PrivateMessage)
+		require.Equal(t, "hello chat", privMsg.Message)
+		require.Equal(t, "22484632", privMsg.RoomID)
+		require.Equal(t, time.UnixMilli(1773144000000), messages[0].SentAt)
+
+		require.IsType(t, &twitchirc.ClearMessage{}, messages[1].Message)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Load(strings.NewReader("  \n"))
+		require.Error(t, err)
+
+		_, err = Load(strings.NewReader("[]"))
+		require.ErrorContains(t, err, "no messages")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Load(strings.NewReader(`[{"id": 1}]`))
+		require.Error(t, err)
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/adrg/xdg"
 	"github.com/spf13/afero"
 )
 
@@ -18,16 +17,22 @@ const (
 )
 
 type AppState struct {
-	Tabs []TabState `json:"tabs"`
+	Tabs         []TabState `json:"tabs"`
+	InputHistory []string   `json:"input_history,omitempty"` // shared between all tabs, when enabled
 }
 
 type TabState struct {
-	IsLocalUnique bool   `json:"is_local_unique"`
-	IsLocalSub    bool   `json:"is_local_sub"`
-	Channel       string `json:"channel"`
-	IsFocused     bool   `json:"is_focused"`
-	IdentityID    string `json:"identity_id"`
-	Kind          int    `json:"kind"`
+	IsLocalUnique bool     `json:"is_local_unique"`
+	IsLocalSub    bool     `json:"is_local_sub"`
+	Channel       string   `json:"channel"`
+	IsFocused     bool     `json:"is_focused"`
+	IdentityID    string   `json:"identity_id"`
+	Kind          int      `json:"kind"`
+	InputHistory  []string `json:"input_history,omitempty"`
+	Draft         string   `json:"draft,omitempty"`        // unsent message input
+	LastReadID    string   `json:"last_read_id,omitempty"` // ID of the message read last, used to place the new messages separator
+	HangulInput   bool     `json:"hangul_input,omitempty"` // typed Hangul jamo are composed into syllables
+	Unopened      bool     `json:"unopened,omitempty"`     // tab was not restored, only kept for its draft and input history
 }
 
 type AppStateManager struct {
@@ -39,7 +44,7 @@ func NewAppStateManager(fs afero.Fs) *AppStateManager {
 }
 
 func (a *AppStateManager) SaveAppState(state AppState) error {
-	f, err := openCreateDataFile(a.fs, stateFileName)
+	f, err := openCreateStateFile(a.fs, stateFileName)
 	if err != nil {
 		return err
 	}
@@ -65,7 +70,7 @@ func (a *AppStateManager) SaveAppState(state AppState) error {
 }
 
 func (a *AppStateManager) LoadAppState() (AppState, error) {
-	f, err := openCreateDataFile(a.fs, stateFileName)
+	f, err := openCreateStateFile(a.fs, stateFileName)
 	if err != nil {
 		return AppState{}, err
 	}
@@ -90,25 +95,13 @@ func (a *AppStateManager) LoadAppState() (AppState, error) {
 	return state, nil
 }
 
-func openCreateFile(fs afero.Fs, base string, file string) (afero.File, error) {
-	// ensure dir config dir exists
-	configDirChatuino := filepath.Join(base, chatuinoConfigDir)
-	err := os.Mkdir(configDirChatuino, 0o755)
-	var alreadyExistsError bool
-
-	if err != nil {
-		if errors.Is(err, afero.ErrFileExists) {
-			alreadyExistsError = true
-		} else {
-			return nil, err
-		}
-	}
-
-	if err != nil && !alreadyExistsError {
+func openCreateFile(fs afero.Fs, dir string, file string) (afero.File, error) {
+	// ensure the directory exists
+	if err := fs.MkdirAll(dir, 0o755); err != nil {
 		return nil, err
 	}
 
-	path := filepath.Join(configDirChatuino, file)
+	path := filepath.Join(dir, file)
 
 	f, err := fs.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
 	if err != nil {
@@ -119,14 +112,19 @@ func openCreateFile(fs afero.Fs, base string, file string) (afero.File, error) {
 }
 
 func openCreateConfigFile(fs afero.Fs, file string) (afero.File, error) {
-	configDir, err := os.UserConfigDir() // get users config directory, depending on OS
+	paths, err := ActivePaths()
 	if err != nil {
 		return nil, err
 	}
 
-	return openCreateFile(fs, configDir, file)
+	return openCreateFile(fs, paths.Config, file)
 }
 
-func openCreateDataFile(fs afero.Fs, file string) (afero.File, error) {
-	return openCreateFile(fs, xdg.DataHome, file)
+func openCreateStateFile(fs afero.Fs, file string) (afero.File, error) {
+	paths, err := ActivePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	return openCreateFile(fs, paths.State, file)
 }
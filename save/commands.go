@@ -0,0 +1,205 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const commandsFileName = "commands.json"
+
+// Quote is a quote saved with /quote add
+type Quote struct {
+	Text      string    `json:"text"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// channelCommands are the quotes and counters of a channel
+type channelCommands struct {
+	Quotes   []Quote        `json:"quotes,omitempty"`
+	Counters map[string]int `json:"counters,omitempty"` // keyed by the lower case counter name
+}
+
+// commandsFile is the content of the commands file, keyed by the lower case channel login
+type commandsFile struct {
+	Channels map[string]*channelCommands `json:"channels,omitempty"`
+}
+
+// CommandStore persists the quotes and counters of the local /quote and /count commands per channel.
+// The file is read once and written on every change.
+type CommandStore struct {
+	fs afero.Fs
+
+	m    sync.Mutex
+	data *commandsFile // nil until loaded
+}
+
+func NewCommandStore(fs afero.Fs) *CommandStore {
+	return &CommandStore{fs: fs}
+}
+
+// Quotes returns the quotes of a channel, the quote number is the index + 1
+func (s *CommandStore) Quotes(channel string) ([]Quote, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(s.channel(channel).Quotes), nil
+}
+
+// AddQuote saves a quote of a channel and returns its number
+func (s *CommandStore) AddQuote(channel string, quote Quote) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return 0, err
+	}
+
+	c := s.channel(channel)
+	c.Quotes = append(c.Quotes, quote)
+	number := len(c.Quotes)
+
+	return number, s.save()
+}
+
+// RemoveQuote removes the quote at index from a channel, the numbers of the following quotes move up
+func (s *CommandStore) RemoveQuote(channel string, index int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	c := s.channel(channel)
+	if index < 0 || index >= len(c.Quotes) {
+		return fmt.Errorf("quote %d not found", index+1)
+	}
+
+	c.Quotes = slices.Delete(c.Quotes, index, index+1)
+
+	return s.save()
+}
+
+// Counter returns the value of a counter of a channel, counters which were never changed are 0
+func (s *CommandStore) Counter(channel, name string) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return 0, err
+	}
+
+	return s.channel(channel).Counters[strings.ToLower(name)], nil
+}
+
+// ChangeCounter sets a counter of a channel to the result of change, called with the current value, and returns the new value
+func (s *CommandStore) ChangeCounter(channel, name string, change func(value int) int) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return 0, err
+	}
+
+	c := s.channel(channel)
+	if c.Counters == nil {
+		c.Counters = map[string]int{}
+	}
+
+	name = strings.ToLower(name)
+	value := change(c.Counters[name])
+	c.Counters[name] = value
+
+	return value, s.save()
+}
+
+// channel returns the quotes and counters of a channel, creating them if they don't exist
+func (s *CommandStore) channel(channel string) *channelCommands {
+	channel = strings.ToLower(channel)
+
+	c, ok := s.data.Channels[channel]
+	if !ok {
+		c = &channelCommands{}
+		s.data.Channels[channel] = c
+	}
+
+	return c
+}
+
+func (s *CommandStore) load() error {
+	if s.data != nil {
+		return nil
+	}
+
+	f, err := openCreateStateFile(s.fs, commandsFileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	commands := commandsFile{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &commands); err != nil {
+			syntaxErr := &json.SyntaxError{}
+			if !errors.As(err, &syntaxErr) {
+				return err
+			}
+		}
+	}
+
+	if commands.Channels == nil {
+		commands.Channels = map[string]*channelCommands{}
+	}
+
+	s.data = &commands
+	return nil
+}
+
+func (s *CommandStore) save() error {
+	f, err := openCreateStateFile(s.fs, commandsFileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	// channels only looked up are not written
+	for channel, c := range s.data.Channels {
+		if len(c.Quotes) == 0 && len(c.Counters) == 0 {
+			delete(s.data.Channels, channel)
+		}
+	}
+
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = f.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,52 @@
+package save
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandStore(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewCommandStore(fs)
+
+	number, err := store.AddQuote("Lirik", Quote{Text: "first", AddedBy: "julez", CreatedAt: at})
+	require.NoError(t, err)
+	require.Equal(t, 1, number)
+
+	number, err = store.AddQuote("lirik", Quote{Text: "second", AddedBy: "julez", CreatedAt: at})
+	require.NoError(t, err)
+	require.Equal(t, 2, number)
+
+	value, err := store.ChangeCounter("lirik", "Deaths", func(value int) int { return value + 1 })
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	// quotes and counters are read from the file by a new store
+	store = NewCommandStore(fs)
+
+	quotes, err := store.Quotes("LIRIK")
+	require.NoError(t, err)
+	require.Equal(t, []Quote{{Text: "first", AddedBy: "julez", CreatedAt: at}, {Text: "second", AddedBy: "julez", CreatedAt: at}}, quotes)
+
+	value, err = store.Counter("lirik", "deaths")
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	require.NoError(t, store.RemoveQuote("lirik", 0))
+	require.ErrorContains(t, store.RemoveQuote("lirik", 1), "quote 2 not found")
+
+	quotes, err = NewCommandStore(fs).Quotes("lirik")
+	require.NoError(t, err)
+	require.Equal(t, []Quote{{Text: "second", AddedBy: "julez", CreatedAt: at}}, quotes)
+
+	value, err = store.Counter("sodapoppin", "deaths")
+	require.NoError(t, err)
+	require.Zero(t, value)
+}
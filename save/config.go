@@ -0,0 +1,148 @@
+package save
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// Config contains the user configuration read from the settings, theme and keymap files
+type Config struct {
+	Settings       Settings
+	Themes         ThemeSet
+	Keymap         KeyMap
+	ChannelKeymaps ChannelKeyMaps
+}
+
+// ConfigFromDisk reads the settings, theme and keymap files. The keymap is built for the profile selected in the settings.
+func ConfigFromDisk() (Config, error) {
+	settings, err := SettingsFromDisk()
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read settings file: %w", err)
+	}
+
+	themes, err := ThemeSetFromDisk()
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read theme file: %w", err)
+	}
+
+	keymap, channelKeymaps, err := CreateReadKeyMaps(settings.KeymapProfile)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read keymap file: %w", err)
+	}
+
+	return Config{
+		Settings:       settings,
+		Themes:         themes,
+		Keymap:         keymap,
+		ChannelKeymaps: channelKeymaps,
+	}, nil
+}
+
+type configFileState struct {
+	exists  bool
+	size    int64
+	modTime int64 // unix nano
+}
+
+// ConfigWatcher detects changes to the config files by polling their size and modification time.
+type ConfigWatcher struct {
+	fs  afero.Fs
+	dir string
+
+	m       sync.Mutex
+	last    map[string]configFileState // state seen by the last poll
+	applied map[string]configFileState // state of the last reported change
+}
+
+func NewConfigWatcher() (*ConfigWatcher, error) {
+	paths, err := ActivePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfigWatcher(afero.NewOsFs(), paths.Config), nil
+}
+
+func newConfigWatcher(fs afero.Fs, dir string) *ConfigWatcher {
+	w := &ConfigWatcher{
+		fs:  fs,
+		dir: dir,
+	}
+
+	w.last = w.snapshot()
+	w.applied = w.last
+
+	return w
+}
+
+func (w *ConfigWatcher) snapshot() map[string]configFileState {
+	states := map[string]configFileState{}
+
+	for _, name := range []string{settingsFileName, themeFileName, keyMapFileName} {
+		stat, err := w.fs.Stat(filepath.Join(w.dir, name))
+		if err != nil {
+			states[name] = configFileState{}
+			continue
+		}
+
+		states[name] = configFileState{
+			exists:  true,
+			size:    stat.Size(),
+			modTime: stat.ModTime().UnixNano(),
+		}
+	}
+
+	// scripts are reloaded together with the config files
+	scripts, _ := afero.ReadDir(w.fs, filepath.Join(w.dir, scriptDirName))
+	for _, stat := range scripts {
+		if stat.IsDir() {
+			continue
+		}
+
+		states[scriptDirName+"/"+stat.Name()] = configFileState{
+			exists:  true,
+			size:    stat.Size(),
+			modTime: stat.ModTime().UnixNano(),
+		}
+	}
+
+	return states
+}
+
+// Changed reports if a config file was created, modified or removed since the last reported change.
+// A change is only reported once the files were left untouched for one poll, since editors may write files in multiple steps.
+func (w *ConfigWatcher) Changed() bool {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	current := w.snapshot()
+
+	stable := maps.Equal(current, w.last)
+	w.last = current
+
+	if !stable || maps.Equal(current, w.applied) {
+		return false
+	}
+
+	w.applied = current
+
+	return true
+}
+
+// Sync marks the current state of the files as applied, used after Chatuino changed the files itself
+func (w *ConfigWatcher) Sync() {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.last = w.snapshot()
+	w.applied = w.last
+}
+
+// Load reads the config files, see ConfigFromDisk
+func (w *ConfigWatcher) Load() (Config, error) {
+	return ConfigFromDisk()
+}
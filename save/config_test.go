@@ -0,0 +1,47 @@
+package save
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigWatcher_Changed(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	dir := "/config/chatuino"
+	settingsPath := filepath.Join(dir, settingsFileName)
+
+	require.NoError(t, afero.WriteFile(fs, settingsPath, []byte("vertical_tab_list: false\n"), 0o600))
+
+	w := newConfigWatcher(fs, dir)
+	require.False(t, w.Changed())
+
+	require.NoError(t, afero.WriteFile(fs, settingsPath, []byte("vertical_tab_list: true\n"), 0o600))
+	require.NoError(t, fs.Chtimes(settingsPath, time.Now(), time.Now().Add(time.Second)))
+
+	// the change is reported once the file stayed the same for one poll
+	require.False(t, w.Changed())
+	require.True(t, w.Changed())
+	require.False(t, w.Changed())
+
+	// creating a file is a change as well
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, themeFileName), []byte("active: dark\n"), 0o600))
+	require.False(t, w.Changed())
+	require.True(t, w.Changed())
+
+	// so is adding a script
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, scriptDirName, "shout.star"), []byte("on_message(print)\n"), 0o600))
+	require.False(t, w.Changed())
+	require.True(t, w.Changed())
+
+	// changes made by Chatuino itself are not reported
+	require.NoError(t, afero.WriteFile(fs, settingsPath, []byte("vertical_tab_list: false\n"), 0o600))
+	w.Sync()
+	require.False(t, w.Changed())
+	require.False(t, w.Changed())
+}
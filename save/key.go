@@ -1,6 +1,7 @@
 package save
 
 import (
+	"fmt"
 	"io"
 	"reflect"
 	"strings"
@@ -14,45 +15,117 @@ const (
 	keyMapFileName = "keymap.yaml"
 )
 
+// Built-in keymap profiles
+const (
+	KeyMapProfileDefault = "default"
+	KeyMapProfileVim     = "vim"
+	KeyMapProfileEmacs   = "emacs"
+)
+
 var (
 	_ yaml.Marshaler   = (*KeyMap)(nil)
 	_ yaml.Unmarshaler = (*KeyMap)(nil)
 )
 
+// KeyMap contains all rebindable key bindings. The section tag groups the bindings in the help screen.
 type KeyMap struct {
 	// General
-	Up      key.Binding `yaml:"up"`
-	Down    key.Binding `yaml:"down"`
-	Escape  key.Binding `yaml:"escape"`
-	Confirm key.Binding `yaml:"confirm"`
-	Help    key.Binding `yaml:"help"`
+	Up      key.Binding `yaml:"up" section:"General"`
+	Down    key.Binding `yaml:"down" section:"General"`
+	Escape  key.Binding `yaml:"escape" section:"General"`
+	Confirm key.Binding `yaml:"confirm" section:"General"`
+	Help    key.Binding `yaml:"help" section:"General"`
+	Leader  key.Binding `yaml:"leader" section:"General"` // first key replaces <leader> in key sequences
 
 	// App Binds
-	Quit       key.Binding `yaml:"quit"`
-	Create     key.Binding `yaml:"create"`
-	Remove     key.Binding `yaml:"remove"`
-	CloseTab   key.Binding `yaml:"close_tab"`
-	DumpScreen key.Binding `yaml:"dump_screen"` // used by lists, and join input type switch
+	Quit       key.Binding `yaml:"quit" section:"App Binds"`
+	Create     key.Binding `yaml:"create" section:"App Binds"`
+	Remove     key.Binding `yaml:"remove" section:"App Binds"`
+	CloseTab   key.Binding `yaml:"close_tab" section:"App Binds"`
+	DumpScreen key.Binding `yaml:"dump_screen" section:"App Binds"` // used by lists, and join input type switch
+
+	ToggleFollowedSidebar key.Binding `yaml:"toggle_followed_sidebar" section:"App Binds"`
+	Settings              key.Binding `yaml:"settings" section:"App Binds"`
+	ToggleStats           key.Binding `yaml:"toggle_stats" section:"App Binds"`
+	DebugLog              key.Binding `yaml:"debug_log" section:"App Binds"`
+	ToggleSounds          key.Binding `yaml:"toggle_sounds" section:"App Binds"`
+	DismissNotice         key.Binding `yaml:"dismiss_notice" section:"App Binds"`
+	OpenLiveChannel       key.Binding `yaml:"open_live_channel" section:"App Binds"`    // opens the channel of the go live notification
+	WatchLiveChannel      key.Binding `yaml:"watch_live_channel" section:"App Binds"`   // starts the player for the channel of the go live notification
+	JumpToNotification    key.Binding `yaml:"jump_to_notification" section:"App Binds"` // opens the source of the notification shown in the notification tray
+	DismissNotification   key.Binding `yaml:"dismiss_notification" section:"App Binds"` // removes the notification shown in the notification tray
 
 	// Tab Binds
-	Next     key.Binding `yaml:"next"`
-	Previous key.Binding `yaml:"previous"`
+	Next     key.Binding `yaml:"next" section:"Tab Binds"`
+	Previous key.Binding `yaml:"previous" section:"Tab Binds"`
+	GoToTab  key.Binding `yaml:"go_to_tab" section:"Tab Binds"` // the n-th key jumps to the n-th tab
 
 	// Chat Binds
-	InsertMode   key.Binding `yaml:"insert_mode"`
-	InspectMode  key.Binding `yaml:"inspect_mode"`
-	ChatPopUp    key.Binding `yaml:"chat_pop_up"`
-	ChannelPopUp key.Binding `yaml:"channel_pop_up"`
-	GoToTop      key.Binding `yaml:"go_to_top"`
-	GoToBottom   key.Binding `yaml:"go_to_bottom"`
-	DumpChat     key.Binding `yaml:"dump_chat"`
-	QuickTimeout key.Binding `yaml:"quick_timeout"`
-	CopyMessage  key.Binding `yaml:"copy_message"`
-	SearchMode   key.Binding `yaml:"search_mode"`
-	QuickSent    key.Binding `yaml:"quick_sent"`
+	InsertMode    key.Binding `yaml:"insert_mode" section:"Chat Binds"`
+	InspectMode   key.Binding `yaml:"inspect_mode" section:"Chat Binds"`
+	ChatPopUp     key.Binding `yaml:"chat_pop_up" section:"Chat Binds"`
+	ChannelPopUp  key.Binding `yaml:"channel_pop_up" section:"Chat Binds"`
+	GoToTop       key.Binding `yaml:"go_to_top" section:"Chat Binds"`
+	GoToBottom    key.Binding `yaml:"go_to_bottom" section:"Chat Binds"`
+	DumpChat      key.Binding `yaml:"dump_chat" section:"Chat Binds"`
+	QuickTimeout  key.Binding `yaml:"quick_timeout" section:"Chat Binds"`
+	CopyMessage   key.Binding `yaml:"copy_message" section:"Chat Binds"`
+	SearchMode    key.Binding `yaml:"search_mode" section:"Chat Binds"`
+	SearchUp      key.Binding `yaml:"search_up" section:"Chat Binds"`
+	SearchDown    key.Binding `yaml:"search_down" section:"Chat Binds"`
+	QuickSent     key.Binding `yaml:"quick_sent" section:"Chat Binds"`
+	EmoteOverview key.Binding `yaml:"emote_overview" section:"Chat Binds"`
+
+	CopyToClipboard         key.Binding `yaml:"copy_to_clipboard" section:"Chat Binds"`
+	CopyUsernameToClipboard key.Binding `yaml:"copy_username_to_clipboard" section:"Chat Binds"`
+	CopyLinkToClipboard     key.Binding `yaml:"copy_link_to_clipboard" section:"Chat Binds"`
+	LinkHintMode            key.Binding `yaml:"link_hint_mode" section:"Chat Binds"`
+	VisualMode              key.Binding `yaml:"visual_mode" section:"Chat Binds"`
+	SaveSelection           key.Binding `yaml:"save_selection" section:"Chat Binds"`
+	WatchStream             key.Binding `yaml:"watch_stream" section:"Chat Binds"`
+	ToggleBlockUser         key.Binding `yaml:"toggle_block_user" section:"Chat Binds"`
+	RetryMessage            key.Binding `yaml:"retry_message" section:"Chat Binds"`
+	ReconnectChat           key.Binding `yaml:"reconnect_chat" section:"Chat Binds"`
+	TranslateMessage        key.Binding `yaml:"translate_message" section:"Chat Binds"`
+	OpenThread              key.Binding `yaml:"open_thread" section:"Chat Binds"`
+
+	// Input Binds
+	AcceptSuggestion key.Binding `yaml:"accept_suggestion" section:"Input Binds"`
+	NextSuggestion   key.Binding `yaml:"next_suggestion" section:"Input Binds"`
+	PrevSuggestion   key.Binding `yaml:"prev_suggestion" section:"Input Binds"`
+	PrevCompletion   key.Binding `yaml:"prev_completion" section:"Input Binds"`
+	ReverseSearch    key.Binding `yaml:"reverse_search" section:"Input Binds"`
+	CorrectSpelling  key.Binding `yaml:"correct_spelling" section:"Input Binds"`
+	AddToDictionary  key.Binding `yaml:"add_to_dictionary" section:"Input Binds"`
+	HangulInput      key.Binding `yaml:"hangul_input" section:"Input Binds"` // toggles composing typed Hangul jamo for the tab
 
 	// Account Binds
-	MarkLeader key.Binding `yaml:"mark_leader"`
+	MarkLeader key.Binding `yaml:"mark_leader" section:"Account Binds"`
+}
+
+// KeyMapSection is a named group of key bindings
+type KeyMapSection struct {
+	Name     string
+	Bindings []key.Binding
+}
+
+// Sections groups all bindings by their section, in the order the sections are declared
+func (c KeyMap) Sections() []KeyMapSection {
+	var sections []KeyMapSection
+
+	val := reflect.ValueOf(c)
+	for i := 0; i < val.NumField(); i++ {
+		name := val.Type().Field(i).Tag.Get("section")
+		bind := val.Field(i).Interface().(key.Binding)
+
+		if len(sections) == 0 || sections[len(sections)-1].Name != name {
+			sections = append(sections, KeyMapSection{Name: name})
+		}
+
+		sections[len(sections)-1].Bindings = append(sections[len(sections)-1].Bindings, bind)
+	}
+
+	return sections
 }
 
 func (c *KeyMap) MarshalYAML() (interface{}, error) {
@@ -78,11 +151,25 @@ func (c *KeyMap) MarshalYAML() (interface{}, error) {
 }
 
 func (c *KeyMap) UnmarshalYAML(value *yaml.Node) error {
-	target := map[string][]string{}
-	if err := value.Decode(&target); err != nil {
+	nodes := map[string]yaml.Node{}
+	if err := value.Decode(&nodes); err != nil {
 		return err
 	}
 
+	target := make(map[string][]string, len(nodes))
+	for name, node := range nodes {
+		switch node.Kind {
+		case yaml.ScalarNode: // a single key
+			target[name] = []string{node.Value}
+		case yaml.SequenceNode:
+			var binds []string
+			if err := node.Decode(&binds); err != nil {
+				return fmt.Errorf("failed to decode key binding %q: %w", name, err)
+			}
+			target[name] = binds
+		}
+	}
+
 	val := reflect.ValueOf(c).Elem()
 
 	for targetField, binds := range target {
@@ -94,9 +181,7 @@ func (c *KeyMap) UnmarshalYAML(value *yaml.Node) error {
 
 			if fieldName == targetField {
 				keyBind := reflect.ValueOf(c).Elem().Field(i).Interface().(key.Binding)
-				keyBind.SetKeys(binds...)
-				keyBind.SetHelp(strings.Join(binds, "/"), keyBind.Help().Desc) // overwrite help with old description but new keys
-				reflect.ValueOf(c).Elem().Field(i).Set(reflect.ValueOf(keyBind))
+				reflect.ValueOf(c).Elem().Field(i).Set(reflect.ValueOf(rebind(keyBind, binds...)))
 			}
 		}
 	}
@@ -104,6 +189,13 @@ func (c *KeyMap) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// rebind replaces the keys of a binding, keeping its help description
+func rebind(b key.Binding, keys ...string) key.Binding {
+	b.SetKeys(keys...)
+	b.SetHelp(strings.Join(keys, "/"), b.Help().Desc) // overwrite help with old description but new keys
+	return b
+}
+
 func BuildDefaultKeyMap() KeyMap {
 	return KeyMap{
 		Up: key.NewBinding(
@@ -126,6 +218,10 @@ func BuildDefaultKeyMap() KeyMap {
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
 		),
+		Leader: key.NewBinding(
+			key.WithKeys("\\"),
+			key.WithHelp("\\", "leader key, used as <leader> in key sequences"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("ctrl+c"),
 			key.WithHelp("ctrl+c", "quit"),
@@ -146,6 +242,46 @@ func BuildDefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+alt+d"),
 			key.WithHelp("ctrl+alt+d", "dump screen"),
 		),
+		ToggleFollowedSidebar: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "toggle followed channels sidebar"),
+		),
+		Settings: key.NewBinding(
+			key.WithKeys("alt+,"),
+			key.WithHelp("alt+,", "open settings editor"),
+		),
+		ToggleStats: key.NewBinding(
+			key.WithKeys("ctrl+alt+s"),
+			key.WithHelp("ctrl+alt+s", "toggle stats overlay"),
+		),
+		DebugLog: key.NewBinding(
+			key.WithKeys("ctrl+alt+l"),
+			key.WithHelp("ctrl+alt+l", "open debug log"),
+		),
+		ToggleSounds: key.NewBinding(
+			key.WithKeys("alt+m"),
+			key.WithHelp("alt+m", "mute/unmute sounds"),
+		),
+		DismissNotice: key.NewBinding(
+			key.WithKeys("ctrl+alt+x"),
+			key.WithHelp("ctrl+alt+x", "dismiss update notice"),
+		),
+		OpenLiveChannel: key.NewBinding(
+			key.WithKeys("ctrl+alt+o"),
+			key.WithHelp("ctrl+alt+o", "open channel of go live notification"),
+		),
+		WatchLiveChannel: key.NewBinding(
+			key.WithKeys("ctrl+alt+w"),
+			key.WithHelp("ctrl+alt+w", "watch channel of go live notification"),
+		),
+		JumpToNotification: key.NewBinding(
+			key.WithKeys("alt+j"),
+			key.WithHelp("alt+j", "jump to source of tray notification"),
+		),
+		DismissNotification: key.NewBinding(
+			key.WithKeys("alt+x"),
+			key.WithHelp("alt+x", "dismiss tray notification"),
+		),
 		Next: key.NewBinding(
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "next item"),
@@ -154,6 +290,10 @@ func BuildDefaultKeyMap() KeyMap {
 			key.WithKeys("shift+tab"),
 			key.WithHelp("shift+tab", "previous item"),
 		),
+		GoToTab: key.NewBinding(
+			key.WithKeys("alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9"),
+			key.WithHelp("alt+1-9", "go to tab 1-9"),
+		),
 		InsertMode: key.NewBinding(
 			key.WithKeys("i", "I"),
 			key.WithHelp("i", "insert mode"),
@@ -198,51 +338,363 @@ func BuildDefaultKeyMap() KeyMap {
 			key.WithKeys("/"),
 			key.WithHelp("/", "start search mode in chat window"),
 		),
+		SearchUp: key.NewBinding(
+			key.WithKeys("up"),
+			key.WithHelp("up", "previous search result"),
+		),
+		SearchDown: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("down", "next search result"),
+		),
+		EmoteOverview: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "open emote overview"),
+		),
 		QuickSent: key.NewBinding(
 			key.WithKeys("alt+enter"),
 			key.WithHelp("alt+enter", "send message but stay in insert mode"),
 		),
+		CopyToClipboard: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy selected message text to clipboard"),
+		),
+		CopyUsernameToClipboard: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy author of selected message to clipboard"),
+		),
+		CopyLinkToClipboard: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "copy first link of selected message to clipboard"),
+		),
+		LinkHintMode: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "label visible links, type a label to open the link or shift+label to copy it"),
+		),
+		VisualMode: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "select a range of messages, y copies and W saves the selection"),
+		),
+		SaveSelection: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "save selected messages to a file in the working directory"),
+		),
+		WatchStream: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "watch stream in external player"),
+		),
+		ToggleBlockUser: key.NewBinding(
+			key.WithKeys("alt+b"),
+			key.WithHelp("alt+b", "block/unblock inspected user"),
+		),
+		RetryMessage: key.NewBinding(
+			key.WithKeys("alt+r"),
+			key.WithHelp("alt+r", "retry selected failed message"),
+		),
+		ReconnectChat: key.NewBinding(
+			key.WithKeys("ctrl+alt+r"),
+			key.WithHelp("ctrl+alt+r", "reconnect to the chat server"),
+		),
+		TranslateMessage: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "translate selected message or hide its translation"),
+		),
+		OpenThread: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "show reply thread of selected message"),
+		),
+		AcceptSuggestion: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "accept suggestion or cycle to next completion"),
+		),
+		NextSuggestion: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓/ctrl+n", "next suggestion or newer history entry"),
+		),
+		PrevSuggestion: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑/ctrl+p", "previous suggestion or older history entry"),
+		),
+		PrevCompletion: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "cycle to previous completion"),
+		),
+		ReverseSearch: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "search sent message history"),
+		),
+		CorrectSpelling: key.NewBinding(
+			key.WithKeys("alt+s"),
+			key.WithHelp("alt+s", "correct misspelled word, press again for the next correction"),
+		),
+		AddToDictionary: key.NewBinding(
+			key.WithKeys("alt+a"),
+			key.WithHelp("alt+a", "add misspelled word to your dictionary"),
+		),
+		HangulInput: key.NewBinding(
+			key.WithKeys("ctrl+alt+k"),
+			key.WithHelp("ctrl+alt+k", "toggle Hangul composition for this tab"),
+		),
+	}
+}
+
+// leaderPlaceholder is replaced with the leader key in key sequences
+const leaderPlaceholder = "<leader>"
+
+// ParseKeySequence splits a binding key into the keys which have to be pressed one after another.
+// Keys are separated by spaces, like "g t". <leader> is replaced with the leader key and
+// may be directly followed by single character keys, so "<leader>mb" is the same as "<leader> m b".
+// Since keys are separated by spaces, the space key is written as "space".
+func ParseKeySequence(binding, leader string) []string {
+	var keys []string
+
+	keyName := func(k string) string {
+		if k == "space" {
+			return " "
+		}
+		return k
+	}
+
+	for token := range strings.FieldsSeq(binding) {
+		rest, ok := strings.CutPrefix(token, leaderPlaceholder)
+		if !ok {
+			keys = append(keys, keyName(token))
+			continue
+		}
+
+		keys = append(keys, keyName(leader))
+		for _, r := range rest {
+			keys = append(keys, string(r))
+		}
 	}
+
+	return keys
 }
 
-func CreateReadKeyMap() (KeyMap, error) {
+// KeySequences returns all binding keys, which consist of more than one key or use <leader>, mapped to the keys which have to be pressed
+func (c KeyMap) KeySequences() map[string][]string {
+	var leader string
+	if keys := c.Leader.Keys(); len(keys) > 0 {
+		leader = keys[0]
+	}
+
+	sequences := map[string][]string{}
+	for _, section := range c.Sections() {
+		for _, b := range section.Bindings {
+			for _, k := range b.Keys() {
+				// the leader itself only starts sequences
+				if k == leader {
+					continue
+				}
+
+				// single keys are only included if they use the leader placeholder or a key name like space
+				if seq := ParseKeySequence(k, leader); len(seq) > 1 || len(seq) == 1 && seq[0] != k {
+					sequences[k] = seq
+				}
+			}
+		}
+	}
+
+	return sequences
+}
+
+// BuiltinKeyMapProfiles returns the built-in keymap profiles by name
+func BuiltinKeyMapProfiles() map[string]KeyMap {
+	return map[string]KeyMap{
+		KeyMapProfileDefault: BuildDefaultKeyMap(),
+		KeyMapProfileVim:     buildVimKeyMap(),
+		KeyMapProfileEmacs:   buildEmacsKeyMap(),
+	}
+}
+
+// buildVimKeyMap extends the default keymap, which already uses j/k and i, with more vim motions
+func buildVimKeyMap() KeyMap {
+	m := BuildDefaultKeyMap()
+
+	m.GoToTop = rebind(m.GoToTop, "g", "home")
+	m.GoToBottom = rebind(m.GoToBottom, "G", "end")
+	m.Next = rebind(m.Next, "tab", "L", "g t")
+	m.Previous = rebind(m.Previous, "shift+tab", "H", "g T")
+	m.InsertMode = rebind(m.InsertMode, "i", "I", "a", "A")
+	m.SearchUp = rebind(m.SearchUp, "up", "ctrl+k")
+	m.SearchDown = rebind(m.SearchDown, "down", "ctrl+j")
+	m.CloseTab = rebind(m.CloseTab, "ctrl+q", "ctrl+w", "Q")
+
+	return m
+}
+
+// buildEmacsKeyMap uses control and meta bindings instead of single letters
+func buildEmacsKeyMap() KeyMap {
+	m := BuildDefaultKeyMap()
+
+	m.Up = rebind(m.Up, "up", "ctrl+p")
+	m.Down = rebind(m.Down, "down", "ctrl+n")
+	m.Escape = rebind(m.Escape, "esc", "ctrl+g")
+	m.ChatPopUp = rebind(m.ChatPopUp, "ctrl+alt+p")
+	m.GoToTop = rebind(m.GoToTop, "alt+<", "home")
+	m.GoToBottom = rebind(m.GoToBottom, "alt+>", "end")
+	m.Next = rebind(m.Next, "tab", "alt+n")
+	m.Previous = rebind(m.Previous, "shift+tab", "alt+p")
+	m.InsertMode = rebind(m.InsertMode, "i", "ctrl+o")
+	m.SearchMode = rebind(m.SearchMode, "/", "ctrl+s")
+	m.SearchUp = rebind(m.SearchUp, "up", "ctrl+p")
+	m.SearchDown = rebind(m.SearchDown, "down", "ctrl+n")
+	m.CopyToClipboard = rebind(m.CopyToClipboard, "y", "alt+w")
+
+	return m
+}
+
+// CreateReadKeyMap reads the keymap file, creating it if it does not exist.
+// The bindings are based on the given profile, bindings in the file override the bindings of the profile.
+func CreateReadKeyMap(profile string) (KeyMap, error) {
+	m, _, err := CreateReadKeyMaps(profile)
+	return m, err
+}
+
+// CreateReadKeyMaps reads the keymap file like CreateReadKeyMap, together with the keymaps of the channels section
+func CreateReadKeyMaps(profile string) (KeyMap, ChannelKeyMaps, error) {
 	f, err := openCreateConfigFile(afero.NewOsFs(), keyMapFileName)
 	if err != nil {
-		return KeyMap{}, err
+		return KeyMap{}, nil, err
 	}
 
 	defer f.Close()
 
-	stat, err := f.Stat()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return KeyMap{}, nil, err
+	}
+
+	m, err := parseKeyMap(b, profile)
 	if err != nil {
+		return KeyMap{}, nil, err
+	}
+
+	channels, err := parseChannelKeyMaps(b, m)
+	if err != nil {
+		return KeyMap{}, nil, err
+	}
+
+	return m, channels, nil
+}
+
+// parseKeyMap builds the keymap of the selected profile. Custom profiles may be defined in the profiles section,
+// based on a built-in profile (base, default "default"). Bindings at the top level override the selected profile.
+func parseKeyMap(b []byte, profile string) (KeyMap, error) {
+	var doc struct {
+		Profiles map[string]yaml.Node `yaml:"profiles"`
+	}
+
+	if err := yaml.Unmarshal(b, &doc); err != nil {
 		return KeyMap{}, err
 	}
 
-	// Config was empty, return default config and write a default one to disk
-	if stat.Size() == 0 {
-		m := BuildDefaultKeyMap()
-		b, err := yaml.Marshal(&m)
-		if err != nil {
-			return KeyMap{}, err
+	builtins := BuiltinKeyMapProfiles()
+	profiles := BuiltinKeyMapProfiles()
+
+	for name, node := range doc.Profiles {
+		var meta struct {
+			Base string `yaml:"base"`
+		}
+
+		if err := node.Decode(&meta); err != nil {
+			return KeyMap{}, fmt.Errorf("keymap profile %q: %w", name, err)
+		}
+
+		if meta.Base == "" {
+			meta.Base = KeyMapProfileDefault
+		}
+
+		m, ok := builtins[meta.Base]
+		if !ok {
+			return KeyMap{}, fmt.Errorf("keymap profile %q: unknown base profile %q", name, meta.Base)
 		}
 
-		if _, err := f.Write(b); err != nil {
-			return KeyMap{}, err
+		if err := node.Decode(&m); err != nil {
+			return KeyMap{}, fmt.Errorf("keymap profile %q: %w", name, err)
 		}
 
-		return m, nil
+		profiles[name] = m
 	}
 
-	b, err := io.ReadAll(f)
-	if err != nil {
-		return KeyMap{}, err
+	if profile == "" {
+		profile = KeyMapProfileDefault
+	}
+
+	m, ok := profiles[profile]
+	if !ok {
+		return KeyMap{}, fmt.Errorf("unknown keymap profile %q", profile)
 	}
 
-	// Config was not empty, read it and return it
-	readableMap := BuildDefaultKeyMap()
-	if err := yaml.Unmarshal(b, &readableMap); err != nil {
+	if err := yaml.Unmarshal(b, &m); err != nil {
 		return KeyMap{}, err
 	}
 
-	return readableMap, nil
+	return m, nil
+}
+
+// ChannelKeyMap is the keymap of a channel with overrides in the channels section of the keymap file
+type ChannelKeyMap struct {
+	KeyMap KeyMap
+	Macros map[string]string // key to the message sent to the channel when pressed in its chat, like "ctrl+d": "!discord"
+}
+
+// ChannelKeyMaps are the keymaps of channels with overrides, keyed by lowercase channel login
+type ChannelKeyMaps map[string]ChannelKeyMap
+
+// For returns the keymap of the channel, base for channels without overrides
+func (c ChannelKeyMaps) For(channel string, base KeyMap) KeyMap {
+	if m, ok := c[strings.ToLower(channel)]; ok {
+		return m.KeyMap
+	}
+
+	return base
+}
+
+// MacroFor returns the message bound to the key in the channel
+func (c ChannelKeyMaps) MacroFor(channel, key string) (string, bool) {
+	text, ok := c[strings.ToLower(channel)].Macros[key]
+	return text, ok
+}
+
+// parseChannelKeyMaps builds the keymaps of the channels section. Bindings of a channel override the bindings of base,
+// which already has the selected profile and the top level bindings applied. An empty list disables a binding in the channel.
+func parseChannelKeyMaps(b []byte, base KeyMap) (ChannelKeyMaps, error) {
+	var doc struct {
+		Channels map[string]yaml.Node `yaml:"channels"`
+	}
+
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Channels) == 0 {
+		return nil, nil
+	}
+
+	channels := make(ChannelKeyMaps, len(doc.Channels))
+
+	for name, node := range doc.Channels {
+		var meta struct {
+			Macros map[string]string `yaml:"macros"`
+		}
+
+		if err := node.Decode(&meta); err != nil {
+			return nil, fmt.Errorf("keymap of channel %q: %w", name, err)
+		}
+
+		for k, text := range meta.Macros {
+			if strings.TrimSpace(k) == "" || strings.TrimSpace(text) == "" {
+				return nil, fmt.Errorf("keymap of channel %q: macros need a key and a message", name)
+			}
+		}
+
+		m := base
+		if err := node.Decode(&m); err != nil {
+			return nil, fmt.Errorf("keymap of channel %q: %w", name, err)
+		}
+
+		channels[strings.ToLower(strings.TrimPrefix(name, "#"))] = ChannelKeyMap{KeyMap: m, Macros: meta.Macros}
+	}
+
+	return channels, nil
 }
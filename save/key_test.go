@@ -1,6 +1,7 @@
 package save
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -36,3 +37,181 @@ func TestKeyMap_UnmarshalYAML(t *testing.T) {
 	require.Equal(t, []string{"w", "q"}, gotKeyMap.Up.Keys()) // should be overwritten
 
 }
+
+func TestParseKeyMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty-file-uses-profile", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := parseKeyMap(nil, KeyMapProfileVim)
+		require.NoError(t, err)
+		require.Equal(t, []string{"g", "home"}, m.GoToTop.Keys())
+		require.Equal(t, "go to top", m.GoToTop.Help().Desc)
+	})
+
+	t.Run("top-level-overrides-profile", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := parseKeyMap([]byte("go_to_top: x\nquit:\n  - ctrl+d\n"), KeyMapProfileEmacs)
+		require.NoError(t, err)
+		require.Equal(t, []string{"x"}, m.GoToTop.Keys())
+		require.Equal(t, []string{"ctrl+d"}, m.Quit.Keys())
+		require.Equal(t, []string{"alt+>", "end"}, m.GoToBottom.Keys())
+	})
+
+	t.Run("custom-profile-with-base", func(t *testing.T) {
+		t.Parallel()
+
+		doc := `
+profiles:
+  mine:
+    base: vim
+    go_to_bottom: [z]
+`
+		m, err := parseKeyMap([]byte(doc), "mine")
+		require.NoError(t, err)
+		require.Equal(t, []string{"z"}, m.GoToBottom.Keys())
+		require.Equal(t, []string{"g", "home"}, m.GoToTop.Keys())
+	})
+
+	t.Run("unknown-profile", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseKeyMap(nil, "nano")
+		require.ErrorContains(t, err, `unknown keymap profile "nano"`)
+	})
+
+	t.Run("unknown-base-profile", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseKeyMap([]byte("profiles:\n  mine:\n    base: nano\n"), KeyMapProfileDefault)
+		require.ErrorContains(t, err, `unknown base profile "nano"`)
+	})
+}
+
+func TestParseChannelKeyMaps(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+go_to_top: x
+channels:
+  "#LIRIK":
+    quick_timeout: []
+    dump_chat: ctrl+d
+    macros:
+      ctrl+g: "!discord"
+`
+	base, err := parseKeyMap([]byte(doc), KeyMapProfileVim)
+	require.NoError(t, err)
+
+	channels, err := parseChannelKeyMaps([]byte(doc), base)
+	require.NoError(t, err)
+
+	m := channels.For("lirik", base)
+	require.Empty(t, m.QuickTimeout.Keys(), "disabled in the channel")
+	require.Equal(t, []string{"ctrl+d"}, m.DumpChat.Keys(), "channel overrides the global binding")
+	require.Equal(t, []string{"x"}, m.GoToTop.Keys(), "top level binding applies to the channel")
+	require.Equal(t, []string{"G", "end"}, m.GoToBottom.Keys(), "profile binding applies to the channel")
+
+	require.Equal(t, base, channels.For("xqc", base), "channels without overrides use the global keymap")
+	require.NotEmpty(t, base.QuickTimeout.Keys(), "other channels are not affected")
+
+	text, ok := channels.MacroFor("Lirik", "ctrl+g")
+	require.True(t, ok)
+	require.Equal(t, "!discord", text)
+
+	_, ok = channels.MacroFor("xqc", "ctrl+g")
+	require.False(t, ok)
+
+	_, err = parseChannelKeyMaps([]byte("channels:\n  lirik:\n    macros:\n      ctrl+g: \"\"\n"), base)
+	require.ErrorContains(t, err, `keymap of channel "lirik": macros need a key and a message`)
+
+	channels, err = parseChannelKeyMaps(nil, base)
+	require.NoError(t, err)
+	require.Equal(t, base, channels.For("lirik", base))
+}
+
+func TestKeyMap_Sections(t *testing.T) {
+	t.Parallel()
+
+	m := BuildDefaultKeyMap()
+	sections := m.Sections()
+
+	var names []string
+	var count int
+	for _, s := range sections {
+		require.NotEmpty(t, s.Name)
+		names = append(names, s.Name)
+		count += len(s.Bindings)
+
+		for _, b := range s.Bindings {
+			require.NotEmpty(t, b.Keys(), "every binding in section %s should have a default key", s.Name)
+		}
+	}
+
+	require.Equal(t, []string{"General", "App Binds", "Tab Binds", "Chat Binds", "Input Binds", "Account Binds"}, names)
+	require.Equal(t, reflect.TypeOf(m).NumField(), count)
+}
+
+func TestParseKeySequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		binding string
+		want    []string
+	}{
+		{binding: "ctrl+a", want: []string{"ctrl+a"}},
+		{binding: "g t", want: []string{"g", "t"}},
+		{binding: "<leader>mb", want: []string{",", "m", "b"}},
+		{binding: "<leader> m ctrl+b", want: []string{",", "m", "ctrl+b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.binding, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, ParseKeySequence(tt.binding, ","))
+		})
+	}
+}
+
+func TestKeyMap_KeySequences(t *testing.T) {
+	t.Parallel()
+
+	m, err := parseKeyMap([]byte("leader: space\ngo_to_top: [\"<leader>gg\", t]\n"), KeyMapProfileVim)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]string{
+		"<leader>gg": {" ", "g", "g"},
+		"g t":        {"g", "t"},
+		"g T":        {"g", "T"},
+	}, m.KeySequences())
+
+	// sequences are kept as written when saving the keymap
+	doc, err := yaml.Marshal(&KeyMap{GoToTop: m.GoToTop})
+	require.NoError(t, err)
+	require.Equal(t, "go_to_top:\n    - <leader>gg\n    - t\n", string(doc))
+}
+
+func Fuzz_parseKeyMap(f *testing.F) {
+	f.Add([]byte(""), KeyMapProfileDefault)
+	f.Add([]byte("go_to_top: x\nquit:\n  - ctrl+d\n"), KeyMapProfileEmacs)
+	f.Add([]byte("profiles:\n  mine:\n    base: vim\n    go_to_bottom: [z]\n"), "mine")
+	f.Add([]byte("profiles:\n  mine:\n    base: nano\n"), KeyMapProfileDefault)
+	f.Add([]byte("profiles: [mine]\n"), KeyMapProfileVim)
+	f.Add([]byte("quit: {a: b}\n"), KeyMapProfileDefault)
+	f.Add([]byte("channels:\n  lirik:\n    quick_timeout: []\n    macros:\n      ctrl+g: \"!discord\"\n"), KeyMapProfileDefault)
+
+	// a broken keymap file must result in an error, never panic
+	f.Fuzz(func(t *testing.T, b []byte, profile string) {
+		m, err := parseKeyMap(b, profile)
+		if err != nil {
+			return
+		}
+
+		_, err = yaml.Marshal(&m)
+		require.NoError(t, err)
+
+		_, _ = parseChannelKeyMaps(b, m)
+	})
+}
@@ -171,6 +171,31 @@ func (b *BatchedMessageLogger) MessagesFromUserInChannel(username string, broadc
 	return b.scanRows(rows)
 }
 
+// MessagesInChannel returns all logged messages of a channel, oldest first
+func (b *BatchedMessageLogger) MessagesInChannel(broadcasterChannel string) ([]LogEntry, error) {
+	query := `SELECT id, broadcast_id, user_id, broadcast_channel, sent_at, sender_display, payload FROM messages WHERE broadcast_channel = ?`
+	rows, err := b.roDB.Query(query, broadcasterChannel)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return []LogEntry{}, nil
+		}
+
+		return nil, err
+	}
+
+	entries, err := b.scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// sent_at is stored with the time zone offset of the machine, so it is not sorted in the query
+	slices.SortStableFunc(entries, func(a, b LogEntry) int {
+		return a.SentAt.Compare(b.SentAt)
+	})
+
+	return entries, nil
+}
+
 func (b *BatchedMessageLogger) scanRows(rows *sql.Rows) ([]LogEntry, error) {
 	defer rows.Close()
 
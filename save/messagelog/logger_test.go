@@ -129,3 +129,23 @@ func TestBatchedMessageLogger_createLogEntries(t *testing.T) {
 		require.Nil(t, err)
 	})
 }
+
+func TestBatchedMessageLogger_MessagesInChannel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "broadcast_id", "user_id", "broadcast_channel", "sent_at", "sender_display", "payload"}).
+		AddRow("second", 1, 10, "lirik", "2026-03-10 13:00:00+01:00", "viewer", []byte(`{"id":"second"}`)).
+		AddRow("first", 1, 10, "lirik", "2026-03-10 11:30:00+00:00", "viewer", []byte(`{"id":"first"}`))
+
+	mock.ExpectQuery("SELECT (.+) FROM messages WHERE broadcast_channel = ?").WithArgs("lirik").WillReturnRows(rows)
+
+	messageLogger := NewBatchedMessageLogger(zerolog.Nop(), db, db, nil, nil)
+	entries, err := messageLogger.MessagesInChannel("lirik")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "first", entries[0].ID)
+	require.Equal(t, "second", entries[1].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
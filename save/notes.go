@@ -0,0 +1,170 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const notesFileName = "notes.json"
+
+// NoteKind is what a note is attached to
+type NoteKind int
+
+const (
+	NoteChannel NoteKind = iota
+	NoteUser
+)
+
+// Note is a free text note attached to a channel or user
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// notesFile is the content of the notes file, notes are keyed by the lower case login of the channel or user
+type notesFile struct {
+	Channels map[string][]Note `json:"channels,omitempty"`
+	Users    map[string][]Note `json:"users,omitempty"`
+}
+
+// NoteStore persists the notes of channels and users. The file is read once and written on every change.
+type NoteStore struct {
+	fs afero.Fs
+
+	m     sync.Mutex
+	notes *notesFile // nil until loaded
+}
+
+func NewNoteStore(fs afero.Fs) *NoteStore {
+	return &NoteStore{fs: fs}
+}
+
+// Notes returns the notes of a channel or user, oldest first
+func (s *NoteStore) Notes(kind NoteKind, name string) ([]Note, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(s.notesOf(kind)[strings.ToLower(name)]), nil
+}
+
+// AddNote attaches a note to a channel or user
+func (s *NoteStore) AddNote(kind NoteKind, name string, note Note) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	notes := s.notesOf(kind)
+	name = strings.ToLower(name)
+	notes[name] = append(notes[name], note)
+
+	return s.save()
+}
+
+// RemoveNote removes the note at index from a channel or user
+func (s *NoteStore) RemoveNote(kind NoteKind, name string, index int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	notes := s.notesOf(kind)
+	name = strings.ToLower(name)
+
+	if index < 0 || index >= len(notes[name]) {
+		return fmt.Errorf("note %d not found", index+1)
+	}
+
+	notes[name] = slices.Delete(notes[name], index, index+1)
+	if len(notes[name]) == 0 {
+		delete(notes, name)
+	}
+
+	return s.save()
+}
+
+func (s *NoteStore) notesOf(kind NoteKind) map[string][]Note {
+	if kind == NoteUser {
+		return s.notes.Users
+	}
+
+	return s.notes.Channels
+}
+
+func (s *NoteStore) load() error {
+	if s.notes != nil {
+		return nil
+	}
+
+	f, err := openCreateStateFile(s.fs, notesFileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	notes := notesFile{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &notes); err != nil {
+			syntaxErr := &json.SyntaxError{}
+			if !errors.As(err, &syntaxErr) {
+				return err
+			}
+		}
+	}
+
+	if notes.Channels == nil {
+		notes.Channels = map[string][]Note{}
+	}
+
+	if notes.Users == nil {
+		notes.Users = map[string][]Note{}
+	}
+
+	s.notes = &notes
+	return nil
+}
+
+func (s *NoteStore) save() error {
+	f, err := openCreateStateFile(s.fs, notesFileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	data, err := json.MarshalIndent(s.notes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = f.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,43 @@
+package save
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteStore(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewNoteStore(fs)
+	require.NoError(t, store.AddNote(NoteChannel, "Lirik", Note{Text: "raid train on fridays", CreatedAt: at}))
+	require.NoError(t, store.AddNote(NoteUser, "lirik", Note{Text: "asked mods about X", CreatedAt: at}))
+	require.NoError(t, store.AddNote(NoteUser, "lirik", Note{Text: "second", CreatedAt: at}))
+
+	// notes are read from the file by a new store
+	store = NewNoteStore(fs)
+
+	notes, err := store.Notes(NoteChannel, "lirik")
+	require.NoError(t, err)
+	require.Equal(t, []Note{{Text: "raid train on fridays", CreatedAt: at}}, notes)
+
+	notes, err = store.Notes(NoteUser, "LIRIK")
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	require.NoError(t, store.RemoveNote(NoteUser, "lirik", 0))
+	require.ErrorContains(t, store.RemoveNote(NoteUser, "lirik", 1), "note 2 not found")
+
+	notes, err = NewNoteStore(fs).Notes(NoteUser, "lirik")
+	require.NoError(t, err)
+	require.Equal(t, []Note{{Text: "second", CreatedAt: at}}, notes)
+
+	notes, err = store.Notes(NoteChannel, "sodapoppin")
+	require.NoError(t, err)
+	require.Empty(t, notes)
+}
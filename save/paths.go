@@ -0,0 +1,239 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/afero"
+)
+
+// Environment variables overriding the default directories, the same as the --config and --data-dir flags
+const (
+	ConfigDirEnv = "CHATUINO_CONFIG"
+	DataDirEnv   = "CHATUINO_DATA_DIR"
+)
+
+const (
+	logFileName      = "chatuino.log"
+	databaseFileName = "chatuino.db"
+	scriptDirName    = "scripts"
+	socketFileName   = "chatuino.sock"
+	emoteSetDirName  = "emote_sets"
+	apiCacheDirName  = "api_cache"
+	badgeSetDirName  = "badge_sets"
+	crashMarkerName  = "crashed"
+	updateCheckName  = "update_check.json"
+	dictionaryName   = "dictionary.txt"
+	syncStateName    = "sync.json"
+	syncRepoDirName  = "sync_repo"
+)
+
+// Paths are the directories Chatuino reads and writes its files in
+type Paths struct {
+	Config  string // settings, theme, keymap, scripts and plain text accounts
+	Data    string // downloaded emote and badge images and the last fetched emote sets
+	State   string // log file, chat log database and the tabs of the previous session
+	Runtime string // control socket
+}
+
+var (
+	pathsMu     sync.RWMutex
+	activePaths *Paths
+)
+
+// DefaultPaths returns the directories following the XDG base directory specification.
+// The config directory is the operating system specific user config directory.
+func DefaultPaths() (Paths, error) {
+	configDir, err := os.UserConfigDir() // get users config directory, depending on OS
+	if err != nil {
+		return Paths{}, err
+	}
+
+	return Paths{
+		Config:  filepath.Join(configDir, chatuinoConfigDir),
+		Data:    filepath.Join(xdg.DataHome, chatuinoConfigDir),
+		State:   filepath.Join(xdg.StateHome, chatuinoConfigDir),
+		Runtime: xdg.RuntimeDir,
+	}, nil
+}
+
+// ResolvePaths returns the default paths with the overrides applied. configDir replaces the config directory,
+// dataDir is used for data, state and runtime files, so all files except the config can be kept in one place.
+// Empty overrides are ignored.
+func ResolvePaths(configDir, dataDir string) (Paths, error) {
+	paths, err := DefaultPaths()
+	if err != nil {
+		return Paths{}, err
+	}
+
+	if configDir != "" {
+		if paths.Config, err = filepath.Abs(configDir); err != nil {
+			return Paths{}, fmt.Errorf("invalid config directory: %w", err)
+		}
+	}
+
+	if dataDir != "" {
+		if paths.Data, err = filepath.Abs(dataDir); err != nil {
+			return Paths{}, fmt.Errorf("invalid data directory: %w", err)
+		}
+		paths.State = paths.Data
+		paths.Runtime = paths.Data
+	}
+
+	return paths, nil
+}
+
+// UsePaths sets the directories used by all functions of this package reading or writing files
+func UsePaths(p Paths) {
+	pathsMu.Lock()
+	defer pathsMu.Unlock()
+
+	activePaths = &p
+}
+
+// ActivePaths returns the directories set by UsePaths or the default directories
+func ActivePaths() (Paths, error) {
+	pathsMu.RLock()
+	defer pathsMu.RUnlock()
+
+	if activePaths != nil {
+		return *activePaths, nil
+	}
+
+	return DefaultPaths()
+}
+
+// SettingsFile returns the path of the settings file
+func (p Paths) SettingsFile() string {
+	return filepath.Join(p.Config, settingsFileName)
+}
+
+// ThemeFile returns the path of the theme file
+func (p Paths) ThemeFile() string {
+	return filepath.Join(p.Config, themeFileName)
+}
+
+// KeymapFile returns the path of the keymap file
+func (p Paths) KeymapFile() string {
+	return filepath.Join(p.Config, keyMapFileName)
+}
+
+// DictionaryFile returns the path of the custom spellcheck dictionary, containing the words added by the user
+func (p Paths) DictionaryFile() string {
+	return filepath.Join(p.Config, dictionaryName)
+}
+
+// ScriptDir returns the directory of the user scripts, see the script package
+func (p Paths) ScriptDir() string {
+	return filepath.Join(p.Config, scriptDirName)
+}
+
+// EmoteSetDir returns the directory of the last fetched emote sets, used when an emote API can't be reached
+func (p Paths) EmoteSetDir() string {
+	return filepath.Join(p.Data, emoteSetDirName)
+}
+
+// BadgeSetDir returns the directory of the last fetched badge sets, used when Twitch can't be reached
+func (p Paths) BadgeSetDir() string {
+	return filepath.Join(p.Data, badgeSetDirName)
+}
+
+// APICacheDir returns the directory of the cached responses of read-only Twitch API endpoints
+func (p Paths) APICacheDir() string {
+	return filepath.Join(p.Data, apiCacheDirName)
+}
+
+// LogFile returns the path of the log file, used with --log-to-file
+func (p Paths) LogFile() string {
+	return filepath.Join(p.State, logFileName)
+}
+
+// DatabaseFile returns the path of the SQLite database containing the chat logs
+func (p Paths) DatabaseFile() string {
+	return filepath.Join(p.State, databaseFileName)
+}
+
+// StateFile returns the path of the file containing the tabs and input history of the previous session
+func (p Paths) StateFile() string {
+	return filepath.Join(p.State, stateFileName)
+}
+
+// NotesFile returns the path of the file containing the notes of channels and users
+func (p Paths) NotesFile() string {
+	return filepath.Join(p.State, notesFileName)
+}
+
+// CommandsFile returns the path of the file containing the quotes and counters of the /quote and /count commands
+func (p Paths) CommandsFile() string {
+	return filepath.Join(p.State, commandsFileName)
+}
+
+// PanicReportFile returns the path of the report written when Chatuino crashes at the given time
+func (p Paths) PanicReportFile(at time.Time) string {
+	return filepath.Join(p.State, "panic-"+at.Format("2006-01-02_15_04_05")+".txt")
+}
+
+// CrashMarkerFile returns the path of the file marking that the previous session crashed, it contains the path of the panic report
+func (p Paths) CrashMarkerFile() string {
+	return filepath.Join(p.State, crashMarkerName)
+}
+
+// UpdateCheckFile returns the path of the file containing the result of the last check for a new release
+func (p Paths) UpdateCheckFile() string {
+	return filepath.Join(p.State, updateCheckName)
+}
+
+// SyncStateFile returns the path of the file containing the hashes of the files at the last remote sync
+func (p Paths) SyncStateFile() string {
+	return filepath.Join(p.State, syncStateName)
+}
+
+// SyncRepoDir returns the directory of the local clone used by the git sync backend
+func (p Paths) SyncRepoDir() string {
+	return filepath.Join(p.State, syncRepoDirName)
+}
+
+// SocketFile returns the default path of the control socket, see the ipc package
+func (p Paths) SocketFile() string {
+	return filepath.Join(p.Runtime, socketFileName)
+}
+
+// MoveLegacyStateFiles moves the log file, chat log database and session state from the data directory,
+// where versions before the split into data and state directories stored them, to the state directory.
+// Files already existing in the state directory are not replaced.
+func MoveLegacyStateFiles(fs afero.Fs, p Paths) error {
+	if p.Data == p.State {
+		return nil
+	}
+
+	if err := fs.MkdirAll(p.State, 0o700); err != nil {
+		return err
+	}
+
+	var errs []error
+	// the SQLite write-ahead log files belong to the database and are only moved together with it
+	for _, names := range [][]string{{logFileName}, {databaseFileName, databaseFileName + "-wal", databaseFileName + "-shm"}, {stateFileName}} {
+		if _, err := fs.Stat(filepath.Join(p.State, names[0])); err == nil {
+			continue
+		}
+
+		for _, name := range names {
+			from, to := filepath.Join(p.Data, name), filepath.Join(p.State, name)
+
+			if _, err := fs.Stat(from); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			if err := fs.Rename(from, to); err != nil {
+				errs = append(errs, fmt.Errorf("failed to move %s to %s: %w", from, to, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,60 @@
+package save
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePaths(t *testing.T) {
+	t.Parallel()
+
+	defaults, err := DefaultPaths()
+	require.NoError(t, err)
+
+	paths, err := ResolvePaths("", "")
+	require.NoError(t, err)
+	require.Equal(t, defaults, paths)
+
+	paths, err = ResolvePaths("/custom/config", "")
+	require.NoError(t, err)
+	require.Equal(t, Paths{Config: filepath.FromSlash("/custom/config"), Data: defaults.Data, State: defaults.State, Runtime: defaults.Runtime}, paths)
+
+	// data and state files share the overridden directory
+	paths, err = ResolvePaths("", "/custom/data")
+	require.NoError(t, err)
+	require.Equal(t, Paths{Config: defaults.Config, Data: filepath.FromSlash("/custom/data"), State: filepath.FromSlash("/custom/data"), Runtime: filepath.FromSlash("/custom/data")}, paths)
+}
+
+func TestMoveLegacyStateFiles(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	paths := Paths{Config: "/config", Data: "/data", State: "/state"}
+
+	require.NoError(t, afero.WriteFile(fs, "/data/chatuino.db", []byte("old db"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "/data/chatuino.db-wal", []byte("old wal"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "/data/state.json", []byte("old state"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, "/state/state.json", []byte("new state"), 0o600))
+
+	require.NoError(t, MoveLegacyStateFiles(fs, paths))
+
+	b, err := afero.ReadFile(fs, "/state/chatuino.db")
+	require.NoError(t, err)
+	require.Equal(t, "old db", string(b))
+
+	b, err = afero.ReadFile(fs, "/state/chatuino.db-wal")
+	require.NoError(t, err)
+	require.Equal(t, "old wal", string(b))
+
+	// existing files are not replaced
+	b, err = afero.ReadFile(fs, "/state/state.json")
+	require.NoError(t, err)
+	require.Equal(t, "new state", string(b))
+
+	exists, err := afero.Exists(fs, "/data/chatuino.db")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
@@ -0,0 +1,412 @@
+package save
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	profileManifestName = "manifest.json"
+	profileAccountsName = "accounts.json"
+	profileVersion      = 1
+	maxProfileFileSize  = 10 << 20
+)
+
+// ErrProfileFilesExist is returned when installing a profile would replace existing files
+var ErrProfileFilesExist = errors.New("files of the profile already exist")
+
+// secretSettings are left out of exported profiles unless secrets are included, like API keys and passwords
+var secretSettings = []string{
+	"image_upload.api_key",
+	"obs.password",
+	"proxy.url",
+	"proxy.password",
+	"sync.remote",
+	"sync.password",
+	"translation.api_key",
+	"youtube.api_key",
+	"youtube.client_secret",
+	"youtube.refresh_token",
+}
+
+// ProfileExportOptions configure what is part of an exported profile besides the configuration files
+type ProfileExportOptions struct {
+	IncludeSecrets bool      // keep API keys and passwords in the settings
+	Accounts       []Account // exported with their tokens, nil leaves the accounts out
+}
+
+type profileManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Secrets   bool      `json:"secrets"` // the settings contain the secret settings
+}
+
+// Profile is the content of a profile archive: settings, theme, keymap, dictionary and scripts,
+// optionally with the accounts
+type Profile struct {
+	Files    map[string][]byte // path relative to the config directory, with forward slashes, to content
+	Accounts []Account
+	Secrets  bool // the settings contain the secret settings, otherwise the installed settings keep the current ones
+}
+
+// ExportProfile writes the configuration files of the config directory as gzip compressed tar archive to w
+// and returns the archived files. Missing files are skipped.
+func ExportProfile(fs afero.Fs, p Paths, w io.Writer, opts ProfileExportOptions) ([]string, error) {
+	names, err := profileFiles(fs, p)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.Marshal(profileManifest{Version: profileVersion, CreatedAt: time.Now(), Secrets: opts.IncludeSecrets})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeProfileFile(tw, profileManifestName, manifest); err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		data, err := afero.ReadFile(fs, filepath.Join(p.Config, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+
+		if name == settingsFileName && !opts.IncludeSecrets {
+			if data, err = stripSecretSettings(data); err != nil {
+				return nil, fmt.Errorf("failed to read settings file: %w", err)
+			}
+		}
+
+		if err := writeProfileFile(tw, name, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Accounts != nil {
+		accounts := slices.DeleteFunc(slices.Clone(opts.Accounts), func(a Account) bool { return a.IsAnonymous })
+
+		data, err := json.Marshal(accountFile{Accounts: accounts})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeProfileFile(tw, profileAccountsName, data); err != nil {
+			return nil, err
+		}
+
+		names = append(names, profileAccountsName)
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// profileFiles returns the configuration files in the config directory, relative to it
+func profileFiles(fs afero.Fs, p Paths) ([]string, error) {
+	var names []string
+
+	for _, name := range []string{settingsFileName, themeFileName, keyMapFileName, dictionaryName} {
+		_, err := fs.Stat(filepath.Join(p.Config, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+
+	if _, err := fs.Stat(p.ScriptDir()); errors.Is(err, os.ErrNotExist) {
+		return names, nil
+	}
+
+	err := afero.Walk(fs, p.ScriptDir(), func(file string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		rel, err := filepath.Rel(p.Config, file)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, filepath.ToSlash(rel))
+		return nil
+	})
+
+	return names, err
+}
+
+func writeProfileFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(data)
+	return err
+}
+
+// ReadProfile reads a profile archive written by ExportProfile
+func ReadProfile(r io.Reader) (Profile, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Profile{}, fmt.Errorf("not a Chatuino profile: %w", err)
+	}
+
+	defer gz.Close()
+
+	var (
+		tr       = tar.NewReader(gz)
+		profile  = Profile{Files: map[string][]byte{}}
+		manifest *profileManifest
+	)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return Profile{}, fmt.Errorf("failed to read profile: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return Profile{}, fmt.Errorf("unexpected entry %q in profile", header.Name)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxProfileFileSize+1))
+		if err != nil {
+			return Profile{}, fmt.Errorf("failed to read %s of profile: %w", header.Name, err)
+		}
+
+		if len(data) > maxProfileFileSize {
+			return Profile{}, fmt.Errorf("%s of profile is larger than %d MB", header.Name, maxProfileFileSize>>20)
+		}
+
+		switch {
+		case header.Name == profileManifestName:
+			manifest = &profileManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return Profile{}, fmt.Errorf("invalid manifest of profile: %w", err)
+			}
+		case header.Name == profileAccountsName:
+			var accounts accountFile
+			if err := json.Unmarshal(data, &accounts); err != nil {
+				return Profile{}, fmt.Errorf("invalid accounts of profile: %w", err)
+			}
+
+			profile.Accounts = accounts.Accounts
+		case isProfileFile(header.Name):
+			profile.Files[header.Name] = data
+		default:
+			return Profile{}, fmt.Errorf("unexpected file %q in profile", header.Name)
+		}
+	}
+
+	if manifest == nil {
+		return Profile{}, fmt.Errorf("not a Chatuino profile: %s is missing", profileManifestName)
+	}
+
+	if manifest.Version > profileVersion {
+		return Profile{}, fmt.Errorf("profile version %d is newer than the supported version %d, update Chatuino to import it", manifest.Version, profileVersion)
+	}
+
+	profile.Secrets = manifest.Secrets
+
+	return profile, nil
+}
+
+// isProfileFile reports if name is one of the configuration files, scripts must stay inside the script directory
+func isProfileFile(name string) bool {
+	switch name {
+	case settingsFileName, themeFileName, keyMapFileName, dictionaryName:
+		return true
+	}
+
+	script, ok := strings.CutPrefix(name, scriptDirName+"/")
+	return ok && filepath.IsLocal(script) && path.Clean(name) == name && !strings.Contains(name, `\`)
+}
+
+// Install writes the files of the profile into the config directory and returns their paths. The settings, theme and keymap are
+// validated before anything is written. Existing files are only replaced with overwrite, otherwise ErrProfileFilesExist is returned and nothing is written.
+// Settings of a profile without secrets keep the secret settings of the current settings file.
+func (p Profile) Install(fs afero.Fs, paths Paths, overwrite bool) ([]string, error) {
+	files := make(map[string][]byte, len(p.Files))
+	for name, data := range p.Files {
+		files[filepath.Join(paths.Config, filepath.FromSlash(name))] = data
+	}
+
+	settingsFile := paths.SettingsFile()
+	keymapProfile := KeyMapProfileDefault
+
+	// the keymap is checked with the profile selected by the installed settings, or the current ones if the profile has none
+	if current, err := afero.ReadFile(fs, settingsFile); err == nil {
+		if settings, report := CheckSettings(current); report.Err() == nil {
+			keymapProfile = settings.KeymapProfile
+		}
+	}
+
+	if data, ok := files[settingsFile]; ok {
+		if !p.Secrets {
+			current, err := afero.ReadFile(fs, settingsFile)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+
+			if data, err = keepSecretSettings(data, current); err != nil {
+				return nil, fmt.Errorf("invalid settings in profile: %w", err)
+			}
+
+			files[settingsFile] = data
+		}
+
+		settings, report := CheckSettings(data)
+		if report.Err() != nil {
+			return nil, fmt.Errorf("invalid settings in profile: %w", report.Err())
+		}
+
+		keymapProfile = settings.KeymapProfile
+	}
+
+	if data, ok := p.Files[themeFileName]; ok {
+		if _, err := parseThemeSet(data); err != nil {
+			return nil, fmt.Errorf("invalid %s in profile: %w", themeFileName, err)
+		}
+	}
+
+	if data, ok := p.Files[keyMapFileName]; ok {
+		m, err := parseKeyMap(data, keymapProfile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in profile: %w", keyMapFileName, err)
+		}
+
+		if _, err := parseChannelKeyMaps(data, m); err != nil {
+			return nil, fmt.Errorf("invalid %s in profile: %w", keyMapFileName, err)
+		}
+	}
+
+	written := slices.Sorted(maps.Keys(files))
+
+	if !overwrite {
+		var existing []string
+		for _, file := range written {
+			if _, err := fs.Stat(file); err == nil {
+				existing = append(existing, file)
+			}
+		}
+
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrProfileFilesExist, strings.Join(existing, ", "))
+		}
+	}
+
+	for _, file := range written {
+		if err := fs.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+			return nil, err
+		}
+
+		if err := afero.WriteFile(fs, file, files[file], 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	return written, nil
+}
+
+// StripSecretSettings removes the API keys and passwords from the settings file content, like exported profiles do
+func StripSecretSettings(b []byte) ([]byte, error) {
+	return stripSecretSettings(b)
+}
+
+// stripSecretSettings removes the secret settings from the settings file content
+func stripSecretSettings(b []byte) ([]byte, error) {
+	return editSettingsDocument(b, func(root *yaml.Node) error {
+		for _, setting := range secretSettings {
+			parts := strings.Split(setting, ".")
+			if parent := settingNode(root, parts[:len(parts)-1]); parent != nil && parent.Kind == yaml.MappingNode {
+				removeMappingEntry(parent, parts[len(parts)-1])
+			}
+		}
+
+		return nil
+	})
+}
+
+// keepSecretSettings copies the secret settings of the current settings file content into the imported settings
+func keepSecretSettings(imported, current []byte) ([]byte, error) {
+	// a broken current settings file has no secrets worth keeping, it is replaced
+	migrated, err := editSettingsDocument(current, func(*yaml.Node) error { return nil })
+	if err != nil {
+		return imported, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(migrated, &doc); err != nil {
+		return nil, err
+	}
+
+	return editSettingsDocument(imported, func(root *yaml.Node) error {
+		for _, setting := range secretSettings {
+			parts := strings.Split(setting, ".")
+
+			value := settingNode(doc.Content[0], parts)
+			if value == nil {
+				continue
+			}
+
+			node := root
+			for _, part := range parts[:len(parts)-1] {
+				node = ensureMapping(node, part)
+			}
+
+			removeMappingEntry(node, parts[len(parts)-1])
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: parts[len(parts)-1]}, value)
+		}
+
+		return nil
+	})
+}
+
+// settingNode returns the value of the setting with the path parts, nil if it is not part of the document
+func settingNode(root *yaml.Node, parts []string) *yaml.Node {
+	node := root
+	for _, part := range parts {
+		if node = mappingValue(node, part); node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
@@ -0,0 +1,195 @@
+package save
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile_exportImport(t *testing.T) {
+	t.Parallel()
+
+	source := Paths{Config: "/home/a/.config/chatuino"}
+	fs := afero.NewMemMapFs()
+
+	settings := "chat:\n  layout: compact\ntranslation:\n  backend: deepl\n  api_key: deepl-key\n"
+	require.NoError(t, afero.WriteFile(fs, source.SettingsFile(), []byte(settings), 0o600))
+	require.NoError(t, afero.WriteFile(fs, source.ThemeFile(), []byte("chat_border_color: \"#ffffff\"\n"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(source.ScriptDir(), "greet", "main.lua"), []byte("-- greet"), 0o600))
+
+	accounts := []Account{{ID: "1", DisplayName: "julez", AccessToken: "token"}, anonymousAccount}
+
+	var archive bytes.Buffer
+	files, err := ExportProfile(fs, source, &archive, ProfileExportOptions{Accounts: accounts})
+	require.NoError(t, err)
+	require.Equal(t, []string{"settings.yaml", "theme.yaml", "scripts/greet/main.lua", "accounts.json"}, files)
+
+	profile, err := ReadProfile(bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.False(t, profile.Secrets)
+	require.NotContains(t, string(profile.Files["settings.yaml"]), "deepl-key", "secrets are left out by default")
+	require.Contains(t, string(profile.Files["settings.yaml"]), "layout: compact")
+	require.Len(t, profile.Accounts, 1, "the anonymous account is not exported")
+	require.Equal(t, "token", profile.Accounts[0].AccessToken)
+
+	target := Paths{Config: "/home/b/.config/chatuino"}
+	require.NoError(t, afero.WriteFile(fs, target.SettingsFile(), []byte("translation:\n  api_key: other-key\n"), 0o600))
+
+	_, err = profile.Install(fs, target, false)
+	require.ErrorIs(t, err, ErrProfileFilesExist)
+
+	written, err := profile.Install(fs, target, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(target.Config, "scripts", "greet", "main.lua"),
+		target.SettingsFile(),
+		target.ThemeFile(),
+	}, written)
+
+	installed, err := afero.ReadFile(fs, target.SettingsFile())
+	require.NoError(t, err)
+
+	imported, report := CheckSettings(installed)
+	require.NoError(t, report.Err())
+	require.Equal(t, ChatLayoutCompact, imported.Chat.Layout)
+	require.Equal(t, "deepl", imported.Translation.Backend)
+	require.Equal(t, "other-key", imported.Translation.APIKey, "the secrets of the target are kept")
+
+	archive.Reset()
+	_, err = ExportProfile(fs, source, &archive, ProfileExportOptions{IncludeSecrets: true})
+	require.NoError(t, err)
+
+	profile, err = ReadProfile(&archive)
+	require.NoError(t, err)
+	require.Nil(t, profile.Accounts)
+	require.Contains(t, string(profile.Files["settings.yaml"]), "deepl-key")
+
+	_, err = profile.Install(fs, target, true)
+	require.NoError(t, err)
+
+	installed, err = afero.ReadFile(fs, target.SettingsFile())
+	require.NoError(t, err)
+	require.Contains(t, string(installed), "deepl-key")
+}
+
+func TestReadProfile_invalid(t *testing.T) {
+	t.Parallel()
+
+	archive := func(files map[string]string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+
+		for name, content := range files {
+			require.NoError(t, writeProfileFile(tw, name, []byte(content)))
+		}
+
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+
+		return &buf
+	}
+
+	manifest := `{"version":1}`
+
+	tests := map[string]struct {
+		archive *bytes.Buffer
+		err     string
+	}{
+		"no archive":       {archive: bytes.NewBufferString("settings: true"), err: "not a Chatuino profile"},
+		"no manifest":      {archive: archive(map[string]string{"settings.yaml": ""}), err: "manifest.json is missing"},
+		"newer version":    {archive: archive(map[string]string{"manifest.json": `{"version":2}`}), err: "profile version 2 is newer"},
+		"path traversal":   {archive: archive(map[string]string{"manifest.json": manifest, "scripts/../../.bashrc": ""}), err: `unexpected file "scripts/../../.bashrc"`},
+		"absolute path":    {archive: archive(map[string]string{"manifest.json": manifest, "/etc/passwd": ""}), err: "unexpected file"},
+		"unknown file":     {archive: archive(map[string]string{"manifest.json": manifest, "state.json": ""}), err: "unexpected file"},
+		"invalid accounts": {archive: archive(map[string]string{"manifest.json": manifest, "accounts.json": "["}), err: "invalid accounts"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ReadProfile(tt.archive)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestProfile_Install_invalidSettings(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	p := Paths{Config: "/config"}
+
+	profile := Profile{Files: map[string][]byte{
+		"settings.yaml":      []byte("chat:\n  layout: sideways\n"),
+		"scripts/a/main.lua": []byte("-- a"),
+	}}
+
+	_, err := profile.Install(fs, p, true)
+	require.ErrorContains(t, err, "invalid settings in profile")
+
+	exists, err := afero.Exists(fs, filepath.Join(p.ScriptDir(), "a", "main.lua"))
+	require.NoError(t, err)
+	require.False(t, exists, "nothing is written")
+}
+
+func TestProfile_Install_invalidThemeOrKeymap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string][]byte
+		want  string
+	}{
+		{
+			name:  "unknown keymap base profile",
+			files: map[string][]byte{"keymap.yaml": []byte("profiles:\n  mine:\n    base: nano\n")},
+			want:  "invalid keymap.yaml in profile",
+		},
+		{
+			name: "keymap profile of the settings is missing",
+			files: map[string][]byte{
+				"settings.yaml": []byte("keymap_profile: mine\n"),
+				"keymap.yaml":   []byte("quit: ctrl+q\n"),
+			},
+			want: "invalid keymap.yaml in profile",
+		},
+		{
+			name:  "unknown active theme",
+			files: map[string][]byte{"theme.yaml": []byte("active: neon\n")},
+			want:  "invalid theme.yaml in profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			p := Paths{Config: "/config"}
+
+			_, err := Profile{Files: tt.files}.Install(fs, p, true)
+			require.ErrorContains(t, err, tt.want)
+
+			exists, err := afero.Exists(fs, p.KeymapFile())
+			require.NoError(t, err)
+			require.False(t, exists, "nothing is written")
+		})
+	}
+}
+
+func TestSecretSettings(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range SettingOptions() {
+		if o.Secret {
+			require.Contains(t, secretSettings, o.Path, "secret settings must not be exported")
+		}
+	}
+}
@@ -1,27 +1,111 @@
 package save
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"regexp"
 	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/julez-dev/chatuino/command"
+	"github.com/julez-dev/chatuino/hook/filter"
+	"github.com/julez-dev/chatuino/imageupload"
+	"github.com/julez-dev/chatuino/profanity"
+	"github.com/julez-dev/chatuino/remotesync"
+	"github.com/julez-dev/chatuino/shortlink"
 	"github.com/spf13/afero"
-	"gopkg.in/yaml.v3"
 )
 
 const (
 	settingsFileName = "settings.yaml"
 )
 
+// Timestamp formats for chat messages
+const (
+	TimestampFormatSeconds  = "hh:mm:ss"
+	TimestampFormatMinutes  = "hh:mm"
+	TimestampFormatRelative = "relative"
+	TimestampFormatOff      = "off"
+)
+
+// Layouts for chat messages
+const (
+	ChatLayoutStandard = "standard" // messages are wrapped onto multiple lines
+	ChatLayoutCompact  = "compact"  // every message uses a single line and is cut off at the end
+	ChatLayoutCozy     = "cozy"     // a blank line between messages and an aligned name column
+)
+
+// Ways to send chat messages
+const (
+	SendMethodHelix = "helix" // Helix Send Chat Message API, falls back to IRC if the request fails
+	SendMethodIRC   = "irc"   // IRC PRIVMSG only
+)
+
+// Badge visibility modes
+const (
+	BadgeShowAll   = "all"
+	BadgeShowRoles = "roles" // only broadcaster, moderator and VIP badges
+	BadgeShowNone  = "none"
+)
+
+// Username color modes
+const (
+	UsernameColorsTwitch     = "twitch"     // the colors chosen on Twitch
+	UsernameColorsColorblind = "colorblind" // Twitch colors mapped to the nearest color of a palette safe for deuteranopia and protanopia
+)
+
+// Clock styles for timestamps
+const (
+	TimestampClock24h = "24h"
+	TimestampClock12h = "12h"
+)
+
 type Settings struct {
-	VerticalTabList bool               `yaml:"vertical_tab_list"`
-	Moderation      ModerationSettings `yaml:"moderation"`
-	Chat            ChatSettings       `yaml:"chat"`
-	CustomCommands  []CustomCommand    `yaml:"custom_commands"`
-	BlockSettings   BlockSettings      `yaml:"block_settings"`
-	Security        SecuritySettings   `yaml:"security"`
+	Version          int                      `yaml:"version"` // schema version, see CurrentSettingsVersion
+	VerticalTabList  bool                     `yaml:"vertical_tab_list"`
+	KeymapProfile    string                   `yaml:"keymap_profile"` // built-in (default, vim, emacs) or custom profile defined in keymap.yaml
+	Moderation       ModerationSettings       `yaml:"moderation"`
+	Chat             ChatSettings             `yaml:"chat"`
+	Timestamps       TimestampSettings        `yaml:"timestamps"`
+	CustomCommands   []CustomCommand          `yaml:"custom_commands"`
+	Snippets         []Snippet                `yaml:"snippets"`
+	Timers           []Timer                  `yaml:"timers"`
+	BlockSettings    BlockSettings            `yaml:"block_settings"`
+	Profanity        ProfanitySettings        `yaml:"profanity"`
+	Favorites        FavoriteSettings         `yaml:"favorites"`
+	Security         SecuritySettings         `yaml:"security"`
+	Session          SessionSettings          `yaml:"session"`
+	FollowedSidebar  FollowedSidebarSettings  `yaml:"followed_sidebar"`
+	GoLive           GoLiveSettings           `yaml:"go_live"`
+	NotificationTray NotificationTraySettings `yaml:"notification_tray"`
+	StreamInfo       StreamInfoSettings       `yaml:"stream_info"`
+	Presence         PresenceSettings         `yaml:"presence"`
+	StatusBar        StatusBarSettings        `yaml:"status_bar"`
+	Idle             IdleSettings             `yaml:"idle"`
+	Links            LinkSettings             `yaml:"links"`
+	ImageUpload      ImageUploadSettings      `yaml:"image_upload"`
+	Mouse            MouseSettings            `yaml:"mouse"`
+	Tmux             TmuxSettings             `yaml:"tmux"`
+	SSH              SSHSettings              `yaml:"ssh"`
+	Accessibility    AccessibilitySettings    `yaml:"accessibility"`
+	Player           PlayerSettings           `yaml:"player"`
+	IPC              IPCSettings              `yaml:"ipc"`
+	Proxy            ProxySettings            `yaml:"proxy"`
+	Sync             SyncSettings             `yaml:"sync"`
+	UpdateCheck      UpdateCheckSettings      `yaml:"update_check"`
+	APICache         APICacheSettings         `yaml:"api_cache"`
+	Translation      TranslationSettings      `yaml:"translation"`
+	Spellcheck       SpellcheckSettings       `yaml:"spellcheck"`
+	YouTube          YouTubeSettings          `yaml:"youtube"`
+	Kick             KickSettings             `yaml:"kick"`
+	OBS              OBSSettings              `yaml:"obs"`
+	Bot              BotSettings              `yaml:"bot"`
+	Sounds           SoundSettings            `yaml:"sounds"`
+	Hooks            []Hook                   `yaml:"hooks"`
 }
 
 type ModerationSettings struct {
@@ -33,62 +117,1083 @@ type ModerationSettings struct {
 type ChatSettings struct {
 	GraphicBadges              bool `yaml:"graphic_badges"`
 	GraphicEmotes              bool `yaml:"graphic_emotes"`
-	DisableBadges              bool `yaml:"disable_badges"`
 	DisablePaddingWrappedLines bool `yaml:"disable_padding_wrapped_lines"`
+	AutoSplitLongMessages      bool `yaml:"auto_split_long_messages"`
+
+	Badges         BadgeSettings     `yaml:"badges"`
+	Layout         string            `yaml:"layout"`
+	ChannelLayouts map[string]string `yaml:"channel_layouts"` // channel login to layout, overrides layout
+
+	// GroupMessages shows the badges and name once for consecutive messages of the same author
+	GroupMessages bool `yaml:"group_messages"`
+
+	SendMethod         string            `yaml:"send_method"`          // helix or irc
+	AccountSendMethods map[string]string `yaml:"account_send_methods"` // account name to send method, overrides send_method
+
+	// SendDelay holds sent messages back for this long, they can be canceled meanwhile, 0 sends right away
+	SendDelay time.Duration `yaml:"send_delay"`
+
+	// AutoModCheck asks AutoMod before sending if a message would be held, only possible with the account of the broadcaster
+	AutoModCheck bool `yaml:"automod_check"`
+
+	// UserAliases are local names shown instead of the display name of a user, keyed by user login
+	UserAliases map[string]string `yaml:"user_aliases"`
+
+	// UsernameMinContrast is the minimum contrast ratio (WCAG, 1-21) of user colors against the terminal background, 0 disables the adjustment
+	UsernameMinContrast float64 `yaml:"username_min_contrast"`
+
+	// SevenTVCosmetics shows the name paints and badges chatters selected on 7TV, fetched once per chatter
+	SevenTVCosmetics bool `yaml:"seventv_cosmetics"`
+
+	// RenderFPS is how often the UI is repainted at most, incoming chat messages are applied in batches at the same rate
+	RenderFPS int `yaml:"render_fps"`
+
+	// MaxMessagesPerSecond is the number of messages shown per channel and second, further messages are summarized, 0 shows all messages
+	MaxMessagesPerSecond int `yaml:"max_messages_per_second"`
+
+	SpamFade SpamFadeSettings `yaml:"spam_fade"`
+
+	EmoteCompression EmoteCompressionSettings `yaml:"emote_compression"`
+
+	EmoteUpdates EmoteUpdateSettings `yaml:"emote_updates"`
+}
+
+// LayoutFor returns the message layout for a channel, falling back to the global layout
+func (s ChatSettings) LayoutFor(channel string) string {
+	for c, layout := range s.ChannelLayouts {
+		if strings.EqualFold(c, channel) {
+			return layout
+		}
+	}
+
+	if s.Layout == "" {
+		return ChatLayoutStandard
+	}
+
+	return s.Layout
+}
+
+// SendMethodFor returns how messages of an account are sent, falling back to the global send method
+func (s ChatSettings) SendMethodFor(account string) string {
+	for a, method := range s.AccountSendMethods {
+		if strings.EqualFold(a, account) {
+			return method
+		}
+	}
+
+	if s.SendMethod == "" {
+		return SendMethodHelix
+	}
+
+	return s.SendMethod
+}
+
+// AliasFor returns the local alias of a user, empty if the user has none
+func (s ChatSettings) AliasFor(login string) string {
+	for l, alias := range s.UserAliases {
+		if strings.EqualFold(l, login) {
+			return alias
+		}
+	}
+
+	return ""
+}
+
+type BadgeSettings struct {
+	Show         string            `yaml:"show"`          // all, roles or none
+	Glyphs       bool              `yaml:"glyphs"`        // show short glyphs instead of badge names, only used without graphic badges
+	CustomGlyphs map[string]string `yaml:"custom_glyphs"` // badge set ID (e.g. subscriber) to text shown instead of the badge name
+}
+
+// SpamFadeSettings configure fading the messages of users writing faster than a limit
+type SpamFadeSettings struct {
+	Messages int           `yaml:"messages"` // messages a user can send within window before further messages are faded, 0 disables fading
+	Window   time.Duration `yaml:"window"`
+	Collapse bool          `yaml:"collapse"` // cut faded messages off after one line
+}
+
+// EmoteCompressionSettings configure showing messages which only repeat one emote as the emote with a count, like "catJAM x12"
+type EmoteCompressionSettings struct {
+	Enabled    bool            `yaml:"enabled"`     // compress messages in all channels
+	MinRepeats int             `yaml:"min_repeats"` // how often the emote must be repeated before the message is compressed
+	Channels   map[string]bool `yaml:"channels"`    // channel login to compressing messages, overrides enabled
+}
+
+// EnabledFor reports if messages are compressed in a channel, falling back to the global setting
+func (s EmoteCompressionSettings) EnabledFor(channel string) bool {
+	for c, enabled := range s.Channels {
+		if strings.EqualFold(c, channel) {
+			return enabled
+		}
+	}
+
+	return s.Enabled
+}
+
+// EmoteUpdateSettings configure reloading the 7TV, BTTV and FFZ emotes of open channels
+type EmoteUpdateSettings struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // how often the emotes of open channels are reloaded, 0 disables it
+	Notify          bool          `yaml:"notify"`           // post a notice in chat listing added and removed emotes
+}
+
+type TimestampSettings struct {
+	Format         string `yaml:"format"` // hh:mm:ss, hh:mm, relative or off
+	Clock          string `yaml:"clock"`  // 24h or 12h
+	DateSeparators bool   `yaml:"date_separators"`
 }
 
 type BlockSettings struct {
 	Users []string `yaml:"users"`
 	Words []string `yaml:"words"`
+	Bots  bool     `yaml:"bots"` // hide messages of known bots of FFZ and BTTV
+}
+
+// ProfanitySettings configure masking of profanity, matched words are replaced with asterisks instead of hiding the message
+type ProfanitySettings struct {
+	Mask         bool            `yaml:"mask"`          // mask profanity in all channels
+	DefaultWords bool            `yaml:"default_words"` // mask the built-in list of common English swear words
+	Words        []string        `yaml:"words"`         // masked in addition to the built-in list, a trailing * matches all words starting with the word
+	Channels     map[string]bool `yaml:"channels"`      // channel login to masking profanity, overrides mask
+}
+
+// MaskFor reports if profanity is masked in a channel, falling back to the global setting
+func (s ProfanitySettings) MaskFor(channel string) bool {
+	for c, mask := range s.Channels {
+		if strings.EqualFold(c, channel) {
+			return mask
+		}
+	}
+
+	return s.Mask
+}
+
+// MaskedWords returns the words which are masked
+func (s ProfanitySettings) MaskedWords() []string {
+	if !s.DefaultWords {
+		return s.Words
+	}
+
+	return append(slices.Clone(profanity.DefaultWords), s.Words...)
+}
+
+// FavoriteSettings configure users whose messages are highlighted with the favorite color of the theme
+type FavoriteSettings struct {
+	Users          []string `yaml:"users"`            // user logins
+	ShowInMentions bool     `yaml:"show_in_mentions"` // also show messages of favorites in the mentions tab
+}
+
+// IsFavorite reports if a user is a favorite
+func (s FavoriteSettings) IsFavorite(login string) bool {
+	return slices.ContainsFunc(s.Users, func(u string) bool {
+		return strings.EqualFold(u, login)
+	})
 }
 
 type SecuritySettings struct {
 	CheckLinks bool `yaml:"check_links"`
 }
 
+type SessionSettings struct {
+	RestoreTabs        bool `yaml:"restore_tabs"`
+	RestoreAfterCrash  bool `yaml:"restore_after_crash"` // restore the tabs after a crash, even if RestoreTabs is false
+	SharedInputHistory bool `yaml:"shared_input_history"`
+	InputHistorySize   int  `yaml:"input_history_size"`
+}
+
+type FollowedSidebarSettings struct {
+	ShowOnStartup   bool          `yaml:"show_on_startup"`
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// GoLiveSettings notify when followed channels go live
+type GoLiveSettings struct {
+	Enabled         bool          `yaml:"enabled"`
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	Channels        []string      `yaml:"channels"`       // channel logins notified about, empty notifies about all followed channels
+	Desktop         bool          `yaml:"desktop"`        // also show a system notification
+	ToastDuration   time.Duration `yaml:"toast_duration"` // how long the notification is shown in Chatuino
+}
+
+// NotificationTraySettings configure the line at the bottom collecting whispers, mentions in background tabs and go live notifications until they are dismissed
+type NotificationTraySettings struct {
+	Enabled       bool          `yaml:"enabled"`
+	CycleInterval time.Duration `yaml:"cycle_interval"` // how long each pending notification is shown before the next one
+}
+
+// PresenceSettings track which chatters are in small channels, Twitch only sends joins and parts for channels with less than 1000 chatters
+type PresenceSettings struct {
+	Enabled    bool `yaml:"enabled"`
+	MaxViewers int  `yaml:"max_viewers"` // only channels with at most this many viewers are tracked, offline channels have no viewers
+	ShowLines  bool `yaml:"show_lines"`  // show a line in chat when a chatter joins or leaves
+}
+
+type StreamInfoSettings struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+
+	// ActivityGraph shows the messages per minute of the last 30 minutes as a sparkline next to the stream info
+	ActivityGraph bool `yaml:"activity_graph"`
+}
+
+// DefaultStatusBarTemplate shows the mode on the left and the chat modes, OBS, degraded mode and chat connection on the right
+const DefaultStatusBarTemplate = "{mode} {keys}{right}{chat_modes} | {obs} | {degraded} | {connection}"
+
+// StatusBarPlaceholders are the placeholders which are replaced with their current value in the status bar template
+var StatusBarPlaceholders = []string{"mode", "keys", "channel", "uptime", "viewers", "present", "latency", "time", "unread", "chat_modes", "obs", "degraded", "connection"}
+
+// StatusBarSections are the markers which place the following text on the left, in the center or on the right of the status bar
+var StatusBarSections = []string{"left", "center", "right"}
+
+type StatusBarSettings struct {
+	Template string `yaml:"template"` // text with {placeholders}, parts separated by " | " are hidden when empty
+}
+
+// ParseStatusBarTemplate splits the template into its left, center and right section
+// and returns an error if it contains unknown placeholders or a section more than once.
+func ParseStatusBarTemplate(template string) (sections [3]string, err error) {
+	seen := [3]bool{}
+	current := 0
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start == -1 {
+			sections[current] += template
+			return sections, nil
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end == -1 {
+			return sections, fmt.Errorf("placeholder %q is not closed", template[start:])
+		}
+
+		name := template[start+1 : start+end]
+
+		if i := slices.Index(StatusBarSections, name); i != -1 {
+			if seen[i] {
+				return sections, fmt.Errorf("section {%s} is used more than once", name)
+			}
+
+			seen[i] = true
+			sections[current] += template[:start]
+			current = i
+		} else {
+			if !slices.Contains(StatusBarPlaceholders, name) {
+				return sections, fmt.Errorf("unknown placeholder {%s}, must be one of {%s}", name, strings.Join(StatusBarPlaceholders, "}, {"))
+			}
+
+			sections[current] += template[:start+end+1]
+		}
+
+		template = template[start+end+1:]
+	}
+}
+
+type IdleSettings struct {
+	Timeout time.Duration `yaml:"timeout"` // without input for this long background refreshes are paused, 0 disables it
+}
+
+type PlayerSettings struct {
+	Command string `yaml:"command"` // {channel} is replaced with the channel login
+}
+
+// UpdateCheckSettings configure the check for new releases on startup
+type UpdateCheckSettings struct {
+	Enabled bool `yaml:"enabled"` // the latest release is fetched from GitHub at most once a day
+}
+
+// APICacheSettings configure how long responses of read-only Twitch API endpoints are reused, 0 disables the cache of an endpoint.
+// The responses are stored on disk and shared by all accounts and running instances.
+type APICacheSettings struct {
+	Users  time.Duration `yaml:"users"`  // users looked up by login or id
+	Badges time.Duration `yaml:"badges"` // global and channel chat badges
+}
+
+// Backends selected messages can be translated with
+const (
+	TranslationBackendDeepL          = "deepl"
+	TranslationBackendLibreTranslate = "libretranslate"
+)
+
+// TranslationSettings configure the translation of selected messages, see the translate package
+type TranslationSettings struct {
+	Backend        string `yaml:"backend"`         // deepl or libretranslate, empty disables translations
+	URL            string `yaml:"url"`             // URL of the LibreTranslate instance, empty uses the DeepL API matching the key
+	APIKey         string `yaml:"api_key"`         // required for DeepL, only required by some LibreTranslate instances
+	TargetLanguage string `yaml:"target_language"` // language code like en or de
+}
+
+// SpellcheckSettings configure the spell checker of the message input, see the spellcheck package
+type SpellcheckSettings struct {
+	Enabled    bool   `yaml:"enabled"`
+	Language   string `yaml:"language"`   // hunspell dictionary name like en_US, searched in the usual dictionary directories
+	Dictionary string `yaml:"dictionary"` // path of a .dic file or word list, overrides language
+}
+
+// YouTubeSettings enable tabs of YouTube live chats (experimental), see the youtube package
+type YouTubeSettings struct {
+	APIKey string `yaml:"api_key"` // YouTube Data API key, required to read live chats, empty disables YouTube tabs
+
+	// the OAuth client and a refresh token of the account with the youtube.force-ssl scope, required to send messages
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	RefreshToken string `yaml:"refresh_token"`
+}
+
+// KickSettings enable read only tabs of Kick chats (experimental), see the kick package
+type KickSettings struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// isLanguageCode reports whether code looks like a language code, two or three letters optionally followed by a region like pt-br
+func isLanguageCode(code string) bool {
+	lang, region, hasRegion := strings.Cut(code, "-")
+	if len(lang) < 2 || len(lang) > 3 || hasRegion && (len(region) < 2 || len(region) > 4) {
+		return false
+	}
+
+	return !strings.ContainsFunc(lang+region, func(r rune) bool {
+		return !unicode.IsLetter(r) || r > unicode.MaxASCII
+	})
+}
+
+type LinkSettings struct {
+	Opener        string `yaml:"opener"`         // command used to open links, the link is appended as last argument; empty uses the system default
+	Shortener     string `yaml:"shortener"`      // URL of a shortener answering with the short link as plain text, {url} is replaced with the link; empty disables shortening
+	ShortenLength int    `yaml:"shorten_length"` // links of sent messages longer than this are offered to be shortened
+}
+
+// ImageUploadSettings configure the upload of images pasted into the message input, the link of the image is inserted instead
+type ImageUploadSettings struct {
+	Host   string `yaml:"host"`    // catbox, kappa or the URL of a host accepting the image in the file field of a multipart form; empty disables uploads
+	APIKey string `yaml:"api_key"` // catbox user hash or the Authorization header of other hosts, empty uploads anonymously
+}
+
+// MouseSettings configure mouse support. While it is enabled, terminals only select text with a modifier key held, usually shift.
+type MouseSettings struct {
+	Enabled bool `yaml:"enabled"` // the wheel scrolls the chat, clicks select messages, open links and switch tabs
+}
+
+// TmuxSettings configure the integration with tmux, only used while Chatuino runs inside tmux
+type TmuxSettings struct {
+	WindowTitle bool `yaml:"window_title"` // rename the window to the focused channel, the previous name is restored on exit
+}
+
+// SSHSettings configure the conservative rendering profile, used while Chatuino runs in an SSH session
+type SSHSettings struct {
+	Graphics       bool          `yaml:"graphics"`         // keep graphic emotes and badges, their pixel data is sent through the connection
+	ImageChunkSize int           `yaml:"image_chunk_size"` // base64 bytes of pixel data per escape sequence, a multiple of 4 up to 4096
+	ProbeTimeout   time.Duration `yaml:"probe_timeout"`    // how long queries of the terminal, like the background color, wait for an answer
+}
+
+// AccessibilitySettings adapt Chatuino to assistive technology
+type AccessibilitySettings struct {
+	// ScreenReader disables graphics and decorative glyphs, shows messages as "user: message" lines, announces tab switches in the chat
+	// and repaints the screen less often, so terminal screen readers can follow the chat
+	ScreenReader bool `yaml:"screen_reader"`
+	// ReducedMotion stops animated emotes on their first frame and disables spinners, the notification tray cycling
+	// and the constant refresh of the activity graph
+	ReducedMotion bool `yaml:"reduced_motion"`
+	// UsernameColors maps the username colors into a colorblind safe palette, each Twitch color always maps to the same palette color
+	UsernameColors string `yaml:"username_colors"`
+}
+
+// IPCSettings configure the control socket other programs can use to control Chatuino, see the ipc package
+type IPCSettings struct {
+	Enabled bool   `yaml:"enabled"`
+	Socket  string `yaml:"socket"` // path of the socket, empty uses chatuino.sock in the runtime directory
+}
+
+// ProxySettings configure the proxy of all connections to Twitch and the emote providers
+type ProxySettings struct {
+	URL      string   `yaml:"url"`      // http, https, socks5 or socks5h proxy, empty uses the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	Username string   `yaml:"username"` // overrides the user of the URL
+	Password string   `yaml:"password"` // overrides the password of the URL
+	NoProxy  []string `yaml:"no_proxy"` // hosts and domains which are connected to directly
+}
+
+// SyncSettings configure syncing the configuration and notes with a git repository or a WebDAV server, see the remotesync package
+type SyncSettings struct {
+	Backend  string `yaml:"backend"`  // git or webdav, empty disables syncing
+	Remote   string `yaml:"remote"`   // URL of the git repository or of the WebDAV directory
+	Branch   string `yaml:"branch"`   // branch of the git repository
+	Username string `yaml:"username"` // user of the WebDAV server
+	Password string `yaml:"password"` // password of the WebDAV server
+	Auto     bool   `yaml:"auto"`     // sync before the UI starts and after it is quit
+}
+
+// ProxyURL returns the proxy URL with the configured credentials, nil if no proxy is configured
+func (s ProxySettings) ProxyURL() (*url.URL, error) {
+	if s.URL == "" {
+		return nil, nil
+	}
+
+	// errors don't contain the URL, which may contain the password
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		urlErr := &url.Error{}
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return nil, fmt.Errorf("proxy URL is invalid: %w", err)
+	}
+
+	if !slices.Contains([]string{"http", "https", "socks5", "socks5h"}, u.Scheme) || u.Host == "" {
+		return nil, errors.New("proxy URL must look like http://host:port or socks5://host:port")
+	}
+
+	username, password := u.User.Username(), ""
+	if p, ok := u.User.Password(); ok {
+		password = p
+	}
+
+	if s.Username != "" {
+		username = s.Username
+	}
+
+	if s.Password != "" {
+		password = s.Password
+	}
+
+	switch {
+	case password != "":
+		u.User = url.UserPassword(username, password)
+	case username != "":
+		u.User = url.User(username)
+	}
+
+	return u, nil
+}
+
+// OBSSettings configure the connection to obs-websocket, see the obs package
+type OBSSettings struct {
+	Enabled  bool   `yaml:"enabled"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Password string `yaml:"password"` // empty if authentication is disabled in OBS
+}
+
+// Match types of bot replies
+const (
+	BotMatchCommand  = "command"  // the first word of the message equals the trigger
+	BotMatchContains = "contains" // the message contains the trigger
+)
+
+// BotSettings configure the headless bot started with the bot command
+type BotSettings struct {
+	Account  string     `yaml:"account"`  // display name of the account the bot runs as, empty uses the main account
+	Channels []string   `yaml:"channels"` // channels joined on start
+	LogChat  bool       `yaml:"log_chat"` // print chat messages of the joined channels
+	Replies  []BotReply `yaml:"replies"`
+}
+
+type BotReply struct {
+	Trigger  string        `yaml:"trigger"`
+	Match    string        `yaml:"match"`    // command or contains, empty means command
+	Response string        `yaml:"response"` // {user}, {channel} and {args} are replaced
+	Cooldown time.Duration `yaml:"cooldown"` // per channel
+	Channels []string      `yaml:"channels"` // channels the reply is used in, empty means all
+}
+
+// Events hooks can be run for
+const (
+	HookEventMessage = "message"
+	HookEventMention = "mention"
+	HookEventRaid    = "raid"
+	HookEventSub     = "sub"
+	HookEventWhisper = "whisper"
+)
+
+// HookEvents are all events hooks and sound rules can be configured for
+var HookEvents = []string{HookEventMessage, HookEventMention, HookEventRaid, HookEventSub, HookEventWhisper}
+
+// HookFilterFields are the event fields available in hook filters
+var HookFilterFields = []string{"event", "channel", "user", "display_name", "message", "mod", "subscriber", "bot", "viewers", "months", "plan", "gifter"}
+
+// DefaultHookTimeout is used for hooks without a timeout
+const DefaultHookTimeout = time.Second * 10
+
+// Hook runs an external command on chat events
+type Hook struct {
+	Event   string        `yaml:"event"`   // message, mention, raid, sub or whisper
+	Command string        `yaml:"command"` // split by whitespace, no shell is involved. The event is written as JSON to stdin.
+	Filter  string        `yaml:"filter"`  // expression the event has to match, see the filter package
+	Timeout time.Duration `yaml:"timeout"` // the command is killed afterwards, 0 uses DefaultHookTimeout
+}
+
+// SoundSettings play an audio file when chat events match one of the rules, e.g. for highlighted messages, raids or whispers
+type SoundSettings struct {
+	Enabled  bool          `yaml:"enabled"`
+	Player   string        `yaml:"player"`   // split by whitespace, {file} is replaced with the audio file, empty uses afplay on macOS and paplay otherwise
+	File     string        `yaml:"file"`     // played for rules without their own file
+	Cooldown time.Duration `yaml:"cooldown"` // events right after a sound don't play another one
+	Rules    []SoundRule   `yaml:"rules"`
+}
+
+// SoundRule plays a sound for matching events, only the first matching rule plays its sound
+type SoundRule struct {
+	Event  string `yaml:"event"`  // same events as hooks
+	Filter string `yaml:"filter"` // same expressions as hook filters
+	File   string `yaml:"file"`   // overrides sounds.file
+}
+
 type CustomCommand struct {
 	Trigger     string `yaml:"trigger"`
 	Replacement string `yaml:"replacement"`
 }
 
+// SnippetPlaceholders are replaced with their current value when a snippet is expanded
+var SnippetPlaceholders = []string{"channel", "user-under-cursor", "account"}
+
+// snippetPlaceholderPattern matches placeholders like {channel}, other text in braces is kept as is
+var snippetPlaceholderPattern = regexp.MustCompile(`\{([a-z][a-z_-]*)\}`)
+
+// Snippet is text inserted into the message input when its trigger is typed, followed by a space
+type Snippet struct {
+	Trigger string `yaml:"trigger"` // single word, like ;gg
+	Text    string `yaml:"text"`    // may contain SnippetPlaceholders
+}
+
+// Expand returns the text of the snippet with the placeholders replaced by their values, placeholders without a value are removed
+func (s Snippet) Expand(values map[string]string) string {
+	return snippetPlaceholderPattern.ReplaceAllStringFunc(s.Text, func(placeholder string) string {
+		return values[placeholder[1:len(placeholder)-1]]
+	})
+}
+
+// SnippetFor returns the snippet with the trigger
+func (s Settings) SnippetFor(trigger string) (Snippet, bool) {
+	for _, snippet := range s.Snippets {
+		if snippet.Trigger == trigger {
+			return snippet, true
+		}
+	}
+
+	return Snippet{}, false
+}
+
+// MinTimerInterval is the shortest interval of timers, so they can't flood the chat
+const MinTimerInterval = time.Minute
+
+// Timer sends a message to a channel at an interval, while there is enough chat activity
+type Timer struct {
+	Name        string        `yaml:"name"`         // single word, used to turn the timer on and off with /timer
+	Channel     string        `yaml:"channel"`      // channel login the message is sent to
+	Account     string        `yaml:"account"`      // display name of the sending account, empty uses the account of the broadcaster
+	Message     string        `yaml:"message"`      // sent like a typed message, commands like /announcement are not run
+	Interval    time.Duration `yaml:"interval"`     // at least MinTimerInterval
+	MinMessages int           `yaml:"min_messages"` // chat messages of others since the last time, 0 sends regardless of chat activity
+	Disabled    bool          `yaml:"disabled"`     // the timer starts turned off
+}
+
+// TimersFor returns the timers sent to the channel by the account, identified by its display name
+func (s Settings) TimersFor(channel, account string, isBroadcaster bool) []Timer {
+	var timers []Timer
+	for _, timer := range s.Timers {
+		if !strings.EqualFold(timer.Channel, channel) {
+			continue
+		}
+
+		if timer.Account == "" && isBroadcaster || timer.Account != "" && strings.EqualFold(timer.Account, account) {
+			timers = append(timers, timer)
+		}
+	}
+
+	return timers
+}
+
 func BuildDefaultSettings() Settings {
 	return Settings{
+		Version:       CurrentSettingsVersion,
+		KeymapProfile: KeyMapProfileDefault,
 		Moderation: ModerationSettings{
 			StoreChatLogs: true,
 		},
 		Security: SecuritySettings{
 			CheckLinks: true,
 		},
+		Profanity: ProfanitySettings{
+			DefaultWords: true,
+		},
+		Session: SessionSettings{
+			RestoreTabs:       true,
+			RestoreAfterCrash: true,
+			InputHistorySize:  100,
+		},
+		FollowedSidebar: FollowedSidebarSettings{
+			RefreshInterval: time.Minute * 2,
+		},
+		GoLive: GoLiveSettings{
+			RefreshInterval: time.Minute * 2,
+			ToastDuration:   time.Second * 15,
+		},
+		NotificationTray: NotificationTraySettings{
+			CycleInterval: time.Second * 5,
+		},
+		Presence: PresenceSettings{
+			MaxViewers: 100,
+		},
+		StreamInfo: StreamInfoSettings{
+			RefreshInterval: time.Second * 90,
+			ActivityGraph:   true,
+		},
+		StatusBar: StatusBarSettings{
+			Template: DefaultStatusBarTemplate,
+		},
+		Idle: IdleSettings{
+			Timeout: time.Minute * 5,
+		},
+		Player: PlayerSettings{
+			Command: "streamlink twitch.tv/{channel} best",
+		},
+		Sync: SyncSettings{
+			Branch: "main",
+		},
+		UpdateCheck: UpdateCheckSettings{
+			Enabled: true,
+		},
+		APICache: APICacheSettings{
+			Users:  time.Hour * 24,
+			Badges: time.Hour * 6,
+		},
+		Translation: TranslationSettings{
+			TargetLanguage: "en",
+		},
+		Spellcheck: SpellcheckSettings{
+			Language: "en_US",
+		},
+		OBS: OBSSettings{
+			Host: "localhost",
+			Port: 4455,
+		},
+		Bot: BotSettings{
+			LogChat: true,
+		},
+		Sounds: SoundSettings{
+			Cooldown: time.Second * 2,
+			Rules: []SoundRule{
+				{Event: HookEventMention},
+				{Event: HookEventRaid},
+				{Event: HookEventWhisper},
+			},
+		},
+		Chat: ChatSettings{
+			Layout:     ChatLayoutStandard,
+			SendMethod: SendMethodHelix,
+			RenderFPS:  60,
+			Badges: BadgeSettings{
+				Show: BadgeShowAll,
+			},
+			SpamFade: SpamFadeSettings{
+				Window: time.Second * 10,
+			},
+			EmoteCompression: EmoteCompressionSettings{
+				MinRepeats: 3,
+			},
+			EmoteUpdates: EmoteUpdateSettings{
+				RefreshInterval: time.Minute * 10,
+				Notify:          true,
+			},
+		},
+		Links: LinkSettings{
+			ShortenLength: 60,
+		},
+		Tmux: TmuxSettings{
+			WindowTitle: true,
+		},
+		SSH: SSHSettings{
+			Graphics:       true,
+			ImageChunkSize: 1024,
+			ProbeTimeout:   10 * time.Second,
+		},
+		Accessibility: AccessibilitySettings{
+			UsernameColors: UsernameColorsTwitch,
+		},
+		Timestamps: TimestampSettings{
+			Format: TimestampFormatSeconds,
+			Clock:  TimestampClock24h,
+		},
 	}
 }
 
+// validate checks the settings and returns all problems joined, each problem is a fieldError pointing to the invalid setting
 func (s Settings) validate() error {
+	var errs []error
+
 	if len(s.Moderation.LogsChannelExclude) > 0 && len(s.Moderation.LogsChannelInclude) > 0 {
-		return fmt.Errorf("cant't have both of logs_channel_include and logs_channel_exclude in settings.moderation")
+		errs = append(errs, invalidField("moderation.logs_channel_exclude", "cant't have both of logs_channel_include and logs_channel_exclude in settings.moderation"))
 	}
 
-	for _, c := range s.CustomCommands {
+	// combine CommandSuggestions and CustomCommands to check for collisions for custom commands
+	predefinedCommands := append(command.CommandSuggestions[:], command.ModeratorSuggestions[:]...)
+
+	for i, c := range s.CustomCommands {
+		path := fmt.Sprintf("custom_commands[%d].trigger", i)
+
 		if len(c.Trigger) < 4 || !strings.HasPrefix(c.Trigger, "/") {
-			return fmt.Errorf("custom command trigger %q must have at least 3 characters and start with a /", c.Trigger)
+			errs = append(errs, invalidField(path, "custom command trigger %q must have at least 3 characters and start with a /", c.Trigger))
+			continue
 		}
 
-		// combine CommandSuggestions and CustomCommands to check for collisions for custom commands
-		predefinedCommands := append(command.CommandSuggestions[:], command.ModeratorSuggestions[:]...)
-
 		if slices.Contains(predefinedCommands, c.Trigger) {
-			return fmt.Errorf("custom command trigger %q is already a default command", c.Trigger)
+			errs = append(errs, invalidField(path, "custom command trigger %q is already a default command", c.Trigger))
+		}
+	}
+
+	triggers := map[string]struct{}{}
+	for i, snippet := range s.Snippets {
+		path := fmt.Sprintf("snippets[%d]", i)
+
+		switch _, duplicate := triggers[snippet.Trigger]; {
+		case snippet.Trigger == "" || strings.ContainsFunc(snippet.Trigger, unicode.IsSpace):
+			errs = append(errs, invalidField(path+".trigger", "snippet trigger %q must be a single word", snippet.Trigger))
+		case strings.HasPrefix(snippet.Trigger, "/"):
+			errs = append(errs, invalidField(path+".trigger", "snippet trigger %q must not start with a /, use custom_commands for commands", snippet.Trigger))
+		case duplicate:
+			errs = append(errs, invalidField(path+".trigger", "snippet trigger %q is used more than once", snippet.Trigger))
+		}
+
+		triggers[snippet.Trigger] = struct{}{}
+
+		if strings.TrimSpace(snippet.Text) == "" || strings.ContainsAny(snippet.Text, "\r\n") {
+			errs = append(errs, invalidField(path+".text", "snippet text of %q must not be empty or contain line breaks", snippet.Trigger))
+			continue
+		}
+
+		for _, match := range snippetPlaceholderPattern.FindAllStringSubmatch(snippet.Text, -1) {
+			if !slices.Contains(SnippetPlaceholders, match[1]) {
+				errs = append(errs, invalidField(path+".text", "unknown placeholder %s in snippet %q, must be one of {%s}", match[0], snippet.Trigger, strings.Join(SnippetPlaceholders, "}, {")))
+			}
+		}
+	}
+
+	timerNames := map[string]struct{}{}
+	for i, timer := range s.Timers {
+		path := fmt.Sprintf("timers[%d]", i)
+		key := strings.ToLower(timer.Channel + " " + timer.Name)
+
+		switch _, duplicate := timerNames[key]; {
+		case timer.Name == "" || strings.ContainsFunc(timer.Name, unicode.IsSpace):
+			errs = append(errs, invalidField(path+".name", "timer name %q must be a single word", timer.Name))
+		case strings.EqualFold(timer.Name, "all"):
+			errs = append(errs, invalidField(path+".name", "timer name %q is reserved for /timer all", timer.Name))
+		case duplicate:
+			errs = append(errs, invalidField(path+".name", "timer name %q is used more than once in channel %q", timer.Name, timer.Channel))
+		}
+
+		timerNames[key] = struct{}{}
+
+		if timer.Channel == "" || strings.ContainsFunc(timer.Channel, unicode.IsSpace) {
+			errs = append(errs, invalidField(path+".channel", "timer channel %q must be a channel login", timer.Channel))
+		}
+
+		if strings.TrimSpace(timer.Message) == "" {
+			errs = append(errs, invalidField(path+".message", "timer message of %q must not be empty", timer.Name))
+		}
+
+		if timer.Interval < MinTimerInterval {
+			errs = append(errs, invalidField(path+".interval", "timer interval of %q must be at least %s", timer.Name, formatDuration(MinTimerInterval)))
+		}
+
+		if timer.MinMessages < 0 {
+			errs = append(errs, invalidField(path+".min_messages", "timer min_messages of %q must not be negative", timer.Name))
+		}
+	}
+
+	for i, r := range s.Bot.Replies {
+		path := fmt.Sprintf("bot.replies[%d]", i)
+
+		if strings.TrimSpace(r.Trigger) == "" {
+			errs = append(errs, invalidField(path+".trigger", "bot reply trigger must not be empty"))
+		}
+
+		if strings.TrimSpace(r.Response) == "" {
+			errs = append(errs, invalidField(path+".response", "bot reply response must not be empty"))
+		}
+
+		if r.Match != "" && r.Match != BotMatchCommand && r.Match != BotMatchContains {
+			errs = append(errs, invalidField(path+".match", "bot reply match %q must be either command or contains", r.Match))
+		}
+
+		if r.Cooldown < 0 {
+			errs = append(errs, invalidField(path+".cooldown", "bot reply cooldown must not be negative"))
+		}
+	}
+
+	for i, h := range s.Hooks {
+		path := fmt.Sprintf("hooks[%d]", i)
+
+		if !slices.Contains(HookEvents, h.Event) {
+			errs = append(errs, invalidField(path+".event", "hook event %q must be one of message, mention, raid, sub or whisper", h.Event))
+		}
+
+		if strings.TrimSpace(h.Command) == "" {
+			errs = append(errs, invalidField(path+".command", "hook command must not be empty"))
+		}
+
+		if _, err := filter.Parse(h.Filter, HookFilterFields); err != nil {
+			errs = append(errs, invalidField(path+".filter", "invalid hook filter: %s", err))
+		}
+
+		if h.Timeout < 0 {
+			errs = append(errs, invalidField(path+".timeout", "hook timeout must not be negative"))
+		}
+	}
+
+	if s.Sounds.Cooldown < 0 {
+		errs = append(errs, invalidField("sounds.cooldown", "sounds cooldown must not be negative"))
+	}
+
+	for i, r := range s.Sounds.Rules {
+		path := fmt.Sprintf("sounds.rules[%d]", i)
+
+		if !slices.Contains(HookEvents, r.Event) {
+			errs = append(errs, invalidField(path+".event", "sound rule event %q must be one of message, mention, raid, sub or whisper", r.Event))
+		}
+
+		if _, err := filter.Parse(r.Filter, HookFilterFields); err != nil {
+			errs = append(errs, invalidField(path+".filter", "invalid sound rule filter: %s", err))
+		}
+
+		if s.Sounds.Enabled && strings.TrimSpace(r.File) == "" && strings.TrimSpace(s.Sounds.File) == "" {
+			errs = append(errs, invalidField(path+".file", "sound rule needs a file, or set sounds.file for all rules"))
+		}
+	}
+
+	if s.Session.InputHistorySize < 1 {
+		errs = append(errs, invalidField("session.input_history_size", "session input_history_size must be at least 1"))
+	}
+
+	if s.FollowedSidebar.RefreshInterval < time.Second*30 {
+		errs = append(errs, invalidField("followed_sidebar.refresh_interval", "followed sidebar refresh_interval must be at least 30s"))
+	}
+
+	if s.GoLive.RefreshInterval < time.Second*30 {
+		errs = append(errs, invalidField("go_live.refresh_interval", "go_live refresh_interval must be at least 30s"))
+	}
+
+	if s.GoLive.ToastDuration < time.Second {
+		errs = append(errs, invalidField("go_live.toast_duration", "go_live toast_duration must be at least 1s"))
+	}
+
+	if s.NotificationTray.CycleInterval < time.Second {
+		errs = append(errs, invalidField("notification_tray.cycle_interval", "notification_tray cycle_interval must be at least 1s"))
+	}
+
+	for i, channel := range s.GoLive.Channels {
+		if channel == "" || strings.ContainsFunc(channel, unicode.IsSpace) {
+			errs = append(errs, invalidField(fmt.Sprintf("go_live.channels[%d]", i), "go_live channel %q must be a channel login", channel))
 		}
 	}
 
-	if slices.Contains(s.BlockSettings.Users, "") {
-		return fmt.Errorf("block settings user entry can't be empty string")
+	if s.APICache.Users < 0 {
+		errs = append(errs, invalidField("api_cache.users", "api_cache users must not be negative"))
 	}
 
-	if slices.Contains(s.BlockSettings.Words, "") {
-		return fmt.Errorf("block settings word entry can't be empty string")
+	if s.APICache.Badges < 0 {
+		errs = append(errs, invalidField("api_cache.badges", "api_cache badges must not be negative"))
 	}
 
-	return nil
+	if s.Presence.MaxViewers < 0 || s.Presence.MaxViewers > 1000 {
+		errs = append(errs, invalidField("presence.max_viewers", "presence max_viewers must be between 0 and 1000"))
+	}
+
+	if s.StreamInfo.RefreshInterval < time.Second*15 {
+		errs = append(errs, invalidField("stream_info.refresh_interval", "stream info refresh_interval must be at least 15s"))
+	}
+
+	if s.Chat.RenderFPS < 1 || s.Chat.RenderFPS > 120 {
+		errs = append(errs, invalidField("chat.render_fps", "chat render_fps must be between 1 and 120"))
+	}
+
+	if s.Chat.MaxMessagesPerSecond < 0 {
+		errs = append(errs, invalidField("chat.max_messages_per_second", "chat max_messages_per_second must not be negative"))
+	}
+
+	if s.Chat.SpamFade.Messages < 0 {
+		errs = append(errs, invalidField("chat.spam_fade.messages", "chat spam_fade messages must not be negative"))
+	}
+
+	if s.Chat.SpamFade.Window < time.Second {
+		errs = append(errs, invalidField("chat.spam_fade.window", "chat spam_fade window must be at least 1s"))
+	}
+
+	if s.Chat.EmoteCompression.MinRepeats < 2 {
+		errs = append(errs, invalidField("chat.emote_compression.min_repeats", "chat emote_compression min_repeats must be at least 2"))
+	}
+
+	if s.Chat.SendDelay < 0 || s.Chat.SendDelay > time.Second*10 {
+		errs = append(errs, invalidField("chat.send_delay", "chat send_delay must be between 0 and 10s"))
+	}
+
+	if s.Chat.EmoteUpdates.RefreshInterval != 0 && s.Chat.EmoteUpdates.RefreshInterval < time.Minute {
+		errs = append(errs, invalidField("chat.emote_updates.refresh_interval", "chat emote_updates refresh_interval must be 0 or at least 1m"))
+	}
+
+	if s.Links.Shortener != "" {
+		if err := shortlink.Validate(s.Links.Shortener); err != nil {
+			errs = append(errs, invalidField("links.shortener", "links shortener: %s", err))
+		}
+	}
+
+	if s.ImageUpload.Host != "" {
+		if err := imageupload.Validate(s.ImageUpload.Host); err != nil {
+			errs = append(errs, invalidField("image_upload.host", "image_upload host: %s", err))
+		}
+	}
+
+	if s.Links.ShortenLength < 20 {
+		errs = append(errs, invalidField("links.shorten_length", "links shorten_length must be at least 20"))
+	}
+
+	if s.Idle.Timeout != 0 && s.Idle.Timeout < time.Minute {
+		errs = append(errs, invalidField("idle.timeout", "idle timeout must be 0 or at least 1m"))
+	}
+
+	if s.OBS.Port < 1 || s.OBS.Port > 65535 {
+		errs = append(errs, invalidField("obs.port", "obs port must be between 1 and 65535"))
+	}
+
+	if s.OBS.Enabled && s.OBS.Host == "" {
+		errs = append(errs, invalidField("obs.host", "obs host must not be empty"))
+	}
+
+	if c := s.Chat.UsernameMinContrast; c != 0 && (c < 1 || c > 21) {
+		errs = append(errs, invalidField("chat.username_min_contrast", "chat username_min_contrast must be between 1 and 21, or 0 to disable"))
+	}
+
+	if !slices.Contains([]string{BadgeShowAll, BadgeShowRoles, BadgeShowNone}, s.Chat.Badges.Show) {
+		errs = append(errs, invalidField("chat.badges.show", "chat badges show %q must be one of all, roles or none", s.Chat.Badges.Show))
+	}
+
+	layouts := []string{ChatLayoutStandard, ChatLayoutCompact, ChatLayoutCozy}
+
+	if !slices.Contains(layouts, s.Chat.Layout) {
+		errs = append(errs, invalidField("chat.layout", "chat layout %q must be one of standard, compact or cozy", s.Chat.Layout))
+	}
+
+	for channel, layout := range s.Chat.ChannelLayouts {
+		if !slices.Contains(layouts, layout) {
+			errs = append(errs, invalidField("chat.channel_layouts."+channel, "chat layout %q for channel %q must be one of standard, compact or cozy", layout, channel))
+		}
+	}
+
+	sendMethods := []string{SendMethodHelix, SendMethodIRC}
+
+	if !slices.Contains(sendMethods, s.Chat.SendMethod) {
+		errs = append(errs, invalidField("chat.send_method", "chat send method %q must be one of helix or irc", s.Chat.SendMethod))
+	}
+
+	for account, method := range s.Chat.AccountSendMethods {
+		if !slices.Contains(sendMethods, method) {
+			errs = append(errs, invalidField("chat.account_send_methods."+account, "chat send method %q for account %q must be one of helix or irc", method, account))
+		}
+	}
+
+	for login, alias := range s.Chat.UserAliases {
+		if strings.TrimSpace(alias) == "" || strings.ContainsFunc(alias, unicode.IsControl) {
+			errs = append(errs, invalidField("chat.user_aliases."+login, "alias %q for user %q must not be empty or contain control characters", alias, login))
+		}
+	}
+
+	if !slices.Contains([]string{TimestampFormatSeconds, TimestampFormatMinutes, TimestampFormatRelative, TimestampFormatOff}, s.Timestamps.Format) {
+		errs = append(errs, invalidField("timestamps.format", "timestamps format %q must be one of hh:mm:ss, hh:mm, relative or off", s.Timestamps.Format))
+	}
+
+	if s.Timestamps.Clock != TimestampClock24h && s.Timestamps.Clock != TimestampClock12h {
+		errs = append(errs, invalidField("timestamps.clock", "timestamps clock %q must be either 24h or 12h", s.Timestamps.Clock))
+	}
+
+	switch s.Translation.Backend {
+	case "":
+	case TranslationBackendDeepL:
+		if s.Translation.APIKey == "" {
+			errs = append(errs, invalidField("translation.api_key", "translation api_key is required for DeepL"))
+		}
+	case TranslationBackendLibreTranslate:
+		if s.Translation.URL == "" {
+			errs = append(errs, invalidField("translation.url", "translation url of the LibreTranslate instance is required"))
+		}
+	default:
+		errs = append(errs, invalidField("translation.backend", "translation backend %q must be one of deepl or libretranslate, or empty to disable translations", s.Translation.Backend))
+	}
+
+	if !isLanguageCode(s.Translation.TargetLanguage) {
+		errs = append(errs, invalidField("translation.target_language", "translation target_language %q must be a language code like en, de or pt-br", s.Translation.TargetLanguage))
+	}
+
+	if s.Spellcheck.Enabled && s.Spellcheck.Dictionary == "" && strings.ContainsAny(s.Spellcheck.Language, `/\.`) {
+		errs = append(errs, invalidField("spellcheck.language", "spellcheck language %q must be a dictionary name like en_US, use spellcheck.dictionary for paths", s.Spellcheck.Language))
+	}
+
+	if oauth := []string{s.YouTube.ClientID, s.YouTube.ClientSecret, s.YouTube.RefreshToken}; slices.Contains(oauth, "") && slices.ContainsFunc(oauth, func(v string) bool { return v != "" }) {
+		errs = append(errs, invalidField("youtube", "youtube client_id, client_secret and refresh_token are required together to send messages"))
+	}
+
+	if _, err := s.Proxy.ProxyURL(); err != nil {
+		errs = append(errs, invalidField("proxy.url", "%s", err))
+	}
+
+	if s.SSH.ImageChunkSize < 4 || s.SSH.ImageChunkSize > 4096 || s.SSH.ImageChunkSize%4 != 0 {
+		errs = append(errs, invalidField("ssh.image_chunk_size", "ssh image_chunk_size %d must be a multiple of 4 between 4 and 4096", s.SSH.ImageChunkSize))
+	}
+
+	if s.SSH.ProbeTimeout <= 0 {
+		errs = append(errs, invalidField("ssh.probe_timeout", "ssh probe_timeout must be positive"))
+	}
+
+	if !slices.Contains([]string{UsernameColorsTwitch, UsernameColorsColorblind}, s.Accessibility.UsernameColors) {
+		errs = append(errs, invalidField("accessibility.username_colors", "accessibility username_colors %q must be one of twitch or colorblind", s.Accessibility.UsernameColors))
+	}
+
+	switch s.Sync.Backend {
+	case "":
+	case remotesync.BackendGit:
+		if s.Sync.Remote == "" {
+			errs = append(errs, invalidField("sync.remote", "sync remote is required for the git backend"))
+		} else if err := remotesync.ValidateGitRemote(s.Sync.Remote); err != nil {
+			errs = append(errs, invalidField("sync.remote", "sync remote: %s", err))
+		}
+
+		if err := remotesync.ValidateGitBranch(s.Sync.Branch); err != nil {
+			errs = append(errs, invalidField("sync.branch", "sync branch: %s", err))
+		}
+	case remotesync.BackendWebDAV:
+		if err := remotesync.ValidateWebDAV(s.Sync.Remote); err != nil {
+			errs = append(errs, invalidField("sync.remote", "sync remote: %s", err))
+		}
+	default:
+		errs = append(errs, invalidField("sync.backend", "sync backend %q must be %s or %s", s.Sync.Backend, remotesync.BackendGit, remotesync.BackendWebDAV))
+	}
+
+	if _, err := ParseStatusBarTemplate(s.StatusBar.Template); err != nil {
+		errs = append(errs, invalidField("status_bar.template", "status bar template is invalid: %s", err))
+	}
+
+	if strings.TrimSpace(s.Player.Command) == "" {
+		errs = append(errs, invalidField("player.command", "player command can't be empty"))
+	}
+
+	if i := slices.Index(s.BlockSettings.Users, ""); i != -1 {
+		errs = append(errs, invalidField(fmt.Sprintf("block_settings.users[%d]", i), "block settings user entry can't be empty string"))
+	}
+
+	if i := slices.Index(s.BlockSettings.Words, ""); i != -1 {
+		errs = append(errs, invalidField(fmt.Sprintf("block_settings.words[%d]", i), "block settings word entry can't be empty string"))
+	}
+
+	for i, w := range s.Profanity.Words {
+		if strings.Trim(w, " *") == "" || strings.ContainsFunc(w, unicode.IsSpace) {
+			errs = append(errs, invalidField(fmt.Sprintf("profanity.words[%d]", i), "profanity word %q must be a single word", w))
+		}
+	}
+
+	for i, u := range s.Favorites.Users {
+		if u == "" || strings.ContainsFunc(u, unicode.IsSpace) {
+			errs = append(errs, invalidField(fmt.Sprintf("favorites.users[%d]", i), "favorite user %q must be a user login", u))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 func (s Settings) BuildCustomSuggestionMap() map[string]string {
@@ -100,6 +1205,16 @@ func (s Settings) BuildCustomSuggestionMap() map[string]string {
 	return m
 }
 
+// SettingsFilePath returns the path of the settings file in the config directory
+func SettingsFilePath() (string, error) {
+	paths, err := ActivePaths()
+	if err != nil {
+		return "", err
+	}
+
+	return paths.SettingsFile(), nil
+}
+
 func SettingsFromDisk() (Settings, error) {
 	f, err := openCreateConfigFile(afero.NewOsFs(), settingsFileName)
 	if err != nil {
@@ -121,13 +1236,8 @@ func SettingsFromDisk() (Settings, error) {
 		return Settings{}, err
 	}
 
-	settings := BuildDefaultSettings()
-
-	if err := yaml.Unmarshal(b, &settings); err != nil {
-		return Settings{}, err
-	}
-
-	if err := settings.validate(); err != nil {
+	settings, report := CheckSettings(b)
+	if err := report.Err(); err != nil {
 		return Settings{}, err
 	}
 
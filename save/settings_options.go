@@ -0,0 +1,451 @@
+package save
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// SettingKind is the type of value of a setting option
+type SettingKind int
+
+const (
+	SettingBool SettingKind = iota
+	SettingChoice
+	SettingText
+	SettingNumber
+	SettingDuration
+)
+
+// SettingOption describes a setting which can be changed in the settings editor.
+// Lists and maps, like custom commands or blocked users, are only editable in the settings file.
+type SettingOption struct {
+	Section     string
+	Path        string // path of the setting in the settings file, e.g. chat.layout
+	Description string
+	Choices     []string // possible values of choice settings
+	Restart     bool     // changes are only applied after a restart
+	Secret      bool     // value may contain credentials, it is left out of panic reports
+}
+
+// Kind returns the type of value of the option
+func (o SettingOption) Kind() SettingKind {
+	if len(o.Choices) > 0 {
+		return SettingChoice
+	}
+
+	v, err := settingField(reflect.ValueOf(&Settings{}).Elem(), o.Path)
+	if err != nil {
+		return SettingText
+	}
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		return SettingDuration
+	case v.Kind() == reflect.Bool:
+		return SettingBool
+	case v.CanInt() || v.CanFloat():
+		return SettingNumber
+	default:
+		return SettingText
+	}
+}
+
+// SettingOptions returns all settings which can be changed in the settings editor, grouped by section
+func SettingOptions() []SettingOption {
+	return []SettingOption{
+		{Section: "General", Path: "vertical_tab_list", Description: "Display tabs vertically instead of horizontally", Restart: true},
+		{Section: "General", Path: "keymap_profile", Description: "Key binding profile: default, vim, emacs or a custom profile from keymap.yaml"},
+		{Section: "General", Path: "mouse.enabled", Description: "Scroll, select messages, open links and switch tabs with the mouse, the terminal then only selects text with shift held"},
+		{Section: "General", Path: "tmux.window_title", Description: "Inside tmux, rename the window to the focused channel with a dot while it is live"},
+		{Section: "General", Path: "ssh.graphics", Description: "In SSH sessions, keep graphic emotes and badges, their pixel data is sent through the connection"},
+		{Section: "General", Path: "ssh.image_chunk_size", Description: "In SSH sessions, bytes of image data per escape sequence, a multiple of 4 up to 4096", Restart: true},
+		{Section: "General", Path: "ssh.probe_timeout", Description: "In SSH sessions, how long queries of the terminal wait for an answer", Restart: true},
+
+		{Section: "Accessibility", Path: "accessibility.screen_reader", Description: "Simple \"user: message\" lines without graphics and decorative glyphs, tab switches are announced and the screen is repainted less often", Restart: true},
+		{Section: "Accessibility", Path: "accessibility.reduced_motion", Description: "Stop animated emotes, spinners and other motion"},
+		{Section: "Accessibility", Path: "accessibility.username_colors", Description: "Keep the Twitch username colors or map them into a colorblind safe palette", Choices: []string{UsernameColorsTwitch, UsernameColorsColorblind}},
+
+		{Section: "Chat", Path: "chat.layout", Description: "Message layout", Choices: []string{ChatLayoutStandard, ChatLayoutCompact, ChatLayoutCozy}},
+		{Section: "Chat", Path: "chat.group_messages", Description: "Show the badges and name once for consecutive messages of the same author"},
+		{Section: "Chat", Path: "chat.graphic_emotes", Description: "Display emotes as images instead of text (kitty terminal only)"},
+		{Section: "Chat", Path: "chat.graphic_badges", Description: "Display badges as images instead of text (kitty terminal only)"},
+		{Section: "Chat", Path: "chat.badges.show", Description: "Which badges are shown", Choices: []string{BadgeShowAll, BadgeShowRoles, BadgeShowNone}},
+		{Section: "Chat", Path: "chat.badges.glyphs", Description: "Show short glyphs instead of badge names, only used without graphic badges"},
+		{Section: "Chat", Path: "chat.disable_padding_wrapped_lines", Description: "Don't indent wrapped lines of a message"},
+		{Section: "Chat", Path: "chat.auto_split_long_messages", Description: "Allow messages longer than 500 characters and send them split into multiple messages"},
+		{Section: "Chat", Path: "chat.username_min_contrast", Description: "Minimum contrast ratio (1-21) of user colors against the terminal background, 0 disables it"},
+		{Section: "Chat", Path: "chat.render_fps", Description: "How often the UI is repainted at most and incoming messages are applied (1-120)", Restart: true},
+		{Section: "Chat", Path: "chat.spam_fade.messages", Description: "Fade messages of users sending more messages than this within the window, 0 disables it"},
+		{Section: "Chat", Path: "chat.spam_fade.window", Description: "Time in which the messages of a user are counted for fading"},
+		{Section: "Chat", Path: "chat.spam_fade.collapse", Description: "Cut faded messages off after one line"},
+		{Section: "Chat", Path: "chat.emote_compression.enabled", Description: "Show messages which only repeat one emote as the emote with a count, chat.emote_compression.channels overrides it per channel"},
+		{Section: "Chat", Path: "chat.emote_compression.min_repeats", Description: "How often the emote must be repeated before the message is compressed (at least 2)"},
+		{Section: "Chat", Path: "chat.emote_updates.refresh_interval", Description: "How often the 7TV, BTTV and FFZ emotes of open channels are reloaded, 0 disables it"},
+		{Section: "Chat", Path: "chat.emote_updates.notify", Description: "Post a notice in chat when emotes were added or removed"},
+		{Section: "Chat", Path: "chat.max_messages_per_second", Description: "Messages shown per channel and second in busy chats, further messages are summarized, 0 shows all"},
+		{Section: "Chat", Path: "chat.send_delay", Description: "Hold sent messages back for this long, escape cancels them meanwhile, 0 sends right away"},
+		{Section: "Chat", Path: "chat.automod_check", Description: "Warn before sending a message AutoMod would hold, needs the account of the broadcaster"},
+		{Section: "Chat", Path: "chat.send_method", Description: "Send messages through the Helix API with IRC as fallback, or only through IRC", Choices: []string{SendMethodHelix, SendMethodIRC}},
+		{Section: "Chat", Path: "favorites.show_in_mentions", Description: "Also show messages of favorite users in the mentions tab"},
+		{Section: "Chat", Path: "chat.seventv_cosmetics", Description: "Show the 7TV name paints and badges of chatters", Restart: true},
+
+		{Section: "Timestamps", Path: "timestamps.format", Description: "Timestamp of chat messages", Choices: []string{TimestampFormatSeconds, TimestampFormatMinutes, TimestampFormatRelative, TimestampFormatOff}},
+		{Section: "Timestamps", Path: "timestamps.clock", Description: "Use a 24h or 12h clock", Choices: []string{TimestampClock24h, TimestampClock12h}},
+		{Section: "Timestamps", Path: "timestamps.date_separators", Description: "Show a separator line when the day changes between two messages"},
+
+		{Section: "Session", Path: "session.restore_tabs", Description: "Restore the tabs of the previous session on startup", Restart: true},
+		{Section: "Session", Path: "session.shared_input_history", Description: "Share the sent message history between all tabs", Restart: true},
+		{Section: "Session", Path: "session.input_history_size", Description: "Number of sent messages kept in the history", Restart: true},
+		{Section: "Session", Path: "session.restore_after_crash", Description: "Restore the tabs after a crash, even if restore_tabs is disabled", Restart: true},
+
+		{Section: "Followed Sidebar", Path: "followed_sidebar.show_on_startup", Description: "Show the followed channels sidebar on startup"},
+		{Section: "Followed Sidebar", Path: "followed_sidebar.refresh_interval", Description: "How often the followed channels are refreshed, at least 30s"},
+		{Section: "Go Live", Path: "go_live.enabled", Description: "Notify when followed channels go live, go_live.channels limits the notified channels", Restart: true},
+		{Section: "Go Live", Path: "go_live.refresh_interval", Description: "How often followed channels are checked, at least 30s"},
+		{Section: "Go Live", Path: "go_live.desktop", Description: "Also show a system notification"},
+		{Section: "Go Live", Path: "go_live.toast_duration", Description: "How long the notification is shown in Chatuino, at least 1s"},
+		{Section: "Notification Tray", Path: "notification_tray.enabled", Description: "Show a line at the bottom cycling through whispers, mentions in background tabs and go live notifications until they are dismissed"},
+		{Section: "Notification Tray", Path: "notification_tray.cycle_interval", Description: "How long each pending notification is shown before the next one, at least 1s"},
+		{Section: "Stream Info", Path: "stream_info.refresh_interval", Description: "How often the stream info of open channels is refreshed, at least 15s"},
+		{Section: "Stream Info", Path: "stream_info.activity_graph", Description: "Show the messages per minute of the last 30 minutes as a graph next to the stream info"},
+		{Section: "Presence", Path: "presence.enabled", Description: "Track which chatters joined small channels, /presence lists them", Restart: true},
+		{Section: "Presence", Path: "presence.max_viewers", Description: "Only track channels with at most this many viewers, at most 1000"},
+		{Section: "Presence", Path: "presence.show_lines", Description: "Show a line in chat when a chatter joins or leaves"},
+		{Section: "Status Bar", Path: "status_bar.template", Description: "Content of the status bar, see the settings documentation for all {placeholders}"},
+		{Section: "Idle", Path: "idle.timeout", Description: "Pause background refreshes without input for this long, at least 1m, 0 disables it"},
+
+		{Section: "Moderation", Path: "moderation.store_chat_logs", Description: "Store chat logs in a SQLite database", Restart: true},
+		{Section: "Moderation", Path: "block_settings.bots", Description: "Hide messages of bots marked on FFZ or BTTV"},
+		{Section: "Moderation", Path: "profanity.mask", Description: "Replace profanity in messages with asterisks, profanity.channels overrides it per channel"},
+		{Section: "Moderation", Path: "profanity.default_words", Description: "Mask the built-in list of common English swear words in addition to profanity.words"},
+		{Section: "Security", Path: "security.check_links", Description: "Check links in messages before opening them"},
+		{Section: "Links", Path: "links.opener", Description: "Command used to open links, empty uses the system default"},
+		{Section: "Links", Path: "links.shortener", Description: "URL of a link shortener, {url} is replaced with the link, empty disables shortening", Restart: true},
+		{Section: "Links", Path: "links.shorten_length", Description: "Links of sent messages longer than this are offered to be shortened"},
+		{Section: "Links", Path: "image_upload.host", Description: "Offer to upload pasted image files to catbox, kappa or the URL of a host and insert the link, empty disables it", Restart: true},
+		{Section: "Links", Path: "image_upload.api_key", Description: "catbox user hash or Authorization header of the image host, empty uploads anonymously", Restart: true, Secret: true},
+		{Section: "Sounds", Path: "sounds.enabled", Description: "Play a sound when chat events match one of sounds.rules, like mentions, raids and whispers"},
+		{Section: "Sounds", Path: "sounds.player", Description: "Command playing the sounds, {file} is replaced with the file, empty uses afplay on macOS and paplay otherwise"},
+		{Section: "Sounds", Path: "sounds.file", Description: "Audio file played for rules without their own file"},
+		{Section: "Sounds", Path: "sounds.cooldown", Description: "Events right after a sound don't play another one"},
+		{Section: "Player", Path: "player.command", Description: "Command used to watch streams, {channel} is replaced with the channel"},
+		{Section: "Proxy", Path: "proxy.url", Description: "Proxy of all connections, like socks5://host:1080, empty uses HTTP_PROXY and HTTPS_PROXY", Restart: true, Secret: true},
+		{Section: "Sync", Path: "sync.backend", Description: "Sync the configuration and notes with a git repository or WebDAV server: git or webdav, empty disables it"},
+		{Section: "Sync", Path: "sync.remote", Description: "URL of the git repository or of the WebDAV directory", Secret: true},
+		{Section: "Sync", Path: "sync.branch", Description: "Branch of the git repository the files are kept in"},
+		{Section: "Sync", Path: "sync.username", Description: "User of the WebDAV server"},
+		{Section: "Sync", Path: "sync.password", Description: "Password of the WebDAV server", Secret: true},
+		{Section: "Sync", Path: "sync.auto", Description: "Sync before Chatuino starts and after it is quit"},
+		{Section: "Translation", Path: "translation.backend", Description: "Backend used to translate messages: deepl or libretranslate, empty disables translations", Restart: true},
+		{Section: "Translation", Path: "translation.url", Description: "URL of the LibreTranslate instance, empty uses the DeepL API", Restart: true},
+		{Section: "Translation", Path: "translation.api_key", Description: "API key of DeepL or the LibreTranslate instance", Restart: true, Secret: true},
+		{Section: "Translation", Path: "translation.target_language", Description: "Language messages are translated to, like en or de", Restart: true},
+		{Section: "Spellcheck", Path: "spellcheck.enabled", Description: "Underline misspelled words in the message input", Restart: true},
+		{Section: "Spellcheck", Path: "spellcheck.language", Description: "Hunspell dictionary used, like en_US or de_DE", Restart: true},
+		{Section: "Spellcheck", Path: "spellcheck.dictionary", Description: "Path of a .dic file or word list, overrides the language", Restart: true},
+		{Section: "YouTube", Path: "youtube.api_key", Description: "YouTube Data API key used to read YouTube live chats, empty disables YouTube tabs", Restart: true, Secret: true},
+		{Section: "YouTube", Path: "youtube.client_id", Description: "OAuth client ID used to send messages to YouTube live chats", Restart: true},
+		{Section: "Kick", Path: "kick.enabled", Description: "Offer read only tabs of Kick chats", Restart: true},
+		{Section: "Updates", Path: "update_check.enabled", Description: "Show a notice when a newer release is available, checked at most once a day", Restart: true},
+		{Section: "API Cache", Path: "api_cache.users", Description: "How long looked up Twitch users are reused, 0 disables it", Restart: true},
+		{Section: "API Cache", Path: "api_cache.badges", Description: "How long chat badges are reused, 0 disables it", Restart: true},
+		{Section: "Control Socket", Path: "ipc.enabled", Description: "Let other programs control Chatuino through a local socket", Restart: true},
+		{Section: "Control Socket", Path: "ipc.socket", Description: "Path of the control socket, empty uses chatuino.sock in the runtime directory", Restart: true},
+		{Section: "OBS", Path: "obs.enabled", Description: "Connect to OBS through obs-websocket to show its status and use the /obs command", Restart: true},
+		{Section: "OBS", Path: "obs.host", Description: "Host of obs-websocket", Restart: true},
+		{Section: "OBS", Path: "obs.port", Description: "Port of obs-websocket, shown in OBS under Tools, WebSocket Server Settings", Restart: true},
+		{Section: "OBS", Path: "obs.password", Description: "Password of obs-websocket, empty if authentication is disabled", Restart: true, Secret: true},
+	}
+}
+
+// settingField returns the struct field of a setting path, v must be an addressable Settings value
+func settingField(v reflect.Value, path string) (reflect.Value, error) {
+	for part := range strings.SplitSeq(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown setting %q", path)
+		}
+
+		var found bool
+		for i := range v.NumField() {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+			if name == part {
+				v, found = v.Field(i), true
+				break
+			}
+		}
+
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown setting %q", path)
+		}
+	}
+
+	return v, nil
+}
+
+// SettingValue returns the value of a setting formatted as it is written in the settings file
+func (s Settings) SettingValue(path string) (string, error) {
+	v, err := settingField(reflect.ValueOf(&s).Elem(), path)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		return formatDuration(time.Duration(v.Int())), nil
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10), nil
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case v.Kind() == reflect.String:
+		return v.String(), nil
+	}
+
+	return "", fmt.Errorf("setting %q can't be edited", path)
+}
+
+// Summary lists the settings of the settings editor with their values, one per line.
+// Values of secret settings are replaced with <redacted>, so the summary can be shared in bug reports.
+func (s Settings) Summary() string {
+	b := &strings.Builder{}
+
+	for _, option := range SettingOptions() {
+		value, err := s.SettingValue(option.Path)
+		if err != nil {
+			value = err.Error()
+		}
+
+		if option.Secret && value != "" {
+			value = "<redacted>"
+		}
+
+		fmt.Fprintf(b, "%s: %s\n", option.Path, value)
+	}
+
+	return b.String()
+}
+
+// formatDuration formats d without trailing zero units, e.g. 2m instead of 2m0s
+func formatDuration(d time.Duration) string {
+	s := d.String()
+
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
+
+// SetSettingValue parses value and sets the setting at path. The settings are validated afterwards,
+// on error the settings may contain the invalid value.
+func (s *Settings) SetSettingValue(path, value string) error {
+	v, err := settingField(reflect.ValueOf(s).Elem(), path)
+	if err != nil {
+		return err
+	}
+
+	value = strings.TrimSpace(value)
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration like 90s or 2m", value)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		v.SetInt(i)
+	case v.CanFloat():
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		v.SetFloat(f)
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	default:
+		return fmt.Errorf("setting %q can't be edited", path)
+	}
+
+	return s.validate()
+}
+
+// SetSnippets replaces the snippets and validates the settings afterwards,
+// on error the settings may contain the invalid snippets.
+func (s *Settings) SetSnippets(snippets []Snippet) error {
+	s.Snippets = snippets
+	return s.validate()
+}
+
+// WriteSettingValues writes changed settings to the settings file and returns the resulting settings.
+// Values are formatted like SettingValue. Other settings and comments in the file are kept,
+// older settings files are migrated to the current version.
+func WriteSettingValues(values map[string]string) (Settings, error) {
+	return writeSettingsFile(func(b []byte) ([]byte, error) {
+		return updateSettingsDocument(b, values)
+	})
+}
+
+// WriteSnippets replaces the snippets in the settings file and returns the resulting settings.
+// Other settings and comments in the file are kept like in WriteSettingValues.
+func WriteSnippets(snippets []Snippet) (Settings, error) {
+	return writeSettingsFile(func(b []byte) ([]byte, error) {
+		return updateSnippetsDocument(b, snippets)
+	})
+}
+
+// writeSettingsFile replaces the content of the settings file with the result of update, if the result is valid
+func writeSettingsFile(update func(b []byte) ([]byte, error)) (Settings, error) {
+	f, err := openCreateConfigFile(afero.NewOsFs(), settingsFileName)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	updated, err := update(b)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	settings, report := CheckSettings(updated)
+	if err := report.Err(); err != nil {
+		return Settings{}, err
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return Settings{}, err
+	}
+
+	if _, err := f.WriteAt(updated, 0); err != nil {
+		return Settings{}, err
+	}
+
+	return settings, nil
+}
+
+// updateSettingsDocument sets the values in the settings file content
+func updateSettingsDocument(b []byte, values map[string]string) ([]byte, error) {
+	return editSettingsDocument(b, func(root *yaml.Node) error {
+		// write in a stable order
+		for _, path := range slices.Sorted(maps.Keys(values)) {
+			parts := strings.Split(path, ".")
+
+			node := root
+			for _, part := range parts[:len(parts)-1] {
+				node = ensureMapping(node, part)
+			}
+
+			// quote strings, so values like "off" or "24h" keep being strings
+			tag := ""
+			if v, err := settingField(reflect.ValueOf(&Settings{}).Elem(), path); err == nil && v.Kind() == reflect.String {
+				tag = "!!str"
+			}
+
+			setMappingScalar(node, parts[len(parts)-1], values[path], tag)
+		}
+
+		return nil
+	})
+}
+
+// updateSnippetsDocument replaces the snippets in the settings file content, the comments above them are kept
+func updateSnippetsDocument(b []byte, snippets []Snippet) ([]byte, error) {
+	return editSettingsDocument(b, func(root *yaml.Node) error {
+		var node yaml.Node
+		if err := node.Encode(snippets); err != nil {
+			return err
+		}
+
+		if existing := mappingValue(root, "snippets"); existing != nil {
+			node.HeadComment, node.LineComment, node.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+			*existing = node
+			return nil
+		}
+
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "snippets"}, &node)
+		return nil
+	})
+}
+
+// editSettingsDocument migrates the settings file content to the current version, calls edit with its root mapping
+// and returns the edited content
+func editSettingsDocument(b []byte, edit func(root *yaml.Node) error) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("settings must be a mapping of setting names to values")
+	}
+
+	version := 1
+	if v := mappingValue(root, "version"); v != nil {
+		if err := v.Decode(&version); err != nil {
+			return nil, fmt.Errorf("invalid settings version: %w", err)
+		}
+	}
+
+	if version > CurrentSettingsVersion {
+		return nil, fmt.Errorf("settings version %d is newer than the supported version %d", version, CurrentSettingsVersion)
+	}
+
+	if _, err := migrateSettings(root, version); err != nil {
+		return nil, err
+	}
+
+	if err := edit(root); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
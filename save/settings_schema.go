@@ -0,0 +1,429 @@
+package save
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentSettingsVersion is the schema version of the settings file.
+// Files without a version are treated as version 1, the schema before versioning was introduced.
+const CurrentSettingsVersion = 2
+
+type settingsMigration struct {
+	version     int // version the migration upgrades to
+	description string
+	migrate     func(root *yaml.Node) error
+}
+
+// settingsMigrations upgrade settings files of older versions, ordered by version.
+// Migrations work on the YAML document, so settings which no longer exist can still be read.
+var settingsMigrations = []settingsMigration{
+	{
+		version:     2,
+		description: "chat.disable_badges was replaced by chat.badges.show: none",
+		migrate: func(root *yaml.Node) error {
+			chat := mappingValue(root, "chat")
+			disable := mappingValue(chat, "disable_badges")
+			if disable == nil {
+				return nil
+			}
+
+			var disabled bool
+			if err := disable.Decode(&disabled); err != nil {
+				return fmt.Errorf("chat.disable_badges: %w", err)
+			}
+
+			removeMappingEntry(chat, "disable_badges")
+
+			if disabled {
+				setMappingScalar(ensureMapping(chat, "badges"), "show", BadgeShowNone, "!!str")
+			}
+
+			return nil
+		},
+	},
+}
+
+// SettingsIssue is a problem found while checking a settings file
+type SettingsIssue struct {
+	Line    int    // line in the settings file, 0 if unknown
+	Path    string // path of the setting, e.g. chat.layout or custom_commands[0].trigger
+	Message string
+	Warning bool // warnings don't prevent the settings from being used
+}
+
+func (i SettingsIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+
+	return i.Message
+}
+
+// SettingsReport is the result of checking a settings file
+type SettingsReport struct {
+	Version    int      // version of the file before migrating
+	Migrations []string // descriptions of the applied migrations
+	Issues     []SettingsIssue
+}
+
+func (r SettingsReport) HasErrors() bool {
+	return slices.ContainsFunc(r.Issues, func(i SettingsIssue) bool {
+		return !i.Warning
+	})
+}
+
+// Err joins all errors of the report, warnings are not included
+func (r SettingsReport) Err() error {
+	var errs []error
+	for _, i := range r.Issues {
+		if !i.Warning {
+			errs = append(errs, errors.New(i.String()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fieldError is a validation error of a single setting, the path is used to find the line of the setting
+type fieldError struct {
+	path string
+	err  error
+}
+
+func (e fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e fieldError) Unwrap() error {
+	return e.err
+}
+
+func invalidField(path, format string, args ...any) error {
+	return fieldError{path: path, err: fmt.Errorf(format, args...)}
+}
+
+var yamlLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// CheckSettings parses the content of a settings file, migrates it to the current version and validates it.
+// Unknown settings are reported as warnings. The returned settings must not be used if the report contains errors.
+func CheckSettings(b []byte) (Settings, SettingsReport) {
+	settings := BuildDefaultSettings()
+	report := SettingsReport{Version: 1}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		report.Issues = append(report.Issues, yamlIssues(err)...)
+		return settings, report
+	}
+
+	// empty file
+	if len(doc.Content) == 0 {
+		report.Version = CurrentSettingsVersion
+		return settings, report
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		report.Issues = append(report.Issues, SettingsIssue{Line: root.Line, Message: "settings must be a mapping of setting names to values"})
+		return settings, report
+	}
+
+	if v := mappingValue(root, "version"); v != nil {
+		if err := v.Decode(&report.Version); err != nil || report.Version < 1 {
+			report.Issues = append(report.Issues, SettingsIssue{Line: v.Line, Path: "version", Message: fmt.Sprintf("version must be a number between 1 and %d", CurrentSettingsVersion)})
+			return settings, report
+		}
+	}
+
+	if report.Version > CurrentSettingsVersion {
+		report.Issues = append(report.Issues, SettingsIssue{
+			Line:    mappingValue(root, "version").Line,
+			Path:    "version",
+			Message: fmt.Sprintf("settings version %d is newer than the supported version %d, update Chatuino to use these settings", report.Version, CurrentSettingsVersion),
+		})
+		return settings, report
+	}
+
+	migrations, err := migrateSettings(root, report.Version)
+	report.Migrations = migrations
+	if err != nil {
+		report.Issues = append(report.Issues, SettingsIssue{Message: err.Error()})
+		return settings, report
+	}
+
+	report.Issues = append(report.Issues, unknownSettings(root, reflect.TypeFor[Settings](), "")...)
+
+	if err := root.Decode(&settings); err != nil {
+		report.Issues = append(report.Issues, yamlIssues(err)...)
+		return settings, report
+	}
+
+	for _, err := range unjoin(settings.validate()) {
+		issue := SettingsIssue{Message: err.Error()}
+
+		var fe fieldError
+		if errors.As(err, &fe) {
+			issue.Path = fe.path
+			issue.Line = settingLine(root, fe.path)
+		}
+
+		report.Issues = append(report.Issues, issue)
+	}
+
+	slices.SortStableFunc(report.Issues, func(a, b SettingsIssue) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+
+	return settings, report
+}
+
+// migrateSettings upgrades the settings document from version to the current version and returns the descriptions of the applied migrations
+func migrateSettings(root *yaml.Node, version int) ([]string, error) {
+	var applied []string
+
+	for _, m := range settingsMigrations {
+		if m.version <= version {
+			continue
+		}
+
+		if err := m.migrate(root); err != nil {
+			return applied, fmt.Errorf("failed to migrate settings to version %d: %w", m.version, err)
+		}
+
+		applied = append(applied, m.description)
+	}
+
+	setMappingScalar(root, "version", strconv.Itoa(CurrentSettingsVersion), "")
+
+	return applied, nil
+}
+
+func unjoin(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
+
+// yamlIssues converts syntax and type errors of the yaml package, which contain the line in their message
+func yamlIssues(err error) []SettingsIssue {
+	messages := []string{err.Error()}
+
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+
+	issues := make([]SettingsIssue, 0, len(messages))
+	for _, msg := range messages {
+		issue := SettingsIssue{Message: msg}
+
+		if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+			issue.Message = m[2]
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// unknownSettings reports keys of the document which don't exist in the settings struct, maps with free form keys are not checked
+func unknownSettings(node *yaml.Node, t reflect.Type, path string) []SettingsIssue {
+	var issues []SettingsIssue
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := map[string]reflect.Type{}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name != "" && name != "-" {
+				fields[name] = f.Type
+			}
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinSettingPath(path, key.Value)
+
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				message := fmt.Sprintf("unknown setting %q", keyPath)
+				if suggestion := closestName(key.Value, fields); suggestion != "" {
+					message += fmt.Sprintf(", did you mean %q?", joinSettingPath(path, suggestion))
+				}
+
+				issues = append(issues, SettingsIssue{Line: key.Line, Path: keyPath, Message: message, Warning: true})
+				continue
+			}
+
+			issues = append(issues, unknownSettings(value, fieldType, keyPath)...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			issues = append(issues, unknownSettings(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return issues
+}
+
+func joinSettingPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// closestName returns the name with the smallest edit distance to name, if the names are similar enough to be a typo
+func closestName[T any](name string, names map[string]T) string {
+	var (
+		best     string
+		bestDist = max(len(name)/3, 2) + 1 // allow about one typo per three characters
+	)
+
+	for candidate := range names {
+		if d := editDistance(name, candidate); d < bestDist || d == bestDist && candidate < best {
+			best, bestDist = candidate, d
+		}
+	}
+
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// settingLine returns the line of a setting path like custom_commands[0].trigger.
+// If the setting is not part of the document, the line of its closest parent is returned.
+func settingLine(root *yaml.Node, path string) int {
+	node, line := root, 0
+
+	for part := range strings.SplitSeq(path, ".") {
+		name, index, hasIndex := strings.Cut(strings.TrimSuffix(part, "]"), "[")
+
+		key, value := mappingEntry(node, name)
+		if value == nil {
+			return line
+		}
+
+		node, line = value, key.Line
+
+		if hasIndex {
+			i, err := strconv.Atoi(index)
+			if err != nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+				return line
+			}
+
+			node, line = node.Content[i], node.Content[i].Line
+		}
+	}
+
+	return line
+}
+
+// mappingEntry returns the key and value node of key in a mapping node, nil if the key does not exist
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, value := mappingEntry(node, key)
+	return value
+}
+
+func removeMappingEntry(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// ensureMapping returns the mapping stored under key, creating it if it does not exist
+func ensureMapping(node *yaml.Node, key string) *yaml.Node {
+	if value := mappingValue(node, key); value != nil && value.Kind == yaml.MappingNode {
+		return value
+	}
+
+	removeMappingEntry(node, key)
+
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+
+	return value
+}
+
+// setMappingScalar sets key to a scalar value, an empty tag resolves the type from the value.
+// Comments of an existing value are kept.
+func setMappingScalar(node *yaml.Node, key, value, tag string) {
+	if existing := mappingValue(node, key); existing != nil {
+		*existing = yaml.Node{
+			Kind:        yaml.ScalarNode,
+			Tag:         tag,
+			Value:       value,
+			Line:        existing.Line,
+			Column:      existing.Column,
+			HeadComment: existing.HeadComment,
+			LineComment: existing.LineComment,
+			FootComment: existing.FootComment,
+		}
+		return
+	}
+
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+	)
+}
@@ -0,0 +1,111 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// SyncedFiles are the files synced with the remote of the sync settings: the configuration files of a profile and the notes.
+// Like exported profiles, the synced settings never contain secrets, pulled settings keep the secrets of the local settings.
+type SyncedFiles struct {
+	FS    afero.Fs
+	Paths Paths
+}
+
+// Files returns the synced files by their name on the remote
+func (s SyncedFiles) Files() (map[string][]byte, error) {
+	names, err := profileFiles(s.FS, s.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(names)+1)
+	for _, name := range append(names, notesFileName) {
+		data, err := afero.ReadFile(s.FS, s.path(name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if name == settingsFileName {
+			if data, err = stripSecretSettings(data); err != nil {
+				return nil, fmt.Errorf("failed to read settings file: %w", err)
+			}
+		}
+
+		files[name] = data
+	}
+
+	return files, nil
+}
+
+// Accept reports if the file of the remote is synced
+func (s SyncedFiles) Accept(name string) bool {
+	return name == notesFileName || isProfileFile(name)
+}
+
+// Write writes the pulled files, nil content removes the file. The settings are validated before anything is written.
+func (s SyncedFiles) Write(files map[string][]byte) error {
+	files = maps.Clone(files)
+
+	for name := range files {
+		if !s.Accept(name) {
+			return fmt.Errorf("unexpected file %q", name)
+		}
+	}
+
+	if data := files[settingsFileName]; data != nil {
+		current, err := afero.ReadFile(s.FS, s.Paths.SettingsFile())
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		if data, err = keepSecretSettings(data, current); err != nil {
+			return fmt.Errorf("invalid pulled settings: %w", err)
+		}
+
+		if _, report := CheckSettings(data); report.Err() != nil {
+			return fmt.Errorf("invalid pulled settings: %w", report.Err())
+		}
+
+		files[settingsFileName] = data
+	}
+
+	for name, data := range files {
+		path := s.path(name)
+
+		if data == nil {
+			if err := s.FS.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+
+			continue
+		}
+
+		if err := s.FS.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+
+		if err := afero.WriteFile(s.FS, path, data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s SyncedFiles) path(name string) string {
+	if name == notesFileName {
+		return s.Paths.NotesFile()
+	}
+
+	return filepath.Join(s.Paths.Config, filepath.FromSlash(name))
+}
@@ -0,0 +1,58 @@
+package save
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncedFiles(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	p := Paths{Config: "/config", State: "/state"}
+	local := SyncedFiles{FS: fs, Paths: p}
+
+	require.NoError(t, afero.WriteFile(fs, p.SettingsFile(), []byte("sync:\n  backend: webdav\n  remote: https://dav.example.com/chatuino\n  password: secret\n"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, p.NotesFile(), []byte(`{"channels":{}}`), 0o600))
+	require.NoError(t, afero.WriteFile(fs, p.ThemeFile(), []byte("chat_border_color: \"#ffffff\"\n"), 0o600))
+
+	files, err := local.Files()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"channels":{}}`), files["notes.json"])
+	require.Contains(t, string(files["settings.yaml"]), "backend: webdav")
+	require.NotContains(t, string(files["settings.yaml"]), "secret", "secrets are never synced")
+	require.NotContains(t, string(files["settings.yaml"]), "dav.example.com")
+
+	require.True(t, local.Accept("scripts/a/main.lua"))
+	require.False(t, local.Accept("README.md"))
+	require.Error(t, local.Write(map[string][]byte{"../.bashrc": []byte("")}))
+
+	require.ErrorContains(t, local.Write(map[string][]byte{
+		"settings.yaml": []byte("chat:\n  layout: sideways\n"),
+		"notes.json":    []byte("{}"),
+	}), "invalid pulled settings")
+
+	notes, err := afero.ReadFile(fs, p.NotesFile())
+	require.NoError(t, err)
+	require.Equal(t, `{"channels":{}}`, string(notes), "nothing is written")
+
+	require.NoError(t, local.Write(map[string][]byte{
+		"settings.yaml": []byte("chat:\n  layout: compact\nsync:\n  backend: webdav\n"),
+		"theme.yaml":    nil,
+	}))
+
+	settings, err := afero.ReadFile(fs, p.SettingsFile())
+	require.NoError(t, err)
+
+	pulled, report := CheckSettings(settings)
+	require.NoError(t, report.Err())
+	require.Equal(t, ChatLayoutCompact, pulled.Chat.Layout)
+	require.Equal(t, "secret", pulled.Sync.Password, "the local secrets are kept")
+	require.Equal(t, "https://dav.example.com/chatuino", pulled.Sync.Remote)
+
+	exists, err := afero.Exists(fs, p.ThemeFile())
+	require.NoError(t, err)
+	require.False(t, exists, "removed on the remote")
+}
@@ -1,7 +1,11 @@
 package save
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"maps"
+	"slices"
 
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v3"
@@ -9,6 +13,9 @@ import (
 
 const (
 	themeFileName = "theme.yaml"
+
+	// DefaultThemeName is the built-in theme used when no theme is selected
+	DefaultThemeName = "dark"
 )
 
 type Theme struct {
@@ -36,6 +43,11 @@ type Theme struct {
 
 	StatusColor string `yaml:"status_color"`
 
+	// Connection health in the status bar
+	ConnectionGoodColor string `yaml:"connection_good_color"`
+	ConnectionSlowColor string `yaml:"connection_slow_color"`
+	ConnectionDownColor string `yaml:"connection_down_color"`
+
 	ChatuinoSplashColor  string `yaml:"chatuino_splash_color"`
 	SplashHighlightColor string `yaml:"splash_highlight_color"`
 
@@ -49,6 +61,15 @@ type Theme struct {
 
 	// UI chrome
 	DimmedTextColor string `yaml:"dimmed_text_color"`
+	BorderColor     string `yaml:"border_color"`
+
+	// Chat content, empty values use the terminal default
+	TimestampColor     string `yaml:"timestamp_color"`
+	UsernameColor      string `yaml:"username_color"` // used for users without a chat color, empty picks a random color
+	SystemMessageColor string `yaml:"system_message_color"`
+	OwnMessageColor    string `yaml:"own_message_color"` // username color of your own messages, empty keeps your chat color
+	MentionColor       string `yaml:"mention_color"`
+	FavoriteColor      string `yaml:"favorite_color"` // messages of favorite users
 }
 
 func BuildDefaultTheme() Theme {
@@ -84,6 +105,10 @@ func BuildDefaultTheme() Theme {
 		// Status
 		StatusColor: "#88c0d0",
 
+		ConnectionGoodColor: "#a3be8c",
+		ConnectionSlowColor: "#ebcb8b",
+		ConnectionDownColor: "#bf616a",
+
 		// Splash screen
 		ChatuinoSplashColor:  "#fd00eb",
 		SplashHighlightColor: "#88c0d0",
@@ -101,35 +126,392 @@ func BuildDefaultTheme() Theme {
 
 		// UI chrome
 		DimmedTextColor: "#4c566a",
+		BorderColor:     "#88c0d0",
+
+		// Chat content
+		TimestampColor: "#4c566a",
+		MentionColor:   "#ebcb8b",
+		FavoriteColor:  "#a3be8c",
 	}
 }
 
-func ThemeFromDisk() (Theme, error) {
-	f, err := openCreateConfigFile(afero.NewOsFs(), themeFileName)
-	if err != nil {
-		return Theme{}, err
+func buildLightTheme() Theme {
+	return Theme{
+		SevenTVEmoteColor:   "#0e7490",
+		TwitchTVEmoteColor:  "#8250df",
+		BetterTTVEmoteColor: "#cf222e",
+		FFZEmoteColor:       "#1a7f37",
+
+		InputPromptColor: "#0969da",
+
+		ChatStreamerColor:  "#bc4c00",
+		ChatVIPColor:       "#8250df",
+		ChatSubColor:       "#1a7f37",
+		ChatTurboColor:     "#0550ae",
+		ChatModeratorColor: "#1a7f37",
+		ChatIndicatorColor: "#0969da",
+
+		ChatSubAlertColor:    "#8250df",
+		ChatNoticeAlertColor: "#9a6700",
+		ChatClearChatColor:   "#bc4c00",
+		ChatErrorColor:       "#cf222e",
+
+		ListSelectedColor: "#0969da",
+		ListLabelColor:    "#57606a",
+		ActiveLabelColor:  "#9a6700",
+
+		StatusColor: "#0969da",
+
+		ConnectionGoodColor: "#1a7f37",
+		ConnectionSlowColor: "#9a6700",
+		ConnectionDownColor: "#cf222e",
+
+		ChatuinoSplashColor:  "#bf00b2",
+		SplashHighlightColor: "#0969da",
+
+		TabHeaderBackgroundColor:       "#eaeef2",
+		TabHeaderActiveBackgroundColor: "#ffffff",
+
+		InspectBorderColor: "#0969da",
+
+		ListBackgroundColor: "#ffffff",
+		ListFontColor:       "#24292f",
+
+		DimmedTextColor: "#8c959f",
+		BorderColor:     "#0969da",
+
+		TimestampColor:     "#6e7781",
+		SystemMessageColor: "#57606a",
+		MentionColor:       "#9a6700",
+		FavoriteColor:      "#1a7f37",
 	}
+}
 
-	defer f.Close()
-	stat, err := f.Stat()
-	if err != nil {
-		return Theme{}, err
+func buildSolarizedTheme() Theme {
+	return Theme{
+		SevenTVEmoteColor:   "#2aa198",
+		TwitchTVEmoteColor:  "#6c71c4",
+		BetterTTVEmoteColor: "#dc322f",
+		FFZEmoteColor:       "#859900",
+
+		InputPromptColor: "#268bd2",
+
+		ChatStreamerColor:  "#cb4b16",
+		ChatVIPColor:       "#d33682",
+		ChatSubColor:       "#859900",
+		ChatTurboColor:     "#6c71c4",
+		ChatModeratorColor: "#859900",
+		ChatIndicatorColor: "#268bd2",
+
+		ChatSubAlertColor:    "#d33682",
+		ChatNoticeAlertColor: "#b58900",
+		ChatClearChatColor:   "#cb4b16",
+		ChatErrorColor:       "#dc322f",
+
+		ListSelectedColor: "#268bd2",
+		ListLabelColor:    "#2aa198",
+		ActiveLabelColor:  "#b58900",
+
+		StatusColor: "#2aa198",
+
+		ConnectionGoodColor: "#859900",
+		ConnectionSlowColor: "#b58900",
+		ConnectionDownColor: "#dc322f",
+
+		ChatuinoSplashColor:  "#d33682",
+		SplashHighlightColor: "#268bd2",
+
+		TabHeaderBackgroundColor:       "#073642",
+		TabHeaderActiveBackgroundColor: "#002b36",
+
+		InspectBorderColor: "#268bd2",
+
+		ListBackgroundColor: "#002b36",
+		ListFontColor:       "#93a1a1",
+
+		DimmedTextColor: "#586e75",
+		BorderColor:     "#268bd2",
+
+		TimestampColor:     "#586e75",
+		SystemMessageColor: "#93a1a1",
+		MentionColor:       "#b58900",
+		FavoriteColor:      "#859900",
 	}
+}
+
+func buildGruvboxTheme() Theme {
+	return Theme{
+		SevenTVEmoteColor:   "#8ec07c",
+		TwitchTVEmoteColor:  "#d3869b",
+		BetterTTVEmoteColor: "#fb4934",
+		FFZEmoteColor:       "#b8bb26",
+
+		InputPromptColor: "#83a598",
+
+		ChatStreamerColor:  "#fe8019",
+		ChatVIPColor:       "#d3869b",
+		ChatSubColor:       "#b8bb26",
+		ChatTurboColor:     "#83a598",
+		ChatModeratorColor: "#b8bb26",
+		ChatIndicatorColor: "#fabd2f",
+
+		ChatSubAlertColor:    "#d3869b",
+		ChatNoticeAlertColor: "#fabd2f",
+		ChatClearChatColor:   "#fe8019",
+		ChatErrorColor:       "#fb4934",
+
+		ListSelectedColor: "#fabd2f",
+		ListLabelColor:    "#83a598",
+		ActiveLabelColor:  "#fabd2f",
+
+		StatusColor: "#8ec07c",
+
+		ConnectionGoodColor: "#b8bb26",
+		ConnectionSlowColor: "#fabd2f",
+		ConnectionDownColor: "#fb4934",
+
+		ChatuinoSplashColor:  "#fe8019",
+		SplashHighlightColor: "#fabd2f",
+
+		TabHeaderBackgroundColor:       "#3c3836",
+		TabHeaderActiveBackgroundColor: "#282828",
+
+		InspectBorderColor: "#83a598",
 
-	if stat.Size() == 0 {
-		return BuildDefaultTheme(), nil
+		ListBackgroundColor: "#282828",
+		ListFontColor:       "#ebdbb2",
+
+		DimmedTextColor: "#665c54",
+		BorderColor:     "#a89984",
+
+		TimestampColor:     "#928374",
+		SystemMessageColor: "#d5c4a1",
+		MentionColor:       "#fabd2f",
+		FavoriteColor:      "#b8bb26",
 	}
+}
 
-	b, err := io.ReadAll(f)
+// buildHighContrastTheme uses bright colors and white text, which reach a contrast ratio of at least 7:1 on a black background
+func buildHighContrastTheme() Theme {
+	return Theme{
+		SevenTVEmoteColor:   "#00ffff",
+		TwitchTVEmoteColor:  "#ff80ff",
+		BetterTTVEmoteColor: "#ff7070",
+		FFZEmoteColor:       "#80ff80",
+
+		InputPromptColor: "#ffff00",
+
+		ChatStreamerColor:  "#ffa500",
+		ChatVIPColor:       "#ff80ff",
+		ChatSubColor:       "#80ff80",
+		ChatTurboColor:     "#a0b4ff",
+		ChatModeratorColor: "#80ff80",
+		ChatIndicatorColor: "#ffff00",
+
+		ChatSubAlertColor:    "#ff80ff",
+		ChatNoticeAlertColor: "#ffff00",
+		ChatClearChatColor:   "#ffa500",
+		ChatErrorColor:       "#ff7070",
+
+		ListSelectedColor: "#ffff00",
+		ListLabelColor:    "#00ffff",
+		ActiveLabelColor:  "#ffff00",
+
+		StatusColor: "#00ffff",
+
+		ConnectionGoodColor: "#80ff80",
+		ConnectionSlowColor: "#ffff00",
+		ConnectionDownColor: "#ff7070",
+
+		ChatuinoSplashColor:  "#ff80ff",
+		SplashHighlightColor: "#ffff00",
+
+		TabHeaderBackgroundColor:       "#303030",
+		TabHeaderActiveBackgroundColor: "#000000",
+
+		InspectBorderColor: "#ffffff",
+
+		ListBackgroundColor: "#000000",
+		ListFontColor:       "#ffffff",
+
+		DimmedTextColor: "#b0b0b0",
+		BorderColor:     "#ffffff",
+
+		TimestampColor:     "#c0c0c0",
+		SystemMessageColor: "#ffffff",
+		MentionColor:       "#ffff00",
+		FavoriteColor:      "#80ff80",
+	}
+}
+
+// buildColorblindTheme uses the Okabe-Ito palette, its colors stay distinguishable with deuteranopia and protanopia.
+// Colors which only differ in red and green, like the connection states, use blue, yellow and vermillion instead.
+func buildColorblindTheme() Theme {
+	return Theme{
+		SevenTVEmoteColor:   "#56b4e9",
+		TwitchTVEmoteColor:  "#cc79a7",
+		BetterTTVEmoteColor: "#d55e00",
+		FFZEmoteColor:       "#f0e442",
+
+		InputPromptColor: "#56b4e9",
+
+		ChatStreamerColor:  "#e69f00",
+		ChatVIPColor:       "#cc79a7",
+		ChatSubColor:       "#56b4e9",
+		ChatTurboColor:     "#0072b2",
+		ChatModeratorColor: "#009e73",
+		ChatIndicatorColor: "#56b4e9",
+
+		ChatSubAlertColor:    "#cc79a7",
+		ChatNoticeAlertColor: "#f0e442",
+		ChatClearChatColor:   "#e69f00",
+		ChatErrorColor:       "#d55e00",
+
+		ListSelectedColor: "#56b4e9",
+		ListLabelColor:    "#56b4e9",
+		ActiveLabelColor:  "#f0e442",
+
+		StatusColor: "#56b4e9",
+
+		ConnectionGoodColor: "#56b4e9",
+		ConnectionSlowColor: "#f0e442",
+		ConnectionDownColor: "#d55e00",
+
+		ChatuinoSplashColor:  "#cc79a7",
+		SplashHighlightColor: "#56b4e9",
+
+		TabHeaderBackgroundColor:       "#3b4252",
+		TabHeaderActiveBackgroundColor: "#2e3440",
+
+		InspectBorderColor: "#0072b2",
+
+		ListBackgroundColor: "#2e3440",
+		ListFontColor:       "#d8dee9",
+
+		DimmedTextColor: "#6b7385",
+		BorderColor:     "#56b4e9",
+
+		TimestampColor: "#6b7385",
+		MentionColor:   "#f0e442",
+		FavoriteColor:  "#e69f00",
+	}
+}
+
+// BuiltinThemes returns the themes shipped with Chatuino
+func BuiltinThemes() map[string]Theme {
+	return map[string]Theme{
+		DefaultThemeName: BuildDefaultTheme(),
+		"light":          buildLightTheme(),
+		"solarized":      buildSolarizedTheme(),
+		"gruvbox":        buildGruvboxTheme(),
+		"high-contrast":  buildHighContrastTheme(),
+		"colorblind":     buildColorblindTheme(),
+	}
+}
+
+// ThemeSet contains all themes available to switch between, the built-in themes and the themes defined by the user
+type ThemeSet struct {
+	Active string
+	Themes map[string]Theme
+}
+
+func (s ThemeSet) ActiveTheme() Theme {
+	return s.Themes[s.Active]
+}
+
+// Names returns the names of all available themes in alphabetical order
+func (s ThemeSet) Names() []string {
+	return slices.Sorted(maps.Keys(s.Themes))
+}
+
+type themeFile struct {
+	Active string               `yaml:"active"`
+	Themes map[string]yaml.Node `yaml:"themes"`
+}
+
+// parseThemeSet reads the theme file. User defined themes are listed under themes and
+// are based on a built-in theme (base, default dark). Colors at the top level override the active theme.
+func parseThemeSet(b []byte) (ThemeSet, error) {
+	builtins := BuiltinThemes()
+
+	set := ThemeSet{
+		Active: DefaultThemeName,
+		Themes: BuiltinThemes(),
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return set, nil
+	}
+
+	var file themeFile
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return ThemeSet{}, err
+	}
+
+	for name, node := range file.Themes {
+		var meta struct {
+			Base string `yaml:"base"`
+		}
+
+		if err := node.Decode(&meta); err != nil {
+			return ThemeSet{}, fmt.Errorf("theme %q: %w", name, err)
+		}
+
+		if meta.Base == "" {
+			meta.Base = DefaultThemeName
+		}
+
+		theme, ok := builtins[meta.Base]
+		if !ok {
+			return ThemeSet{}, fmt.Errorf("theme %q: unknown base theme %q", name, meta.Base)
+		}
+
+		if err := node.Decode(&theme); err != nil {
+			return ThemeSet{}, fmt.Errorf("theme %q: %w", name, err)
+		}
+
+		set.Themes[name] = theme
+	}
+
+	if file.Active != "" {
+		if _, ok := set.Themes[file.Active]; !ok {
+			return ThemeSet{}, fmt.Errorf("active theme %q does not exist", file.Active)
+		}
+
+		set.Active = file.Active
+	}
+
+	active := set.ActiveTheme()
+	if err := yaml.Unmarshal(b, &active); err != nil {
+		return ThemeSet{}, err
+	}
+
+	set.Themes[set.Active] = active
+
+	return set, nil
+}
+
+func ThemeFromDisk() (Theme, error) {
+	set, err := ThemeSetFromDisk()
 	if err != nil {
 		return Theme{}, err
 	}
 
-	theme := BuildDefaultTheme()
+	return set.ActiveTheme(), nil
+}
 
-	if err := yaml.Unmarshal(b, &theme); err != nil {
-		return Theme{}, err
+func ThemeSetFromDisk() (ThemeSet, error) {
+	f, err := openCreateConfigFile(afero.NewOsFs(), themeFileName)
+	if err != nil {
+		return ThemeSet{}, err
+	}
+
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return ThemeSet{}, err
 	}
 
-	return theme, nil
+	return parseThemeSet(b)
 }
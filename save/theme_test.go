@@ -0,0 +1,83 @@
+package save
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThemeSet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file uses default theme", func(t *testing.T) {
+		t.Parallel()
+
+		set, err := parseThemeSet(nil)
+		require.NoError(t, err)
+		require.Equal(t, DefaultThemeName, set.Active)
+		require.Equal(t, BuildDefaultTheme(), set.ActiveTheme())
+		require.Equal(t, []string{"colorblind", "dark", "gruvbox", "high-contrast", "light", "solarized"}, set.Names())
+	})
+
+	t.Run("top level colors override active theme", func(t *testing.T) {
+		t.Parallel()
+
+		set, err := parseThemeSet([]byte("chat_error_color: \"#ff0000\"\n"))
+		require.NoError(t, err)
+		require.Equal(t, "#ff0000", set.ActiveTheme().ChatErrorColor)
+		require.Equal(t, BuildDefaultTheme().InputPromptColor, set.ActiveTheme().InputPromptColor)
+
+		// other themes are not affected
+		require.Equal(t, buildLightTheme(), set.Themes["light"])
+	})
+
+	t.Run("user defined theme based on built-in", func(t *testing.T) {
+		t.Parallel()
+
+		set, err := parseThemeSet([]byte(`
+active: mine
+themes:
+  mine:
+    base: gruvbox
+    mention_color: "#123456"
+`))
+		require.NoError(t, err)
+		require.Equal(t, "mine", set.Active)
+
+		want := buildGruvboxTheme()
+		want.MentionColor = "#123456"
+		require.Equal(t, want, set.ActiveTheme())
+	})
+
+	t.Run("unknown active theme", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseThemeSet([]byte("active: neon\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("unknown base theme", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseThemeSet([]byte("themes:\n  mine:\n    base: neon\n"))
+		require.Error(t, err)
+	})
+}
+
+func Fuzz_parseThemeSet(f *testing.F) {
+	f.Add([]byte(""))
+	f.Add([]byte("chat_error_color: \"#ff0000\"\n"))
+	f.Add([]byte("active: mine\nthemes:\n  mine:\n    base: light\n    chat_error_color: \"#00ff00\"\n"))
+	f.Add([]byte("active: missing\n"))
+	f.Add([]byte("themes: [mine]\n"))
+
+	// a broken theme file must result in an error, never panic
+	f.Fuzz(func(t *testing.T, b []byte) {
+		set, err := parseThemeSet(b)
+		if err != nil {
+			return
+		}
+
+		require.Contains(t, set.Themes, set.Active)
+	})
+}
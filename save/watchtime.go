@@ -0,0 +1,123 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const watchtimeFileName = "watchtime.json"
+
+// Watchtime is the time a channel tab was open and the part of it the tab was focused
+type Watchtime struct {
+	Open    time.Duration `json:"open"`
+	Focused time.Duration `json:"focused"`
+}
+
+// WatchtimeStore persists the watchtime per channel. The file is read once and written on every change.
+type WatchtimeStore struct {
+	fs afero.Fs
+
+	m        sync.Mutex
+	channels map[string]Watchtime // keyed by the lower case channel login, nil until loaded
+}
+
+func NewWatchtimeStore(fs afero.Fs) *WatchtimeStore {
+	return &WatchtimeStore{fs: fs}
+}
+
+// Watchtime returns the totals of all channels
+func (s *WatchtimeStore) Watchtime() (map[string]Watchtime, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return maps.Clone(s.channels), nil
+}
+
+// AddWatchtime adds the time to the totals of the channels
+func (s *WatchtimeStore) AddWatchtime(add map[string]Watchtime) error {
+	if len(add) == 0 {
+		return nil
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	for channel, w := range add {
+		channel = strings.ToLower(channel)
+		total := s.channels[channel]
+		total.Open += w.Open
+		total.Focused += w.Focused
+		s.channels[channel] = total
+	}
+
+	return s.save()
+}
+
+func (s *WatchtimeStore) load() error {
+	if s.channels != nil {
+		return nil
+	}
+
+	f, err := openCreateStateFile(s.fs, watchtimeFileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	channels := map[string]Watchtime{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &channels); err != nil {
+			syntaxErr := &json.SyntaxError{}
+			if !errors.As(err, &syntaxErr) {
+				return err
+			}
+		}
+	}
+
+	s.channels = channels
+	return nil
+}
+
+func (s *WatchtimeStore) save() error {
+	f, err := openCreateStateFile(s.fs, watchtimeFileName)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	data, err := json.MarshalIndent(s.channels, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = f.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, bytes.NewReader(data))
+	return err
+}
@@ -0,0 +1,36 @@
+package save
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchtimeStore(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	store := NewWatchtimeStore(fs)
+	require.NoError(t, store.AddWatchtime(map[string]Watchtime{
+		"Lirik":      {Open: time.Hour, Focused: time.Minute * 20},
+		"sodapoppin": {Open: time.Minute},
+	}))
+	require.NoError(t, store.AddWatchtime(map[string]Watchtime{"lirik": {Open: time.Minute, Focused: time.Minute}}))
+
+	// the totals are read from the file by a new store
+	got, err := NewWatchtimeStore(fs).Watchtime()
+	require.NoError(t, err)
+	require.Equal(t, map[string]Watchtime{
+		"lirik":      {Open: time.Hour + time.Minute, Focused: time.Minute * 21},
+		"sodapoppin": {Open: time.Minute},
+	}, got)
+
+	// the returned map is a copy
+	got["lirik"] = Watchtime{}
+	got, err = store.Watchtime()
+	require.NoError(t, err)
+	require.Equal(t, time.Minute*21, got["lirik"].Focused)
+}
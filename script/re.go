@@ -0,0 +1,122 @@
+package script
+
+import (
+	"regexp"
+	"sync"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// reModule gives scripts access to regular expressions using the RE2 syntax of Go
+var reModule = &starlarkstruct.Module{
+	Name: "re",
+	Members: starlark.StringDict{
+		"match":    starlark.NewBuiltin("re.match", reMatch),
+		"find":     starlark.NewBuiltin("re.find", reFind),
+		"find_all": starlark.NewBuiltin("re.find_all", reFindAll),
+		"sub":      starlark.NewBuiltin("re.sub", reSub),
+		"split":    starlark.NewBuiltin("re.split", reSplit),
+	},
+}
+
+// compiled caches the compiled patterns, since handlers are called for every message
+var compiled sync.Map // pattern -> *regexp.Regexp
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := compiled.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled.Store(pattern, re)
+
+	return re, nil
+}
+
+func unpackPattern(b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (*regexp.Regexp, string, error) {
+	var pattern, text string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &pattern, &text); err != nil {
+		return nil, "", err
+	}
+
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return re, text, nil
+}
+
+// reMatch reports if the text contains a match of the pattern
+func reMatch(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, text, err := unpackPattern(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.Bool(re.MatchString(text)), nil
+}
+
+// reFind returns the first match and its groups as list, None if there is no match
+func reFind(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, text, err := unpackPattern(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return starlark.None, nil
+	}
+
+	return stringsValue(match), nil
+}
+
+// reFindAll returns all matches
+func reFindAll(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, text, err := unpackPattern(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	return stringsValue(re.FindAllString(text, -1)), nil
+}
+
+// reSub replaces all matches, the replacement can reference groups with $1 or ${name}
+func reSub(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern, replacement, text string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 3, &pattern, &replacement, &text); err != nil {
+		return nil, err
+	}
+
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.String(re.ReplaceAllString(text, replacement)), nil
+}
+
+// reSplit splits the text around the matches
+func reSplit(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	re, text, err := unpackPattern(b, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+
+	return stringsValue(re.Split(text, -1)), nil
+}
+
+func stringsValue(values []string) *starlark.List {
+	list := make([]starlark.Value, 0, len(values))
+	for _, v := range values {
+		list = append(list, starlark.String(v))
+	}
+
+	return starlark.NewList(list)
+}
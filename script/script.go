@@ -0,0 +1,508 @@
+// Package script runs user scripts written in Starlark, a Python dialect, to transform chat messages and add slash commands.
+//
+// Scripts are loaded from the scripts directory in the config directory, every *.star file is a script.
+// Scripts register functions when they are loaded:
+//
+//	def shout(msg):
+//	    if msg.user == "nightbot":
+//	        return False                       # hide the message
+//	    return msg.text.replace("KEKW", "LUL") # replace the text
+//
+//	on_message(shout)
+//
+//	def hello(ctx):
+//	    return "Hello " + ctx.args             # sent to chat
+//
+//	command("hello", hello, help="<user>")
+//
+// Scripts run sandboxed, they can't access files, the network or other programs and every call is limited in the
+// number of execution steps.
+package script
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	starlarkjson "go.starlark.net/lib/json"
+	starlarkmath "go.starlark.net/lib/math"
+	starlarktime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+const (
+	// FileExtension is the extension of script files in the scripts directory
+	FileExtension = ".star"
+
+	// maxSteps limits the execution steps of loading a script and every call, so endless loops don't block the UI
+	maxSteps = 1_000_000
+
+	// registryKey is the thread local holding the registry while a script is loaded
+	registryKey = "registry"
+)
+
+var commandNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+var fileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// Message is a chat message passed to the message handlers
+type Message struct {
+	ID           string
+	Channel      string
+	User         string
+	DisplayName  string
+	Text         string
+	Mod          bool
+	VIP          bool
+	Subscriber   bool
+	FirstMessage bool
+	Bot          bool // known bot of FFZ or BTTV
+}
+
+// TransformResult is the result of all message handlers
+type TransformResult struct {
+	Text      string   // text shown instead of the original text
+	Hide      bool     // message should not be shown
+	Highlight []string // words which should be highlighted
+}
+
+// Command is a slash command registered by a script
+type Command struct {
+	Name   string
+	Help   string
+	Script string
+}
+
+// CommandContext is passed to commands
+type CommandContext struct {
+	Channel   string
+	ChannelID string
+	Account   string
+	Args      string
+}
+
+// CommandResult is the result of a command
+type CommandResult struct {
+	Send   string // chat message sent to the channel
+	Notice string // notice only shown to the user
+}
+
+type handler struct {
+	script string
+	fn     starlark.Callable
+}
+
+type command struct {
+	Command
+	fn starlark.Callable
+}
+
+// registry collects the handlers and commands registered while loading the scripts
+type registry struct {
+	script   string
+	handlers []handler
+	commands map[string]command
+}
+
+// Engine holds the loaded scripts, it is safe for concurrent use
+type Engine struct {
+	logger zerolog.Logger
+	dir    string
+
+	m        sync.RWMutex
+	handlers []handler
+	commands map[string]command
+}
+
+// Load loads all scripts in dir. A missing directory is not an error.
+// Scripts failing to load are skipped and reported in the returned error, the engine can be used anyway.
+func Load(logger zerolog.Logger, dir string) (*Engine, error) {
+	e := &Engine{
+		logger:   logger.With().Str("component", "script").Logger(),
+		dir:      dir,
+		commands: map[string]command{},
+	}
+
+	return e, e.Reload()
+}
+
+// Reload loads the scripts again, used after the scripts were changed
+func (e *Engine) Reload() error {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read scripts directory: %w", err)
+	}
+
+	reg := &registry{commands: map[string]command{}}
+
+	var errs []error
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != FileExtension {
+			continue
+		}
+
+		src, err := os.ReadFile(filepath.Join(e.dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := e.load(reg, entry.Name(), src); err != nil {
+			errs = append(errs, fmt.Errorf("failed to load script %s: %w", entry.Name(), err))
+		}
+	}
+
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.handlers = reg.handlers
+	e.commands = reg.commands
+
+	e.logger.Info().Int("handlers", len(reg.handlers)).Int("commands", len(reg.commands)).Msg("loaded scripts")
+
+	return errors.Join(errs...)
+}
+
+// load executes a script, the functions it registers are only kept if the script loaded without error
+func (e *Engine) load(reg *registry, name string, src []byte) error {
+	scriptReg := &registry{script: name, commands: map[string]command{}}
+
+	thread := e.thread(name)
+	thread.SetLocal(registryKey, scriptReg)
+
+	if _, err := starlark.ExecFileOptions(fileOptions, thread, name, src, predeclared); err != nil {
+		return scriptError(err)
+	}
+
+	for _, c := range scriptReg.commands {
+		if other, ok := reg.commands[c.Name]; ok {
+			return fmt.Errorf("command %q is already registered by %s", c.Name, other.Script)
+		}
+	}
+
+	reg.handlers = append(reg.handlers, scriptReg.handlers...)
+	for name, c := range scriptReg.commands {
+		reg.commands[name] = c
+	}
+
+	return nil
+}
+
+// Transform runs all message handlers in the order of the script file names, each handler gets the text returned by the previous handler.
+// Failing handlers are logged and skipped.
+func (e *Engine) Transform(msg Message) TransformResult {
+	e.m.RLock()
+	handlers := e.handlers
+	e.m.RUnlock()
+
+	result := TransformResult{Text: msg.Text}
+
+	for _, h := range handlers {
+		msg.Text = result.Text
+
+		v, err := starlark.Call(e.thread(h.script), h.fn, starlark.Tuple{messageValue(msg)}, nil)
+		if err != nil {
+			e.logger.Err(scriptError(err)).Str("script", h.script).Msg("message handler failed")
+			continue
+		}
+
+		if err := applyHandlerResult(&result, v); err != nil {
+			e.logger.Err(err).Str("script", h.script).Msg("message handler returned invalid value")
+			continue
+		}
+
+		if result.Hide {
+			break
+		}
+	}
+
+	return result
+}
+
+// Commands returns the registered commands sorted by name
+func (e *Engine) Commands() []Command {
+	e.m.RLock()
+	defer e.m.RUnlock()
+
+	commands := make([]Command, 0, len(e.commands))
+	for _, c := range e.commands {
+		commands = append(commands, c.Command)
+	}
+
+	slices.SortFunc(commands, func(a, b Command) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return commands
+}
+
+// HasCommand reports if a script registered the command
+func (e *Engine) HasCommand(name string) bool {
+	e.m.RLock()
+	defer e.m.RUnlock()
+
+	_, ok := e.commands[name]
+	return ok
+}
+
+// RunCommand runs the command registered with the name
+func (e *Engine) RunCommand(name string, ctx CommandContext) (CommandResult, error) {
+	e.m.RLock()
+	c, ok := e.commands[name]
+	e.m.RUnlock()
+
+	if !ok {
+		return CommandResult{}, fmt.Errorf("unknown command %q", name)
+	}
+
+	v, err := starlark.Call(e.thread(c.Script), c.fn, starlark.Tuple{contextValue(ctx)}, nil)
+	if err != nil {
+		return CommandResult{}, scriptError(err)
+	}
+
+	return commandResult(v)
+}
+
+func (e *Engine) thread(script string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: script,
+		Print: func(_ *starlark.Thread, msg string) {
+			e.logger.Info().Str("script", script).Msg(msg)
+		},
+		// load is not supported, every script is standalone
+	}
+
+	thread.SetMaxExecutionSteps(maxSteps)
+
+	return thread
+}
+
+// scriptError returns the error with the script backtrace if available
+func scriptError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+
+	return err
+}
+
+func messageValue(msg Message) starlark.Value {
+	return starlarkstruct.FromStringDict(starlark.String("message"), starlark.StringDict{
+		"id":            starlark.String(msg.ID),
+		"channel":       starlark.String(msg.Channel),
+		"user":          starlark.String(msg.User),
+		"display_name":  starlark.String(msg.DisplayName),
+		"text":          starlark.String(msg.Text),
+		"mod":           starlark.Bool(msg.Mod),
+		"vip":           starlark.Bool(msg.VIP),
+		"subscriber":    starlark.Bool(msg.Subscriber),
+		"first_message": starlark.Bool(msg.FirstMessage),
+		"bot":           starlark.Bool(msg.Bot),
+	})
+}
+
+func contextValue(ctx CommandContext) starlark.Value {
+	argv := make([]starlark.Value, 0)
+	for arg := range strings.FieldsSeq(ctx.Args) {
+		argv = append(argv, starlark.String(arg))
+	}
+
+	return starlarkstruct.FromStringDict(starlark.String("context"), starlark.StringDict{
+		"channel":    starlark.String(ctx.Channel),
+		"channel_id": starlark.String(ctx.ChannelID),
+		"account":    starlark.String(ctx.Account),
+		"args":       starlark.String(ctx.Args),
+		"argv":       starlark.NewList(argv),
+	})
+}
+
+// applyHandlerResult applies the value returned by a message handler:
+// None keeps the message, False hides it, a string replaces the text and a dict can set text, hide and highlight
+func applyHandlerResult(result *TransformResult, v starlark.Value) error {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil
+	case starlark.Bool:
+		result.Hide = !bool(v)
+		return nil
+	case starlark.String:
+		result.Text = string(v)
+		return nil
+	case *starlark.Dict:
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return fmt.Errorf("expected string keys, got %s", item[0].Type())
+			}
+
+			switch key {
+			case "text":
+				text, ok := starlark.AsString(item[1])
+				if !ok {
+					return fmt.Errorf("expected string for text, got %s", item[1].Type())
+				}
+				result.Text = text
+			case "hide":
+				result.Hide = bool(item[1].Truth())
+			case "highlight":
+				words, err := stringList(item[1])
+				if err != nil {
+					return fmt.Errorf("invalid highlight: %w", err)
+				}
+				result.Highlight = append(result.Highlight, words...)
+			default:
+				return fmt.Errorf("unknown key %q, expected text, hide or highlight", key)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("expected None, bool, string or dict, got %s", v.Type())
+}
+
+// commandResult converts the value returned by a command: a string is sent to chat, a dict can set send and notice
+func commandResult(v starlark.Value) (CommandResult, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return CommandResult{}, nil
+	case starlark.String:
+		return CommandResult{Send: string(v)}, nil
+	case *starlark.Dict:
+		var result CommandResult
+
+		for _, item := range v.Items() {
+			key, _ := starlark.AsString(item[0])
+
+			value, ok := starlark.AsString(item[1])
+			if !ok {
+				return CommandResult{}, fmt.Errorf("expected string for %s, got %s", item[0], item[1].Type())
+			}
+
+			switch key {
+			case "send":
+				result.Send = value
+			case "notice":
+				result.Notice = value
+			default:
+				return CommandResult{}, fmt.Errorf("unknown key %s, expected send or notice", item[0])
+			}
+		}
+
+		return result, nil
+	}
+
+	return CommandResult{}, fmt.Errorf("command returned %s, expected None, string or dict", v.Type())
+}
+
+// stringList accepts a string or an iterable of strings
+func stringList(v starlark.Value) ([]string, error) {
+	if s, ok := starlark.AsString(v); ok {
+		return []string{s}, nil
+	}
+
+	iterable, ok := v.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("expected string or list of strings, got %s", v.Type())
+	}
+
+	var list []string
+
+	iter := iterable.Iterate()
+	defer iter.Done()
+
+	var item starlark.Value
+	for iter.Next(&item) {
+		s, ok := starlark.AsString(item)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %s", item.Type())
+		}
+		list = append(list, s)
+	}
+
+	return list, nil
+}
+
+// predeclared are the names available to scripts
+var predeclared = starlark.StringDict{
+	"on_message": starlark.NewBuiltin("on_message", onMessage),
+	"command":    starlark.NewBuiltin("command", registerCommand),
+	"json":       starlarkjson.Module,
+	"math":       starlarkmath.Module,
+	"time":       starlarktime.Module,
+	"re":         reModule,
+}
+
+// loadingRegistry returns the registry of the script being loaded, registering functions is only allowed on load
+func loadingRegistry(thread *starlark.Thread, name string) (*registry, error) {
+	reg, ok := thread.Local(registryKey).(*registry)
+	if !ok {
+		return nil, fmt.Errorf("%s can only be called when the script is loaded", name)
+	}
+
+	return reg, nil
+}
+
+func onMessage(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var fn starlark.Callable
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &fn); err != nil {
+		return nil, err
+	}
+
+	reg, err := loadingRegistry(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	reg.handlers = append(reg.handlers, handler{script: reg.script, fn: fn})
+
+	return starlark.None, nil
+}
+
+func registerCommand(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		name string
+		fn   starlark.Callable
+		help string
+	)
+
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "fn", &fn, "help?", &help); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimPrefix(name, "/")
+	if !commandNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%s: invalid command name %q, only lowercase letters, digits and _ are allowed", b.Name(), name)
+	}
+
+	reg, err := loadingRegistry(thread, b.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := reg.commands[name]; ok {
+		return nil, fmt.Errorf("%s: command %q is already registered", b.Name(), name)
+	}
+
+	reg.commands[name] = command{
+		Command: Command{Name: name, Help: help, Script: reg.script},
+		fn:      fn,
+	}
+
+	return starlark.None, nil
+}
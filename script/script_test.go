@@ -0,0 +1,154 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func loadScripts(t *testing.T, scripts map[string]string) (*Engine, error) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, src := range scripts {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600))
+	}
+
+	return Load(zerolog.Nop(), dir)
+}
+
+func TestEngine_Transform(t *testing.T) {
+	t.Parallel()
+
+	e, err := loadScripts(t, map[string]string{
+		"1_replace.star": `
+def replace(msg):
+    if msg.user == "nightbot":
+        return False
+    return msg.text.replace("KEKW", "LUL")
+
+on_message(replace)
+`,
+		"2_highlight.star": `
+def highlight(msg):
+    words = re.find_all("(?i)\\bpog\\w*", msg.text)
+    if words:
+        return {"highlight": words, "text": msg.text + "!"}
+
+on_message(highlight)
+`,
+		"3_failing.star": `
+def failing(msg):
+    return 1 // 0
+
+on_message(failing)
+`,
+		"readme.txt": "not a script",
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		msg  Message
+		want TransformResult
+	}{
+		{
+			name: "unchanged",
+			msg:  Message{User: "viewer", Text: "hello"},
+			want: TransformResult{Text: "hello"},
+		},
+		{
+			name: "handlers get the text of the previous handler",
+			msg:  Message{User: "viewer", Text: "KEKW PogChamp"},
+			want: TransformResult{Text: "LUL PogChamp!", Highlight: []string{"PogChamp"}},
+		},
+		{
+			name: "hidden",
+			msg:  Message{User: "nightbot", Text: "PogChamp"},
+			want: TransformResult{Text: "PogChamp", Hide: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, e.Transform(tc.msg))
+		})
+	}
+}
+
+func TestEngine_RunCommand(t *testing.T) {
+	t.Parallel()
+
+	e, err := loadScripts(t, map[string]string{
+		"commands.star": `
+def hello(ctx):
+    return "Hello " + ctx.args + " in " + ctx.channel
+
+def count(ctx):
+    return {"notice": "%d arguments" % len(ctx.argv)}
+
+def loop(ctx):
+    while True:
+        pass
+
+command("hello", hello, help="<user>")
+command("/count", count)
+command("loop", loop)
+`,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []Command{
+		{Name: "count", Script: "commands.star"},
+		{Name: "hello", Help: "<user>", Script: "commands.star"},
+		{Name: "loop", Script: "commands.star"},
+	}, e.Commands())
+	require.True(t, e.HasCommand("hello"))
+	require.False(t, e.HasCommand("ban"))
+
+	result, err := e.RunCommand("hello", CommandContext{Channel: "lirik", Args: "viewer"})
+	require.NoError(t, err)
+	require.Equal(t, CommandResult{Send: "Hello viewer in lirik"}, result)
+
+	result, err = e.RunCommand("count", CommandContext{Args: " a  b "})
+	require.NoError(t, err)
+	require.Equal(t, CommandResult{Notice: "2 arguments"}, result)
+
+	_, err = e.RunCommand("loop", CommandContext{})
+	require.ErrorContains(t, err, "too many steps")
+
+	_, err = e.RunCommand("missing", CommandContext{})
+	require.ErrorContains(t, err, `unknown command "missing"`)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	e, err := loadScripts(t, map[string]string{
+		"a_ok.star":        `command("hello", lambda ctx: "hi")`,
+		"b_duplicate.star": `command("hello", lambda ctx: "hey")`,
+		"c_syntax.star":    `def broken(`,
+		"d_load.star":      `load("other.star", "x")`,
+		"e_late.star": `
+def register(msg):
+    on_message(register)
+
+on_message(register)
+`,
+	})
+	require.ErrorContains(t, err, `failed to load script b_duplicate.star: command "hello" is already registered by a_ok.star`)
+	require.ErrorContains(t, err, "failed to load script c_syntax.star")
+	require.ErrorContains(t, err, "failed to load script d_load.star")
+
+	// scripts without errors are still loaded
+	result, err := e.RunCommand("hello", CommandContext{})
+	require.NoError(t, err)
+	require.Equal(t, CommandResult{Send: "hi"}, result)
+
+	// registering is only allowed while loading, the failing handler keeps the message
+	require.Equal(t, TransformResult{Text: "text"}, e.Transform(Message{Text: "text"}))
+}
@@ -0,0 +1,115 @@
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// CheckInterval is the minimum time between two checks for a new release
+const CheckInterval = time.Hour * 24
+
+// checkState is the result of the last check, stored so GitHub is asked at most once per CheckInterval
+type checkState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Tag       string    `json:"tag"`
+	URL       string    `json:"url"`
+	Dismissed string    `json:"dismissed"` // version whose notice was dismissed, it is not shown again
+}
+
+// Checker checks for a release newer than the running version on startup
+type Checker struct {
+	updater *Updater
+	fs      afero.Fs
+	path    string
+	current string
+	now     func() time.Time
+}
+
+func NewChecker(updater *Updater, fs afero.Fs, path, current string) *Checker {
+	return &Checker{
+		updater: updater,
+		fs:      fs,
+		path:    path,
+		current: current,
+		now:     time.Now,
+	}
+}
+
+// Check returns the latest release and reports whether it is newer than the running version and its notice was not dismissed.
+// The stored result of the last check is used if it is younger than CheckInterval.
+func (c *Checker) Check(ctx context.Context) (Release, bool, error) {
+	state, err := c.load()
+	if err != nil {
+		return Release{}, false, err
+	}
+
+	if c.now().Sub(state.CheckedAt) >= CheckInterval {
+		release, err := c.updater.Latest(ctx)
+		if err != nil {
+			return Release{}, false, err
+		}
+
+		state.CheckedAt, state.Tag, state.URL = c.now(), release.Tag, release.URL
+		if err := c.store(state); err != nil {
+			return Release{}, false, err
+		}
+	}
+
+	release := Release{Tag: state.Tag, URL: state.URL}
+	if release.Version() == state.Dismissed {
+		return release, false, nil
+	}
+
+	return release, IsNewer(c.current, release.Version()), nil
+}
+
+// Dismiss hides the notice of the version until a newer release is published
+func (c *Checker) Dismiss(version string) error {
+	state, err := c.load()
+	if err != nil {
+		return err
+	}
+
+	state.Dismissed = version
+
+	return c.store(state)
+}
+
+func (c *Checker) load() (checkState, error) {
+	var state checkState
+
+	data, err := afero.ReadFile(c.fs, c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+
+	if err != nil {
+		return state, err
+	}
+
+	// a broken file is replaced by the next check
+	if err := json.Unmarshal(data, &state); err != nil {
+		return checkState{}, nil
+	}
+
+	return state, nil
+}
+
+func (c *Checker) store(state checkState) error {
+	if err := c.fs.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.fs, c.path, data, 0o600)
+}
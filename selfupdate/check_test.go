@@ -0,0 +1,62 @@
+package selfupdate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_ = json.NewEncoder(w).Encode(Release{Tag: "v0.7.0", URL: "https://github.com/julez-dev/chatuino/releases/tag/v0.7.0"})
+	}))
+	t.Cleanup(srv.Close)
+
+	updater := New(srv.Client(), "0.6.0")
+	updater.baseURL = srv.URL
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	checker := NewChecker(updater, afero.NewMemMapFs(), "/state/update_check.json", "0.6.0")
+	checker.now = func() time.Time { return now }
+
+	release, newer, err := checker.Check(t.Context())
+	require.NoError(t, err)
+	require.True(t, newer)
+	require.Equal(t, "0.7.0", release.Version())
+	require.Equal(t, "https://github.com/julez-dev/chatuino/releases/tag/v0.7.0", release.URL)
+	require.EqualValues(t, 1, requests.Load())
+
+	// the stored result is used within a day
+	now = now.Add(time.Hour * 23)
+	_, newer, err = checker.Check(t.Context())
+	require.NoError(t, err)
+	require.True(t, newer)
+	require.EqualValues(t, 1, requests.Load())
+
+	require.NoError(t, checker.Dismiss("0.7.0"))
+	_, newer, err = checker.Check(t.Context())
+	require.NoError(t, err)
+	require.False(t, newer, "dismissed version")
+
+	now = now.Add(time.Hour)
+	_, newer, err = checker.Check(t.Context())
+	require.NoError(t, err)
+	require.False(t, newer, "still dismissed after checking again")
+	require.EqualValues(t, 2, requests.Load())
+
+	upToDate := NewChecker(updater, checker.fs, checker.path, "0.7.0")
+	upToDate.now = checker.now
+	_, newer, err = upToDate.Check(t.Context())
+	require.NoError(t, err)
+	require.False(t, newer)
+}
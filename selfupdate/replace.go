@@ -0,0 +1,78 @@
+package selfupdate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Replace atomically replaces the binary at exePath, keeping its permissions.
+// The new binary is written next to the old one first, so a failed update never leaves a broken binary behind.
+// Windows can't replace a running executable, there it is moved aside to exePath.old, which is removed on the next update.
+func Replace(exePath string, binary []byte) error {
+	info, err := os.Stat(exePath)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(exePath)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(exePath)+".new-*")
+	if err != nil {
+		return fmt.Errorf("could not create new binary in %s: %w", dir, err)
+	}
+
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exePath + ".old"
+		_ = os.Remove(old)
+
+		if err := os.Rename(exePath, old); err != nil {
+			return fmt.Errorf("could not move running binary aside: %w", err)
+		}
+
+		if err := os.Rename(tmpPath, exePath); err != nil {
+			_ = os.Rename(old, exePath)
+			return err
+		}
+
+		return nil
+	}
+
+	return os.Rename(tmpPath, exePath)
+}
+
+// PackageManager returns the package manager which likely installed the binary at exePath, empty if none.
+// Binaries of package managers must be updated through them, replacing them causes conflicts.
+func PackageManager(exePath string) string {
+	p := filepath.ToSlash(exePath)
+
+	switch {
+	case strings.Contains(p, "/Cellar/") || strings.HasPrefix(p, "/opt/homebrew/") || strings.HasPrefix(p, "/home/linuxbrew/"):
+		return "Homebrew"
+	case strings.HasPrefix(p, "/nix/store/"):
+		return "Nix"
+	case strings.HasPrefix(p, "/usr/bin/") || strings.HasPrefix(p, "/usr/sbin/") || strings.HasPrefix(p, "/bin/"):
+		return "the system package manager"
+	case strings.Contains(p, "/scoop/apps/"):
+		return "Scoop"
+	}
+
+	return ""
+}
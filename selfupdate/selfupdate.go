@@ -0,0 +1,314 @@
+// Package selfupdate replaces the running binary with the latest Chatuino release from GitHub.
+// Release archives are verified with the SHA-256 checksums published with every release.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	apiURL     = "https://api.github.com"
+	repository = "julez-dev/chatuino"
+
+	// maxDownloadSize limits downloaded archives, releases are about 20 MiB
+	maxDownloadSize = 256 * 1024 * 1024
+)
+
+var ErrChecksumMismatch = errors.New("checksum of the downloaded archive does not match the release")
+
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Version returns the version of the release without the leading v, like Chatuino's version
+func (r Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+func (r Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+
+	return Asset{}, false
+}
+
+type Updater struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+}
+
+func New(client *http.Client, version string) *Updater {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Updater{
+		client:    client,
+		baseURL:   apiURL,
+		userAgent: "chatuino/" + version,
+	}
+}
+
+// Latest returns the latest release, pre-releases and drafts are never returned by GitHub
+func (u *Updater) Latest(ctx context.Context) (Release, error) {
+	body, err := u.get(ctx, u.baseURL+"/repos/"+repository+"/releases/latest")
+	if err != nil {
+		return Release{}, fmt.Errorf("could not fetch latest release: %w", err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return Release{}, fmt.Errorf("could not decode latest release: %w", err)
+	}
+
+	return release, nil
+}
+
+// Download downloads the archive of the release for the platform, verifies its checksum and returns the contained binary
+func (u *Updater) Download(ctx context.Context, release Release, goos, goarch string) ([]byte, error) {
+	archiveName, err := ArchiveName(goos, goarch)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, ok := release.asset(archiveName)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no archive %s", release.Tag, archiveName)
+	}
+
+	checksumsName := "chatuino_" + release.Version() + "_checksums.txt"
+	checksums, ok := release.asset(checksumsName)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no checksums file %s", release.Tag, checksumsName)
+	}
+
+	checksumList, err := u.get(ctx, checksums.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not download checksums: %w", err)
+	}
+
+	want, err := findChecksum(checksumList, archiveName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := u.get(ctx, archive.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not download %s: %w", archiveName, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("%w: %s has %s, expected %s", ErrChecksumMismatch, archiveName, got, want)
+	}
+
+	return extractBinary(data, goos)
+}
+
+func (u *Updater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", u.userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json, application/octet-stream")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > maxDownloadSize {
+		return nil, fmt.Errorf("response is larger than %d bytes", maxDownloadSize)
+	}
+
+	return body, nil
+}
+
+// ArchiveName returns the name of the release archive for the platform, matching the release configuration and the install script
+func ArchiveName(goos, goarch string) (string, error) {
+	var osName string
+	switch goos {
+	case "linux":
+		osName = "Linux"
+	case "darwin":
+		osName = "Darwin"
+	case "windows":
+		osName = "Windows"
+	default:
+		return "", fmt.Errorf("no releases are published for %s", goos)
+	}
+
+	var arch string
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "arm64":
+		arch = "arm64"
+	default:
+		return "", fmt.Errorf("no releases are published for %s", goarch)
+	}
+
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+
+	return "chatuino_" + osName + "_" + arch + ext, nil
+}
+
+// findChecksum returns the SHA-256 checksum of name from a checksums file in the format of sha256sum
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no checksum published for %s", name)
+}
+
+// extractBinary returns the chatuino binary from a tar.gz archive, or a zip archive on Windows
+func extractBinary(archive []byte, goos string) ([]byte, error) {
+	if goos == "windows" {
+		return extractZipBinary(archive, "chatuino.exe")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive: %w", err)
+	}
+
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("archive contains no chatuino binary")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read archive: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == "chatuino" {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+		}
+	}
+}
+
+func extractZipBinary(archive []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if path.Base(f.Name) != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		defer rc.Close()
+
+		return io.ReadAll(io.LimitReader(rc, maxDownloadSize))
+	}
+
+	return nil, errors.New("archive contains no chatuino binary")
+}
+
+// IsNewer reports whether version latest is newer than current, both like 0.6.2 with an optional v prefix.
+// A release is newer than a pre-release of the same version. Versions which can't be parsed, like dev builds, are never older.
+func IsNewer(current, latest string) bool {
+	cur, curPre, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+
+	lat, latPre, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+
+	for i := range cur {
+		if lat[i] != cur[i] {
+			return lat[i] > cur[i]
+		}
+	}
+
+	return curPre && !latPre
+}
+
+// parseVersion parses major, minor and patch and reports whether the version is a pre-release
+func parseVersion(version string) ([3]int, bool, bool) {
+	var parts [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	version, pre, isPre := strings.Cut(version, "-")
+
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return parts, false, false
+	}
+
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false, false
+		}
+
+		parts[i] = n
+	}
+
+	return parts, isPre && pre != "", true
+}
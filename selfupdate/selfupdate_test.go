@@ -0,0 +1,173 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "README.md", Mode: 0o644, Size: 2, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("hi"))
+	require.NoError(t, err)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func releaseServer(t *testing.T, archive []byte, checksum string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/repos/julez-dev/chatuino/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "chatuino/0.6.0", r.Header.Get("User-Agent"))
+
+		_ = json.NewEncoder(w).Encode(Release{
+			Tag: "v0.7.0",
+			Assets: []Asset{
+				{Name: "chatuino_Linux_x86_64.tar.gz", DownloadURL: srv.URL + "/archive"},
+				{Name: "chatuino_0.7.0_checksums.txt", DownloadURL: srv.URL + "/checksums"},
+			},
+		})
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(archive)
+	})
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("0000  chatuino_Darwin_arm64.tar.gz\n" + checksum + "  chatuino_Linux_x86_64.tar.gz\n"))
+	})
+
+	return srv
+}
+
+func TestUpdater_Download(t *testing.T) {
+	t.Parallel()
+
+	archive := tarGz(t, "chatuino", []byte("new binary"))
+	sum := sha256.Sum256(archive)
+
+	t.Run("verified", func(t *testing.T) {
+		t.Parallel()
+
+		srv := releaseServer(t, archive, hex.EncodeToString(sum[:]))
+		updater := New(srv.Client(), "0.6.0")
+		updater.baseURL = srv.URL
+
+		release, err := updater.Latest(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "0.7.0", release.Version())
+
+		binary, err := updater.Download(t.Context(), release, "linux", "amd64")
+		require.NoError(t, err)
+		require.Equal(t, []byte("new binary"), binary)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		srv := releaseServer(t, archive, hex.EncodeToString(make([]byte, sha256.Size)))
+		updater := New(srv.Client(), "0.6.0")
+		updater.baseURL = srv.URL
+
+		release, err := updater.Latest(t.Context())
+		require.NoError(t, err)
+
+		_, err = updater.Download(t.Context(), release, "linux", "amd64")
+		require.ErrorIs(t, err, ErrChecksumMismatch)
+	})
+
+	t.Run("no archive for platform", func(t *testing.T) {
+		t.Parallel()
+
+		srv := releaseServer(t, archive, hex.EncodeToString(sum[:]))
+		updater := New(srv.Client(), "0.6.0")
+		updater.baseURL = srv.URL
+
+		release, err := updater.Latest(t.Context())
+		require.NoError(t, err)
+
+		_, err = updater.Download(t.Context(), release, "darwin", "amd64")
+		require.ErrorContains(t, err, "has no archive chatuino_Darwin_x86_64.tar.gz")
+	})
+}
+
+func TestIsNewer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		current string
+		latest  string
+		want    bool
+	}{
+		{current: "0.6.2", latest: "v0.7.0", want: true},
+		{current: "0.6.2", latest: "0.6.10", want: true},
+		{current: "1.0.0", latest: "0.9.9", want: false},
+		{current: "0.7.0", latest: "v0.7.0", want: false},
+		{current: "0.7.0-rc1", latest: "0.7.0", want: true},
+		{current: "0.7.0", latest: "0.7.1-rc1", want: true},
+		{current: "dev", latest: "0.7.0", want: false},
+		{current: "0.7.0", latest: "latest", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+" "+tt.latest, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, IsNewer(tt.current, tt.latest))
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	exePath := filepath.Join(t.TempDir(), "chatuino")
+	require.NoError(t, os.WriteFile(exePath, []byte("old binary"), 0o755))
+
+	require.NoError(t, Replace(exePath, []byte("new binary")))
+
+	data, err := os.ReadFile(exePath)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new binary"), data)
+
+	info, err := os.Stat(exePath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(exePath))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary binary must be removed")
+}
+
+func TestPackageManager(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Homebrew", PackageManager("/opt/homebrew/Cellar/chatuino/0.6.0/bin/chatuino"))
+	require.Equal(t, "the system package manager", PackageManager("/usr/bin/chatuino"))
+	require.Empty(t, PackageManager("/home/user/.local/bin/chatuino"))
+	require.Empty(t, PackageManager("/usr/local/bin/chatuino"))
+}
@@ -17,7 +17,8 @@ import (
 var scopes = [...]string{
 	"chat:read", "chat:edit", "channel:moderate", "moderator:read:chat_settings", "moderation:read", "user:read:chat", "moderator:manage:banned_users",
 	"moderator:manage:unban_requests", "user:read:follows", "channel:manage:polls", "channel:read:ads", "moderator:read:followers", "clips:edit", "moderator:manage:announcements",
-	"channel:manage:broadcast", "user:read:emotes", "moderator:manage:chat_messages", "user:write:chat",
+	"channel:manage:broadcast", "user:read:emotes", "moderator:manage:chat_messages", "user:write:chat", "moderator:manage:chat_settings",
+	"user:read:blocked_users", "user:manage:blocked_users", "channel:manage:redemptions", "channel:manage:predictions",
 }
 
 type tokenPair struct {
@@ -0,0 +1,83 @@
+// Package shortlink shortens links with a URL shortener which answers a GET request with the short link as plain text,
+// like https://is.gd/create.php?format=simple&url={url}.
+package shortlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Placeholder is replaced with the query escaped link in the URL of the shortener
+const Placeholder = "{url}"
+
+type Shortener struct {
+	client   *http.Client
+	template string
+}
+
+// New returns a shortener requesting the template URL, which has to contain Placeholder
+func New(client *http.Client, template string) (*Shortener, error) {
+	if err := Validate(template); err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Shortener{client: client, template: template}, nil
+}
+
+// Validate checks the template URL of a shortener
+func Validate(template string) error {
+	if !strings.Contains(template, Placeholder) {
+		return fmt.Errorf("shortener URL must contain %s", Placeholder)
+	}
+
+	u, err := url.Parse(strings.ReplaceAll(template, Placeholder, "x"))
+	if err != nil {
+		return fmt.Errorf("invalid shortener URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return errors.New("shortener URL must be an http or https URL")
+	}
+
+	return nil
+}
+
+// Shorten returns the short link of link
+func (s *Shortener) Shorten(ctx context.Context, link string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(s.template, Placeholder, url.QueryEscape(link)), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("shortener responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	short := strings.TrimSpace(string(body))
+	if !strings.HasPrefix(short, "http://") && !strings.HasPrefix(short, "https://") {
+		return "", fmt.Errorf("shortener returned no link: %q", short)
+	}
+
+	return short, nil
+}
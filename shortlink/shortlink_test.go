@@ -0,0 +1,68 @@
+package shortlink
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		template string
+		wantErr  bool
+	}{
+		"is.gd":          {template: "https://is.gd/create.php?format=simple&url={url}"},
+		"no placeholder": {template: "https://is.gd/create.php?format=simple", wantErr: true},
+		"no scheme":      {template: "is.gd/create.php?url={url}", wantErr: true},
+		"ftp":            {template: "ftp://example.com/{url}", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate(tt.template)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestShortener_Shorten(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("url") {
+		case "https://example.com/a?b=c&d=e":
+			_, _ = io.WriteString(w, "https://is.gd/abc\n")
+		case "https://example.com/error":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, "Error: invalid URL")
+		default:
+			_, _ = io.WriteString(w, "Error")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := New(srv.Client(), srv.URL+"/create.php?format=simple&url={url}")
+	require.NoError(t, err)
+
+	short, err := s.Shorten(t.Context(), "https://example.com/a?b=c&d=e")
+	require.NoError(t, err)
+	require.Equal(t, "https://is.gd/abc", short)
+
+	_, err = s.Shorten(t.Context(), "https://example.com/error")
+	require.ErrorContains(t, err, "invalid URL")
+
+	_, err = s.Shorten(t.Context(), "https://example.com/other")
+	require.ErrorContains(t, err, "no link")
+}
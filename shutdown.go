@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/ui/mainui"
+	"github.com/rs/zerolog/log"
+)
+
+// sgrReset resets colors and text attributes, so a crash in the middle of a styled line doesn't color the shell
+const sgrReset = "\x1b[0m"
+
+// terminalResetSequence disables everything the UI enables: the hidden cursor, mouse and focus reporting,
+// bracketed paste and the alternate screen
+const terminalResetSequence = sgrReset + "\x1b[?25h\x1b[?1000l\x1b[?1002l\x1b[?1003l\x1b[?1006l\x1b[?1004l\x1b[?2004l\x1b[?1049l"
+
+// terminalGuard cleans up the terminal when Chatuino exits and writes panic reports. Bubble Tea restores the terminal after the UI quits,
+// is killed by a signal or panics, but a panic in any other goroutine ends the process in raw mode on the alternate screen with images still placed.
+type terminalGuard struct {
+	out          io.Writer
+	fd           uintptr
+	state        *term.State   // nil if the input is no terminal
+	imageCleanup func() string // command deleting all placed images, empty without graphics
+	settings     save.Settings // summarized in panic reports
+
+	running atomic.Bool // the UI owns the terminal, only then its modes are reset after a panic
+	once    sync.Once
+
+	m          sync.Mutex
+	panicValue any // first panic of the UI recovered by Bubble Tea, nil if none happened
+	panicStack []byte
+}
+
+func newTerminalGuard(out io.Writer, fd uintptr, settings save.Settings, imageCleanup func() string) *terminalGuard {
+	state, err := term.GetState(fd)
+	if err != nil {
+		log.Logger.Debug().Err(err).Msg("input is no terminal, terminal state is not restored")
+	}
+
+	return &terminalGuard{
+		out:          out,
+		fd:           fd,
+		state:        state,
+		imageCleanup: imageCleanup,
+		settings:     settings,
+	}
+}
+
+// run runs the UI, marking the terminal as owned by it
+func (g *terminalGuard) run(p *tea.Program) error {
+	g.running.Store(true)
+	defer g.running.Store(false)
+
+	_, err := p.Run()
+	return err
+}
+
+// restore deletes the placed images after Bubble Tea restored the terminal, called once on every exit
+func (g *terminalGuard) restore() {
+	g.cleanup(false)
+}
+
+// recordPanic keeps the first panic of the UI, its report is written once Bubble Tea recovered from it and restored the terminal
+func (g *terminalGuard) recordPanic(value any, stack []byte) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if g.panicValue == nil {
+		g.panicValue, g.panicStack = value, stack
+	}
+}
+
+// reportUIPanic writes the report of a panic Bubble Tea recovered from and adds its path to the error returned by Run.
+// Panics in commands of the UI are recovered by Bubble Tea without passing through Root, their stack is missing in the report.
+func (g *terminalGuard) reportUIPanic(runErr error) error {
+	g.m.Lock()
+	value, stack := g.panicValue, g.panicStack
+	g.m.Unlock()
+
+	if value == nil {
+		value = "panic in a background command of the UI"
+	}
+
+	path, err := writePanicReport(value, stack, g.settings)
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to write panic report")
+	}
+
+	if path == "" {
+		return runErr
+	}
+
+	return fmt.Errorf("%w, a report was written to %s", runErr, path)
+}
+
+// handlePanic restores the terminal and writes a panic report before a panic crashes Chatuino,
+// deferred in main and at the start of every goroutine started by main
+func (g *terminalGuard) handlePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	log.Logger.Error().Msgf("panic: %v", r)
+
+	g.cleanup(g.running.Load())
+
+	path, err := writePanicReport(r, stack, g.settings)
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to write panic report")
+	}
+
+	closeLogFile()
+
+	// without a report the panic continues, so its stack is printed at least
+	if path == "" {
+		panic(r)
+	}
+
+	fmt.Fprintf(os.Stderr, "Chatuino crashed: %v\nA report was written to %s\n", r, path)
+	os.Exit(2)
+}
+
+func (g *terminalGuard) cleanup(reset bool) {
+	g.once.Do(func() {
+		// images are deleted first, while they are still on the alternate screen
+		if cmd := g.imageCleanup(); cmd != "" {
+			_, _ = io.WriteString(g.out, cmd)
+		}
+
+		if !reset {
+			_, _ = io.WriteString(g.out, sgrReset)
+			return
+		}
+
+		_, _ = io.WriteString(g.out, terminalResetSequence)
+
+		if g.state != nil {
+			if err := term.Restore(g.fd, g.state); err != nil {
+				log.Logger.Err(err).Msg("failed to restore terminal state")
+			}
+		}
+	})
+}
+
+// saveSession persists the open tabs. After a panic in the UI the model may be broken, a panic while taking the snapshot only skips saving.
+func saveSession(ui *mainui.Root, appStateManager *save.AppStateManager) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to take state snapshot: %v", r)
+		}
+	}()
+
+	// persist open tabs on disk when session was actually loaded
+	// to prevent saving empty state when Chatuino was closed while loading
+	if !ui.HasSessionLoaded() {
+		return nil
+	}
+
+	return appStateManager.SaveAppState(ui.TakeStateSnapshot())
+}
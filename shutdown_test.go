@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/ui/mainui"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_terminalGuard_cleanup(t *testing.T) {
+	t.Parallel()
+
+	const deleteImages = "\x1b_Ga=d\x1b\\"
+
+	tests := []struct {
+		name         string
+		imageCleanup string
+		reset        bool
+		want         string
+	}{
+		{name: "quit", want: sgrReset},
+		{name: "quit with images", imageCleanup: deleteImages, want: deleteImages + sgrReset},
+		{name: "panic while running", reset: true, want: terminalResetSequence},
+		{name: "panic while running with images", imageCleanup: deleteImages, reset: true, want: deleteImages + terminalResetSequence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			g := &terminalGuard{
+				out:          &out,
+				imageCleanup: func() string { return tt.imageCleanup },
+			}
+
+			g.cleanup(tt.reset)
+			require.Equal(t, tt.want, out.String())
+
+			// restore runs on every exit, after a panic was handled nothing is written again
+			g.restore()
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_terminalGuard_recordPanic(t *testing.T) {
+	t.Parallel()
+
+	g := &terminalGuard{}
+	g.recordPanic("first", []byte("stack of first"))
+	g.recordPanic("second", []byte("stack of second"))
+
+	require.Equal(t, "first", g.panicValue)
+	require.Equal(t, []byte("stack of first"), g.panicStack)
+}
+
+func Test_saveSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ui      *mainui.Root
+		wantErr string
+	}{
+		{name: "session not loaded yet", ui: &mainui.Root{}},
+		{name: "broken model", ui: nil, wantErr: "failed to take state snapshot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+
+			err := saveSession(tt.ui, save.NewAppStateManager(fs))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// nothing is saved, so the last complete session is kept
+			files, err := afero.ReadDir(fs, "/")
+			require.NoError(t, err)
+			require.Empty(t, files)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Package sound plays audio files with an external player when chat events match the sound rules of the settings.
+package sound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/julez-dev/chatuino/hook"
+	"github.com/julez-dev/chatuino/hook/filter"
+	"github.com/julez-dev/chatuino/internal/cmdout"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// FilePlaceholder is replaced with the audio file in the player command
+	FilePlaceholder = "{file}"
+	// playTimeout stops players which don't exit after playing the file
+	playTimeout = time.Second * 30
+)
+
+type compiledRule struct {
+	save.SoundRule
+	filter *filter.Filter
+}
+
+// Player plays the sound of the first rule matching an event. Only one sound is played at a time,
+// events while a sound plays or during the cooldown after it are skipped.
+type Player struct {
+	logger zerolog.Logger
+	muted  atomic.Bool
+	now    func() time.Time
+	run    func(ctx context.Context, args []string) error
+
+	m        sync.Mutex
+	settings save.SoundSettings
+	rules    []compiledRule
+	playing  bool
+	last     time.Time
+}
+
+func New(logger zerolog.Logger, settings save.SoundSettings) (*Player, error) {
+	p := &Player{
+		logger: logger.With().Str("component", "sound").Logger(),
+		now:    time.Now,
+		run:    run,
+	}
+
+	if err := p.SetSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// SetSettings replaces the settings, used when the settings are reloaded. The settings are kept on error.
+func (p *Player) SetSettings(settings save.SoundSettings) error {
+	compiled := make([]compiledRule, 0, len(settings.Rules))
+
+	for i, r := range settings.Rules {
+		f, err := filter.Parse(r.Filter, save.HookFilterFields)
+		if err != nil {
+			return fmt.Errorf("invalid filter of sound rule %d: %w", i+1, err)
+		}
+
+		compiled = append(compiled, compiledRule{SoundRule: r, filter: f})
+	}
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.settings = settings
+	p.rules = compiled
+
+	return nil
+}
+
+// ToggleMuted mutes or unmutes all sounds, it reports if sounds are muted afterwards
+func (p *Player) ToggleMuted() bool {
+	for {
+		muted := p.muted.Load()
+		if p.muted.CompareAndSwap(muted, !muted) {
+			return !muted
+		}
+	}
+}
+
+// Play plays the sound of the first rule matching one of the events in the background
+func (p *Player) Play(events ...hook.Event) {
+	if p.muted.Load() {
+		return
+	}
+
+	args, ok := p.next(events)
+	if !ok {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), playTimeout)
+		defer cancel()
+
+		if err := p.run(ctx, args); err != nil {
+			p.logger.Err(err).Strs("command", args).Msg("failed to play sound")
+		}
+
+		p.m.Lock()
+		defer p.m.Unlock()
+
+		p.playing = false
+		p.last = p.now()
+	}()
+}
+
+// next returns the player command for the sound of the first matching rule and marks the player as playing
+func (p *Player) next(events []hook.Event) ([]string, bool) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if !p.settings.Enabled || p.playing || p.now().Sub(p.last) < p.settings.Cooldown {
+		return nil, false
+	}
+
+	for _, r := range p.rules {
+		for _, event := range events {
+			if r.Event != event.Type || !r.filter.Match(event.Fields()) {
+				continue
+			}
+
+			file := r.File
+			if file == "" {
+				file = p.settings.File
+			}
+
+			p.playing = true
+			return playerCommand(p.settings.Player, expandHome(file)), true
+		}
+	}
+
+	return nil, false
+}
+
+// playerCommand splits the player template by whitespace and replaces FilePlaceholder with the file.
+// The file is appended if the template doesn't contain the placeholder.
+func playerCommand(template, file string) []string {
+	if strings.TrimSpace(template) == "" {
+		template = defaultPlayer()
+	}
+
+	fields := strings.Fields(template)
+	replaced := false
+
+	for i, f := range fields {
+		if strings.Contains(f, FilePlaceholder) {
+			fields[i] = strings.ReplaceAll(f, FilePlaceholder, file)
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		fields = append(fields, file)
+	}
+
+	return fields
+}
+
+func defaultPlayer() string {
+	if runtime.GOOS == "darwin" {
+		return "afplay " + FilePlaceholder
+	}
+
+	return "paplay " + FilePlaceholder
+}
+
+// expandHome replaces a leading ~ with the home directory of the user
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, rest)
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no player configured")
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	// don't wait for child processes keeping the output open after the player was killed
+	cmd.WaitDelay = time.Second
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if line := cmdout.LastLine(out); line != "" {
+			return fmt.Errorf("%w: %s", err, line)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package sound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/hook"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayer_Play(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	played := make(chan []string, 1)
+
+	p, err := New(zerolog.Nop(), save.SoundSettings{
+		Enabled:  true,
+		Player:   "mpv --really-quiet {file}",
+		File:     "/sounds/ping.wav",
+		Cooldown: time.Second * 2,
+		Rules: []save.SoundRule{
+			{Event: save.HookEventMessage, Filter: `message contains "giveaway"`, File: "/sounds/alert.wav"},
+			{Event: save.HookEventMention},
+			{Event: save.HookEventRaid, Filter: "viewers >= 10"},
+		},
+	})
+	require.NoError(t, err)
+
+	p.now = func() time.Time { return now }
+	p.run = func(_ context.Context, args []string) error {
+		played <- args
+		return nil
+	}
+
+	// wait returns the command of the played sound and advances the time
+	wait := func(advance time.Duration) []string {
+		args := <-played
+		require.Eventually(t, func() bool {
+			p.m.Lock()
+			defer p.m.Unlock()
+			return !p.playing
+		}, time.Second, time.Millisecond)
+		now = now.Add(advance)
+		return args
+	}
+
+	// the first matching rule plays its own file
+	p.Play(
+		hook.Event{Type: save.HookEventMessage, Message: "hey @julezdev giveaway?"},
+		hook.Event{Type: save.HookEventMention, Message: "hey @julezdev giveaway?"},
+	)
+	require.Equal(t, []string{"mpv", "--really-quiet", "/sounds/alert.wav"}, wait(time.Second))
+
+	// events during the cooldown are skipped
+	p.Play(hook.Event{Type: save.HookEventMention})
+	now = now.Add(time.Second)
+
+	p.Play(hook.Event{Type: save.HookEventMessage, Message: "hello"})
+	p.Play(hook.Event{Type: save.HookEventRaid, Viewers: 5})
+	p.Play(hook.Event{Type: save.HookEventMention})
+	require.Equal(t, []string{"mpv", "--really-quiet", "/sounds/ping.wav"}, wait(time.Minute))
+
+	require.True(t, p.ToggleMuted())
+	p.Play(hook.Event{Type: save.HookEventRaid, Viewers: 50})
+	require.False(t, p.ToggleMuted())
+
+	p.Play(hook.Event{Type: save.HookEventRaid, Viewers: 50})
+	require.Equal(t, []string{"mpv", "--really-quiet", "/sounds/ping.wav"}, wait(time.Minute))
+	require.Empty(t, played, "muted events are not played later")
+
+	// invalid settings keep the previous settings
+	require.Error(t, p.SetSettings(save.SoundSettings{Enabled: true, Rules: []save.SoundRule{{Event: save.HookEventRaid, Filter: "unknown"}}}))
+
+	require.NoError(t, p.SetSettings(save.SoundSettings{Rules: []save.SoundRule{{Event: save.HookEventRaid}}}))
+	p.Play(hook.Event{Type: save.HookEventRaid})
+	require.Empty(t, played, "disabled sounds are not played")
+}
+
+func Test_playerCommand(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"paplay", "/a.wav"}, playerCommand("paplay", "/a.wav"))
+	require.Equal(t, []string{"ffplay", "-nodisp", "-i", "/a.wav", "-autoexit"}, playerCommand("ffplay -nodisp -i {file} -autoexit", "/a.wav"))
+	require.Len(t, playerCommand("", "/a.wav"), 2)
+}
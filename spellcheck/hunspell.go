@@ -0,0 +1,338 @@
+package spellcheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// affixRule is a single PFX or SFX line of a hunspell affix file
+type affixRule struct {
+	strip     string
+	add       string
+	condition []charClass
+}
+
+// affix is a prefix or suffix class, referenced by its flag in the dictionary
+type affix struct {
+	prefix       bool
+	crossProduct bool // may be combined with affixes of the other kind
+	rules        []affixRule
+}
+
+// charClass is one character of an affix condition: ., a literal character, [abc] or [^abc]
+type charClass struct {
+	any    bool
+	negate bool
+	chars  string
+}
+
+func (c charClass) matches(r rune) bool {
+	if c.any {
+		return true
+	}
+
+	return strings.ContainsRune(c.chars, r) != c.negate
+}
+
+// affixFile contains the parts of a hunspell .aff file needed to expand the words of the dictionary
+type affixFile struct {
+	encoding string // SET, UTF-8 or ISO8859-1
+	flagType string // FLAG, empty for single characters, long, num or UTF-8
+	affixes  map[string]*affix
+}
+
+func parseAffixFile(r io.Reader) (*affixFile, error) {
+	aff := &affixFile{
+		encoding: "UTF-8",
+		affixes:  map[string]*affix{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// lines are decoded once SET is known, which is always declared before any affix
+	for scanner.Scan() {
+		line, err := aff.decode(scanner.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "SET":
+			aff.encoding = strings.ToUpper(fields[1])
+			if aff.encoding != "UTF-8" && aff.encoding != "ISO8859-1" {
+				return nil, fmt.Errorf("unsupported dictionary encoding %s, only UTF-8 and ISO8859-1 are supported", fields[1])
+			}
+		case "FLAG":
+			aff.flagType = fields[1]
+		case "PFX", "SFX":
+			if err := aff.parseAffixLine(fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return aff, scanner.Err()
+}
+
+// parseAffixLine handles the header "SFX flag Y count" and the rules "SFX flag strip add condition" of an affix class
+func (aff *affixFile) parseAffixLine(fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("invalid affix line %q", strings.Join(fields, " "))
+	}
+
+	flag := fields[1]
+
+	a, ok := aff.affixes[flag]
+	if !ok {
+		aff.affixes[flag] = &affix{
+			prefix:       fields[0] == "PFX",
+			crossProduct: fields[2] == "Y",
+		}
+
+		return nil
+	}
+
+	rule := affixRule{
+		strip: fields[2],
+		add:   fields[3],
+	}
+
+	if rule.strip == "0" {
+		rule.strip = ""
+	}
+
+	// continuation classes of twofold affixes are ignored
+	rule.add, _, _ = strings.Cut(rule.add, "/")
+	if rule.add == "0" {
+		rule.add = ""
+	}
+
+	if len(fields) > 4 {
+		condition, err := parseCondition(fields[4])
+		if err != nil {
+			return err
+		}
+
+		rule.condition = condition
+	}
+
+	a.rules = append(a.rules, rule)
+
+	return nil
+}
+
+func parseCondition(s string) ([]charClass, error) {
+	if s == "." {
+		return nil, nil
+	}
+
+	var condition []charClass
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			condition = append(condition, charClass{any: true})
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, fmt.Errorf("unclosed bracket in affix condition %q", s)
+			}
+
+			class := charClass{chars: string(runes[i+1 : end])}
+			if strings.HasPrefix(class.chars, "^") {
+				class.negate = true
+				class.chars = class.chars[1:]
+			}
+
+			condition = append(condition, class)
+			i = end
+		default:
+			condition = append(condition, charClass{chars: string(runes[i])})
+		}
+	}
+
+	return condition, nil
+}
+
+func (aff *affixFile) decode(line []byte) (string, error) {
+	if aff.encoding == "UTF-8" {
+		if !utf8.Valid(line) {
+			return "", fmt.Errorf("invalid UTF-8 in dictionary line %q", line)
+		}
+
+		return string(line), nil
+	}
+
+	// ISO8859-1 maps every byte to the code point of the same value
+	runes := make([]rune, len(line))
+	for i, b := range line {
+		runes[i] = rune(b)
+	}
+
+	return string(runes), nil
+}
+
+// splitFlags splits the flags of a dictionary word according to the FLAG type
+func (aff *affixFile) splitFlags(flags string) []string {
+	switch aff.flagType {
+	case "long":
+		var split []string
+		for i := 0; i+1 < len(flags); i += 2 {
+			split = append(split, flags[i:i+2])
+		}
+
+		return split
+	case "num":
+		var split []string
+		for f := range strings.SplitSeq(flags, ",") {
+			if _, err := strconv.Atoi(f); err == nil {
+				split = append(split, f)
+			}
+		}
+
+		return split
+	}
+
+	split := make([]string, 0, len(flags))
+	for _, r := range flags {
+		split = append(split, string(r))
+	}
+
+	return split
+}
+
+// expand returns the word with all forms built by its affix flags
+func (aff *affixFile) expand(word string, flags []string) []string {
+	forms := []string{word}
+
+	var prefixes, suffixes []*affix
+	for _, f := range flags {
+		a, ok := aff.affixes[f]
+		if !ok {
+			continue
+		}
+
+		if a.prefix {
+			prefixes = append(prefixes, a)
+		} else {
+			suffixes = append(suffixes, a)
+		}
+	}
+
+	var suffixed []string
+	for _, s := range suffixes {
+		for _, form := range s.apply(word) {
+			forms = append(forms, form)
+
+			if s.crossProduct {
+				suffixed = append(suffixed, form)
+			}
+		}
+	}
+
+	for _, p := range prefixes {
+		forms = append(forms, p.apply(word)...)
+
+		if p.crossProduct {
+			for _, form := range suffixed {
+				forms = append(forms, p.apply(form)...)
+			}
+		}
+	}
+
+	return forms
+}
+
+func (a *affix) apply(word string) []string {
+	var forms []string
+
+	runes := []rune(word)
+	for _, rule := range a.rules {
+		if len(rule.condition) > len(runes) {
+			continue
+		}
+
+		if a.prefix {
+			if !matchesCondition(runes[:len(rule.condition)], rule.condition) || !strings.HasPrefix(word, rule.strip) {
+				continue
+			}
+
+			forms = append(forms, rule.add+word[len(rule.strip):])
+			continue
+		}
+
+		if !matchesCondition(runes[len(runes)-len(rule.condition):], rule.condition) || !strings.HasSuffix(word, rule.strip) {
+			continue
+		}
+
+		forms = append(forms, word[:len(word)-len(rule.strip)]+rule.add)
+	}
+
+	return forms
+}
+
+func matchesCondition(runes []rune, condition []charClass) bool {
+	for i, class := range condition {
+		if !class.matches(runes[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseDictionary calls add with every word of a hunspell .dic file and the forms built by its affixes.
+// Without an affix file, every line is a word.
+func parseDictionary(r io.Reader, aff *affixFile, add func(word string)) error {
+	if aff == nil {
+		aff = &affixFile{encoding: "UTF-8"}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	first := true
+	for scanner.Scan() {
+		line, err := aff.decode(scanner.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// the first line of hunspell dictionaries is the approximate word count
+		if first {
+			first = false
+			if _, err := strconv.Atoi(strings.TrimSpace(line)); err == nil {
+				continue
+			}
+		}
+
+		// morphological fields are separated by whitespace
+		entry, _, _ := strings.Cut(strings.TrimSpace(line), "\t")
+		entry, _, _ = strings.Cut(entry, " ")
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		word, flags, _ := strings.Cut(entry, "/")
+		for _, form := range aff.expand(word, aff.splitFlags(flags)) {
+			add(form)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,321 @@
+// Package spellcheck checks the spelling of chat messages with hunspell dictionaries or plain word lists.
+// Affix rules of hunspell dictionaries are expanded when loading, compound words are not supported.
+// Words added by the user are kept in a custom dictionary file, one word per line.
+package spellcheck
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/spf13/afero"
+)
+
+// DefaultLanguage is used when no language is configured
+const DefaultLanguage = "en_US"
+
+// maxSuggestions is the number of corrections returned by Suggest
+const maxSuggestions = 8
+
+// DictionaryDirs are searched for <language>.dic, in this order
+func DictionaryDirs() []string {
+	dirs := []string{
+		"/usr/share/hunspell",
+		"/usr/share/myspell",
+		"/usr/share/myspell/dicts",
+		"/usr/local/share/hunspell",
+		"/opt/homebrew/share/hunspell",
+		"/Library/Spelling",
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append([]string{filepath.Join(home, ".local", "share", "hunspell"), filepath.Join(home, "Library", "Spelling")}, dirs...)
+	}
+
+	return dirs
+}
+
+// FindDictionary returns the path of the hunspell dictionary of the language, like en_US
+func FindDictionary(fs afero.Fs, dirs []string, language string) (string, error) {
+	for _, dir := range dirs {
+		path := filepath.Join(dir, language+".dic")
+		if _, err := fs.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no hunspell dictionary %s.dic found in %s, install the hunspell dictionary of the language or set spellcheck.dictionary", language, strings.Join(dirs, ", "))
+}
+
+// Checker knows the words of a dictionary and the custom dictionary
+type Checker struct {
+	fs         afero.Fs
+	customPath string
+
+	m        sync.RWMutex
+	words    map[string]struct{} // lower case
+	alphabet []rune              // letters used by the dictionary, used to build corrections
+}
+
+// Load reads the dictionary at path and the custom dictionary at customPath, which doesn't need to exist.
+// The affix file next to a .dic file is used if it exists, other files are read as one word per line.
+func Load(fs afero.Fs, path, customPath string) (*Checker, error) {
+	c := &Checker{
+		fs:         fs,
+		customPath: customPath,
+		words:      map[string]struct{}{},
+	}
+
+	var aff *affixFile
+	if affPath, ok := strings.CutSuffix(path, ".dic"); ok {
+		f, err := fs.Open(affPath + ".aff")
+		switch {
+		case err == nil:
+			aff, err = parseAffixFile(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read affix file: %w", err)
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := parseDictionary(f, aff, c.add); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+
+	custom, err := afero.ReadFile(fs, customPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if err := parseDictionary(bytes.NewReader(custom), nil, c.add); err != nil {
+		return nil, fmt.Errorf("failed to read custom dictionary: %w", err)
+	}
+
+	letters := map[rune]struct{}{}
+	for w := range c.words {
+		for _, r := range w {
+			if unicode.IsLetter(r) {
+				letters[r] = struct{}{}
+			}
+		}
+	}
+
+	for r := range letters {
+		c.alphabet = append(c.alphabet, r)
+	}
+	slices.Sort(c.alphabet)
+
+	return c, nil
+}
+
+func (c *Checker) add(word string) {
+	c.words[strings.ToLower(word)] = struct{}{}
+}
+
+// Check reports whether the word is spelled correctly, case is ignored
+func (c *Checker) Check(word string) bool {
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	_, ok := c.words[strings.ToLower(word)]
+	return ok
+}
+
+// Suggest returns corrections of a misspelled word, words with two changed letters only if none with one exist.
+// The corrections start with an upper case letter if the word does.
+func (c *Checker) Suggest(word string) []string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	lower := strings.ToLower(word)
+
+	found := map[string]struct{}{}
+	var suggestions []string
+
+	collect := func(candidates []string) {
+		var matched []string
+		for _, candidate := range candidates {
+			if _, ok := c.words[candidate]; !ok {
+				continue
+			}
+
+			if _, ok := found[candidate]; ok || candidate == lower {
+				continue
+			}
+
+			found[candidate] = struct{}{}
+			matched = append(matched, candidate)
+		}
+
+		slices.Sort(matched)
+		suggestions = append(suggestions, matched...)
+	}
+
+	first := c.edits(lower)
+	collect(first)
+
+	// words with two changed letters are only suggested if there is no closer word
+	if len(suggestions) == 0 {
+		var second []string
+		for _, e := range first {
+			second = append(second, c.edits(e)...)
+		}
+
+		collect(second)
+	}
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	if r := []rune(word); len(r) > 0 && unicode.IsUpper(r[0]) {
+		for i, s := range suggestions {
+			sr := []rune(s)
+			sr[0] = unicode.ToUpper(sr[0])
+			suggestions[i] = string(sr)
+		}
+	}
+
+	return suggestions
+}
+
+// edits returns all strings with one deleted, swapped, replaced or inserted letter
+func (c *Checker) edits(word string) []string {
+	runes := []rune(word)
+	edits := make([]string, 0, len(runes)*(2*len(c.alphabet)+2)+len(c.alphabet))
+
+	for i := 0; i <= len(runes); i++ {
+		head, tail := runes[:i], runes[i:]
+
+		if len(tail) > 0 {
+			edits = append(edits, string(head)+string(tail[1:]))
+		}
+
+		if len(tail) > 1 {
+			edits = append(edits, string(head)+string(tail[1])+string(tail[0])+string(tail[2:]))
+		}
+
+		for _, r := range c.alphabet {
+			if len(tail) > 0 && tail[0] != r {
+				edits = append(edits, string(head)+string(r)+string(tail[1:]))
+			}
+
+			edits = append(edits, string(head)+string(r)+string(tail))
+		}
+	}
+
+	return edits
+}
+
+// Add adds the word to the custom dictionary, so it is no longer reported as misspelled
+func (c *Checker) Add(word string) error {
+	if word == "" || strings.ContainsFunc(word, unicode.IsSpace) {
+		return fmt.Errorf("%q is not a single word", word)
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if err := c.fs.MkdirAll(filepath.Dir(c.customPath), 0o755); err != nil {
+		return err
+	}
+
+	f, err := c.fs.OpenFile(c.customPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(f, word+"\n"); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	c.add(word)
+
+	return nil
+}
+
+// Word is a word of a message, Start and End are rune indices
+type Word struct {
+	Text       string
+	Start, End int
+}
+
+// Words returns the words of a message which can be spell checked. Punctuation around words is removed.
+// Mentions, commands, links and words with digits or upper case letters after the first letter, like emotes
+// such as KEKW or monkaS, are skipped.
+func Words(text string) []Word {
+	var words []Word
+
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if runes[start] == ' ' {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(runes) && runes[end] != ' ' {
+			end++
+		}
+
+		token := string(runes[start:end])
+		if w, ok := checkableWord(runes[start:end], start); ok && !strings.HasPrefix(token, "@") && !strings.Contains(token, "://") &&
+			!(start == 0 && strings.HasPrefix(token, "/")) {
+			words = append(words, w)
+		}
+
+		start = end
+	}
+
+	return words
+}
+
+func checkableWord(token []rune, offset int) (Word, bool) {
+	if slices.ContainsFunc(token, unicode.IsDigit) {
+		return Word{}, false
+	}
+
+	// punctuation and quotes around the word are not part of it
+	start, end := 0, len(token)
+	for start < end && !unicode.IsLetter(token[start]) {
+		start++
+	}
+
+	for end > start && !unicode.IsLetter(token[end-1]) {
+		end--
+	}
+
+	word := token[start:end]
+	if len(word) < 2 {
+		return Word{}, false
+	}
+
+	for i, r := range word {
+		if !unicode.IsLetter(r) && r != '\'' || i > 0 && unicode.IsUpper(r) {
+			return Word{}, false
+		}
+	}
+
+	return Word{Text: string(word), Start: offset + start, End: offset + end}, true
+}
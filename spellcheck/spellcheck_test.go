@@ -0,0 +1,156 @@
+package spellcheck
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const testAffix = `SET UTF-8
+TRY esianrtolcdugmphbyfvkwzESIANRTOLCDUGMPHBYFVKWZ'
+
+PFX A Y 1
+PFX A   0     re         .
+
+SFX D Y 4
+SFX D   0     d          e
+SFX D   y     ied        [^aeiou]y
+SFX D   0     ed         [^ey]
+SFX D   0     ed         [aeiou]y
+
+SFX S Y 3
+SFX S   y     ies        [^aeiou]y
+SFX S   0     s          [aeiou]y
+SFX S   0     s          [^y]
+`
+
+const testDictionary = `5
+play/DS
+try/DS
+receive/ADS
+hello
+Twitch
+`
+
+func newTestChecker(t *testing.T) (*Checker, afero.Fs) {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/dict/en_US.aff", []byte(testAffix), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/dict/en_US.dic", []byte(testDictionary), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/config/dictionary.txt", []byte("pog\n"), 0o644))
+
+	c, err := Load(fs, "/dict/en_US.dic", "/config/dictionary.txt")
+	require.NoError(t, err)
+
+	return c, fs
+}
+
+func TestChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestChecker(t)
+
+	for _, word := range []string{"play", "played", "plays", "tried", "tries", "received", "receives", "rereceived", "hello", "Hello", "twitch", "pog"} {
+		require.True(t, c.Check(word), word)
+	}
+
+	for _, word := range []string{"plaied", "tryed", "recieve", "rehello", "5"} {
+		require.False(t, c.Check(word), word)
+	}
+}
+
+func TestChecker_Suggest(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestChecker(t)
+
+	require.Equal(t, []string{"receive"}, c.Suggest("recieve"))
+	require.Equal(t, []string{"Hello"}, c.Suggest("Helo"))
+	require.Equal(t, []string{"played", "plays"}, c.Suggest("playes"))
+	require.Empty(t, c.Suggest("xyzxyzxyz"))
+}
+
+func TestChecker_Add(t *testing.T) {
+	t.Parallel()
+
+	c, fs := newTestChecker(t)
+
+	require.False(t, c.Check("kekw"))
+	require.NoError(t, c.Add("kekw"))
+	require.True(t, c.Check("KEKW"))
+	require.Error(t, c.Add("two words"))
+
+	content, err := afero.ReadFile(fs, "/config/dictionary.txt")
+	require.NoError(t, err)
+	require.Equal(t, "pog\nkekw\n", string(content))
+
+	// the custom dictionary is read again on the next start
+	reloaded, err := Load(fs, "/dict/en_US.dic", "/config/dictionary.txt")
+	require.NoError(t, err)
+	require.True(t, reloaded.Check("kekw"))
+}
+
+func TestLoad_wordList(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/words", []byte("hello\nworld\n"), 0o644))
+
+	c, err := Load(fs, "/words", "/missing/dictionary.txt")
+	require.NoError(t, err)
+	require.True(t, c.Check("world"))
+	require.False(t, c.Check("worlds"))
+}
+
+func TestFindDictionary(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/b/de_DE.dic", nil, 0o644))
+
+	path, err := FindDictionary(fs, []string{"/a", "/b"}, "de_DE")
+	require.NoError(t, err)
+	require.Equal(t, "/b/de_DE.dic", path)
+
+	_, err = FindDictionary(fs, []string{"/a", "/b"}, "fr_FR")
+	require.Error(t, err)
+}
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+		want  []Word
+	}{
+		"punctuation": {
+			input: "hello, wrld!",
+			want:  []Word{{Text: "hello", Start: 0, End: 5}, {Text: "wrld", Start: 7, End: 11}},
+		},
+		"skipped": {
+			input: "@someone KEKW monkaS https://example.com abc123 a",
+			want:  nil,
+		},
+		"command": {
+			input: "/me dances",
+			want:  []Word{{Text: "dances", Start: 4, End: 10}},
+		},
+		"apostrophe": {
+			input: "don't 'quote'",
+			want:  []Word{{Text: "don't", Start: 0, End: 5}, {Text: "quote", Start: 7, End: 12}},
+		},
+		"unicode": {
+			input: "schön über",
+			want:  []Word{{Text: "schön", Start: 0, End: 5}, {Text: "über", Start: 6, End: 10}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, Words(tt.input))
+		})
+	}
+}
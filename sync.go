@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julez-dev/chatuino/remotesync"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v3"
+)
+
+// autoSyncTimeout limits the automatic syncs on start and exit, a slow remote must not block Chatuino
+const autoSyncTimeout = 30 * time.Second
+
+var syncCMD = &cli.Command{
+	Name:        "sync",
+	Usage:       "Sync the configuration and notes with the remote of the sync settings",
+	Description: "Pull the changes of the git repository or WebDAV server configured in the sync settings and push the local changes. Files changed on both sides since the last sync are conflicts, they are left alone until --prefer picks a side. API keys and passwords are never synced.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "prefer",
+			Usage: "Resolve conflicts with the local or remote files",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only list the changes",
+		},
+	},
+	Action: func(ctx context.Context, command *cli.Command) error {
+		settings, err := save.SettingsFromDisk()
+		if err != nil {
+			return fmt.Errorf("failed to read settings file: %w\nrun \"chatuino config validate\" for details", err)
+		}
+
+		httpClient, err := newHTTPClient(settings.Proxy, false)
+		if err != nil {
+			return err
+		}
+
+		prefer, err := remotesync.ParsePrefer(command.String("prefer"))
+		if err != nil {
+			return err
+		}
+
+		syncer, err := newSyncer(httpClient, settings.Sync)
+		if err != nil {
+			return err
+		}
+
+		dryRun := command.Bool("dry-run")
+
+		result, err := syncer.Sync(ctx, remotesync.Options{Prefer: prefer, DryRun: dryRun})
+		if err != nil {
+			return err
+		}
+
+		printSyncResult(result, dryRun)
+
+		if len(result.Conflicts) > 0 {
+			return fmt.Errorf("%d files changed locally and on the remote, use --prefer local or --prefer remote to resolve them", len(result.Conflicts))
+		}
+
+		return nil
+	},
+}
+
+// newSyncer returns the syncer of the configured backend, WebDAV requests are made with client
+func newSyncer(client *http.Client, settings save.SyncSettings) (*remotesync.Syncer, error) {
+	var backend remotesync.Backend
+
+	switch settings.Backend {
+	case "":
+		return nil, errors.New("no sync backend configured, set sync.backend and sync.remote in the settings")
+	case remotesync.BackendGit:
+		git, err := remotesync.NewGit(settings.Remote, settings.Branch, appPaths.SyncRepoDir())
+		if err != nil {
+			return nil, err
+		}
+
+		backend = git
+	case remotesync.BackendWebDAV:
+		webDAV, err := remotesync.NewWebDAV(client, settings.Remote, settings.Username, settings.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		backend = webDAV
+	default:
+		return nil, fmt.Errorf("unknown sync backend %q", settings.Backend)
+	}
+
+	return &remotesync.Syncer{
+		Backend:   backend,
+		Local:     save.SyncedFiles{FS: afero.NewOsFs(), Paths: appPaths},
+		FS:        afero.NewOsFs(),
+		StateFile: appPaths.SyncStateFile(),
+	}, nil
+}
+
+// autoSync syncs without resolving conflicts, failures are logged and returned but never stop Chatuino
+func autoSync(ctx context.Context, client *http.Client, settings save.SyncSettings) (remotesync.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, autoSyncTimeout)
+	defer cancel()
+
+	syncer, err := newSyncer(client, settings)
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to sync")
+		return remotesync.Result{}, err
+	}
+
+	result, err := syncer.Sync(ctx, remotesync.Options{})
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to sync")
+		return remotesync.Result{}, err
+	}
+
+	log.Logger.Info().Strs("pulled", result.Pulled).Strs("pushed", result.Pushed).Strs("conflicts", result.Conflicts).Msg("synced with remote")
+
+	return result, nil
+}
+
+func printSyncResult(result remotesync.Result, dryRun bool) {
+	for _, name := range result.Pulled {
+		fmt.Println("  " + cacheTextStyle.Render("↓ ") + configPathStyle.Render(name))
+	}
+
+	for _, name := range result.Pushed {
+		fmt.Println("  " + cacheTextStyle.Render("↑ ") + configPathStyle.Render(name))
+	}
+
+	for _, name := range result.Conflicts {
+		fmt.Println("  " + configErrorStyle.Render("! ") + configPathStyle.Render(name) + configWarningStyle.Render(" changed locally and on the remote"))
+	}
+
+	switch {
+	case dryRun:
+		fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Would pull %d and push %d files", len(result.Pulled), len(result.Pushed))))
+	case len(result.Pulled)+len(result.Pushed) == 0 && len(result.Conflicts) == 0:
+		fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(" Already in sync"))
+	case len(result.Pulled)+len(result.Pushed) == 0:
+		// only conflicts, the returned error explains how to resolve them
+	default:
+		fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Pulled %d and pushed %d files", len(result.Pulled), len(result.Pushed))))
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// defaultProbeTimeout is how long a local terminal may take to answer queries, the ssh settings configure it for SSH sessions
+const defaultProbeTimeout = 5 * time.Second
+
+// isDarkBackground reports if the background color of the terminal's answer to the OSC 11 query is dark, the HSL lightness like lipgloss.
+// An answer without a color counts as dark.
+func isDarkBackground(answer string) bool {
+	_, value, ok := strings.Cut(answer, "\x1b]11;")
+	if !ok {
+		return true
+	}
+
+	if end := strings.IndexAny(value, "\x07\x1b"); end >= 0 {
+		value = value[:end]
+	}
+
+	c := ansi.XParseColor(value)
+	if c == nil {
+		return true
+	}
+
+	r, g, b, _ := c.RGBA()
+	lightness := float64(max(r, g, b)+min(r, g, b)) / 2 / 0xffff
+
+	return lightness < 0.5
+}
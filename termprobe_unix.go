@@ -0,0 +1,74 @@
+//go:build unix || darwin
+
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"golang.org/x/sys/unix"
+)
+
+// queryDarkBackground asks the terminal for its background color and reports if it is dark. lipgloss waits a fixed time for each byte
+// of the answer, this waits up to timeout for the whole answer, which takes longer over SSH. Without an answer a dark background is assumed.
+func queryDarkBackground(timeout time.Duration) bool {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return true
+	}
+
+	defer tty.Close()
+
+	fd := tty.Fd()
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return true
+	}
+
+	defer func() { _ = term.Restore(fd, state) }()
+
+	// every terminal answers the device attributes, terminals which don't know the background query answer only them
+	if _, err := io.WriteString(tty, ansi.RequestBackgroundColor+ansi.RequestPrimaryDeviceAttributes); err != nil {
+		return true
+	}
+
+	var (
+		answer   []byte
+		buf      = make([]byte, 256)
+		deadline = time.Now().Add(timeout)
+	)
+
+	for !strings.Contains(string(answer), "\x1b[?") || answer[len(answer)-1] != 'c' {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return true
+		}
+
+		var readfds unix.FdSet
+		readfds.Set(int(fd))
+		tv := unix.NsecToTimeval(remaining.Nanoseconds())
+
+		n, err := unix.Select(int(fd)+1, &readfds, nil, nil, &tv)
+		if err == unix.EINTR {
+			continue
+		}
+
+		if err != nil || n == 0 {
+			return true
+		}
+
+		n, err = tty.Read(buf)
+		if err != nil {
+			return true
+		}
+
+		answer = append(answer, buf[:n]...)
+	}
+
+	return isDarkBackground(string(answer))
+}
@@ -4,6 +4,9 @@ package main
 
 import (
 	"errors"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 var errUnsupported = errors.New("image support not available for this platform")
@@ -15,3 +18,7 @@ func hasImageSupport() bool {
 func getTermCellWidthHeight() (float32, float32, error) {
 	return 0, 0, errUnsupported
 }
+
+func queryDarkBackground(time.Duration) bool {
+	return lipgloss.HasDarkBackground()
+}
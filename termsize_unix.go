@@ -5,6 +5,8 @@ package main
 import (
 	"os"
 
+	"github.com/julez-dev/chatuino/tmux"
+
 	"golang.org/x/sys/unix"
 )
 
@@ -12,7 +14,14 @@ func hasImageSupport() bool {
 	_, isKitty := os.LookupEnv("KITTY_WINDOW_ID") // always defined by kitty
 	term := os.Getenv("TERM")
 
-	return isKitty || term == "xterm-ghostty"
+	// inside tmux TERM belongs to tmux, the terminal the client is attached from draws the images
+	if client, ok := tmux.FromEnv(os.Getenv); ok {
+		if outer, err := client.OuterTerm(); err == nil {
+			term = outer
+		}
+	}
+
+	return isKitty || term == "xterm-kitty" || term == "xterm-ghostty"
 }
 
 func getTermCellWidthHeight() (float32, float32, error) {
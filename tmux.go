@@ -0,0 +1,38 @@
+package main
+
+import (
+	"sync/atomic"
+
+	"github.com/julez-dev/chatuino/tmux"
+)
+
+// tmuxWindow renames the tmux window for the UI and restores the previous name on exit
+type tmuxWindow struct {
+	client    *tmux.Client
+	name      string
+	automatic bool
+	renamed   atomic.Bool
+}
+
+func newTmuxWindow(client *tmux.Client) (*tmuxWindow, error) {
+	name, automatic, err := client.WindowName()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tmuxWindow{client: client, name: name, automatic: automatic}, nil
+}
+
+func (w *tmuxWindow) RenameWindow(name string) error {
+	w.renamed.Store(true)
+	return w.client.RenameWindow(name)
+}
+
+// restore restores the name of the window before it was renamed, nothing is done if it never was
+func (w *tmuxWindow) restore() error {
+	if !w.renamed.Load() {
+		return nil
+	}
+
+	return w.client.RestoreWindowName(w.name, w.automatic)
+}
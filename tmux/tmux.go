@@ -0,0 +1,136 @@
+// Package tmux talks to the tmux server Chatuino runs in. Graphics escape sequences only reach the terminal running tmux
+// when they are wrapped in passthrough sequences and tmux allows passthrough, the window is renamed to the focused channel.
+package tmux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandTimeout limits each tmux command, a hanging tmux server must not block Chatuino
+const commandTimeout = 2 * time.Second
+
+// ErrPassthroughOff is returned when tmux drops the graphics escape sequences because allow-passthrough is off
+var ErrPassthroughOff = errors.New(`tmux doesn't forward graphics because allow-passthrough is off, add "set -g allow-passthrough on" to ~/.tmux.conf and run "tmux source-file ~/.tmux.conf", or disable chat.graphic_emotes and chat.graphic_badges`)
+
+// Runner runs tmux with the arguments and returns its trimmed output
+type Runner func(args ...string) (string, error)
+
+// Client runs tmux commands for the pane Chatuino runs in
+type Client struct {
+	pane string
+	run  Runner
+}
+
+// FromEnv returns the client of the tmux pane Chatuino runs in, false outside of tmux
+func FromEnv(getenv func(string) string) (*Client, bool) {
+	if getenv("TMUX") == "" {
+		return nil, false
+	}
+
+	return &Client{pane: getenv("TMUX_PANE"), run: runTmux}, true
+}
+
+// NewClient returns a client of the pane running tmux commands with run
+func NewClient(pane string, run Runner) *Client {
+	return &Client{pane: pane, run: run}
+}
+
+// Passthrough reports if tmux forwards wrapped escape sequences to the terminal. tmux before 3.3 has no allow-passthrough option and always forwards them.
+func (c *Client) Passthrough() (bool, error) {
+	value, err := c.display("#{allow-passthrough}")
+	if err != nil {
+		return false, err
+	}
+
+	return value != "off" && value != "0", nil
+}
+
+// OuterTerm returns the TERM of the terminal the tmux client is attached from, inside tmux TERM is the one of tmux
+func (c *Client) OuterTerm() (string, error) {
+	return c.display("#{client_termname}")
+}
+
+// WindowName returns the name of the window and if tmux renames it automatically to the running program
+func (c *Client) WindowName() (string, bool, error) {
+	out, err := c.display("#{automatic-rename} #{window_name}")
+	if err != nil {
+		return "", false, err
+	}
+
+	automatic, name, _ := strings.Cut(out, " ")
+
+	return name, automatic == "1" || automatic == "on", nil
+}
+
+// RenameWindow sets the name of the window, which disables the automatic rename of the window
+func (c *Client) RenameWindow(name string) error {
+	_, err := c.run(c.target("rename-window", name)...)
+	return err
+}
+
+// RestoreWindowName restores the name returned by WindowName
+func (c *Client) RestoreWindowName(name string, automatic bool) error {
+	if automatic {
+		_, err := c.run(c.target("set-option", "-w", "automatic-rename", "on")...)
+		return err
+	}
+
+	return c.RenameWindow(name)
+}
+
+func (c *Client) display(format string) (string, error) {
+	return c.run(c.target("display-message", "-p", format)...)
+}
+
+// target adds the pane to the command, so it applies to the pane of Chatuino and not to the focused one
+func (c *Client) target(command string, args ...string) []string {
+	if c.pane == "" {
+		return append([]string{command}, args...)
+	}
+
+	return append([]string{command, "-t", c.pane}, args...)
+}
+
+func runTmux(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "tmux", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("tmux %s: %s", args[0], msg)
+		}
+
+		return "", fmt.Errorf("tmux %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Wrap wraps each escape sequence terminated by ST in a passthrough sequence, which tmux forwards to the terminal unchanged
+func Wrap(seq string) string {
+	var b strings.Builder
+
+	for part := range strings.SplitAfterSeq(seq, "\x1b\\") {
+		if part == "" {
+			continue
+		}
+
+		b.WriteString("\x1bPtmux;")
+		b.WriteString(strings.ReplaceAll(part, "\x1b", "\x1b\x1b"))
+		b.WriteString("\x1b\\")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package tmux
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "\x1bPtmux;\x1b\x1b_Ga=D\x1b\x1b\\\x1b\\", Wrap("\x1b_Ga=D\x1b\\"))
+	require.Equal(t,
+		"\x1bPtmux;\x1b\x1b_Gi=1\x1b\x1b\\\x1b\\\x1bPtmux;\x1b\x1b_Ga=p\x1b\x1b\\\x1b\\",
+		Wrap("\x1b_Gi=1\x1b\\\x1b_Ga=p\x1b\\"),
+		"each sequence is wrapped on its own",
+	)
+	require.Empty(t, Wrap(""))
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	_, ok := FromEnv(func(string) string { return "" })
+	require.False(t, ok)
+
+	env := map[string]string{"TMUX": "/tmp/tmux-1000/default,1234,0", "TMUX_PANE": "%3"}
+	client, ok := FromEnv(func(key string) string { return env[key] })
+	require.True(t, ok)
+	require.Equal(t, "%3", client.pane)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls   []string
+		answers = map[string]string{
+			"display-message -t %3 -p #{allow-passthrough}":               "0",
+			"display-message -t %3 -p #{client_termname}":                 "xterm-kitty",
+			"display-message -t %3 -p #{automatic-rename} #{window_name}": "1 zsh",
+		}
+	)
+
+	client := NewClient("%3", func(args ...string) (string, error) {
+		call := strings.Join(args, " ")
+		calls = append(calls, call)
+
+		if answer, ok := answers[call]; ok {
+			return answer, nil
+		}
+
+		if args[0] == "display-message" {
+			return "", errors.New("unexpected command")
+		}
+
+		return "", nil
+	})
+
+	passthrough, err := client.Passthrough()
+	require.NoError(t, err)
+	require.False(t, passthrough)
+
+	answers["display-message -t %3 -p #{allow-passthrough}"] = ""
+	passthrough, err = client.Passthrough()
+	require.NoError(t, err)
+	require.True(t, passthrough, "tmux before 3.3 always forwards")
+
+	term, err := client.OuterTerm()
+	require.NoError(t, err)
+	require.Equal(t, "xterm-kitty", term)
+
+	name, automatic, err := client.WindowName()
+	require.NoError(t, err)
+	require.Equal(t, "zsh", name)
+	require.True(t, automatic)
+
+	calls = nil
+	require.NoError(t, client.RenameWindow("● lirik"))
+	require.NoError(t, client.RestoreWindowName(name, automatic))
+	require.NoError(t, client.RestoreWindowName("chat", false))
+	require.Equal(t, []string{
+		"rename-window -t %3 ● lirik",
+		"set-option -t %3 -w automatic-rename on",
+		"rename-window -t %3 chat",
+	}, calls)
+}
@@ -0,0 +1,207 @@
+// Package translate translates chat messages with DeepL or a LibreTranslate instance.
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Backends messages can be translated with
+const (
+	BackendDeepL          = "deepl"
+	BackendLibreTranslate = "libretranslate"
+)
+
+const (
+	deepLURL     = "https://api.deepl.com"
+	deepLFreeURL = "https://api-free.deepl.com" // used for keys of the free plan, which end with :fx
+)
+
+// Config selects the backend, URL is required for LibreTranslate and optional for DeepL
+type Config struct {
+	Backend        string
+	URL            string
+	APIKey         string
+	TargetLanguage string // language code like en or de
+}
+
+// Result is a translated text with the language detected for the original
+type Result struct {
+	Text           string
+	SourceLanguage string // lower case language code, empty if the backend did not detect it
+}
+
+type Translator interface {
+	Translate(ctx context.Context, text string) (Result, error)
+}
+
+// New returns the translator of the configured backend
+func New(client *http.Client, cfg Config) (Translator, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	switch cfg.Backend {
+	case BackendDeepL:
+		if cfg.APIKey == "" {
+			return nil, errors.New("DeepL requires an API key")
+		}
+
+		url := cfg.URL
+		if url == "" {
+			url = deepLURL
+			if strings.HasSuffix(cfg.APIKey, ":fx") {
+				url = deepLFreeURL
+			}
+		}
+
+		return &DeepL{client: client, url: strings.TrimSuffix(url, "/"), key: cfg.APIKey, target: cfg.TargetLanguage}, nil
+	case BackendLibreTranslate:
+		if cfg.URL == "" {
+			return nil, errors.New("LibreTranslate requires the URL of an instance")
+		}
+
+		return &LibreTranslate{client: client, url: strings.TrimSuffix(cfg.URL, "/"), key: cfg.APIKey, target: cfg.TargetLanguage}, nil
+	}
+
+	return nil, fmt.Errorf("unknown translation backend %q", cfg.Backend)
+}
+
+// DeepL uses the DeepL API, https://developers.deepl.com/docs/api-reference/translate
+type DeepL struct {
+	client *http.Client
+	url    string
+	key    string
+	target string
+}
+
+func (d *DeepL) Translate(ctx context.Context, text string) (Result, error) {
+	body := struct {
+		Text       []string `json:"text"`
+		TargetLang string   `json:"target_lang"`
+	}{
+		Text:       []string{text},
+		TargetLang: strings.ToUpper(d.target),
+	}
+
+	var resp struct {
+		Translations []struct {
+			DetectedSourceLanguage string `json:"detected_source_language"`
+			Text                   string `json:"text"`
+		} `json:"translations"`
+	}
+
+	header := http.Header{"Authorization": []string{"DeepL-Auth-Key " + d.key}}
+	if err := doRequest(ctx, d.client, d.url+"/v2/translate", header, body, &resp); err != nil {
+		return Result{}, fmt.Errorf("DeepL: %w", err)
+	}
+
+	if len(resp.Translations) == 0 {
+		return Result{}, errors.New("DeepL returned no translation")
+	}
+
+	return Result{
+		Text:           resp.Translations[0].Text,
+		SourceLanguage: strings.ToLower(resp.Translations[0].DetectedSourceLanguage),
+	}, nil
+}
+
+// LibreTranslate uses a LibreTranslate instance, https://libretranslate.com/docs
+type LibreTranslate struct {
+	client *http.Client
+	url    string
+	key    string // optional, only required by some instances
+	target string
+}
+
+func (l *LibreTranslate) Translate(ctx context.Context, text string) (Result, error) {
+	body := struct {
+		Q      string `json:"q"`
+		Source string `json:"source"`
+		Target string `json:"target"`
+		Format string `json:"format"`
+		APIKey string `json:"api_key,omitempty"`
+	}{
+		Q:      text,
+		Source: "auto",
+		Target: strings.ToLower(l.target),
+		Format: "text",
+		APIKey: l.key,
+	}
+
+	var resp struct {
+		TranslatedText   string `json:"translatedText"`
+		DetectedLanguage struct {
+			Language string `json:"language"`
+		} `json:"detectedLanguage"`
+	}
+
+	if err := doRequest(ctx, l.client, l.url+"/translate", nil, body, &resp); err != nil {
+		return Result{}, fmt.Errorf("LibreTranslate: %w", err)
+	}
+
+	return Result{
+		Text:           resp.TranslatedText,
+		SourceLanguage: strings.ToLower(resp.DetectedLanguage.Language),
+	}, nil
+}
+
+func doRequest(ctx context.Context, client *http.Client, url string, header http.Header, body, data any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, errorMessage(respBody))
+	}
+
+	return json.Unmarshal(respBody, data)
+}
+
+// errorMessage returns the error of a failed request, both backends describe it in a JSON object
+func errorMessage(body []byte) string {
+	var resp struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	if resp.Error != "" {
+		return resp.Error
+	}
+
+	return resp.Message
+}
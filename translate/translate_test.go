@@ -0,0 +1,125 @@
+package translate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		url     string
+		wantErr bool
+	}{
+		"deepl": {
+			cfg: Config{Backend: BackendDeepL, APIKey: "key"},
+			url: deepLURL,
+		},
+		"deepl-free": {
+			cfg: Config{Backend: BackendDeepL, APIKey: "key:fx"},
+			url: deepLFreeURL,
+		},
+		"deepl-without-key": {
+			cfg:     Config{Backend: BackendDeepL},
+			wantErr: true,
+		},
+		"libretranslate-without-url": {
+			cfg:     Config{Backend: BackendLibreTranslate},
+			wantErr: true,
+		},
+		"unknown": {
+			cfg:     Config{Backend: "google"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			translator, err := New(nil, tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.url, translator.(*DeepL).url)
+		})
+	}
+}
+
+func TestDeepL_Translate(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/translate", r.URL.Path)
+		require.Equal(t, "DeepL-Auth-Key secret", r.Header.Get("Authorization"))
+
+		var body struct {
+			Text       []string `json:"text"`
+			TargetLang string   `json:"target_lang"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, []string{"hallo zusammen"}, body.Text)
+		require.Equal(t, "EN", body.TargetLang)
+
+		_, _ = w.Write([]byte(`{"translations":[{"detected_source_language":"DE","text":"hello everyone"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	translator, err := New(srv.Client(), Config{Backend: BackendDeepL, URL: srv.URL, APIKey: "secret", TargetLanguage: "en"})
+	require.NoError(t, err)
+
+	result, err := translator.Translate(t.Context(), "hallo zusammen")
+	require.NoError(t, err)
+	require.Equal(t, Result{Text: "hello everyone", SourceLanguage: "de"}, result)
+}
+
+func TestLibreTranslate_Translate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/translate", r.URL.Path)
+
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]string{"q": "hola", "source": "auto", "target": "en", "format": "text"}, body)
+
+			_, _ = w.Write([]byte(`{"translatedText":"hello","detectedLanguage":{"confidence":90,"language":"es"}}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		translator, err := New(srv.Client(), Config{Backend: BackendLibreTranslate, URL: srv.URL + "/", TargetLanguage: "EN"})
+		require.NoError(t, err)
+
+		result, err := translator.Translate(t.Context(), "hola")
+		require.NoError(t, err)
+		require.Equal(t, Result{Text: "hello", SourceLanguage: "es"}, result)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"Invalid API key"}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		translator, err := New(srv.Client(), Config{Backend: BackendLibreTranslate, URL: srv.URL, TargetLanguage: "en"})
+		require.NoError(t, err)
+
+		_, err = translator.Translate(t.Context(), "hola")
+		require.ErrorContains(t, err, "Invalid API key")
+	})
+}
@@ -34,6 +34,16 @@ func (a API) GetChannelEmotes(ctx context.Context, channelID string) (UserRespon
 	return resp, nil
 }
 
+// GetChannelBots returns the logins the channel marked as bot on BTTV.
+func (a API) GetChannelBots(ctx context.Context, channelID string) ([]string, error) {
+	resp, err := a.GetChannelEmotes(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Bots, nil
+}
+
 func (a API) GetGlobalEmotes(ctx context.Context) (GlobalEmoteResponse, error) {
 	resp, err := doRequest[GlobalEmoteResponse](ctx, a, http.MethodGet, "/cached/emotes/global", nil)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/coder/websocket"
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/julez-dev/chatuino/metrics"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/rs/zerolog"
 )
@@ -176,6 +177,7 @@ func (c *Conn) Run() {
 		if errors.As(err, &forced) {
 			c.logger.Info().Str("new_url", forced.newURL).Msg("forced reconnect to new URL")
 			url = forced.newURL
+			metrics.EventSubReconnects.Inc()
 			continue
 		}
 
@@ -190,6 +192,7 @@ func (c *Conn) Run() {
 		case <-time.After(eventSubReconnectDelay):
 			c.logger.Info().Msg("reconnecting...")
 			url = c.WSURL // reset to default URL on error reconnect
+			metrics.EventSubReconnects.Inc()
 		}
 	}
 }
@@ -81,6 +81,11 @@ type Event struct {
 
 	Viewers int `json:"viewers"`
 
+	// Channel update related, the new title is in Title
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Language     string `json:"language"`
+
 	// Ad related
 	IsAutomatic       bool `json:"is_automatic"`
 	DurationInSeconds int  `json:"duration_seconds"`
@@ -88,6 +93,19 @@ type Event struct {
 	RequesterUserID    string `json:"requester_user_id"`
 	RequesterUserLogin string `json:"requester_user_login"`
 	RequesterUserName  string `json:"requester_user_name"`
+
+	// Channel point redemption related, the user redeemed the reward, the status is in Status
+	ID         string    `json:"id"`
+	UserInput  string    `json:"user_input"`
+	Reward     Reward    `json:"reward"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+type Reward struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Cost   int    `json:"cost"`
+	Prompt string `json:"prompt"`
 }
 
 type Voting struct {
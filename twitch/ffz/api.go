@@ -63,6 +63,43 @@ func (a API) GetGlobalEmotes(ctx context.Context) ([]Emote, error) {
 	return emotes, nil
 }
 
+// GetBotUserIDs fetches the Twitch user IDs with the global FFZ bot badge.
+func (a API) GetBotUserIDs(ctx context.Context) ([]string, error) {
+	resp, err := doRequest[badgeIDsResponse](ctx, a, http.MethodGet, "/badges/ids", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	badgeID := botBadgeID
+	for _, b := range resp.Badges {
+		if b.Name == "bot" {
+			badgeID = b.ID
+			break
+		}
+	}
+
+	return formatUserIDs(resp.Users[strconv.Itoa(badgeID)]), nil
+}
+
+// GetChannelBotUserIDs fetches the Twitch user IDs the channel marked as bot on FFZ.
+func (a API) GetChannelBotUserIDs(ctx context.Context, channelID string) ([]string, error) {
+	resp, err := doRequest[channelResponse](ctx, a, http.MethodGet, "/room/id/"+channelID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatUserIDs(resp.Room.UserBadgeIDs[strconv.Itoa(botBadgeID)]), nil
+}
+
+func formatUserIDs(ids []int) []string {
+	formatted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		formatted = append(formatted, strconv.Itoa(id))
+	}
+
+	return formatted
+}
+
 // collectEmotes flattens all emote sets into a single slice.
 func collectEmotes(sets map[string]emoteSet) []Emote {
 	var emotes []Emote
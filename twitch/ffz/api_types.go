@@ -2,6 +2,9 @@ package ffz
 
 import "fmt"
 
+// botBadgeID is the ID of the FFZ bot badge, used by rooms to mark their bots
+const botBadgeID = 2
+
 type APIError struct {
 	StatusCode int    `json:"-"`
 	Status     string `json:"-"`
@@ -27,8 +30,18 @@ type (
 	}
 
 	Room struct {
-		TwitchID int `json:"twitch_id"`
-		Set      int `json:"set"`
+		TwitchID     int              `json:"twitch_id"`
+		Set          int              `json:"set"`
+		UserBadgeIDs map[string][]int `json:"user_badge_ids"` // badge ID to Twitch user IDs
+	}
+
+	// badgeIDsResponse is the raw API response from /v1/badges/ids.
+	badgeIDsResponse struct {
+		Badges []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"badges"`
+		Users map[string][]int `json:"users"` // badge ID to Twitch user IDs
 	}
 
 	emoteSet struct {
@@ -34,6 +34,17 @@ func (a API) GetChannelEmotes(ctx context.Context, channelID string) (ChannelEmo
 	return resp, nil
 }
 
+// GetTwitchUser returns the 7TV user connected to the Twitch user, including the selected paint and badge.
+// Twitch users without 7TV account result in an APIError with status code 404.
+func (a API) GetTwitchUser(ctx context.Context, twitchUserID string) (TwitchUserResponse, error) {
+	resp, err := doRequest[TwitchUserResponse](ctx, a, http.MethodGet, "/users/twitch/"+twitchUserID, nil)
+	if err != nil {
+		return TwitchUserResponse{}, err
+	}
+
+	return resp, nil
+}
+
 func (a API) GetGlobalEmotes(ctx context.Context) (EmoteResponse, error) {
 	resp, err := doRequest[EmoteResponse](ctx, a, http.MethodGet, "/emote-sets/global", nil)
 	if err != nil {
@@ -47,3 +47,55 @@ type (
 		Files []Files `json:"files"`
 	}
 )
+
+type (
+	TwitchUserResponse struct {
+		User User `json:"user"`
+	}
+	User struct {
+		ID       string    `json:"id"`
+		Username string    `json:"username"`
+		Style    UserStyle `json:"style"`
+	}
+	// UserStyle are the cosmetics selected by the user, Paint and Badge are only set if the user selected one
+	UserStyle struct {
+		Color   int32  `json:"color"`
+		PaintID string `json:"paint_id"`
+		Paint   *Paint `json:"paint"`
+		BadgeID string `json:"badge_id"`
+		Badge   *Badge `json:"badge"`
+	}
+	// Paint is a CSS-like gradient or image drawn over the username
+	Paint struct {
+		ID       string      `json:"id"`
+		Name     string      `json:"name"`
+		Function string      `json:"function"` // LINEAR_GRADIENT, RADIAL_GRADIENT or URL
+		Color    *int32      `json:"color"`    // solid color, set for some paints without stops
+		Repeat   bool        `json:"repeat"`
+		Angle    int         `json:"angle"` // degrees, CSS convention: 0 is bottom to top, 90 left to right
+		Stops    []PaintStop `json:"stops"`
+		ImageURL string      `json:"image_url"`
+	}
+	PaintStop struct {
+		At    float64 `json:"at"` // 0-1
+		Color int32   `json:"color"`
+	}
+	Badge struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Tooltip string `json:"tooltip"`
+		Host    Host   `json:"host"`
+	}
+)
+
+const (
+	PaintLinearGradient = "LINEAR_GRADIENT"
+	PaintRadialGradient = "RADIAL_GRADIENT"
+	PaintURL            = "URL"
+)
+
+// RGBA splits a 7TV color, which is packed as signed 32 bit RGBA integer
+func RGBA(color int32) (r, g, b, a uint8) {
+	v := uint32(color)
+	return uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 
 	"github.com/rs/zerolog/log"
@@ -334,6 +335,92 @@ func (a *API) FetchUserFollowedChannels(ctx context.Context, userID string, broa
 	return channels, nil
 }
 
+// GetUserBlockList returns all users blocked by the user
+func (a *API) GetUserBlockList(ctx context.Context, userID string) ([]BlockedUser, error) {
+	users := []BlockedUser{}
+	var after string
+
+	for {
+		values := url.Values{}
+		values.Add("broadcaster_id", userID)
+		values.Add("first", "100")
+		if after != "" {
+			values.Add("after", after)
+		}
+
+		url := fmt.Sprintf("/users/blocks?%s", values.Encode())
+
+		resp, err := doAuthenticatedUserRequest[GetUserBlockListResponse](ctx, a, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, resp.Data...)
+
+		if resp.Pagination.Cursor == "" {
+			break
+		}
+
+		after = resp.Pagination.Cursor
+	}
+
+	return users, nil
+}
+
+// BlockUser blocks the target user for the authenticated user
+func (a *API) BlockUser(ctx context.Context, targetUserID string) error {
+	values := url.Values{}
+	values.Add("target_user_id", targetUserID)
+
+	url := fmt.Sprintf("/users/blocks?%s", values.Encode())
+
+	_, err := doAuthenticatedUserRequest[any](ctx, a, http.MethodPut, url, nil)
+	return err
+}
+
+// UnblockUser removes the target user from the block list of the authenticated user
+func (a *API) UnblockUser(ctx context.Context, targetUserID string) error {
+	values := url.Values{}
+	values.Add("target_user_id", targetUserID)
+
+	url := fmt.Sprintf("/users/blocks?%s", values.Encode())
+
+	_, err := doAuthenticatedUserRequest[any](ctx, a, http.MethodDelete, url, nil)
+	return err
+}
+
+// GetFollowedStreams returns all live streams the user follows, sorted by viewer count.
+func (a *API) GetFollowedStreams(ctx context.Context, userID string) ([]StreamData, error) {
+	streams := []StreamData{}
+	var after string
+
+	for {
+		values := url.Values{}
+		values.Add("user_id", userID)
+		values.Add("first", "100")
+		if after != "" {
+			values.Add("after", after)
+		}
+
+		url := fmt.Sprintf("/streams/followed?%s", values.Encode())
+
+		resp, err := doAuthenticatedUserRequest[GetStreamsResponse](ctx, a, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		streams = append(streams, resp.Data...)
+
+		if resp.Pagination.Cursor == "" {
+			break
+		}
+
+		after = resp.Pagination.Cursor
+	}
+
+	return streams, nil
+}
+
 func (a *API) FetchUnbanRequests(ctx context.Context, broadcasterID, moderatorID string) ([]UnbanRequest, error) {
 	// Fetch all unban requests for the broadcaster
 	// For all statuses, handle each status in a separate goroutine
@@ -558,6 +645,31 @@ func (a *API) GetChatSettings(ctx context.Context, broadcasterID string, moderat
 	return resp, nil
 }
 
+// UpdateChatSettings changes the set fields of the request, moderatorID needs to match ID of the user the token was generated for
+func (a *API) UpdateChatSettings(ctx context.Context, broadcasterID string, moderatorID string, req UpdateChatSettingsRequest) (ChatSettingData, error) {
+	values := url.Values{}
+	values.Add("broadcaster_id", broadcasterID)
+	values.Add("moderator_id", moderatorID)
+
+	url := fmt.Sprintf("/chat/settings?%s", values.Encode())
+
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return ChatSettingData{}, err
+	}
+
+	resp, err := doAuthenticatedUserRequest[GetChatSettingsResponse](ctx, a, http.MethodPatch, url, reqBytes)
+	if err != nil {
+		return ChatSettingData{}, err
+	}
+
+	if len(resp.Data) == 0 {
+		return ChatSettingData{}, errors.New("no chat settings returned")
+	}
+
+	return resp.Data[0], nil
+}
+
 func (a *API) SendChatAnnouncement(ctx context.Context, broadcasterID string, moderatorID string, req CreateChatAnnouncementRequest) error {
 	values := url.Values{}
 	values.Add("broadcaster_id", broadcasterID)
@@ -578,6 +690,143 @@ func (a *API) SendChatAnnouncement(ctx context.Context, broadcasterID string, mo
 	return nil
 }
 
+// CheckAutoModStatus checks if AutoMod would hold the messages in the channel of the broadcaster.
+// broadcasterID needs to match the ID of the user the token was generated for.
+func (a *API) CheckAutoModStatus(ctx context.Context, broadcasterID string, messages []AutoModCheckMessage) ([]AutoModStatus, error) {
+	values := url.Values{}
+	values.Add("broadcaster_id", broadcasterID)
+
+	url := fmt.Sprintf("/moderation/enforcements/status?%s", values.Encode())
+
+	reqBytes, err := json.Marshal(CheckAutoModStatusRequest{Data: messages})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := doAuthenticatedUserRequest[CheckAutoModStatusResponse](ctx, a, http.MethodPost, url, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// GetCustomRewards returns the custom channel point rewards of the broadcaster. Only redemptions of rewards
+// created with the client ID of the token can be updated, onlyManageable limits the result to those rewards.
+func (a *API) GetCustomRewards(ctx context.Context, broadcasterID string, onlyManageable bool) ([]CustomReward, error) {
+	values := url.Values{}
+	values.Add("broadcaster_id", broadcasterID)
+	values.Add("only_manageable_rewards", strconv.FormatBool(onlyManageable))
+
+	url := fmt.Sprintf("/channel_points/custom_rewards?%s", values.Encode())
+
+	resp, err := doAuthenticatedUserRequest[GetCustomRewardsResponse](ctx, a, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// GetCustomRewardRedemptions returns all redemptions of the reward with the status, oldest first
+func (a *API) GetCustomRewardRedemptions(ctx context.Context, broadcasterID, rewardID, status string) ([]CustomRewardRedemption, error) {
+	var (
+		redemptions []CustomRewardRedemption
+		after       string
+	)
+
+	for {
+		values := url.Values{}
+		values.Add("broadcaster_id", broadcasterID)
+		values.Add("reward_id", rewardID)
+		values.Add("status", status)
+		values.Add("sort", "OLDEST")
+		values.Add("first", "50")
+		if after != "" {
+			values.Add("after", after)
+		}
+
+		url := fmt.Sprintf("/channel_points/custom_rewards/redemptions?%s", values.Encode())
+
+		resp, err := doAuthenticatedUserRequest[GetCustomRewardRedemptionsResponse](ctx, a, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		redemptions = append(redemptions, resp.Data...)
+
+		if resp.Pagination.Cursor == "" {
+			return redemptions, nil
+		}
+
+		after = resp.Pagination.Cursor
+	}
+}
+
+// UpdateRedemptionStatus marks a redemption as fulfilled or canceled, canceling refunds the channel points to the user
+func (a *API) UpdateRedemptionStatus(ctx context.Context, broadcasterID, rewardID, redemptionID, status string) (CustomRewardRedemption, error) {
+	values := url.Values{}
+	values.Add("id", redemptionID)
+	values.Add("broadcaster_id", broadcasterID)
+	values.Add("reward_id", rewardID)
+
+	url := fmt.Sprintf("/channel_points/custom_rewards/redemptions?%s", values.Encode())
+
+	reqBytes, err := json.Marshal(UpdateRedemptionStatusRequest{Status: status})
+	if err != nil {
+		return CustomRewardRedemption{}, err
+	}
+
+	resp, err := doAuthenticatedUserRequest[GetCustomRewardRedemptionsResponse](ctx, a, http.MethodPatch, url, reqBytes)
+	if err != nil {
+		return CustomRewardRedemption{}, err
+	}
+
+	if len(resp.Data) == 0 {
+		return CustomRewardRedemption{}, errors.New("no redemption returned")
+	}
+
+	return resp.Data[0], nil
+}
+
+// CreatePoll starts a poll in the channel of the broadcaster, broadcasterID needs to match the ID of the user the token was generated for
+func (a *API) CreatePoll(ctx context.Context, req CreatePollRequest) (Poll, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	resp, err := doAuthenticatedUserRequest[CreatePollResponse](ctx, a, http.MethodPost, "/polls", reqBytes)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	if len(resp.Data) == 0 {
+		return Poll{}, errors.New("no poll returned")
+	}
+
+	return resp.Data[0], nil
+}
+
+// CreatePrediction starts a prediction in the channel of the broadcaster, broadcasterID needs to match the ID of the user the token was generated for
+func (a *API) CreatePrediction(ctx context.Context, req CreatePredictionRequest) (Prediction, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return Prediction{}, err
+	}
+
+	resp, err := doAuthenticatedUserRequest[CreatePredictionResponse](ctx, a, http.MethodPost, "/predictions", reqBytes)
+	if err != nil {
+		return Prediction{}, err
+	}
+
+	if len(resp.Data) == 0 {
+		return Prediction{}, errors.New("no prediction returned")
+	}
+
+	return resp.Data[0], nil
+}
+
 func (a *API) CreateStreamMarker(ctx context.Context, req CreateStreamMarkerRequest) (StreamMarker, error) {
 	reqBytes, err := json.Marshal(req)
 	if err != nil {
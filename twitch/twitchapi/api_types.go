@@ -147,6 +147,23 @@ type (
 	}
 )
 
+// https://dev.twitch.tv/docs/api/reference/#update-chat-settings
+// Only the set fields are changed
+type (
+	//easyjson:json
+	UpdateChatSettingsRequest struct {
+		EmoteMode                     *bool `json:"emote_mode,omitempty"`
+		FollowerMode                  *bool `json:"follower_mode,omitempty"`
+		FollowerModeDuration          *int  `json:"follower_mode_duration,omitempty"` // in minutes, 0-129600
+		NonModeratorChatDelay         *bool `json:"non_moderator_chat_delay,omitempty"`
+		NonModeratorChatDelayDuration *int  `json:"non_moderator_chat_delay_duration,omitempty"` // 2, 4 or 6 seconds
+		SlowMode                      *bool `json:"slow_mode,omitempty"`
+		SlowModeWaitTime              *int  `json:"slow_mode_wait_time,omitempty"` // in seconds, 3-120
+		SubscriberMode                *bool `json:"subscriber_mode,omitempty"`
+		UniqueChatMode                *bool `json:"unique_chat_mode,omitempty"`
+	}
+)
+
 // https://dev.twitch.tv/docs/api/reference/#ban-user
 type (
 	//easyjson:json
@@ -208,6 +225,22 @@ type (
 	}
 )
 
+// https://dev.twitch.tv/docs/api/reference/#get-user-block-list
+type (
+	//easyjson:json
+	GetUserBlockListResponse struct {
+		Data       []BlockedUser `json:"data"`
+		Pagination Pagination    `json:"pagination"`
+	}
+
+	//easyjson:json
+	BlockedUser struct {
+		UserID      string `json:"user_id"`
+		UserLogin   string `json:"user_login"`
+		DisplayName string `json:"display_name"`
+	}
+)
+
 // https://dev.twitch.tv/docs/api/reference/#get-eventsub-subscriptions
 type (
 	//easyjson:json
@@ -382,3 +415,129 @@ type (
 		ClickURL     string `json:"click_url"`
 	}
 )
+
+// https://dev.twitch.tv/docs/api/reference/#check-automod-status
+type (
+	//easyjson:json
+	CheckAutoModStatusRequest struct {
+		Data []AutoModCheckMessage `json:"data"`
+	}
+	//easyjson:json
+	AutoModCheckMessage struct {
+		MsgID   string `json:"msg_id"`
+		MsgText string `json:"msg_text"`
+	}
+	//easyjson:json
+	CheckAutoModStatusResponse struct {
+		Data []AutoModStatus `json:"data"`
+	}
+	//easyjson:json
+	AutoModStatus struct {
+		MsgID       string `json:"msg_id"`
+		IsPermitted bool   `json:"is_permitted"`
+	}
+)
+
+// Redemption statuses of custom rewards
+const (
+	RedemptionStatusUnfulfilled = "UNFULFILLED"
+	RedemptionStatusFulfilled   = "FULFILLED"
+	RedemptionStatusCanceled    = "CANCELED"
+)
+
+// https://dev.twitch.tv/docs/api/reference/#get-custom-reward
+type (
+	//easyjson:json
+	GetCustomRewardsResponse struct {
+		Data []CustomReward `json:"data"`
+	}
+	//easyjson:json
+	CustomReward struct {
+		ID     string `json:"id"`
+		Title  string `json:"title"`
+		Prompt string `json:"prompt"`
+		Cost   int    `json:"cost"`
+	}
+)
+
+// https://dev.twitch.tv/docs/api/reference/#get-custom-reward-redemption
+type (
+	//easyjson:json
+	GetCustomRewardRedemptionsResponse struct {
+		Data       []CustomRewardRedemption `json:"data"`
+		Pagination Pagination               `json:"pagination"`
+	}
+	//easyjson:json
+	CustomRewardRedemption struct {
+		ID               string       `json:"id"`
+		BroadcasterID    string       `json:"broadcaster_id"`
+		BroadcasterLogin string       `json:"broadcaster_login"`
+		UserID           string       `json:"user_id"`
+		UserLogin        string       `json:"user_login"`
+		UserName         string       `json:"user_name"`
+		UserInput        string       `json:"user_input"`
+		Status           string       `json:"status"`
+		RedeemedAt       time.Time    `json:"redeemed_at"`
+		Reward           CustomReward `json:"reward"`
+	}
+	//easyjson:json
+	UpdateRedemptionStatusRequest struct {
+		Status string `json:"status"`
+	}
+)
+
+// https://dev.twitch.tv/docs/api/reference/#create-poll
+type (
+	//easyjson:json
+	CreatePollRequest struct {
+		BroadcasterID string       `json:"broadcaster_id"`
+		Title         string       `json:"title"`
+		Choices       []PollChoice `json:"choices"`
+		Duration      int          `json:"duration"` // seconds
+	}
+	//easyjson:json
+	PollChoice struct {
+		Title string `json:"title"`
+	}
+	//easyjson:json
+	CreatePollResponse struct {
+		Data []Poll `json:"data"`
+	}
+	//easyjson:json
+	Poll struct {
+		ID        string       `json:"id"`
+		Title     string       `json:"title"`
+		Choices   []PollChoice `json:"choices"`
+		Duration  int          `json:"duration"`
+		Status    string       `json:"status"`
+		StartedAt time.Time    `json:"started_at"`
+	}
+)
+
+// https://dev.twitch.tv/docs/api/reference/#create-prediction
+type (
+	//easyjson:json
+	CreatePredictionRequest struct {
+		BroadcasterID    string              `json:"broadcaster_id"`
+		Title            string              `json:"title"`
+		Outcomes         []PredictionOutcome `json:"outcomes"`
+		PredictionWindow int                 `json:"prediction_window"` // seconds
+	}
+	//easyjson:json
+	PredictionOutcome struct {
+		Title string `json:"title"`
+	}
+	//easyjson:json
+	CreatePredictionResponse struct {
+		Data []Prediction `json:"data"`
+	}
+	//easyjson:json
+	Prediction struct {
+		ID               string              `json:"id"`
+		Title            string              `json:"title"`
+		Outcomes         []PredictionOutcome `json:"outcomes"`
+		PredictionWindow int                 `json:"prediction_window"`
+		Status           string              `json:"status"`
+		CreatedAt        time.Time           `json:"created_at"`
+	}
+)
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/coder/websocket"
+	"github.com/julez-dev/chatuino/metrics"
 	"github.com/julez-dev/chatuino/save"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
@@ -31,6 +32,13 @@ type ConnAccountProvider interface {
 	GetAccountBy(id string) (save.Account, error)
 }
 
+// ConnHealth describes the state of a connection
+type ConnHealth struct {
+	Connected   bool
+	Latency     time.Duration // round trip time of the last ping, 0 until measured
+	LastMessage time.Time     // when the last message was received, including PINGs of the server
+}
+
 // Conn manages a single IRC WebSocket connection with automatic reconnection.
 type Conn struct {
 	accountID string
@@ -41,15 +49,27 @@ type Conn struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	sendCh chan IRCer
+	sendCh      chan IRCer
+	reconnectCh chan struct{}
 
-	mu       sync.Mutex
-	channels []string
-	refs     int
-	closed   bool
+	mu         sync.Mutex
+	channels   []string
+	refs       int
+	closed     bool
+	health     ConnHealth
+	connCancel context.CancelFunc // cancels the current connection attempt
 
 	// WSURL allows overriding the WebSocket URL for testing
 	WSURL string
+
+	// Traffic records the raw lines of the connection, optional
+	Traffic *Traffic
+
+	// Membership requests the JOIN and PART messages of chatters
+	Membership bool
+
+	// Transport is used to dial the WebSocket, optional, defaults to http.DefaultTransport
+	Transport http.RoundTripper
 }
 
 // NewConn creates a new IRC connection for the given account.
@@ -57,14 +77,15 @@ type Conn struct {
 func NewConn(accountID string, accounts ConnAccountProvider, logger zerolog.Logger, sendFn func(msg IRCer, err error)) *Conn {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Conn{
-		accountID: accountID,
-		accounts:  accounts,
-		logger:    logger.With().Str("account_id", accountID).Str("conn", "irc").Logger(),
-		sendFn:    sendFn,
-		ctx:       ctx,
-		cancel:    cancel,
-		sendCh:    make(chan IRCer, ircSendBufferSize),
-		WSURL:     DefaultIRCWSURL,
+		accountID:   accountID,
+		accounts:    accounts,
+		logger:      logger.With().Str("account_id", accountID).Str("conn", "irc").Logger(),
+		sendFn:      sendFn,
+		ctx:         ctx,
+		cancel:      cancel,
+		sendCh:      make(chan IRCer, ircSendBufferSize),
+		reconnectCh: make(chan struct{}, 1),
+		WSURL:       DefaultIRCWSURL,
 	}
 }
 
@@ -94,6 +115,33 @@ func (c *Conn) Close() {
 	c.cancel()
 }
 
+// Health returns the current state of the connection.
+func (c *Conn) Health() ConnHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.health
+}
+
+// Reconnect drops the current connection and connects again without waiting for the reconnect delay.
+func (c *Conn) Reconnect() {
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+
+	c.mu.Lock()
+	if c.connCancel != nil {
+		c.connCancel()
+	}
+	c.mu.Unlock()
+}
+
+func (c *Conn) setHealth(fn func(h *ConnHealth)) {
+	c.mu.Lock()
+	fn(&c.health)
+	c.mu.Unlock()
+}
+
 // Send queues a message to be sent over the connection.
 func (c *Conn) Send(msg IRCer) error {
 	c.mu.Lock()
@@ -148,7 +196,8 @@ func (c *Conn) Run() {
 			return
 		}
 
-		if err != nil {
+		// a requested reconnect cancels the connection, which is not an error
+		if err != nil && len(c.reconnectCh) == 0 {
 			c.logger.Warn().Err(err).Msg("connection error, will reconnect")
 			c.emitError(fmt.Errorf("disconnected from chat server: %w", err))
 		}
@@ -156,18 +205,29 @@ func (c *Conn) Run() {
 		select {
 		case <-c.ctx.Done():
 			return
+		case <-c.reconnectCh:
+			c.logger.Info().Msg("reconnecting on request...")
+			metrics.IRCReconnects.Inc()
 		case <-time.After(ircReconnectDelay):
 			c.logger.Info().Msg("reconnecting...")
+			metrics.IRCReconnects.Inc()
 		}
 	}
 }
 
 func (c *Conn) connectOnce() error {
-	dialCtx, dialCancel := context.WithTimeout(c.ctx, ircDialTimeout)
+	connCtx, connCancel := context.WithCancel(c.ctx)
+	defer connCancel()
+
+	c.mu.Lock()
+	c.connCancel = connCancel
+	c.mu.Unlock()
+
+	dialCtx, dialCancel := context.WithTimeout(connCtx, ircDialTimeout)
 	defer dialCancel()
 
 	ws, _, err := websocket.Dial(dialCtx, c.WSURL, &websocket.DialOptions{
-		HTTPClient: &http.Client{Timeout: ircDialTimeout * 2},
+		HTTPClient: &http.Client{Transport: c.Transport, Timeout: ircDialTimeout * 2},
 	})
 	if err != nil {
 		return fmt.Errorf("dial failed: %w", err)
@@ -176,20 +236,28 @@ func (c *Conn) connectOnce() error {
 
 	ws.SetReadLimit(ircMaxMessageSize)
 
-	if err := c.authenticate(ws); err != nil {
+	if err := c.authenticate(connCtx, ws); err != nil {
 		return fmt.Errorf("auth failed: %w", err)
 	}
 
+	c.setHealth(func(h *ConnHealth) {
+		*h = ConnHealth{Connected: true, LastMessage: time.Now()}
+	})
+	defer c.setHealth(func(h *ConnHealth) {
+		h.Connected = false
+		h.Latency = 0
+	})
+
 	// Rejoin channels after reconnect
 	for _, ch := range c.getChannels() {
 		msg := fmt.Sprintf("JOIN #%s", ch)
-		if err := ws.Write(c.ctx, websocket.MessageText, []byte(msg)); err != nil {
+		if err := c.write(connCtx, ws, msg); err != nil {
 			return fmt.Errorf("rejoin failed: %w", err)
 		}
 	}
 
 	// Run reader/writer/pinger concurrently
-	g, ctx := errgroup.WithContext(c.ctx)
+	g, ctx := errgroup.WithContext(connCtx)
 
 	// Internal channel for PONG messages (reader → writer)
 	pongCh := make(chan struct{}, 1)
@@ -209,7 +277,7 @@ func (c *Conn) connectOnce() error {
 	return g.Wait()
 }
 
-func (c *Conn) authenticate(ws *websocket.Conn) error {
+func (c *Conn) authenticate(ctx context.Context, ws *websocket.Conn) error {
 	account, err := c.accounts.GetAccountBy(c.accountID)
 	if err != nil {
 		return fmt.Errorf("get account: %w", err)
@@ -220,14 +288,19 @@ func (c *Conn) authenticate(ws *websocket.Conn) error {
 		oauth = "oauth:" + oauth
 	}
 
+	capabilities := "twitch.tv/tags twitch.tv/commands"
+	if c.Membership {
+		capabilities = "twitch.tv/membership " + capabilities
+	}
+
 	authMsgs := []string{
 		fmt.Sprintf("PASS %s", oauth),
 		fmt.Sprintf("NICK %s", account.DisplayName),
-		"CAP REQ :twitch.tv/membership twitch.tv/tags twitch.tv/commands",
+		"CAP REQ :" + capabilities,
 	}
 
 	for _, msg := range authMsgs {
-		if err := ws.Write(c.ctx, websocket.MessageText, []byte(msg)); err != nil {
+		if err := c.write(ctx, ws, msg); err != nil {
 			return err
 		}
 	}
@@ -242,19 +315,23 @@ func (c *Conn) readLoop(ctx context.Context, ws *websocket.Conn, pongCh chan<- s
 			return err
 		}
 
+		c.setHealth(func(h *ConnHealth) {
+			h.LastMessage = time.Now()
+		})
+
 		// Twitch may send multiple messages in one frame
 		for _, line := range strings.Split(string(data), "\r\n") {
 			if line == "" {
 				continue
 			}
 
+			c.Traffic.Add(Inbound, c.accountID, line)
+
 			parsed, err := ParseIRC(line)
 			if err != nil {
 				if errors.Is(err, ErrUnhandledCommand) {
-					// Ignore PART, JOIN, tmi.twitch.tv notices
-					if !strings.Contains(line, "PART") &&
-						!strings.Contains(line, "JOIN") &&
-						!strings.HasPrefix(line, ":tmi.twitch.tv") {
+					// Ignore tmi.twitch.tv notices
+					if !strings.HasPrefix(line, ":tmi.twitch.tv") {
 						c.logger.Debug().Str("line", line).Msg("unhandled IRC command")
 					}
 					continue
@@ -282,20 +359,26 @@ func (c *Conn) writeLoop(ctx context.Context, ws *websocket.Conn, pongCh <-chan
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-pongCh:
-			if err := ws.Write(ctx, websocket.MessageText, []byte("PONG")); err != nil {
+			if err := c.write(ctx, ws, "PONG"); err != nil {
 				return err
 			}
 		case msg, ok := <-c.sendCh:
 			if !ok {
 				return nil
 			}
-			if err := ws.Write(ctx, websocket.MessageText, []byte(msg.IRC())); err != nil {
+			if err := c.write(ctx, ws, msg.IRC()); err != nil {
 				return err
 			}
 		}
 	}
 }
 
+// write sends a raw line and records it in the traffic
+func (c *Conn) write(ctx context.Context, ws *websocket.Conn, line string) error {
+	c.Traffic.Add(Outbound, c.accountID, line)
+	return ws.Write(ctx, websocket.MessageText, []byte(line))
+}
+
 func (c *Conn) pingLoop(ctx context.Context, ws *websocket.Conn) error {
 	ticker := time.NewTicker(ircPingInterval)
 	defer ticker.Stop()
@@ -306,11 +389,17 @@ func (c *Conn) pingLoop(ctx context.Context, ws *websocket.Conn) error {
 			return nil
 		case <-ticker.C:
 			pingCtx, cancel := context.WithTimeout(ctx, ircPingTimeout)
+			start := time.Now()
 			err := ws.Ping(pingCtx)
 			cancel()
 			if err != nil {
 				return fmt.Errorf("ping timeout: %w", err)
 			}
+
+			latency := time.Since(start)
+			c.setHealth(func(h *ConnHealth) {
+				h.Latency = latency
+			})
 		}
 	}
 }
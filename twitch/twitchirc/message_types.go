@@ -329,6 +329,7 @@ type Whisper struct {
 	DisplayName string
 	Emotes      []Emote
 	ID          string
+	LoginName   string
 	ThreadID    string
 	Turbo       bool
 	UserID      string
@@ -392,3 +393,25 @@ type ClearMessage struct {
 func (c *ClearMessage) IRC() string {
 	return ""
 }
+
+// Membership is a JOIN or PART of a chatter, only sent with the membership capability.
+// Twitch sends them in batches and only for channels with less than 1000 chatters.
+type Membership struct {
+	Joined          bool // false if the chatter left
+	ChannelUserName string
+	LoginName       string
+}
+
+func (m *Membership) IRC() string {
+	return ""
+}
+
+// Names lists chatters already present when joining a channel, only sent with the membership capability
+type Names struct {
+	ChannelUserName string
+	LoginNames      []string
+}
+
+func (n *Names) IRC() string {
+	return ""
+}
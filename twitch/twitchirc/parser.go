@@ -25,6 +25,10 @@ var (
 	// command in the parsed message.
 	ErrMissingCommand = errors.New("irc: missing message command")
 
+	// ErrMissingChannel is returned when parsing if a command sent to
+	// a channel has no channel param.
+	ErrMissingChannel = errors.New("irc: missing channel param")
+
 	ErrUnhandledCommand = errors.New("irc: message command not handled by parser")
 )
 
@@ -88,6 +92,10 @@ func ParseIRC(message string) (IRCer, error) {
 
 		c.tags = parseTags(message[1:loc])
 		message = message[loc+1:]
+
+		if len(message) == 0 {
+			return nil, ErrMissingCommand
+		}
 	}
 
 	if message[0] == ':' {
@@ -136,6 +144,13 @@ func ParseIRC(message string) (IRCer, error) {
 	// spew.Fdump(&buff, c)
 	// log.Log().Str("buff", buff.String()).Msg("opend")
 
+	switch c.Command {
+	case "PRIVMSG", "NOTICE", "USERNOTICE", "USERSTATE", "ROOMSTATE", "CLEARCHAT", "CLEARMSG":
+		if len(c.Params) == 0 {
+			return nil, ErrMissingChannel
+		}
+	}
+
 	switch c.Command {
 	case "PRIVMSG":
 		bits, err := strconv.Atoi(emptyStringZero(string(c.tags["bits"])))
@@ -371,6 +386,7 @@ func ParseIRC(message string) (IRCer, error) {
 			DisplayName: string(c.tags["display-name"]),
 			Emotes:      parseEmotes(string(c.tags["emotes"])),
 			ID:          string(c.tags["id"]),
+			LoginName:   c.prefix.Name,
 			ThreadID:    string(c.tags["thread-id"]),
 			Turbo:       c.tags["turbo"] == "1",
 			UserID:      string(c.tags["user-id"]),
@@ -455,6 +471,29 @@ func ParseIRC(message string) (IRCer, error) {
 		}
 
 		return &c, nil
+	case "JOIN", "PART":
+		if len(c.Params) == 0 {
+			return nil, ErrUnhandledCommand
+		}
+
+		m := Membership{
+			Joined:          c.Command == "JOIN",
+			ChannelUserName: strings.TrimPrefix(c.Params[0], "#"),
+			LoginName:       c.prefix.Name,
+		}
+
+		return &m, nil
+	case "353": // RPL_NAMREPLY, chatters present when joining a channel
+		if len(c.Params) < 4 {
+			return nil, ErrUnhandledCommand
+		}
+
+		n := Names{
+			ChannelUserName: strings.TrimPrefix(c.Params[2], "#"),
+			LoginNames:      strings.Fields(c.Params[3]),
+		}
+
+		return &n, nil
 	}
 
 	return nil, ErrUnhandledCommand
@@ -229,6 +229,42 @@ func Test_ParseIRC_Files(t *testing.T) {
 	}
 }
 
+func Test_ParseIRC_Membership(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  IRCer
+	}{
+		{
+			name:  "join",
+			input: ":ronni!ronni@ronni.tmi.twitch.tv JOIN #dallas",
+			want:  &Membership{Joined: true, ChannelUserName: "dallas", LoginName: "ronni"},
+		},
+		{
+			name:  "part",
+			input: ":ronni!ronni@ronni.tmi.twitch.tv PART #dallas",
+			want:  &Membership{ChannelUserName: "dallas", LoginName: "ronni"},
+		},
+		{
+			name:  "names",
+			input: ":foo.tmi.twitch.tv 353 foo = #bar :foo ronni dallas",
+			want:  &Names{ChannelUserName: "bar", LoginNames: []string{"foo", "ronni", "dallas"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseIRC(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Fuzz_ParseIRC(f *testing.F) {
 	msgLineFmt := `@badge-info=subscriber/21;badges=subscriber/18;client-nonce=3b4d1fa0f6549a0228e5feafc4382755;color=#8A2BE2;display-name=julezdev;emotes=;first-msg=0;flags=;id=60654e92-f779-4e3b-beec-3f2d38031be9;mod=0;returning-chatter=0;room-id=92038375;subscriber=1;tmi-sent-ts=1763899302525;turbo=0;user-id=1;user-type= :julezdev!julezdev@julezdev.tmi.twitch.tv PRIVMSG #julezdev :%s`
 
@@ -238,3 +274,32 @@ func Fuzz_ParseIRC(f *testing.F) {
 		require.NotNil(t, irc)
 	})
 }
+
+func Fuzz_ParseIRCLine(f *testing.F) {
+	messageFile, err := os.Open("testdata/messages.txt")
+	require.NoError(f, err)
+	defer messageFile.Close()
+
+	scanner := bufio.NewScanner(messageFile)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		f.Add(scanner.Text())
+	}
+	require.NoError(f, scanner.Err())
+
+	f.Add(":tmi.twitch.tv CLEARCHAT #channel")
+	f.Add("@ban-duration=;target-user-id= :tmi.twitch.tv CLEARCHAT #channel :user")
+	f.Add("@emotes=25:0-4,6-10/1902:12-16 :a!a@a.tmi.twitch.tv PRIVMSG #a :Kappa Kappa Keepo")
+	f.Add(":a!a@a.tmi.twitch.tv JOIN #a")
+	f.Add(":a.tmi.twitch.tv 353 a = #a :b c d")
+	f.Add("PING :tmi.twitch.tv")
+	f.Add("@")
+
+	// malformed lines must result in an error, never in a panic or a nil message
+	f.Fuzz(func(t *testing.T, line string) {
+		irc, err := ParseIRC(line)
+		if err == nil {
+			require.NotNil(t, irc)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package twitchirc
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTrafficSize is the number of raw lines kept by Chatuino for the IRC inspector
+const DefaultTrafficSize = 2000
+
+// Direction tells if a raw line was received or sent
+type Direction int
+
+const (
+	Inbound Direction = iota
+	Outbound
+)
+
+// TrafficLine is a raw IRC line of a connection, as received from or sent to Twitch
+type TrafficLine struct {
+	Time      time.Time
+	Direction Direction
+	AccountID string
+	Line      string
+}
+
+// Traffic keeps the last raw lines of all connections it is set on. It is safe for concurrent use.
+type Traffic struct {
+	m       sync.Mutex
+	lines   []TrafficLine
+	next    int    // index the next line is written to
+	written uint64 // number of lines ever added
+}
+
+func NewTraffic(size int) *Traffic {
+	return &Traffic{lines: make([]TrafficLine, 0, size)}
+}
+
+// Add keeps a line, the token of PASS commands is redacted. Add on a nil Traffic does nothing.
+func (t *Traffic) Add(direction Direction, accountID, line string) {
+	if t == nil {
+		return
+	}
+
+	if strings.HasPrefix(line, "PASS ") {
+		line = "PASS <redacted>"
+	}
+
+	entry := TrafficLine{Time: time.Now(), Direction: direction, AccountID: accountID, Line: line}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if len(t.lines) < cap(t.lines) {
+		t.lines = append(t.lines, entry)
+	} else if cap(t.lines) > 0 {
+		t.lines[t.next] = entry
+	}
+
+	if cap(t.lines) > 0 {
+		t.next = (t.next + 1) % cap(t.lines)
+	}
+
+	t.written++
+}
+
+// Lines returns the kept lines, oldest first
+func (t *Traffic) Lines() []TrafficLine {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if len(t.lines) < cap(t.lines) {
+		return slices.Clone(t.lines)
+	}
+
+	return slices.Concat(t.lines[t.next:], t.lines[:t.next])
+}
+
+// Written returns the number of lines added so far, which changes whenever a new line is kept
+func (t *Traffic) Written() uint64 {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	return t.written
+}
+
+// Tag is a message tag of an IRC line with its unescaped value
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// SplitTags splits a raw line into its tags and the rest of the line, lines without tags are returned unchanged
+func SplitTags(line string) ([]Tag, string) {
+	if !strings.HasPrefix(line, "@") {
+		return nil, line
+	}
+
+	rawTags, rest, _ := strings.Cut(line[1:], " ")
+
+	var tags []Tag
+	for _, raw := range strings.Split(rawTags, ";") {
+		key, value, _ := strings.Cut(raw, "=")
+		tags = append(tags, Tag{Key: key, Value: string(parseTagValue(value))})
+	}
+
+	return tags, rest
+}
@@ -0,0 +1,45 @@
+package twitchirc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraffic(t *testing.T) {
+	t.Parallel()
+
+	traffic := NewTraffic(2)
+	traffic.Add(Outbound, "1", "PASS oauth:token")
+	traffic.Add(Inbound, "1", "PING :tmi.twitch.tv")
+	traffic.Add(Outbound, "1", "PONG")
+
+	lines := traffic.Lines()
+	require.Len(t, lines, 2)
+	require.Equal(t, "PING :tmi.twitch.tv", lines[0].Line)
+	require.Equal(t, "PONG", lines[1].Line)
+	require.Equal(t, uint64(3), traffic.Written())
+
+	redacted := NewTraffic(1)
+	redacted.Add(Outbound, "1", "PASS oauth:token")
+	require.Equal(t, "PASS <redacted>", redacted.Lines()[0].Line)
+
+	var disabled *Traffic
+	disabled.Add(Inbound, "1", "PING") // recording on a nil traffic is a no-op
+}
+
+func TestSplitTags(t *testing.T) {
+	t.Parallel()
+
+	tags, rest := SplitTags(`@badge-info=;display-name=Viewer;system-msg=hello\sworld\:) :viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #channel :hi`)
+	require.Equal(t, []Tag{
+		{Key: "badge-info", Value: ""},
+		{Key: "display-name", Value: "Viewer"},
+		{Key: "system-msg", Value: "hello world;)"},
+	}, tags)
+	require.Equal(t, ":viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #channel :hi", rest)
+
+	tags, rest = SplitTags("PING :tmi.twitch.tv")
+	require.Nil(t, tags)
+	require.Equal(t, "PING :tmi.twitch.tv", rest)
+}
@@ -0,0 +1,156 @@
+package component
+
+import "slices"
+
+// Hangul compatibility jamo in the order of the Unicode syllable composition
+var (
+	hangulInitials = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	hangulMedials  = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	hangulFinals   = []rune("ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ") // index + 1, 0 is no final
+)
+
+// hangulVowelPairs are the vowels typed as two keys on the 2-set (dubeolsik) layout
+var hangulVowelPairs = map[[2]rune]rune{
+	{'ㅗ', 'ㅏ'}: 'ㅘ',
+	{'ㅗ', 'ㅐ'}: 'ㅙ',
+	{'ㅗ', 'ㅣ'}: 'ㅚ',
+	{'ㅜ', 'ㅓ'}: 'ㅝ',
+	{'ㅜ', 'ㅔ'}: 'ㅞ',
+	{'ㅜ', 'ㅣ'}: 'ㅟ',
+	{'ㅡ', 'ㅣ'}: 'ㅢ',
+}
+
+// hangulFinalPairs are the final consonant clusters typed as two keys
+var hangulFinalPairs = map[[2]rune]rune{
+	{'ㄱ', 'ㅅ'}: 'ㄳ',
+	{'ㄴ', 'ㅈ'}: 'ㄵ',
+	{'ㄴ', 'ㅎ'}: 'ㄶ',
+	{'ㄹ', 'ㄱ'}: 'ㄺ',
+	{'ㄹ', 'ㅁ'}: 'ㄻ',
+	{'ㄹ', 'ㅂ'}: 'ㄼ',
+	{'ㄹ', 'ㅅ'}: 'ㄽ',
+	{'ㄹ', 'ㅌ'}: 'ㄾ',
+	{'ㄹ', 'ㅍ'}: 'ㄿ',
+	{'ㄹ', 'ㅎ'}: 'ㅀ',
+	{'ㅂ', 'ㅅ'}: 'ㅄ',
+}
+
+func isHangulJamo(r rune) bool {
+	return r >= 'ㄱ' && r <= 'ㅣ'
+}
+
+func isHangulVowel(r rune) bool {
+	return r >= 'ㅏ' && r <= 'ㅣ'
+}
+
+// hangulComposer composes the jamo of the 2-set keyboard layout into syllables, for terminals which send the
+// typed jamo instead of composing them with an input method. The syllable being composed is the preedit text.
+type hangulComposer struct {
+	initial, medial, final rune
+	keys                   []rune // jamo typed for the current syllable, replayed on backspace
+}
+
+// feed adds a jamo to the current syllable. The returned text is committed, because the jamo started a new syllable.
+func (h *hangulComposer) feed(r rune) string {
+	var commit string
+
+	if isHangulVowel(r) {
+		switch {
+		case h.final != 0:
+			// the last final consonant moves to the new syllable: 각 + ㅏ = 가가, 닭 + ㅏ = 달가
+			moved := h.final
+			h.final = 0
+
+			for pair, cluster := range hangulFinalPairs {
+				if cluster == moved {
+					h.final, moved = pair[0], pair[1]
+				}
+			}
+
+			commit = h.flush()
+			h.initial, h.medial, h.keys = moved, r, []rune{moved, r}
+
+			return commit
+		case h.medial != 0:
+			if v, ok := hangulVowelPairs[[2]rune{h.medial, r}]; ok {
+				h.medial = v
+				h.keys = append(h.keys, r)
+				return ""
+			}
+
+			commit = h.flush()
+		}
+
+		h.medial = r
+		h.keys = append(h.keys, r)
+
+		return commit
+	}
+
+	switch {
+	case h.medial == 0 || h.initial == 0:
+		commit = h.flush()
+	case h.final == 0 && slices.Contains(hangulFinals, r):
+		h.final = r
+		h.keys = append(h.keys, r)
+		return ""
+	case h.final != 0:
+		if cluster, ok := hangulFinalPairs[[2]rune{h.final, r}]; ok {
+			h.final = cluster
+			h.keys = append(h.keys, r)
+			return ""
+		}
+
+		commit = h.flush()
+	default:
+		commit = h.flush()
+	}
+
+	h.initial = r
+	h.keys = []rune{r}
+
+	return commit
+}
+
+// backspace removes the jamo typed last, returns false if nothing is composed
+func (h *hangulComposer) backspace() bool {
+	if len(h.keys) == 0 {
+		return false
+	}
+
+	keys := h.keys[:len(h.keys)-1]
+	h.flush()
+
+	for _, r := range keys {
+		h.feed(r)
+	}
+
+	return true
+}
+
+// preedit returns the syllable being composed
+func (h *hangulComposer) preedit() string {
+	switch {
+	case h.initial != 0 && h.medial != 0:
+		syllable := 0xAC00 + (slices.Index(hangulInitials, h.initial)*21+slices.Index(hangulMedials, h.medial))*28
+		if h.final != 0 {
+			syllable += slices.Index(hangulFinals, h.final) + 1
+		}
+
+		return string(rune(syllable))
+	case h.initial != 0:
+		return string(h.initial)
+	case h.medial != 0:
+		return string(h.medial)
+	}
+
+	return ""
+}
+
+// flush ends the composition and returns the composed syllable
+func (h *hangulComposer) flush() string {
+	text := h.preedit()
+	*h = hangulComposer{}
+
+	return text
+}
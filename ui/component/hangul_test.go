@@ -0,0 +1,93 @@
+package component
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHangulComposer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		keys        string
+		wantCommit  string
+		wantPreedit string
+	}{
+		"syllable":           {keys: "ㅎㅏㄴ", wantPreedit: "한"},
+		"next syllable":      {keys: "ㅎㅏㄴㄱㅡㄹ", wantCommit: "한", wantPreedit: "글"},
+		"final moves":        {keys: "ㅎㅏㄴㅏ", wantCommit: "하", wantPreedit: "나"},
+		"cluster":            {keys: "ㄷㅏㄹㄱ", wantPreedit: "닭"},
+		"cluster splits":     {keys: "ㄷㅏㄹㄱㅏ", wantCommit: "달", wantPreedit: "가"},
+		"vowel pair":         {keys: "ㄱㅗㅏ", wantPreedit: "과"},
+		"consonants":         {keys: "ㅋㅋㅋ", wantCommit: "ㅋㅋ", wantPreedit: "ㅋ"},
+		"vowel alone":        {keys: "ㅏㄱ", wantCommit: "ㅏ", wantPreedit: "ㄱ"},
+		"no double final":    {keys: "ㄱㅏㄸ", wantCommit: "가", wantPreedit: "ㄸ"},
+		"vowel after vowel":  {keys: "ㄱㅏㅏ", wantCommit: "가", wantPreedit: "ㅏ"},
+		"final after single": {keys: "ㅂㅏㅂㅅ", wantPreedit: "밦"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				h      hangulComposer
+				commit string
+			)
+
+			for _, r := range tt.keys {
+				commit += h.feed(r)
+			}
+
+			require.Equal(t, tt.wantCommit, commit)
+			require.Equal(t, tt.wantPreedit, h.preedit())
+		})
+	}
+}
+
+func TestHangulComposer_backspace(t *testing.T) {
+	t.Parallel()
+
+	var h hangulComposer
+	for _, r := range "ㄷㅏㄹㄱ" {
+		h.feed(r)
+	}
+
+	for _, want := range []string{"달", "다", "ㄷ", ""} {
+		require.True(t, h.backspace())
+		require.Equal(t, want, h.preedit())
+	}
+
+	require.False(t, h.backspace())
+}
+
+func TestSuggestionTextInput_hangulComposition(t *testing.T) {
+	t.Parallel()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.SetHangulComposition(true)
+	s.Focus()
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi ㅎㅏㄴㄱㅡㄹ")})
+	require.Equal(t, "hi 한", s.Value(), "the syllable being composed is not part of the value")
+	require.Equal(t, "글", s.Preedit())
+	require.Contains(t, s.View(), "composing")
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	require.Equal(t, "그", s.Preedit())
+	require.Equal(t, "hi 한", s.Value())
+
+	// other keys end the composition before they are handled
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	require.Equal(t, "hi 한그 ", s.InputModel.Value())
+	require.Empty(t, s.Preedit())
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ㅋ")})
+	s.SetHangulComposition(false)
+	require.Equal(t, "hi 한그 ㅋ", s.Value())
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ㅋ")})
+	require.Equal(t, "hi 한그 ㅋㅋ", s.Value(), "jamo are inserted as typed without composition")
+}
@@ -0,0 +1,71 @@
+package component
+
+import (
+	"slices"
+	"strings"
+)
+
+// InputHistory stores the messages sent through a SuggestionTextInput, oldest entry first.
+// A single history may be shared between multiple inputs.
+type InputHistory struct {
+	entries []string
+	limit   int
+}
+
+// NewInputHistory creates a history pre-filled with entries. Only the newest limit entries are kept, a limit of 0 keeps all entries.
+func NewInputHistory(limit int, entries []string) *InputHistory {
+	h := &InputHistory{
+		limit: limit,
+	}
+
+	for _, e := range entries {
+		h.Add(e)
+	}
+
+	return h
+}
+
+// Add appends a new entry, empty entries and entries equal to the latest entry are ignored.
+func (h *InputHistory) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return
+	}
+
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+		return
+	}
+
+	h.entries = append(h.entries, entry)
+
+	if h.limit > 0 && len(h.entries) > h.limit {
+		h.entries = slices.Delete(h.entries, 0, len(h.entries)-h.limit)
+	}
+}
+
+func (h *InputHistory) Len() int {
+	return len(h.entries)
+}
+
+func (h *InputHistory) At(i int) string {
+	return h.entries[i]
+}
+
+// Entries returns a copy of all entries, oldest entry first.
+func (h *InputHistory) Entries() []string {
+	return slices.Clone(h.entries)
+}
+
+// SearchBackwards returns the index of the newest entry older than before, which contains query (case-insensitive).
+// Returns -1 if no entry matches.
+func (h *InputHistory) SearchBackwards(query string, before int) int {
+	query = strings.ToLower(query)
+
+	for i := min(before, len(h.entries)) - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(h.entries[i]), query) {
+			return i
+		}
+	}
+
+	return -1
+}
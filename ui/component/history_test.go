@@ -0,0 +1,80 @@
+package component
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInputHistory_Add(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		limit   int
+		entries []string
+		want    []string
+	}{
+		{
+			name:    "keeps order",
+			entries: []string{"a", "b", "c"},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "ignores empty entries",
+			entries: []string{"a", "", "   ", "b"},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "ignores consecutive duplicates",
+			entries: []string{"a", "a", "b", "a"},
+			want:    []string{"a", "b", "a"},
+		},
+		{
+			name:    "drops oldest entries over limit",
+			limit:   2,
+			entries: []string{"a", "b", "c"},
+			want:    []string{"b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewInputHistory(tt.limit, tt.entries)
+			if got := h.Entries(); !slices.Equal(got, tt.want) {
+				t.Errorf("Entries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputHistory_SearchBackwards(t *testing.T) {
+	t.Parallel()
+
+	h := NewInputHistory(0, []string{"hello chat", "!song", "Hello streamer", "gg"})
+
+	tests := []struct {
+		name   string
+		query  string
+		before int
+		want   int
+	}{
+		{name: "newest match", query: "hello", before: h.Len(), want: 2},
+		{name: "older match", query: "hello", before: 2, want: 0},
+		{name: "no older match", query: "hello", before: 0, want: -1},
+		{name: "empty query matches newest", query: "", before: h.Len(), want: 3},
+		{name: "no match", query: "kappa", before: h.Len(), want: -1},
+		{name: "before out of range", query: "gg", before: 100, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := h.SearchBackwards(tt.query, tt.before); got != tt.want {
+				t.Errorf("SearchBackwards() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package component
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExpandSnippet replaces the word before the cursor with the text of its snippet.
+// Returns false if there is no snippet with the word as trigger.
+func (s *SuggestionTextInput) ExpandSnippet() bool {
+	if s.SnippetExpander == nil {
+		return false
+	}
+
+	value := []rune(s.InputModel.Value())
+	pos := s.InputModel.Position()
+
+	start := pos
+	for start > 0 && !unicode.IsSpace(value[start-1]) {
+		start--
+	}
+
+	if start == pos {
+		return false
+	}
+
+	text, ok := s.SnippetExpander(string(value[start:pos]))
+	if !ok {
+		return false
+	}
+
+	s.InputModel.SetValue(string(value[:start]) + text + string(value[pos:]))
+	s.InputModel.SetCursor(start + utf8.RuneCountInString(text))
+	s.suggestions = nil
+
+	return true
+}
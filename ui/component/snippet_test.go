@@ -0,0 +1,39 @@
+package component
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestionTextInput_ExpandSnippet(t *testing.T) {
+	t.Parallel()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.SnippetExpander = func(trigger string) (string, bool) {
+		if trigger != ";gg" {
+			return "", false
+		}
+
+		return "gg wp everyone 👏", true
+	}
+	s.Focus()
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("nice ;gg")})
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	require.Equal(t, "nice gg wp everyone 👏 ", s.InputModel.Value())
+
+	// only the word right before the cursor is expanded
+	s.SetValue(";gg; and ;g")
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	require.Equal(t, ";gg; and ;g ", s.InputModel.Value())
+
+	s.SetValue("hi ;gg there")
+	s.InputModel.SetCursor(len([]rune("hi ;gg")))
+	require.True(t, s.ExpandSnippet())
+	require.Equal(t, "hi gg wp everyone 👏 there", s.InputModel.Value())
+	require.Equal(t, len([]rune("hi gg wp everyone 👏")), s.InputModel.Position())
+
+	require.False(t, s.ExpandSnippet())
+}
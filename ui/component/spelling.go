@@ -0,0 +1,156 @@
+package component
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/julez-dev/chatuino/spellcheck"
+)
+
+// misspelledWords returns the words of the input the spell checker doesn't know
+func (s *SuggestionTextInput) misspelledWords() []spellcheck.Word {
+	if s.SpellChecker == nil {
+		return nil
+	}
+
+	var misspelled []spellcheck.Word
+	for _, w := range spellcheck.Words(s.InputModel.Value()) {
+		if _, ok := s.knownWords[strings.ToLower(w.Text)]; ok || s.SpellChecker.Check(w.Text) {
+			continue
+		}
+
+		misspelled = append(misspelled, w)
+	}
+
+	return misspelled
+}
+
+// MisspelledWord returns the misspelled word at the cursor, or the last one before the cursor
+func (s *SuggestionTextInput) MisspelledWord() (spellcheck.Word, bool) {
+	var (
+		word  spellcheck.Word
+		found bool
+	)
+
+	pos := s.InputModel.Position()
+	for _, w := range s.misspelledWords() {
+		if w.Start > pos {
+			break
+		}
+
+		word, found = w, true
+	}
+
+	return word, found
+}
+
+// correctSpelling replaces the misspelled word at or before the cursor with its first correction.
+// Pressing the key again right after, replaces the correction with the next one.
+func (s *SuggestionTextInput) correctSpelling() {
+	if c := s.correction; c != nil && c.value == s.InputModel.Value() && len(c.candidates) > 1 {
+		c.index = (c.index + 1) % len(c.candidates)
+		s.replaceCorrection(c, c.candidates[c.index])
+		return
+	}
+
+	s.correction = nil
+
+	word, ok := s.MisspelledWord()
+	if !ok {
+		return
+	}
+
+	candidates := s.SpellChecker.Suggest(word.Text)
+	if len(candidates) == 0 {
+		return
+	}
+
+	value := s.InputModel.Value()
+	c := &completionCycle{
+		candidates: candidates,
+		start:      len(string([]rune(value)[:word.Start])),
+		text:       word.Text,
+		value:      value,
+	}
+
+	s.replaceCorrection(c, candidates[0])
+	s.correction = c
+}
+
+// replaceCorrection replaces the current text of the correction, the cursor keeps its position relative to the text around it
+func (s *SuggestionTextInput) replaceCorrection(c *completionCycle, text string) {
+	pos := s.InputModel.Position()
+
+	before := c.value[:c.start]
+	after := c.value[c.start+len(c.text):]
+
+	start := utf8.RuneCountInString(before)
+	oldEnd := start + utf8.RuneCountInString(c.text)
+	newEnd := start + utf8.RuneCountInString(text)
+
+	switch {
+	case pos >= oldEnd:
+		pos += newEnd - oldEnd
+	case pos > start:
+		pos = newEnd
+	}
+
+	s.InputModel.SetValue(before + text + after)
+	s.InputModel.SetCursor(pos)
+
+	c.text = text
+	c.value = s.InputModel.Value()
+	s.suggestions = nil
+}
+
+// activeCorrection returns the last correction, if the input didn't change since
+func (s *SuggestionTextInput) activeCorrection() *completionCycle {
+	if s.correction == nil || s.correction.value != s.InputModel.Value() {
+		return nil
+	}
+
+	return s.correction
+}
+
+// renderText renders a part of the input starting at the rune index offset, misspelled words are rendered with MisspelledStyle
+func (s *SuggestionTextInput) renderText(text string, offset int, misspelled []spellcheck.Word) string {
+	if len(misspelled) == 0 || text == "" {
+		return s.InputModel.TextStyle.Render(text)
+	}
+
+	runes := []rune(text)
+
+	var b strings.Builder
+
+	runStart := 0
+	runMisspelled := isMisspelled(offset, misspelled)
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && isMisspelled(offset+i, misspelled) == runMisspelled {
+			continue
+		}
+
+		style := s.InputModel.TextStyle
+		if runMisspelled {
+			style = s.MisspelledStyle
+		}
+
+		b.WriteString(style.Render(string(runes[runStart:i])))
+
+		if i < len(runes) {
+			runStart = i
+			runMisspelled = isMisspelled(offset+i, misspelled)
+		}
+	}
+
+	return b.String()
+}
+
+func isMisspelled(index int, misspelled []spellcheck.Word) bool {
+	for _, w := range misspelled {
+		if index >= w.Start && index < w.Start+utf8.RuneCountInString(w.Text) {
+			return true
+		}
+	}
+
+	return false
+}
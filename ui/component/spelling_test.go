@@ -0,0 +1,59 @@
+package component
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSpellChecker map[string][]string
+
+func (f fakeSpellChecker) Check(word string) bool {
+	_, misspelled := f[strings.ToLower(word)]
+	return !misspelled
+}
+
+func (f fakeSpellChecker) Suggest(word string) []string {
+	return slices.Clone(f[strings.ToLower(word)])
+}
+
+func TestSuggestionTextInput_correctSpelling(t *testing.T) {
+	t.Parallel()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.SpellChecker = fakeSpellChecker{
+		"teh":   {"the", "ten"},
+		"chatt": {"chat"},
+		"kekw":  {"keks"},
+	}
+	s.SetSuggestions([]string{"KEKW"})
+	s.Focus()
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("teh chatt KEKW")})
+
+	word, ok := s.MisspelledWord()
+	require.True(t, ok)
+	require.Equal(t, "chatt", word.Text)
+
+	// the last misspelled word before the cursor is corrected first, emotes are known words
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+	require.Equal(t, "teh chat KEKW", s.InputModel.Value())
+	require.Equal(t, len([]rune("teh chat KEKW")), s.InputModel.Position())
+
+	s.InputModel.SetCursor(1)
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+	require.Equal(t, "the chat KEKW", s.InputModel.Value())
+
+	// pressing again cycles through the corrections
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+	require.Equal(t, "ten chat KEKW", s.InputModel.Value())
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+	require.Equal(t, "the chat KEKW", s.InputModel.Value())
+
+	_, ok = s.MisspelledWord()
+	require.False(t, ok)
+}
@@ -3,10 +3,13 @@ package component
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"reflect"
 	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	trie "github.com/Vivino/go-autocomplete-trie"
 	"github.com/charmbracelet/bubbles/key"
@@ -14,7 +17,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/julez-dev/chatuino/command"
+	"github.com/julez-dev/chatuino/spellcheck"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/mattn/go-runewidth"
 	"github.com/rs/zerolog/log"
 )
@@ -34,6 +39,15 @@ type KeyMap struct {
 	AcceptSuggestion key.Binding
 	NextSuggestion   key.Binding
 	PrevSuggestion   key.Binding
+	ReverseSearch    key.Binding
+	PrevCompletion   key.Binding // cycles backwards through completions after a suggestion was accepted
+	CorrectSpelling  key.Binding // replaces the misspelled word at or before the cursor, pressing again cycles through the corrections
+}
+
+// SpellChecker checks the words of the input, misspelled words are underlined
+type SpellChecker interface {
+	Check(word string) bool
+	Suggest(word string) []string
 }
 
 // DefaultKeyMap is the default set of key bindings for navigating and acting
@@ -42,6 +56,9 @@ var DefaultKeyMap = KeyMap{
 	AcceptSuggestion: key.NewBinding(key.WithKeys("tab")),
 	NextSuggestion:   key.NewBinding(key.WithKeys("down", "ctrl+n")),
 	PrevSuggestion:   key.NewBinding(key.WithKeys("up", "ctrl+p")),
+	ReverseSearch:    key.NewBinding(key.WithKeys("ctrl+r")),
+	PrevCompletion:   key.NewBinding(key.WithKeys("shift+tab")),
+	CorrectSpelling:  key.NewBinding(key.WithKeys("alt+s")),
 }
 
 type SuggestionTextInput struct {
@@ -53,7 +70,7 @@ type SuggestionTextInput struct {
 	suggestionIndex int
 	suggestions     []string
 
-	history                    []string
+	history                    *InputHistory
 	historyIndex               int
 	browsingHistory            bool // true when navigating history with up/down
 	IncludeCommandSuggestions  bool
@@ -61,11 +78,43 @@ type SuggestionTextInput struct {
 	DisableAutoSpaceSuggestion bool
 	DisableHistory             bool
 	EmoteReplacer              Replacer
+	SpellChecker               SpellChecker // optional, words of the suggestions like emotes and users are never misspelled
+
+	// reverse history search (ctrl+r)
+	searchingHistory      bool
+	historySearchQuery    string
+	historySearchIndex    int  // index of the currently matched history entry
+	historySearchFailed   bool // true when no entry matches the query
+	historySearchOriginal string
+
+	// FuzzySuggestions matches the current word fuzzy against all suggestions instead of by prefix.
+	// Matches are ranked by distance, ties keep the order passed to SetSuggestions.
+	FuzzySuggestions bool
+
+	allSuggestions         []string
+	suggestionDescriptions map[string]string // suggestion:description shown next to the suggestion
 
 	customSuggestions map[string]string
 	emoteReplacements map[string]string // emoteText:unicode
 
-	userCache map[string]func(...string) string // [username]render func
+	userCache    map[string]func(...string) string // [username]render func
+	userActivity map[string]userActivity           // [username]activity, used to rank user suggestions
+	userAliases  map[string]string                 // [username]alias, users can be suggested by their alias
+	now          func() time.Time
+
+	// completion is set after a suggestion was accepted, so the accept key can cycle through the other suggestions
+	completion *completionCycle
+
+	// correction is set after a misspelled word was corrected, so the correct key can cycle through the other corrections
+	correction *completionCycle
+	knownWords map[string]struct{} // lower case suggestions, skipped by the spell checker
+
+	// hangul composes typed Hangul jamo into syllables, composition is disabled if nil
+	hangul *hangulComposer
+
+	// SnippetExpander returns the text of the snippet with the trigger. The word before the cursor is replaced
+	// with its snippet when a space is typed, snippets are disabled if nil.
+	SnippetExpander func(trigger string) (string, bool)
 
 	// Multi-line display support
 	maxVisibleLines int // 1 = single line (default), >1 = wrapped multi-line display
@@ -75,6 +124,7 @@ type SuggestionTextInput struct {
 	// Line number styles (only shown when content spans multiple lines)
 	LineNumberStyle        lipgloss.Style // style for non-current line numbers
 	CurrentLineNumberStyle lipgloss.Style // style for current line number (highlighted)
+	MisspelledStyle        lipgloss.Style // style of misspelled words, only used in the multi-line display
 }
 
 func defaultTrie() *trie.Trie {
@@ -106,8 +156,10 @@ func NewSuggestionTextInput(userCache map[string]func(...string) string, customS
 		trie:                      t,
 		KeyMap:                    DefaultKeyMap,
 		InputModel:                input,
-		history:                   []string{},
+		history:                   NewInputHistory(0, nil),
 		userCache:                 userCache,
+		userActivity:              map[string]userActivity{},
+		now:                       time.Now,
 		IncludeCommandSuggestions: true,
 		IncludeModeratorCommands:  false,
 		customSuggestions:         customSuggestions,
@@ -115,6 +167,7 @@ func NewSuggestionTextInput(userCache map[string]func(...string) string, customS
 		maxVisibleLines:           1, // default single-line for backward compat
 		LineNumberStyle:           lipgloss.NewStyle().Faint(true),
 		CurrentLineNumberStyle:    lipgloss.NewStyle().Foreground(lipgloss.Color("212")), // bright highlight
+		MisspelledStyle:           lipgloss.NewStyle().Underline(true),
 	}
 }
 
@@ -130,72 +183,107 @@ func (s *SuggestionTextInput) Update(msg tea.Msg) (*SuggestionTextInput, tea.Cmd
 		_, _ = io.WriteString(os.Stdout, msg.prepare)
 		s.emoteReplacements[msg.word] = msg.replaceCode
 	case tea.KeyMsg:
+		if s.searchingHistory && s.updateHistorySearch(msg) {
+			return s, nil
+		}
+
+		// a bracketed paste arrives as a single key press, it is inserted as one edit instead of being typed
+		if msg.Paste && msg.Type == tea.KeyRunes {
+			s.Paste(string(msg.Runes))
+			return s, nil
+		}
+
+		if s.hangul != nil && s.updateComposition(msg) {
+			return s, nil
+		}
+
 		switch {
 		case msg.String() == "enter" && !s.DisableHistory:
-			s.history = append(s.history, s.InputModel.Value())
-			s.historyIndex = len(s.history)
+			s.history.Add(s.InputModel.Value())
+			s.historyIndex = s.history.Len()
+			s.browsingHistory = false
+			return s, nil
+		case key.Matches(msg, s.KeyMap.ReverseSearch) && !s.DisableHistory:
+			if s.history.Len() == 0 {
+				return s, nil
+			}
+
+			s.searchingHistory = true
 			s.browsingHistory = false
+			s.historySearchQuery = ""
+			s.historySearchIndex = s.history.Len()
+			s.historySearchFailed = false
+			s.historySearchOriginal = s.InputModel.Value()
+			s.suggestions = nil
+
 			return s, nil
 		case key.Matches(msg, s.KeyMap.PrevSuggestion) && (s.InputModel.Value() == "" || s.browsingHistory):
-			if len(s.history) == 0 {
+			if s.history.Len() == 0 {
 				return s, nil
 			}
 			s.historyIndex--
 			s.browsingHistory = true
 
 			if s.historyIndex < 0 {
-				s.historyIndex = len(s.history) - 1
+				s.historyIndex = s.history.Len() - 1
 			}
 
-			if len(s.history) > s.historyIndex {
-				s.SetValue(s.history[s.historyIndex])
+			if s.history.Len() > s.historyIndex {
+				s.SetValue(s.history.At(s.historyIndex))
 				s.InputModel.CursorEnd()
 			}
 
 			return s, nil
 		case key.Matches(msg, s.KeyMap.NextSuggestion) && (s.InputModel.Value() == "" || s.browsingHistory):
-			if len(s.history) == 0 {
+			if s.history.Len() == 0 {
 				return s, nil
 			}
 			s.historyIndex++
 			s.browsingHistory = true
 
-			if s.historyIndex >= len(s.history) {
+			if s.historyIndex >= s.history.Len() {
 				s.historyIndex = 0
 			}
 
-			if len(s.history) > s.historyIndex {
-				s.SetValue(s.history[s.historyIndex])
+			if s.history.Len() > s.historyIndex {
+				s.SetValue(s.history.At(s.historyIndex))
 				s.InputModel.CursorEnd()
 			}
 
 			return s, nil
 		case key.Matches(msg, s.KeyMap.AcceptSuggestion):
+			// pressing accept again right after accepting, replaces the accepted suggestion with the next one
+			if s.cycleCompletion(1) {
+				return s, nil
+			}
+
 			// If we can accept a suggestion, do it
 			if s.canAcceptSuggestion() {
 				_, startIndex, endIndex := selectWordAtIndex(s.InputModel.Value(), s.InputModel.Position())
 				before := s.InputModel.Value()[:startIndex]
 				after := s.InputModel.Value()[endIndex:]
-				suggestion := s.suggestions[s.suggestionIndex]
-
-				// if the suggestion is in custom suggestions, replace with custom suggestion text
-				if s.customSuggestions != nil {
-					if customSuggestion, ok := s.customSuggestions[suggestion]; ok {
-						suggestion = customSuggestion
-					}
-				}
-
-				// add space on non command suggestions
-				if !strings.HasPrefix(suggestion, "/") && !s.DisableAutoSpaceSuggestion {
-					suggestion = suggestion + " "
-				}
+				suggestion := s.completionText(s.suggestions[s.suggestionIndex])
 
 				s.InputModel.SetValue(before + suggestion + after)
-				s.InputModel.SetCursor(len(before) + len(suggestion)) // set cursor to end of suggestion + 1 for space
+				s.InputModel.SetCursor(len([]rune(before + suggestion))) // set cursor to end of suggestion + 1 for space
+
+				s.completion = &completionCycle{
+					candidates: slices.Clone(s.suggestions),
+					index:      s.suggestionIndex,
+					start:      len(before),
+					text:       suggestion,
+					value:      s.InputModel.Value(),
+				}
 			}
 			// Always return when AcceptSuggestion key is pressed - don't fall through to default
 			// This prevents the key from being inserted as text when there's no suggestion
 			return s, nil
+		case key.Matches(msg, s.KeyMap.PrevCompletion):
+			s.cycleCompletion(-1)
+			return s, nil
+		case s.SpellChecker != nil && key.Matches(msg, s.KeyMap.CorrectSpelling):
+			s.correctSpelling()
+			return s, nil
 		case key.Matches(msg, s.KeyMap.NextSuggestion):
 			s.nextSuggestion()
 
@@ -211,6 +299,15 @@ func (s *SuggestionTextInput) Update(msg tea.Msg) (*SuggestionTextInput, tea.Cmd
 				return s, s.loadEmoteImageCommand()
 			}
 		default:
+			// pasted text may contain line breaks, which would be rejected by the input validation
+			if msg.Type == tea.KeyRunes {
+				msg.Runes = sanitizeInputRunes(msg.Runes)
+			}
+
+			if msg.Type == tea.KeySpace {
+				s.ExpandSnippet()
+			}
+
 			s.InputModel, cmd = s.InputModel.Update(msg)
 			s.updateSuggestions()
 			s.browsingHistory = false // exit history mode when typing
@@ -229,6 +326,203 @@ func (s *SuggestionTextInput) Update(msg tea.Msg) (*SuggestionTextInput, tea.Cmd
 	return s, cmd
 }
 
+type completionCycle struct {
+	candidates []string
+	index      int
+	start      int    // byte index of the inserted completion
+	text       string // the inserted completion
+	value      string // input value after the completion was inserted
+}
+
+// completionText returns the text inserted into the input, when accepting the suggestion
+func (s *SuggestionTextInput) completionText(suggestion string) string {
+	// if the suggestion is in custom suggestions, replace with custom suggestion text
+	if s.customSuggestions != nil {
+		if customSuggestion, ok := s.customSuggestions[suggestion]; ok {
+			suggestion = customSuggestion
+		}
+	}
+
+	// add space on non command suggestions
+	if !strings.HasPrefix(suggestion, "/") && !s.DisableAutoSpaceSuggestion {
+		suggestion = suggestion + " "
+	}
+
+	return suggestion
+}
+
+// cycleCompletion replaces the last accepted completion with the next (delta 1) or previous (delta -1) candidate.
+// Returns false if there is no completion to cycle, because the input changed since.
+func (s *SuggestionTextInput) cycleCompletion(delta int) bool {
+	c := s.completion
+	if c == nil || c.value != s.InputModel.Value() || len(c.candidates) < 2 {
+		s.completion = nil
+		return false
+	}
+
+	c.index = (c.index + delta + len(c.candidates)) % len(c.candidates)
+	text := s.completionText(c.candidates[c.index])
+
+	before := c.value[:c.start]
+	after := c.value[c.start+len(c.text):]
+
+	s.InputModel.SetValue(before + text + after)
+	s.InputModel.SetCursor(len([]rune(before + text)))
+
+	c.text = text
+	c.value = s.InputModel.Value()
+
+	return true
+}
+
+// updateHistorySearch handles key presses while in reverse history search.
+// Returns false if the key ended the search and should be handled like a normal key press, the matched entry is kept as value.
+func (s *SuggestionTextInput) updateHistorySearch(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, s.KeyMap.ReverseSearch):
+		// search for the next older match
+		s.searchHistory(s.historySearchIndex)
+		return true
+	case msg.Type == tea.KeyEsc:
+		s.searchingHistory = false
+		s.SetValue(s.historySearchOriginal)
+		return true
+	case msg.Type == tea.KeyBackspace:
+		queryRunes := []rune(s.historySearchQuery)
+		if len(queryRunes) > 0 {
+			s.historySearchQuery = string(queryRunes[:len(queryRunes)-1])
+			s.searchHistory(s.history.Len())
+		}
+		return true
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		s.historySearchQuery += string(msg.Runes)
+		// the current match is still preferred if it contains the extended query
+		s.searchHistory(min(s.historySearchIndex+1, s.history.Len()))
+		return true
+	}
+
+	s.searchingHistory = false
+	s.InputModel.CursorEnd()
+	return false
+}
+
+func (s *SuggestionTextInput) searchHistory(before int) {
+	i := s.history.SearchBackwards(s.historySearchQuery, before)
+	if i == -1 {
+		s.historySearchFailed = true
+		return
+	}
+
+	s.historySearchFailed = false
+	s.historySearchIndex = i
+	s.InputModel.SetValue(s.history.At(i))
+	s.InputModel.CursorEnd()
+}
+
+// SetHangulComposition enables composing typed Hangul jamo into syllables, for terminals without an input method.
+// The syllable being composed is shown above the input and only inserted once it is complete.
+func (s *SuggestionTextInput) SetHangulComposition(enabled bool) {
+	switch {
+	case enabled && s.hangul == nil:
+		s.hangul = &hangulComposer{}
+	case !enabled && s.hangul != nil:
+		s.CommitPreedit()
+		s.hangul = nil
+	}
+}
+
+// HangulComposition reports whether typed Hangul jamo are composed into syllables
+func (s *SuggestionTextInput) HangulComposition() bool {
+	return s.hangul != nil
+}
+
+// Preedit returns the text being composed, which is not part of the value yet
+func (s *SuggestionTextInput) Preedit() string {
+	if s.hangul == nil {
+		return ""
+	}
+
+	return s.hangul.preedit()
+}
+
+// CommitPreedit inserts the text being composed at the cursor
+func (s *SuggestionTextInput) CommitPreedit() {
+	if s.hangul == nil {
+		return
+	}
+
+	s.insertText(s.hangul.flush())
+}
+
+// updateComposition composes the jamo of typed text and removes composed jamo on backspace.
+// Returns false if the key ends the composition and should be handled like a normal key press.
+func (s *SuggestionTextInput) updateComposition(msg tea.KeyMsg) bool {
+	switch {
+	case msg.Type == tea.KeyRunes && !msg.Alt && slices.ContainsFunc(msg.Runes, isHangulJamo):
+		// fast typing may arrive as a single key press with multiple runes
+		for _, r := range msg.Runes {
+			if isHangulJamo(r) {
+				s.insertText(s.hangul.feed(r))
+				continue
+			}
+
+			s.insertText(s.hangul.flush() + string(sanitizeInputRunes([]rune{r})))
+		}
+
+		return true
+	case msg.Type == tea.KeyBackspace && s.hangul.backspace():
+		return true
+	}
+
+	s.CommitPreedit()
+	return false
+}
+
+// insertText inserts text at the cursor, like it was typed
+func (s *SuggestionTextInput) insertText(text string) {
+	if text == "" {
+		return
+	}
+
+	s.InputModel, _ = s.InputModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+	s.updateSuggestions()
+	s.browsingHistory = false
+}
+
+// Paste inserts text at the cursor as a single edit. Unlike typed text, pasted Hangul jamo are not composed,
+// snippets are not expanded and no suggestions are opened for the last pasted word.
+func (s *SuggestionTextInput) Paste(text string) {
+	s.CommitPreedit()
+
+	s.InputModel, _ = s.InputModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: sanitizeInputRunes([]rune(text)), Paste: true})
+	s.suggestions = nil
+	s.suggestionIndex = 0
+	s.completion = nil
+	s.correction = nil
+	s.browsingHistory = false
+}
+
+// IsSearchingHistory reports whether the input is currently in reverse history search.
+func (s *SuggestionTextInput) IsSearchingHistory() bool {
+	return s.searchingHistory
+}
+
+// SetHistory replaces the sent message history, a history can be shared between inputs.
+func (s *SuggestionTextInput) SetHistory(history *InputHistory) {
+	s.history = history
+	s.historyIndex = history.Len()
+	s.browsingHistory = false
+}
+
+// SetCustomSuggestions replaces the custom commands, mapping the trigger to its replacement
+func (s *SuggestionTextInput) SetCustomSuggestions(customSuggestions map[string]string) {
+	s.customSuggestions = customSuggestions
+}
+
+func (s *SuggestionTextInput) History() *InputHistory {
+	return s.history
+}
+
 func (s *SuggestionTextInput) loadEmoteImageCommand() tea.Cmd {
 	suggestion := s.suggestions[s.suggestionIndex]
 
@@ -237,7 +531,7 @@ func (s *SuggestionTextInput) loadEmoteImageCommand() tea.Cmd {
 		return nil
 	}
 
-	if _, ok := s.userCache[strings.TrimPrefix(suggestion, "@")]; ok {
+	if _, ok := s.userCache[strings.ToLower(strings.TrimPrefix(suggestion, "@"))]; ok {
 		return nil
 	}
 
@@ -271,19 +565,45 @@ func (s *SuggestionTextInput) View() string {
 		inputView = s.InputModel.View()
 	}
 
+	if s.searchingHistory {
+		label := "reverse-i-search"
+		if s.historySearchFailed {
+			label = "failing reverse-i-search"
+		}
+
+		return fmt.Sprintf(" %s\n%s", lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(%s)`%s'", label, s.historySearchQuery)), inputView)
+	}
+
+	if preedit := s.Preedit(); preedit != "" {
+		return fmt.Sprintf(" %s %s\n%s", lipgloss.NewStyle().Underline(true).Render(preedit), lipgloss.NewStyle().Faint(true).Render("(composing)"), inputView)
+	}
+
+	if c := s.activeCorrection(); c != nil {
+		return fmt.Sprintf(" %s (%d/%d)\n%s", c.candidates[c.index], c.index+1, len(c.candidates), inputView)
+	}
+
 	if s.canAcceptSuggestion() {
 		suggestion := s.suggestions[s.suggestionIndex]
 
 		// If the suggestion is a username, render it with the users color function
-		if renderFunc, ok := s.userCache[strings.TrimPrefix(suggestion, "@")]; ok {
+		login := strings.ToLower(strings.TrimPrefix(suggestion, "@"))
+		if renderFunc, ok := s.userCache[login]; ok {
 			suggestion = renderFunc(suggestion)
 		}
 
+		if alias, ok := s.userAliases[login]; ok {
+			suggestion = fmt.Sprintf("%s (%s)", suggestion, alias)
+		}
+
 		// current suggestion is emote and has a relacement
 		if replace, ok := s.emoteReplacements[suggestion]; ok && replace != suggestion {
 			return fmt.Sprintf(" %s %s (%dx)\n%s", suggestion, replace, len(s.suggestions), inputView)
 		}
 
+		if description, ok := s.suggestionDescriptions[s.suggestions[s.suggestionIndex]]; ok && description != "" {
+			return fmt.Sprintf(" %s %s (%dx)\n%s", suggestion, description, len(s.suggestions), inputView)
+		}
+
 		return fmt.Sprintf(" %s (%dx)\n%s", suggestion, len(s.suggestions), inputView)
 	}
 
@@ -291,6 +611,8 @@ func (s *SuggestionTextInput) View() string {
 }
 
 func (s *SuggestionTextInput) Blur() {
+	s.searchingHistory = false
+	s.CommitPreedit()
 	s.InputModel.Blur()
 }
 
@@ -355,11 +677,32 @@ func (s *SuggestionTextInput) SetSuggestions(suggestions []string) {
 	trie.Insert(sugg...)
 
 	s.trie = trie
+	s.allSuggestions = sugg
+
+	s.knownWords = make(map[string]struct{}, len(sugg))
+	for _, suggestion := range sugg {
+		s.knownWords[strings.ToLower(strings.TrimPrefix(suggestion, "@"))] = struct{}{}
+	}
 
 	s.suggestionIndex = 0
 	s.updateSuggestions()
 }
 
+// SetUserAliases sets the local aliases of users by login. Users can be suggested by their alias, the suggestion is still the login.
+func (s *SuggestionTextInput) SetUserAliases(aliases map[string]string) {
+	s.userAliases = make(map[string]string, len(aliases))
+	for login, alias := range aliases {
+		s.userAliases[strings.ToLower(login)] = alias
+	}
+
+	s.updateSuggestions()
+}
+
+// SetSuggestionDescriptions sets additional information rendered next to the current suggestion.
+func (s *SuggestionTextInput) SetSuggestionDescriptions(descriptions map[string]string) {
+	s.suggestionDescriptions = descriptions
+}
+
 func (s *SuggestionTextInput) SetValue(val string) {
 	s.InputModel.SetValue(val)
 	s.InputModel.CursorEnd()
@@ -372,8 +715,8 @@ func (s *SuggestionTextInput) canAcceptSuggestion() bool {
 	word, _, _ := selectWordAtIndex(tiVal, s.InputModel.Position())
 
 	// only show if the current word is longer than 2 characters and the suggestion is different from the current word
-	// or if the current word is a command
-	return (len(word) > 2 || strings.HasPrefix(tiVal, "/")) && len(s.suggestions) > 0 && s.suggestions[s.suggestionIndex] != word
+	// or if the current word is a command or a mention
+	return (len(word) > 2 || strings.HasPrefix(tiVal, "/") || strings.HasPrefix(word, "@")) && len(s.suggestions) > 0 && s.suggestions[s.suggestionIndex] != word
 }
 
 func (s *SuggestionTextInput) updateSuggestions() {
@@ -388,7 +731,12 @@ func (s *SuggestionTextInput) updateSuggestions() {
 		return
 	}
 
-	matches := s.trie.SearchAll(currWord)
+	var matches []string
+	if s.FuzzySuggestions {
+		matches = s.fuzzyMatches(currWord)
+	} else {
+		matches = s.trie.SearchAll(currWord)
+	}
 
 	if !reflect.DeepEqual(matches, s.suggestions) {
 		s.suggestionIndex = 0
@@ -423,45 +771,75 @@ func (s *SuggestionTextInput) updateSuggestions() {
 		}
 	}
 
-	// sort suggestions by word length
-	slices.SortFunc(s.suggestions, func(a, b string) int {
-		if len(a) == len(b) {
-			return strings.Compare(a, b)
-		}
+	// sort suggestions by word length, fuzzy matches are already ranked
+	if !s.FuzzySuggestions {
+		slices.SortFunc(s.suggestions, func(a, b string) int {
+			if len(a) == len(b) {
+				return strings.Compare(a, b)
+			}
 
-		return len(a) - len(b)
-	})
+			return len(a) - len(b)
+		})
+	}
 
 	// If the current word is a user, add user suggestions to suggestions (with @ prefix)
+	// Users are ranked by how recent and often they wrote in chat
 	if strings.HasPrefix(currWord, "@") {
-		var matchedUsers []string
+		query := strings.ToLower(currWord[1:])
+		matched := map[string]struct{}{}
 
 		for user := range s.userCache {
-			if strings.Contains(user, strings.ToLower(currWord[1:])) {
-				// if the current word is a command, don't add the @ prefix, since commands don't support it
-				// else add mention (@) prefix, so the target user gets a notification
-				if strings.HasPrefix(s.InputModel.Value(), "/") {
-					matchedUsers = append(matchedUsers, user)
-				} else {
-					matchedUsers = append(matchedUsers, "@"+user)
-				}
+			if strings.Contains(user, query) {
+				matched[user] = struct{}{}
 			}
 		}
 
-		slices.SortFunc(matchedUsers, func(a, b string) int {
-			// sorty by length
-			// if same length, sort alphabetically
-			if len(a) == len(b) {
-				return strings.Compare(a, b)
+		for user := range s.userActivity {
+			if strings.Contains(user, query) {
+				matched[user] = struct{}{}
 			}
+		}
 
-			return len(a) - len(b)
-		})
+		for user, alias := range s.userAliases {
+			if strings.Contains(strings.ToLower(alias), query) {
+				matched[user] = struct{}{}
+			}
+		}
 
-		s.suggestions = append(s.suggestions, matchedUsers...)
+		logins := slices.Collect(maps.Keys(matched))
+		s.rankUsers(logins, query)
+
+		for _, login := range logins {
+			// if the current word is a command, don't add the @ prefix, since commands don't support it
+			// else add mention (@) prefix, so the target user gets a notification
+			if strings.HasPrefix(s.InputModel.Value(), "/") {
+				s.suggestions = append(s.suggestions, s.userSuggestion(login))
+			} else {
+				s.suggestions = append(s.suggestions, "@"+s.userSuggestion(login))
+			}
+		}
 	}
 }
 
+func (s *SuggestionTextInput) fuzzyMatches(word string) []string {
+	ranks := fuzzy.RankFindNormalizedFold(word, s.allSuggestions)
+
+	slices.SortFunc(ranks, func(a, b fuzzy.Rank) int {
+		if a.Distance == b.Distance {
+			return a.OriginalIndex - b.OriginalIndex
+		}
+
+		return a.Distance - b.Distance
+	})
+
+	matches := make([]string, 0, len(ranks))
+	for _, r := range ranks {
+		matches = append(matches, r.Target)
+	}
+
+	return matches
+}
+
 func (s *SuggestionTextInput) nextSuggestion() {
 	s.suggestionIndex = s.suggestionIndex + 1
 	if s.suggestionIndex >= len(s.suggestions) {
@@ -476,6 +854,30 @@ func (s *SuggestionTextInput) previousSuggestion() {
 	}
 }
 
+// sanitizeInputRunes replaces line breaks and tabs with a single space each.
+func sanitizeInputRunes(runes []rune) []rune {
+	sanitized := make([]rune, 0, len(runes))
+
+	for i, r := range runes {
+		switch r {
+		case '\r':
+			sanitized = append(sanitized, ' ')
+		case '\n':
+			// \r\n was already replaced
+			if i > 0 && runes[i-1] == '\r' {
+				continue
+			}
+			sanitized = append(sanitized, ' ')
+		case '\t':
+			sanitized = append(sanitized, ' ')
+		default:
+			sanitized = append(sanitized, r)
+		}
+	}
+
+	return sanitized
+}
+
 // selectWordAtIndex returns the word at the given rune index, along with byte start/end indices.
 // The index parameter is a rune position (as returned by textinput.Model.Position()).
 // Returns the word, byte start index, and byte end index for use with string slicing.
@@ -696,6 +1098,14 @@ func (s *SuggestionTextInput) renderMultiLineView() string {
 	showUpArrow := s.viewOffset > 0
 	showDownArrow := endLine < totalLines
 
+	// rune index of the first visible line, lines contain every rune of the value
+	lineOffset := 0
+	for _, line := range lines[:s.viewOffset] {
+		lineOffset += utf8.RuneCountInString(line)
+	}
+
+	misspelled := s.misspelledWords()
+
 	// Render each visible line
 	var result strings.Builder
 	promptPadding := strings.Repeat(" ", promptWidth)
@@ -729,10 +1139,12 @@ func (s *SuggestionTextInput) renderMultiLineView() string {
 
 		// Render line content with cursor if this is the cursor line
 		if actualLineIdx == cursorLine {
-			result.WriteString(s.renderLineWithCursor(line, cursorCol))
+			result.WriteString(s.renderLineWithCursor(line, cursorCol, lineOffset, misspelled))
 		} else {
-			result.WriteString(s.InputModel.TextStyle.Render(line))
+			result.WriteString(s.renderText(line, lineOffset, misspelled))
 		}
+
+		lineOffset += utf8.RuneCountInString(line)
 	}
 
 	// Add scroll indicators
@@ -764,14 +1176,15 @@ func (s *SuggestionTextInput) cursorStyle() lipgloss.Style {
 }
 
 // renderLineWithCursor renders a single line with the cursor at the specified column.
-func (s *SuggestionTextInput) renderLineWithCursor(line string, cursorCol int) string {
+// offset is the rune index of the line in the value, used to mark misspelled words.
+func (s *SuggestionTextInput) renderLineWithCursor(line string, cursorCol int, offset int, misspelled []spellcheck.Word) string {
 	runes := []rune(line)
 	lineLen := len(runes)
 	curStyle := s.cursorStyle()
 
 	// Cursor at end of line
 	if cursorCol >= lineLen {
-		rendered := s.InputModel.TextStyle.Render(line)
+		rendered := s.renderText(line, offset, misspelled)
 		if s.InputModel.Focused() && !s.InputModel.Cursor.Blink {
 			rendered += curStyle.Render(" ")
 		}
@@ -784,7 +1197,7 @@ func (s *SuggestionTextInput) renderLineWithCursor(line string, cursorCol int) s
 	after := string(runes[cursorCol+1:])
 
 	var result strings.Builder
-	result.WriteString(s.InputModel.TextStyle.Render(before))
+	result.WriteString(s.renderText(before, offset, misspelled))
 
 	if s.InputModel.Focused() && !s.InputModel.Cursor.Blink {
 		result.WriteString(curStyle.Render(cursorRune))
@@ -792,7 +1205,7 @@ func (s *SuggestionTextInput) renderLineWithCursor(line string, cursorCol int) s
 		result.WriteString(s.InputModel.TextStyle.Render(cursorRune))
 	}
 
-	result.WriteString(s.InputModel.TextStyle.Render(after))
+	result.WriteString(s.renderText(after, offset+cursorCol+1, misspelled))
 
 	return result.String()
 }
@@ -3,6 +3,9 @@ package component
 import (
 	"strings"
 	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSuggestionTextInput_wrapTextPreservingSpaces(t *testing.T) {
@@ -432,3 +435,127 @@ func TestSuggestionTextInput_lineNumberWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestSuggestionTextInput_fuzzyMatches(t *testing.T) {
+	t.Parallel()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.FuzzySuggestions = true
+	s.SetSuggestions([]string{"xqc", "lirik", "sodapoppin", "lec", "lirikk"})
+
+	tests := []struct {
+		name string
+		word string
+		want []string
+	}{
+		{"exact prefix ranks first", "lirik", []string{"lirik", "lirikk"}},
+		{"non prefix match", "rik", []string{"lirik", "lirikk"}},
+		{"case insensitive", "SODA", []string{"sodapoppin"}},
+		{"subsequence match", "sdpn", []string{"sodapoppin"}},
+		{"ranked by distance", "l", []string{"lec", "lirik", "lirikk"}},
+		{"ties keep input order", "c", []string{"xqc", "lec"}},
+		{"no match", "zzz", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := s.fuzzyMatches(tt.word)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("fuzzyMatches(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionTextInput_reverseHistorySearch(t *testing.T) {
+	t.Parallel()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.SetHistory(NewInputHistory(0, []string{"hello chat", "!song", "hello streamer"}))
+	s.Focus()
+	s.SetValue("draft")
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if !s.IsSearchingHistory() {
+		t.Fatalf("expected history search to be active")
+	}
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hel")})
+	if got := s.InputModel.Value(); got != "hello streamer" {
+		t.Errorf("value after query = %q, want %q", got, "hello streamer")
+	}
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if got := s.InputModel.Value(); got != "hello chat" {
+		t.Errorf("value after next search = %q, want %q", got, "hello chat")
+	}
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if s.IsSearchingHistory() {
+		t.Errorf("expected history search to be canceled")
+	}
+
+	if got := s.InputModel.Value(); got != "draft" {
+		t.Errorf("value after cancel = %q, want %q", got, "draft")
+	}
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("song")})
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if s.IsSearchingHistory() {
+		t.Errorf("expected history search to end on enter")
+	}
+
+	if got := s.InputModel.Value(); got != "!song" {
+		t.Errorf("value after enter = %q, want %q", got, "!song")
+	}
+}
+
+func TestSanitizeInputRunes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no line breaks", input: "hello chat", want: "hello chat"},
+		{name: "unix line breaks", input: "hello\nchat\n", want: "hello chat "},
+		{name: "windows line breaks", input: "hello\r\nchat", want: "hello chat"},
+		{name: "old mac line breaks", input: "hello\rchat", want: "hello chat"},
+		{name: "tabs", input: "hello\tchat", want: "hello chat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := string(sanitizeInputRunes([]rune(tt.input))); got != tt.want {
+				t.Errorf("sanitizeInputRunes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionTextInput_Paste(t *testing.T) {
+	t.Parallel()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.SetSuggestions([]string{"Kappa", "KappaPride"})
+	s.SnippetExpander = func(trigger string) (string, bool) {
+		return "expanded", trigger == ";gg"
+	}
+	s.SetHangulComposition(true)
+	s.Focus()
+
+	s.SetValue("say ")
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(";gg ㅎㅏ\nKap"), Paste: true})
+
+	require.Equal(t, "say ;gg ㅎㅏ Kap", s.InputModel.Value())
+	require.Equal(t, len([]rune(s.InputModel.Value())), s.InputModel.Position())
+	require.Empty(t, s.suggestions, "no suggestions are opened for the pasted word")
+
+	// typing after the paste works like before
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	require.NotEmpty(t, s.suggestions)
+}
@@ -0,0 +1,108 @@
+package component
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// activityHalfLife is the duration after which a message counts half as much for the ranking
+	activityHalfLife = time.Minute * 5
+	maxTrackedUsers  = 1000
+)
+
+type userActivity struct {
+	displayName string
+	count       int
+	lastSeen    time.Time
+}
+
+// score ranks users by how often and how recently they wrote messages
+func (a userActivity) score(now time.Time) float64 {
+	age := max(now.Sub(a.lastSeen), 0)
+	return float64(a.count) * math.Pow(0.5, float64(age)/float64(activityHalfLife))
+}
+
+// RecordUserActivity records that a user wrote a message, active users are ranked higher in user suggestions.
+func (s *SuggestionTextInput) RecordUserActivity(login, displayName string, at time.Time) {
+	login = strings.ToLower(login)
+	if login == "" {
+		return
+	}
+
+	activity, ok := s.userActivity[login]
+	if !ok && len(s.userActivity) >= maxTrackedUsers {
+		s.forgetLeastRecentUser()
+	}
+
+	activity.count++
+	if at.After(activity.lastSeen) {
+		activity.lastSeen = at
+	}
+
+	// only keep display names which only differ in casing, localized display names can't be used for mentions
+	if strings.EqualFold(displayName, login) {
+		activity.displayName = displayName
+	}
+
+	s.userActivity[login] = activity
+}
+
+func (s *SuggestionTextInput) forgetLeastRecentUser() {
+	var (
+		oldestLogin string
+		oldest      time.Time
+	)
+
+	for login, a := range s.userActivity {
+		if oldestLogin == "" || a.lastSeen.Before(oldest) {
+			oldestLogin = login
+			oldest = a.lastSeen
+		}
+	}
+
+	delete(s.userActivity, oldestLogin)
+}
+
+// userSuggestion returns the name used to suggest a user, preserving the casing of the display name if known
+func (s *SuggestionTextInput) userSuggestion(login string) string {
+	if a, ok := s.userActivity[login]; ok && a.displayName != "" {
+		return a.displayName
+	}
+
+	return login
+}
+
+func (s *SuggestionTextInput) userHasPrefix(login, query string) bool {
+	return strings.HasPrefix(login, query) || strings.HasPrefix(strings.ToLower(s.userAliases[login]), query)
+}
+
+// rankUsers sorts logins matching the query, prefix matches of the login or alias first, then by activity score
+func (s *SuggestionTextInput) rankUsers(logins []string, query string) {
+	now := s.now()
+	query = strings.ToLower(query)
+
+	slices.SortFunc(logins, func(a, b string) int {
+		aPrefix, bPrefix := s.userHasPrefix(a, query), s.userHasPrefix(b, query)
+		if aPrefix != bPrefix {
+			if aPrefix {
+				return -1
+			}
+			return 1
+		}
+
+		if c := cmp.Compare(s.userActivity[b].score(now), s.userActivity[a].score(now)); c != 0 {
+			return c
+		}
+
+		// sort by length, if same length, sort alphabetically
+		if len(a) == len(b) {
+			return strings.Compare(a, b)
+		}
+
+		return len(a) - len(b)
+	})
+}
@@ -0,0 +1,83 @@
+package component
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestionTextInput_rankUsers(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.now = func() time.Time { return now }
+
+	// frequent, but a long time ago
+	for range 10 {
+		s.RecordUserActivity("alice", "Alice", now.Add(-time.Hour))
+	}
+
+	// recent
+	s.RecordUserActivity("albert", "albert", now.Add(-time.Second))
+	s.RecordUserActivity("bob", "bob", now)
+
+	logins := []string{"bob", "alice", "albert", "malfoy", "al"}
+	s.rankUsers(logins, "al")
+
+	// prefix matches first, then ranked by activity, inactive users sorted by length
+	require.Equal(t, []string{"albert", "alice", "al", "bob", "malfoy"}, logins)
+}
+
+func TestSuggestionTextInput_userSuggestionCycling(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.now = func() time.Time { return now }
+	s.RecordUserActivity("xqc", "xQc", now)
+	s.RecordUserActivity("xantos", "Xantos", now.Add(-time.Minute))
+	s.RecordUserActivity("localized", "本地化", now) // display name can't be used for mentions
+	s.Focus()
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi @x")})
+	require.Equal(t, []string{"@xQc", "@Xantos"}, s.suggestions)
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, "hi @xQc ", s.InputModel.Value())
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, "hi @Xantos ", s.InputModel.Value())
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	require.Equal(t, "hi @xQc ", s.InputModel.Value())
+
+	s.SetValue("@loc")
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	require.Equal(t, []string{"@localized"}, s.suggestions)
+}
+
+func TestSuggestionTextInput_userAliases(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	s := NewSuggestionTextInput(nil, nil)
+	s.now = func() time.Time { return now }
+	s.RecordUserActivity("xx_longname_xx", "xX_LongName_Xx", now)
+	s.RecordUserActivity("bobby", "Bobby", now.Add(-time.Hour))
+	s.SetUserAliases(map[string]string{"XX_LongName_Xx": "Bob"})
+	s.Focus()
+
+	// the alias is matched, but the login is inserted so the user is mentioned
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi @bob")})
+	require.Equal(t, []string{"@xX_LongName_Xx", "@Bobby"}, s.suggestions)
+	require.Contains(t, s.View(), "@xX_LongName_Xx (Bob)")
+
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, "hi @xX_LongName_Xx ", s.InputModel.Value())
+}
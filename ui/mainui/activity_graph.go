@@ -0,0 +1,126 @@
+package mainui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const (
+	activityGraphMinutes = 30               // minutes shown in the chat activity graph, one bar per minute
+	activityGraphRefresh = time.Second * 10 // how often the graph is rendered again, counting messages does not render it
+)
+
+var sparklineBars = []rune("▁▂▃▄▅▆▇█")
+
+type activityGraphTickMessage struct {
+	tabID string
+}
+
+// chatActivity counts the chat messages of a channel per minute for the last activityGraphMinutes minutes
+type chatActivity struct {
+	counts [activityGraphMinutes]int // oldest first, the last bucket is the current minute
+	newest int64                     // unix minute of the last bucket
+}
+
+// advance moves the buckets forward so the last bucket is the given minute
+func (c *chatActivity) advance(minute int64) {
+	shift := minute - c.newest
+	if shift <= 0 {
+		return
+	}
+
+	c.newest = minute
+
+	if shift >= activityGraphMinutes {
+		c.counts = [activityGraphMinutes]int{}
+		return
+	}
+
+	copy(c.counts[:], c.counts[shift:])
+	clear(c.counts[activityGraphMinutes-shift:])
+}
+
+// record counts a message sent at the given time, messages older than the graph are ignored
+func (c *chatActivity) record(at time.Time) {
+	minute := at.Unix() / 60
+	c.advance(minute)
+
+	i := activityGraphMinutes - 1 - int(c.newest-minute)
+	if i < 0 {
+		return
+	}
+
+	c.counts[i]++
+}
+
+// sparkline renders the messages per minute as bars scaled to the busiest minute, followed by the messages of the last full minute.
+// Empty without any messages in the graph.
+func (c *chatActivity) sparkline(now time.Time) string {
+	c.advance(now.Unix() / 60)
+
+	peak := 0
+	for _, count := range c.counts {
+		peak = max(peak, count)
+	}
+
+	if peak == 0 {
+		return ""
+	}
+
+	b := strings.Builder{}
+	for _, count := range c.counts {
+		// rounded up, so minutes with any message stand out from minutes without
+		level := (count*(len(sparklineBars)-1) + peak - 1) / peak
+
+		_, _ = b.WriteRune(sparklineBars[level])
+	}
+
+	return fmt.Sprintf("%s %d/min", b.String(), c.counts[activityGraphMinutes-2])
+}
+
+// recordChatActivity counts a chat message in the activity graph, the graph is shown right away for the first message
+func (t *broadcastTab) recordChatActivity(msg *twitchirc.PrivateMessage) {
+	at := msg.TMISentTS
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	t.activity.record(at)
+
+	if t.streamInfo.activity == "" && t.deps.UserConfig.Settings.StreamInfo.ActivityGraph {
+		t.updateActivityGraph(time.Now())
+	}
+}
+
+// activityGraphTickCommand schedules the next render of the graph. With reduced motion the graph is only rendered when a minute is over,
+// so the bars move once a minute instead of growing while the current minute fills.
+func activityGraphTickCommand(tabID string, reducedMotion bool) tea.Cmd {
+	refresh := activityGraphRefresh
+	if reducedMotion {
+		now := time.Now()
+		refresh = now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+	}
+
+	return tea.Tick(refresh, func(_ time.Time) tea.Msg {
+		return activityGraphTickMessage{tabID: tabID}
+	})
+}
+
+// updateActivityGraph shows the chat activity graph in the stream info
+func (t *broadcastTab) updateActivityGraph(now time.Time) {
+	heightBefore := lipgloss.Height(t.streamInfo.View())
+
+	t.streamInfo.activity = ""
+	if t.deps.UserConfig.Settings.StreamInfo.ActivityGraph {
+		t.streamInfo.activity = t.activity.sparkline(now)
+	}
+
+	if lipgloss.Height(t.streamInfo.View()) != heightBefore {
+		t.HandleResize()
+	}
+}
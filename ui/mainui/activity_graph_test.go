@@ -0,0 +1,44 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatActivity(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	c := &chatActivity{}
+	require.Empty(t, c.sparkline(start), "no messages")
+
+	for range 7 {
+		c.record(start)
+	}
+	c.record(start.Add(time.Minute))
+	c.record(start.Add(-time.Hour)) // older than the graph
+
+	got := c.sparkline(start.Add(time.Minute + time.Second*30))
+	require.Equal(t, strings.Repeat("▁", activityGraphMinutes-2)+"█▂ 7/min", got)
+
+	// minutes without messages move the activity out of the graph
+	got = c.sparkline(start.Add(time.Minute * 30))
+	require.Equal(t, "█"+strings.Repeat("▁", activityGraphMinutes-1)+" 0/min", got, "scaled to the busiest minute left")
+
+	require.Empty(t, c.sparkline(start.Add(time.Hour)))
+}
+
+func Test_streamInfo_View_activity(t *testing.T) {
+	t.Parallel()
+
+	s := newStreamInfo("1", nil, 120)
+	s.activity = "▁█ 7/min"
+	require.Equal(t, "Chat ▁█ 7/min", strings.TrimSpace(s.View()), "shown on its own while offline")
+
+	s, _ = s.Update(setStreamInfoMessage{target: "1", title: "chill stream", game: "Just Chatting", viewer: 1200, isLive: true, startedAt: time.Now().Add(-time.Hour)})
+	require.Equal(t, "Just Chatting - chill stream (1,200 Viewer, Uptime: 1h 00m) ▁█ 7/min", strings.TrimSpace(s.View()))
+}
@@ -0,0 +1,90 @@
+package mainui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/rs/zerolog/log"
+)
+
+type autoModChecker interface {
+	CheckAutoModStatus(ctx context.Context, broadcasterID string, messages []twitchapi.AutoModCheckMessage) ([]twitchapi.AutoModStatus, error)
+}
+
+type autoModCheckMessage struct {
+	tabID string
+	text  string
+	held  bool
+	err   error
+}
+
+// autoModCheckFor returns the client checking messages in the tab's channel. Twitch only answers the broadcaster, so
+// messages can only be checked when the broadcaster is one of the accounts. Moderators and the broadcaster itself are
+// never held by AutoMod, their messages are not checked.
+func (t *broadcastTab) autoModCheckFor(text string) (autoModChecker, bool) {
+	if !t.deps.UserConfig.Settings.Chat.AutoModCheck || t.isUserMod {
+		return nil, false
+	}
+
+	// the user chose to send the held message anyway
+	if t.autoModSkip != "" && t.autoModSkip == text {
+		t.autoModSkip = ""
+		return nil, false
+	}
+
+	checker, ok := t.deps.APIUserClients[t.channelID].(autoModChecker)
+	return checker, ok
+}
+
+// checkAutoMod asks AutoMod if the message would be held, the message is sent by handleAutoModCheck
+func (t *broadcastTab) checkAutoMod(checker autoModChecker, text string) tea.Cmd {
+	tabID, broadcasterID := t.id, t.channelID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		statuses, err := checker.CheckAutoModStatus(ctx, broadcasterID, []twitchapi.AutoModCheckMessage{{MsgID: "1", MsgText: text}})
+
+		msg := autoModCheckMessage{tabID: tabID, text: text, err: err}
+		for _, status := range statuses {
+			if status.MsgID == "1" {
+				msg.held = !status.IsPermitted
+			}
+		}
+
+		return msg
+	}
+}
+
+// handleAutoModCheck sends the message unless AutoMod would hold it. Held messages are put back into the input,
+// sending the same message again sends it without asking AutoMod.
+func (t *broadcastTab) handleAutoModCheck(msg autoModCheckMessage) tea.Cmd {
+	// a failed check does not keep the message from being sent
+	if msg.err != nil {
+		log.Logger.Warn().Err(msg.err).Str("channel", t.channelLogin).Msg("failed to check message with AutoMod, sending anyway")
+	}
+
+	if !msg.held {
+		return t.delaySend(msg.text, func() tea.Cmd {
+			return t.sendMessage(msg.text)
+		})
+	}
+
+	t.autoModSkip = msg.text
+
+	notice := t.localNotice()
+	text := "AutoMod would hold this message, send it again to send it anyway"
+
+	if t.messageInput.Value() == "" {
+		t.messageInput.SetValue(msg.text)
+		t.HandleResize()
+		text = "AutoMod would hold this message, it was put back into the input. Send it again to send it anyway"
+	}
+
+	return tea.Batch(t.updateDraftIndicator(), func() tea.Msg {
+		return notice(text)
+	})
+}
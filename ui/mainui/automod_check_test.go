@@ -0,0 +1,77 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/ui/component"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAutoModChecker struct {
+	APIClient
+	held map[string]bool
+}
+
+func (f fakeAutoModChecker) CheckAutoModStatus(_ context.Context, broadcasterID string, messages []twitchapi.AutoModCheckMessage) ([]twitchapi.AutoModStatus, error) {
+	if broadcasterID != "22484632" {
+		return nil, errors.New("broadcaster does not match token")
+	}
+
+	statuses := make([]twitchapi.AutoModStatus, 0, len(messages))
+	for _, m := range messages {
+		statuses = append(statuses, twitchapi.AutoModStatus{MsgID: m.MsgID, IsPermitted: !f.held[m.MsgText]})
+	}
+
+	return statuses, nil
+}
+
+func Test_broadcastTab_autoModCheck(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Chat.AutoModCheck = true
+	deps.APIUserClients = map[string]APIClient{"22484632": fakeAutoModChecker{held: map[string]bool{"bad words": true}}}
+
+	tab := &broadcastTab{
+		id:           "tab",
+		channelID:    "22484632",
+		deps:         deps,
+		messageInput: component.NewSuggestionTextInput(nil, nil),
+	}
+
+	checker, ok := tab.autoModCheckFor("hello")
+	require.True(t, ok)
+
+	msg := tab.checkAutoMod(checker, "hello")().(autoModCheckMessage)
+	require.False(t, msg.held)
+	require.NoError(t, msg.err)
+
+	msg = tab.checkAutoMod(checker, "bad words")().(autoModCheckMessage)
+	require.True(t, msg.held)
+
+	// held messages are put back into the input and sent without asking again
+	_, ok = tab.handleAutoModCheck(msg)().(tea.BatchMsg)
+	require.True(t, ok)
+	require.Equal(t, "bad words", tab.messageInput.Value())
+
+	_, ok = tab.autoModCheckFor("bad words")
+	require.False(t, ok)
+
+	_, ok = tab.autoModCheckFor("bad words")
+	require.True(t, ok, "only the next send skips the check")
+
+	// moderators are never held
+	tab.isUserMod = true
+	_, ok = tab.autoModCheckFor("bad words")
+	require.False(t, ok)
+
+	// without the account of the broadcaster messages can't be checked
+	tab.isUserMod = false
+	tab.channelID = "1"
+	_, ok = tab.autoModCheckFor("bad words")
+	require.False(t, ok)
+}
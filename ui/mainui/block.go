@@ -0,0 +1,223 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/blocklist"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// userBlockChangedMessage is sent after a user was blocked or unblocked on Twitch, or the attempt failed
+type userBlockChangedMessage struct {
+	tabID     string // tab which requested the change, empty for the settings editor
+	accountID string
+	user      blocklist.User
+	blocked   bool
+	err       error
+}
+
+// isBlockedSender reports if the account blocked the sender of the message on Twitch
+func isBlockedSender(deps *DependencyContainer, accountID string, msg twitchirc.IRCer) bool {
+	if deps.Blocks == nil {
+		return false
+	}
+
+	switch msg := msg.(type) {
+	case *twitchirc.PrivateMessage:
+		return deps.Blocks.IsBlocked(accountID, msg.UserID)
+	case *twitchirc.SubMessage:
+		return deps.Blocks.IsBlocked(accountID, msg.UserID)
+	case *twitchirc.SubGiftMessage:
+		return deps.Blocks.IsBlocked(accountID, msg.UserID)
+	}
+
+	return false
+}
+
+// setUserBlocked blocks or unblocks the user on Twitch outside of the update loop
+func setUserBlocked(deps *DependencyContainer, tabID, accountID string, user blocklist.User, block bool) tea.Cmd {
+	blocks := deps.Blocks
+	client, ok := deps.APIUserClients[accountID].(blocklist.Client)
+
+	return func() tea.Msg {
+		msg := userBlockChangedMessage{tabID: tabID, accountID: accountID, user: user, blocked: block}
+
+		if blocks == nil || !ok {
+			msg.err = errors.New("blocking users is not available for this account")
+			return msg
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		if block {
+			msg.err = blocks.Block(ctx, client, accountID, user)
+		} else {
+			msg.err = blocks.Unblock(ctx, client, accountID, user.ID)
+		}
+
+		return msg
+	}
+}
+
+// handleBlockCommand runs /block and /unblock. Without a username the inspected user is used.
+func (t *broadcastTab) handleBlockCommand(args []string, block bool) tea.Cmd {
+	tabID, accountID := t.id, t.account.ID
+
+	notice := func(text string) tea.Cmd {
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     tabID,
+				accountID: accountID,
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       text,
+				},
+			}
+		}
+	}
+
+	if t.account.IsAnonymous {
+		return notice("Blocking users is not available for anonymous accounts")
+	}
+
+	if len(args) == 0 || args[0] == "" {
+		if t.userInspect == nil || !t.userInspect.isDataFetched || t.userInspect.userData.ID == "" {
+			command := "/unblock"
+			if block {
+				command = "/block"
+			}
+
+			return notice(fmt.Sprintf("Usage: %s <username>", command))
+		}
+
+		return setUserBlocked(t.deps, tabID, accountID, t.userInspect.blockUser(), block)
+	}
+
+	login := strings.TrimPrefix(strings.ToLower(args[0]), "@")
+	client := t.deps.APIUserClients[accountID]
+	deps := t.deps
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		resp, err := client.GetUsers(ctx, []string{login}, nil)
+		if err == nil && len(resp.Data) == 0 {
+			err = fmt.Errorf("user %s not found", login)
+		}
+
+		if err != nil {
+			return userBlockChangedMessage{
+				tabID:     tabID,
+				accountID: accountID,
+				user:      blocklist.User{Login: login, DisplayName: login},
+				blocked:   block,
+				err:       err,
+			}
+		}
+
+		user := blocklist.User{ID: resp.Data[0].ID, Login: resp.Data[0].Login, DisplayName: resp.Data[0].DisplayName}
+		return setUserBlocked(deps, tabID, accountID, user, block)()
+	}
+}
+
+// handleToggleBlockInspectedUser blocks the inspected user or unblocks an already blocked user
+func (t *broadcastTab) handleToggleBlockInspectedUser() tea.Cmd {
+	if t.deps.Blocks == nil || t.userInspect == nil || !t.userInspect.isDataFetched || t.userInspect.userData.ID == "" {
+		return nil
+	}
+
+	user := t.userInspect.blockUser()
+	return t.handleBlockCommand(nil, !t.deps.Blocks.IsBlocked(t.account.ID, user.ID))
+}
+
+// handleUserBlockChanged hides the messages of a newly blocked user and reports the result in the tab which requested it
+func (t *broadcastTab) handleUserBlockChanged(msg userBlockChangedMessage) tea.Cmd {
+	if msg.accountID != t.account.ID {
+		return nil
+	}
+
+	if msg.err == nil && msg.blocked {
+		t.chatWindow.removeUserMessages(msg.user.ID)
+
+		// keep the messages while the blocked user is inspected
+		if t.userInspect != nil && t.userInspect.userData.ID != msg.user.ID {
+			t.userInspect.chatWindow.removeUserMessages(msg.user.ID)
+		}
+	}
+
+	if msg.tabID != t.id {
+		return nil
+	}
+
+	text := fmt.Sprintf("Blocked %s, their messages are hidden", msg.user.DisplayName)
+	switch {
+	case msg.err != nil && msg.blocked:
+		text = fmt.Sprintf("Failed to block %s: %s", msg.user.DisplayName, blockErrorText(msg.err))
+	case msg.err != nil:
+		text = fmt.Sprintf("Failed to unblock %s: %s", msg.user.DisplayName, blockErrorText(msg.err))
+	case !msg.blocked:
+		text = fmt.Sprintf("Unblocked %s", msg.user.DisplayName)
+	}
+
+	tabID, accountID := t.id, t.account.ID
+	return func() tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+}
+
+// blockUser is the inspected user, only valid once the user data is fetched
+func (u *userInspect) blockUser() blocklist.User {
+	return blocklist.User{ID: u.userData.ID, Login: u.userData.Login, DisplayName: u.userData.DisplayName}
+}
+
+// removeUserMessages removes all messages sent by the user, used after the user was blocked
+func (c *chatWindow) removeUserMessages(userID string) {
+	if userID == "" {
+		return
+	}
+
+	before := len(c.entries)
+	c.entries = slices.DeleteFunc(c.entries, func(e *chatEntry) bool {
+		switch msg := e.Event.message.(type) {
+		case *twitchirc.PrivateMessage:
+			return msg.UserID == userID
+		case *twitchirc.SubMessage:
+			return msg.UserID == userID
+		case *twitchirc.SubGiftMessage:
+			return msg.UserID == userID
+		}
+
+		return false
+	})
+
+	if len(c.entries) != before {
+		c.recalculateLines()
+	}
+}
+
+// blockErrorText explains missing scopes, accounts added before blocking was supported need to be added again
+func blockErrorText(err error) string {
+	var apiErr twitchapi.APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized {
+		return apiErr.Message + ", add the account again to allow Chatuino to block users"
+	}
+
+	return err.Error()
+}
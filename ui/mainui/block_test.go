@@ -0,0 +1,59 @@
+package mainui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julez-dev/chatuino/blocklist"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBlockClient struct{}
+
+func (fakeBlockClient) GetUserBlockList(context.Context, string) ([]twitchapi.BlockedUser, error) {
+	return nil, nil
+}
+
+func (fakeBlockClient) BlockUser(context.Context, string) error {
+	return nil
+}
+
+func (fakeBlockClient) UnblockUser(context.Context, string) error {
+	return nil
+}
+
+func Test_isBlockedSender(t *testing.T) {
+	t.Parallel()
+
+	blocks := blocklist.New()
+	require.NoError(t, blocks.Block(context.Background(), fakeBlockClient{}, "acc", blocklist.User{ID: "10", Login: "spammer"}))
+
+	deps := newTestDeps(t)
+	deps.Blocks = blocks
+
+	tests := []struct {
+		name      string
+		accountID string
+		msg       twitchirc.IRCer
+		want      bool
+	}{
+		{name: "message", accountID: "acc", msg: &twitchirc.PrivateMessage{UserID: "10"}, want: true},
+		{name: "moderator message", accountID: "acc", msg: &twitchirc.PrivateMessage{UserID: "10", Mod: true}, want: true},
+		{name: "sub", accountID: "acc", msg: &twitchirc.SubMessage{UserNotice: twitchirc.UserNotice{UserID: "10"}}, want: true},
+		{name: "sub gift", accountID: "acc", msg: &twitchirc.SubGiftMessage{UserNotice: twitchirc.UserNotice{UserID: "10"}}, want: true},
+		{name: "other user", accountID: "acc", msg: &twitchirc.PrivateMessage{UserID: "11"}},
+		{name: "other account", accountID: "other", msg: &twitchirc.PrivateMessage{UserID: "10"}},
+		{name: "notice", accountID: "acc", msg: &twitchirc.Notice{Message: "hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, isBlockedSender(deps, tt.accountID, tt.msg))
+		})
+	}
+
+	require.False(t, isBlockedSender(newTestDeps(t), "acc", &twitchirc.PrivateMessage{UserID: "10"}))
+}
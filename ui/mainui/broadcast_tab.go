@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"maps"
 	"net/http"
+	"os/exec"
 	"slices"
 	"strconv"
 	"strings"
@@ -38,6 +39,10 @@ import (
 const (
 	streamWebFmt       = "https://player.twitch.tv/?channel=%s&enableExtensions=false&muted=false&parent=chatuino.net&player=popout&quality=chunked&volume=0.2"
 	streamChatPopUpFmt = "https://www.twitch.tv/popout/%s/chat?popout=1"
+
+	messageCharLimit         = 500 // twitch rejects longer chat messages
+	messageCharWarnThreshold = 450
+	maxSplitMessages         = 5 // max number of messages a long input is split into
 )
 
 var modCommandAlternativeMapping = map[string]string{
@@ -58,6 +63,7 @@ type setChannelDataMessage struct {
 	channelID       string
 	initialMessages []twitchirc.IRCer
 	isUserMod       bool
+	notes           []save.Note
 }
 
 type emoteSetRefreshedMessage struct {
@@ -125,11 +131,15 @@ type broadcastTab struct {
 	channelDataLoaded bool
 	lastMessageSent   string
 	lastMessageSentAt time.Time
+	sentMessages      int // number of messages sent from the tab, used to identify pending messages
 
 	channel      string
 	channelID    string
 	channelLogin string
 
+	unread             int    // messages received since the tab lost focus
+	restoredLastReadID string // message read last in the previous session, the marker is placed once it is received again
+
 	width, height int
 	fullWidth     int // full terminal width (for status bar in vertical mode)
 
@@ -142,10 +152,34 @@ type broadcastTab struct {
 	chatWindow    *chatWindow
 	userInspect   *userInspect
 	messageInput  *component.SuggestionTextInput
+	inputHistory  *component.InputHistory // sent messages, may be shared with other tabs
+	draft         string                  // restored unsent input, applied once the message input is created
+	hasDraft      bool                    // last draft state reported to the tab header
+	hangulInput   bool                    // typed Hangul jamo are composed into syllables, for terminals without an input method
+	pendingSend   *pendingSend            // message held back by chat.send_delay
+	pendingSendID int                     // incremented for every held back message
+	autoModSkip   string                  // message AutoMod would hold, which is sent without asking again
+	sendPrompt    *sendPrompt             // asks to shorten the links of the message being sent
+	uploadPrompt  *uploadPrompt           // asks to upload the image pasted into the message input
+	promptSkip    string                  // message sent without asking again
+	player        *exec.Cmd               // running external stream player, nil if none was started
 	statusInfo    *streamStatus
 	emoteOverview *emoteOverview
 	spinner       spinner.Model
 
+	degraded       bool          // emotes or badges could not be fetched, cached emotes are used
+	retryPending   bool          // a background refresh of the degraded tab is scheduled
+	refreshRetryIn time.Duration // delay of the last scheduled background refresh
+
+	timers        []*channelTimer // timers sent to the channel by the account of the tab
+	timersTicking bool            // a timerTickMessage is scheduled
+
+	activity  chatActivity  // messages per minute, shown as a graph in the stream info
+	chatStats *chatStats    // top chatters and emotes since the tab was opened, shown by /chatstats
+	presence  *chatPresence // chatters in the channel, nil while not tracked
+
+	redemptions *redemptionQueue // unfulfilled redemptions, nil unless the tab shows the channel of the account
+
 	err error
 }
 
@@ -170,8 +204,10 @@ func newBroadcastTab(
 		channelLogin: channel, // Initialize from param; updated to canonical value after init
 		lastMessages: cache,
 		deps:         deps,
-		modFetcher:   ivr.NewAPI(http.DefaultClient),
+		inputHistory: component.NewInputHistory(deps.UserConfig.Settings.Session.InputHistorySize, nil),
+		modFetcher:   ivr.NewAPI(deps.httpClient()),
 		spinner:      spinner.New(spinner.WithSpinner(customEllipsisSpinner)),
+		chatStats:    newChatStats(time.Now()),
 	}
 }
 
@@ -227,9 +263,11 @@ func (t *broadcastTab) InitWithUserData(userData twitchapi.UserData) tea.Cmd {
 
 		var isUserMod bool
 		group.Go(func() error {
+			// without the mod list the tab works without mod features instead of failing
 			modVips, err := t.modFetcher.GetModVIPList(ctx, userData.Login)
 			if err != nil {
-				return fmt.Errorf("could not fetch mods for %s (%s): %w", userData.Login, userData.ID, err)
+				log.Logger.Warn().Err(err).Str("channel", userData.Login).Msg("could not fetch mods, continuing without mod features")
+				return nil
 			}
 
 			for _, mod := range modVips.Mods {
@@ -256,6 +294,7 @@ func (t *broadcastTab) InitWithUserData(userData twitchapi.UserData) tea.Cmd {
 			channelLogin:    userData.Login,
 			initialMessages: recentMessages,
 			isUserMod:       isUserMod,
+			notes:           loadNotes(t.deps, save.NoteChannel, userData.Login),
 		}
 	}
 
@@ -267,11 +306,24 @@ func (t *broadcastTab) refreshEmotes(login, channelID string, manually bool) tea
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 		defer cancel()
 
-		group, ctx := errgroup.WithContext(ctx)
+		// a failing fetch doesn't cancel the others, the tab keeps working in degraded mode and retries later
+		var (
+			group                           errgroup.Group
+			errGlobal, errEmotes, errBadges error
+		)
+
+		group.Go(func() error {
+			// only fetched if a previous fetch failed, Root fetches the global emotes on start
+			if err := t.deps.EmoteCache.RefreshGlobal(ctx); err != nil {
+				errGlobal = fmt.Errorf("could not refresh global emotes: %w", err)
+			}
+
+			return nil
+		})
 
 		group.Go(func() error {
 			if err := t.deps.EmoteCache.RefreshLocal(ctx, channelID); err != nil {
-				return fmt.Errorf("could not refresh emote cache for %s (%s): %w", login, channelID, err)
+				errEmotes = fmt.Errorf("could not refresh emote cache for %s (%s): %w", login, channelID, err)
 			}
 
 			return nil
@@ -279,23 +331,28 @@ func (t *broadcastTab) refreshEmotes(login, channelID string, manually bool) tea
 
 		group.Go(func() error {
 			if err := t.deps.BadgeCache.RefreshChannel(ctx, channelID); err != nil {
-				return fmt.Errorf("could not refresh badge cache for %s (%s): %w", login, channelID, err)
+				errBadges = fmt.Errorf("could not refresh badge cache for %s (%s): %w", login, channelID, err)
 			}
 
 			return nil
 		})
 
-		err := group.Wait()
-		if err != nil {
-			return emoteSetRefreshedMessage{
-				targetID: t.id,
-				err:      err,
-				manually: manually,
-			}
+		if t.deps.Bots != nil {
+			group.Go(func() error {
+				// messages of unknown bots are just not marked, so failures are not shown in chat
+				if err := t.deps.Bots.RefreshChannel(ctx, channelID); err != nil {
+					log.Logger.Info().Err(err).Str("channel-id", channelID).Msg("could not refresh bots")
+				}
+
+				return nil
+			})
 		}
 
+		_ = group.Wait()
+
 		return emoteSetRefreshedMessage{
 			targetID: t.id,
+			err:      errors.Join(errGlobal, errEmotes, errBadges),
 			manually: manually,
 		}
 	}
@@ -350,15 +407,33 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 		t.channelLogin = msg.channelLogin
 		t.channelID = msg.channelID
 		t.streamInfo = newStreamInfo(msg.channelID, t.deps.APIUserClients[t.account.ID], t.width)
+		t.streamInfo.notes = msg.notes
 		t.poll = newPoll(t.width)
 		t.chatWindow = newChatWindow(t.width, t.height, t.deps)
+		t.chatWindow.channel = t.channelLogin
+		t.chatWindow.setAccount(t.account)
+		t.chatWindow.setLayout(t.deps.UserConfig.Settings.Chat.LayoutFor(t.channelLogin))
 
-		t.messageInput = component.NewSuggestionTextInput(t.chatWindow.userColorCache, t.deps.UserConfig.Settings.BuildCustomSuggestionMap())
+		t.messageInput = component.NewSuggestionTextInput(t.chatWindow.userColorCache, t.customSuggestions())
+		t.messageInput.SetUserAliases(t.deps.UserConfig.Settings.Chat.UserAliases)
+		t.messageInput.KeyMap = inputKeyMap(t.keymap())
 		t.messageInput.EmoteReplacer = t.deps.EmoteReplacer // enable emote replacement
+		t.messageInput.SpellChecker = t.deps.SpellChecker
+		t.messageInput.SnippetExpander = t.expandSnippet
+		t.messageInput.MisspelledStyle = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color(t.deps.UserConfig.Theme.ChatErrorColor))
 		t.messageInput.InputModel.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.deps.UserConfig.Theme.InputPromptColor))
 		t.messageInput.SetMaxVisibleLines(3) // allow input to grow up to 3 lines
+		t.messageInput.SetHistory(t.inputHistory)
+		t.messageInput.SetValue(t.draft)
+		t.messageInput.SetHangulComposition(t.hangulInput)
+
+		// allow longer messages, they are split into multiple messages when sent
+		if t.deps.UserConfig.Settings.Chat.AutoSplitLongMessages {
+			t.messageInput.InputModel.CharLimit = messageCharLimit * maxSplitMessages
+		}
 
 		t.statusInfo = newStreamStatus(t.width, t.height, t, t.account.ID, msg.channelID, t.deps)
+		cmds = append(cmds, t.updateDraftIndicator(), t.syncTimers(time.Now()), activityGraphTickCommand(t.id, t.deps.UserConfig.Settings.Accessibility.ReducedMotion))
 
 		// set chat suggestions if non-anonymous user
 		if !t.account.IsAnonymous {
@@ -411,6 +486,24 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 
 		cmds = append(cmds, t.refreshEmotes(msg.channelLogin, msg.channelID, false))
 
+		// channel.update needs no authorization, so unlike the events below it is subscribed for every channel.
+		// These subscriptions count against the cost limit of the connection, once it is reached the title and category only change with the stream info refresh.
+		if eventSubAPI, ok := t.deps.APIUserClients[t.account.ID].(wspool.EventSubService); ok {
+			accountID := t.account.ID
+			channelID := msg.channelID
+
+			cmds = append(cmds, func() tea.Msg {
+				t.deps.Pool.SubscribeEventSub(accountID, twitchapi.CreateEventSubSubscriptionRequest{
+					Type:    "channel.update",
+					Version: "2",
+					Condition: map[string]string{
+						"broadcaster_user_id": channelID,
+					},
+				}, eventSubAPI)
+				return nil
+			})
+		}
+
 		// subscribe to channel events
 		//  - if authenticated user
 		//  - if channel belongs to user
@@ -419,7 +512,12 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 			accountID := t.account.ID
 			channelID := msg.channelID
 
-			for _, subType := range [...]string{"channel.poll.begin", "channel.poll.progress", "channel.poll.end", "channel.ad_break.begin"} {
+			t.redemptions = &redemptionQueue{}
+
+			for _, subType := range [...]string{
+				"channel.poll.begin", "channel.poll.progress", "channel.poll.end", "channel.ad_break.begin",
+				"channel.channel_points_custom_reward_redemption.add", "channel.channel_points_custom_reward_redemption.update",
+			} {
 				subType := subType // capture for closure
 				cmds = append(cmds, func() tea.Msg {
 					t.deps.Pool.SubscribeEventSub(accountID, twitchapi.CreateEventSubSubscriptionRequest{
@@ -459,13 +557,26 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 		t.HandleResize()
 		cmds = append(cmds, t.streamInfo.Init(), t.statusInfo.Init(), tea.Sequence(ircCmds...))
 		return t, tea.Batch(cmds...)
+	case scriptCommandResultMessage:
+		if msg.targetID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleScriptCommandResult(msg)
+	case refreshRetryMessage:
+		if msg.targetID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleRefreshRetry()
 	case emoteSetRefreshedMessage:
-		if !t.account.IsAnonymous && msg.targetID == t.id {
-			if msg.err != nil && !errors.Is(msg.err, emote.ErrPartialFetch) {
-				t.err = errors.Join(t.err, msg.err)
-				return t, nil
-			}
+		if msg.targetID != t.id {
+			return t, nil
+		}
+
+		degradedCmd := t.handleRefreshResult(msg)
 
+		if !t.account.IsAnonymous {
 			userEmoteSet := t.deps.EmoteCache.AllEmotesUsableByUser(t.account.ID)
 
 			log.Info().Str("user-id", t.account.ID).Int("len", len(userEmoteSet)).Msg("fetched emotes for user")
@@ -494,17 +605,8 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 			t.messageInput.SetSuggestions(suggestions)
 
 			// notify user if not all emotes could be fetched
-			if errors.Is(msg.err, emote.ErrPartialFetch) {
-				return t, func() tea.Msg {
-					return requestLocalMessageHandleMessage{
-						tabID:     t.id,
-						accountID: t.AccountID(),
-						message: &twitchirc.Notice{
-							FakeTimestamp: time.Now(),
-							Message:       msg.err.Error(),
-						},
-					}
-				}
+			if degradedCmd != nil {
+				return t, degradedCmd
 			}
 
 			if msg.manually {
@@ -521,7 +623,7 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 			}
 		}
 
-		return t, nil
+		return t, degradedCmd
 	case wspool.EventSubEvent:
 		if msg.Error != nil {
 			log.Logger.Err(msg.Error).Msg("EventSub error")
@@ -529,6 +631,140 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 		}
 		cmd = t.handleEventSubMessage(msg.Message)
 		return t, cmd
+	case themeChangedMessage:
+		if !t.channelDataLoaded {
+			return t, nil
+		}
+
+		t.messageInput.InputModel.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.deps.UserConfig.Theme.InputPromptColor))
+		t.messageInput.MisspelledStyle = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color(t.deps.UserConfig.Theme.ChatErrorColor))
+		t.chatWindow.applyTheme()
+
+		if t.userInspect != nil {
+			t.userInspect.chatWindow.applyTheme()
+		}
+
+		return t, nil
+	case settingsChangedMessage:
+		if !t.channelDataLoaded {
+			return t, nil
+		}
+
+		t.messageInput.KeyMap = inputKeyMap(t.keymap())
+		t.messageInput.EmoteReplacer = t.deps.EmoteReplacer
+		t.messageInput.SetCustomSuggestions(t.customSuggestions())
+		t.messageInput.SetUserAliases(t.deps.UserConfig.Settings.Chat.UserAliases)
+		t.chatWindow.applySettings(t.deps.UserConfig.Settings.Chat.LayoutFor(t.channelLogin))
+
+		if t.userInspect != nil {
+			t.userInspect.chatWindow.applySettings(t.deps.UserConfig.Settings.Chat.LayoutFor(t.channelLogin))
+		}
+
+		return t, t.syncTimers(time.Now())
+	case autoModCheckMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleAutoModCheck(msg)
+	case linksShortenedMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleLinksShortened(msg)
+	case imageUploadedMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleImageUploaded(msg)
+	case sendDelayTickMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleSendDelayTick(msg)
+	case timerTickMessage:
+		if msg.tabID != t.id || !t.channelDataLoaded {
+			return t, nil
+		}
+
+		return t, t.handleTimerTick(time.Now())
+	case presenceMessage:
+		return t, t.handlePresence(msg)
+	case activityGraphTickMessage:
+		if msg.tabID != t.id || !t.channelDataLoaded {
+			return t, nil
+		}
+
+		t.updateActivityGraph(time.Now())
+
+		return t, activityGraphTickCommand(t.id, t.deps.UserConfig.Settings.Accessibility.ReducedMotion)
+	case localCommandResultMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleLocalCommandResult(msg)
+	case relativeTimestampTickMessage:
+		if !t.channelDataLoaded {
+			return t, nil
+		}
+
+		t.chatWindow.rerenderLines()
+
+		if t.userInspect != nil {
+			t.userInspect.chatWindow.rerenderLines()
+		}
+
+		return t, nil
+	case messageSendResultMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		t.chatWindow.handleSendResult(msg)
+		return t, nil
+	case userBlockChangedMessage:
+		if !t.channelDataLoaded {
+			return t, nil
+		}
+
+		return t, t.handleUserBlockChanged(msg)
+	case notesChangedMessage:
+		if !t.channelDataLoaded {
+			return t, nil
+		}
+
+		return t, t.handleNotesChanged(msg)
+	case translationMessage:
+		if msg.tabID != t.id {
+			return t, nil
+		}
+
+		return t, t.handleTranslation(msg)
+	case streamPlayerExitedMessage:
+		if msg.tabID != t.id || msg.player != t.player {
+			return t, nil
+		}
+
+		t.player = nil
+
+		if msg.err == nil {
+			return t, nil
+		}
+
+		return t, func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     t.id,
+				accountID: t.AccountID(),
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       fmt.Sprintf("Player for %s exited with error: %s", t.channelLogin, msg.err),
+				},
+			}
+		}
 	case chatEventMessage: // delegate message event to chat window
 		// ignore all messages that don't target this account and channel
 
@@ -542,6 +778,11 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 			}
 
 			if msg, ok := msg.message.(*twitchirc.PrivateMessage); ok {
+				t.messageInput.RecordUserActivity(msg.LoginName, msg.DisplayName, msg.TMISentTS)
+				t.recordTimerActivity(msg)
+				t.recordChatActivity(msg)
+				t.recordChatStats(msg)
+
 				if messageContainsCaseInsensitive(msg, t.account.DisplayName) {
 					cmds = append(cmds, func() tea.Msg {
 						return requestNotificationIconMessage{
@@ -551,9 +792,13 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 				}
 			}
 
+			cmds = append(cmds, t.countUnread(msg))
+
 			t.chatWindow, cmd = t.chatWindow.Update(msg)
 			cmds = append(cmds, cmd)
 
+			t.placeRestoredReadMarker(msg)
+
 			// if room state update, update status info
 			if _, ok := msg.message.(*twitchirc.RoomState); ok {
 				cmds = append(cmds, t.statusInfo.Init()) // resend init command
@@ -577,37 +822,94 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 	if t.channelDataLoaded {
 		if t.focused {
 			switch msg := msg.(type) {
+			case tea.MouseMsg:
+				return t, t.handleMouse(msg)
 			case tea.KeyMsg:
+				// A message held back by the send delay is canceled before escape does anything else
+				if t.pendingSend != nil && key.Matches(msg, t.keymap().Escape) {
+					return t, t.cancelPendingSend()
+				}
+
+				// While asked to shorten the links of the message being sent, keys answer the prompt
+				if t.sendPrompt != nil {
+					return t, t.handleSendPromptKey(msg)
+				}
+
+				// While asked what to do with a pasted image, keys answer the prompt
+				if t.uploadPrompt != nil {
+					return t, t.handleUploadPromptKey(msg)
+				}
+
+				if t.state == insertMode || t.state == userInspectInsertMode {
+					if prompt := t.uploadPromptFor(msg); prompt != nil {
+						t.uploadPrompt = prompt
+						t.messageInput.Blur()
+						t.HandleResize()
+
+						return t, nil
+					}
+				}
+
+				// While link hints are shown, every key press selects a hint
+				if cw := t.activeChatWindow(); cw != nil && cw.state == linkHintChatWindowState {
+					return t, t.handleLinkHintKey(cw, msg)
+				}
+
+				// While a range of messages is selected, keys move, copy or save the selection
+				if cw := t.activeChatWindow(); cw != nil && cw.state == visualChatWindowState {
+					return t, t.handleVisualKey(cw, msg)
+				}
+
 				// Focus message input, when not in insert mode and not in search mode inside chat window, depending on the current active chat window
-				if key.Matches(msg, t.deps.Keymap.InsertMode) &&
+				if key.Matches(msg, t.keymap().InsertMode) &&
 					(t.state == inChatWindow && t.chatWindow.state != searchChatWindowState || t.state == userInspectMode && t.userInspect.chatWindow.state != searchChatWindowState) {
 					cmd := t.handleStartInsertMode()
 					cmds = append(cmds, cmd)
 					return t, tea.Batch(cmds...)
 				}
 
+				// Send the message bound to the key in the keymap of the channel
+				if text, ok := t.deps.ChannelKeymaps.MacroFor(t.channelLogin, msg.String()); ok && !t.account.IsAnonymous && t.state == inChatWindow && t.chatWindow.state == viewChatWindowState {
+					return t, t.handleMacro(text)
+				}
+
 				// Open user inspect mode, where only messages from a specific user are shown
-				if key.Matches(msg, t.deps.Keymap.InspectMode) && (t.state == inChatWindow || t.state == userInspectMode) {
+				if key.Matches(msg, t.keymap().InspectMode) && (t.state == inChatWindow || t.state == userInspectMode) {
 					cmd := t.handleOpenUserInspectFromMessage()
 					cmds = append(cmds, cmd)
 					return t, tea.Batch(cmds...)
 				}
 
 				// Open chat in browser
-				if key.Matches(msg, t.deps.Keymap.ChatPopUp, t.deps.Keymap.ChannelPopUp) && (t.state == inChatWindow || t.state == userInspectMode) {
+				if key.Matches(msg, t.keymap().ChatPopUp, t.keymap().ChannelPopUp) && (t.state == inChatWindow || t.state == userInspectMode) {
 					return t, t.handleOpenBrowser(msg)
 				}
 
+				// Toggle composing Hangul for this tab
+				if key.Matches(msg, t.keymap().HangulInput) && (t.state == insertMode || t.state == userInspectInsertMode) {
+					t.hangulInput = !t.hangulInput
+					t.messageInput.SetHangulComposition(t.hangulInput)
+					return t, t.updateDraftIndicator()
+				}
+
+				// Sending first ends the composition, like an input method does, so incomplete syllables are never sent
+				if key.Matches(msg, t.keymap().Confirm, t.keymap().QuickSent) && t.messageInput.Preedit() != "" && (t.state == insertMode || t.state == userInspectInsertMode) {
+					t.messageInput.CommitPreedit()
+					return t, t.updateDraftIndicator()
+				}
+
 				// Send message
-				if key.Matches(msg, t.deps.Keymap.Confirm) && len(t.messageInput.Value()) > 0 && (t.state == insertMode || t.state == userInspectInsertMode) {
+				if key.Matches(msg, t.keymap().Confirm) && len(t.messageInput.Value()) > 0 && (t.state == insertMode || t.state == userInspectInsertMode) {
+					t.messageInput.ExpandSnippet()
 					t.messageInput, _ = t.messageInput.Update(tea.KeyMsg{Type: tea.KeyEnter})
-					return t, t.handleMessageSent(false)
+					return t, tea.Batch(t.handleMessageSent(false), t.updateDraftIndicator())
 				}
 
 				// Send message - quick send
-				if key.Matches(msg, t.deps.Keymap.QuickSent) && len(t.messageInput.Value()) > 0 && (t.state == insertMode || t.state == userInspectInsertMode) {
+				if key.Matches(msg, t.keymap().QuickSent) && len(t.messageInput.Value()) > 0 && (t.state == insertMode || t.state == userInspectInsertMode) {
+					t.messageInput.ExpandSnippet()
 					t.messageInput, _ = t.messageInput.Update(tea.KeyMsg{Type: tea.KeyEnter})
-					return t, t.handleMessageSent(true)
+					return t, tea.Batch(t.handleMessageSent(true), t.updateDraftIndicator())
 				}
 
 				// Message Accept Suggestion Template Replace
@@ -615,27 +917,104 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 				if key.Matches(msg, t.messageInput.KeyMap.AcceptSuggestion) && len(t.messageInput.Value()) > 0 && (t.state == insertMode || t.state == userInspectInsertMode) {
 					lineCountBefore := t.messageInput.LineCount()
 					t.messageInput, _ = t.messageInput.Update(msg)
-					cmds = append(cmds, t.replaceInputTemplate())
+					cmds = append(cmds, t.replaceInputTemplate(), t.updateDraftIndicator())
 					if t.messageInput.LineCount() != lineCountBefore {
 						t.HandleResize()
 					}
 					return t, tea.Batch(cmds...)
 				}
 
+				// Add the misspelled word at the cursor to the custom dictionary
+				if key.Matches(msg, t.keymap().AddToDictionary) && t.deps.SpellChecker != nil && (t.state == insertMode || t.state == userInspectInsertMode) {
+					return t, t.handleAddToDictionary()
+				}
+
 				// Set quick time out message to message input
-				if key.Matches(msg, t.deps.Keymap.QuickTimeout) && (t.state == inChatWindow || t.state == userInspectMode) {
+				if key.Matches(msg, t.keymap().QuickTimeout) && (t.state == inChatWindow || t.state == userInspectMode) {
 					t.handleTimeoutShortcut()
-					return t, nil
+					return t, t.updateDraftIndicator()
+				}
+
+				// Send the selected failed message again
+				if key.Matches(msg, t.keymap().RetryMessage) && t.state == inChatWindow && t.chatWindow.state == viewChatWindowState {
+					return t, t.handleRetryMessage()
+				}
+
+				// Drop the chat connection and connect again
+				if key.Matches(msg, t.keymap().ReconnectChat) && (t.state == inChatWindow || t.state == userInspectMode) {
+					return t, t.handleReconnectChat()
+				}
+
+				// Block or unblock the inspected user
+				if key.Matches(msg, t.keymap().ToggleBlockUser) && t.state == userInspectMode {
+					return t, t.handleToggleBlockInspectedUser()
 				}
 
 				// Copy selected message to message input
-				if key.Matches(msg, t.deps.Keymap.CopyMessage) && (t.state == inChatWindow || t.state == userInspectMode) {
+				if key.Matches(msg, t.keymap().CopyMessage) && (t.state == inChatWindow || t.state == userInspectMode) {
 					t.handleCopyMessage()
-					return t, nil
+					return t, t.updateDraftIndicator()
+				}
+
+				// Open emote overview
+				if key.Matches(msg, t.keymap().EmoteOverview) && (t.state == inChatWindow && t.chatWindow.state == viewChatWindowState) {
+					return t, t.handleOpenEmoteOverview()
+				}
+
+				// Watch stream in external player
+				if key.Matches(msg, t.keymap().WatchStream) && (t.state == inChatWindow && t.chatWindow.state == viewChatWindowState || t.state == userInspectMode && t.userInspect.chatWindow.state == viewChatWindowState) {
+					return t, t.handleWatchStream()
+				}
+
+				// Label all visible links
+				if key.Matches(msg, t.keymap().LinkHintMode) {
+					if cw := t.activeChatWindow(); cw != nil && cw.state == viewChatWindowState {
+						cw.handleStartLinkHintMode()
+						return t, nil
+					}
+				}
+
+				// Select a range of messages
+				if key.Matches(msg, t.keymap().VisualMode) {
+					if cw := t.activeChatWindow(); cw != nil && cw.state == viewChatWindowState {
+						cw.handleStartVisualMode()
+						return t, nil
+					}
+				}
+
+				// Copy parts of the selected message to the system clipboard
+				if key.Matches(msg, t.keymap().CopyToClipboard, t.keymap().CopyUsernameToClipboard, t.keymap().CopyLinkToClipboard) &&
+					(t.state == inChatWindow && t.chatWindow.state != searchChatWindowState || t.state == userInspectMode && t.userInspect.chatWindow.state != searchChatWindowState) {
+					switch {
+					case key.Matches(msg, t.keymap().CopyUsernameToClipboard):
+						return t, t.handleCopyToClipboard(clipboardAuthorName)
+					case key.Matches(msg, t.keymap().CopyLinkToClipboard):
+						return t, t.handleCopyToClipboard(clipboardFirstLink)
+					default:
+						return t, t.handleCopyToClipboard(clipboardMessageText)
+					}
+				}
+
+				// Translate the selected message below it
+				if key.Matches(msg, t.keymap().TranslateMessage) &&
+					(t.state == inChatWindow && t.chatWindow.state != searchChatWindowState || t.state == userInspectMode && t.userInspect.chatWindow.state != searchChatWindowState) {
+					return t, t.handleTranslateMessage()
+				}
+
+				// Show the reply thread of the selected message
+				if key.Matches(msg, t.keymap().OpenThread) &&
+					(t.state == inChatWindow && t.chatWindow.state != searchChatWindowState || t.state == userInspectMode && t.userInspect.chatWindow.state != searchChatWindowState) {
+					return t, t.handleOpenThread()
 				}
 
 				// Close overlay windows
-				if key.Matches(msg, t.deps.Keymap.Escape) {
+				if key.Matches(msg, t.keymap().Escape) {
+					// cancel reverse history search before leaving insert mode
+					if (t.state == insertMode || t.state == userInspectInsertMode) && t.messageInput.IsSearchingHistory() {
+						t.messageInput, cmd = t.messageInput.Update(msg)
+						return t, cmd
+					}
+
 					// first end search in user inspect sub window
 					if t.userInspect != nil && t.userInspect.chatWindow.state == searchChatWindowState {
 						t.userInspect.chatWindow, cmd = t.userInspect.chatWindow.Update(msg)
@@ -666,7 +1045,7 @@ func (t *broadcastTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 				lineCountBefore := t.messageInput.LineCount()
 
 				t.messageInput, cmd = t.messageInput.Update(msg)
-				cmds = append(cmds, cmd)
+				cmds = append(cmds, cmd, t.updateDraftIndicator())
 
 				// Recalculate layout if input line count changed (text wrapped/unwrapped)
 				if t.messageInput.LineCount() != lineCountBefore {
@@ -733,7 +1112,7 @@ func (t *broadcastTab) View() string {
 			MaxHeight(t.height).
 			AlignHorizontal(lipgloss.Center).
 			AlignVertical(lipgloss.Center).
-			Render(t.spinner.View() + " Loading")
+			Render(spinnerView(t.spinner, t.deps.UserConfig.Settings) + " Loading")
 	}
 
 	builder := strings.Builder{}
@@ -817,7 +1196,7 @@ func (t *broadcastTab) ViewWithoutStatusBar() string {
 			MaxHeight(t.height).
 			AlignHorizontal(lipgloss.Center).
 			AlignVertical(lipgloss.Center).
-			Render(t.spinner.View() + " Loading")
+			Render(spinnerView(t.spinner, t.deps.UserConfig.Settings) + " Loading")
 	}
 
 	builder := strings.Builder{}
@@ -891,10 +1270,24 @@ func (t *broadcastTab) ChannelID() string {
 	return t.channelID
 }
 
+// isLive reports if the channel was live at the last stream info refresh
+func (t *broadcastTab) isLive() bool {
+	return t.streamInfo != nil && !t.streamInfo.startedAt.IsZero()
+}
+
 func (t *broadcastTab) State() broadcastTabState {
 	return t.state
 }
 
+func (t *broadcastTab) IsTyping() bool {
+	if t.state == insertMode || t.state == userInspectInsertMode || t.state == emoteOverviewMode {
+		return true
+	}
+
+	cw := t.activeChatWindow()
+	return cw != nil && cw.state != viewChatWindowState
+}
+
 func (t *broadcastTab) IsDataLoaded() bool {
 	return t.channelDataLoaded
 }
@@ -943,7 +1336,7 @@ func (t *broadcastTab) handleEscapePressed() {
 func (t *broadcastTab) handleOpenBrowser(msg tea.KeyMsg) tea.Cmd {
 	return func() tea.Msg {
 		// open popup chat if modifier is pressed
-		if key.Matches(msg, t.deps.Keymap.ChatPopUp) {
+		if key.Matches(msg, t.keymap().ChatPopUp) {
 			t.handleOpenBrowserChatPopUp()()
 			return nil
 		}
@@ -975,6 +1368,26 @@ func (t *broadcastTab) handleOpenBrowserChannel() tea.Cmd {
 	}
 }
 
+// keymap returns the key bindings of the channel, with the overrides of the keymap file applied
+func (t *broadcastTab) keymap() save.KeyMap {
+	return t.deps.keymapFor(t.channelLogin)
+}
+
+// handleMacro sends the text of a macro like a typed message, the draft in the message input is kept
+func (t *broadcastTab) handleMacro(text string) tea.Cmd {
+	draft := t.messageInput.Value()
+
+	t.messageInput.SetValue(text)
+	cmd := t.handleMessageSent(false)
+
+	// links to shorten are asked for with the macro text in the input, like for typed messages
+	if t.sendPrompt == nil {
+		t.messageInput.SetValue(draft)
+	}
+
+	return cmd
+}
+
 func (t *broadcastTab) handleStartInsertMode() tea.Cmd {
 	if !t.account.IsAnonymous && (t.state == inChatWindow || t.state == userInspectMode) {
 		if t.state == inChatWindow {
@@ -1050,7 +1463,14 @@ func (t *broadcastTab) handlePyramidMessagesCommand(args []string) tea.Cmd {
 		}
 	}
 
-	client := t.deps.APIUserClients[t.account.ID].(userAuthenticatedAPIClient)
+	client, ok := t.deps.APIUserClients[t.account.ID].(userAuthenticatedAPIClient)
+	if !ok || t.deps.Offline {
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice(offlineSendNotice)
+		}
+	}
+
 	broadcasterID := t.channelID
 	userID := t.account.ID
 
@@ -1190,7 +1610,7 @@ func (t *broadcastTab) handleUniqueOnlyChatCommand(enable bool) tea.Cmd {
 }
 
 func (t *broadcastTab) shouldIgnoreMessage(msg twitchirc.IRCer) bool {
-	if messageMatchesBlocked(msg, t.deps.UserConfig.Settings.BlockSettings) {
+	if messageMatchesBlocked(msg, t.deps.UserConfig.Settings.BlockSettings) || isBlockedSender(t.deps, t.account.ID, msg) {
 		return true
 	}
 
@@ -1266,6 +1686,14 @@ func (t *broadcastTab) shouldIgnoreMessage(msg twitchirc.IRCer) bool {
 func (t *broadcastTab) handleMessageSent(quickSend bool) tea.Cmd {
 	input := t.messageInput.Value()
 
+	if prompt := t.sendPromptFor(input, quickSend); prompt != nil {
+		t.sendPrompt = prompt
+		t.messageInput.Blur()
+		t.HandleResize()
+
+		return nil
+	}
+
 	if !quickSend {
 		// reset state
 		if t.state == userInspectInsertMode {
@@ -1322,6 +1750,46 @@ func (t *broadcastTab) handleMessageSent(quickSend bool) tea.Cmd {
 			return t.handleOpenEmoteOverview()
 		case "refreshemotes":
 			return t.handleManualRefreshEmotes()
+		case "watch":
+			return t.handleWatchStream()
+		case "theme":
+			return t.handleThemeCommand(args)
+		case "obs":
+			return t.handleOBSCommand(args)
+		case "chatsettings":
+			return t.handleChatSettingsCommand()
+		case "redemptions":
+			return t.handleRedemptionsCommand()
+		case "chatstats":
+			return t.handleChatStatsCommand()
+		case "presence":
+			return t.handlePresenceCommand()
+		case "poll":
+			return t.handleCreatePollCommand(argStr, false)
+		case "prediction":
+			return t.handleCreatePollCommand(argStr, true)
+		case "block":
+			return t.handleBlockCommand(args, true)
+		case "unblock":
+			return t.handleBlockCommand(args, false)
+		case "export":
+			return t.handleExportCommand(args)
+		case "note":
+			return t.handleNoteCommand(args)
+		case "notes":
+			return t.handleNotesCommand(args)
+		case "removenote":
+			return t.handleRemoveNoteCommand(args)
+		case "timer":
+			return t.handleTimerCommand(args)
+		case "quote":
+			return t.handleQuoteCommand(args)
+		case "count":
+			return t.handleCountCommand(args)
+		}
+
+		if t.deps.Scripts != nil && t.deps.Scripts.HasCommand(commandName) {
+			return t.handleScriptCommand(commandName, argStr)
 		}
 
 		if !t.isUserMod {
@@ -1339,69 +1807,52 @@ func (t *broadcastTab) handleMessageSent(quickSend bool) tea.Cmd {
 			}
 		}
 
-		// Message input is only allowed for authenticated users
-		// so ttvAPI is guaranteed to be a moderationAPIClient
-		client := t.deps.APIUserClients[t.account.ID].(moderationAPIClient)
+		// offline the accounts use the API of Chatuino's server, which can't moderate
+		client, ok := t.deps.APIUserClients[t.account.ID].(moderationAPIClient)
+		if !ok || t.deps.Offline {
+			notice := t.localNotice()
+			return func() tea.Msg {
+				return notice("Moderator commands are not available in offline mode")
+			}
+		}
 
-		return handleCommand(commandName, args, channelID, channel, accountID, client)
+		return t.delaySend(input, func() tea.Cmd {
+			return handleCommand(commandName, args, channelID, channel, accountID, client)
+		})
+	}
+
+	if t.deps.Offline {
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice(offlineSendNotice)
+		}
 	}
 
+	if checker, ok := t.autoModCheckFor(input); ok {
+		return t.checkAutoMod(checker, input)
+	}
+
+	return t.delaySend(input, func() tea.Cmd {
+		return t.sendMessage(input)
+	})
+}
+
+// sendMessage sends the input as chat message, long messages are split if enabled
+func (t *broadcastTab) sendMessage(input string) tea.Cmd {
 	// Check if message is the same as the last message sent
 	// If so, append special character to bypass twitch duplicate message filter
 	if strings.EqualFold(input, t.lastMessageSent) {
 		input = input + " " + string(duplicateBypass)
 	}
 
-	lastSent := t.lastMessageSentAt
-	client := t.deps.APIUserClients[t.account.ID].(userAuthenticatedAPIClient)
-	broadcasterID := t.channelID
-	userID := t.account.ID
-
-	cmd := func() tea.Msg {
-		const delay = time.Second
-		diff := time.Since(lastSent)
-		if diff < delay {
-			time.Sleep(delay - diff)
-		}
-
-		notice := &twitchirc.Notice{
-			FakeTimestamp: time.Now(),
-		}
-
-		resp := chatEventMessage{
-			isFakeEvent: true,
-			accountID:   userID,
-			channel:     t.channelLogin,
-			channelID:   t.channelID,
-			tabID:       t.id,
-			message:     notice,
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-		defer cancel()
-
-		r, err := client.SendChatMessage(ctx, twitchapi.SendChatMessageRequest{
-			BroadcasterID: broadcasterID,
-			SenderID:      userID,
-			Message:       input,
-		})
-		if err != nil {
-			notice.Message = fmt.Sprintf("Could not send message: %s", err.Error())
-			return resp
-		}
-
-		if len(r.Data) > 0 && !r.Data[0].IsSent {
-			notice.Message = fmt.Sprintf("Could not send message: %s", r.Data[0].DropReason.Message)
-			return resp
-		}
-
-		return nil
+	messages := []string{input}
+	if t.deps.UserConfig.Settings.Chat.AutoSplitLongMessages {
+		messages = splitMessage(input, messageCharLimit)
 	}
 
 	t.lastMessageSent = input
-	t.lastMessageSentAt = time.Now()
 
-	return cmd
+	return t.sendParts(messages)
 }
 
 func (t *broadcastTab) handleCreateClipMessage() tea.Cmd {
@@ -1455,6 +1906,136 @@ func (t *broadcastTab) handleCreateClipMessage() tea.Cmd {
 	}
 }
 
+func (t *broadcastTab) handleThemeCommand(args []string) tea.Cmd {
+	if len(args) < 1 || args[0] == "" {
+		themes := t.deps.UserConfig.Themes
+
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     t.id,
+				accountID: t.AccountID(),
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       fmt.Sprintf("Active theme: %s, available themes: %s", themes.Active, strings.Join(themes.Names(), ", ")),
+				},
+			}
+		}
+	}
+
+	name := args[0]
+	tabID := t.id
+	accountID := t.AccountID()
+
+	return func() tea.Msg {
+		return setThemeMessage{
+			name:      name,
+			tabID:     tabID,
+			accountID: accountID,
+		}
+	}
+}
+
+// activeChatWindow returns the chat window currently navigated by the user, nil when in insert mode or another overlay is open
+func (t *broadcastTab) activeChatWindow() *chatWindow {
+	switch t.state {
+	case inChatWindow:
+		return t.chatWindow
+	case userInspectMode:
+		return t.userInspect.chatWindow
+	}
+
+	return nil
+}
+
+func (t *broadcastTab) handleLinkHintKey(cw *chatWindow, msg tea.KeyMsg) tea.Cmd {
+	hint, copyLink, matched := cw.handleLinkHintKey(msg)
+	if !matched {
+		return nil
+	}
+
+	return t.openLink(hint.url, copyLink)
+}
+
+// openLink opens the link with the configured opener or copies it, failures are shown in chat
+func (t *broadcastTab) openLink(link string, copyLink bool) tea.Cmd {
+	opener := t.deps.UserConfig.Settings.Links.Opener
+
+	if copyLink {
+		return copyToClipboard(link)
+	}
+
+	return func() tea.Msg {
+		err := openURL(opener, link)
+		if err == nil {
+			return nil
+		}
+
+		log.Logger.Err(err).Str("url", link).Msg("failed to open link")
+
+		return chatEventMessage{
+			isFakeEvent: true,
+			accountID:   t.account.ID,
+			channel:     t.channelLogin,
+			channelID:   t.channelID,
+			tabID:       t.id,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       fmt.Sprintf("Failed to open link %s: %s", link, err),
+			},
+		}
+	}
+}
+
+func (t *broadcastTab) handleCopyToClipboard(content clipboardContent) tea.Cmd {
+	var entry *chatEntry
+
+	if t.state == userInspectMode {
+		_, entry = t.userInspect.chatWindow.entryForCurrentCursor()
+	} else {
+		_, entry = t.chatWindow.entryForCurrentCursor()
+	}
+
+	if entry == nil {
+		return nil
+	}
+
+	msg, ok := entry.Event.message.(*twitchirc.PrivateMessage)
+	if !ok {
+		return nil
+	}
+
+	var text string
+
+	switch content {
+	case clipboardMessageText:
+		text = strings.ReplaceAll(msg.Message, string(duplicateBypass), "")
+	case clipboardAuthorName:
+		text = msg.DisplayName
+	case clipboardFirstLink:
+		if urls := extractValidURLs(msg.Message); len(urls) > 0 {
+			text = urls[0]
+		}
+	}
+
+	if text != "" {
+		return copyToClipboard(text)
+	}
+
+	return func() tea.Msg {
+		return chatEventMessage{
+			isFakeEvent: true,
+			accountID:   t.account.ID,
+			channel:     t.channelLogin,
+			channelID:   t.channelID,
+			tabID:       t.id,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       fmt.Sprintf("Selected message contains no %s to copy", content),
+			},
+		}
+	}
+}
+
 func (t *broadcastTab) handleCopyMessage() {
 	if t.account.IsAnonymous {
 		return
@@ -1514,6 +2095,8 @@ func (t *broadcastTab) handleOpenUserInspect(args []string) tea.Cmd {
 
 	t.state = userInspectMode
 	t.userInspect = newUserInspect(t.id, t.width, t.height, username, t.channelLogin, t.account.ID, t.deps)
+	t.userInspect.chatWindow.setAccount(t.account)
+	t.userInspect.chatWindow.setLayout(t.deps.UserConfig.Settings.Chat.LayoutFor(t.channelLogin))
 
 	initialEvents := make([]chatEventMessage, 0, 15)
 	for e := range slices.Values(t.chatWindow.entries) {
@@ -1614,12 +2197,42 @@ func (t *broadcastTab) renderMessageInput() string {
 	}
 
 	inputView := t.messageInput.View()
-	borderColor := lipgloss.Color(t.deps.UserConfig.Theme.InputPromptColor)
+	switch {
+	case t.sendPrompt != nil:
+		_, input, _ := strings.Cut(inputView, "\n")
+		inputView = t.sendPrompt.view(t.keymap().Confirm.Help().Key, t.keymap().Escape.Help().Key) + "\n" + input
+	case t.uploadPrompt != nil:
+		_, input, _ := strings.Cut(inputView, "\n")
+		inputView = t.uploadPrompt.view(t.keymap().Confirm.Help().Key, t.keymap().Escape.Help().Key) + "\n" + input
+	}
+
+	borderColor := lipgloss.Color(t.deps.UserConfig.Theme.BorderColor)
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor)
 
 	// Labels
 	topLabel := "[ Chat ]"
-	charCount := fmt.Sprintf("[ %d / %d ]", len([]rune(t.messageInput.Value())), t.messageInput.InputModel.CharLimit)
+	if t.hangulInput {
+		topLabel = "[ Chat | Hangul ]"
+	}
+
+	if t.pendingSend != nil {
+		topLabel = fmt.Sprintf("[ Sending in %d, %s cancels ]", t.pendingSend.remaining, t.keymap().Escape.Help().Key)
+	}
+
+	// warn when the message is close to or over the twitch message limit
+	inputLength := len([]rune(t.messageInput.Value()))
+	charCount := fmt.Sprintf("[ %d / %d ]", inputLength, messageCharLimit)
+	counterStyle := borderStyle
+
+	switch {
+	case inputLength > messageCharLimit:
+		charCount = fmt.Sprintf("[ %d / %d, %d messages ]", inputLength, messageCharLimit, len(splitMessage(t.messageInput.Value(), messageCharLimit)))
+		counterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.deps.UserConfig.Theme.ChatErrorColor))
+	case inputLength >= messageCharLimit:
+		counterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.deps.UserConfig.Theme.ChatErrorColor))
+	case inputLength >= messageCharWarnThreshold:
+		counterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.deps.UserConfig.Theme.ChatNoticeAlertColor))
+	}
 
 	innerWidth := t.width - 2 // -2 for left/right border chars
 
@@ -1628,8 +2241,8 @@ func (t *broadcastTab) renderMessageInput() string {
 	topBorder := "┌─" + topLabel + strings.Repeat("─", topFill) + "─┐"
 
 	// Bottom border: └─────...─[ 7 / 500 ]─┘ (counter on RIGHT)
-	bottomFill := innerWidth - len(charCount) - 2
-	bottomBorder := "└─" + strings.Repeat("─", bottomFill) + charCount + "─┘"
+	bottomFill := max(0, innerWidth-len(charCount)-2)
+	bottomBorder := borderStyle.Render("└─"+strings.Repeat("─", bottomFill)) + counterStyle.Render(charCount) + borderStyle.Render("─┘")
 
 	// Wrap input lines with │ borders
 	inputLines := strings.Split(inputView, "\n")
@@ -1642,7 +2255,7 @@ func (t *broadcastTab) renderMessageInput() string {
 	// Combine
 	result := borderStyle.Render(topBorder) + "\n"
 	result += borderStyle.Render(strings.Join(borderedLines, "\n")) + "\n"
-	result += borderStyle.Render(bottomBorder)
+	result += bottomBorder
 
 	return result
 }
@@ -1798,6 +2411,26 @@ func (t *broadcastTab) handleEventSubMessage(msg eventsub.Message[eventsub.Notif
 				Message:         fmt.Sprintf("You are getting raided by %s with %d Viewers!", msg.Payload.Event.FromBroadcasterUserName, msg.Payload.Event.Viewers),
 			},
 		)
+	case "channel.channel_points_custom_reward_redemption.add", "channel.channel_points_custom_reward_redemption.update":
+		if t.redemptions != nil {
+			t.redemptions.handleRedemptionEvent(msg.Payload.Subscription.Type, msg.Payload.Event)
+		}
+	case "channel.update":
+		text := t.streamInfo.applyChannelUpdate(msg.Payload.Event.Title, msg.Payload.Event.CategoryName, time.Now())
+		t.HandleResize()
+
+		if text == "" {
+			return nil
+		}
+
+		return createCMDFunc(
+			&twitchirc.Notice{
+				FakeTimestamp:   time.Now(),
+				ChannelUserName: t.channelLogin,
+				MsgID:           twitchirc.MsgID(uuid.NewString()),
+				Message:         text,
+			},
+		)
 	case "channel.ad_break.begin":
 		var chatMsg string
 
@@ -1930,11 +2563,27 @@ func (t *broadcastTab) handleOpenEmoteOverview() tea.Cmd {
 		return nil
 	}
 
-	t.emoteOverview = NewEmoteOverview(t.channelID, t.deps.EmoteCache, t.deps.EmoteReplacer, t.width, t.height)
+	t.emoteOverview = NewEmoteOverview(t.channelID, t.deps, t.width, t.height)
 	t.HandleResize()
 	return t.emoteOverview.Init()
 }
 
+// localNotice returns a function creating a notice shown only in the tab's chat, safe to call from commands
+func (t *broadcastTab) localNotice() func(text string) tea.Msg {
+	tabID, accountID := t.id, t.account.ID
+
+	return func(text string) tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+}
+
 func (t *broadcastTab) handleManualRefreshEmotes() tea.Cmd {
 	if t.account.IsAnonymous {
 		return nil
@@ -1945,8 +2594,36 @@ func (t *broadcastTab) handleManualRefreshEmotes() tea.Cmd {
 	return t.refreshEmotes(t.channelLogin, t.channelID, true)
 }
 
+// Draft returns the unsent text of the message input
+func (t *broadcastTab) Draft() string {
+	if t.messageInput == nil {
+		return t.draft
+	}
+
+	return t.messageInput.Value()
+}
+
+// updateDraftIndicator notifies the tab header when the message input became empty or non-empty
+func (t *broadcastTab) updateDraftIndicator() tea.Cmd {
+	hasDraft := strings.TrimSpace(t.Draft()) != ""
+	if hasDraft == t.hasDraft {
+		return nil
+	}
+
+	t.hasDraft = hasDraft
+	id := t.id
+
+	return func() tea.Msg {
+		return tabDraftMessage{
+			tabID:    id,
+			hasDraft: hasDraft,
+		}
+	}
+}
+
 func (t *broadcastTab) Focus() {
 	t.focused = true
+	t.unread = 0
 
 	if t.channelDataLoaded {
 		switch t.state {
@@ -1964,6 +2641,7 @@ func (t *broadcastTab) Blur() {
 	t.focused = false
 
 	if t.channelDataLoaded {
+		t.chatWindow.markRead()
 		t.chatWindow.Blur()
 		t.messageInput.Blur()
 
@@ -1974,6 +2652,11 @@ func (t *broadcastTab) Blur() {
 }
 
 func (t *broadcastTab) close() {
+	if t.player != nil {
+		stopPlayer(t.player)
+		t.player = nil
+	}
+
 	t.lastMessages.DeleteAll()
 	t.lastMessages.Stop()
 	t.lastMessages = nil
@@ -1983,3 +2666,15 @@ func (t *broadcastTab) close() {
 		t.emoteOverview = nil
 	}
 }
+
+// inputKeyMap maps the rebindable input bindings to the key map of the suggestion input
+func inputKeyMap(keymap save.KeyMap) component.KeyMap {
+	return component.KeyMap{
+		AcceptSuggestion: keymap.AcceptSuggestion,
+		NextSuggestion:   keymap.NextSuggestion,
+		PrevSuggestion:   keymap.PrevSuggestion,
+		ReverseSearch:    keymap.ReverseSearch,
+		PrevCompletion:   keymap.PrevCompletion,
+		CorrectSpelling:  keymap.CorrectSpelling,
+	}
+}
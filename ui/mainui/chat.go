@@ -13,10 +13,12 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/cosmetic"
+	"github.com/julez-dev/chatuino/internal/termtext"
 	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
-	"github.com/julez-dev/reflow/wordwrap"
-	"github.com/julez-dev/reflow/wrap"
 	"github.com/rs/zerolog/log"
 )
 
@@ -25,6 +27,8 @@ const (
 	cleanupThreshold            = int(cleanupAfterMessage * 1.5)
 	// prefixPadding               = 41
 	prefixPadding = 0
+	// cozyPrefixWidth is the width of the message prefix in the cozy layout, so all messages start in the same column
+	cozyPrefixWidth = 36
 )
 
 type chatEntry struct {
@@ -33,6 +37,9 @@ type chatEntry struct {
 	IsDeleted  bool
 	Event      chatEventMessage
 	IsFiltered bool // message is filtered out by search
+
+	rendered      []string // lines of the message rendered at renderedWidth, nil if the message changed since
+	renderedWidth int
 }
 
 type position struct {
@@ -45,6 +52,8 @@ type chatWindowState int
 const (
 	viewChatWindowState chatWindowState = iota
 	searchChatWindowState
+	linkHintChatWindowState
+	visualChatWindowState
 )
 
 type chatWindow struct {
@@ -71,6 +80,13 @@ type chatWindow struct {
 	userColorCache map[string]func(...string) string
 	searchInput    textinput.Model
 
+	// link hint mode
+	linkHints     []linkHint
+	linkHintInput string
+
+	// visual mode, all messages between the anchor and the selected message are selected
+	visualAnchor *chatEntry
+
 	// styles
 	indicator      string
 	indicatorWidth int
@@ -79,7 +95,27 @@ type chatWindow struct {
 	noticeAlertStyle    lipgloss.Style
 	clearChatAlertStyle lipgloss.Style
 	errorAlertStyle     lipgloss.Style
-	dimmedStyle         lipgloss.Style
+	timestampStyle      lipgloss.Style
+	systemMessageStyle  lipgloss.Style
+	mentionStyle        lipgloss.Style
+	favoriteStyle       lipgloss.Style
+	fadedStyle          lipgloss.Style
+	botIndicator        string // shown in front of the badges of known bots
+	favoriteIndicator   string // shown in front of the badges of favorite users
+
+	// the account viewing the chat, used to highlight own messages and mentions
+	accountID   string
+	accountName string
+
+	layout  string // one of the save.ChatLayout values
+	channel string // login of the channel shown, its key binding overrides apply
+
+	lastRead *chatEntry // newest entry when the tab lost focus, the new messages separator is shown after it
+}
+
+// keymap returns the key bindings of the channel shown
+func (c *chatWindow) keymap() save.KeyMap {
+	return c.deps.keymapFor(c.channel)
 }
 
 func newChatWindow(width, height int, deps *DependencyContainer) *chatWindow {
@@ -87,34 +123,96 @@ func newChatWindow(width, height int, deps *DependencyContainer) *chatWindow {
 	input.CharLimit = 25
 	input.Prompt = "  /"
 	input.Placeholder = "search"
-	input.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.InputPromptColor))
 	input.Cursor.BlinkSpeed = time.Millisecond * 750
 	input.Width = width
 
-	indicator := lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.ChatIndicatorColor)).Background(lipgloss.Color(deps.UserConfig.Theme.ChatIndicatorColor)).Render(">")
-
 	c := chatWindow{
 		deps:           deps,
 		width:          width,
 		height:         height,
 		userColorCache: map[string]func(...string) string{},
 		timeFormatFunc: func(t time.Time) string {
-			return t.Local().Format("15:04:05")
+			return formatTimestamp(deps.UserConfig.Settings.Timestamps, false, t, time.Now())
 		},
 		searchInput: input,
-
-		indicator:           indicator,
-		indicatorWidth:      lipgloss.Width(indicator),
-		subAlertStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.ChatSubAlertColor)).Bold(true),
-		noticeAlertStyle:    lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.ChatNoticeAlertColor)).Bold(true),
-		clearChatAlertStyle: lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.ChatClearChatColor)).Bold(true),
-		errorAlertStyle:     lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.ChatErrorColor)).Bold(true),
-		dimmedStyle:         lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.DimmedTextColor)),
 	}
 
+	c.layout = c.effectiveLayout(deps.UserConfig.Settings.Chat.LayoutFor(""))
+	c.setStyles()
+
 	return &c
 }
 
+// setStyles builds all styles from the active theme
+func (c *chatWindow) setStyles() {
+	theme := c.deps.UserConfig.Theme
+
+	c.searchInput.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.InputPromptColor))
+
+	c.indicator = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatIndicatorColor)).Background(lipgloss.Color(theme.ChatIndicatorColor)).Render(">")
+	c.indicatorWidth = lipgloss.Width(c.indicator)
+
+	c.subAlertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatSubAlertColor)).Bold(true)
+	c.noticeAlertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatNoticeAlertColor)).Bold(true)
+	c.clearChatAlertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatClearChatColor)).Bold(true)
+	c.errorAlertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor)).Bold(true)
+	c.timestampStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.TimestampColor))
+	c.systemMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.SystemMessageColor))
+	c.mentionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.MentionColor)).Bold(true)
+	c.botIndicator = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor)).Render("BOT")
+	c.favoriteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.FavoriteColor))
+	c.fadedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	c.favoriteIndicator = c.favoriteStyle.Bold(true).Render("★")
+}
+
+// applyTheme rebuilds the styles and all rendered lines after the active theme changed
+func (c *chatWindow) applyTheme() {
+	c.setStyles()
+	c.rerenderLines()
+}
+
+// setLayout changes the message layout and re-renders all messages
+func (c *chatWindow) setLayout(layout string) {
+	layout = c.effectiveLayout(layout)
+	if c.layout == layout {
+		return
+	}
+
+	c.layout = layout
+	c.rerenderLines()
+}
+
+// applySettings applies reloaded settings, which can change how every message is rendered, like the layout,
+// grouping, aliases, username colors or the screen reader mode, so all messages are rendered again
+func (c *chatWindow) applySettings(layout string) {
+	c.layout = c.effectiveLayout(layout)
+	c.rerenderLines()
+}
+
+// effectiveLayout returns the layout used for the configured layout, the screen reader mode always uses the standard layout
+func (c *chatWindow) effectiveLayout(layout string) string {
+	if c.screenReader() {
+		return save.ChatLayoutStandard
+	}
+
+	return layout
+}
+
+// screenReader reports if messages are shown as simple "user: message" lines, see save.AccessibilitySettings
+func (c *chatWindow) screenReader() bool {
+	return c.deps.UserConfig.Settings.Accessibility.ScreenReader
+}
+
+// setAccount sets the account viewing the chat, so its own messages and mentions can be highlighted
+func (c *chatWindow) setAccount(account save.Account) {
+	if account.IsAnonymous {
+		return
+	}
+
+	c.accountID = account.ID
+	c.accountName = account.DisplayName
+}
+
 func (c *chatWindow) Init() tea.Cmd {
 	return nil
 }
@@ -128,36 +226,52 @@ func (c *chatWindow) Update(msg tea.Msg) (*chatWindow, tea.Cmd) {
 	switch msg := msg.(type) {
 	case chatEventMessage:
 		c.handleMessage(msg)
+		return c, nil
+	case themeChangedMessage:
+		c.applyTheme()
+		return c, nil
+	case relativeTimestampTickMessage:
+		c.rerenderLines()
+		return c, nil
+	case tea.MouseMsg:
+		if !c.focused {
+			return c, nil
+		}
+
+		if link := c.handleMouse(msg); link != "" {
+			return c, c.openLink(link)
+		}
+
 		return c, nil
 	case tea.KeyMsg:
 		if c.focused {
 			switch {
 			// start search
-			case key.Matches(msg, c.deps.Keymap.SearchMode):
+			case key.Matches(msg, c.keymap().SearchMode):
 				return c, c.handleStartSearchMode()
 			// stop search
-			case key.Matches(msg, c.deps.Keymap.Escape) && c.state == searchChatWindowState:
+			case key.Matches(msg, c.keymap().Escape) && c.state == searchChatWindowState:
 				c.handleStopSearchMode()
 				return c, nil
-			case key.Matches(msg, c.deps.Keymap.Confirm) && c.state == searchChatWindowState:
+			case key.Matches(msg, c.keymap().Confirm) && c.state == searchChatWindowState:
 				c.handleStopSearchModeKeepSelected()
 				return c, nil
 			// update search, allow up and down arrow keys for navigation in result
-			case c.state == searchChatWindowState && msg.String() != "up" && msg.String() != "down":
+			case c.state == searchChatWindowState && !key.Matches(msg, c.keymap().SearchUp, c.keymap().SearchDown):
 				c.searchInput, cmd = c.searchInput.Update(msg)
 				c.applySearch()
 				cmds = append(cmds, cmd)
 				return c, tea.Batch(cmds...)
-			case key.Matches(msg, c.deps.Keymap.Down):
+			case key.Matches(msg, c.keymap().Down) || c.state == searchChatWindowState && key.Matches(msg, c.keymap().SearchDown):
 				c.messageDown(1)
-			case key.Matches(msg, c.deps.Keymap.Up):
+			case key.Matches(msg, c.keymap().Up) || c.state == searchChatWindowState && key.Matches(msg, c.keymap().SearchUp):
 				c.messageUp(1)
 				return c, nil
-			case key.Matches(msg, c.deps.Keymap.GoToBottom):
+			case key.Matches(msg, c.keymap().GoToBottom):
 				c.moveToBottom()
-			case key.Matches(msg, c.deps.Keymap.GoToTop):
+			case key.Matches(msg, c.keymap().GoToTop):
 				c.moveToTop()
-			case key.Matches(msg, c.deps.Keymap.DumpChat):
+			case key.Matches(msg, c.keymap().DumpChat):
 				c.debugDumpChat()
 			}
 		}
@@ -220,8 +334,13 @@ func (c *chatWindow) View() string {
 		return ""
 	}
 
-	spaces := make([]string, height-len(c.lines[c.lineStart:c.lineEnd]))
-	lines := append(c.lines[c.lineStart:c.lineEnd], spaces...)
+	visible := c.lines[c.lineStart:c.lineEnd]
+	if c.state == linkHintChatWindowState {
+		visible = c.applyLinkHintLabels(visible)
+	}
+
+	spaces := make([]string, height-len(visible))
+	lines := append(visible, spaces...)
 
 	if c.state == searchChatWindowState {
 		return c.searchInput.View() + "\n" + strings.Join(lines, "\n")
@@ -384,6 +503,16 @@ func (c *chatWindow) moveToTop() {
 	c.messageUp(i)
 }
 
+// entriesBelowSelection returns the number of messages after the selected message, which were not scrolled to yet
+func (c *chatWindow) entriesBelowSelection() int {
+	i, _ := c.entryForCurrentCursor()
+	if i == -1 {
+		return 0
+	}
+
+	return len(c.activeEntries()) - 1 - i
+}
+
 func (c *chatWindow) getNewestEntry() *chatEntry {
 	active := c.activeEntries()
 	if len(active) > 0 {
@@ -394,18 +523,18 @@ func (c *chatWindow) getNewestEntry() *chatEntry {
 }
 
 func (c *chatWindow) markSelectedMessage() {
-	linesInView := c.lines[c.lineStart:c.lineEnd]
-	for i, s := range linesInView {
-		s, found := strings.CutPrefix(s, c.indicator+" ")
-		if found {
-			linesInView[i] = "  " + s
-		}
+	// a visual selection may have marked lines outside the view
+	if c.state == visualChatWindowState {
+		c.unmarkLines(c.lines)
+	} else {
+		c.unmarkLines(c.lines[c.lineStart:c.lineEnd])
 	}
 
 	active := c.activeEntries()
+	from, to := c.visualRange(active)
 
-	for e := range slices.Values(active) {
-		if !e.Selected {
+	for i, e := range active {
+		if !e.Selected && (i < from || i > to) {
 			continue
 		}
 
@@ -423,6 +552,15 @@ func (c *chatWindow) markSelectedMessage() {
 	}
 }
 
+func (c *chatWindow) unmarkLines(lines []string) {
+	for i, s := range lines {
+		s, found := strings.CutPrefix(s, c.indicator+" ")
+		if found {
+			lines[i] = "  " + s
+		}
+	}
+}
+
 func (c *chatWindow) cleanup() {
 	// todo: make this smarter, so we can delete more often
 	// c.logger.Info().Msgf("(%d/%d)", len(c.entries), cleanupThreshold)
@@ -430,7 +568,7 @@ func (c *chatWindow) cleanup() {
 		return
 	}
 
-	if c.state == searchChatWindowState {
+	if c.state == searchChatWindowState || c.state == visualChatWindowState {
 		return
 	}
 
@@ -479,17 +617,28 @@ func (c *chatWindow) cleanup() {
 }
 
 func (c *chatWindow) handleMessage(msg chatEventMessage) {
+	if msg.send == nil && c.handleIRCSendReply(msg.message) {
+		return
+	}
+
 	switch msg.message.(type) {
 	case error, *twitchirc.PrivateMessage, *twitchirc.Notice, *twitchirc.ClearChat, *twitchirc.SubMessage, *twitchirc.SubGiftMessage, *twitchirc.AnnouncementMessage, *twitchirc.ClearMessage: // supported Message types
 	default: // exit only on other types
 		return
 	}
 
+	if c.replaceSentMessage(msg) {
+		return
+	}
+
 	c.cleanup()
 	c.handleTimeoutMessage(msg)
 	c.handleMessageDeletion(msg)
 
-	lines := c.messageToText(msg)
+	newestEntry := c.getNewestEntry()
+	msg.displayModifier.grouped = c.continuesGroup(newestEntry, msg)
+	rendered := c.messageToText(msg)
+	lines := c.withReadMarker(newestEntry, c.withDateSeparator(newestEntry, msg, rendered))
 
 	// create new message - append to entries list
 	var (
@@ -497,10 +646,13 @@ func (c *chatWindow) handleMessage(msg chatEventMessage) {
 		wasLatestMessage = true
 	)
 
-	if newestEntry := c.getNewestEntry(); newestEntry != nil {
+	if newestEntry != nil {
 		positionStart = newestEntry.Position.CursorEnd
-		wasLatestMessage = newestEntry.Selected
-		newestEntry.Selected = false
+		// new messages don't extend a visual selection
+		wasLatestMessage = newestEntry.Selected && c.state != visualChatWindowState
+		if wasLatestMessage {
+			newestEntry.Selected = false
+		}
 	}
 
 	entry := &chatEntry{
@@ -508,8 +660,10 @@ func (c *chatWindow) handleMessage(msg chatEventMessage) {
 			CursorStart: positionStart + 1,
 			CursorEnd:   positionStart + len(lines),
 		},
-		Selected: wasLatestMessage,
-		Event:    msg,
+		Selected:      wasLatestMessage,
+		Event:         msg,
+		rendered:      rendered,
+		renderedWidth: c.width,
 	}
 
 	// we are currently searching and the new entry does not match the search, then ignore new entry
@@ -542,6 +696,7 @@ func (c *chatWindow) handleTimeoutMessage(msg chatEventMessage) {
 				hasDeleted = true
 				e.IsDeleted = true
 				e.Event.displayModifier.strikethrough = true
+				e.rendered = nil
 			}
 		}
 
@@ -565,6 +720,7 @@ func (c *chatWindow) handleMessageDeletion(msg chatEventMessage) {
 				hasDeleted = true
 				e.IsDeleted = true
 				e.Event.displayModifier.strikethrough = true
+				e.rendered = nil
 			}
 		}
 
@@ -577,11 +733,18 @@ func (c *chatWindow) handleMessageDeletion(msg chatEventMessage) {
 // buildAlertPrefix creates a standardized prefix with timestamp and styled alert label.
 // Example output: "  15:04:05 [Notice]: "
 func (c *chatWindow) buildAlertPrefix(timestamp time.Time, label string, style lipgloss.Style) string {
-	return "  " + c.dimmedStyle.Render(c.timeFormatFunc(timestamp)) + " [" + style.Render(label) + "]: "
+	// screen readers read the brackets aloud
+	if c.screenReader() {
+		return "  " + c.timestampPrefix(timestamp) + style.Render(label) + ": "
+	}
+
+	return "  " + c.timestampPrefix(timestamp) + "[" + style.Render(label) + "]: "
 }
 
-// formatMessageText applies word replacements and color processing to message content.
+// formatMessageText removes control sequences, applies word replacements and color processing to message content.
 func (c *chatWindow) formatMessageText(content string, modifier messageContentModifier) string {
+	content = termtext.Sanitize(content)
+
 	if modifier.strikethrough || modifier.italic {
 		s := lipgloss.NewStyle()
 
@@ -631,6 +794,20 @@ func (c *chatWindow) applyWordReplacements(content string, replacements wordRepl
 	return content
 }
 
+// maskProfanity replaces profanity in text written by a user with asterisks, if masking is enabled for the channel
+func (c *chatWindow) maskProfanity(channel, text string) string {
+	return maskProfanity(c.deps, channel, text)
+}
+
+// maskProfanity replaces profanity in text with asterisks for views outside the chat window, like the thread viewer
+func maskProfanity(deps *DependencyContainer, channel, text string) string {
+	if !deps.UserConfig.Settings.Profanity.MaskFor(channel) {
+		return text
+	}
+
+	return deps.Profanity.Mask(text)
+}
+
 func (c *chatWindow) setUserColorModifier(content string, modifier *messageContentModifier) {
 	words := strings.Split(content, " ")
 
@@ -657,38 +834,134 @@ func (c *chatWindow) setUserColorModifier(content string, modifier *messageConte
 	}
 }
 
+// setMentionModifier highlights mentions of the account viewing the chat
+func (c *chatWindow) setMentionModifier(msg *twitchirc.PrivateMessage, modifier *messageContentModifier) {
+	if c.accountName == "" || msg.UserID == c.accountID || !messageContainsCaseInsensitive(msg, c.accountName) {
+		return
+	}
+
+	if modifier.wordReplacements == nil {
+		modifier.wordReplacements = make(wordReplacement)
+	}
+
+	for word := range strings.FieldsSeq(msg.Message) {
+		stripped := stripDisplayNameEdges(word)
+		if !strings.EqualFold(stripped, c.accountName) {
+			continue
+		}
+
+		modifier.wordReplacements[word] = c.mentionStyle.Render(word)
+		modifier.wordReplacements[stripped] = c.mentionStyle.Render(stripped)
+	}
+}
+
 func (c *chatWindow) messageToText(event chatEventMessage) []string {
 	switch msg := event.message.(type) {
 	case error:
-		prefix := "  " + strings.Repeat(" ", len(c.timeFormatFunc(time.Now()))) + " [" + c.errorAlertStyle.Render("Error") + "]: "
+		prefix := "  " + strings.Repeat(" ", c.timestampWidth()) + "[" + c.errorAlertStyle.Render("Error") + "]: "
+		if c.screenReader() {
+			prefix = "  " + c.errorAlertStyle.Render("Error") + ": "
+		}
+
 		text := strings.ReplaceAll(msg.Error(), "\n", "")
 		return c.wordwrapMessage(prefix, c.formatMessageText(text, event.displayModifier))
 	case *twitchirc.PrivateMessage:
 		userRenderFunc := c.getSetUserColorFunc(msg.LoginName, msg.Color)
 
+		// paints are approximated with 24-bit colors, other terminals keep the plain user color
+		if paint := event.displayModifier.namePaint; paint != nil && c.deps.UserConfig.TrueColor {
+			if render := c.paintRenderFunc(*paint); render != nil {
+				userRenderFunc = render
+			}
+		}
+
+		if event.displayModifier.faded {
+			userRenderFunc = c.fadedStyle.Render
+		}
+
+		// own messages may use a different color for the username
+		if ownColor := c.deps.UserConfig.Theme.OwnMessageColor; ownColor != "" && c.accountID != "" && msg.UserID == c.accountID {
+			userRenderFunc = lipgloss.NewStyle().Foreground(lipgloss.Color(ownColor)).Render
+		}
+
 		// Build prefix components: time, [guest channel], [badges], username
-		parts := []string{"  " + c.dimmedStyle.Render(c.timeFormatFunc(msg.TMISentTS))}
+		var (
+			parts     []string
+			separator = " "
+		)
 
 		if event.channelGuestDisplayName != "" {
-			parts = append(parts, "|"+event.channelGuestDisplayName+"|")
+			parts = append(parts, "|"+termtext.Sanitize(event.channelGuestDisplayName)+"|")
+		}
+
+		if event.displayModifier.bot {
+			parts = append(parts, c.botIndicator)
+		}
+
+		isFavorite := c.deps.UserConfig.Settings.Favorites.IsFavorite(msg.LoginName)
+		if isFavorite {
+			parts = append(parts, c.favoriteIndicator)
 		}
 
-		if len(event.displayModifier.badgeReplacement) > 0 && !c.deps.UserConfig.Settings.Chat.DisableBadges {
-			badges := formatBadgeReplacement(c.deps.UserConfig.Settings, event.displayModifier.badgeReplacement)
+		if len(event.displayModifier.badgeReplacement) > 0 {
+			parts = append(parts, formatBadgeReplacement(c.deps.UserConfig.Settings, event.displayModifier.badgeReplacement))
+
 			if c.deps.UserConfig.Settings.Chat.GraphicBadges {
 				// Hair space (U+200A) - narrower gap since badges have pixel padding
-				parts = append(parts, badges+" "+userRenderFunc(msg.DisplayName)+": ")
-			} else {
-				parts = append(parts, badges)
-				parts = append(parts, userRenderFunc(msg.DisplayName)+": ")
+				separator = " "
 			}
-		} else {
-			parts = append(parts, userRenderFunc(msg.DisplayName)+": ")
 		}
-		prefix := strings.Join(parts, " ")
+
+		// screen readers read the indicators and badges before every name, the linear format only keeps the name
+		if c.screenReader() {
+			parts = nil
+		}
+
+		timestamp := "  " + c.timestampPrefix(msg.TMISentTS)
+		lead := timestamp + strings.Join(parts, " ")
+		if len(parts) > 0 {
+			lead += separator
+		}
+
+		displayName := msg.DisplayName
+		if alias := c.deps.UserConfig.Settings.Chat.AliasFor(msg.LoginName); alias != "" {
+			displayName = alias
+		}
+
+		name := userRenderFunc(termtext.Sanitize(displayName)) + ": "
+
+		// align the names in a column by right aligning them
+		if c.layout == save.ChatLayoutCozy {
+			lead += strings.Repeat(" ", max(cozyPrefixWidth-lipgloss.Width(lead+name), 0))
+		}
+
+		prefix := lead + name
+
+		// the badges and name are shown once per group, the following messages keep their timestamp and are aligned below the first one
+		if event.displayModifier.grouped {
+			prefix = timestamp + strings.Repeat(" ", lipgloss.Width(prefix)-lipgloss.Width(timestamp))
+		}
 
 		c.setUserColorModifier(msg.Message, &event.displayModifier)
-		return c.wordwrapMessage(prefix, c.formatMessageText(msg.Message, event.displayModifier))
+		c.setMentionModifier(msg, &event.displayModifier)
+
+		text := c.formatMessageText(c.maskProfanity(event.channel, msg.Message), event.displayModifier)
+		if repeat := event.displayModifier.emoteRepeat; repeat.count > 0 {
+			text = c.formatMessageText(repeat.emote, event.displayModifier) + " " + c.systemMessageStyle.Render(fmt.Sprintf("x%d", repeat.count))
+		}
+
+		switch {
+		case event.displayModifier.faded:
+			text = c.fadedStyle.Render(text)
+		case isFavorite:
+			text = c.favoriteStyle.Render(text)
+		}
+
+		if event.displayModifier.faded && c.deps.UserConfig.Settings.Chat.SpamFade.Collapse {
+			return c.truncateMessage(prefix, text)
+		}
+
+		return c.withTranslation(c.wordwrapMessage(prefix, text+c.sendStateSuffix(event.send)), event.displayModifier.translation)
 	case *twitchirc.Notice:
 		title := "Notice"
 		if event.isFakeEvent {
@@ -700,7 +973,7 @@ func (c *chatWindow) messageToText(event chatEventMessage) []string {
 		event.displayModifier.italic = true
 		c.setUserColorModifier(msg.Message, &event.displayModifier)
 
-		return c.wordwrapMessage(prefix, c.formatMessageText(msg.Message, event.displayModifier))
+		return c.wordwrapMessage(prefix, c.systemMessageStyle.Render(c.formatMessageText(msg.Message, event.displayModifier)))
 	case *twitchirc.ClearMessage:
 		prefix := c.buildAlertPrefix(msg.TMISentTS, "Clear Message", c.clearChatAlertStyle)
 		prefix += "A message from "
@@ -746,7 +1019,7 @@ func (c *chatWindow) messageToText(event chatEventMessage) []string {
 
 		// Append user message if present
 		if msg.Message != "" {
-			text += ": " + msg.Message
+			text += ": " + c.maskProfanity(event.channel, msg.Message)
 		}
 
 		c.setUserColorModifier(text, &event.displayModifier)
@@ -769,13 +1042,13 @@ func (c *chatWindow) messageToText(event chatEventMessage) []string {
 		return c.wordwrapMessage(prefix, c.formatMessageText(text, event.displayModifier))
 	case *twitchirc.AnnouncementMessage:
 		style := lipgloss.NewStyle().Foreground(lipgloss.Color(msg.ParamColor.RGBHex())).Bold(true)
-		prefix := "  " + c.timeFormatFunc(msg.TMISentTS) + " [" + style.Render("Announcement") + "] "
+		prefix := "  " + c.timestampPrefix(msg.TMISentTS) + "[" + style.Render("Announcement") + "] "
+		if c.screenReader() {
+			prefix = "  " + c.timestampPrefix(msg.TMISentTS) + style.Render("Announcement from") + " "
+		}
 
 		_ = c.getSetUserColorFunc(msg.Login, msg.Color)
-		text := fmt.Sprintf("%s: %s",
-			msg.DisplayName,
-			c.applyWordReplacements(msg.Message, event.displayModifier.wordReplacements),
-		)
+		text := fmt.Sprintf("%s: %s", msg.DisplayName, c.maskProfanity(event.channel, msg.Message))
 
 		c.setUserColorModifier(text, &event.displayModifier)
 
@@ -790,7 +1063,19 @@ func (c *chatWindow) getSetUserColorFunc(name string, colorHex string) func(strs
 
 	if !ok {
 		if colorHex == "" {
-			colorHex = randomHexColor()
+			colorHex = c.deps.UserConfig.Theme.UsernameColor
+		}
+
+		if colorHex == "" {
+			colorHex = deterministicUserColor(name)
+		}
+
+		if c.deps.UserConfig.Settings.Accessibility.UsernameColors == save.UsernameColorsColorblind {
+			colorHex = colorblindUserColor(colorHex)
+		}
+
+		if target := c.deps.UserConfig.Settings.Chat.UsernameMinContrast; target > 0 {
+			colorHex = adjustColorContrast(colorHex, c.deps.UserConfig.DarkBackground, target)
 		}
 
 		style := lipgloss.NewStyle().Foreground(lipgloss.Color(colorHex))
@@ -800,6 +1085,37 @@ func (c *chatWindow) getSetUserColorFunc(name string, colorHex string) func(strs
 	return c.userColorCache[name]
 }
 
+// paintRenderFunc renders a name with one color per character approximating the 7TV paint,
+// nil is returned for paints which can't be shown as colors
+func (c *chatWindow) paintRenderFunc(paint seventv.Paint) func(strs ...string) string {
+	if cosmetic.PaintColors(paint, 1) == nil {
+		return nil
+	}
+
+	return func(strs ...string) string {
+		name := []rune(strings.Join(strs, " "))
+		colors := cosmetic.PaintColors(paint, len(name))
+
+		b := strings.Builder{}
+		for i, r := range name {
+			color := colors[i]
+			if target := c.deps.UserConfig.Settings.Chat.UsernameMinContrast; target > 0 {
+				color = adjustColorContrast(color, c.deps.UserConfig.DarkBackground, target)
+			}
+
+			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(string(r)))
+		}
+
+		return b.String()
+	}
+}
+
+// truncateMessage renders the message in a single line, cut off at the end of the line
+func (c *chatWindow) truncateMessage(prefix, content string) []string {
+	line := prefix + strings.ReplaceAll(content, "\n", " ")
+	return []string{ansi.Truncate(line, max(c.width-c.indicatorWidth, 0), "…")}
+}
+
 func (c *chatWindow) wordwrapMessage(prefix, content string) []string {
 	content = strings.Map(func(r rune) rune {
 		// this rune is commonly used to bypass the twitch spam detection
@@ -810,10 +1126,15 @@ func (c *chatWindow) wordwrapMessage(prefix, content string) []string {
 		return r
 	}, content)
 
+	// compact messages are cut off at the end of the line instead of wrapping
+	if c.layout == save.ChatLayoutCompact {
+		return c.truncateMessage(prefix, content)
+	}
+
 	prefixWidth := lipgloss.Width(prefix)
 
-	// Assure that the prefix is at least prefixPadding wide
-	if prefixWidth < prefixPadding {
+	// Assure that the prefix is at least prefixPadding wide, screen readers would read the padding as a pause
+	if prefixWidth < prefixPadding && !c.screenReader() {
 		prefix = prefix + strings.Repeat(" ", prefixPadding-prefixWidth)
 		prefixWidth = lipgloss.Width(prefix)
 	}
@@ -821,7 +1142,7 @@ func (c *chatWindow) wordwrapMessage(prefix, content string) []string {
 	contentWidthLimit := c.width - c.indicatorWidth - prefixWidth
 
 	// softwrap text to contentWidthLimit, if soft wrapping fails (for example in links) force break
-	wrappedText := wrap.String(wordwrap.String(content, contentWidthLimit), contentWidthLimit)
+	wrappedText := wrapText(content, contentWidthLimit)
 	splits := strings.Split(wrappedText, "\n")
 
 	lines := make([]string, 0, len(splits))
@@ -829,13 +1150,21 @@ func (c *chatWindow) wordwrapMessage(prefix, content string) []string {
 
 	// if there are more lines, add prefixPadding spaces to the beginning of the line
 	for _, line := range splits[1:] {
-		if c.deps.UserConfig.Settings.Chat.DisablePaddingWrappedLines {
-			lines = append(lines, strings.Repeat(" ", len(c.timeFormatFunc(time.Now()))+3)+line)
-		} else {
+		switch {
+		case c.screenReader():
+			lines = append(lines, "  "+line)
+		case c.deps.UserConfig.Settings.Chat.DisablePaddingWrappedLines:
+			lines = append(lines, strings.Repeat(" ", c.timestampWidth()+2)+line)
+		default:
 			lines = append(lines, strings.Repeat(" ", prefixWidth)+line)
 		}
 	}
 
+	// cozy messages are separated by a blank line
+	if c.layout == save.ChatLayoutCozy {
+		lines = append(lines, "")
+	}
+
 	return lines
 }
 
@@ -890,6 +1219,27 @@ func (c *chatWindow) updatePort() {
 	}
 }
 
+// renderEntry returns the lines of the entry. Only messages which changed since they were rendered last,
+// or were rendered at a different width, are rendered again.
+func (c *chatWindow) renderEntry(e *chatEntry) []string {
+	if e.rendered == nil || e.renderedWidth != c.width {
+		e.rendered = c.messageToText(e.Event)
+		e.renderedWidth = c.width
+	}
+
+	return e.rendered
+}
+
+// rerenderLines renders all messages again, after a change which affects every message like the theme or layout
+func (c *chatWindow) rerenderLines() {
+	for _, e := range c.entries {
+		e.rendered = nil
+	}
+
+	c.recalculateLines()
+}
+
+// recalculateLines rebuilds the lines of the viewport from the rendered entries
 func (c *chatWindow) recalculateLines() {
 	c.searchInput.Width = c.width
 
@@ -913,7 +1263,9 @@ func (c *chatWindow) recalculateLines() {
 			lastCursorEnd = prevEntry.Position.CursorEnd
 		}
 
-		lines := c.messageToText(e.Event)
+		e.setGrouped(c.continuesGroup(prevEntry, e.Event))
+
+		lines := c.withReadMarker(prevEntry, c.withDateSeparator(prevEntry, e.Event, c.renderEntry(e)))
 		c.lines = append(c.lines, lines...)
 
 		e.Position.CursorStart = lastCursorEnd + 1
@@ -0,0 +1,540 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// limits of the Helix Update Chat Settings endpoint
+const (
+	minSlowModeWait     = time.Second * 3
+	maxSlowModeWait     = time.Minute * 2
+	maxFollowerDuration = time.Hour * 24 * 90
+)
+
+// chatDelays are the delays in seconds Twitch allows for messages of non-moderators, 0 disables the delay
+var chatDelays = []int{0, 2, 4, 6}
+
+type chatSettingsClient interface {
+	GetChatSettings(ctx context.Context, broadcasterID string, moderatorID string) (twitchapi.GetChatSettingsResponse, error)
+	UpdateChatSettings(ctx context.Context, broadcasterID string, moderatorID string, req twitchapi.UpdateChatSettingsRequest) (twitchapi.ChatSettingData, error)
+}
+
+// openChatSettingsMessage is sent by /chatsettings to open the chat settings editor for the channel of the tab
+type openChatSettingsMessage struct {
+	accountID string
+	channelID string
+	channel   string
+}
+
+// chatSettingsLoadedMessage contains the current chat settings, after loading or changing them
+type chatSettingsLoadedMessage struct {
+	channelID string
+	settings  twitchapi.ChatSettingData
+	updated   string // name of the changed option, empty after loading
+	err       error
+}
+
+// chatSettingOption is a chat setting shown in the editor. Options with parse are edited as text,
+// all others are changed by activate.
+type chatSettingOption struct {
+	name        string
+	description string
+	value       func(s twitchapi.ChatSettingData) string
+	activate    func(s twitchapi.ChatSettingData) twitchapi.UpdateChatSettingsRequest
+	parse       func(value string) (twitchapi.UpdateChatSettingsRequest, error)
+	editValue   func(s twitchapi.ChatSettingData) string
+}
+
+var chatSettingOptions = []chatSettingOption{
+	{
+		name:        "Slow mode",
+		description: "Time users have to wait between messages, between 3s and 2m. off disables slow mode.",
+		value: func(s twitchapi.ChatSettingData) string {
+			if !s.SlowMode {
+				return "off"
+			}
+			return humanizeDuration(time.Duration(s.SlowModeWaitTime) * time.Second)
+		},
+		parse: parseSlowMode,
+		editValue: func(s twitchapi.ChatSettingData) string {
+			if !s.SlowMode {
+				return "30s"
+			}
+			return (time.Duration(s.SlowModeWaitTime) * time.Second).String()
+		},
+	},
+	{
+		name:        "Follower-only",
+		description: "Time users have to follow before they can chat, up to 90d. 0 allows all followers, off disables follower-only mode.",
+		value: func(s twitchapi.ChatSettingData) string {
+			switch {
+			case !s.FollowerMode:
+				return "off"
+			case s.FollowerModeDuration == 0:
+				return "all followers"
+			}
+			return humanizeDuration(time.Duration(s.FollowerModeDuration) * time.Minute)
+		},
+		parse: parseFollowerMode,
+		editValue: func(s twitchapi.ChatSettingData) string {
+			if !s.FollowerMode {
+				return "10m"
+			}
+			return formatChatSettingDuration(time.Duration(s.FollowerModeDuration) * time.Minute)
+		},
+	},
+	{
+		name:        "Subscriber-only",
+		description: "Only subscribers, moderators and VIPs can chat.",
+		value:       func(s twitchapi.ChatSettingData) string { return onOff(s.SubscriberMode) },
+		activate: func(s twitchapi.ChatSettingData) twitchapi.UpdateChatSettingsRequest {
+			return twitchapi.UpdateChatSettingsRequest{SubscriberMode: pointer(!s.SubscriberMode)}
+		},
+	},
+	{
+		name:        "Emote-only",
+		description: "Messages may only contain emotes.",
+		value:       func(s twitchapi.ChatSettingData) string { return onOff(s.EmoteMode) },
+		activate: func(s twitchapi.ChatSettingData) twitchapi.UpdateChatSettingsRequest {
+			return twitchapi.UpdateChatSettingsRequest{EmoteMode: pointer(!s.EmoteMode)}
+		},
+	},
+	{
+		name:        "Unique chat",
+		description: "Messages must be unique, repeated messages of at least 9 characters are rejected.",
+		value:       func(s twitchapi.ChatSettingData) string { return onOff(s.UniqueChatMode) },
+		activate: func(s twitchapi.ChatSettingData) twitchapi.UpdateChatSettingsRequest {
+			return twitchapi.UpdateChatSettingsRequest{UniqueChatMode: pointer(!s.UniqueChatMode)}
+		},
+	},
+	{
+		name:        "Chat delay",
+		description: "Messages of non-moderators are shown after 2, 4 or 6 seconds, so moderators can remove them before.",
+		value: func(s twitchapi.ChatSettingData) string {
+			if !s.NonModeratorChatDelay {
+				return "off"
+			}
+			return humanizeDuration(time.Duration(s.NonModeratorChatDelayDuration) * time.Second)
+		},
+		activate: nextChatDelay,
+	},
+}
+
+// chatSettingsEditor shows the chat settings of a channel the user moderates. Every change is sent to Twitch immediately.
+type chatSettingsEditor struct {
+	deps          *DependencyContainer
+	width, height int
+
+	client    chatSettingsClient
+	accountID string
+	channelID string
+	channel   string
+
+	settings twitchapi.ChatSettingData
+	loaded   bool
+	loading  bool // a request is running, further changes are ignored until it finished
+
+	cursor  int
+	editing bool
+	input   textinput.Model
+	err     string
+	status  string
+}
+
+func newChatSettingsEditor(width, height int, msg openChatSettingsMessage, client chatSettingsClient, deps *DependencyContainer) *chatSettingsEditor {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.InputPromptColor))
+
+	e := &chatSettingsEditor{
+		deps:      deps,
+		client:    client,
+		accountID: msg.accountID,
+		channelID: msg.channelID,
+		channel:   msg.channel,
+		input:     input,
+	}
+	e.handleResize(width, height)
+
+	return e
+}
+
+func (e *chatSettingsEditor) handleResize(width, height int) {
+	// the editor is shown as a modal, leave some space to the terminal border
+	e.width = max(min(width-4, 70), 20)
+	e.height = max(height-4, 8)
+	e.input.Width = e.width - 8
+}
+
+// load fetches the current chat settings, including the chat delay only visible to moderators
+func (e *chatSettingsEditor) load() tea.Cmd {
+	e.loading = true
+
+	client, channelID, accountID := e.client, e.channelID, e.accountID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		resp, err := client.GetChatSettings(ctx, channelID, accountID)
+		if err == nil && len(resp.Data) == 0 {
+			err = errors.New("no chat settings returned")
+		}
+
+		if err != nil {
+			return chatSettingsLoadedMessage{channelID: channelID, err: err}
+		}
+
+		return chatSettingsLoadedMessage{channelID: channelID, settings: resp.Data[0]}
+	}
+}
+
+func (e *chatSettingsEditor) update(name string, req twitchapi.UpdateChatSettingsRequest) tea.Cmd {
+	e.loading = true
+	e.err = ""
+	e.status = "Saving " + strings.ToLower(name) + "…"
+
+	client, channelID, accountID := e.client, e.channelID, e.accountID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		settings, err := client.UpdateChatSettings(ctx, channelID, accountID, req)
+		return chatSettingsLoadedMessage{channelID: channelID, settings: settings, updated: name, err: err}
+	}
+}
+
+func (e *chatSettingsEditor) Update(msg tea.Msg) (*chatSettingsEditor, tea.Cmd) {
+	switch msg := msg.(type) {
+	case chatSettingsLoadedMessage:
+		if msg.channelID != e.channelID {
+			return e, nil
+		}
+
+		e.loading = false
+
+		if msg.err != nil {
+			e.err = chatSettingsErrorText(msg.err)
+			e.status = ""
+			return e, nil
+		}
+
+		e.settings = msg.settings
+		e.loaded = true
+		e.status = ""
+
+		if msg.updated != "" {
+			e.status = "Saved " + strings.ToLower(msg.updated)
+		}
+
+		return e, nil
+	case tea.KeyMsg:
+		if e.editing {
+			return e, e.handleEditKey(msg)
+		}
+
+		switch {
+		case key.Matches(msg, e.deps.Keymap.Up):
+			e.moveCursor(-1)
+		case key.Matches(msg, e.deps.Keymap.Down):
+			e.moveCursor(1)
+		case key.Matches(msg, e.deps.Keymap.Confirm):
+			return e, e.activateOption()
+		}
+	}
+
+	return e, nil
+}
+
+func (e *chatSettingsEditor) handleEditKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, e.deps.Keymap.Escape):
+		e.editing = false
+		e.input.Blur()
+		e.err = ""
+		return nil
+	case key.Matches(msg, e.deps.Keymap.Confirm):
+		option := chatSettingOptions[e.cursor]
+
+		req, err := option.parse(e.input.Value())
+		if err != nil {
+			e.err = err.Error()
+			return nil
+		}
+
+		e.editing = false
+		e.input.Blur()
+
+		return e.update(option.name, req)
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+func (e *chatSettingsEditor) moveCursor(delta int) {
+	e.cursor = min(max(e.cursor+delta, 0), len(chatSettingOptions)-1)
+	e.err = ""
+	if !e.loading {
+		e.status = ""
+	}
+}
+
+// activateOption toggles and cycles options or starts editing them
+func (e *chatSettingsEditor) activateOption() tea.Cmd {
+	if !e.loaded || e.loading {
+		return nil
+	}
+
+	option := chatSettingOptions[e.cursor]
+
+	if option.parse == nil {
+		return e.update(option.name, option.activate(e.settings))
+	}
+
+	e.editing = true
+	e.err = ""
+	e.status = ""
+	e.input.SetValue(option.editValue(e.settings))
+	e.input.CursorEnd()
+
+	return e.input.Focus()
+}
+
+func (e *chatSettingsEditor) View() string {
+	theme := e.deps.UserConfig.Theme
+	innerWidth := e.width - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ActiveLabelColor))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor))
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render("Chat Settings of "+e.channel) + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(fmt.Sprintf("%s select · %s toggle/edit · %s close",
+		e.deps.Keymap.Up.Help().Key+"/"+e.deps.Keymap.Down.Help().Key,
+		e.deps.Keymap.Confirm.Help().Key,
+		e.deps.Keymap.Escape.Help().Key,
+	)) + "\n\n")
+
+	for i, option := range chatSettingOptions {
+		name := "  " + option.name
+		if i == e.cursor {
+			name = selectedStyle.Render("> " + option.name)
+		}
+
+		value := dimmedStyle.Render("…")
+		if e.loaded {
+			value = option.value(e.settings)
+		}
+
+		gap := max(innerWidth-lipgloss.Width(name)-lipgloss.Width(value), 1)
+		_, _ = b.WriteString(name + strings.Repeat(" ", gap) + value + "\n")
+	}
+
+	_, _ = b.WriteString("\n" + lipgloss.NewStyle().Width(innerWidth).Render(chatSettingOptions[e.cursor].description) + "\n")
+
+	if e.editing {
+		_, _ = b.WriteString(e.input.View() + "\n")
+	} else {
+		_, _ = b.WriteString("\n")
+	}
+
+	switch {
+	case e.err != "":
+		_, _ = b.WriteString(errorStyle.Width(innerWidth).Render(e.err))
+	case e.status != "":
+		_, _ = b.WriteString(dimmedStyle.Render(e.status))
+	case !e.loaded:
+		_, _ = b.WriteString(dimmedStyle.Render("Loading chat settings…"))
+	}
+
+	return lipgloss.NewStyle().
+		Width(e.width).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
+
+// handleChatSettingsCommand opens the chat settings editor, it is only available to moderators
+func (t *broadcastTab) handleChatSettingsCommand() tea.Cmd {
+	if !t.isUserMod || t.channelID == "" {
+		tabID, accountID := t.id, t.account.ID
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     tabID,
+				accountID: accountID,
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       "The chat settings can only be changed by moderators",
+				},
+			}
+		}
+	}
+
+	msg := openChatSettingsMessage{
+		accountID: t.account.ID,
+		channelID: t.channelID,
+		channel:   t.channelLogin,
+	}
+
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// parseSlowMode parses the wait time between messages, plain numbers are seconds
+func parseSlowMode(value string) (twitchapi.UpdateChatSettingsRequest, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "off" || value == "0" {
+		return twitchapi.UpdateChatSettingsRequest{SlowMode: pointer(false)}, nil
+	}
+
+	d, err := parseChatSettingDuration(value, time.Second)
+	if err != nil {
+		return twitchapi.UpdateChatSettingsRequest{}, err
+	}
+
+	if d < minSlowModeWait || d > maxSlowModeWait {
+		return twitchapi.UpdateChatSettingsRequest{}, fmt.Errorf("slow mode must be between %s and %s", formatChatSettingDuration(minSlowModeWait), formatChatSettingDuration(maxSlowModeWait))
+	}
+
+	return twitchapi.UpdateChatSettingsRequest{
+		SlowMode:         pointer(true),
+		SlowModeWaitTime: pointer(int(d / time.Second)),
+	}, nil
+}
+
+// parseFollowerMode parses the time users have to follow, plain numbers are minutes
+func parseFollowerMode(value string) (twitchapi.UpdateChatSettingsRequest, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "off" {
+		return twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(false)}, nil
+	}
+
+	d, err := parseChatSettingDuration(value, time.Minute)
+	if err != nil {
+		return twitchapi.UpdateChatSettingsRequest{}, err
+	}
+
+	if d < 0 || d > maxFollowerDuration {
+		return twitchapi.UpdateChatSettingsRequest{}, fmt.Errorf("follower-only mode must be between 0m and %s", formatChatSettingDuration(maxFollowerDuration))
+	}
+
+	return twitchapi.UpdateChatSettingsRequest{
+		FollowerMode:         pointer(true),
+		FollowerModeDuration: pointer(int(d / time.Minute)),
+	}, nil
+}
+
+// parseChatSettingDuration parses Go durations with the additional unit d for days. Plain numbers use unit.
+func parseChatSettingDuration(value string, unit time.Duration) (time.Duration, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return time.Duration(n) * unit, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Duration(n) * time.Hour * 24, nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 30s, 10m, 1h or 7d", value)
+	}
+
+	return d, nil
+}
+
+// formatChatSettingDuration formats d without trailing zero units, whole days use the unit d
+func formatChatSettingDuration(d time.Duration) string {
+	if d >= time.Hour*24 && d%(time.Hour*24) == 0 {
+		return strconv.Itoa(int(d/(time.Hour*24))) + "d"
+	}
+
+	if d == 0 {
+		return "0m"
+	}
+
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
+
+// nextChatDelay switches to the next allowed chat delay, after 6 seconds the delay is disabled
+func nextChatDelay(s twitchapi.ChatSettingData) twitchapi.UpdateChatSettingsRequest {
+	current := 0
+	if s.NonModeratorChatDelay {
+		current = s.NonModeratorChatDelayDuration
+	}
+
+	next := chatDelays[0]
+	for _, d := range chatDelays {
+		if d > current {
+			next = d
+			break
+		}
+	}
+
+	if next == 0 {
+		return twitchapi.UpdateChatSettingsRequest{NonModeratorChatDelay: pointer(false)}
+	}
+
+	return twitchapi.UpdateChatSettingsRequest{
+		NonModeratorChatDelay:         pointer(true),
+		NonModeratorChatDelayDuration: pointer(next),
+	}
+}
+
+// chatSettingsErrorText explains the common errors of the chat settings endpoints
+func chatSettingsErrorText(err error) string {
+	var apiErr twitchapi.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return "Not allowed to change the chat settings, the account needs to be a moderator and have the moderator:manage:chat_settings scope: " + apiErr.Message
+		case http.StatusBadRequest:
+			return "Invalid chat settings: " + apiErr.Message
+		}
+	}
+
+	return "Failed to update chat settings: " + err.Error()
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+
+	return "off"
+}
+
+func pointer[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,105 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSlowMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    twitchapi.UpdateChatSettingsRequest
+		wantErr string
+	}{
+		{value: "off", want: twitchapi.UpdateChatSettingsRequest{SlowMode: pointer(false)}},
+		{value: "0", want: twitchapi.UpdateChatSettingsRequest{SlowMode: pointer(false)}},
+		{value: "30", want: twitchapi.UpdateChatSettingsRequest{SlowMode: pointer(true), SlowModeWaitTime: pointer(30)}},
+		{value: " 1m30s ", want: twitchapi.UpdateChatSettingsRequest{SlowMode: pointer(true), SlowModeWaitTime: pointer(90)}},
+		{value: "2m", want: twitchapi.UpdateChatSettingsRequest{SlowMode: pointer(true), SlowModeWaitTime: pointer(120)}},
+		{value: "2s", wantErr: "slow mode must be between 3s and 2m"},
+		{value: "5m", wantErr: "slow mode must be between 3s and 2m"},
+		{value: "fast", wantErr: `invalid duration "fast", use e.g. 30s, 10m, 1h or 7d`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSlowMode(tt.value)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseFollowerMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    twitchapi.UpdateChatSettingsRequest
+		wantErr string
+	}{
+		{value: "OFF", want: twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(false)}},
+		{value: "0", want: twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(true), FollowerModeDuration: pointer(0)}},
+		{value: "10", want: twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(true), FollowerModeDuration: pointer(10)}},
+		{value: "1h30m", want: twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(true), FollowerModeDuration: pointer(90)}},
+		{value: "7d", want: twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(true), FollowerModeDuration: pointer(10080)}},
+		{value: "90d", want: twitchapi.UpdateChatSettingsRequest{FollowerMode: pointer(true), FollowerModeDuration: pointer(129600)}},
+		{value: "91d", wantErr: "follower-only mode must be between 0m and 90d"},
+		{value: "-5", wantErr: "follower-only mode must be between 0m and 90d"},
+		{value: "a week", wantErr: `invalid duration "a week", use e.g. 30s, 10m, 1h or 7d`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseFollowerMode(tt.value)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_formatChatSettingDuration(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "0m", formatChatSettingDuration(0))
+	require.Equal(t, "30s", formatChatSettingDuration(time.Second*30))
+	require.Equal(t, "2m", formatChatSettingDuration(time.Minute*2))
+	require.Equal(t, "1h30m", formatChatSettingDuration(time.Minute*90))
+	require.Equal(t, "7d", formatChatSettingDuration(time.Hour*24*7))
+}
+
+func Test_nextChatDelay(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		twitchapi.UpdateChatSettingsRequest{NonModeratorChatDelay: pointer(true), NonModeratorChatDelayDuration: pointer(2)},
+		nextChatDelay(twitchapi.ChatSettingData{}),
+	)
+	require.Equal(t,
+		twitchapi.UpdateChatSettingsRequest{NonModeratorChatDelay: pointer(true), NonModeratorChatDelayDuration: pointer(6)},
+		nextChatDelay(twitchapi.ChatSettingData{NonModeratorChatDelay: true, NonModeratorChatDelayDuration: 4}),
+	)
+	require.Equal(t,
+		twitchapi.UpdateChatSettingsRequest{NonModeratorChatDelay: pointer(false)},
+		nextChatDelay(twitchapi.ChatSettingData{NonModeratorChatDelay: true, NonModeratorChatDelayDuration: 6}),
+	)
+}
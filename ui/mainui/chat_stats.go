@@ -0,0 +1,208 @@
+package mainui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// chatStatsTop is the number of chatters and emotes listed in the chat stats
+const chatStatsTop = 10
+
+// chatStatsCount is a chatter or emote with the number of its messages or uses
+type chatStatsCount struct {
+	name  string
+	count int
+}
+
+// chatStats counts the messages, chatters and emotes of a channel since the tab was opened.
+// Only the counters are kept, the messages themselves are not stored.
+type chatStats struct {
+	since    time.Time
+	messages int
+	chatters map[string]*chatStatsCount // keyed by user login, the name is the display name
+	emotes   map[string]int
+}
+
+func newChatStats(since time.Time) *chatStats {
+	return &chatStats{
+		since:    since,
+		chatters: map[string]*chatStatsCount{},
+		emotes:   map[string]int{},
+	}
+}
+
+// record counts the message, its chatter and the emotes used in it. isEmote reports whether a word is an emote of the channel,
+// Twitch emotes are known from the message tags.
+func (s *chatStats) record(msg *twitchirc.PrivateMessage, isEmote func(word string) bool) {
+	s.messages++
+
+	chatter, ok := s.chatters[msg.LoginName]
+	if !ok {
+		chatter = &chatStatsCount{}
+		s.chatters[msg.LoginName] = chatter
+	}
+
+	chatter.name = cmp.Or(msg.DisplayName, msg.LoginName)
+	chatter.count++
+
+	runes := []rune(msg.Message)
+	twitchEmotes := map[string]bool{}
+
+	for _, e := range msg.Emotes {
+		for _, pos := range e.Positions {
+			if pos.Start < 0 || pos.End >= len(runes) || pos.Start > pos.End {
+				continue
+			}
+
+			twitchEmotes[string(runes[pos.Start:pos.End+1])] = true
+		}
+	}
+
+	for word := range strings.FieldsSeq(msg.Message) {
+		if twitchEmotes[word] || isEmote != nil && isEmote(word) {
+			s.emotes[word]++
+		}
+	}
+}
+
+// topChatters returns the chatters with the most messages
+func (s *chatStats) topChatters(n int) []chatStatsCount {
+	top := make([]chatStatsCount, 0, len(s.chatters))
+	for _, c := range s.chatters {
+		top = append(top, *c)
+	}
+
+	return sortChatStatsCounts(top, n)
+}
+
+// topEmotes returns the most used emotes
+func (s *chatStats) topEmotes(n int) []chatStatsCount {
+	top := make([]chatStatsCount, 0, len(s.emotes))
+	for name, count := range s.emotes {
+		top = append(top, chatStatsCount{name: name, count: count})
+	}
+
+	return sortChatStatsCounts(top, n)
+}
+
+func sortChatStatsCounts(counts []chatStatsCount, n int) []chatStatsCount {
+	slices.SortFunc(counts, func(a, b chatStatsCount) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name)))
+	})
+
+	return counts[:min(len(counts), n)]
+}
+
+// openChatStatsMessage is sent by /chatstats to show the chat stats of the channel
+type openChatStatsMessage struct {
+	channel string
+	stats   *chatStats
+}
+
+// chatStatsView shows the chat stats of a channel, it is updated with each render while open
+type chatStatsView struct {
+	deps    *DependencyContainer
+	channel string
+	stats   *chatStats
+	width   int
+	now     func() time.Time
+}
+
+func newChatStatsView(width int, msg openChatStatsMessage, deps *DependencyContainer) *chatStatsView {
+	v := &chatStatsView{
+		deps:    deps,
+		channel: msg.channel,
+		stats:   msg.stats,
+		now:     time.Now,
+	}
+	v.handleResize(width)
+
+	return v
+}
+
+func (v *chatStatsView) handleResize(width int) {
+	// the view is shown as a modal, leave some space to the terminal border
+	v.width = max(min(width-4, 80), 30)
+}
+
+func (v *chatStatsView) View() string {
+	theme := v.deps.UserConfig.Theme
+	innerWidth := v.width - 4
+	columnWidth := innerWidth / 2
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	headingStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ListLabelColor))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render("Chat Stats of "+v.channel) + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(v.deps.Keymap.Escape.Help().Key+" close") + "\n\n")
+
+	elapsed := v.now().Sub(v.stats.since)
+
+	perMinute := 0.0
+	if elapsed >= time.Minute {
+		perMinute = float64(v.stats.messages) / elapsed.Minutes()
+	}
+
+	_, _ = b.WriteString(ansi.Truncate(fmt.Sprintf("%d messages from %d chatters in %s, %.1f per minute",
+		v.stats.messages,
+		len(v.stats.chatters),
+		formatWatchtime(elapsed),
+		perMinute,
+	), innerWidth, "…") + "\n\n")
+
+	column := func(heading, empty string, counts []chatStatsCount) string {
+		lines := []string{headingStyle.Render(heading)}
+		if len(counts) == 0 {
+			lines = append(lines, dimmedStyle.Render(empty))
+		}
+
+		for i, c := range counts {
+			lines = append(lines, ansi.Truncate(fmt.Sprintf("%2d. %s %s", i+1, c.name, dimmedStyle.Render(fmt.Sprintf("%d", c.count))), columnWidth-1, "…"))
+		}
+
+		return lipgloss.NewStyle().Width(columnWidth).Render(strings.Join(lines, "\n"))
+	}
+
+	_, _ = b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		column("Top Chatters", "No messages yet", v.stats.topChatters(chatStatsTop)),
+		column("Top Emotes", "No emotes used yet", v.stats.topEmotes(chatStatsTop)),
+	))
+
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Width(v.width - 2).
+		Render(b.String())
+}
+
+// recordChatStats counts a chat message in the chat stats of the channel
+func (t *broadcastTab) recordChatStats(msg *twitchirc.PrivateMessage) {
+	t.chatStats.record(msg, func(word string) bool {
+		_, ok := t.deps.EmoteCache.GetByText(t.channelID, word)
+		return ok
+	})
+}
+
+// handleChatStatsCommand runs /chatstats, which shows the top chatters and emotes of the channel since the tab was opened
+func (t *broadcastTab) handleChatStatsCommand() tea.Cmd {
+	msg := openChatStatsMessage{
+		channel: t.channelLogin,
+		stats:   t.chatStats,
+	}
+
+	return func() tea.Msg {
+		return msg
+	}
+}
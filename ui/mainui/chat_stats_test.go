@@ -0,0 +1,43 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatStats(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	s := newChatStats(since)
+
+	isEmote := func(word string) bool { return word == "OMEGALUL" || word == "catJAM" }
+
+	s.record(&twitchirc.PrivateMessage{
+		LoginName:   "lirik",
+		DisplayName: "LIRIK",
+		Message:     "Kappa OMEGALUL Kappa",
+		Emotes:      []twitchirc.Emote{{ID: "25", Positions: []twitchirc.EmotePosition{{Start: 0, End: 4}, {Start: 15, End: 19}}}},
+	}, isEmote)
+	s.record(&twitchirc.PrivateMessage{LoginName: "xqc", DisplayName: "xQc", Message: "catJAM catJAM"}, isEmote)
+	s.record(&twitchirc.PrivateMessage{LoginName: "xqc", DisplayName: "xQc", Message: "no emotes here"}, isEmote)
+	s.record(&twitchirc.PrivateMessage{LoginName: "forsen", Message: "Kappa", Emotes: []twitchirc.Emote{{ID: "25", Positions: []twitchirc.EmotePosition{{Start: 0, End: 40}}}}}, isEmote)
+
+	require.Equal(t, 4, s.messages)
+	require.Equal(t, []chatStatsCount{{name: "xQc", count: 2}, {name: "forsen", count: 1}}, s.topChatters(2))
+	require.Equal(t, []chatStatsCount{{name: "catJAM", count: 2}, {name: "Kappa", count: 2}, {name: "OMEGALUL", count: 1}}, s.topEmotes(10), "positions outside the message are ignored")
+
+	deps := newTestDeps(t)
+	v := newChatStatsView(100, openChatStatsMessage{channel: "lirik", stats: s}, deps)
+	v.now = func() time.Time { return since.Add(time.Minute * 2) }
+
+	view := ansi.Strip(v.View())
+	require.Contains(t, view, "Chat Stats of lirik")
+	require.Contains(t, view, "4 messages from 3 chatters in 2m, 2.0 per minute")
+	require.Contains(t, view, " 1. xQc 2")
+	require.Contains(t, view, " 1. catJAM 2")
+}
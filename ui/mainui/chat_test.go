@@ -0,0 +1,181 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/chatuino/profanity"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatWindow_renderEntry(t *testing.T) {
+	t.Parallel()
+
+	newWindow := func() *chatWindow {
+		deps := newTestDeps(t)
+
+		c := newChatWindow(80, 20, deps)
+		c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{ID: "m1", LoginName: "a", DisplayName: "a", Message: strings.Repeat("word ", 20)}})
+		c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{ID: "m2", LoginName: "b", DisplayName: "b", Message: "hello"}})
+
+		return c
+	}
+
+	t.Run("unchanged messages are not rendered again", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		first, second := &c.entries[0].rendered[0], &c.entries[1].rendered[0]
+
+		c.recalculateLines()
+		require.Same(t, first, &c.entries[0].rendered[0])
+		require.Same(t, second, &c.entries[1].rendered[0])
+	})
+
+	t.Run("deleted message", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		first, second := &c.entries[0].rendered[0], &c.entries[1].rendered[0]
+
+		c.handleMessage(chatEventMessage{message: &twitchirc.ClearMessage{Login: "b", TargetMsgID: "m2"}})
+		require.Same(t, first, &c.entries[0].rendered[0])
+		require.True(t, c.entries[1].IsDeleted)
+		require.NotSame(t, second, &c.entries[1].rendered[0])
+		require.Equal(t, c.entries[1].rendered, c.lines[c.entries[1].Position.CursorStart:c.entries[1].Position.CursorEnd+1])
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		before := len(c.entries[0].rendered)
+
+		c.width = 40
+		c.recalculateLines()
+		require.Equal(t, 40, c.entries[0].renderedWidth)
+		require.Greater(t, len(c.entries[0].rendered), before)
+		require.Len(t, c.lines, len(c.entries[0].rendered)+len(c.entries[1].rendered))
+	})
+
+	t.Run("layout change", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		first := &c.entries[0].rendered[0]
+
+		c.setLayout(save.ChatLayoutCompact)
+		require.NotSame(t, first, &c.entries[0].rendered[0])
+		require.Len(t, c.entries[0].rendered, 1)
+	})
+}
+
+func Test_chatWindow_applySettings(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	c := newChatWindow(80, 20, deps)
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "julez", DisplayName: "julez", Message: "hi"}})
+	require.Contains(t, stripAnsi(c.lines[0]), "julez: hi")
+
+	// cached messages are rendered again with the changed settings, even if the layout stays the same
+	deps.UserConfig.Settings.Chat.UserAliases = map[string]string{"julez": "Jules"}
+	c.applySettings(c.layout)
+	require.Contains(t, stripAnsi(c.lines[0]), "Jules: hi")
+}
+
+func Test_chatWindow_maskProfanity(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Profanity.Mask = true
+	deps.UserConfig.Settings.Profanity.Channels = map[string]bool{"lirik": false}
+	deps.Profanity = profanity.New([]string{"heck"})
+
+	c := newChatWindow(80, 20, deps)
+	c.handleMessage(chatEventMessage{channel: "julez", message: &twitchirc.PrivateMessage{LoginName: "a", DisplayName: "a", Message: "what the heck"}})
+	c.handleMessage(chatEventMessage{channel: "lirik", message: &twitchirc.PrivateMessage{LoginName: "a", DisplayName: "a", Message: "what the heck"}})
+
+	require.Contains(t, c.lines[0], "what the ****")
+	require.Contains(t, c.lines[1], "what the heck")
+}
+
+func Test_chatWindow_sanitizesMessages(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	c := newChatWindow(80, 20, deps)
+	c.handleMessage(chatEventMessage{
+		message: &twitchirc.PrivateMessage{LoginName: "a", DisplayName: "a\x1b[2J", Message: "hi\x1b]52;c;cm0gLXJmIH4=\x07\r there"},
+		displayModifier: messageContentModifier{
+			wordReplacements: wordReplacement{"there": "\x1b[31mthere\x1b[0m"}, // replacements are added by Chatuino and kept
+		},
+	})
+	c.handleMessage(chatEventMessage{message: &twitchirc.AnnouncementMessage{UserNotice: twitchirc.UserNotice{DisplayName: "b"}, Message: "\x1b[6nhello"}})
+
+	require.Contains(t, c.lines[0], "hi \x1b[31mthere\x1b[0m")
+	require.NotContains(t, c.lines[0], "\x1b]")
+	require.NotContains(t, c.lines[0], "\x1b[2J")
+	require.NotContains(t, c.lines[0], "\r")
+	require.NotContains(t, c.lines[1], "\x1b[6n")
+	require.Contains(t, stripAnsi(c.lines[1]), "b: hello")
+}
+
+func Test_chatWindow_userAliases(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Chat.UserAliases = map[string]string{"xx_longname_xx": "Bob"}
+
+	c := newChatWindow(80, 20, deps)
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "xx_longname_xx", DisplayName: "xX_LongName_Xx", Message: "hello"}})
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "julez", DisplayName: "Julez", Message: "hi"}})
+
+	require.Contains(t, stripAnsi(c.lines[0]), "Bob: hello")
+	require.Contains(t, stripAnsi(c.lines[1]), "Julez: hi")
+}
+
+func Test_chatWindow_favorites(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Favorites.Users = []string{"Julez"}
+
+	c := newChatWindow(80, 20, deps)
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "julez", DisplayName: "Julez", Message: "hello"}})
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "lirik", DisplayName: "Lirik", Message: "hi"}})
+
+	require.Contains(t, stripAnsi(c.lines[0]), "★ Julez: hello")
+	require.NotContains(t, c.lines[1], "★")
+}
+
+func Test_chatWindow_screenReader(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Accessibility.ScreenReader = true
+	deps.UserConfig.Settings.Chat.Layout = save.ChatLayoutCozy
+	deps.UserConfig.Settings.Timestamps.Format = save.TimestampFormatOff
+	deps.UserConfig.Settings.Favorites.Users = []string{"Julez"}
+
+	c := newChatWindow(40, 20, deps)
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "julez", DisplayName: "Julez", Message: strings.Repeat("word ", 10)}})
+	c.handleMessage(chatEventMessage{message: &twitchirc.Notice{Message: "slow mode"}})
+
+	require.Equal(t, save.ChatLayoutStandard, c.layout, "cozy messages would be aligned and separated by blank lines")
+
+	lines := make([]string, 0, len(c.lines))
+	for _, line := range c.lines {
+		lines = append(lines, strings.TrimRight(stripAnsi(line), " "))
+	}
+
+	require.Equal(t, []string{
+		"  Julez: word word word word word word",
+		"  word word word word",
+		"> Notice: slow mode", // the newest message is selected
+	}, lines)
+}
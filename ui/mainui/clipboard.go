@@ -0,0 +1,77 @@
+package mainui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// clipboardContent selects which part of a chat message is copied to the clipboard
+type clipboardContent int
+
+const (
+	clipboardMessageText clipboardContent = iota
+	clipboardAuthorName
+	clipboardFirstLink
+)
+
+func (c clipboardContent) String() string {
+	switch c {
+	case clipboardAuthorName:
+		return "username"
+	case clipboardFirstLink:
+		return "link"
+	default:
+		return "message"
+	}
+}
+
+// copyToClipboard returns the command writing text to the system clipboard.
+// The text is always sent to the terminal via OSC 52, which also works in SSH sessions. The sequence is written by the root model
+// in Update, a command writing to the terminal would race the renderer.
+// When running locally, the native clipboard tools (xclip, wl-copy, pbcopy, ...) are used as well,
+// since not every terminal supports OSC 52.
+func copyToClipboard(text string) tea.Cmd {
+	sequence := osc52Sequence(text, os.Getenv).String()
+
+	osc52Cmd := func() tea.Msg {
+		return terminalSequenceMessage{sequence: sequence}
+	}
+
+	if IsSSHSession(os.Getenv) || clipboard.Unsupported {
+		return osc52Cmd
+	}
+
+	return tea.Batch(osc52Cmd, func() tea.Msg {
+		// the text was already sent via OSC 52, the native clipboard is only a fallback
+		if err := clipboard.WriteAll(text); err != nil {
+			log.Logger.Warn().Err(err).Msg("failed to write to native clipboard")
+		}
+
+		return nil
+	})
+}
+
+// osc52Sequence builds the OSC 52 sequence, wrapped for terminal multiplexers which don't forward it on their own
+func osc52Sequence(text string, getenv func(string) string) osc52.Sequence {
+	seq := osc52.New(text)
+
+	if getenv("TMUX") != "" {
+		return seq.Tmux()
+	}
+
+	if strings.HasPrefix(getenv("TERM"), "screen") {
+		return seq.Screen()
+	}
+
+	return seq
+}
+
+// IsSSHSession reports if Chatuino runs in an SSH session, where the terminal runs on another machine
+func IsSSHSession(getenv func(string) string) bool {
+	return getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != "" || getenv("SSH_CLIENT") != ""
+}
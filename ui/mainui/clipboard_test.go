@@ -0,0 +1,65 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_osc52Sequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "plain terminal",
+			env:  map[string]string{"TERM": "xterm-kitty"},
+			want: "\x1b]52;c;aGVsbG8=\x07",
+		},
+		{
+			name: "tmux",
+			env:  map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux-1000/default,1234,0"},
+			want: "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\x07\x1b\\",
+		},
+		{
+			name: "screen",
+			env:  map[string]string{"TERM": "screen"},
+			want: "\x1bP\x1b]52;c;aGVsbG8=\x07\x1b\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := osc52Sequence("hello", func(k string) string { return tt.env[k] }).String()
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsSSHSession(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsSSHSession(func(k string) string {
+		if k == "SSH_CONNECTION" {
+			return "10.0.0.2 51234 10.0.0.1 22"
+		}
+		return ""
+	}))
+
+	require.False(t, IsSSHSession(func(string) string { return "" }))
+}
+
+func Test_copyToClipboard(t *testing.T) {
+	t.Setenv("SSH_TTY", "/dev/pts/1")
+	t.Setenv("TERM", "xterm-kitty")
+	t.Setenv("TMUX", "")
+
+	// the sequence is written by the root model, not by the command
+	msg := copyToClipboard("hello")()
+	require.Equal(t, terminalSequenceMessage{sequence: "\x1b]52;c;aGVsbG8=\x07"}, msg)
+}
@@ -0,0 +1,166 @@
+package mainui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/profanity"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+// configReloadInterval is how often the config files are checked for changes
+const configReloadInterval = time.Second * 2
+
+type configReloadTickMessage struct{}
+
+// configReloadedMessage contains the config read after the config files changed
+type configReloadedMessage struct {
+	config save.Config
+	err    error
+}
+
+// settingsChangedMessage is sent to all components after the config files were reloaded, so settings which are cached can be applied
+type settingsChangedMessage struct{}
+
+// configReloadTickCommand checks the config files for changes and reads them if they changed.
+// The files are read outside of the update loop, so slow disks don't block the UI.
+func configReloadTickCommand(source ConfigSource) tea.Cmd {
+	if source == nil {
+		return nil
+	}
+
+	return tea.Tick(configReloadInterval, func(_ time.Time) tea.Msg {
+		if !source.Changed() {
+			return configReloadTickMessage{}
+		}
+
+		config, err := source.Load()
+		return configReloadedMessage{config: config, err: err}
+	})
+}
+
+// handleConfigReloaded applies the reloaded config. If the config is invalid, the previous config is kept and the error is shown in the focused tab.
+func (r *Root) handleConfigReloaded(msg configReloadedMessage) tea.Cmd {
+	if msg.err != nil {
+		log.Logger.Err(msg.err).Msg("failed to reload config")
+		return r.focusedTabNotice(fmt.Sprintf("Failed to reload config, keeping the previous config: %s", msg.err))
+	}
+
+	deps := r.dependencies
+	previous := deps.UserConfig.Settings
+	settings := msg.config.Settings
+	theme := msg.config.Themes.ActiveTheme()
+
+	// flags given on startup still apply
+	settings.Session.RestoreTabs = previous.Session.RestoreTabs
+
+	if deps.BuildReplacers != nil {
+		replacers, err := deps.BuildReplacers(settings, theme)
+		if err != nil {
+			log.Logger.Err(err).Msg("failed to apply reloaded config")
+			return r.focusedTabNotice(fmt.Sprintf("Failed to reload config, keeping the previous config: %s", err))
+		}
+
+		deps.EmoteReplacer = replacers.Emote
+		deps.BadgeReplacer = replacers.Badge
+		deps.ImageDisplayManager = replacers.DisplayManager
+	}
+
+	// the animated emotes already shown are stopped or started right away
+	if deps.ImageDisplayManager != nil {
+		_, _ = io.WriteString(os.Stdout, deps.ImageDisplayManager.SetReducedMotion(settings.Accessibility.ReducedMotion))
+	}
+
+	if deps.Hooks != nil {
+		if err := deps.Hooks.SetHooks(settings.Hooks); err != nil {
+			log.Logger.Err(err).Msg("failed to apply reloaded hooks")
+			return r.focusedTabNotice(fmt.Sprintf("Failed to reload config, keeping the previous config: %s", err))
+		}
+	}
+
+	if deps.Sounds != nil {
+		if err := deps.Sounds.SetSettings(settings.Sounds); err != nil {
+			log.Logger.Err(err).Msg("failed to apply reloaded sounds")
+			return r.focusedTabNotice(fmt.Sprintf("Failed to reload config, keeping the previous config: %s", err))
+		}
+	}
+
+	deps.Profanity = profanity.New(settings.Profanity.MaskedWords())
+	deps.UserConfig.Settings = settings
+	deps.UserConfig.Theme = theme
+	deps.UserConfig.Themes = msg.config.Themes
+	deps.Keymap = msg.config.Keymap
+	deps.ChannelKeymaps = msg.config.ChannelKeymaps
+
+	r.keySequencer = newKeySequencer(deps.Keymap, deps.ChannelKeymaps)
+	r.splash.keymap = deps.Keymap
+	r.splash.userConfiguration = deps.UserConfig
+	r.help = newHelp(r.height, r.width, deps)
+	r.handleResize()
+
+	cmds := []tea.Cmd{
+		func() tea.Msg { return themeChangedMessage{} },
+		func() tea.Msg { return settingsChangedMessage{} },
+		r.focusedTabNotice("Reloaded config files"),
+	}
+
+	// failing scripts are skipped, the other scripts and the config still apply
+	if deps.Scripts != nil {
+		if err := deps.Scripts.Reload(); err != nil {
+			log.Logger.Err(err).Msg("failed to reload scripts")
+			cmds = append(cmds, r.focusedTabNotice(fmt.Sprintf("Failed to load scripts: %s", err)))
+		}
+	}
+
+	// restart ticks which stopped, since they were disabled by the previous settings
+	if previous.Timestamps.Format != save.TimestampFormatRelative {
+		cmds = append(cmds, relativeTimestampTickCommand(settings.Timestamps))
+	}
+
+	if !previous.Chat.GraphicEmotes && !previous.Chat.GraphicBadges {
+		cmds = append(cmds, r.imageCleanUpCommand())
+	}
+
+	if previous.Accessibility.ReducedMotion {
+		cmds = append(cmds, r.tray.cycle())
+	}
+
+	if settings.Mouse.Enabled != previous.Mouse.Enabled {
+		if settings.Mouse.Enabled {
+			cmds = append(cmds, tea.EnableMouseCellMotion)
+		} else {
+			cmds = append(cmds, tea.DisableMouse)
+		}
+	}
+
+	if settings.VerticalTabList != previous.VerticalTabList {
+		cmds = append(cmds, r.focusedTabNotice("Changing vertical_tab_list requires a restart"))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// focusedTabNotice shows a notice in the focused tab, the notice is only logged if there is no tab
+func (r *Root) focusedTabNotice(message string) tea.Cmd {
+	if len(r.tabs) <= r.tabCursor {
+		return nil
+	}
+
+	focused := r.tabs[r.tabCursor]
+
+	return func() tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     focused.ID(),
+			accountID: focused.AccountID(),
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       message,
+			},
+		}
+	}
+}
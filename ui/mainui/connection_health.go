@@ -0,0 +1,83 @@
+package mainui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const (
+	// slowConnectionLatency is the ping round trip from which the connection is shown as slow
+	slowConnectionLatency = time.Millisecond * 300
+
+	// quietConnectionAfter is how long without any message the connection is shown as slow,
+	// Twitch sends a PING about every 5 minutes, so longer silence hints at a stale connection
+	quietConnectionAfter = time.Minute * 6
+)
+
+// formatLatency formats the round trip time of a connection like "42ms", empty while not measured
+func formatLatency(health twitchirc.ConnHealth) string {
+	if !health.Connected || health.Latency <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%dms", max(health.Latency.Milliseconds(), 1))
+}
+
+// connectionHealthView shows the health of the chat connection as green, yellow or red dot followed by the latency
+func connectionHealthView(health twitchirc.ConnHealth, theme save.Theme, now time.Time) string {
+	color, text := theme.ConnectionGoodColor, formatLatency(health)
+
+	switch {
+	case !health.Connected:
+		color, text = theme.ConnectionDownColor, "disconnected"
+	case health.Latency >= slowConnectionLatency:
+		color = theme.ConnectionSlowColor
+	case now.Sub(health.LastMessage) >= quietConnectionAfter:
+		color, text = theme.ConnectionSlowColor, "quiet for "+formatUptime(now.Sub(health.LastMessage))
+	}
+
+	dot := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("●")
+	if text == "" {
+		return dot
+	}
+
+	return dot + " " + text
+}
+
+// connectionHealth returns the health of the chat connection of the tab's account
+func (t *broadcastTab) connectionHealth() (twitchirc.ConnHealth, bool) {
+	if t.deps.Pool == nil {
+		return twitchirc.ConnHealth{}, false
+	}
+
+	return t.deps.Pool.IRCHealth(t.account.ID)
+}
+
+// handleReconnectChat drops the chat connection of the tab's account and connects again, all tabs of the account rejoin their channel
+func (t *broadcastTab) handleReconnectChat() tea.Cmd {
+	tabID, accountID := t.id, t.account.ID
+	pool := t.deps.Pool
+
+	return func() tea.Msg {
+		text := "Reconnecting to chat server..."
+		if pool == nil {
+			text = "Chat connection is not available"
+		} else if err := pool.ReconnectIRC(accountID); err != nil {
+			text = "Failed to reconnect: " + err.Error()
+		}
+
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+}
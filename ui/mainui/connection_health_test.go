@@ -0,0 +1,35 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_connectionHealthView(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		health twitchirc.ConnHealth
+		want   string
+	}{
+		{name: "healthy", health: twitchirc.ConnHealth{Connected: true, Latency: 42 * time.Millisecond, LastMessage: now}, want: "● 42ms"},
+		{name: "not measured yet", health: twitchirc.ConnHealth{Connected: true, LastMessage: now}, want: "●"},
+		{name: "slow", health: twitchirc.ConnHealth{Connected: true, Latency: 800 * time.Millisecond, LastMessage: now}, want: "● 800ms"},
+		{name: "quiet", health: twitchirc.ConnHealth{Connected: true, Latency: 42 * time.Millisecond, LastMessage: now.Add(-10 * time.Minute)}, want: "● quiet for 10m"},
+		{name: "disconnected", health: twitchirc.ConnHealth{}, want: "● disconnected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, stripAnsi(connectionHealthView(tt.health, save.BuildDefaultTheme(), now)))
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package mainui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/rs/zerolog"
+)
+
+const debugLogRefreshInterval = time.Second
+
+// debugLogLevels are the minimum levels the viewer cycles through, trace shows all events
+var debugLogLevels = []zerolog.Level{zerolog.TraceLevel, zerolog.InfoLevel, zerolog.WarnLevel, zerolog.ErrorLevel}
+
+// debugLogTickMessage refreshes the log viewer, ticks of a previously opened viewer are ignored
+type debugLogTickMessage struct {
+	generation int
+}
+
+// debugLogViewer shows the events of the log buffer, filtered by level and text. New events are shown while it is open.
+type debugLogViewer struct {
+	deps          *DependencyContainer
+	width, height int
+	generation    int
+
+	filter   textinput.Model
+	level    int               // index into debugLogLevels
+	entries  []logbuffer.Entry // matching entries, oldest first
+	written  uint64            // written count of the buffer at the last refresh
+	fromLast int               // number of entries scrolled up from the newest entry
+}
+
+func newDebugLogViewer(width, height int, generation int, deps *DependencyContainer) *debugLogViewer {
+	filter := textinput.New()
+	filter.Prompt = "Filter: "
+	filter.Placeholder = "text in message or fields"
+	filter.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.InputPromptColor))
+	filter.Focus()
+
+	v := &debugLogViewer{
+		deps:       deps,
+		generation: generation,
+		filter:     filter,
+	}
+	v.handleResize(width, height)
+	v.refresh()
+
+	return v
+}
+
+func (v *debugLogViewer) handleResize(width, height int) {
+	// the viewer is shown as a modal, leave some space to the terminal border
+	v.width = max(width-4, 20)
+	v.height = max(height-4, 8)
+	v.filter.Width = v.width - 16
+	v.fromLast = min(v.fromLast, v.maxScroll())
+}
+
+func (v *debugLogViewer) tick() tea.Cmd {
+	generation := v.generation
+	return tea.Tick(debugLogRefreshInterval, func(time.Time) tea.Msg {
+		return debugLogTickMessage{generation: generation}
+	})
+}
+
+func (v *debugLogViewer) Update(msg tea.Msg) (*debugLogViewer, tea.Cmd) {
+	switch msg := msg.(type) {
+	case debugLogTickMessage:
+		if msg.generation != v.generation {
+			return v, nil
+		}
+
+		if v.deps.Logs != nil && v.deps.Logs.Written() != v.written {
+			before := len(v.entries)
+			v.refresh()
+
+			// keep the scrolled to entries in place, while new entries arrive
+			if v.fromLast > 0 {
+				v.fromLast = min(v.fromLast+len(v.entries)-before, v.maxScroll())
+			}
+		}
+
+		return v, v.tick()
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, v.deps.Keymap.Up):
+			v.fromLast = min(v.fromLast+1, v.maxScroll())
+			return v, nil
+		case key.Matches(msg, v.deps.Keymap.Down):
+			v.fromLast = max(v.fromLast-1, 0)
+			return v, nil
+		case key.Matches(msg, v.deps.Keymap.Next):
+			v.level = (v.level + 1) % len(debugLogLevels)
+			v.refresh()
+			return v, nil
+		case key.Matches(msg, v.deps.Keymap.Previous):
+			v.level = (v.level + len(debugLogLevels) - 1) % len(debugLogLevels)
+			v.refresh()
+			return v, nil
+		}
+
+		var cmd tea.Cmd
+		filter := v.filter.Value()
+		v.filter, cmd = v.filter.Update(msg)
+
+		if v.filter.Value() != filter {
+			v.refresh()
+		}
+
+		return v, cmd
+	}
+
+	return v, nil
+}
+
+// refresh reads the matching entries from the log buffer
+func (v *debugLogViewer) refresh() {
+	if v.deps.Logs == nil {
+		return
+	}
+
+	v.written = v.deps.Logs.Written()
+	v.entries = filterLogEntries(v.deps.Logs.Entries(), debugLogLevels[v.level], v.filter.Value())
+	v.fromLast = min(v.fromLast, v.maxScroll())
+}
+
+// filterLogEntries returns the entries with at least the level, containing the text in the message or fields, ignoring case
+func filterLogEntries(entries []logbuffer.Entry, level zerolog.Level, text string) []logbuffer.Entry {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	filtered := entries[:0:0]
+	for _, e := range entries {
+		// events without level are always shown, they are written by libraries
+		if e.Level != zerolog.NoLevel && e.Level < level {
+			continue
+		}
+
+		if text != "" && !strings.Contains(strings.ToLower(e.Message), text) && !strings.Contains(strings.ToLower(e.Fields), text) {
+			continue
+		}
+
+		filtered = append(filtered, e)
+	}
+
+	return filtered
+}
+
+func (v *debugLogViewer) listHeight() int {
+	// title, help, filter, blank line, borders and padding
+	return max(v.height-8, 1)
+}
+
+func (v *debugLogViewer) maxScroll() int {
+	return max(len(v.entries)-v.listHeight(), 0)
+}
+
+func (v *debugLogViewer) View() string {
+	theme := v.deps.UserConfig.Theme
+	innerWidth := v.width - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	levelStyles := map[zerolog.Level]lipgloss.Style{
+		zerolog.WarnLevel:  lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatNoticeAlertColor)),
+		zerolog.ErrorLevel: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor)),
+		zerolog.FatalLevel: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor)),
+		zerolog.PanicLevel: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor)),
+	}
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render("Debug Log") + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(
+		v.deps.Keymap.Next.Help().Key+" level ≥ "+debugLogLevels[v.level].String()+" · "+
+			v.deps.Keymap.Up.Help().Key+"/"+v.deps.Keymap.Down.Help().Key+" scroll · "+
+			v.deps.Keymap.Escape.Help().Key+" close",
+	) + "\n")
+	_, _ = b.WriteString(v.filter.View() + "\n\n")
+
+	lines := make([]string, 0, v.listHeight())
+
+	switch {
+	case v.deps.Logs == nil:
+		lines = append(lines, dimmedStyle.Render("The log buffer is not available"))
+	case len(v.entries) == 0:
+		lines = append(lines, dimmedStyle.Render("No matching log events"))
+	default:
+		end := len(v.entries) - v.fromLast
+		for _, e := range v.entries[max(end-v.listHeight(), 0):end] {
+			level := strings.ToUpper(zerolog.FormattedLevels[e.Level])
+			if level == "" {
+				level = "???"
+			}
+
+			line := e.Time.Local().Format("15:04:05") + " " + levelStyles[e.Level].Render(level) + " " + e.Message
+			if e.Fields != "" {
+				line += " " + dimmedStyle.Render(e.Fields)
+			}
+
+			lines = append(lines, ansi.Truncate(line, innerWidth, "…"))
+		}
+	}
+
+	// fill up the list, so the border doesn't jump
+	for len(lines) < v.listHeight() {
+		lines = append(lines, "")
+	}
+
+	_, _ = b.WriteString(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(v.width).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
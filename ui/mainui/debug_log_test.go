@@ -0,0 +1,66 @@
+package mainui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_filterLogEntries(t *testing.T) {
+	t.Parallel()
+
+	entries := []logbuffer.Entry{
+		{Level: zerolog.DebugLevel, Message: "incremented IRC ref count", Fields: "account_id=123"},
+		{Level: zerolog.InfoLevel, Message: "downloaded image", Fields: "id=abc"},
+		{Level: zerolog.ErrorLevel, Message: "failed to join", Fields: "channel=Lirik"},
+		{Level: zerolog.NoLevel, Message: "written without level"},
+	}
+
+	tests := []struct {
+		name  string
+		level zerolog.Level
+		text  string
+		want  []string
+	}{
+		{name: "all", level: zerolog.TraceLevel, want: []string{"incremented IRC ref count", "downloaded image", "failed to join", "written without level"}},
+		{name: "level", level: zerolog.InfoLevel, want: []string{"downloaded image", "failed to join", "written without level"}},
+		{name: "message", level: zerolog.TraceLevel, text: "IMAGE", want: []string{"downloaded image"}},
+		{name: "fields", level: zerolog.TraceLevel, text: " lirik ", want: []string{"failed to join"}},
+		{name: "level and text", level: zerolog.ErrorLevel, text: "image", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := []string{}
+			for _, e := range filterLogEntries(entries, tt.level, tt.text) {
+				got = append(got, e.Message)
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_ipcLogs(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "the log buffer is not available", ipcLogs(nil).Error)
+
+	ring := logbuffer.New(10)
+	logger := zerolog.New(ring)
+	logger.Info().Str("component", "ipc").Msg("listening")
+	_, err := ring.Write([]byte("not json"))
+	require.NoError(t, err)
+
+	resp := ipcLogs(ring)
+	require.True(t, resp.OK)
+	require.Equal(t, []json.RawMessage{
+		json.RawMessage(`{"level":"info","component":"ipc","message":"listening"}`),
+		json.RawMessage(`{"message":"not json"}`),
+	}, resp.Logs)
+}
@@ -0,0 +1,106 @@
+package mainui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// firstRefreshRetry is the delay before emotes and badges are fetched again after a failed refresh
+	firstRefreshRetry = time.Second * 30
+
+	// maxRefreshRetry caps the delay, which doubles with every failed retry
+	maxRefreshRetry = time.Minute * 5
+)
+
+// refreshRetryMessage fetches the emotes and badges of a degraded tab again
+type refreshRetryMessage struct {
+	targetID string
+}
+
+// nextRefreshRetry returns the delay before the next retry, given the delay of the previous one
+func nextRefreshRetry(last time.Duration) time.Duration {
+	if last <= 0 {
+		return firstRefreshRetry
+	}
+
+	return min(last*2, maxRefreshRetry)
+}
+
+// degradedView shows that cached emotes are used, followed by the platforms which can't be reached
+func degradedView(platforms []emote.Platform, theme save.Theme) string {
+	text := "degraded"
+	if len(platforms) > 0 {
+		names := make([]string, 0, len(platforms))
+		for _, p := range platforms {
+			names = append(names, p.String())
+		}
+
+		text += " (" + strings.Join(names, ", ") + ")"
+	}
+
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ConnectionSlowColor)).Render(text)
+}
+
+// handleRefreshResult enters degraded mode when emotes or badges could not be fetched, the tab keeps working
+// with the emotes stored on disk and fetches them again in the background until it succeeds.
+func (t *broadcastTab) handleRefreshResult(msg emoteSetRefreshedMessage) tea.Cmd {
+	notice := t.localNotice()
+
+	if msg.err == nil && len(t.deps.EmoteCache.Degraded(t.channelID)) == 0 {
+		if !t.degraded {
+			return nil
+		}
+
+		t.degraded, t.refreshRetryIn = false, 0
+		return func() tea.Msg {
+			return notice("Emotes and badges fetched again, degraded mode ended")
+		}
+	}
+
+	log.Logger.Warn().Err(msg.err).Str("channel-id", t.channelID).Msg("could not refresh emotes, using cached emotes")
+
+	wasDegraded := t.degraded
+	t.degraded = true
+
+	var cmds []tea.Cmd
+
+	if !t.retryPending {
+		t.retryPending = true
+		t.refreshRetryIn = nextRefreshRetry(t.refreshRetryIn)
+
+		tabID := t.id
+		cmds = append(cmds, tea.Tick(t.refreshRetryIn, func(time.Time) tea.Msg {
+			return refreshRetryMessage{targetID: tabID}
+		}))
+	}
+
+	if !wasDegraded || msg.manually {
+		text := "Degraded mode: using cached emotes, retrying in the background"
+		if msg.err != nil {
+			text += ": " + msg.err.Error()
+		}
+
+		cmds = append(cmds, func() tea.Msg {
+			return notice(text)
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (t *broadcastTab) handleRefreshRetry() tea.Cmd {
+	t.retryPending = false
+
+	if !t.degraded {
+		return nil
+	}
+
+	return t.refreshEmotes(t.channelLogin, t.channelID, false)
+}
@@ -0,0 +1,39 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nextRefreshRetry(t *testing.T) {
+	t.Parallel()
+
+	var got []time.Duration
+	var last time.Duration
+	for range 6 {
+		last = nextRefreshRetry(last)
+		got = append(got, last)
+	}
+
+	require.Equal(t, []time.Duration{
+		time.Second * 30,
+		time.Minute,
+		time.Minute * 2,
+		time.Minute * 4,
+		time.Minute * 5,
+		time.Minute * 5,
+	}, got)
+}
+
+func Test_degradedView(t *testing.T) {
+	t.Parallel()
+
+	theme := save.BuildDefaultTheme()
+
+	require.Equal(t, "degraded", stripAnsi(degradedView(nil, theme)))
+	require.Equal(t, "degraded (Twitch, BTTV)", stripAnsi(degradedView([]emote.Platform{emote.Twitch, emote.BTTV}, theme)))
+}
@@ -2,21 +2,41 @@ package mainui
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/julez-dev/chatuino/badge"
+	"github.com/julez-dev/chatuino/blocklist"
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/cosmetic"
 	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/hook"
 	"github.com/julez-dev/chatuino/kittyimg"
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/julez-dev/chatuino/obs"
+	"github.com/julez-dev/chatuino/profanity"
 	"github.com/julez-dev/chatuino/save"
 	"github.com/julez-dev/chatuino/save/messagelog"
+	"github.com/julez-dev/chatuino/script"
+	"github.com/julez-dev/chatuino/selfupdate"
 	"github.com/julez-dev/chatuino/server"
+	"github.com/julez-dev/chatuino/translate"
+	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/ui/component"
 	"github.com/julez-dev/chatuino/wspool"
 )
 
 type UserConfiguration struct {
 	Settings save.Settings
 	Theme    save.Theme
+	Themes   save.ThemeSet // all themes the active theme can be switched to at runtime
+
+	// DarkBackground reports if the terminal uses a dark background, used to adjust the contrast of user colors
+	DarkBackground bool
+
+	// TrueColor reports if the terminal supports 24-bit colors, 7TV paints are only shown with them
+	TrueColor bool
 }
 
 type AccountProvider interface {
@@ -28,12 +48,14 @@ type AccountProvider interface {
 type EmoteCache interface {
 	GetByText(channelID, text string) (emote.Emote, bool)
 	RefreshLocal(ctx context.Context, channelID string) error
+	Reload(ctx context.Context, channelID string) error
 	RefreshGlobal(ctx context.Context) error
 	GetAllForChannel(id string) emote.EmoteSet
 	AddUserEmotes(userID string, emotes []emote.Emote)
 	AllEmotesUsableByUser(userID string) []emote.Emote
 	RemoveEmoteSetForChannel(channelID string)
 	LoadSetForeignEmote(emoteID, emoteText string) emote.Emote
+	Degraded(channelID string) []emote.Platform
 }
 
 type EmoteReplacer interface {
@@ -43,6 +65,28 @@ type EmoteReplacer interface {
 type BadgeReplacer interface {
 	Replace(broadcasterID string, badgeList []twitchirc.Badge) (string, map[string]string, error)
 	InjectContributorBadge(loginName string, badges map[string]string) (string, error)
+	InjectSevenTVBadge(badge seventv.Badge, badges map[string]string) (string, error)
+}
+
+// BotList knows the bot accounts marked on FFZ and BTTV
+type BotList interface {
+	RefreshGlobal(ctx context.Context) error
+	RefreshChannel(ctx context.Context, channelID string) error
+	IsBot(channelID, userID, login string) bool
+}
+
+// BlockList knows the users the accounts blocked on Twitch
+type BlockList interface {
+	Refresh(ctx context.Context, client blocklist.Client, accountID string) error
+	Block(ctx context.Context, client blocklist.Client, accountID string, user blocklist.User) error
+	Unblock(ctx context.Context, client blocklist.Client, accountID string, userID string) error
+	IsBlocked(accountID, userID string) bool
+	Users(accountID string) []blocklist.User
+}
+
+// CosmeticCache returns the 7TV cosmetics of chatters without blocking
+type CosmeticCache interface {
+	Lookup(twitchUserID string) cosmetic.Cosmetics
 }
 
 type APIClient interface {
@@ -67,16 +111,125 @@ type ConnectionPool interface {
 	DisconnectIRC(accountID string)
 	SendIRC(accountID string, msg twitchirc.IRCer) error
 	JoinChannel(accountID, channel string) error
+	IRCHealth(accountID string) (twitchirc.ConnHealth, bool)
+	ReconnectIRC(accountID string) error
 	SubscribeEventSub(accountID string, req twitchapi.CreateEventSubSubscriptionRequest, service wspool.EventSubService) error
 	Close() error
 }
 
+type OBSClient interface {
+	State() obs.State
+	Scenes(ctx context.Context) ([]string, error)
+	SetScene(ctx context.Context, name string) error
+	StartStream(ctx context.Context) error
+	StopStream(ctx context.Context) error
+	StartRecord(ctx context.Context) error
+	StopRecord(ctx context.Context) error
+}
+
 type RecentMessageService interface {
 	GetRecentMessagesFor(ctx context.Context, channelLogin string) ([]twitchirc.IRCer, error)
 }
 
 type MessageLogger interface {
 	MessagesFromUserInChannel(username string, broadcasterChannel string) ([]messagelog.LogEntry, error)
+	MessagesInChannel(broadcasterChannel string) ([]messagelog.LogEntry, error)
+}
+
+// ConfigSource reports changes to the config files, so they can be applied at runtime
+type ConfigSource interface {
+	Changed() bool
+	Sync() // marks the current files as applied, after Chatuino wrote them itself
+	Load() (save.Config, error)
+}
+
+// Replacers are the emote and badge replacers built for the current settings
+type Replacers struct {
+	Emote          EmoteReplacer
+	Badge          BadgeReplacer
+	DisplayManager *kittyimg.DisplayManager // nil if neither graphic emotes nor badges are enabled
+}
+
+// PanicHandler is called with the recovered value and stack of a panic, the panic continues afterwards
+type PanicHandler func(value any, stack []byte)
+
+// ReplacerFactory builds the replacers for changed settings or themes
+type ReplacerFactory func(settings save.Settings, theme save.Theme) (Replacers, error)
+
+// HookRunner runs the external commands configured for chat events
+type HookRunner interface {
+	Fire(events ...hook.Event)
+	SetHooks(hooks []save.Hook) error
+}
+
+// SoundPlayer plays the sounds configured for chat events
+type SoundPlayer interface {
+	Play(events ...hook.Event)
+	SetSettings(settings save.SoundSettings) error
+	ToggleMuted() bool
+}
+
+// ScriptEngine runs the message transforms and slash commands of the user scripts
+type ScriptEngine interface {
+	Transform(msg script.Message) script.TransformResult
+	HasCommand(name string) bool
+	Commands() []script.Command
+	RunCommand(name string, ctx script.CommandContext) (script.CommandResult, error)
+	Reload() error
+}
+
+// NoteStore persists the notes of channels and users
+type NoteStore interface {
+	Notes(kind save.NoteKind, name string) ([]save.Note, error)
+	AddNote(kind save.NoteKind, name string, note save.Note) error
+	RemoveNote(kind save.NoteKind, name string, index int) error
+}
+
+// CommandStore persists the quotes and counters of the /quote and /count commands per channel
+type CommandStore interface {
+	Quotes(channel string) ([]save.Quote, error)
+	AddQuote(channel string, quote save.Quote) (int, error)
+	RemoveQuote(channel string, index int) error
+	Counter(channel, name string) (int, error)
+	ChangeCounter(channel, name string, change func(value int) int) (int, error)
+}
+
+// WatchtimeStore persists the time channel tabs were open and focused
+type WatchtimeStore interface {
+	Watchtime() (map[string]save.Watchtime, error)
+	AddWatchtime(add map[string]save.Watchtime) error
+}
+
+// UpdateChecker reports releases newer than the running version
+type UpdateChecker interface {
+	Check(ctx context.Context) (selfupdate.Release, bool, error)
+	Dismiss(version string) error
+}
+
+// Translator translates the text of chat messages
+type Translator interface {
+	Translate(ctx context.Context, text string) (translate.Result, error)
+}
+
+// LinkShortener shortens long links of sent messages
+type LinkShortener interface {
+	Shorten(ctx context.Context, link string) (string, error)
+}
+
+// TmuxWindow renames the tmux window Chatuino runs in, see the tmux package
+type TmuxWindow interface {
+	RenameWindow(name string) error
+}
+
+// ImageUploader uploads images pasted into the message input and returns their link
+type ImageUploader interface {
+	Upload(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// SpellChecker checks the words of the message input, words added by the user are persisted
+type SpellChecker interface {
+	component.SpellChecker
+	Add(word string) error
 }
 
 type AppStateManager interface {
@@ -85,9 +238,10 @@ type AppStateManager interface {
 }
 
 type DependencyContainer struct {
-	UserConfig UserConfiguration
-	Keymap     save.KeyMap
-	Accounts   []save.Account
+	UserConfig     UserConfiguration
+	Keymap         save.KeyMap
+	ChannelKeymaps save.ChannelKeyMaps // optional, keymaps of channels with overrides in the keymap file, merged over Keymap
+	Accounts       []save.Account
 
 	ServerAPI      ChatuinoServer
 	APIUserClients map[string]APIClient
@@ -102,4 +256,46 @@ type DependencyContainer struct {
 	MessageLogger        MessageLogger
 	Pool                 ConnectionPool
 	AppStateManager      AppStateManager
+	ConfigSource         ConfigSource          // optional, enables live reload of the config files
+	BuildReplacers       ReplacerFactory       // optional, used to apply changed graphic and badge settings
+	Hooks                HookRunner            // optional, runs hooks for events received from chat
+	Sounds               SoundPlayer           // optional, plays sounds for events received from chat
+	Scripts              ScriptEngine          // optional, transforms messages and adds slash commands
+	Logs                 *logbuffer.Ring       // optional, recent log events shown in the debug log
+	Traffic              *twitchirc.Traffic    // optional, raw IRC lines shown in the IRC inspector tab
+	OBS                  OBSClient             // optional, shows the OBS status and enables the /obs command
+	Cosmetics            CosmeticCache         // optional, 7TV name paints and badges of chatters
+	Bots                 BotList               // optional, marks messages of known bots
+	Blocks               BlockList             // optional, hides messages of users blocked on Twitch
+	Profanity            *profanity.Filter     // optional, masks profanity in channels it is enabled for
+	Notes                NoteStore             // optional, notes of channels and users
+	Commands             CommandStore          // optional, quotes and counters of the /quote and /count commands
+	Watchtime            WatchtimeStore        // optional, time channel tabs were open and focused
+	OnPanic              PanicHandler          // optional, receives panics of the UI with their stack before Bubble Tea recovers from them
+	Updates              UpdateChecker         // optional, shows a notice when a newer release is available
+	Translator           Translator            // optional, translates the selected message
+	SpellChecker         SpellChecker          // optional, underlines misspelled words in the message input
+	Shortener            LinkShortener         // optional, offers to shorten long links before sending
+	ImageUploader        ImageUploader         // optional, offers to upload pasted images and insert their link
+	TmuxWindow           TmuxWindow            // optional, renamed to the focused channel while running inside tmux
+	Warnings             []string              // optional, shown in the focused tab once the session is restored, like graphics disabled on startup
+	YouTube              chatprovider.Provider // optional, enables tabs of YouTube live chats
+	Kick                 chatprovider.Provider // optional, enables read only tabs of Kick chats
+	Replay               *Replay               // optional, set by the replay and vod commands, opens the replay tab instead of restoring the session
+	HTTPClient           *http.Client          // optional, used for third party APIs like IVR, defaults to http.DefaultClient
+	Offline              bool                  // optional, set by --offline, periodic refreshes are skipped since every request fails
+}
+
+// keymapFor returns the keymap of the channel, Keymap for channels without overrides
+func (d *DependencyContainer) keymapFor(channel string) save.KeyMap {
+	return d.ChannelKeymaps.For(channel, d.Keymap)
+}
+
+// httpClient returns the client used for third party APIs
+func (d *DependencyContainer) httpClient() *http.Client {
+	if d.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return d.HTTPClient
 }
@@ -0,0 +1,18 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/julez-dev/chatuino/save"
+)
+
+// newTestDeps returns the dependencies of a test with the default settings, theme and keymap,
+// tests change the settings and add the dependencies they need
+func newTestDeps(t *testing.T) *DependencyContainer {
+	t.Helper()
+
+	return &DependencyContainer{
+		UserConfig: UserConfiguration{Settings: save.BuildDefaultSettings(), Theme: save.BuildDefaultTheme()},
+		Keymap:     save.BuildDefaultKeyMap(),
+	}
+}
@@ -0,0 +1,107 @@
+package mainui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/ui/component"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_broadcastTab_Draft(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		draft   string
+		created bool // the message input is created once the channel is resolved
+		input   string
+		want    string
+	}{
+		{name: "restored draft before input is created", draft: "unsent message", want: "unsent message"},
+		{name: "input replaces restored draft", draft: "unsent message", created: true, input: "typed", want: "typed"},
+		{name: "cleared input", draft: "unsent message", created: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tab := &broadcastTab{draft: tt.draft}
+			if tt.created {
+				tab.messageInput = component.NewSuggestionTextInput(nil, nil)
+				tab.messageInput.SetValue(tt.input)
+			}
+
+			require.Equal(t, tt.want, tab.Draft())
+		})
+	}
+}
+
+func Test_broadcastTab_updateDraftIndicator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		hasDraft bool
+		input    string
+		want     tea.Msg
+	}{
+		{name: "input became non-empty", input: "hello", want: tabDraftMessage{tabID: "tab", hasDraft: true}},
+		{name: "input became empty", hasDraft: true, input: "", want: tabDraftMessage{tabID: "tab", hasDraft: false}},
+		{name: "whitespace is no draft", input: "   "},
+		{name: "unchanged non-empty input", hasDraft: true, input: "hello again"},
+		{name: "unchanged empty input", input: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tab := &broadcastTab{
+				id:           "tab",
+				hasDraft:     tt.hasDraft,
+				messageInput: component.NewSuggestionTextInput(nil, nil),
+			}
+			tab.messageInput.SetValue(tt.input)
+
+			cmd := tab.updateDraftIndicator()
+			if tt.want == nil {
+				require.Nil(t, cmd)
+				return
+			}
+
+			require.NotNil(t, cmd)
+			require.Equal(t, tt.want, cmd())
+			require.Equal(t, tt.want.(tabDraftMessage).hasDraft, tab.hasDraft)
+		})
+	}
+}
+
+func Test_tabHeader_draftMarker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header func(deps *DependencyContainer) header
+	}{
+		{name: "horizontal", header: func(deps *DependencyContainer) header { return newHorizontalTabHeader(80, deps) }},
+		{name: "vertical", header: func(deps *DependencyContainer) header { return newVerticalTabHeader(40, 20, deps) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := tt.header(newTestDeps(t))
+			id, _ := h.AddTab("lirik", "julez")
+			require.NotContains(t, h.View(), "[✎]")
+
+			h, _ = h.Update(tabDraftMessage{tabID: id, hasDraft: true})
+			require.Contains(t, h.View(), "[✎]")
+
+			h, _ = h.Update(tabDraftMessage{tabID: id, hasDraft: false})
+			require.NotContains(t, h.View(), "[✎]")
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package mainui
+
+import (
+	"strings"
+)
+
+// emoteRepeat is an emote a message consists of, repeated count times
+type emoteRepeat struct {
+	emote string
+	count int
+}
+
+// findEmoteRepeat returns the emote the message consists of, if the message only repeats one of the replaced emotes at least minRepeats times.
+// The zero value is returned for all other messages.
+func findEmoteRepeat(message string, emotes map[string]string, minRepeats int) emoteRepeat {
+	words := strings.Fields(message)
+	if len(words) < max(minRepeats, 2) {
+		return emoteRepeat{}
+	}
+
+	if _, ok := emotes[words[0]]; !ok {
+		return emoteRepeat{}
+	}
+
+	for _, word := range words[1:] {
+		if word != words[0] {
+			return emoteRepeat{}
+		}
+	}
+
+	return emoteRepeat{emote: words[0], count: len(words)}
+}
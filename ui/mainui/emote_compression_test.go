@@ -0,0 +1,52 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findEmoteRepeat(t *testing.T) {
+	t.Parallel()
+
+	emotes := map[string]string{"catJAM": "catJAM", "Kappa": "Kappa"}
+
+	tests := []struct {
+		name    string
+		message string
+		want    emoteRepeat
+	}{
+		{name: "repeated emote", message: "catJAM catJAM catJAM catJAM", want: emoteRepeat{emote: "catJAM", count: 4}},
+		{name: "extra spaces", message: " catJAM  catJAM catJAM ", want: emoteRepeat{emote: "catJAM", count: 3}},
+		{name: "too few repeats", message: "catJAM catJAM"},
+		{name: "different emotes", message: "catJAM Kappa catJAM"},
+		{name: "text between emotes", message: "catJAM catJAM lol catJAM"},
+		{name: "repeated word", message: "lol lol lol"},
+		{name: "empty", message: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, findEmoteRepeat(tt.message, emotes, 3))
+		})
+	}
+}
+
+func Test_chatWindow_emoteRepeat(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	c := newChatWindow(40, 10, deps)
+	c.handleMessage(chatEventMessage{
+		message:         &twitchirc.PrivateMessage{ID: "1", LoginName: "a", DisplayName: "a", Message: strings.Repeat("catJAM ", 12)},
+		displayModifier: messageContentModifier{emoteRepeat: emoteRepeat{emote: "catJAM", count: 12}},
+	})
+
+	require.Len(t, c.lines, 1)
+	require.True(t, strings.HasSuffix(ansi.Strip(c.lines[0]), "a: catJAM x12"), ansi.Strip(c.lines[0]))
+}
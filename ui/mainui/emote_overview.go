@@ -15,6 +15,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/google/uuid"
 	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/save"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/semaphore"
 )
@@ -41,6 +42,7 @@ type emoteOverview struct {
 	cancel context.CancelFunc
 
 	emoteReplacer EmoteReplacer
+	deps          *DependencyContainer
 	emotes        map[string][]emoteWithOverwrite
 	isLoaded      bool
 }
@@ -50,16 +52,26 @@ var customEllipsisSpinner = spinner.Spinner{
 	FPS:    time.Second / 3, //nolint:mnd
 }
 
-func NewEmoteOverview(channelID string, cache EmoteCache, replacer EmoteReplacer, width, height int) *emoteOverview {
+// spinnerView renders the spinner, with reduced motion its last frame is shown instead of the animation
+func spinnerView(s spinner.Model, settings save.Settings) string {
+	if settings.Accessibility.ReducedMotion && len(s.Spinner.Frames) > 0 {
+		return s.Spinner.Frames[len(s.Spinner.Frames)-1]
+	}
+
+	return s.View()
+}
+
+func NewEmoteOverview(channelID string, deps *DependencyContainer, width, height int) *emoteOverview {
 	vp := viewport.New(width, height)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &emoteOverview{
 		id:            uuid.New().String(),
-		store:         cache,
+		store:         deps.EmoteCache,
 		channelID:     channelID,
-		emoteReplacer: replacer,
+		emoteReplacer: deps.EmoteReplacer,
+		deps:          deps,
 		vp:            vp,
 		spinner:       spinner.New(spinner.WithSpinner(customEllipsisSpinner)),
 		ctx:           ctx,
@@ -167,7 +179,7 @@ func (e *emoteOverview) Update(msg tea.Msg) (*emoteOverview, tea.Cmd) {
 
 func (e *emoteOverview) View() string {
 	if !e.isLoaded {
-		return lipgloss.NewStyle().Width(e.vp.Width).Height(e.vp.Height).AlignHorizontal(lipgloss.Center).AlignVertical(lipgloss.Center).Render(e.spinner.View() + " Loading Emote Overview")
+		return lipgloss.NewStyle().Width(e.vp.Width).Height(e.vp.Height).AlignHorizontal(lipgloss.Center).AlignVertical(lipgloss.Center).Render(spinnerView(e.spinner, e.deps.UserConfig.Settings) + " Loading Emote Overview")
 	}
 
 	return e.vp.View()
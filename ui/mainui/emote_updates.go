@@ -0,0 +1,174 @@
+package mainui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+type emoteUpdateTickMessage struct{}
+
+// emoteUpdatesMessage holds the emote changes of reloaded channels, keyed by channel ID
+type emoteUpdatesMessage struct {
+	changes map[string][]string
+}
+
+// tickEmoteUpdates schedules the next reload of the emotes of open channels. When reloading is disabled the setting
+// is checked again every minute, so enabling it in the config takes effect without a restart.
+func (r *Root) tickEmoteUpdates() tea.Cmd {
+	// offline the emotes can't change
+	if r.dependencies.Offline {
+		return nil
+	}
+
+	interval := r.dependencies.UserConfig.Settings.Chat.EmoteUpdates.RefreshInterval
+	if interval == 0 {
+		interval = time.Minute
+	}
+
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return emoteUpdateTickMessage{}
+	})
+}
+
+// reloadEmotes fetches the 7TV, BTTV and FFZ emotes of all open channels again and reports what changed
+func (r *Root) reloadEmotes() tea.Cmd {
+	// replays show old messages, emotes changed since then are of no interest
+	if r.dependencies.UserConfig.Settings.Chat.EmoteUpdates.RefreshInterval == 0 || r.idle.idle || r.dependencies.Replay != nil {
+		return r.tickEmoteUpdates()
+	}
+
+	var channelIDs []string
+	for _, tab := range r.tabs {
+		if tab.Kind() != broadcastTabKind || !tab.IsDataLoaded() || slices.Contains(channelIDs, tab.ChannelID()) {
+			continue
+		}
+
+		channelIDs = append(channelIDs, tab.ChannelID())
+	}
+
+	if len(channelIDs) == 0 {
+		return r.tickEmoteUpdates()
+	}
+
+	emoteCache := r.dependencies.EmoteCache
+
+	return func() tea.Msg {
+		changes := map[string][]string{}
+
+		for _, channelID := range channelIDs {
+			before := emoteCache.GetAllForChannel(channelID)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+			err := emoteCache.Reload(ctx, channelID)
+			cancel()
+
+			if err != nil {
+				log.Logger.Warn().Err(err).Str("channel-id", channelID).Msg("failed to reload emotes")
+				continue
+			}
+
+			added, removed := emote.Diff(before, emoteCache.GetAllForChannel(channelID))
+
+			// emotes of platforms which could not be fetched are not changed, the cached emotes are used instead
+			ignored := append(emoteCache.Degraded(channelID), emote.Twitch)
+			changes[channelID] = formatEmoteChanges(added, removed, ignored)
+		}
+
+		return emoteUpdatesMessage{changes: changes}
+	}
+}
+
+func (r *Root) handleEmoteUpdates(msg emoteUpdatesMessage) tea.Cmd {
+	cmds := []tea.Cmd{r.tickEmoteUpdates()}
+	notify := r.dependencies.UserConfig.Settings.Chat.EmoteUpdates.Notify
+
+	for _, tab := range r.tabs {
+		lines, reloaded := msg.changes[tab.ChannelID()]
+		if tab.Kind() != broadcastTabKind || !reloaded {
+			continue
+		}
+
+		tabID, accountID := tab.ID(), tab.AccountID()
+
+		// the tab rebuilds its emote suggestions and leaves degraded mode if it was in it
+		cmds = append(cmds, func() tea.Msg {
+			return emoteSetRefreshedMessage{targetID: tabID}
+		})
+
+		if !notify {
+			continue
+		}
+
+		for _, line := range lines {
+			cmds = append(cmds, func() tea.Msg {
+				return requestLocalMessageHandleMessage{
+					tabID:     tabID,
+					accountID: accountID,
+					message: &twitchirc.Notice{
+						FakeTimestamp: time.Now(),
+						Message:       line,
+					},
+				}
+			})
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// formatEmoteChanges returns one line per platform listing its added and removed emotes, like
+// "SevenTV: added peepoSnow, removed OMEGALUL". Platforms in ignored are left out.
+func formatEmoteChanges(added, removed emote.EmoteSet, ignored []emote.Platform) []string {
+	type change struct {
+		added, removed []string
+	}
+
+	changes := map[emote.Platform]*change{}
+	get := func(p emote.Platform) *change {
+		if changes[p] == nil {
+			changes[p] = &change{}
+		}
+
+		return changes[p]
+	}
+
+	for _, e := range added {
+		if !slices.Contains(ignored, e.Platform) {
+			get(e.Platform).added = append(get(e.Platform).added, e.Text)
+		}
+	}
+
+	for _, e := range removed {
+		if !slices.Contains(ignored, e.Platform) {
+			get(e.Platform).removed = append(get(e.Platform).removed, e.Text)
+		}
+	}
+
+	var lines []string
+	for _, p := range []emote.Platform{emote.SevenTV, emote.BTTV, emote.FFZ} {
+		c, ok := changes[p]
+		if !ok {
+			continue
+		}
+
+		var parts []string
+		if len(c.added) > 0 {
+			parts = append(parts, "added "+strings.Join(c.added, " "))
+		}
+
+		if len(c.removed) > 0 {
+			parts = append(parts, "removed "+strings.Join(c.removed, " "))
+		}
+
+		lines = append(lines, p.String()+": "+strings.Join(parts, ", "))
+	}
+
+	return lines
+}
@@ -0,0 +1,30 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatEmoteChanges(t *testing.T) {
+	t.Parallel()
+
+	added := emote.EmoteSet{
+		{Text: "peepoSnow", Platform: emote.SevenTV},
+		{Text: "catJAM", Platform: emote.SevenTV},
+		{Text: "monkaS", Platform: emote.FFZ},
+		{Text: "lirikHi", Platform: emote.Twitch},
+	}
+	removed := emote.EmoteSet{
+		{Text: "OMEGALUL", Platform: emote.SevenTV},
+		{Text: "KEKW", Platform: emote.BTTV},
+	}
+
+	require.Equal(t, []string{
+		"SevenTV: added peepoSnow catJAM, removed OMEGALUL",
+		"FFZ: added monkaS",
+	}, formatEmoteChanges(added, removed, []emote.Platform{emote.BTTV, emote.Twitch}))
+
+	require.Empty(t, formatEmoteChanges(nil, nil, nil))
+}
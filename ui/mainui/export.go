@@ -0,0 +1,153 @@
+package mainui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/chatexport"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// exportOptions are the arguments of the /export command, given in any order
+type exportOptions struct {
+	format chatexport.Format
+	since  time.Time // zero exports all messages
+	logs   bool      // export the logged history instead of the chat buffer
+}
+
+func parseExportOptions(args []string, now time.Time) (exportOptions, error) {
+	opts := exportOptions{format: chatexport.FormatText}
+
+	for _, arg := range args {
+		if arg == "logs" {
+			opts.logs = true
+			continue
+		}
+
+		if format, err := chatexport.ParseFormat(arg); err == nil {
+			opts.format = format
+			continue
+		}
+
+		since, err := chatexport.ParseTime(arg, now)
+		if err != nil {
+			return exportOptions{}, fmt.Errorf("unknown argument %q, usage: /export [text|json|csv] [logs] [since, e.g. 30m]", arg)
+		}
+
+		opts.since = since
+	}
+
+	return opts, nil
+}
+
+// bufferedExportMessages returns the messages shown in the chat window, including deleted ones, without own messages which were not sent
+func (c *chatWindow) bufferedExportMessages() []chatexport.Message {
+	return exportMessages(c.entries)
+}
+
+// exportMessages converts the chat messages of entries, system messages and own messages which were not sent are skipped
+func exportMessages(entries []*chatEntry) []chatexport.Message {
+	messages := make([]chatexport.Message, 0, len(entries))
+	for _, e := range entries {
+		msg, ok := e.Event.message.(*twitchirc.PrivateMessage)
+		if !ok || e.Event.send != nil && e.Event.send.state != sendConfirmed {
+			continue
+		}
+
+		exported := chatexport.FromPrivateMessage(msg)
+		if exported.Channel == "" {
+			exported.Channel = e.Event.channel
+		}
+
+		messages = append(messages, exported)
+	}
+
+	return messages
+}
+
+// writeExportFile writes messages to a new file in the working directory and returns the absolute path of the file
+func writeExportFile(name string, format chatexport.Format, messages []chatexport.Message) (string, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	if err := chatexport.Write(f, format, messages); err != nil {
+		_ = f.Close()
+		_ = os.Remove(name)
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if abs, err := filepath.Abs(name); err == nil {
+		name = abs
+	}
+
+	return name, nil
+}
+
+// handleExportCommand runs /export, which writes the chat buffer or the logged history of the channel to a file in the working directory
+func (t *broadcastTab) handleExportCommand(args []string) tea.Cmd {
+	tabID, accountID, channel := t.id, t.account.ID, t.channelLogin
+
+	notice := func(text string) tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+
+	opts, err := parseExportOptions(args, time.Now())
+	if err != nil {
+		return func() tea.Msg { return notice(err.Error()) }
+	}
+
+	if opts.logs && t.deps.MessageLogger == nil {
+		return func() tea.Msg { return notice("Chat logs are not available") }
+	}
+
+	var buffered []chatexport.Message
+	if !opts.logs {
+		buffered = t.chatWindow.bufferedExportMessages()
+	}
+
+	logger := t.deps.MessageLogger
+
+	return func() tea.Msg {
+		messages := buffered
+
+		if opts.logs {
+			entries, err := logger.MessagesInChannel(channel)
+			if err != nil {
+				return notice("Failed to read chat logs: " + err.Error())
+			}
+
+			messages = make([]chatexport.Message, 0, len(entries))
+			for _, entry := range entries {
+				msg := chatexport.FromPrivateMessage(entry.PrivateMessage)
+				msg.SentAt = entry.SentAt
+				messages = append(messages, msg)
+			}
+		}
+
+		messages = chatexport.Range{Since: opts.since}.Filter(messages)
+
+		name := fmt.Sprintf("%s_%s.%s", channel, time.Now().Format("2006-01-02_15_04_05"), opts.format.Extension())
+		name, err := writeExportFile(name, opts.format, messages)
+		if err != nil {
+			return notice("Failed to write export: " + err.Error())
+		}
+
+		return notice(fmt.Sprintf("Exported %d messages to %s", len(messages), name))
+	}
+}
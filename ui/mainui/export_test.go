@@ -0,0 +1,66 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/chatexport"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseExportOptions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    exportOptions
+		wantErr bool
+	}{
+		{name: "defaults", want: exportOptions{format: chatexport.FormatText}},
+		{name: "format and since", args: []string{"30m", "csv"}, want: exportOptions{format: chatexport.FormatCSV, since: now.Add(-30 * time.Minute)}},
+		{name: "logs", args: []string{"logs", "json"}, want: exportOptions{format: chatexport.FormatJSON, logs: true}},
+		{name: "unknown argument", args: []string{"xml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseExportOptions(tt.args, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_chatWindow_bufferedExportMessages(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	c := newChatWindow(80, 20, deps)
+	c.setAccount(save.Account{ID: "1", DisplayName: "me"})
+
+	c.handleMessage(chatEventMessage{channel: "lirik", message: &twitchirc.PrivateMessage{ID: "m1", UserID: "2", DisplayName: "viewer", Message: "hello"}})
+	c.handleMessage(chatEventMessage{channel: "lirik", message: &twitchirc.Notice{Message: "notice"}})
+	c.handleMessage(chatEventMessage{
+		channel: "lirik",
+		message: &twitchirc.PrivateMessage{UserID: "1", DisplayName: "me", Message: "pending"},
+		send:    &sentMessage{nonce: "a", text: "pending"},
+	})
+
+	messages := c.bufferedExportMessages()
+	require.Len(t, messages, 1)
+	require.Equal(t, "m1", messages[0].ID)
+	require.Equal(t, "lirik", messages[0].Channel)
+}
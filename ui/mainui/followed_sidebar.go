@@ -0,0 +1,447 @@
+package mainui
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const followedSidebarWidth = 32
+
+type followedStreamsFetcher interface {
+	followedFetcher
+	GetFollowedStreams(ctx context.Context, userID string) ([]twitchapi.StreamData, error)
+}
+
+type followedSidebarEntry struct {
+	login       string
+	displayName string
+	viewers     int
+	isLive      bool
+}
+
+type followedSidebarDataMessage struct {
+	generation int
+	entries    []followedSidebarEntry
+	err        error
+}
+
+type followedSidebarRefreshMessage struct {
+	generation int
+}
+
+type followedSidebar struct {
+	width, height int
+	visible       bool
+	focused       bool
+
+	deps    *DependencyContainer
+	account save.Account
+	fetcher followedStreamsFetcher
+
+	// generation is incremented every time the sidebar is shown or hidden, so stale refresh ticks are dropped
+	generation int
+	hasLoaded  bool
+	err        error
+
+	idle    bool // no refreshes while the user is idle
+	skipped bool // a refresh was skipped while idle, it is fetched once the user is back
+
+	entries []followedSidebarEntry
+	cursor  int
+	offset  int
+}
+
+func newFollowedSidebar(deps *DependencyContainer) *followedSidebar {
+	s := &followedSidebar{
+		width: followedSidebarWidth,
+		deps:  deps,
+	}
+	s.account, s.fetcher = followedStreamsAccount(deps)
+
+	return s
+}
+
+// followedStreamsAccount returns the account used to fetch followed channels, the main account is preferred.
+// The fetcher is nil if no account is able to fetch followed streams.
+func followedStreamsAccount(deps *DependencyContainer) (save.Account, followedStreamsFetcher) {
+	accounts := slices.Clone(deps.Accounts)
+	slices.SortStableFunc(accounts, func(a, b save.Account) int {
+		if a.IsMain == b.IsMain {
+			return 0
+		}
+
+		if a.IsMain {
+			return -1
+		}
+
+		return 1
+	})
+
+	for _, acc := range accounts {
+		if acc.IsAnonymous {
+			continue
+		}
+
+		if f, ok := deps.APIUserClients[acc.ID].(followedStreamsFetcher); ok {
+			return acc, f
+		}
+	}
+
+	return save.Account{}, nil
+}
+
+func (s *followedSidebar) show() tea.Cmd {
+	s.visible = true
+	s.skipped = false
+	s.generation++
+	return s.fetch()
+}
+
+func (s *followedSidebar) hide() {
+	s.visible = false
+	s.focused = false
+	s.generation++
+}
+
+func (s *followedSidebar) focus() {
+	s.focused = true
+}
+
+func (s *followedSidebar) blur() {
+	s.focused = false
+}
+
+func (s *followedSidebar) setHeight(height int) {
+	s.height = height
+	s.clampOffset()
+}
+
+func (s *followedSidebar) fetch() tea.Cmd {
+	if s.fetcher == nil {
+		return nil
+	}
+
+	generation := s.generation
+	fetcher := s.fetcher
+	userID := s.account.ID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+		defer cancel()
+
+		followed, err := fetcher.FetchUserFollowedChannels(ctx, userID, "")
+		if err != nil {
+			return followedSidebarDataMessage{generation: generation, err: err}
+		}
+
+		streams, err := fetcher.GetFollowedStreams(ctx, userID)
+		if err != nil {
+			return followedSidebarDataMessage{generation: generation, err: err}
+		}
+
+		return followedSidebarDataMessage{
+			generation: generation,
+			entries:    buildFollowedSidebarEntries(followed, streams),
+		}
+	}
+}
+
+func (s *followedSidebar) tickRefresh() tea.Cmd {
+	generation := s.generation
+	return tea.Tick(s.deps.UserConfig.Settings.FollowedSidebar.RefreshInterval, func(_ time.Time) tea.Msg {
+		return followedSidebarRefreshMessage{generation: generation}
+	})
+}
+
+// buildFollowedSidebarEntries merges the followed channels with the currently live streams.
+// Live channels are listed first, sorted by viewer count, followed by all offline channels in alphabetical order.
+func buildFollowedSidebarEntries(followed []twitchapi.FollowedChannel, streams []twitchapi.StreamData) []followedSidebarEntry {
+	entries := make([]followedSidebarEntry, 0, len(followed))
+	live := make(map[string]struct{}, len(streams))
+
+	for _, stream := range streams {
+		login := strings.ToLower(stream.UserLogin)
+		if _, has := live[login]; has {
+			continue
+		}
+
+		live[login] = struct{}{}
+		entries = append(entries, followedSidebarEntry{
+			login:       login,
+			displayName: stream.UserName,
+			viewers:     stream.ViewerCount,
+			isLive:      true,
+		})
+	}
+
+	for _, channel := range followed {
+		login := strings.ToLower(channel.BroadcasterLogin)
+		if _, has := live[login]; has {
+			continue
+		}
+
+		entries = append(entries, followedSidebarEntry{
+			login:       login,
+			displayName: channel.BroadcasterName,
+		})
+	}
+
+	slices.SortStableFunc(entries, func(a, b followedSidebarEntry) int {
+		if a.isLive != b.isLive {
+			if a.isLive {
+				return -1
+			}
+			return 1
+		}
+
+		if a.isLive {
+			if c := cmp.Compare(b.viewers, a.viewers); c != 0 {
+				return c
+			}
+		}
+
+		return cmp.Compare(a.login, b.login)
+	})
+
+	return entries
+}
+
+func (s *followedSidebar) Update(msg tea.Msg) (*followedSidebar, tea.Cmd) {
+	switch msg := msg.(type) {
+	case followedSidebarDataMessage:
+		if msg.generation != s.generation || !s.visible {
+			return s, nil
+		}
+
+		s.hasLoaded = true
+		s.err = msg.err
+
+		if msg.err != nil {
+			log.Logger.Err(msg.err).Msg("failed to fetch followed streams for sidebar")
+		} else {
+			s.setEntries(msg.entries)
+		}
+
+		return s, s.tickRefresh()
+	case followedSidebarRefreshMessage:
+		if msg.generation != s.generation || !s.visible {
+			return s, nil
+		}
+
+		if s.idle {
+			s.skipped = true
+			return s, nil
+		}
+
+		return s, s.fetch()
+	case idleChangedMessage:
+		s.idle = msg.idle
+
+		if !s.idle && s.skipped {
+			s.skipped = false
+
+			if s.visible {
+				return s, s.fetch()
+			}
+		}
+
+		return s, nil
+	case tea.KeyMsg:
+		if !s.focused {
+			return s, nil
+		}
+
+		switch {
+		case key.Matches(msg, s.deps.Keymap.Up):
+			s.moveCursor(-1)
+		case key.Matches(msg, s.deps.Keymap.Down):
+			s.moveCursor(1)
+		case key.Matches(msg, s.deps.Keymap.Confirm):
+			if len(s.entries) == 0 {
+				return s, nil
+			}
+
+			entry := s.entries[s.cursor]
+			account := s.account
+			s.blur()
+
+			return s, func() tea.Msg {
+				return joinChannelMessage{
+					tabKind: broadcastTabKind,
+					channel: entry.login,
+					account: account,
+				}
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// setEntries replaces the listed channels while keeping the cursor on the previously selected channel
+func (s *followedSidebar) setEntries(entries []followedSidebarEntry) {
+	var selected string
+	if len(s.entries) > s.cursor {
+		selected = s.entries[s.cursor].login
+	}
+
+	s.entries = entries
+	s.cursor = 0
+
+	for i, e := range entries {
+		if e.login == selected {
+			s.cursor = i
+			break
+		}
+	}
+
+	s.clampOffset()
+}
+
+func (s *followedSidebar) moveCursor(delta int) {
+	if len(s.entries) == 0 {
+		return
+	}
+
+	s.cursor = min(max(s.cursor+delta, 0), len(s.entries)-1)
+	s.clampOffset()
+}
+
+func (s *followedSidebar) visibleRows() int {
+	return max(1, s.height-2) // -2 for top/bottom borders
+}
+
+func (s *followedSidebar) clampOffset() {
+	rows := s.visibleRows()
+
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+
+	if s.cursor >= s.offset+rows {
+		s.offset = s.cursor - rows + 1
+	}
+
+	s.offset = max(0, min(s.offset, len(s.entries)-rows))
+}
+
+func (s *followedSidebar) View() string {
+	borderColor := s.deps.UserConfig.Theme.DimmedTextColor
+	if s.focused {
+		borderColor = s.deps.UserConfig.Theme.BorderColor
+	}
+
+	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(borderColor))
+	bulletStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(s.deps.UserConfig.Theme.InputPromptColor))
+	activeStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(s.deps.UserConfig.Theme.InputPromptColor))
+	liveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(s.deps.UserConfig.Theme.ChatErrorColor))
+	offlineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(s.deps.UserConfig.Theme.DimmedTextColor))
+
+	innerWidth := s.width - 2
+	rows := s.visibleRows()
+
+	lines := make([]string, 0, rows)
+
+	switch {
+	case s.fetcher == nil:
+		lines = append(lines, offlineStyle.Render(" No account logged in"))
+	case !s.hasLoaded:
+		lines = append(lines, offlineStyle.Render(" Loading..."))
+	case s.err != nil && len(s.entries) == 0:
+		lines = append(lines, offlineStyle.Render(" Failed to load channels"))
+	case len(s.entries) == 0:
+		lines = append(lines, offlineStyle.Render(" No followed channels"))
+	}
+
+	p := message.NewPrinter(language.English)
+
+	for i := s.offset; i < len(s.entries) && len(lines) < rows; i++ {
+		entry := s.entries[i]
+		selected := s.focused && i == s.cursor
+
+		prefix := "  "
+		if selected {
+			prefix = bulletStyle.Render("▸ ")
+		}
+
+		var viewers string
+		if entry.isLive {
+			viewers = p.Sprintf("%d", entry.viewers)
+		}
+
+		// prefix (2) + live dot (2) + name + space + viewer count
+		nameWidth := max(1, innerWidth-4-lipgloss.Width(viewers)-1)
+		name := lipgloss.NewStyle().MaxWidth(nameWidth).Render(entry.displayName)
+		name += strings.Repeat(" ", max(0, nameWidth-lipgloss.Width(name)))
+
+		var line string
+		switch {
+		case selected:
+			line = activeStyle.Render(name)
+		case entry.isLive:
+			line = name
+		default:
+			line = offlineStyle.Render(name)
+		}
+
+		dot := "  "
+		if entry.isLive {
+			dot = liveStyle.Render("●") + " "
+		}
+
+		lines = append(lines, prefix+dot+line+" "+offlineStyle.Render(viewers))
+	}
+
+	for len(lines) < rows {
+		lines = append(lines, "")
+	}
+
+	showUpArrow := s.offset > 0
+	showDownArrow := s.offset+rows < len(s.entries)
+
+	topLabel := "[ Followed ]"
+	if len(s.entries) > 0 {
+		topLabel = fmt.Sprintf("[ Followed %d/%d ]", s.cursor+1, len(s.entries))
+	}
+
+	topFill := innerWidth - lipgloss.Width(topLabel) - 2
+	if showUpArrow {
+		topFill -= 2 // space for " ▲"
+	}
+	topBorder := "┌─" + topLabel + strings.Repeat("─", max(0, topFill))
+	if showUpArrow {
+		topBorder += " ▲"
+	}
+	topBorder += "─┐"
+
+	bottomFill := innerWidth
+	bottomBorder := "└"
+	if showDownArrow {
+		bottomBorder += "▼ "
+		bottomFill -= 2 // space for "▼ "
+	}
+	bottomBorder += strings.Repeat("─", max(0, bottomFill)) + "┘"
+
+	var b strings.Builder
+	b.WriteString(borderStyle.Render(topBorder) + "\n")
+	for _, line := range lines {
+		padNeeded := max(0, innerWidth-lipgloss.Width(line))
+		b.WriteString(borderStyle.Render("│") + line + strings.Repeat(" ", padNeeded) + borderStyle.Render("│") + "\n")
+	}
+	b.WriteString(borderStyle.Render(bottomBorder))
+
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildFollowedSidebarEntries(t *testing.T) {
+	t.Parallel()
+
+	followed := []twitchapi.FollowedChannel{
+		{BroadcasterLogin: "sodapoppin", BroadcasterName: "sodapoppin"},
+		{BroadcasterLogin: "lirik", BroadcasterName: "LIRIK"},
+		{BroadcasterLogin: "xqc", BroadcasterName: "xQc"},
+		{BroadcasterLogin: "amouranth", BroadcasterName: "Amouranth"},
+	}
+
+	streams := []twitchapi.StreamData{
+		{UserLogin: "lirik", UserName: "LIRIK", ViewerCount: 1200},
+		{UserLogin: "xqc", UserName: "xQc", ViewerCount: 45000},
+	}
+
+	got := buildFollowedSidebarEntries(followed, streams)
+
+	require.Equal(t, []followedSidebarEntry{
+		{login: "xqc", displayName: "xQc", viewers: 45000, isLive: true},
+		{login: "lirik", displayName: "LIRIK", viewers: 1200, isLive: true},
+		{login: "amouranth", displayName: "Amouranth"},
+		{login: "sodapoppin", displayName: "sodapoppin"},
+	}, got)
+}
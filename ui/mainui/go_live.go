@@ -0,0 +1,307 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/rs/zerolog/log"
+)
+
+// maxGoLiveToasts is the number of go live notifications shown at once, older ones are hidden
+const maxGoLiveToasts = 3
+
+type goLiveRefreshMessage struct{}
+
+type goLiveStreamsMessage struct {
+	streams []twitchapi.StreamData
+	err     error
+}
+
+type goLiveToastExpiredMessage struct {
+	id int
+}
+
+// goLiveToast is a notification about a followed channel which just went live
+type goLiveToast struct {
+	id          int
+	login       string
+	displayName string
+	title       string
+	game        string
+}
+
+// goLiveWatcher polls the live followed channels and shows a notification when one of them goes live.
+// Twitch limits EventSub subscriptions per connection, so followed channels are polled instead of subscribing to stream.online.
+type goLiveWatcher struct {
+	deps    *DependencyContainer
+	account save.Account
+	fetcher followedStreamsFetcher
+	notify  func(title, body string) error // shows a system notification
+	tray    *notificationTray              // optional, keeps the notifications until they are dismissed
+
+	live   map[string]bool // logins live at the last refresh, nil until the first refresh
+	toasts []goLiveToast   // newest first
+	nextID int
+}
+
+func newGoLiveWatcher(deps *DependencyContainer) *goLiveWatcher {
+	w := &goLiveWatcher{
+		deps:   deps,
+		notify: desktopNotify,
+	}
+	w.account, w.fetcher = followedStreamsAccount(deps)
+
+	return w
+}
+
+// start fetches the live channels for the first time, channels already live on startup are not notified about
+func (w *goLiveWatcher) start() tea.Cmd {
+	if !w.deps.UserConfig.Settings.GoLive.Enabled || w.fetcher == nil {
+		return nil
+	}
+
+	return w.fetch()
+}
+
+func (w *goLiveWatcher) fetch() tea.Cmd {
+	fetcher, userID := w.fetcher, w.account.ID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+		defer cancel()
+
+		streams, err := fetcher.GetFollowedStreams(ctx, userID)
+		return goLiveStreamsMessage{streams: streams, err: err}
+	}
+}
+
+func (w *goLiveWatcher) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case goLiveStreamsMessage:
+		refresh := tea.Tick(w.deps.UserConfig.Settings.GoLive.RefreshInterval, func(time.Time) tea.Msg {
+			return goLiveRefreshMessage{}
+		})
+
+		if msg.err != nil {
+			log.Logger.Warn().Err(msg.err).Msg("failed to fetch live followed channels")
+			return refresh
+		}
+
+		cmds := []tea.Cmd{refresh}
+		for _, stream := range w.wentLive(msg.streams) {
+			cmds = append(cmds, w.show(stream))
+		}
+
+		return tea.Batch(cmds...)
+	case goLiveRefreshMessage:
+		return w.fetch()
+	case goLiveToastExpiredMessage:
+		w.toasts = slices.DeleteFunc(w.toasts, func(t goLiveToast) bool { return t.id == msg.id })
+	}
+
+	return nil
+}
+
+// wentLive returns the streams of channels which were offline at the last refresh and are on the notification allowlist
+func (w *goLiveWatcher) wentLive(streams []twitchapi.StreamData) []twitchapi.StreamData {
+	allowlist := w.deps.UserConfig.Settings.GoLive.Channels
+	live := make(map[string]bool, len(streams))
+
+	var started []twitchapi.StreamData
+	for _, stream := range streams {
+		login := strings.ToLower(stream.UserLogin)
+		live[login] = true
+
+		if w.live == nil || w.live[login] {
+			continue
+		}
+
+		if len(allowlist) > 0 && !slices.ContainsFunc(allowlist, func(c string) bool { return strings.EqualFold(c, login) }) {
+			continue
+		}
+
+		started = append(started, stream)
+	}
+
+	w.live = live
+
+	return started
+}
+
+// show adds the notification of the stream and hides it after the toast duration
+func (w *goLiveWatcher) show(stream twitchapi.StreamData) tea.Cmd {
+	w.nextID++
+
+	toast := goLiveToast{
+		id:          w.nextID,
+		login:       strings.ToLower(stream.UserLogin),
+		displayName: stream.UserName,
+		title:       stream.Title,
+		game:        stream.GameName,
+	}
+	w.toasts = append([]goLiveToast{toast}, w.toasts...)
+
+	settings := w.deps.UserConfig.Settings.GoLive
+	cmds := []tea.Cmd{
+		tea.Tick(settings.ToastDuration, func(time.Time) tea.Msg {
+			return goLiveToastExpiredMessage{id: toast.id}
+		}),
+	}
+
+	if w.tray != nil {
+		cmds = append(cmds, w.tray.add(trayNotification{
+			kind:      trayGoLive,
+			accountID: w.account.ID,
+			channel:   toast.login,
+			user:      toast.displayName,
+			text:      toast.title,
+		}))
+	}
+
+	if settings.Desktop {
+		notify := w.notify
+		cmds = append(cmds, func() tea.Msg {
+			if err := notify(toast.displayName+" is live", toast.title); err != nil {
+				log.Logger.Warn().Err(err).Msg("failed to show system notification")
+			}
+			return nil
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// take removes the newest notification, the open and watch actions apply to it
+func (w *goLiveWatcher) take() (goLiveToast, bool) {
+	if len(w.toasts) == 0 {
+		return goLiveToast{}, false
+	}
+
+	toast := w.toasts[0]
+	w.toasts = w.toasts[1:]
+
+	return toast, true
+}
+
+func (w *goLiveWatcher) View(width int) string {
+	if len(w.toasts) == 0 {
+		return ""
+	}
+
+	theme := w.deps.UserConfig.Theme
+	innerWidth := max(min(width-6, 50), 10)
+
+	nameStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ActiveLabelColor))
+	liveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ConnectionDownColor))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	lines := make([]string, 0, maxGoLiveToasts*2+1)
+	for i, toast := range w.toasts[:min(len(w.toasts), maxGoLiveToasts)] {
+		line := liveStyle.Render("●") + " " + nameStyle.Render(toast.displayName) + " is live"
+		if toast.game != "" {
+			line += dimmedStyle.Render(" · " + toast.game)
+		}
+
+		lines = append(lines, ansi.Truncate(line, innerWidth, "…"))
+
+		if toast.title != "" && i == 0 {
+			lines = append(lines, ansi.Truncate(toast.title, innerWidth, "…"))
+		}
+	}
+
+	if hidden := len(w.toasts) - maxGoLiveToasts; hidden > 0 {
+		lines = append(lines, dimmedStyle.Render(fmt.Sprintf("+%d more", hidden)))
+	}
+
+	lines = append(lines, dimmedStyle.Render(ansi.Truncate(fmt.Sprintf("%s open · %s watch",
+		w.deps.Keymap.OpenLiveChannel.Help().Key,
+		w.deps.Keymap.WatchLiveChannel.Help().Key,
+	), innerWidth, "…")))
+
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(strings.Join(lines, "\n"))
+}
+
+// openLiveChannel switches to the tab of the channel of the newest go live notification, the tab is opened if needed
+func (r *Root) openLiveChannel() tea.Cmd {
+	toast, ok := r.goLive.take()
+	if !ok {
+		return nil
+	}
+
+	return r.switchToChannel(r.goLive.account, toast.login)
+}
+
+// switchToChannel switches to the tab of the channel, a tab with the account is opened if there is none
+func (r *Root) switchToChannel(account save.Account, channel string) tea.Cmd {
+	for i, t := range r.tabs {
+		if t.Kind() == broadcastTabKind && strings.EqualFold(t.Channel(), channel) {
+			r.goToTab(i)
+			return nil
+		}
+	}
+
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	return r.openTab(account, channel, broadcastTabKind)
+}
+
+// watchLiveChannel starts the player for the channel of the newest go live notification
+func (r *Root) watchLiveChannel() tea.Cmd {
+	toast, ok := r.goLive.take()
+	if !ok {
+		return nil
+	}
+
+	player, err := buildPlayerCommand(r.dependencies.UserConfig.Settings.Player.Command, toast.login)
+	if err != nil {
+		return r.focusedTabNotice(fmt.Sprintf("Failed to start player: %s", err))
+	}
+
+	wait, err := startPlayer(player, "", toast.login)
+	if err != nil {
+		return r.focusedTabNotice(fmt.Sprintf("Failed to start player: %s", err))
+	}
+
+	r.players = append(r.players, player)
+
+	return wait
+}
+
+// desktopNotify shows a system notification with notify-send on Linux and osascript on macOS
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf(`display notification "%s" with title "%s"`, quote.Replace(body), quote.Replace(title)))
+	case "windows":
+		return errors.New("system notifications are not supported on windows")
+	default:
+		// titles starting with a dash, like a stream title, must not be parsed as options
+		cmd = exec.Command("notify-send", "--app-name=Chatuino", "--", title, body)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_goLiveWatcher(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.GoLive.Enabled = true
+
+	w := newGoLiveWatcher(deps)
+
+	lirik := twitchapi.StreamData{UserLogin: "lirik", UserName: "LIRIK", Title: "variety", GameName: "Just Chatting"}
+	xqc := twitchapi.StreamData{UserLogin: "xqc", UserName: "xQc", Title: "react andy"}
+	soda := twitchapi.StreamData{UserLogin: "Sodapoppin", UserName: "sodapoppin", Title: "wow"}
+
+	// channels live on startup are not notified about
+	w.Update(goLiveStreamsMessage{streams: []twitchapi.StreamData{lirik}})
+	require.Empty(t, w.toasts)
+
+	// failed refreshes keep the last known live channels
+	w.Update(goLiveStreamsMessage{err: twitchapi.APIError{Status: 500}})
+	require.Equal(t, map[string]bool{"lirik": true}, w.live)
+
+	w.Update(goLiveStreamsMessage{streams: []twitchapi.StreamData{lirik, xqc}})
+	require.Len(t, w.toasts, 1)
+	require.Equal(t, goLiveToast{id: 1, login: "xqc", displayName: "xQc", title: "react andy"}, w.toasts[0])
+
+	view := ansi.Strip(w.View(120))
+	require.Contains(t, view, "● xQc is live")
+	require.Contains(t, view, "react andy")
+	require.Contains(t, view, "ctrl+alt+o open · ctrl+alt+w watch")
+
+	// only channels on the allowlist are notified about
+	deps.UserConfig.Settings.GoLive.Channels = []string{"SODAPOPPIN"}
+	w.Update(goLiveStreamsMessage{streams: []twitchapi.StreamData{soda}})
+	w.Update(goLiveStreamsMessage{streams: []twitchapi.StreamData{soda, lirik, xqc}})
+	require.Len(t, w.toasts, 2)
+	require.Equal(t, "sodapoppin", w.toasts[0].login)
+
+	w.Update(goLiveToastExpiredMessage{id: 1})
+	toast, ok := w.take()
+	require.True(t, ok)
+	require.Equal(t, "sodapoppin", toast.login)
+
+	_, ok = w.take()
+	require.False(t, ok)
+	require.Empty(t, w.View(120))
+}
+
+func Test_goLiveWatcher_show(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.GoLive.Desktop = true
+	deps.UserConfig.Settings.GoLive.ToastDuration = time.Millisecond
+
+	var notified []string
+	w := newGoLiveWatcher(deps)
+	w.notify = func(title, body string) error {
+		notified = append(notified, title+": "+body)
+		return nil
+	}
+
+	// the toast expires and the system notification is shown by the returned commands
+	var msgs []tea.Msg
+	for _, cmd := range w.show(twitchapi.StreamData{UserLogin: "xqc", UserName: "xQc", Title: "react andy"})().(tea.BatchMsg) {
+		msgs = append(msgs, cmd())
+	}
+
+	require.Contains(t, msgs, goLiveToastExpiredMessage{id: 1})
+	require.Equal(t, []string{"xQc is live: react andy"}, notified)
+
+	for range maxGoLiveToasts + 1 {
+		w.show(twitchapi.StreamData{UserLogin: "xqc", UserName: "xQc", Title: "react andy"})
+	}
+
+	require.Contains(t, ansi.Strip(w.View(120)), "+2 more")
+	require.Equal(t, 5, w.toasts[0].id, "newest first")
+}
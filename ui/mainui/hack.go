@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"iter"
-	"math/rand/v2"
 	"net/url"
 	"regexp"
 	"slices"
@@ -177,10 +176,40 @@ func messageMatchesBlocked(msg twitchirc.IRCer, settings save.BlockSettings) boo
 	return false
 }
 
-func randomHexColor() string {
-	red := rand.Int32N(256)
-	green := rand.Int32N(256)
-	blue := rand.Int32N(256)
+// splitMessage splits a message into parts of at most limit runes.
+// Messages are split at spaces, words longer than limit are split inside the word.
+func splitMessage(message string, limit int) []string {
+	var (
+		parts   []string
+		current []rune
+	)
+
+	for _, word := range strings.Fields(message) {
+		wordRunes := []rune(word)
+
+		// word does not fit into the current part, start a new one
+		if len(current) > 0 && len(current)+1+len(wordRunes) > limit {
+			parts = append(parts, string(current))
+			current = nil
+		}
+
+		if len(current) > 0 {
+			current = append(current, ' ')
+		}
+
+		for len(wordRunes) > limit-len(current) {
+			n := limit - len(current)
+			parts = append(parts, string(append(current, wordRunes[:n]...)))
+			current = nil
+			wordRunes = wordRunes[n:]
+		}
+
+		current = append(current, wordRunes...)
+	}
+
+	if len(current) > 0 {
+		parts = append(parts, string(current))
+	}
 
-	return fmt.Sprintf("#%02x%02x%02x", red, green, blue)
+	return parts
 }
@@ -68,3 +68,58 @@ func Test_filter(t *testing.T) {
 
 	require.Len(t, filtered, 1)
 }
+
+func Test_splitMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		limit   int
+		want    []string
+	}{
+		{
+			name:    "fits",
+			message: "hello chat",
+			limit:   20,
+			want:    []string{"hello chat"},
+		},
+		{
+			name:    "split at spaces",
+			message: "one two three four",
+			limit:   9,
+			want:    []string{"one two", "three", "four"},
+		},
+		{
+			name:    "exact limit",
+			message: "abc def",
+			limit:   7,
+			want:    []string{"abc def"},
+		},
+		{
+			name:    "long word is split",
+			message: "hi abcdefghij",
+			limit:   4,
+			want:    []string{"hi", "abcd", "efgh", "ij"},
+		},
+		{
+			name:    "multi byte runes",
+			message: "äöü äöü",
+			limit:   3,
+			want:    []string{"äöü", "äöü"},
+		},
+		{
+			name:    "collapses whitespace",
+			message: "  a   b  ",
+			limit:   10,
+			want:    []string{"a b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, splitMessage(tt.message, tt.limit))
+		})
+	}
+}
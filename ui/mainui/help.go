@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/save"
 )
 
 type helpSection struct {
@@ -16,63 +17,23 @@ type helpSection struct {
 
 type help struct {
 	keySections []helpSection
+	profile     string
 	port        viewport.Model
 }
 
 func newHelp(height, width int, deps *DependencyContainer) *help {
-	sections := []helpSection{
-		{
-			"General",
-			[]key.Binding{
-				deps.Keymap.Up,
-				deps.Keymap.Down,
-				deps.Keymap.Escape,
-				deps.Keymap.Confirm,
-				deps.Keymap.Help,
-			},
-		},
-		{
-			"App Binds",
-			[]key.Binding{
-				deps.Keymap.Quit,
-				deps.Keymap.Create,
-				deps.Keymap.Remove,
-				deps.Keymap.CloseTab,
-				deps.Keymap.DumpScreen,
-			},
-		},
-		{
-			"Tab Binds",
-			[]key.Binding{
-				deps.Keymap.Next,
-				deps.Keymap.Previous,
-			},
-		},
-		{
-			"Chat Binds",
-			[]key.Binding{
-				deps.Keymap.InsertMode,
-				deps.Keymap.InspectMode,
-				deps.Keymap.ChatPopUp,
-				deps.Keymap.ChannelPopUp,
-				deps.Keymap.GoToTop,
-				deps.Keymap.GoToBottom,
-				deps.Keymap.DumpChat,
-				deps.Keymap.QuickTimeout,
-				deps.Keymap.CopyMessage,
-				deps.Keymap.SearchMode,
-				deps.Keymap.QuickSent,
-			},
-		},
-		{
-			"Account Binds",
-			[]key.Binding{
-				deps.Keymap.MarkLeader,
-			},
-		},
+	// sections are generated from the active keymap, so every binding is listed with its current keys
+	var sections []helpSection
+	for _, s := range deps.Keymap.Sections() {
+		sections = append(sections, helpSection{name: s.Name, binds: s.Bindings})
 	}
 
-	help := &help{port: viewport.New(width, height), keySections: sections}
+	profile := deps.UserConfig.Settings.KeymapProfile
+	if profile == "" {
+		profile = save.KeyMapProfileDefault
+	}
+
+	help := &help{port: viewport.New(width, height), keySections: sections, profile: profile}
 	help.port.SetContent(help.render())
 
 	return help
@@ -103,7 +64,7 @@ func (h *help) render() string {
 
 	head := lipgloss.NewStyle().
 		Width(h.port.Width).
-		AlignHorizontal(lipgloss.Center).Bold(true).Render("\n\nKeybind Help")
+		AlignHorizontal(lipgloss.Center).Bold(true).Render("\n\nKeybind Help (" + h.profile + " profile)")
 
 	centered := lipgloss.NewStyle().Width(h.port.Width).AlignHorizontal(lipgloss.Center).Render
 	left := lipgloss.NewStyle().Width(h.port.Width / 2).AlignHorizontal(lipgloss.Right).Render
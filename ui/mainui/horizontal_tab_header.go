@@ -37,6 +37,24 @@ func (h *horizontalTabHeader) Update(msg tea.Msg) (header, tea.Cmd) {
 		}
 	}
 
+	if req, ok := msg.(tabUnreadMessage); ok {
+		for i, e := range h.entries {
+			if e.id == req.tabID && !e.selected {
+				h.entries[i].unread = req.unread
+				break
+			}
+		}
+	}
+
+	if req, ok := msg.(tabDraftMessage); ok {
+		for i, e := range h.entries {
+			if e.id == req.tabID {
+				h.entries[i].hasDraft = req.hasDraft
+				break
+			}
+		}
+	}
+
 	return h, nil
 }
 
@@ -45,7 +63,7 @@ func (h *horizontalTabHeader) View() string {
 		return ""
 	}
 
-	borderColor := lipgloss.Color(h.deps.UserConfig.Theme.InputPromptColor)
+	borderColor := lipgloss.Color(h.deps.UserConfig.Theme.BorderColor)
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor)
 
 	// Calculate pages with variable width
@@ -228,6 +246,38 @@ func (h *horizontalTabHeader) renderTabContent(pages [][]int, currentPage, total
 	return b.String()
 }
 
+// TabAt returns the tab on the current page at the cell, the cells of the bullet in front of a tab belong to the tab
+func (h *horizontalTabHeader) TabAt(x, y int) (string, bool) {
+	// the tabs are in the row between the borders
+	if y != 1 {
+		return "", false
+	}
+
+	pages := h.calculatePages()
+	currentPage, _ := h.findCurrentPage(pages)
+	if currentPage >= len(pages) {
+		return "", false
+	}
+
+	// border │ and the left arrow or its padding
+	column := 3
+
+	for i, entryIdx := range pages[currentPage] {
+		if i > 0 {
+			column += 3 // separator " │ "
+		}
+
+		width := 2 + lipgloss.Width(h.entries[entryIdx].render())
+		if x >= column && x < column+width {
+			return h.entries[entryIdx].id, true
+		}
+
+		column += width
+	}
+
+	return "", false
+}
+
 // hasNotificationInRange checks if any entry in [start, end) has a notification
 func (h *horizontalTabHeader) hasNotificationInRange(start, end int) bool {
 	for i := start; i < end && i < len(h.entries); i++ {
@@ -255,6 +305,7 @@ func (h *horizontalTabHeader) SelectTab(id string) {
 		h.entries[i].selected = h.entries[i].id == id
 		if h.entries[i].selected {
 			h.entries[i].hasNotification = false
+			h.entries[i].unread = 0
 		}
 	}
 }
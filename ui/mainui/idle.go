@@ -0,0 +1,80 @@
+package mainui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+)
+
+// idleCheckInterval is how often the time since the last key press is checked
+const idleCheckInterval = time.Second * 15
+
+type idleCheckTickMessage struct{}
+
+// idleChangedMessage is sent to components with background refreshes when the user became idle or active again
+type idleChangedMessage struct {
+	idle bool
+}
+
+// idleTracker detects when there was no key press for the idle timeout
+type idleTracker struct {
+	lastInput time.Time
+	idle      bool
+}
+
+// input records a key press, reports true if the user was idle before
+func (i *idleTracker) input(now time.Time) bool {
+	wasIdle := i.idle
+	i.lastInput = now
+	i.idle = false
+
+	return wasIdle
+}
+
+// check reports true if the user just became idle, a timeout of 0 disables idle detection
+func (i *idleTracker) check(now time.Time, timeout time.Duration) bool {
+	if i.idle || timeout <= 0 || now.Sub(i.lastInput) < timeout {
+		return false
+	}
+
+	i.idle = true
+
+	return true
+}
+
+func idleCheckTickCommand() tea.Cmd {
+	return tea.Tick(idleCheckInterval, func(_ time.Time) tea.Msg {
+		return idleCheckTickMessage{}
+	})
+}
+
+// handleIdleChanged passes the idle state to the components and refreshes everything skipped while idle once the user is back
+func (r *Root) handleIdleChanged(idle bool) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	r.sidebar, cmd = r.sidebar.Update(idleChangedMessage{idle: idle})
+	cmds = append(cmds, cmd)
+
+	if idle {
+		return tea.Batch(cmds...)
+	}
+
+	if r.streamInfoSkipped {
+		r.streamInfoSkipped = false
+		cmds = append(cmds, r.refreshStreamInfos())
+	}
+
+	// relative timestamps were not updated while idle
+	if r.dependencies.UserConfig.Settings.Timestamps.Format == save.TimestampFormatRelative {
+		for i := range r.tabs {
+			r.tabs[i], cmd = r.tabs[i].Update(relativeTimestampTickMessage{})
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
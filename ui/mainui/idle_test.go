@@ -0,0 +1,40 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_idleTracker(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := idleTracker{lastInput: start}
+
+	require.False(t, tracker.check(start.Add(4*time.Minute), 5*time.Minute))
+	require.False(t, tracker.check(start.Add(time.Hour), 0), "a timeout of 0 disables idle detection")
+
+	require.True(t, tracker.check(start.Add(5*time.Minute), 5*time.Minute))
+	require.False(t, tracker.check(start.Add(6*time.Minute), 5*time.Minute), "only reported once")
+
+	require.True(t, tracker.input(start.Add(7*time.Minute)), "resumed")
+	require.False(t, tracker.input(start.Add(8*time.Minute)))
+	require.False(t, tracker.check(start.Add(12*time.Minute), 5*time.Minute))
+	require.True(t, tracker.check(start.Add(13*time.Minute), 5*time.Minute))
+}
+
+func Test_followedSidebar_idle(t *testing.T) {
+	t.Parallel()
+
+	s := &followedSidebar{visible: true}
+
+	s, _ = s.Update(idleChangedMessage{idle: true})
+	s, _ = s.Update(followedSidebarRefreshMessage{generation: s.generation})
+	require.True(t, s.skipped)
+
+	s, _ = s.Update(idleChangedMessage{idle: false})
+	require.False(t, s.idle)
+	require.False(t, s.skipped)
+}
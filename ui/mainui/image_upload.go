@@ -0,0 +1,117 @@
+package mainui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/imageupload"
+)
+
+// uploadPrompt asks what to do with an image pasted into the message input, uploading it inserts the link of the image
+type uploadPrompt struct {
+	image     imageupload.Image
+	text      string // the pasted text, inserted if the image is not uploaded
+	uploading bool
+}
+
+type imageUploadedMessage struct {
+	tabID string
+	link  string
+	err   error
+}
+
+// uploadPromptFor returns the prompt shown for the pasted text, nil if the text is no image and is inserted right away
+func (t *broadcastTab) uploadPromptFor(msg tea.KeyMsg) *uploadPrompt {
+	if t.deps.ImageUploader == nil || !msg.Paste {
+		return nil
+	}
+
+	image, ok := imageupload.Parse(string(msg.Runes))
+	if !ok {
+		return nil
+	}
+
+	return &uploadPrompt{image: image, text: string(msg.Runes)}
+}
+
+// handleUploadPromptKey uploads the image, inserts the pasted text or discards it
+func (t *broadcastTab) handleUploadPromptKey(msg tea.KeyMsg) tea.Cmd {
+	prompt := t.uploadPrompt
+	if prompt.uploading {
+		return nil
+	}
+
+	switch {
+	case msg.String() == "u":
+		prompt.uploading = true
+		return t.uploadImage(prompt)
+	case key.Matches(msg, t.keymap().Confirm):
+		t.closeUploadPrompt()
+		t.messageInput.Paste(prompt.text)
+		t.HandleResize()
+
+		return t.updateDraftIndicator()
+	case key.Matches(msg, t.keymap().Escape):
+		t.closeUploadPrompt()
+		t.HandleResize()
+	}
+
+	return nil
+}
+
+func (t *broadcastTab) closeUploadPrompt() {
+	t.uploadPrompt = nil
+	t.messageInput.Focus()
+}
+
+func (t *broadcastTab) uploadImage(prompt *uploadPrompt) tea.Cmd {
+	uploader, tabID := t.deps.ImageUploader, t.id
+
+	return func() tea.Msg {
+		data, err := prompt.image.Read()
+		if err != nil {
+			return imageUploadedMessage{tabID: tabID, err: err}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		link, err := uploader.Upload(ctx, prompt.image.Name, data)
+		return imageUploadedMessage{tabID: tabID, link: link, err: err}
+	}
+}
+
+// handleImageUploaded inserts the link of the uploaded image at the cursor
+func (t *broadcastTab) handleImageUploaded(msg imageUploadedMessage) tea.Cmd {
+	if t.uploadPrompt == nil {
+		return nil
+	}
+
+	t.closeUploadPrompt()
+
+	if msg.err != nil {
+		t.HandleResize()
+
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice("Failed to upload image: " + msg.err.Error())
+		}
+	}
+
+	t.messageInput.Paste(msg.link)
+	t.HandleResize()
+
+	return t.updateDraftIndicator()
+}
+
+// view returns the line shown in place of the suggestions while the prompt is open
+func (p *uploadPrompt) view(confirmKey, escapeKey string) string {
+	if p.uploading {
+		return fmt.Sprintf(" Uploading %s...", p.image.Name)
+	}
+
+	return fmt.Sprintf(" Pasted image %s: u upload and insert link, %s paste as text, %s discard", p.image.Name, confirmKey, escapeKey)
+}
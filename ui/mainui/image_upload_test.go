@@ -0,0 +1,116 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/ui/component"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeImageUploader struct{}
+
+func (fakeImageUploader) Upload(_ context.Context, name string, data []byte) (string, error) {
+	if string(data) == "broken" {
+		return "", errors.New("file too large")
+	}
+
+	return "https://kappa.lol/" + name, nil
+}
+
+func Test_broadcastTab_uploadPrompt(t *testing.T) {
+	t.Parallel()
+
+	newTab := func() *broadcastTab {
+		input := component.NewSuggestionTextInput(nil, nil)
+		input.Focus()
+		input.SetValue("look ")
+
+		deps := newTestDeps(t)
+		deps.ImageUploader = fakeImageUploader{}
+
+		return &broadcastTab{
+			id:           "tab",
+			deps:         deps,
+			messageInput: input,
+		}
+	}
+
+	paste := func(text string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true}
+	}
+
+	dir := t.TempDir()
+	image := filepath.Join(dir, "cat.png")
+	require.NoError(t, os.WriteFile(image, []byte("image"), 0o600))
+
+	t.Run("only pasted images are offered", func(t *testing.T) {
+		t.Parallel()
+
+		tab := newTab()
+		require.Nil(t, tab.uploadPromptFor(paste("hello chat")))
+		require.Nil(t, tab.uploadPromptFor(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(image)}), "typed text is no paste")
+
+		prompt := tab.uploadPromptFor(paste(image))
+		require.Equal(t, image, prompt.image.Path)
+		require.Equal(t, " Pasted image cat.png: u upload and insert link, enter paste as text, esc discard", prompt.view("enter", "esc"))
+
+		tab.deps.ImageUploader = nil
+		require.Nil(t, tab.uploadPromptFor(paste(image)), "uploads are disabled")
+	})
+
+	t.Run("upload inserts the link", func(t *testing.T) {
+		t.Parallel()
+
+		tab := newTab()
+		tab.uploadPrompt = tab.uploadPromptFor(paste(image))
+		tab.messageInput.Blur()
+
+		cmd := tab.handleUploadPromptKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+		require.True(t, tab.uploadPrompt.uploading)
+		require.Nil(t, tab.handleUploadPromptKey(tea.KeyMsg{Type: tea.KeyEsc}), "keys are ignored while uploading")
+
+		tab.handleImageUploaded(cmd().(imageUploadedMessage))
+		require.Nil(t, tab.uploadPrompt)
+		require.True(t, tab.messageInput.InputModel.Focused())
+		require.Equal(t, "look https://kappa.lol/cat.png", tab.messageInput.Value())
+	})
+
+	t.Run("failed upload keeps the input", func(t *testing.T) {
+		t.Parallel()
+
+		broken := filepath.Join(t.TempDir(), "broken.png")
+		require.NoError(t, os.WriteFile(broken, []byte("broken"), 0o600))
+
+		tab := newTab()
+		tab.uploadPrompt = tab.uploadPromptFor(paste(broken))
+
+		cmd := tab.handleUploadPromptKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+		notice := tab.handleImageUploaded(cmd().(imageUploadedMessage))
+
+		require.Nil(t, tab.uploadPrompt)
+		require.Equal(t, "look", tab.messageInput.Value())
+		require.Contains(t, notice().(requestLocalMessageHandleMessage).message.(*twitchirc.Notice).Message, "file too large")
+	})
+
+	t.Run("paste as text or discard", func(t *testing.T) {
+		t.Parallel()
+
+		tab := newTab()
+		tab.uploadPrompt = tab.uploadPromptFor(paste(image))
+		tab.handleUploadPromptKey(tea.KeyMsg{Type: tea.KeyEnter})
+		require.Nil(t, tab.uploadPrompt)
+		require.Equal(t, "look "+image, tab.messageInput.Value())
+
+		tab = newTab()
+		tab.uploadPrompt = tab.uploadPromptFor(paste(image))
+		tab.handleUploadPromptKey(tea.KeyMsg{Type: tea.KeyEsc})
+		require.Nil(t, tab.uploadPrompt)
+		require.Equal(t, "look", tab.messageInput.Value())
+	})
+}
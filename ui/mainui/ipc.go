@@ -0,0 +1,286 @@
+package mainui
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/ipc"
+	"github.com/julez-dev/chatuino/logbuffer"
+	"github.com/julez-dev/chatuino/save"
+)
+
+// ipcRequestMessage is a request received on the control socket, it is answered through reply
+type ipcRequestMessage struct {
+	request ipc.Request
+	reply   chan<- ipc.Response
+}
+
+// IPCHandler returns the handler of the control socket. Requests are passed to the program with send and answered in the update loop.
+// Logs are answered directly from the log buffer, so they can be read even if the update loop hangs.
+func IPCHandler(send func(tea.Msg), logs *logbuffer.Ring) ipc.Handler {
+	return func(req ipc.Request) ipc.Response {
+		if req.Command == ipc.CommandLogs {
+			return ipcLogs(logs)
+		}
+
+		reply := make(chan ipc.Response, 1)
+
+		// send blocks until the program is running
+		go send(ipcRequestMessage{request: req, reply: reply})
+
+		select {
+		case resp := <-reply:
+			return resp
+		case <-time.After(ipc.RequestTimeout):
+			return ipc.Errorf("timed out waiting for Chatuino to answer")
+		}
+	}
+}
+
+func ipcLogs(logs *logbuffer.Ring) ipc.Response {
+	if logs == nil {
+		return ipc.Errorf("the log buffer is not available")
+	}
+
+	entries := logs.Entries()
+
+	resp := ipc.Response{OK: true, Logs: make([]json.RawMessage, 0, len(entries))}
+	for _, e := range entries {
+		if json.Valid([]byte(e.Raw)) {
+			resp.Logs = append(resp.Logs, json.RawMessage(e.Raw))
+			continue
+		}
+
+		// keep events written by other writers than zerolog
+		raw, _ := json.Marshal(map[string]string{"message": e.Raw})
+		resp.Logs = append(resp.Logs, raw)
+	}
+
+	return resp
+}
+
+var ipcTabKinds = map[tabKind]string{
+	broadcastTabKind:        ipc.TabKindChannel,
+	mentionTabKind:          ipc.TabKindMention,
+	liveNotificationTabKind: ipc.TabKindLiveNotification,
+	youTubeTabKind:          ipc.TabKindYouTube,
+	kickTabKind:             ipc.TabKindKick,
+	ircInspectorTabKind:     ipc.TabKindIRCInspector,
+	replayTabKind:           ipc.TabKindReplay,
+}
+
+// handleIPCRequest runs a command of the control socket
+func (r *Root) handleIPCRequest(req ipc.Request) (ipc.Response, tea.Cmd) {
+	if !r.hasLoadedSession {
+		return ipc.Errorf("Chatuino is still starting"), nil
+	}
+
+	switch req.Command {
+	case ipc.CommandState:
+		tabs := make([]ipc.Tab, 0, len(r.tabs))
+		for i := range r.tabs {
+			tabs = append(tabs, r.ipcTab(i))
+		}
+
+		return ipc.Response{OK: true, Tabs: tabs}, nil
+	case ipc.CommandOpenTab:
+		return r.handleIPCOpenTab(req)
+	case ipc.CommandCloseTab:
+		index, resp := r.ipcSelectTab(req)
+		if index == -1 {
+			return resp, nil
+		}
+
+		closed := r.ipcTab(index)
+		cmd := r.closeTabAt(index)
+
+		return ipc.Response{OK: true, Tab: &closed}, cmd
+	case ipc.CommandSwitchTab:
+		index, resp := r.ipcSelectTab(req)
+		if index == -1 {
+			return resp, nil
+		}
+
+		r.screenType = mainScreen
+		r.goToTab(index)
+		tab := r.ipcTab(index)
+
+		return ipc.Response{OK: true, Tab: &tab}, nil
+	case ipc.CommandSendMessage:
+		return r.handleIPCSendMessage(req)
+	}
+
+	return ipc.Errorf("unknown command %q", req.Command), nil
+}
+
+func (r *Root) handleIPCOpenTab(req ipc.Request) (ipc.Response, tea.Cmd) {
+	kind, known := broadcastTabKind, req.Kind == ""
+	for k, name := range ipcTabKinds {
+		if name == req.Kind {
+			kind, known = k, true
+		}
+	}
+
+	if !known {
+		return ipc.Errorf("unknown tab kind %q, expected channel, mention, live_notification, youtube, kick or irc_inspector", req.Kind), nil
+	}
+
+	channel := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Channel), "#"))
+	if kind == broadcastTabKind && channel == "" {
+		return ipc.Errorf("channel is required to open a channel tab"), nil
+	}
+
+	// video IDs of other platforms are case sensitive
+	if isProviderTabKind(kind) {
+		channel = strings.TrimSpace(req.Channel)
+		if channel == "" {
+			return ipc.Errorf("channel is required to open a %s tab", req.Kind), nil
+		}
+
+		if r.providerFor(kind) == nil {
+			return ipc.Errorf("%s tabs are not configured", req.Kind), nil
+		}
+	}
+
+	if kind == replayTabKind {
+		return ipc.Errorf("replay tabs are only opened by the replay and vod commands"), nil
+	}
+
+	if kind == ircInspectorTabKind && r.dependencies.Traffic == nil {
+		return ipc.Errorf("the IRC traffic is not recorded"), nil
+	}
+
+	account, ok := r.ipcAccount(req.Account)
+	if !ok {
+		return ipc.Errorf("account %q not found", req.Account), nil
+	}
+
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	cmd := r.openTab(account, channel, kind)
+	tab := r.ipcTab(r.tabCursor)
+
+	return ipc.Response{OK: true, Tab: &tab}, cmd
+}
+
+func (r *Root) handleIPCSendMessage(req ipc.Request) (ipc.Response, tea.Cmd) {
+	index, resp := r.ipcSelectTab(req)
+	if index == -1 {
+		return resp, nil
+	}
+
+	bt, ok := r.tabs[index].(*broadcastTab)
+	if !ok {
+		return ipc.Errorf("messages can only be sent to channel tabs"), nil
+	}
+
+	text := strings.TrimSpace(req.Text)
+
+	switch {
+	case text == "":
+		return ipc.Errorf("text is required"), nil
+	case strings.HasPrefix(text, "/"):
+		return ipc.Errorf("commands can't be sent through the control socket"), nil
+	case bt.account.IsAnonymous:
+		return ipc.Errorf("the anonymous account can't send messages"), nil
+	case !bt.IsDataLoaded():
+		return ipc.Errorf("the tab is still loading"), nil
+	}
+
+	tab := r.ipcTab(index)
+
+	return ipc.Response{OK: true, Tab: &tab}, bt.sendMessage(text)
+}
+
+// ipcSelectTab returns the index of the tab selected by its ID, position or channel, without any the focused tab is used.
+// If no tab matches, the index is -1 and the response contains the error.
+func (r *Root) ipcSelectTab(req ipc.Request) (int, ipc.Response) {
+	var index int
+
+	switch {
+	case req.Tab != "":
+		index = slices.IndexFunc(r.tabs, func(t tab) bool { return t.ID() == req.Tab })
+	case req.Index != 0:
+		index = req.Index - 1
+		if index < 0 || index >= len(r.tabs) {
+			index = -1
+		}
+	case req.Channel != "":
+		channel := strings.TrimPrefix(strings.TrimSpace(req.Channel), "#")
+		index = slices.IndexFunc(r.tabs, func(t tab) bool {
+			bt, ok := t.(*broadcastTab)
+			if !ok || !strings.EqualFold(bt.channelName(), channel) {
+				return false
+			}
+
+			return req.Account == "" || strings.EqualFold(bt.account.DisplayName, req.Account)
+		})
+	default:
+		index = r.tabCursor
+		if index >= len(r.tabs) {
+			index = -1
+		}
+	}
+
+	if index == -1 {
+		return -1, ipc.Errorf("tab not found")
+	}
+
+	return index, ipc.Response{}
+}
+
+// ipcAccount returns the account with the display name, an empty name selects the main account or the anonymous account without any
+func (r *Root) ipcAccount(name string) (save.Account, bool) {
+	accounts := r.dependencies.Accounts
+
+	if name != "" {
+		i := slices.IndexFunc(accounts, func(a save.Account) bool { return strings.EqualFold(a.DisplayName, name) })
+		if i == -1 {
+			return save.Account{}, false
+		}
+
+		return accounts[i], true
+	}
+
+	if i := slices.IndexFunc(accounts, func(a save.Account) bool { return a.IsMain }); i != -1 {
+		return accounts[i], true
+	}
+
+	if i := slices.IndexFunc(accounts, func(a save.Account) bool { return a.IsAnonymous }); i != -1 {
+		return accounts[i], true
+	}
+
+	return save.Account{}, false
+}
+
+// channelName returns the channel login, or the name the tab was opened with while it is loading
+func (t *broadcastTab) channelName() string {
+	return cmp.Or(t.channelLogin, t.channel)
+}
+
+func (r *Root) ipcTab(index int) ipc.Tab {
+	t := r.tabs[index]
+
+	info := ipc.Tab{
+		ID:      t.ID(),
+		Index:   index + 1,
+		Kind:    ipcTabKinds[t.Kind()],
+		Focused: index == r.tabCursor,
+	}
+
+	switch t := t.(type) {
+	case *broadcastTab:
+		info.Channel = t.channelName()
+		info.Account = t.account.DisplayName
+	case *providerTab:
+		info.Channel = t.channel
+	}
+
+	return info
+}
@@ -0,0 +1,342 @@
+package mainui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const ircInspectorRefreshInterval = time.Second
+
+// ircInspectorTickMessage refreshes the lines of the inspector, ticks of closed tabs are ignored
+type ircInspectorTickMessage struct {
+	tabID string
+}
+
+// ircInspectorTab shows the raw lines of all IRC connections, received and sent, for debugging parsing issues.
+// New lines are shown while it is not paused, tags can be shown one per line.
+type ircInspectorTab struct {
+	id   string
+	deps *DependencyContainer
+
+	focused       bool
+	width, height int
+
+	filter     textinput.Model
+	filtering  bool                    // the filter input has focus
+	paused     bool                    // lines are not refreshed, to read them while chat is busy
+	expandTags bool                    // show the tags of a line one per line
+	lines      []twitchirc.TrafficLine // matching lines, oldest first
+	written    uint64                  // written count of the traffic at the last refresh
+	fromLast   int                     // number of rendered lines scrolled up from the newest line
+}
+
+func newIRCInspectorTab(id string, width, height int, deps *DependencyContainer) *ircInspectorTab {
+	filter := textinput.New()
+	filter.Prompt = "Filter: "
+	filter.Placeholder = "text in line or account ID"
+	filter.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.InputPromptColor))
+
+	i := &ircInspectorTab{
+		id:     id,
+		deps:   deps,
+		width:  width,
+		height: height,
+		filter: filter,
+	}
+	i.refresh()
+
+	return i
+}
+
+func (i *ircInspectorTab) Init() tea.Cmd {
+	return i.tick()
+}
+
+func (i *ircInspectorTab) InitWithUserData(twitchapi.UserData) tea.Cmd {
+	return i.Init()
+}
+
+func (i *ircInspectorTab) tick() tea.Cmd {
+	tabID := i.id
+	return tea.Tick(ircInspectorRefreshInterval, func(time.Time) tea.Msg {
+		return ircInspectorTickMessage{tabID: tabID}
+	})
+}
+
+func (i *ircInspectorTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ircInspectorTickMessage:
+		if msg.tabID != i.id {
+			return i, nil
+		}
+
+		if !i.paused && i.deps.Traffic != nil && i.deps.Traffic.Written() != i.written {
+			i.refresh()
+		}
+
+		return i, i.tick()
+	case tea.KeyMsg:
+		if !i.focused {
+			return i, nil
+		}
+
+		if i.filtering {
+			if key.Matches(msg, i.deps.Keymap.Escape) || key.Matches(msg, i.deps.Keymap.Confirm) {
+				i.filtering = false
+				i.filter.Blur()
+				return i, nil
+			}
+
+			var cmd tea.Cmd
+			filter := i.filter.Value()
+			i.filter, cmd = i.filter.Update(msg)
+
+			if i.filter.Value() != filter {
+				i.refresh()
+			}
+
+			return i, cmd
+		}
+
+		switch {
+		case key.Matches(msg, i.deps.Keymap.SearchMode):
+			i.filtering = true
+			return i, i.filter.Focus()
+		case key.Matches(msg, i.deps.Keymap.Confirm):
+			i.paused = !i.paused
+			if !i.paused {
+				i.refresh()
+			}
+		case key.Matches(msg, i.deps.Keymap.InspectMode):
+			i.expandTags = !i.expandTags
+			i.fromLast = 0
+		case key.Matches(msg, i.deps.Keymap.Up):
+			i.fromLast = min(i.fromLast+1, i.maxScroll())
+		case key.Matches(msg, i.deps.Keymap.Down):
+			i.fromLast = max(i.fromLast-1, 0)
+		case key.Matches(msg, i.deps.Keymap.GoToTop):
+			i.fromLast = i.maxScroll()
+		case key.Matches(msg, i.deps.Keymap.GoToBottom):
+			i.fromLast = 0
+		}
+	}
+
+	return i, nil
+}
+
+// refresh reads the matching lines from the traffic
+func (i *ircInspectorTab) refresh() {
+	if i.deps.Traffic == nil {
+		return
+	}
+
+	before := len(i.lines)
+
+	i.written = i.deps.Traffic.Written()
+	i.lines = filterTrafficLines(i.deps.Traffic.Lines(), i.filter.Value())
+
+	// keep the scrolled to lines in place, while new lines arrive
+	if i.fromLast > 0 && !i.expandTags {
+		i.fromLast += len(i.lines) - before
+	}
+
+	i.fromLast = min(max(i.fromLast, 0), i.maxScroll())
+}
+
+// filterTrafficLines returns the lines containing the text in the line or account ID, ignoring case
+func filterTrafficLines(lines []twitchirc.TrafficLine, text string) []twitchirc.TrafficLine {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return lines
+	}
+
+	filtered := lines[:0:0]
+	for _, l := range lines {
+		if strings.Contains(strings.ToLower(l.Line), text) || strings.Contains(l.AccountID, text) {
+			filtered = append(filtered, l)
+		}
+	}
+
+	return filtered
+}
+
+// renderLines renders the newest matching lines until at least n rendered lines exist, all lines if n is negative
+func (i *ircInspectorTab) renderLines(n int) []string {
+	theme := i.deps.UserConfig.Theme
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	inboundStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ListLabelColor))
+	outboundStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatNoticeAlertColor))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.InputPromptColor))
+
+	var rendered []string
+
+	for idx := len(i.lines) - 1; idx >= 0 && (n < 0 || len(rendered) < n); idx-- {
+		l := i.lines[idx]
+
+		direction := inboundStyle.Render("←")
+		if l.Direction == twitchirc.Outbound {
+			direction = outboundStyle.Render("→")
+		}
+
+		prefix := dimmedStyle.Render(l.Time.Local().Format("15:04:05")) + " " + direction + " "
+
+		// raw lines are shown as received, so control sequences in messages and tags have to be removed
+		if !i.expandTags {
+			rendered = append(rendered, ansi.Truncate(prefix+termtext.Sanitize(l.Line), i.width, "…"))
+			continue
+		}
+
+		tags, rest := twitchirc.SplitTags(l.Line)
+
+		entry := []string{ansi.Truncate(prefix+termtext.Sanitize(rest), i.width, "…")}
+		for _, tag := range tags {
+			value := termtext.Sanitize(strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(tag.Value))
+			entry = append(entry, ansi.Truncate("           "+keyStyle.Render(termtext.Sanitize(tag.Key))+" = "+value, i.width, "…"))
+		}
+
+		// the lines are collected newest first and reversed at the end
+		for j := len(entry) - 1; j >= 0; j-- {
+			rendered = append(rendered, entry[j])
+		}
+	}
+
+	for l, r := 0, len(rendered)-1; l < r; l, r = l+1, r-1 {
+		rendered[l], rendered[r] = rendered[r], rendered[l]
+	}
+
+	return rendered
+}
+
+func (i *ircInspectorTab) listHeight() int {
+	// help and filter line
+	return max(i.height-2, 1)
+}
+
+func (i *ircInspectorTab) maxScroll() int {
+	// without tags every line is rendered as one line
+	if !i.expandTags {
+		return max(len(i.lines)-i.listHeight(), 0)
+	}
+
+	return max(len(i.renderLines(-1))-i.listHeight(), 0)
+}
+
+func (i *ircInspectorTab) View() string {
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(i.deps.UserConfig.Theme.DimmedTextColor))
+	keymap := i.deps.Keymap
+
+	state := "live"
+	if i.paused {
+		state = "paused"
+		if i.deps.Traffic != nil && i.deps.Traffic.Written() != i.written {
+			state = fmt.Sprintf("paused, %d new", i.deps.Traffic.Written()-i.written)
+		}
+	}
+
+	help := fmt.Sprintf("%s %s · %s filter · %s tags · %s/%s scroll",
+		keymap.Confirm.Help().Key, state,
+		keymap.SearchMode.Help().Key,
+		keymap.InspectMode.Help().Key,
+		keymap.Up.Help().Key, keymap.Down.Help().Key,
+	)
+
+	var lines []string
+
+	switch {
+	case i.deps.Traffic == nil:
+		lines = append(lines, dimmedStyle.Render("The IRC traffic is not recorded"))
+	case len(i.lines) == 0:
+		lines = append(lines, dimmedStyle.Render("No matching IRC lines"))
+	default:
+		rendered := i.renderLines(i.listHeight() + i.fromLast)
+
+		end := max(len(rendered)-i.fromLast, 0)
+		lines = rendered[max(end-i.listHeight(), 0):end]
+	}
+
+	// fill up the list, so the filter stays at the bottom
+	for len(lines) < i.listHeight() {
+		lines = append(lines, "")
+	}
+
+	i.filter.Width = max(i.width-len(i.filter.Prompt)-1, 1)
+
+	return strings.Join(lines, "\n") + "\n" +
+		ansi.Truncate(dimmedStyle.Render(help), i.width, "…") + "\n" +
+		i.filter.View()
+}
+
+func (i *ircInspectorTab) ViewWithoutStatusBar() string {
+	return i.View() // the inspector tab has no status bar
+}
+
+func (i *ircInspectorTab) StatusBarView() string {
+	return ""
+}
+
+func (i *ircInspectorTab) Focus() {
+	i.focused = true
+}
+
+func (i *ircInspectorTab) Blur() {
+	i.focused = false
+	i.filtering = false
+	i.filter.Blur()
+}
+
+func (i *ircInspectorTab) AccountID() string {
+	return ""
+}
+
+func (i *ircInspectorTab) Channel() string {
+	return ""
+}
+
+func (i *ircInspectorTab) State() broadcastTabState {
+	return inChatWindow
+}
+
+func (i *ircInspectorTab) IsTyping() bool {
+	return i.filtering
+}
+
+func (i *ircInspectorTab) IsDataLoaded() bool {
+	return true
+}
+
+func (i *ircInspectorTab) ID() string {
+	return i.id
+}
+
+func (i *ircInspectorTab) Focused() bool {
+	return i.focused
+}
+
+func (i *ircInspectorTab) ChannelID() string {
+	return ""
+}
+
+func (i *ircInspectorTab) HandleResize() {}
+
+func (i *ircInspectorTab) SetSize(width, height int) {
+	i.width = width
+	i.height = height
+}
+
+func (i *ircInspectorTab) SetFullWidth(_ int) {
+	// No-op for the inspector tab (no status bar)
+}
+
+func (i *ircInspectorTab) Kind() tabKind {
+	return ircInspectorTabKind
+}
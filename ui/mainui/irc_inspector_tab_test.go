@@ -0,0 +1,73 @@
+package mainui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIRCInspectorTab(t *testing.T) {
+	t.Parallel()
+
+	traffic := twitchirc.NewTraffic(10)
+	deps := newTestDeps(t)
+	deps.Traffic = traffic
+
+	traffic.Add(twitchirc.Outbound, "1", "JOIN #lirik")
+	traffic.Add(twitchirc.Inbound, "1", `@display-name=Viewer;system-msg=hello\sworld :viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #lirik :hi`)
+
+	inspector := newIRCInspectorTab("tab", 120, 20, deps)
+	inspector.Focus()
+	require.Len(t, inspector.lines, 2)
+	require.Contains(t, inspector.View(), "JOIN #lirik")
+
+	// tags are shown one per line with their unescaped value
+	_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	require.Contains(t, inspector.View(), "hello world")
+
+	// paused inspectors don't show new lines until resumed
+	_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	traffic.Add(twitchirc.Inbound, "1", "PING :tmi.twitch.tv")
+	_, _ = inspector.Update(ircInspectorTickMessage{tabID: "tab"})
+	require.Len(t, inspector.lines, 2)
+	require.Contains(t, inspector.View(), "paused, 1 new")
+
+	_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Len(t, inspector.lines, 3)
+
+	// the filter is typed after the search key
+	_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	require.True(t, inspector.IsTyping())
+
+	for _, r := range "ping" {
+		_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	require.Len(t, inspector.lines, 1)
+	require.Equal(t, "PING :tmi.twitch.tv", inspector.lines[0].Line)
+
+	_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, inspector.IsTyping())
+}
+
+func TestIRCInspectorTab_SanitizesLines(t *testing.T) {
+	t.Parallel()
+
+	traffic := twitchirc.NewTraffic(10)
+	deps := newTestDeps(t)
+	deps.Traffic = traffic
+
+	traffic.Add(twitchirc.Inbound, "1", "@display-name=Viewer\x1b[2J :viewer!viewer@viewer.tmi.twitch.tv PRIVMSG #lirik :hi\x1b]0;title\x07")
+
+	inspector := newIRCInspectorTab("tab", 200, 20, deps)
+	inspector.Focus()
+
+	require.NotContains(t, inspector.View(), "\x1b]0;")
+	require.Contains(t, inspector.View(), "PRIVMSG #lirik :hi")
+
+	_, _ = inspector.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	require.NotContains(t, inspector.View(), "\x1b[2J")
+	require.Contains(t, inspector.View(), "Viewer")
+}
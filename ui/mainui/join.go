@@ -17,6 +17,8 @@ import (
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/ui/component"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
 )
 
 type followedFetcher interface {
@@ -58,7 +60,8 @@ type setJoinAccountsMessage struct {
 }
 
 type setJoinSuggestionMessage struct {
-	suggestions []string
+	suggestions  []string
+	descriptions map[string]string // channel login:live status
 }
 
 type join struct {
@@ -108,6 +111,7 @@ func newJoin(parentWidth int, deps *DependencyContainer) *join {
 	}
 
 	input := component.NewSuggestionTextInput(emptyUserMap, nil)
+	input.KeyMap = inputKeyMap(deps.Keymap)
 	input.DisableAutoSpaceSuggestion = true
 	input.InputModel.CharLimit = 25
 	input.InputModel.Prompt = " "
@@ -122,6 +126,7 @@ func newJoin(parentWidth int, deps *DependencyContainer) *join {
 		return nil
 	}
 	input.IncludeCommandSuggestions = false
+	input.FuzzySuggestions = true
 	input.DisableHistory = true
 	input.InputModel.Cursor.BlinkSpeed = time.Millisecond * 750
 	// Set input width to reasonable size (will be centered in modal)
@@ -200,6 +205,9 @@ func (j *join) Init() tea.Cmd {
 			}
 
 			uniqueChannels := map[string]struct{}{}
+			followedIDs := map[string]struct{}{}
+
+			var streamFetcher APIClient
 			for id, fetcher := range j.followedFetchers {
 				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 				defer cancel()
@@ -214,7 +222,10 @@ func (j *join) Init() tea.Cmd {
 
 				for _, f := range followed {
 					uniqueChannels[f.BroadcasterLogin] = struct{}{}
+					followedIDs[f.BroadcasterID] = struct{}{}
 				}
+
+				streamFetcher = j.deps.APIUserClients[id]
 			}
 
 			for _, a := range accounts {
@@ -225,12 +236,81 @@ func (j *join) Init() tea.Cmd {
 				uniqueChannels[a.DisplayName] = struct{}{}
 			}
 
-			return setJoinSuggestionMessage{suggestions: slices.Collect(maps.Keys(uniqueChannels))}
+			streams := j.fetchLiveStreams(streamFetcher, slices.Collect(maps.Keys(followedIDs)))
+			descriptions := make(map[string]string, len(uniqueChannels))
+			printer := message.NewPrinter(language.English)
+
+			for login := range uniqueChannels {
+				stream, isLive := streams[strings.ToLower(login)]
+				if !isLive {
+					descriptions[login] = "offline"
+					continue
+				}
+
+				descriptions[login] = printer.Sprintf("● live (%d Viewers)", stream.ViewerCount)
+			}
+
+			// live channels with the most viewers first, then offline channels alphabetically
+			suggestions := slices.SortedFunc(maps.Keys(uniqueChannels), func(a, b string) int {
+				streamA, liveA := streams[strings.ToLower(a)]
+				streamB, liveB := streams[strings.ToLower(b)]
+
+				if liveA != liveB {
+					if liveA {
+						return -1
+					}
+
+					return 1
+				}
+
+				if streamA.ViewerCount != streamB.ViewerCount {
+					return streamB.ViewerCount - streamA.ViewerCount
+				}
+
+				return strings.Compare(a, b)
+			})
+
+			return setJoinSuggestionMessage{
+				suggestions:  suggestions,
+				descriptions: descriptions,
+			}
 		},
 		j.input.InputModel.Cursor.BlinkCmd(),
 	)
 }
 
+// fetchLiveStreams returns the current streams of all live broadcasters, keyed by login.
+// The Helix API allows up to 100 broadcasters per request, so the IDs are fetched in chunks.
+func (j *join) fetchLiveStreams(fetcher APIClient, broadcasterIDs []string) map[string]twitchapi.StreamData {
+	streams := map[string]twitchapi.StreamData{}
+
+	if fetcher == nil {
+		return streams
+	}
+
+	for chunk := range slices.Chunk(broadcasterIDs, 100) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		resp, err := fetcher.GetStreamInfo(ctx, chunk)
+		cancel()
+
+		// live status is only additional information, just skip if the call fails
+		if err != nil {
+			log.Logger.Err(err).Msg("could not fetch live status of followed channels")
+			continue
+		}
+
+		for _, stream := range resp.Data {
+			if stream.StartedAt.IsZero() {
+				continue
+			}
+
+			streams[strings.ToLower(stream.UserLogin)] = stream
+		}
+	}
+
+	return streams
+}
+
 func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 	var (
 		cmd  tea.Cmd
@@ -270,6 +350,7 @@ func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 
 	if msg, ok := msg.(setJoinSuggestionMessage); ok {
 		j.input.SetSuggestions(msg.suggestions)
+		j.input.SetSuggestionDescriptions(msg.descriptions)
 		return j, nil
 	}
 
@@ -282,11 +363,16 @@ func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 
 			if key.Matches(msg, j.deps.Keymap.Next) {
 				// don't allow next input when mention or live notification tab selected
-				if i, ok := j.tabKindList.SelectedItem().(listItem); ok && (i.title == mentionTabKind.String() || i.title == liveNotificationTabKind.String()) {
+				if hasOnlyKindInput(j.selectedKind()) {
 					// For mention/live notification tabs, Tab does nothing (only one field)
 					return j, nil
 				}
 
+				// tabs of other platforms have no identity
+				if isProviderTabKind(j.selectedKind()) {
+					return j, j.toggleChannelInput()
+				}
+
 				switch j.selectedInput {
 				case tabSelect:
 					j.selectedInput = accountSelect
@@ -302,11 +388,15 @@ func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 
 			if key.Matches(msg, j.deps.Keymap.Previous) {
 				// don't allow previous input when mention or live notification tab selected
-				if i, ok := j.tabKindList.SelectedItem().(listItem); ok && (i.title == mentionTabKind.String() || i.title == liveNotificationTabKind.String()) {
+				if hasOnlyKindInput(j.selectedKind()) {
 					// For mention/live notification tabs, Shift+Tab does nothing (only one field)
 					return j, nil
 				}
 
+				if isProviderTabKind(j.selectedKind()) {
+					return j, j.toggleChannelInput()
+				}
+
 				switch j.selectedInput {
 				case tabSelect:
 					j.selectedInput = channelInput
@@ -324,13 +414,16 @@ func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 			kind := j.tabKindList.SelectedItem().(listItem).kind
 
 			// Check if inputs are valid for confirmation
-			isValid := (j.input.Value() != "" && kind == broadcastTabKind) ||
-				kind == mentionTabKind ||
-				kind == liveNotificationTabKind
+			isValid := (j.input.Value() != "" && (kind == broadcastTabKind || isProviderTabKind(kind))) ||
+				hasOnlyKindInput(kind)
 
 			if key.Matches(msg, j.deps.Keymap.Confirm) && isValid {
 				channel := j.input.Value()
-				account := j.accounts[j.accountList.Cursor()]
+
+				var account save.Account
+				if !isProviderTabKind(kind) {
+					account = j.accounts[j.accountList.Cursor()]
+				}
 
 				return j, func() tea.Msg {
 					// Normalize channel name via Twitch API for broadcast tabs
@@ -364,6 +457,8 @@ func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 		}
 	}
 
+	j.setChannelInputKind(j.selectedKind())
+
 	switch j.selectedInput {
 	case channelInput:
 		// For channel input, always pass to input component for handling
@@ -372,11 +467,8 @@ func (j *join) Update(msg tea.Msg) (*join, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 		// on update change the width to the width of content
-		iw := lipgloss.Width(j.input.Value())
-
-		if iw < 7 {
-			iw = 7
-		}
+		// the input is at least as wide as the placeholder
+		iw := max(lipgloss.Width(j.input.Value()), lipgloss.Width(j.input.InputModel.Placeholder))
 
 		j.input.SetWidth(iw)
 		j.input.InputModel.Width = iw
@@ -442,8 +534,20 @@ func (j *join) View() string {
 	_, _ = b.WriteString(styleCenter.Render(headlineStyle.Render("Create new Tab")) + "\n")
 
 	// If mention tab is selected, only display kind select input, because other values are not needed
-	if i, ok := j.tabKindList.SelectedItem().(listItem); ok && (i.title == mentionTabKind.String() || i.title == liveNotificationTabKind.String()) {
+	if hasOnlyKindInput(j.selectedKind()) {
 		_, _ = b.WriteString(styleCenter.Render(labelTab + "\n" + j.tabKindList.View() + "\n"))
+	} else if isProviderTabKind(j.selectedKind()) {
+		// tabs of other platforms only need the chat
+		_, _ = b.WriteString(styleCenter.Render(labelTab))
+		_, _ = b.WriteString(styleCenter.Render(j.tabKindList.View()))
+		_, _ = b.WriteString("\n")
+
+		_, _ = b.WriteString(styleCenter.Render(labelChannel))
+		_, _ = b.WriteString("\n")
+
+		for _, line := range strings.Split(j.input.View(), "\n") {
+			_, _ = b.WriteString(styleCenter.Render(line) + "\n")
+		}
 	} else {
 		_, _ = labelIdentity, labelChannel
 
@@ -525,3 +629,44 @@ func (c *join) setTabOptions(kinds ...tabKind) {
 		items,
 	)
 }
+
+// hasOnlyKindInput reports whether tabs of the kind are created without channel and account, like the mention tab
+func hasOnlyKindInput(kind tabKind) bool {
+	return kind == mentionTabKind || kind == liveNotificationTabKind || kind == ircInspectorTabKind
+}
+
+func (c *join) selectedKind() tabKind {
+	if i, ok := c.tabKindList.SelectedItem().(listItem); ok {
+		return i.kind
+	}
+
+	return broadcastTabKind
+}
+
+// toggleChannelInput switches between the tab kind and channel input, used by tabs without an identity
+func (c *join) toggleChannelInput() tea.Cmd {
+	if c.selectedInput == channelInput {
+		c.selectedInput = tabSelect
+		return nil
+	}
+
+	c.selectedInput = channelInput
+	return c.input.InputModel.Cursor.BlinkCmd()
+}
+
+// setChannelInputKind adjusts the channel input to the selected tab kind, chats of other platforms are joined by links or IDs
+func (c *join) setChannelInputKind(kind tabKind) {
+	switch kind {
+	case youTubeTabKind:
+		c.input.InputModel.CharLimit = 100
+		c.input.InputModel.Placeholder = "Link, @handle or ID"
+		return
+	case kickTabKind:
+		c.input.InputModel.CharLimit = 100
+		c.input.InputModel.Placeholder = "Link or channel"
+		return
+	}
+
+	c.input.InputModel.CharLimit = 25
+	c.input.InputModel.Placeholder = "Channel"
+}
@@ -0,0 +1,157 @@
+package mainui
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+)
+
+// keySequenceTimeout is how long to wait for the next key of a key sequence
+const keySequenceTimeout = time.Second
+
+// keySequenceTimeoutMessage is sent when no further key of a started sequence was pressed in time
+type keySequenceTimeoutMessage struct {
+	id int
+}
+
+// pendingKeysMessage updates the pending key indicator in the status bar, empty keys clear the indicator
+type pendingKeysMessage struct {
+	keys string
+}
+
+// replayedKeyMessage wraps key presses, which turned out not to be part of a sequence.
+// They are handled like normal key presses, without starting a new sequence.
+type replayedKeyMessage struct {
+	tea.KeyMsg
+}
+
+// keySequencer collects key presses for bindings made of multiple keys, like "g t" or "<leader>mb"
+type keySequencer struct {
+	sequences map[string][]string // binding key to keys pressed one after another
+	pending   []tea.KeyMsg
+	id        int // increased for every pending key, so outdated timeouts are ignored
+}
+
+// newKeySequencer collects the sequences of the keymap and of the channel keymaps, including their macros.
+// A sequence of another channel completes like any other, its key message just matches no binding.
+func newKeySequencer(keymap save.KeyMap, channels save.ChannelKeyMaps) *keySequencer {
+	sequences := keymap.KeySequences()
+
+	for _, channel := range channels {
+		maps.Copy(sequences, channel.KeyMap.KeySequences())
+
+		var leader string
+		if keys := channel.KeyMap.Leader.Keys(); len(keys) > 0 {
+			leader = keys[0]
+		}
+
+		for k := range channel.Macros {
+			if seq := save.ParseKeySequence(k, leader); len(seq) > 1 || len(seq) == 1 && seq[0] != k {
+				sequences[k] = seq
+			}
+		}
+	}
+
+	return &keySequencer{
+		sequences: sequences,
+	}
+}
+
+func (k *keySequencer) enabled() bool {
+	return len(k.sequences) > 0
+}
+
+// handle processes a key press. If pass is false, the key press is part of a sequence and must not be handled any further.
+// Once a sequence is complete, a key message matching the binding key (e.g. "g t") is returned, so key.Matches works as with single keys.
+func (k *keySequencer) handle(msg tea.KeyMsg) (result tea.KeyMsg, pass bool, cmd tea.Cmd) {
+	pressed := make([]string, 0, len(k.pending)+1)
+	for _, p := range k.pending {
+		pressed = append(pressed, p.String())
+	}
+	pressed = append(pressed, msg.String())
+
+	var isPrefix bool
+	for binding, seq := range k.sequences {
+		if slices.Equal(seq, pressed) {
+			hadPending := len(k.pending) > 0
+			k.reset()
+
+			if hadPending {
+				cmd = clearPendingKeys
+			}
+
+			return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(binding)}, true, cmd
+		}
+
+		if len(seq) > len(pressed) && slices.Equal(seq[:len(pressed)], pressed) {
+			isPrefix = true
+		}
+	}
+
+	if isPrefix {
+		k.pending = append(k.pending, msg)
+		k.id++
+		id := k.id
+
+		names := make([]string, 0, len(pressed))
+		for _, p := range pressed {
+			if p == " " {
+				p = "space"
+			}
+			names = append(names, p)
+		}
+
+		keys := strings.Join(names, " ")
+		return tea.KeyMsg{}, false, tea.Batch(
+			func() tea.Msg {
+				return pendingKeysMessage{keys: keys}
+			},
+			tea.Tick(keySequenceTimeout, func(time.Time) tea.Msg {
+				return keySequenceTimeoutMessage{id: id}
+			}),
+		)
+	}
+
+	if len(k.pending) == 0 {
+		return msg, true, nil
+	}
+
+	// the started sequence was not continued, handle all collected keys as single key presses
+	return tea.KeyMsg{}, false, k.flush(msg)
+}
+
+// timeout replays the pending keys, if no other key was pressed since the timeout was started
+func (k *keySequencer) timeout(id int) tea.Cmd {
+	if id != k.id || len(k.pending) == 0 {
+		return nil
+	}
+
+	return k.flush()
+}
+
+func (k *keySequencer) flush(extra ...tea.KeyMsg) tea.Cmd {
+	cmds := []tea.Cmd{clearPendingKeys}
+
+	for _, msg := range append(k.pending, extra...) {
+		cmds = append(cmds, func() tea.Msg {
+			return replayedKeyMessage{KeyMsg: msg}
+		})
+	}
+
+	k.reset()
+
+	return tea.Sequence(cmds...)
+}
+
+func (k *keySequencer) reset() {
+	k.pending = nil
+	k.id++
+}
+
+func clearPendingKeys() tea.Msg {
+	return pendingKeysMessage{}
+}
@@ -0,0 +1,121 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/stretchr/testify/require"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestKeySequencer_Handle(t *testing.T) {
+	t.Parallel()
+
+	newSequencer := func() *keySequencer {
+		return &keySequencer{
+			sequences: map[string][]string{
+				"g t":        {"g", "t"},
+				"<leader>mb": {" ", "m", "b"},
+			},
+		}
+	}
+
+	t.Run("complete-sequence", func(t *testing.T) {
+		t.Parallel()
+
+		k := newSequencer()
+
+		_, pass, cmd := k.handle(runeKey('g'))
+		require.False(t, pass)
+		require.NotNil(t, cmd)
+
+		result, pass, _ := k.handle(runeKey('t'))
+		require.True(t, pass)
+		require.Equal(t, "g t", result.String())
+		require.Empty(t, k.pending)
+	})
+
+	t.Run("leader-sequence", func(t *testing.T) {
+		t.Parallel()
+
+		k := newSequencer()
+
+		for _, r := range " m" {
+			_, pass, _ := k.handle(runeKey(r))
+			require.False(t, pass)
+		}
+
+		result, pass, _ := k.handle(runeKey('b'))
+		require.True(t, pass)
+		require.Equal(t, "<leader>mb", result.String())
+	})
+
+	t.Run("unrelated-key-passes", func(t *testing.T) {
+		t.Parallel()
+
+		k := newSequencer()
+
+		result, pass, cmd := k.handle(runeKey('x'))
+		require.True(t, pass)
+		require.Nil(t, cmd)
+		require.Equal(t, "x", result.String())
+	})
+
+	t.Run("broken-sequence-replays-keys", func(t *testing.T) {
+		t.Parallel()
+
+		k := newSequencer()
+
+		_, _, _ = k.handle(runeKey('g'))
+
+		_, pass, cmd := k.handle(runeKey('x'))
+		require.False(t, pass)
+		require.NotNil(t, cmd)
+		require.Empty(t, k.pending)
+	})
+
+	t.Run("outdated-timeout-ignored", func(t *testing.T) {
+		t.Parallel()
+
+		k := newSequencer()
+
+		_, _, _ = k.handle(runeKey('g'))
+		id := k.id
+
+		require.NotNil(t, k.timeout(id))
+		require.Empty(t, k.pending)
+		require.Nil(t, k.timeout(id))
+	})
+}
+
+func Test_newKeySequencer_channelKeymaps(t *testing.T) {
+	t.Parallel()
+
+	keymap := save.BuildDefaultKeyMap()
+
+	channel := keymap
+	channel.DumpChat = key.NewBinding(key.WithKeys("g d"))
+
+	k := newKeySequencer(keymap, save.ChannelKeyMaps{
+		"lirik": {KeyMap: channel, Macros: map[string]string{"<leader>d": "!discord", "ctrl+g": "!socials"}},
+	})
+
+	require.Equal(t, []string{"g", "d"}, k.sequences["g d"], "bindings of channels")
+	require.Equal(t, []string{"\\", "d"}, k.sequences["<leader>d"], "macros of channels")
+	require.NotContains(t, k.sequences, "ctrl+g", "single keys are no sequence")
+
+	deps := newTestDeps(t)
+	deps.Keymap = keymap
+	deps.ChannelKeymaps = save.ChannelKeyMaps{"lirik": {KeyMap: channel}}
+
+	tab := &broadcastTab{channelLogin: "LIRIK", deps: deps}
+	require.Equal(t, []string{"g d"}, tab.keymap().DumpChat.Keys())
+
+	tab.channelLogin = "xqc"
+	require.Equal(t, keymap.DumpChat.Keys(), tab.keymap().DumpChat.Keys())
+}
@@ -0,0 +1,228 @@
+package mainui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/browser"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// linkHintAlphabet contains the characters used for hint labels, home row keys first
+const linkHintAlphabet = "asdfghjkl"
+
+// minLinkHintMatch is the minimum amount of characters of a wrapped link, which need to be on one line to place the hint label
+const minLinkHintMatch = 12
+
+type linkHint struct {
+	label string
+	url   string
+	entry *chatEntry
+}
+
+// generateLinkHintLabels returns n unique labels of equal length, so no label is the prefix of another label
+func generateLinkHintLabels(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	length := 1
+	for capacity := len(linkHintAlphabet); capacity < n; capacity *= len(linkHintAlphabet) {
+		length++
+	}
+
+	labels := make([]string, 0, n)
+	indices := make([]int, length)
+
+	for range n {
+		var b strings.Builder
+		for _, i := range indices {
+			b.WriteByte(linkHintAlphabet[i])
+		}
+		labels = append(labels, b.String())
+
+		// increment like a number in base len(alphabet)
+		for i := length - 1; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(linkHintAlphabet) {
+				break
+			}
+			indices[i] = 0
+		}
+	}
+
+	return labels
+}
+
+// handleStartLinkHintMode labels all links in the currently visible messages.
+// Returns false if there are no links to label.
+func (c *chatWindow) handleStartLinkHintMode() bool {
+	type candidate struct {
+		url   string
+		entry *chatEntry
+	}
+
+	var candidates []candidate
+
+	for _, e := range c.activeEntries() {
+		if e.Position.CursorEnd < c.lineStart || e.Position.CursorStart >= c.lineEnd {
+			continue
+		}
+
+		msg, ok := e.Event.message.(*twitchirc.PrivateMessage)
+		if !ok || e.IsDeleted {
+			continue
+		}
+
+		seen := map[string]struct{}{}
+		for _, u := range extractValidURLs(msg.Message) {
+			if _, ok := seen[u]; ok {
+				continue
+			}
+
+			seen[u] = struct{}{}
+			candidates = append(candidates, candidate{url: u, entry: e})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return false
+	}
+
+	labels := generateLinkHintLabels(len(candidates))
+	c.linkHints = make([]linkHint, 0, len(candidates))
+
+	for i, cand := range candidates {
+		c.linkHints = append(c.linkHints, linkHint{
+			label: labels[i],
+			url:   cand.url,
+			entry: cand.entry,
+		})
+	}
+
+	c.linkHintInput = ""
+	c.state = linkHintChatWindowState
+
+	return true
+}
+
+func (c *chatWindow) handleStopLinkHintMode() {
+	c.state = viewChatWindowState
+	c.linkHints = nil
+	c.linkHintInput = ""
+}
+
+// handleLinkHintKey processes a key press while link hints are shown.
+// Once the typed keys match a label, the hint mode ends and the matched hint is returned.
+// Typing any upper case character requests to copy the link instead of opening it.
+func (c *chatWindow) handleLinkHintKey(msg tea.KeyMsg) (hint linkHint, copyLink bool, matched bool) {
+	if msg.Type == tea.KeyBackspace {
+		if c.linkHintInput != "" {
+			c.linkHintInput = c.linkHintInput[:len(c.linkHintInput)-1]
+		}
+		return linkHint{}, false, false
+	}
+
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		c.handleStopLinkHintMode()
+		return linkHint{}, false, false
+	}
+
+	c.linkHintInput += string(msg.Runes)
+	typed := strings.ToLower(c.linkHintInput)
+	copyLink = typed != c.linkHintInput
+
+	var hasPrefix bool
+	for _, h := range c.linkHints {
+		if h.label == typed {
+			c.handleStopLinkHintMode()
+			return h, copyLink, true
+		}
+
+		if strings.HasPrefix(h.label, typed) {
+			hasPrefix = true
+		}
+	}
+
+	// no label starts with the typed keys, so no label can match anymore
+	if !hasPrefix {
+		c.handleStopLinkHintMode()
+	}
+
+	return linkHint{}, false, false
+}
+
+// applyLinkHintLabels overlays the hint labels onto the start of the links in the visible lines.
+// The label replaces the first characters of the link, so the line width does not change.
+func (c *chatWindow) applyLinkHintLabels(lines []string) []string {
+	lines = append([]string(nil), lines...)
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Reverse(true).Foreground(lipgloss.Color(c.deps.UserConfig.Theme.ChatNoticeAlertColor))
+	dimmedLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(c.deps.UserConfig.Theme.DimmedTextColor))
+	typed := strings.ToLower(c.linkHintInput)
+
+	for _, h := range c.linkHints {
+		start := max(h.entry.Position.CursorStart, c.lineStart)
+		end := min(h.entry.Position.CursorEnd, c.lineEnd-1)
+
+		label := "[" + h.label + "]"
+
+		style := labelStyle
+		if !strings.HasPrefix(h.label, typed) {
+			style = dimmedLabelStyle
+		}
+
+		for i := start; i <= end && i-c.lineStart < len(lines); i++ {
+			idx, n := findLinkStart(lines[i-c.lineStart], h.url)
+			if idx == -1 || n < len(label) {
+				continue
+			}
+
+			line := lines[i-c.lineStart]
+			lines[i-c.lineStart] = line[:idx] + style.Render(label) + line[idx+len(label):]
+			break
+		}
+	}
+
+	return lines
+}
+
+// findLinkStart returns the index of the link in line and how many characters of the link are on this line.
+// The link may be wrapped onto the next line, in that case only its beginning is part of line.
+func findLinkStart(line, url string) (int, int) {
+	for n := len(url); n >= min(len(url), minLinkHintMatch); n-- {
+		prefix := url[:n]
+
+		idx := strings.Index(line, prefix)
+		if idx == -1 {
+			continue
+		}
+
+		// the shortened prefix must end the line, otherwise it's a different link
+		if n < len(url) && strings.TrimRight(stripAnsi(line[idx:]), " ") != prefix {
+			continue
+		}
+
+		return idx, n
+	}
+
+	return -1, 0
+}
+
+// openURL opens the url with the configured opener command, or the system default opener if none is configured
+func openURL(opener, url string) error {
+	fields := strings.Fields(opener)
+	if len(fields) == 0 {
+		return browser.OpenURL(url)
+	}
+
+	cmd := exec.Command(fields[0], append(fields[1:], url)...)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run link opener %q: %w", opener, err)
+	}
+
+	return nil
+}
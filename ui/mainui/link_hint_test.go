@@ -0,0 +1,112 @@
+package mainui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_generateLinkHintLabels(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, generateLinkHintLabels(0))
+	require.Equal(t, []string{"a", "s", "d"}, generateLinkHintLabels(3))
+
+	labels := generateLinkHintLabels(len(linkHintAlphabet) + 2)
+	require.Len(t, labels, len(linkHintAlphabet)+2)
+	require.Equal(t, []string{"aa", "as", "ad"}, labels[:3])
+
+	seen := map[string]struct{}{}
+	for _, l := range labels {
+		require.Len(t, l, 2)
+		require.NotContains(t, seen, l)
+		seen[l] = struct{}{}
+	}
+}
+
+func Test_findLinkStart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		line      string
+		url       string
+		wantIndex int
+		wantLen   int
+	}{
+		{
+			name:      "full link",
+			line:      "12:00:00 user: look https://example.com/clip here",
+			url:       "https://example.com/clip",
+			wantIndex: 20,
+			wantLen:   24,
+		},
+		{
+			name:      "wrapped link",
+			line:      "12:00:00 user: look https://example.com/",
+			url:       "https://example.com/very/long/path",
+			wantIndex: 20,
+			wantLen:   20,
+		},
+		{
+			name:      "different link with same prefix",
+			line:      "12:00:00 user: https://example.com/other",
+			url:       "https://example.com/clip",
+			wantIndex: -1,
+		},
+		{
+			name:      "not on line",
+			line:      "12:00:00 user: no links",
+			url:       "https://example.com",
+			wantIndex: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			idx, n := findLinkStart(tt.line, tt.url)
+			require.Equal(t, tt.wantIndex, idx)
+			require.Equal(t, tt.wantLen, n)
+		})
+	}
+}
+
+func Test_chatWindow_handleLinkHintKey(t *testing.T) {
+	t.Parallel()
+
+	newWindow := func() *chatWindow {
+		return &chatWindow{
+			state: linkHintChatWindowState,
+			linkHints: []linkHint{
+				{label: "a", url: "https://a.example"},
+				{label: "s", url: "https://s.example"},
+			},
+		}
+	}
+
+	c := newWindow()
+	hint, copyLink, matched := c.handleLinkHintKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	require.True(t, matched)
+	require.False(t, copyLink)
+	require.Equal(t, "https://s.example", hint.url)
+	require.Equal(t, viewChatWindowState, c.state)
+
+	c = newWindow()
+	hint, copyLink, matched = c.handleLinkHintKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	require.True(t, matched)
+	require.True(t, copyLink)
+	require.Equal(t, "https://a.example", hint.url)
+
+	c = newWindow()
+	_, _, matched = c.handleLinkHintKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	require.False(t, matched)
+	require.Equal(t, viewChatWindowState, c.state)
+
+	c = newWindow()
+	_, _, matched = c.handleLinkHintKey(tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, matched)
+	require.Equal(t, viewChatWindowState, c.state)
+}
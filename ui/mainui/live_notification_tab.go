@@ -132,6 +132,10 @@ func (l *liveNotificationTab) State() broadcastTabState {
 	return l.state
 }
 
+func (l *liveNotificationTab) IsTyping() bool {
+	return l.chatWindow != nil && l.chatWindow.state != viewChatWindowState
+}
+
 func (l *liveNotificationTab) IsDataLoaded() bool {
 	return true
 }
@@ -0,0 +1,198 @@
+package mainui
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// localCommandResultMessage carries the result of /quote or /count, which is sent to chat like a reply of a bot
+type localCommandResultMessage struct {
+	tabID  string
+	text   string // sent to chat
+	notice string // shown only in the tab instead, like usage and errors
+}
+
+func formatQuote(number int, quote save.Quote) string {
+	return fmt.Sprintf("#%d: %s", number, quote.Text)
+}
+
+// handleQuoteCommand runs /quote [random|<number>], /quote add <text> and /quote remove <number> with the quotes of the channel
+func (t *broadcastTab) handleQuoteCommand(args []string) tea.Cmd {
+	const usage = "Usage: /quote [random|<number>], /quote add <text> or /quote remove <number>"
+
+	store := t.deps.Commands
+	channel := t.channelLogin
+	author := t.account.DisplayName
+	msg := localCommandResultMessage{tabID: t.id}
+
+	action := strings.ToLower(args[0])
+	text := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	return func() tea.Msg {
+		if store == nil {
+			msg.notice = "Quotes are not available"
+			return msg
+		}
+
+		switch action {
+		case "add":
+			if text == "" {
+				msg.notice = usage
+				return msg
+			}
+
+			number, err := store.AddQuote(channel, save.Quote{Text: text, AddedBy: author, CreatedAt: time.Now()})
+			if err != nil {
+				msg.notice = fmt.Sprintf("Failed to save quote: %s", err)
+				return msg
+			}
+
+			msg.text = fmt.Sprintf("Added quote #%d", number)
+			return msg
+		case "remove":
+			number, err := strconv.Atoi(text)
+			if err != nil {
+				msg.notice = usage
+				return msg
+			}
+
+			if err := store.RemoveQuote(channel, number-1); err != nil {
+				msg.notice = fmt.Sprintf("Failed to remove quote: %s", err)
+				return msg
+			}
+
+			msg.notice = fmt.Sprintf("Removed quote #%d", number)
+			return msg
+		}
+
+		quotes, err := store.Quotes(channel)
+		if err != nil {
+			msg.notice = fmt.Sprintf("Failed to load quotes: %s", err)
+			return msg
+		}
+
+		if len(quotes) == 0 {
+			msg.notice = fmt.Sprintf("No quotes for %s, add one with /quote add <text>", channel)
+			return msg
+		}
+
+		if action == "" || action == "random" {
+			i := rand.IntN(len(quotes))
+			msg.text = formatQuote(i+1, quotes[i])
+			return msg
+		}
+
+		number, err := strconv.Atoi(action)
+		if err != nil {
+			msg.notice = usage
+			return msg
+		}
+
+		if number < 1 || number > len(quotes) {
+			msg.notice = fmt.Sprintf("Quote #%d not found, there are %d quotes", number, len(quotes))
+			return msg
+		}
+
+		msg.text = formatQuote(number, quotes[number-1])
+		return msg
+	}
+}
+
+// parseCounterChange parses the change of a /count command: +n and -n add to the counter, a number sets it and reset sets it to 0
+func parseCounterChange(arg string) (func(value int) int, error) {
+	if strings.EqualFold(arg, "reset") {
+		return func(int) int { return 0 }, nil
+	}
+
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not +n, -n, a number or reset", arg)
+	}
+
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		return func(value int) int { return value + n }, nil
+	}
+
+	return func(int) int { return n }, nil
+}
+
+// handleCountCommand runs /count <name> [+n|-n|<number>|reset], which shows or changes a counter of the channel
+func (t *broadcastTab) handleCountCommand(args []string) tea.Cmd {
+	const usage = "Usage: /count <name> [+n|-n|<number>|reset]"
+
+	store := t.deps.Commands
+	channel := t.channelLogin
+	msg := localCommandResultMessage{tabID: t.id}
+
+	args = strings.Fields(strings.Join(args, " "))
+	if len(args) == 0 || len(args) > 2 {
+		msg.notice = usage
+		return func() tea.Msg { return msg }
+	}
+
+	name := args[0]
+
+	var change func(int) int
+	if len(args) == 2 {
+		var err error
+		if change, err = parseCounterChange(args[1]); err != nil {
+			msg.notice = usage
+			return func() tea.Msg { return msg }
+		}
+	}
+
+	return func() tea.Msg {
+		if store == nil {
+			msg.notice = "Counters are not available"
+			return msg
+		}
+
+		var (
+			value int
+			err   error
+		)
+
+		if change == nil {
+			value, err = store.Counter(channel, name)
+		} else {
+			value, err = store.ChangeCounter(channel, name, change)
+		}
+
+		if err != nil {
+			msg.notice = fmt.Sprintf("Failed to update counter: %s", err)
+			return msg
+		}
+
+		msg.text = fmt.Sprintf("%s: %d", name, value)
+		return msg
+	}
+}
+
+// handleLocalCommandResult sends the result of /quote or /count to chat, notices are only shown in the tab
+func (t *broadcastTab) handleLocalCommandResult(msg localCommandResultMessage) tea.Cmd {
+	if msg.notice == "" && !t.account.IsAnonymous {
+		return t.sendMessage(msg.text)
+	}
+
+	text := cmp.Or(msg.notice, msg.text)
+	tabID, accountID := t.id, t.account.ID
+
+	return func() tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseCounterChange(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		arg     string
+		want    int
+		wantErr bool
+	}{
+		"add":      {arg: "+2", want: 7},
+		"subtract": {arg: "-1", want: 4},
+		"set":      {arg: "10", want: 10},
+		"reset":    {arg: "Reset", want: 0},
+		"invalid":  {arg: "+one", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			change, err := parseCounterChange(tt.arg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, change(5))
+		})
+	}
+}
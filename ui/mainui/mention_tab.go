@@ -119,7 +119,12 @@ func (m *mentionTab) Update(msg tea.Msg) (tab, tea.Cmd) {
 				}
 			}
 
-			if !mentioned || messageMatchesBlocked(event.message, m.deps.UserConfig.Settings.BlockSettings) {
+			if favorites := m.deps.UserConfig.Settings.Favorites; !mentioned && favorites.ShowInMentions && favorites.IsFavorite(privMsg.LoginName) {
+				event.displayModifier.messageSuffix = fmt.Sprintf(" (favorite in %s)", privMsg.ChannelUserName)
+				mentioned = true
+			}
+
+			if !mentioned || messageMatchesBlocked(event.message, m.deps.UserConfig.Settings.BlockSettings) || isBlockedSender(m.deps, event.accountID, event.message) {
 				return m, nil
 			}
 
@@ -177,6 +182,10 @@ func (m *mentionTab) State() broadcastTabState {
 	return m.state
 }
 
+func (m *mentionTab) IsTyping() bool {
+	return m.chatWindow != nil && m.chatWindow.state != viewChatWindowState
+}
+
 func (m *mentionTab) IsDataLoaded() bool {
 	return m.hasDataLoaded
 }
@@ -1,8 +1,11 @@
 package mainui
 
 import (
+	"time"
+
 	"github.com/julez-dev/chatuino/save"
 	"github.com/julez-dev/chatuino/twitch/eventsub"
+	"github.com/julez-dev/chatuino/twitch/seventv"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
 )
@@ -29,6 +32,12 @@ type chatEventMessage struct {
 	message         twitchirc.IRCer
 	displayModifier messageContentModifier // modifier for the original irc message
 
+	// message was hidden by a script and is not shown in any tab
+	hidden bool
+
+	// set for own messages shown before they were received through IRC
+	send *sentMessage
+
 	// if message should only be sent to a specific tab ID
 	// if empty send to all
 	tabID string
@@ -39,6 +48,12 @@ type (
 		wordReplacements wordReplacement
 		badgeReplacement wordReplacement
 		messageSuffix    string
+		namePaint        *seventv.Paint // 7TV paint of the author, drawn over the username
+		bot              bool           // the author is a known bot
+		faded            bool           // the author writes faster than the spam fade limit
+		emoteRepeat      emoteRepeat    // set if the message only repeats one emote, shown as the emote once with the count
+		translation      string         // shown below the message, empty if the message is not translated
+		grouped          bool           // continues the messages of the same author shown above, the badges and name are left out
 		strikethrough    bool
 		italic           bool
 	}
@@ -69,11 +84,17 @@ type EventSubMessage struct {
 // polledStreamInfoMessage comes when current stream info is refreshed
 type polledStreamInfoMessage struct {
 	streamInfos []setStreamInfoMessage
+	once        bool // refreshed after the user was idle, the regular refreshes are already scheduled
 }
 
 // appStateSaveMessage comes when current app state was saved
 type appStateSaveMessage struct{}
 
+// terminalSequenceMessage comes when an escape sequence, like the OSC 52 clipboard sequence, should be written to the terminal
+type terminalSequenceMessage struct {
+	sequence string
+}
+
 // imageCleanupTickMessage comes when images should be cleaned up
 type imageCleanupTickMessage struct {
 	deletionCommand string
@@ -88,15 +109,38 @@ type joinChannelMessage struct {
 
 // setStreamInfoMessage comes when new live info about a streamer was fetched
 type setStreamInfoMessage struct {
-	target   string // the broadcasters ID
-	username string // is broadcasters display name
-	viewer   int
-	title    string
-	game     string
-	isLive   bool
+	target    string // the broadcasters ID
+	username  string // is broadcasters display name
+	viewer    int
+	title     string
+	game      string
+	isLive    bool
+	startedAt time.Time
 }
 
 // requestNotificationIconMessage comes when app requests an notification icon for a tab
 type requestNotificationIconMessage struct {
 	tabID string
 }
+
+// setThemeMessage requests to switch the active theme, errors are reported to the requesting tab
+type setThemeMessage struct {
+	name      string
+	tabID     string
+	accountID string
+}
+
+// themeChangedMessage is sent to all components after the active theme changed, so cached styles can be rebuilt
+type themeChangedMessage struct{}
+
+// tabUnreadMessage comes when a tab which is not focused received a message
+type tabUnreadMessage struct {
+	tabID  string
+	unread int
+}
+
+// tabDraftMessage comes when the message input of a tab becomes empty or non-empty
+type tabDraftMessage struct {
+	tabID    string
+	hasDraft bool
+}
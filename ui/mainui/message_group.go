@@ -0,0 +1,44 @@
+package mainui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// messageGroupWindow is the longest gap between two messages of a group, a later message starts a new group with the name shown again
+const messageGroupWindow = 5 * time.Minute
+
+// continuesGroup reports if the message continues the group of the message shown above, so its badges and name are left out.
+// Messages of the same author are grouped until another message, a new day or the new messages separator comes in between.
+func (c *chatWindow) continuesGroup(prev *chatEntry, event chatEventMessage) bool {
+	// screen readers need the name in front of every message
+	if !c.deps.UserConfig.Settings.Chat.GroupMessages || c.screenReader() || prev == nil || prev == c.lastRead {
+		return false
+	}
+
+	msg, ok := event.message.(*twitchirc.PrivateMessage)
+	if !ok {
+		return false
+	}
+
+	prevMsg, ok := prev.Event.message.(*twitchirc.PrivateMessage)
+	if !ok || !strings.EqualFold(msg.LoginName, prevMsg.LoginName) || event.channelGuestDisplayName != prev.Event.channelGuestDisplayName {
+		return false
+	}
+
+	gap := msg.TMISentTS.Sub(prevMsg.TMISentTS)
+
+	return gap >= 0 && gap <= messageGroupWindow && isSameDay(msg.TMISentTS, prevMsg.TMISentTS)
+}
+
+// setGrouped changes if the entry continues a group, the entry is rendered again if it changed
+func (e *chatEntry) setGrouped(grouped bool) {
+	if e.Event.displayModifier.grouped == grouped {
+		return
+	}
+
+	e.Event.displayModifier.grouped = grouped
+	e.rendered = nil
+}
@@ -0,0 +1,64 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatWindow_groupMessages(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Chat.GroupMessages = true
+
+	start := time.Date(2024, 3, 9, 14, 0, 0, 0, time.Local)
+	message := func(id, login string, after time.Duration, text string) chatEventMessage {
+		return chatEventMessage{message: &twitchirc.PrivateMessage{ID: id, LoginName: login, DisplayName: login, Message: text, TMISentTS: start.Add(after)}}
+	}
+
+	c := newChatWindow(80, 20, deps)
+
+	lines := func() []string {
+		lines := make([]string, 0, len(c.lines))
+		for _, line := range c.lines {
+			lines = append(lines, strings.TrimRight(stripAnsi(line), " "))
+		}
+
+		return lines
+	}
+
+	c.handleMessage(message("1", "julez", 0, "first"))
+	c.handleMessage(message("2", "julez", time.Second*30, "second"))
+	c.handleMessage(message("3", "lirik", time.Minute, "hi"))
+	c.handleMessage(message("4", "lirik", time.Minute*10, "later"))
+
+	require.Equal(t, []string{
+		"  14:00:00 julez: first",
+		"  14:00:30        second", // the timestamp is kept, the text is aligned below the first message
+		"  14:01:00 lirik: hi",
+		"> 14:10:00 lirik: later", // too long after the previous message
+	}, lines())
+
+	// deleted messages keep their group, struck through
+	c.handleMessage(chatEventMessage{message: &twitchirc.ClearMessage{Login: "julez", TargetMsgID: "1", TMISentTS: start.Add(time.Minute * 11)}})
+	require.Equal(t, "  14:00:00 julez: first", lines()[0])
+	require.Equal(t, "  14:00:30        second", lines()[1])
+
+	// the new messages separator starts a new group
+	c.markRead()
+	c.handleMessage(message("5", "lirik", time.Minute*12, "back"))
+	require.Contains(t, lines()[len(lines())-1], "14:12:00 lirik: back")
+
+	// once the first message of a group is cleaned up, the next one shows the name
+	c.entries = c.entries[1:]
+	c.recalculateLines()
+	require.Equal(t, "  14:00:30 julez: second", lines()[0])
+
+	deps.UserConfig.Settings.Chat.GroupMessages = false
+	c.handleMessage(message("6", "lirik", time.Minute*13, "again"))
+	require.Contains(t, lines()[len(lines())-1], "14:13:00 lirik: again")
+}
@@ -0,0 +1,223 @@
+package mainui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+// mouseScrollMessages is how many messages one step of the mouse wheel scrolls
+const mouseScrollMessages = 3
+
+// handleMouse switches to the clicked tab of the header. Other mouse events are passed to the focused tab,
+// with the coordinates relative to the top left corner of the tab.
+func (r *Root) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if !r.dependencies.UserConfig.Settings.Mouse.Enabled || !r.hasLoadedSession || r.screenType != mainScreen || len(r.tabs) <= r.tabCursor {
+		return nil
+	}
+
+	// the update notice takes the first line, the notification tray below the tabs isn't clickable
+	msg.Y -= r.updateNoticeHeight()
+	if msg.Y >= r.contentHeight() {
+		return nil
+	}
+
+	if r.sidebar.visible {
+		if msg.X < r.sidebar.width {
+			return nil
+		}
+
+		msg.X -= r.sidebar.width
+	}
+
+	var inHeader bool
+	if r.dependencies.UserConfig.Settings.VerticalTabList {
+		headerWidth := lipgloss.Width(r.header.View())
+		inHeader = msg.X < headerWidth
+
+		if !inHeader {
+			msg.X -= headerWidth
+		}
+	} else {
+		headerHeight := r.getHeaderHeight()
+		inHeader = msg.Y < headerHeight
+
+		if !inHeader {
+			msg.Y -= headerHeight
+		}
+	}
+
+	if inHeader {
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return nil
+		}
+
+		if id, ok := r.header.TabAt(msg.X, msg.Y); ok {
+			r.goToTab(slices.IndexFunc(r.tabs, func(t tab) bool { return t.ID() == id }))
+		}
+
+		return nil
+	}
+
+	var cmd tea.Cmd
+	r.tabs[r.tabCursor], cmd = r.tabs[r.tabCursor].Update(msg)
+
+	return cmd
+}
+
+// handleMouse passes the mouse event to the chat window below the pointer, a clicked link is opened
+func (t *broadcastTab) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if t.state == emoteOverviewMode || t.sendPrompt != nil {
+		return nil
+	}
+
+	// the stream info takes one line, even while there is no stream info
+	msg.Y -= lipgloss.Height(t.streamInfo.View())
+
+	if pollView := t.poll.View(); pollView != "" {
+		msg.Y -= lipgloss.Height(pollView)
+	}
+
+	cw := t.chatWindow
+
+	// the user inspect window is shown below the chat
+	if msg.Y >= cw.height {
+		if t.state != userInspectMode && t.state != userInspectInsertMode {
+			return nil
+		}
+
+		msg.Y -= cw.height
+		if info := t.userInspect.renderUserInfo(); info != "" {
+			msg.Y -= lipgloss.Height(info)
+		}
+
+		cw = t.userInspect.chatWindow
+	}
+
+	link := cw.handleMouse(msg)
+	if link == "" {
+		return nil
+	}
+
+	return t.openLink(link, false)
+}
+
+// handleMouse scrolls the chat with the mouse wheel and selects the clicked message.
+// It returns the link which was clicked, empty if the click was not on a link.
+func (c *chatWindow) handleMouse(msg tea.MouseMsg) string {
+	if c.state != viewChatWindowState && c.state != searchChatWindowState {
+		return ""
+	}
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		c.messageUp(mouseScrollMessages)
+		return ""
+	case msg.Button == tea.MouseButtonWheelDown:
+		c.messageDown(mouseScrollMessages)
+		return ""
+	case msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress:
+		return ""
+	}
+
+	y := msg.Y
+	if c.state == searchChatWindowState {
+		y-- // the search input is shown above the messages
+	}
+
+	line := c.lineStart + y
+	if y < 0 || line >= c.lineEnd {
+		return ""
+	}
+
+	entry := c.entryAtLine(line)
+	if entry == nil {
+		return ""
+	}
+
+	for _, e := range c.entries {
+		e.Selected = false
+	}
+
+	// the cursor stays on the clicked line, so the view does not scroll to the start or end of the message
+	entry.Selected = true
+	c.cursor = line
+	c.updatePort()
+	c.markSelectedMessage()
+
+	return c.linkAt(entry, line, msg.X)
+}
+
+// entryAtLine returns the shown message the line belongs to, nil if there is none
+func (c *chatWindow) entryAtLine(line int) *chatEntry {
+	for _, e := range c.activeEntries() {
+		if line >= e.Position.CursorStart && line <= e.Position.CursorEnd {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// linkAt returns the link of the message shown at the cell x of the line. A link wrapped onto multiple lines can be clicked on each line.
+func (c *chatWindow) linkAt(entry *chatEntry, line, x int) string {
+	msg, ok := entry.Event.message.(*twitchirc.PrivateMessage)
+	if !ok || entry.IsDeleted {
+		return ""
+	}
+
+	word := wordAt(ansi.Strip(c.lines[line]), x)
+	if word == "" {
+		return ""
+	}
+
+	for _, u := range extractValidURLs(msg.Message) {
+		switch {
+		// punctuation around a link is not part of it
+		case strings.Contains(word, u):
+			return u
+		// the start or end of a wrapped link
+		case len(word) >= 3 && (strings.HasPrefix(u, word) || strings.HasSuffix(u, word)):
+			return u
+		// the middle of a link wrapped onto more than two lines
+		case len(word) >= minLinkHintMatch && strings.Contains(u, word):
+			return u
+		}
+	}
+
+	return ""
+}
+
+// wordAt returns the word covering the cell x of the line, words are separated by spaces
+func wordAt(line string, x int) string {
+	column := 0
+
+	for word := range strings.SplitSeq(line, " ") {
+		width := ansi.StringWidth(word)
+		if x >= column && x < column+width {
+			return word
+		}
+
+		column += width + 1
+	}
+
+	return ""
+}
+
+// openLink opens the link with the configured opener, failures are only logged
+func (c *chatWindow) openLink(link string) tea.Cmd {
+	opener := c.deps.UserConfig.Settings.Links.Opener
+
+	return func() tea.Msg {
+		if err := openURL(opener, link); err != nil {
+			log.Logger.Err(err).Str("url", link).Msg("failed to open clicked link")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package mainui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_wordAt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+		x    int
+		want string
+	}{
+		{name: "first word", line: "hello chat", x: 0, want: "hello"},
+		{name: "last cell of word", line: "hello chat", x: 4, want: "hello"},
+		{name: "space", line: "hello chat", x: 5, want: ""},
+		{name: "second word", line: "hello chat", x: 6, want: "chat"},
+		{name: "behind the line", line: "hello chat", x: 20, want: ""},
+		{name: "wide characters", line: "日本 https://example.com", x: 6, want: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, wordAt(tt.line, tt.x))
+		})
+	}
+}
+
+func Test_chatWindow_handleMouse(t *testing.T) {
+	t.Parallel()
+
+	const link = "https://example.com/a/very/long/link/which/is/wrapped/onto/the/next/line"
+
+	newWindow := func() *chatWindow {
+		deps := newTestDeps(t)
+
+		c := newChatWindow(40, 5, deps)
+		for i := range 10 {
+			c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{ID: fmt.Sprint(i), LoginName: "a", DisplayName: "a", Message: fmt.Sprintf("message %d", i)}})
+		}
+		c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{ID: "link", LoginName: "b", DisplayName: "b", Message: "look " + link + " here"}})
+
+		return c
+	}
+
+	click := func(x, y int) tea.MouseMsg {
+		return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	}
+
+	t.Run("wheel scrolls", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.moveToBottom()
+		start := c.lineStart
+
+		require.Empty(t, c.handleMouse(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp}))
+		require.Less(t, c.lineStart, start)
+
+		c.handleMouse(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+		c.handleMouse(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+		require.Equal(t, start, c.lineStart)
+	})
+
+	t.Run("click selects message", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.moveToTop()
+
+		require.Empty(t, c.handleMouse(click(2, 3)))
+		require.True(t, c.entries[3].Selected)
+		require.False(t, c.entries[0].Selected)
+		require.Equal(t, 0, c.lineStart, "the view does not scroll")
+		require.True(t, strings.HasPrefix(c.lines[3], c.indicator))
+
+		// below the last message and releasing the button select nothing
+		require.Empty(t, c.handleMouse(click(2, 10)))
+		require.Empty(t, c.handleMouse(tea.MouseMsg{X: 2, Y: 1, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft}))
+		require.True(t, c.entries[3].Selected)
+	})
+
+	t.Run("click on link", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.moveToBottom()
+
+		entry := c.entries[len(c.entries)-1]
+		require.Greater(t, entry.Position.CursorEnd, entry.Position.CursorStart, "the link is wrapped")
+
+		first := ansi.Strip(c.lines[entry.Position.CursorStart])
+		y := entry.Position.CursorStart - c.lineStart
+
+		require.Empty(t, c.handleMouse(click(strings.Index(first, "look"), y)))
+		require.Equal(t, link, c.handleMouse(click(strings.Index(first, "https"), y)))
+
+		// the wrapped part of the link on the next line
+		next := ansi.Strip(c.lines[entry.Position.CursorStart+1])
+		x := len(next) - len(strings.TrimLeft(next, " >"))
+		require.Equal(t, link, c.handleMouse(click(x, y+1)))
+	})
+
+	t.Run("ignored while link hints are shown", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.moveToTop()
+		c.state = linkHintChatWindowState
+
+		c.handleMouse(click(2, 3))
+		require.False(t, c.entries[3].Selected)
+	})
+}
+
+func Test_horizontalTabHeader_TabAt(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	h := newHorizontalTabHeader(80, deps)
+	first, _ := h.AddTab("lirik", "")
+	second, _ := h.AddTab("xqc", "")
+	h.SelectTab(first)
+
+	lines := strings.Split(ansi.Strip(h.View()), "\n")
+	// the borders and the bullet take more bytes than cells
+	firstX := ansi.StringWidth(lines[1][:strings.Index(lines[1], "lirik")])
+	secondX := ansi.StringWidth(lines[1][:strings.Index(lines[1], "xqc")])
+
+	id, ok := h.TabAt(firstX, 1)
+	require.True(t, ok)
+	require.Equal(t, first, id)
+
+	id, ok = h.TabAt(secondX+2, 1)
+	require.True(t, ok)
+	require.Equal(t, second, id)
+
+	_, ok = h.TabAt(firstX, 0)
+	require.False(t, ok, "top border")
+
+	_, ok = h.TabAt(70, 1)
+	require.False(t, ok, "behind the last tab")
+}
+
+func Test_verticalTabHeader_TabAt(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	v := newVerticalTabHeader(20, 10, deps)
+	first, _ := v.AddTab("lirik", "")
+	second, _ := v.AddTab("xqc", "")
+	v.SelectTab(first)
+
+	lines := strings.Split(ansi.Strip(v.View()), "\n")
+	require.Contains(t, lines[1], "lirik")
+	require.Contains(t, lines[2], "xqc")
+
+	id, ok := v.TabAt(3, 1)
+	require.True(t, ok)
+	require.Equal(t, first, id)
+
+	id, ok = v.TabAt(3, 2)
+	require.True(t, ok)
+	require.Equal(t, second, id)
+
+	_, ok = v.TabAt(3, 3)
+	require.False(t, ok, "below the last tab")
+
+	_, ok = v.TabAt(3, 0)
+	require.False(t, ok, "top border")
+}
@@ -0,0 +1,161 @@
+package mainui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+// notesChangedMessage is sent after a note was added to or removed from a channel or user, or the attempt failed
+type notesChangedMessage struct {
+	tabID string // tab which requested the change
+	kind  save.NoteKind
+	name  string
+	text  string // result shown in the tab which requested the change
+}
+
+// loadNotes returns the notes of a channel or user, notes are optional so errors are only logged
+func loadNotes(deps *DependencyContainer, kind save.NoteKind, name string) []save.Note {
+	if deps.Notes == nil || name == "" {
+		return nil
+	}
+
+	notes, err := deps.Notes.Notes(kind, name)
+	if err != nil {
+		log.Logger.Err(err).Str("name", name).Msg("failed to load notes")
+		return nil
+	}
+
+	return notes
+}
+
+// formatNote formats a note with the date it was written, like "01.05.2024: asked mods about X"
+func formatNote(note save.Note) string {
+	return note.CreatedAt.Format("02.01.2006") + ": " + termtext.Sanitize(note.Text)
+}
+
+// noteTarget returns what a note command refers to, a user if the first argument is @user, else the channel of the tab
+func (t *broadcastTab) noteTarget(args []string) (save.NoteKind, string, []string) {
+	if len(args) > 0 && strings.HasPrefix(args[0], "@") && len(args[0]) > 1 {
+		return save.NoteUser, strings.ToLower(args[0][1:]), args[1:]
+	}
+
+	return save.NoteChannel, t.channelLogin, args
+}
+
+// handleNoteCommand runs /note [@user] <text>, which attaches a note to the channel or a user
+func (t *broadcastTab) handleNoteCommand(args []string) tea.Cmd {
+	kind, name, args := t.noteTarget(args)
+	text := strings.TrimSpace(strings.Join(args, " "))
+
+	if text == "" {
+		return t.noteResult(kind, name, "Usage: /note [@user] <text>")
+	}
+
+	notes := t.deps.Notes
+	msg := notesChangedMessage{tabID: t.id, kind: kind, name: name}
+
+	return func() tea.Msg {
+		if notes == nil {
+			msg.text = "Notes are not available"
+			return msg
+		}
+
+		if err := notes.AddNote(kind, name, save.Note{Text: text, CreatedAt: time.Now()}); err != nil {
+			msg.text = fmt.Sprintf("Failed to save note: %s", err)
+			return msg
+		}
+
+		msg.text = fmt.Sprintf("Added note to %s", name)
+		return msg
+	}
+}
+
+// handleRemoveNoteCommand runs /removenote [@user] <number>, the number is shown by /notes
+func (t *broadcastTab) handleRemoveNoteCommand(args []string) tea.Cmd {
+	kind, name, args := t.noteTarget(args)
+
+	number, err := strconv.Atoi(strings.Join(args, ""))
+	if err != nil {
+		return t.noteResult(kind, name, "Usage: /removenote [@user] <number>")
+	}
+
+	notes := t.deps.Notes
+	msg := notesChangedMessage{tabID: t.id, kind: kind, name: name}
+
+	return func() tea.Msg {
+		if notes == nil {
+			msg.text = "Notes are not available"
+			return msg
+		}
+
+		if err := notes.RemoveNote(kind, name, number-1); err != nil {
+			msg.text = fmt.Sprintf("Failed to remove note: %s", err)
+			return msg
+		}
+
+		msg.text = fmt.Sprintf("Removed note %d from %s", number, name)
+		return msg
+	}
+}
+
+// handleNotesCommand runs /notes [@user], which lists all notes of the channel or a user
+func (t *broadcastTab) handleNotesCommand(args []string) tea.Cmd {
+	kind, name, _ := t.noteTarget(args)
+
+	notes := loadNotes(t.deps, kind, name)
+	if len(notes) == 0 {
+		return t.noteResult(kind, name, fmt.Sprintf("No notes for %s, add one with /note", name))
+	}
+
+	lines := make([]string, 0, len(notes)+1)
+	lines = append(lines, fmt.Sprintf("Notes for %s:", name))
+
+	for i, note := range notes {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, formatNote(note)))
+	}
+
+	return t.noteResult(kind, name, strings.Join(lines, "\n"))
+}
+
+func (t *broadcastTab) noteResult(kind save.NoteKind, name, text string) tea.Cmd {
+	msg := notesChangedMessage{tabID: t.id, kind: kind, name: name, text: text}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// handleNotesChanged shows the changed notes in the stream info and user card and reports the result in the tab which requested it
+func (t *broadcastTab) handleNotesChanged(msg notesChangedMessage) tea.Cmd {
+	switch {
+	case msg.kind == save.NoteChannel && strings.EqualFold(msg.name, t.channelLogin):
+		t.streamInfo.notes = loadNotes(t.deps, save.NoteChannel, t.channelLogin)
+		t.HandleResize()
+	case msg.kind == save.NoteUser && t.userInspect != nil && strings.EqualFold(msg.name, t.userInspect.login()):
+		t.userInspect.notes = loadNotes(t.deps, save.NoteUser, msg.name)
+		t.HandleResize()
+	}
+
+	if msg.tabID != t.id || msg.text == "" {
+		return nil
+	}
+
+	tabID, accountID := t.id, t.account.ID
+	return func() tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       msg.text,
+			},
+		}
+	}
+}
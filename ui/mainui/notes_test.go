@@ -0,0 +1,50 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_broadcastTab_noteCommands(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.Notes = save.NewNoteStore(afero.NewMemMapFs())
+	tab := &broadcastTab{id: "tab", channelLogin: "lirik", deps: deps}
+
+	msg := tab.handleNoteCommand([]string{"raid", "train", "on", "fridays"})().(notesChangedMessage)
+	require.Equal(t, notesChangedMessage{tabID: "tab", kind: save.NoteChannel, name: "lirik", text: "Added note to lirik"}, msg)
+
+	msg = tab.handleNoteCommand([]string{"@Julez", "asked", "mods", "about", "X"})().(notesChangedMessage)
+	require.Equal(t, save.NoteUser, msg.kind)
+	require.Equal(t, "julez", msg.name)
+
+	msg = tab.handleNotesCommand([]string{"@julez"})().(notesChangedMessage)
+	require.Contains(t, msg.text, "Notes for julez:\n1. ")
+	require.Contains(t, msg.text, ": asked mods about X")
+
+	msg = tab.handleNoteCommand([]string{"@julez"})().(notesChangedMessage)
+	require.Equal(t, "Usage: /note [@user] <text>", msg.text)
+
+	msg = tab.handleRemoveNoteCommand([]string{"@julez", "2"})().(notesChangedMessage)
+	require.Equal(t, "Failed to remove note: note 2 not found", msg.text)
+
+	msg = tab.handleRemoveNoteCommand([]string{"@julez", "1"})().(notesChangedMessage)
+	require.Equal(t, "Removed note 1 from julez", msg.text)
+	require.Empty(t, loadNotes(deps, save.NoteUser, "julez"))
+}
+
+func Test_streamInfo_notes(t *testing.T) {
+	t.Parallel()
+
+	s := newStreamInfo("1", nil, 80)
+	require.Empty(t, s.View())
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.notes = []save.Note{{Text: "first", CreatedAt: at}, {Text: "asked mods about X", CreatedAt: at}}
+	require.Contains(t, s.View(), "Note 01.05.2024: asked mods about X (+1 more, /notes)")
+}
@@ -0,0 +1,239 @@
+package mainui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// maxTrayNotifications is the number of pending notifications kept, the oldest are dropped first
+const maxTrayNotifications = 50
+
+type trayNotificationKind int
+
+const (
+	trayWhisper trayNotificationKind = iota
+	trayMention
+	trayGoLive
+)
+
+// trayNotification is a whisper, a mention in a background tab or a channel which went live, waiting to be seen
+type trayNotification struct {
+	kind      trayNotificationKind
+	id        string // message ID of whispers and mentions, the same message received by multiple accounts is only added once
+	accountID string
+	channel   string // channel login of mentions and go live notifications
+	user      string // display name of the author of whispers and mentions, or of the channel which went live
+	text      string
+}
+
+type trayCycleMessage struct{}
+
+// notificationTray keeps notifications until they are dismissed, the line at the bottom cycles through them
+type notificationTray struct {
+	deps *DependencyContainer
+
+	notifications []trayNotification // oldest first
+	cursor        int                // index of the shown notification
+	cycling       bool               // a cycle tick is pending
+}
+
+func newNotificationTray(deps *DependencyContainer) *notificationTray {
+	return &notificationTray{deps: deps}
+}
+
+// add shows the notification, the tray keeps cycling through all pending notifications
+func (t *notificationTray) add(n trayNotification) tea.Cmd {
+	if !t.deps.UserConfig.Settings.NotificationTray.Enabled {
+		return nil
+	}
+
+	if n.id != "" && slices.ContainsFunc(t.notifications, func(p trayNotification) bool { return p.kind == n.kind && p.id == n.id }) {
+		return nil
+	}
+
+	t.notifications = append(t.notifications, n)
+	if len(t.notifications) > maxTrayNotifications {
+		t.notifications = t.notifications[len(t.notifications)-maxTrayNotifications:]
+	}
+
+	t.cursor = len(t.notifications) - 1
+
+	return t.cycle()
+}
+
+// cycle schedules showing the next notification, if there is more than one and no tick is pending
+func (t *notificationTray) cycle() tea.Cmd {
+	// screen readers would read the line on every cycle and reduced motion avoids the changing line,
+	// the newest notification stays until it is dismissed
+	if t.cycling || len(t.notifications) < 2 || t.still() {
+		return nil
+	}
+
+	t.cycling = true
+
+	return tea.Tick(t.deps.UserConfig.Settings.NotificationTray.CycleInterval, func(time.Time) tea.Msg {
+		return trayCycleMessage{}
+	})
+}
+
+func (t *notificationTray) Update(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(trayCycleMessage); !ok {
+		return nil
+	}
+
+	t.cycling = false
+	if len(t.notifications) < 2 || t.still() {
+		return nil
+	}
+
+	t.cursor = (t.cursor + 1) % len(t.notifications)
+
+	return t.cycle()
+}
+
+// take removes the shown notification, the jump and dismiss actions apply to it
+func (t *notificationTray) take() (trayNotification, bool) {
+	if len(t.notifications) == 0 {
+		return trayNotification{}, false
+	}
+
+	n := t.notifications[t.cursor]
+	t.notifications = slices.Delete(t.notifications, t.cursor, t.cursor+1)
+
+	if t.cursor >= len(t.notifications) {
+		t.cursor = 0
+	}
+
+	return n, true
+}
+
+// height is the number of lines taken by the tray below the tabs
+func (t *notificationTray) height() int {
+	if !t.deps.UserConfig.Settings.NotificationTray.Enabled {
+		return 0
+	}
+
+	return 1
+}
+
+func (t *notificationTray) View(width int) string {
+	if t.height() == 0 {
+		return ""
+	}
+
+	theme := t.deps.UserConfig.Theme
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	if len(t.notifications) == 0 {
+		return dimmedStyle.Render(ansi.Truncate(" No notifications", width, "…"))
+	}
+
+	n := t.notifications[t.cursor]
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatNoticeAlertColor))
+
+	var label string
+	switch n.kind {
+	case trayWhisper:
+		label = "Whisper from " + n.user
+	case trayMention:
+		label = n.user + " mentioned you in " + n.channel
+	case trayGoLive:
+		label = n.user + " is live"
+	}
+
+	text := fmt.Sprintf(" [%d/%d] ", t.cursor+1, len(t.notifications)) + labelStyle.Render(termtext.Sanitize(label))
+	if n.text != "" {
+		text += ": " + termtext.Sanitize(n.text)
+	}
+
+	hint := dimmedStyle.Render(fmt.Sprintf(" · %s jump · %s dismiss",
+		t.deps.Keymap.JumpToNotification.Help().Key,
+		t.deps.Keymap.DismissNotification.Help().Key,
+	))
+
+	// the key hints stay visible, long messages are cut off instead
+	text = ansi.Truncate(text, max(width-lipgloss.Width(hint), 0), "…")
+
+	return ansi.Truncate(text+hint, width, "…")
+}
+
+// collectTrayNotification adds whispers and mentions of the account in channels which are not shown right now to the tray
+func (r *Root) collectTrayNotification(accountID string, message twitchirc.IRCer) tea.Cmd {
+	switch msg := message.(type) {
+	case *twitchirc.Whisper:
+		return r.tray.add(trayNotification{
+			kind:      trayWhisper,
+			id:        msg.ID,
+			accountID: accountID,
+			user:      msg.DisplayName,
+			text:      msg.Message,
+		})
+	case *twitchirc.PrivateMessage:
+		if msg.UserID == accountID || !r.mentionsAccount(accountID, msg) || r.isShownChannel(msg.ChannelUserName) {
+			return nil
+		}
+
+		return r.tray.add(trayNotification{
+			kind:      trayMention,
+			id:        msg.ID,
+			accountID: accountID,
+			channel:   msg.ChannelUserName,
+			user:      msg.DisplayName,
+			text:      msg.Message,
+		})
+	}
+
+	return nil
+}
+
+// isShownChannel reports if the chat of the channel is visible in the focused tab
+func (r *Root) isShownChannel(channel string) bool {
+	if r.screenType != mainScreen || len(r.tabs) <= r.tabCursor {
+		return false
+	}
+
+	focused := r.tabs[r.tabCursor]
+
+	return focused.Kind() == broadcastTabKind && strings.EqualFold(focused.Channel(), channel)
+}
+
+// jumpToNotification switches to the channel of the shown mention or go live notification, whispers are posted in the focused tab
+func (r *Root) jumpToNotification() tea.Cmd {
+	n, ok := r.tray.take()
+	if !ok {
+		return nil
+	}
+
+	if n.kind == trayWhisper {
+		return r.focusedTabNotice(fmt.Sprintf("Whisper from %s: %s", n.user, n.text))
+	}
+
+	var account save.Account
+	for _, a := range r.dependencies.Accounts {
+		if a.ID == n.accountID {
+			account = a
+		}
+	}
+
+	return r.switchToChannel(account, n.channel)
+}
+
+// dismissNotification removes the shown notification without jumping to it
+func (r *Root) dismissNotification() {
+	r.tray.take()
+}
+
+// still reports if the tray shows a single notification instead of cycling through them
+func (t *notificationTray) still() bool {
+	accessibility := t.deps.UserConfig.Settings.Accessibility
+	return accessibility.ScreenReader || accessibility.ReducedMotion
+}
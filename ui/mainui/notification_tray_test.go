@@ -0,0 +1,91 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_notificationTray(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.NotificationTray.Enabled = true
+
+	tray := newNotificationTray(deps)
+	require.Equal(t, " No notifications", ansi.Strip(tray.View(80)))
+
+	require.Nil(t, tray.add(trayNotification{kind: trayWhisper, id: "1", user: "julez", text: "hey"}), "a single notification doesn't cycle")
+	require.Equal(t, " [1/1] Whisper from julez: hey · alt+j jump · alt+x dismiss", ansi.Strip(tray.View(80)))
+
+	require.NotNil(t, tray.add(trayNotification{kind: trayMention, id: "2", channel: "lirik", user: "viewer", text: "@julez hi"}))
+	require.Nil(t, tray.add(trayNotification{kind: trayMention, id: "2", channel: "lirik", user: "viewer", text: "@julez hi"}), "received by another account")
+	require.Len(t, tray.notifications, 2)
+	require.Equal(t, " [2/2] viewer mentioned you in lirik: @julez hi · alt+j jump · alt+x dismiss", ansi.Strip(tray.View(80)))
+
+	// long messages are cut off before the key hints
+	require.Equal(t, " [2/2] viewer mentioned you… · alt+j jump · alt+x dismiss", ansi.Strip(tray.View(57)))
+
+	// the tray cycles while notifications are pending
+	require.NotNil(t, tray.Update(trayCycleMessage{}))
+	require.Contains(t, ansi.Strip(tray.View(80)), "[1/2] Whisper from julez")
+
+	// with reduced motion the shown notification stays
+	deps.UserConfig.Settings.Accessibility.ReducedMotion = true
+	require.Nil(t, tray.Update(trayCycleMessage{}))
+	require.Contains(t, ansi.Strip(tray.View(80)), "[1/2] Whisper from julez")
+	deps.UserConfig.Settings.Accessibility.ReducedMotion = false
+
+	n, ok := tray.take()
+	require.True(t, ok)
+	require.Equal(t, "julez", n.user)
+	require.Nil(t, tray.Update(trayCycleMessage{}), "cycling stops with one notification")
+	require.Contains(t, ansi.Strip(tray.View(80)), "[1/1] viewer mentioned you")
+
+	for range maxTrayNotifications + 5 {
+		tray.add(trayNotification{kind: trayGoLive, channel: "xqc", user: "xQc"})
+	}
+	require.Len(t, tray.notifications, maxTrayNotifications)
+	require.Equal(t, trayGoLive, tray.notifications[0].kind, "the oldest notifications are dropped")
+
+	deps.UserConfig.Settings.NotificationTray.Enabled = false
+	require.Zero(t, tray.height())
+	require.Empty(t, tray.View(80))
+	require.Nil(t, tray.add(trayNotification{kind: trayWhisper, id: "3"}))
+}
+
+func TestRoot_collectTrayNotification(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.NotificationTray.Enabled = true
+	deps.Accounts = []save.Account{{ID: "1", DisplayName: "julez"}}
+
+	r := NewUI(nil, deps)
+	r.hasLoadedSession = true
+	r.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+
+	require.Equal(t, 19, r.splash.height, "the tray takes the last line")
+
+	lines := strings.Split(ansi.Strip(r.View()), "\n")
+	require.Len(t, lines, 20)
+	require.Equal(t, " No notifications", lines[19])
+
+	r.collectTrayNotification("1", &twitchirc.PrivateMessage{ID: "a", ChannelUserName: "lirik", UserID: "2", DisplayName: "viewer", Message: "hello chat"})
+	r.collectTrayNotification("1", &twitchirc.PrivateMessage{ID: "b", ChannelUserName: "lirik", UserID: "1", DisplayName: "julez", Message: "I am julez"})
+	require.Empty(t, r.tray.notifications, "no mention or an own message")
+
+	r.collectTrayNotification("1", &twitchirc.PrivateMessage{ID: "c", ChannelUserName: "lirik", UserID: "2", DisplayName: "viewer", Message: "hi @julez"})
+	r.collectTrayNotification("1", &twitchirc.Whisper{ID: "d", DisplayName: "friend", Message: "psst"})
+	require.Len(t, r.tray.notifications, 2)
+	require.Equal(t, trayNotification{kind: trayMention, id: "c", accountID: "1", channel: "lirik", user: "viewer", text: "hi @julez"}, r.tray.notifications[0])
+
+	r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x"), Alt: true})
+	require.Len(t, r.tray.notifications, 1)
+	require.Equal(t, trayMention, r.tray.notifications[0].kind)
+}
@@ -0,0 +1,154 @@
+package mainui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/obs"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// obsStateChangedMessage is sent when the scene, streaming or recording status of OBS changed, the status bar reads the new state
+type obsStateChangedMessage struct{}
+
+// OBSStateHandler returns the function passed to obs.Client.SetOnChange, it redraws the UI with send
+func OBSStateHandler(send func(tea.Msg)) func(obs.State) {
+	return func(obs.State) {
+		send(obsStateChangedMessage{})
+	}
+}
+
+// obsStatusText describes the state for the /obs command
+func obsStatusText(state obs.State) string {
+	if !state.Connected {
+		return "OBS is not connected"
+	}
+
+	streaming, recording := "not streaming", "not recording"
+	if state.Streaming {
+		streaming = "streaming"
+	}
+
+	if state.Recording {
+		recording = "recording"
+	}
+
+	return fmt.Sprintf("OBS scene: %s, %s, %s", state.Scene, streaming, recording)
+}
+
+// obsStatusView is the OBS part of the status bar
+func obsStatusView(state obs.State, theme save.Theme) string {
+	dimmed := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	if !state.Connected {
+		return dimmed.Render("OBS offline")
+	}
+
+	view := "OBS: " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.StatusColor)).Render(state.Scene)
+
+	live := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ChatErrorColor))
+	if state.Streaming {
+		view += " " + live.Render("LIVE")
+	}
+
+	if state.Recording {
+		view += " " + live.Render("REC")
+	}
+
+	return view
+}
+
+// handleOBSCommand runs /obs outside of the update loop and shows the result as notice
+func (t *broadcastTab) handleOBSCommand(args []string) tea.Cmd {
+	client := t.deps.OBS
+	tabID := t.id
+	accountID := t.AccountID()
+
+	notice := func(text string) tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     tabID,
+			accountID: accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+
+	if client == nil {
+		return func() tea.Msg {
+			return notice("OBS is not enabled, set obs.enabled in the settings")
+		}
+	}
+
+	subcommand := strings.ToLower(args[0])
+	name := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		text, err := runOBSCommand(ctx, client, subcommand, name)
+		if err != nil {
+			return notice("/obs " + subcommand + " failed: " + err.Error())
+		}
+
+		return notice(text)
+	}
+}
+
+// runOBSCommand runs a subcommand of /obs and returns the text shown to the user
+func runOBSCommand(ctx context.Context, client OBSClient, subcommand, name string) (string, error) {
+	switch subcommand {
+	case "", "status":
+		return obsStatusText(client.State()), nil
+	case "scenes":
+		scenes, err := client.Scenes(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return "OBS scenes: " + strings.Join(scenes, ", "), nil
+	case "scene":
+		if name == "" {
+			return "", fmt.Errorf("usage: /obs scene <name>")
+		}
+
+		scenes, err := client.Scenes(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		// scene names are case sensitive in OBS, but typing them exactly is tedious
+		i := slices.IndexFunc(scenes, func(s string) bool { return s == name })
+		if i == -1 {
+			i = slices.IndexFunc(scenes, func(s string) bool { return strings.EqualFold(s, name) })
+		}
+
+		if i == -1 {
+			return "", fmt.Errorf("scene %q not found, available scenes: %s", name, strings.Join(scenes, ", "))
+		}
+
+		if err := client.SetScene(ctx, scenes[i]); err != nil {
+			return "", err
+		}
+
+		return "Switched OBS to scene " + scenes[i], nil
+	case "startstream":
+		return "Started streaming in OBS", client.StartStream(ctx)
+	case "stopstream":
+		return "Stopped streaming in OBS", client.StopStream(ctx)
+	case "startrecord":
+		return "Started recording in OBS", client.StartRecord(ctx)
+	case "stoprecord":
+		return "Stopped recording in OBS", client.StopRecord(ctx)
+	}
+
+	return "", fmt.Errorf("unknown subcommand, expected status, scenes, scene, startstream, stopstream, startrecord or stoprecord")
+}
@@ -0,0 +1,150 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/julez-dev/chatuino/obs"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOBSClient struct {
+	state  obs.State
+	scenes []string
+	err    error
+
+	calls []string
+}
+
+func (f *fakeOBSClient) State() obs.State {
+	return f.state
+}
+
+func (f *fakeOBSClient) Scenes(context.Context) ([]string, error) {
+	return f.scenes, f.err
+}
+
+func (f *fakeOBSClient) SetScene(_ context.Context, name string) error {
+	f.calls = append(f.calls, "scene "+name)
+	return f.err
+}
+
+func (f *fakeOBSClient) StartStream(context.Context) error {
+	f.calls = append(f.calls, "startstream")
+	return f.err
+}
+
+func (f *fakeOBSClient) StopStream(context.Context) error {
+	f.calls = append(f.calls, "stopstream")
+	return f.err
+}
+
+func (f *fakeOBSClient) StartRecord(context.Context) error {
+	f.calls = append(f.calls, "startrecord")
+	return f.err
+}
+
+func (f *fakeOBSClient) StopRecord(context.Context) error {
+	f.calls = append(f.calls, "stoprecord")
+	return f.err
+}
+
+func Test_runOBSCommand(t *testing.T) {
+	t.Parallel()
+
+	scenes := []string{"Starting", "Game", "game 2"}
+
+	tests := []struct {
+		name       string
+		client     *fakeOBSClient
+		subcommand string
+		arg        string
+		want       string
+		wantErr    string
+		wantCalls  []string
+	}{
+		{
+			name:   "status",
+			client: &fakeOBSClient{state: obs.State{Connected: true, Scene: "Game", Streaming: true}},
+			want:   "OBS scene: Game, streaming, not recording",
+		},
+		{
+			name:       "status disconnected",
+			client:     &fakeOBSClient{},
+			subcommand: "status",
+			want:       "OBS is not connected",
+		},
+		{
+			name:       "scenes",
+			client:     &fakeOBSClient{scenes: scenes},
+			subcommand: "scenes",
+			want:       "OBS scenes: Starting, Game, game 2",
+		},
+		{
+			name:       "scene exact match",
+			client:     &fakeOBSClient{scenes: scenes},
+			subcommand: "scene",
+			arg:        "game 2",
+			want:       "Switched OBS to scene game 2",
+			wantCalls:  []string{"scene game 2"},
+		},
+		{
+			name:       "scene ignoring case",
+			client:     &fakeOBSClient{scenes: scenes},
+			subcommand: "scene",
+			arg:        "starting",
+			want:       "Switched OBS to scene Starting",
+			wantCalls:  []string{"scene Starting"},
+		},
+		{
+			name:       "scene not found",
+			client:     &fakeOBSClient{scenes: scenes},
+			subcommand: "scene",
+			arg:        "Ending",
+			wantErr:    `scene "Ending" not found, available scenes: Starting, Game, game 2`,
+		},
+		{
+			name:       "scene without name",
+			client:     &fakeOBSClient{scenes: scenes},
+			subcommand: "scene",
+			wantErr:    "usage: /obs scene <name>",
+		},
+		{
+			name:       "startstream",
+			client:     &fakeOBSClient{},
+			subcommand: "startstream",
+			want:       "Started streaming in OBS",
+			wantCalls:  []string{"startstream"},
+		},
+		{
+			name:       "stoprecord failed",
+			client:     &fakeOBSClient{err: errors.New("not connected to OBS")},
+			subcommand: "stoprecord",
+			wantErr:    "not connected to OBS",
+			wantCalls:  []string{"stoprecord"},
+		},
+		{
+			name:       "unknown subcommand",
+			client:     &fakeOBSClient{},
+			subcommand: "mute",
+			wantErr:    "unknown subcommand, expected status, scenes, scene, startstream, stopstream, startrecord or stoprecord",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := runOBSCommand(context.Background(), tt.client, tt.subcommand, tt.arg)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+
+			require.Equal(t, tt.wantCalls, tt.client.calls)
+		})
+	}
+}
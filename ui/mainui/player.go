@@ -0,0 +1,118 @@
+package mainui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/internal/cmdout"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+// playerOutputLimit is the amount of bytes kept from the player output to show in case of an error
+const playerOutputLimit = 4096
+
+type streamPlayerExitedMessage struct {
+	tabID  string // empty for players started from a go live notification
+	player *exec.Cmd
+	err    error
+}
+
+// buildPlayerCommand creates the command to watch a channel from the configured template.
+// The template is split by whitespace and {channel} is replaced in every argument, so no shell is involved.
+func buildPlayerCommand(template, channel string) (*exec.Cmd, error) {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return nil, errors.New("no player command configured")
+	}
+
+	for i, f := range fields {
+		fields[i] = strings.ReplaceAll(f, "{channel}", channel)
+	}
+
+	return exec.Command(fields[0], fields[1:]...), nil
+}
+
+// startPlayer starts the player and returns the command waiting for it to exit. The player is started right away,
+// so it can be stopped with stopPlayer once the tab is closed or Chatuino quits.
+func startPlayer(player *exec.Cmd, tabID, channel string) (tea.Cmd, error) {
+	output := cmdout.NewTailWriter(playerOutputLimit)
+	player.Stdout = output
+	player.Stderr = output
+
+	if err := player.Start(); err != nil {
+		return nil, err
+	}
+
+	return func() tea.Msg {
+		err := player.Wait()
+		if err != nil {
+			if line := output.LastLine(); line != "" {
+				err = fmt.Errorf("%w: %s", err, line)
+			}
+
+			log.Logger.Err(err).Str("channel", channel).Strs("args", player.Args).Msg("stream player exited with error")
+		}
+
+		return streamPlayerExitedMessage{tabID: tabID, player: player, err: err}
+	}, nil
+}
+
+// stopPlayer asks a started player to quit, so it can close a player it started itself, like streamlink does with mpv.
+// It's killed where that's not supported.
+func stopPlayer(player *exec.Cmd) {
+	if err := player.Process.Signal(syscall.SIGTERM); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		_ = player.Process.Kill()
+	}
+}
+
+func (t *broadcastTab) handleWatchStream() tea.Cmd {
+	notice := func(msg string) tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     t.id,
+			accountID: t.AccountID(),
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       msg,
+			},
+		}
+	}
+
+	if t.player != nil {
+		return func() tea.Msg {
+			return notice(fmt.Sprintf("Player for %s is already running", t.channelLogin))
+		}
+	}
+
+	player, err := buildPlayerCommand(t.deps.UserConfig.Settings.Player.Command, t.channelLogin)
+	if err != nil {
+		return func() tea.Msg {
+			return notice(fmt.Sprintf("Failed to start player: %s", err))
+		}
+	}
+
+	wait, err := startPlayer(player, t.id, t.channelLogin)
+	if err != nil {
+		log.Logger.Err(err).Str("channel", t.channelLogin).Strs("args", player.Args).Msg("failed to start stream player")
+
+		return func() tea.Msg {
+			return notice(fmt.Sprintf("Failed to start player: %s", err))
+		}
+	}
+
+	t.player = player
+	channel := t.channelLogin
+
+	return tea.Batch(
+		func() tea.Msg {
+			return notice(fmt.Sprintf("Starting player for %s: %s", channel, strings.Join(player.Args, " ")))
+		},
+		wait,
+	)
+}
@@ -0,0 +1,40 @@
+package mainui
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_buildPlayerCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd, err := buildPlayerCommand("streamlink  --player mpv twitch.tv/{channel} best", "lirik")
+	require.NoError(t, err)
+	require.Equal(t, []string{"streamlink", "--player", "mpv", "twitch.tv/lirik", "best"}, cmd.Args)
+
+	_, err = buildPlayerCommand("   ", "lirik")
+	require.Error(t, err)
+}
+
+func Test_stopPlayer(t *testing.T) {
+	t.Parallel()
+
+	player, err := buildPlayerCommand("sleep 30", "lirik")
+	require.NoError(t, err)
+
+	wait, err := startPlayer(player, "tab", "lirik")
+	if errors.Is(err, exec.ErrNotFound) {
+		t.Skip("sleep is not installed")
+	}
+	require.NoError(t, err)
+
+	stopPlayer(player)
+
+	msg := wait().(streamPlayerExitedMessage)
+	require.Equal(t, "tab", msg.tabID)
+	require.Same(t, player, msg.player)
+	require.Error(t, msg.err, "the player was stopped by a signal")
+}
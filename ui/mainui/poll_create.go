@@ -0,0 +1,311 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// limits of the Helix Create Poll and Create Prediction endpoints
+const (
+	maxPollTitleLength       = 60
+	maxPredictionTitleLength = 45
+	maxChoiceLength          = 25
+	maxPollChoices           = 5
+	maxPredictionOutcomes    = 10
+	minPollDuration          = time.Second * 15
+	minPredictionWindow      = time.Second * 30
+	maxPollDuration          = time.Minute * 30
+
+	defaultPollDuration = time.Minute * 2
+)
+
+type pollCreationClient interface {
+	CreatePoll(ctx context.Context, req twitchapi.CreatePollRequest) (twitchapi.Poll, error)
+	CreatePrediction(ctx context.Context, req twitchapi.CreatePredictionRequest) (twitchapi.Prediction, error)
+}
+
+// pollDraft is a poll or prediction parsed from /poll or /prediction, it is created once confirmed
+type pollDraft struct {
+	prediction bool
+	title      string
+	choices    []string
+	duration   time.Duration
+}
+
+func (d pollDraft) kind() string {
+	if d.prediction {
+		return "prediction"
+	}
+
+	return "poll"
+}
+
+// openPollConfirmMessage asks to confirm the poll or prediction before it is created
+type openPollConfirmMessage struct {
+	tabID     string
+	accountID string
+	channelID string
+	channel   string
+	draft     pollDraft
+}
+
+// parsePollDraft parses the arguments of /poll and /prediction: a title followed by the choices and an optional duration.
+// Arguments containing spaces are quoted, the last argument is used as duration if it is a duration with a unit like 90s or 5m.
+func parsePollDraft(argStr string, prediction bool) (pollDraft, error) {
+	draft := pollDraft{prediction: prediction, duration: defaultPollDuration}
+
+	args, err := splitQuotedArgs(argStr)
+	if err != nil {
+		return pollDraft{}, err
+	}
+
+	if len(args) > 1 {
+		if d, err := time.ParseDuration(args[len(args)-1]); err == nil {
+			draft.duration = d
+			args = args[:len(args)-1]
+		}
+	}
+
+	maxTitle, maxChoices, minDuration := maxPollTitleLength, maxPollChoices, minPollDuration
+	if prediction {
+		maxTitle, maxChoices, minDuration = maxPredictionTitleLength, maxPredictionOutcomes, minPredictionWindow
+	}
+
+	if len(args) < 3 || len(args) > maxChoices+1 {
+		return pollDraft{}, fmt.Errorf("a %s needs a title and 2 to %d choices", draft.kind(), maxChoices)
+	}
+
+	draft.title, draft.choices = args[0], args[1:]
+
+	if utf8.RuneCountInString(draft.title) > maxTitle {
+		return pollDraft{}, fmt.Errorf("the title of a %s can be at most %d characters long", draft.kind(), maxTitle)
+	}
+
+	for _, c := range draft.choices {
+		if utf8.RuneCountInString(c) > maxChoiceLength {
+			return pollDraft{}, fmt.Errorf("choice %q is longer than %d characters", c, maxChoiceLength)
+		}
+	}
+
+	if draft.duration < minDuration || draft.duration > maxPollDuration || draft.duration%time.Second != 0 {
+		return pollDraft{}, fmt.Errorf("the duration of a %s must be between %s and %s in whole seconds", draft.kind(), formatChatSettingDuration(minDuration), formatChatSettingDuration(maxPollDuration))
+	}
+
+	return draft, nil
+}
+
+// splitQuotedArgs splits s by whitespace, text in double quotes is kept together
+func splitQuotedArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quoted  bool
+	)
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("missing closing quote")
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// handleCreatePollCommand validates /poll or /prediction and asks to confirm it, Twitch only allows broadcasters to start them
+func (t *broadcastTab) handleCreatePollCommand(argStr string, prediction bool) tea.Cmd {
+	notice := t.localNotice()
+
+	_, ok := t.deps.APIUserClients[t.account.ID].(pollCreationClient)
+	if !ok || t.channelID == "" || t.account.ID != t.channelID {
+		return func() tea.Msg {
+			return notice("Twitch only allows broadcasters to start polls and predictions")
+		}
+	}
+
+	draft, err := parsePollDraft(argStr, prediction)
+	if err != nil {
+		kind, usage := "poll", `/poll "<title>" <choice> <choice> [duration]`
+		if prediction {
+			kind = "prediction"
+			usage = `/prediction "<title>" <outcome> <outcome> [duration]`
+		}
+
+		return func() tea.Msg {
+			return notice(fmt.Sprintf("Invalid %s, %s. Usage: %s", kind, err, usage))
+		}
+	}
+
+	msg := openPollConfirmMessage{
+		tabID:     t.id,
+		accountID: t.account.ID,
+		channelID: t.channelID,
+		channel:   t.channelLogin,
+		draft:     draft,
+	}
+
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// pollConfirm shows a poll or prediction before it is created
+type pollConfirm struct {
+	deps   *DependencyContainer
+	client pollCreationClient
+	width  int
+
+	msg openPollConfirmMessage
+}
+
+func newPollConfirm(width int, msg openPollConfirmMessage, client pollCreationClient, deps *DependencyContainer) *pollConfirm {
+	c := &pollConfirm{
+		deps:   deps,
+		client: client,
+		msg:    msg,
+	}
+	c.handleResize(width)
+
+	return c
+}
+
+func (c *pollConfirm) handleResize(width int) {
+	// the confirmation is shown as a modal, leave some space to the terminal border
+	c.width = max(min(width-4, 60), 20)
+}
+
+// create starts the poll or prediction, the result is shown as a notice in the tab
+func (c *pollConfirm) create() tea.Cmd {
+	client, msg := c.client, c.msg
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		draft := msg.draft
+		seconds := int(draft.duration / time.Second)
+
+		var err error
+		if draft.prediction {
+			outcomes := make([]twitchapi.PredictionOutcome, 0, len(draft.choices))
+			for _, o := range draft.choices {
+				outcomes = append(outcomes, twitchapi.PredictionOutcome{Title: o})
+			}
+
+			_, err = client.CreatePrediction(ctx, twitchapi.CreatePredictionRequest{
+				BroadcasterID:    msg.channelID,
+				Title:            draft.title,
+				Outcomes:         outcomes,
+				PredictionWindow: seconds,
+			})
+		} else {
+			choices := make([]twitchapi.PollChoice, 0, len(draft.choices))
+			for _, c := range draft.choices {
+				choices = append(choices, twitchapi.PollChoice{Title: c})
+			}
+
+			_, err = client.CreatePoll(ctx, twitchapi.CreatePollRequest{
+				BroadcasterID: msg.channelID,
+				Title:         draft.title,
+				Choices:       choices,
+				Duration:      seconds,
+			})
+		}
+
+		text := fmt.Sprintf("Started %s %q, voting closes in %s", draft.kind(), draft.title, humanizeDuration(draft.duration))
+		if err != nil {
+			text = pollErrorText(draft.kind(), err)
+		}
+
+		return requestLocalMessageHandleMessage{
+			tabID:     msg.tabID,
+			accountID: msg.accountID,
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       text,
+			},
+		}
+	}
+}
+
+func (c *pollConfirm) View() string {
+	theme := c.deps.UserConfig.Theme
+	innerWidth := c.width - 4
+	draft := c.msg.draft
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ActiveLabelColor))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render(fmt.Sprintf("Start %s in %s?", draft.kind(), c.msg.channel)) + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(fmt.Sprintf("%s start · %s cancel",
+		c.deps.Keymap.Confirm.Help().Key,
+		c.deps.Keymap.Escape.Help().Key,
+	)) + "\n\n")
+
+	_, _ = b.WriteString(lipgloss.NewStyle().Width(innerWidth).Bold(true).Render(draft.title) + "\n\n")
+
+	for i, choice := range draft.choices {
+		_, _ = b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render(fmt.Sprintf("%2d.", i+1)), choice))
+	}
+
+	label := "Duration"
+	if draft.prediction {
+		label = "Prediction window"
+	}
+
+	_, _ = b.WriteString("\n" + dimmedStyle.Render(fmt.Sprintf("%s: %s", label, humanizeDuration(draft.duration))))
+
+	return lipgloss.NewStyle().
+		Width(c.width).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
+
+// pollErrorText explains the common errors of the poll and prediction endpoints
+func pollErrorText(kind string, err error) string {
+	var apiErr twitchapi.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Sprintf("Not allowed to start a %s, the channel needs to be affiliate or partner and the account needs the channel:manage:polls and channel:manage:predictions scopes, add the account again to grant them: %s", kind, apiErr.Message)
+		case http.StatusBadRequest, http.StatusTooManyRequests:
+			return fmt.Sprintf("Twitch rejected the %s, is another one still running? %s", kind, apiErr.Message)
+		}
+	}
+
+	return fmt.Sprintf("Failed to start %s: %s", kind, err)
+}
@@ -0,0 +1,123 @@
+package mainui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePollClient struct {
+	poll       twitchapi.CreatePollRequest
+	prediction twitchapi.CreatePredictionRequest
+	err        error
+}
+
+func (f *fakePollClient) CreatePoll(_ context.Context, req twitchapi.CreatePollRequest) (twitchapi.Poll, error) {
+	f.poll = req
+	return twitchapi.Poll{}, f.err
+}
+
+func (f *fakePollClient) CreatePrediction(_ context.Context, req twitchapi.CreatePredictionRequest) (twitchapi.Prediction, error) {
+	f.prediction = req
+	return twitchapi.Prediction{}, f.err
+}
+
+func Test_parsePollDraft(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       string
+		prediction bool
+		want       pollDraft
+		wantErr    string
+	}{
+		{
+			name: "default duration",
+			args: `"Best map?" Dust2 Mirage`,
+			want: pollDraft{title: "Best map?", choices: []string{"Dust2", "Mirage"}, duration: defaultPollDuration},
+		},
+		{
+			name: "quoted choices and duration",
+			args: ` "Who wins?"  "Team Liquid" NaVi   90s `,
+			want: pollDraft{title: "Who wins?", choices: []string{"Team Liquid", "NaVi"}, duration: time.Second * 90},
+		},
+		{
+			name: "numbers are choices",
+			args: `Rating 1 2 3`,
+			want: pollDraft{title: "Rating", choices: []string{"1", "2", "3"}, duration: defaultPollDuration},
+		},
+		{
+			name:       "prediction",
+			args:       `"Win this round?" yes no 5m`,
+			prediction: true,
+			want:       pollDraft{prediction: true, title: "Win this round?", choices: []string{"yes", "no"}, duration: time.Minute * 5},
+		},
+		{name: "one choice", args: `"Best map?" Dust2 5m`, wantErr: "a poll needs a title and 2 to 5 choices"},
+		{name: "too many choices", args: `Pick a b c d e f`, wantErr: "a poll needs a title and 2 to 5 choices"},
+		{name: "unclosed quote", args: `"Best map? Dust2 Mirage`, wantErr: "missing closing quote"},
+		{name: "long choice", args: `Pick a "this choice is way too long to show"`, wantErr: `choice "this choice is way too long to show" is longer than 25 characters`},
+		{name: "short poll", args: `Pick a b 10s`, wantErr: "the duration of a poll must be between 15s and 30m in whole seconds"},
+		{name: "short prediction", args: `Pick a b 20s`, prediction: true, wantErr: "the duration of a prediction must be between 30s and 30m in whole seconds"},
+		{
+			name:       "long prediction title",
+			args:       `"This title is too long for a prediction on Twitch" a b`,
+			prediction: true,
+			wantErr:    "the title of a prediction can be at most 45 characters long",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parsePollDraft(tt.args, tt.prediction)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_pollConfirm(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	client := &fakePollClient{}
+	draft := pollDraft{title: "Who wins?", choices: []string{"Team Liquid", "NaVi"}, duration: time.Second * 90}
+	c := newPollConfirm(80, openPollConfirmMessage{tabID: "tab", channelID: "1", channel: "julezdev", draft: draft}, client, deps)
+
+	view := ansi.Strip(c.View())
+	require.Contains(t, view, "Start poll in julezdev?")
+	require.Contains(t, view, " 2. NaVi")
+	require.Contains(t, view, "Duration: 1 minute 30 seconds")
+
+	msg := c.create()().(requestLocalMessageHandleMessage)
+	require.Equal(t, "tab", msg.tabID)
+	require.Equal(t, `Started poll "Who wins?", voting closes in 1 minute 30 seconds`, msg.message.(*twitchirc.Notice).Message)
+	require.Equal(t, twitchapi.CreatePollRequest{
+		BroadcasterID: "1",
+		Title:         "Who wins?",
+		Choices:       []twitchapi.PollChoice{{Title: "Team Liquid"}, {Title: "NaVi"}},
+		Duration:      90,
+	}, client.poll)
+
+	client.err = twitchapi.APIError{Status: 400, Message: "poll already active"}
+	draft.prediction = true
+	c = newPollConfirm(80, openPollConfirmMessage{channelID: "1", draft: draft}, client, deps)
+
+	msg = c.create()().(requestLocalMessageHandleMessage)
+	require.Equal(t, "Twitch rejected the prediction, is another one still running? poll already active", msg.message.(*twitchirc.Notice).Message)
+	require.Equal(t, 90, client.prediction.PredictionWindow)
+	require.Len(t, client.prediction.Outcomes, 2)
+}
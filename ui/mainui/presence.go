@@ -0,0 +1,159 @@
+package mainui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const (
+	// presenceSettle is how long after joining a channel no join and part lines are shown, Twitch sends the chatters already present as joins
+	presenceSettle = time.Second * 30
+
+	// presenceListLimit is the number of chatters listed by /presence
+	presenceListLimit = 100
+)
+
+// presenceMessage is a join, part or list of chatters of a channel, forwarded to the tabs of the channel
+type presenceMessage struct {
+	accountID string
+	channel   string
+	message   twitchirc.IRCer // *twitchirc.Membership or *twitchirc.Names
+}
+
+// chatPresence is the list of chatters in a channel, known from the joins and parts sent by Twitch
+type chatPresence struct {
+	since    time.Time
+	chatters map[string]bool // keyed by user login
+}
+
+func newChatPresence(since time.Time) *chatPresence {
+	return &chatPresence{
+		since:    since,
+		chatters: map[string]bool{},
+	}
+}
+
+// apply adds or removes the chatters and reports whether a join or part line should be shown
+func (p *chatPresence) apply(msg twitchirc.IRCer, now time.Time) bool {
+	switch msg := msg.(type) {
+	case *twitchirc.Names:
+		for _, login := range msg.LoginNames {
+			p.chatters[strings.ToLower(login)] = true
+		}
+	case *twitchirc.Membership:
+		login := strings.ToLower(msg.LoginName)
+		if p.chatters[login] == msg.Joined {
+			return false
+		}
+
+		if msg.Joined {
+			p.chatters[login] = true
+		} else {
+			delete(p.chatters, login)
+		}
+
+		return now.Sub(p.since) >= presenceSettle
+	}
+
+	return false
+}
+
+// list returns the logins of the present chatters sorted by name
+func (p *chatPresence) list() []string {
+	return slices.Sorted(maps.Keys(p.chatters))
+}
+
+// forwardPresence passes joins and parts to the tabs of the channel, they are not shown as chat messages
+func (r *Root) forwardPresence(accountID string, msg twitchirc.IRCer) tea.Cmd {
+	if !r.dependencies.UserConfig.Settings.Presence.Enabled {
+		return nil
+	}
+
+	var channel string
+	switch msg := msg.(type) {
+	case *twitchirc.Membership:
+		channel = msg.ChannelUserName
+	case *twitchirc.Names:
+		channel = msg.ChannelUserName
+	}
+
+	presence := presenceMessage{accountID: accountID, channel: channel, message: msg}
+
+	cmds := make([]tea.Cmd, 0, len(r.tabs))
+	for i, t := range r.tabs {
+		if t.Kind() != broadcastTabKind || t.AccountID() != accountID || !strings.EqualFold(t.Channel(), channel) {
+			continue
+		}
+
+		var cmd tea.Cmd
+		r.tabs[i], cmd = r.tabs[i].Update(presence)
+		cmds = append(cmds, cmd)
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// presenceTracked reports whether the chatters of the channel are tracked, only channels with few viewers are tracked
+func (t *broadcastTab) presenceTracked() bool {
+	settings := t.deps.UserConfig.Settings.Presence
+	return settings.Enabled && t.channelDataLoaded && t.streamInfo.viewer <= settings.MaxViewers
+}
+
+// handlePresence updates the chatters of the channel and shows a line when a chatter joined or left
+func (t *broadcastTab) handlePresence(msg presenceMessage) tea.Cmd {
+	if !t.presenceTracked() {
+		t.presence = nil
+		return nil
+	}
+
+	if t.presence == nil {
+		t.presence = newChatPresence(time.Now())
+	}
+
+	m, ok := msg.message.(*twitchirc.Membership)
+	if !t.presence.apply(msg.message, time.Now()) || !ok || !t.deps.UserConfig.Settings.Presence.ShowLines {
+		return nil
+	}
+
+	text := m.LoginName + " joined"
+	if !m.Joined {
+		text = m.LoginName + " left"
+	}
+
+	notice := t.localNotice()
+	return func() tea.Msg {
+		return notice(text)
+	}
+}
+
+// handlePresenceCommand runs /presence, which lists the chatters present in the channel
+func (t *broadcastTab) handlePresenceCommand() tea.Cmd {
+	var text string
+
+	switch {
+	case !t.deps.UserConfig.Settings.Presence.Enabled:
+		text = "Presence tracking is disabled, enable presence.enabled in the settings"
+	case !t.presenceTracked():
+		text = fmt.Sprintf("Presence is only tracked in channels with at most %d viewers", t.deps.UserConfig.Settings.Presence.MaxViewers)
+	case t.presence == nil || len(t.presence.chatters) == 0:
+		text = "No chatters known yet, Twitch sends joins and parts every few seconds"
+	default:
+		chatters := t.presence.list()
+		text = fmt.Sprintf("%d chatters present: %s", len(chatters), strings.Join(chatters[:min(len(chatters), presenceListLimit)], ", "))
+
+		if hidden := len(chatters) - presenceListLimit; hidden > 0 {
+			text += fmt.Sprintf(" and %d more", hidden)
+		}
+	}
+
+	notice := t.localNotice()
+	return func() tea.Msg {
+		return notice(text)
+	}
+}
@@ -0,0 +1,30 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatPresence(t *testing.T) {
+	t.Parallel()
+
+	joined := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	p := newChatPresence(joined)
+
+	require.False(t, p.apply(&twitchirc.Names{ChannelUserName: "dallas", LoginNames: []string{"Ronni", "dallas"}}, joined))
+
+	// chatters already present are sent as joins right after joining
+	require.False(t, p.apply(&twitchirc.Membership{Joined: true, ChannelUserName: "dallas", LoginName: "xqc"}, joined.Add(time.Second)))
+	require.Equal(t, []string{"dallas", "ronni", "xqc"}, p.list())
+
+	settled := joined.Add(presenceSettle)
+	require.False(t, p.apply(&twitchirc.Membership{Joined: true, ChannelUserName: "dallas", LoginName: "xqc"}, settled), "already present")
+	require.True(t, p.apply(&twitchirc.Membership{ChannelUserName: "dallas", LoginName: "ronni"}, settled))
+	require.False(t, p.apply(&twitchirc.Membership{ChannelUserName: "dallas", LoginName: "ronni"}, settled), "already left")
+	require.True(t, p.apply(&twitchirc.Membership{Joined: true, ChannelUserName: "dallas", LoginName: "forsen"}, settled))
+
+	require.Equal(t, []string{"dallas", "forsen", "xqc"}, p.list())
+}
@@ -0,0 +1,440 @@
+package mainui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/ui/component"
+)
+
+// maxProviderBatch is the number of messages of a provider chat applied in one update
+const maxProviderBatch = 100
+
+type providerJoinedMessage struct {
+	tabID string
+	chat  chatprovider.Chat
+	err   error
+}
+
+type providerMessagesMessage struct {
+	tabID    string
+	messages []twitchirc.IRCer
+	closed   bool // the chat ended or was left, no more messages are received
+}
+
+type providerSendResultMessage struct {
+	tabID string
+	err   error
+}
+
+// providerTab shows the live chat of another platform than Twitch, like YouTube or Kick.
+// Messages are not passed through emote, badge or link replacement, they are shown as sent.
+type providerTab struct {
+	id       string
+	kind     tabKind
+	deps     *DependencyContainer
+	provider chatprovider.Provider
+	channel  string
+
+	focused bool
+
+	state         broadcastTabState // only inChatWindow and insertMode are used
+	width, height int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	joined bool              // the join finished, successful or not
+	chat   chatprovider.Chat // nil if the join failed
+	err    error
+
+	spinner      spinner.Model
+	chatWindow   *chatWindow
+	messageInput *component.SuggestionTextInput
+}
+
+func newProviderTab(id string, width, height int, kind tabKind, provider chatprovider.Provider, channel string, deps *DependencyContainer) *providerTab {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	input := component.NewSuggestionTextInput(map[string]func(...string) string{}, nil)
+	input.KeyMap = inputKeyMap(deps.Keymap)
+	input.IncludeCommandSuggestions = false
+	input.DisableHistory = true
+	input.InputModel.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.InputPromptColor))
+
+	return &providerTab{
+		id:           id,
+		kind:         kind,
+		deps:         deps,
+		provider:     provider,
+		channel:      channel,
+		state:        inChatWindow,
+		width:        width,
+		height:       height,
+		ctx:          ctx,
+		cancel:       cancel,
+		spinner:      spinner.New(spinner.WithSpinner(customEllipsisSpinner)),
+		chatWindow:   newChatWindow(width, height, deps),
+		messageInput: input,
+	}
+}
+
+func (p *providerTab) Init() tea.Cmd {
+	tabID, ctx, provider, channel := p.id, p.ctx, p.provider, p.channel
+
+	return tea.Batch(p.spinner.Tick, func() tea.Msg {
+		chat, err := provider.Join(ctx, channel)
+		return providerJoinedMessage{tabID: tabID, chat: chat, err: err}
+	})
+}
+
+func (p *providerTab) InitWithUserData(twitchapi.UserData) tea.Cmd {
+	return p.Init()
+}
+
+// waitProviderMessages receives the next messages of the chat, messages already waiting are received together
+func waitProviderMessages(tabID string, chat chatprovider.Chat) tea.Cmd {
+	return func() tea.Msg {
+		first, ok := <-chat.Messages()
+		if !ok {
+			return providerMessagesMessage{tabID: tabID, closed: true}
+		}
+
+		messages := []twitchirc.IRCer{first}
+		for len(messages) < maxProviderBatch {
+			select {
+			case msg, ok := <-chat.Messages():
+				if !ok {
+					return providerMessagesMessage{tabID: tabID, messages: messages, closed: true}
+				}
+
+				messages = append(messages, msg)
+			default:
+				return providerMessagesMessage{tabID: tabID, messages: messages}
+			}
+		}
+
+		return providerMessagesMessage{tabID: tabID, messages: messages}
+	}
+}
+
+func (p *providerTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case providerJoinedMessage:
+		if msg.tabID != p.id {
+			return p, nil
+		}
+
+		p.joined = true
+
+		if msg.err != nil {
+			p.err = fmt.Errorf("could not join %s chat %s: %w", p.provider.Name(), p.channel, msg.err)
+			return p, nil
+		}
+
+		p.chat = msg.chat
+		p.chatWindow.Focus()
+		p.HandleResize()
+
+		if p.chat.ReadOnly() {
+			p.notice(fmt.Sprintf("Joined %s chat %s (read only)", p.provider.Name(), p.channel))
+		} else {
+			p.notice(fmt.Sprintf("Joined %s chat %s", p.provider.Name(), p.channel))
+		}
+
+		return p, waitProviderMessages(p.id, p.chat)
+	case providerMessagesMessage:
+		if msg.tabID != p.id {
+			return p, nil
+		}
+
+		for _, message := range msg.messages {
+			p.chatWindow.handleMessage(providerChatEvent(p.id, p.channel, message))
+		}
+
+		if !msg.closed {
+			return p, waitProviderMessages(p.id, p.chat)
+		}
+
+		// the chat was left because the tab was closed
+		if p.ctx.Err() != nil {
+			return p, nil
+		}
+
+		if err := p.chat.Err(); err != nil {
+			p.notice(fmt.Sprintf("Left %s chat: %s", p.provider.Name(), err))
+		} else {
+			p.notice(fmt.Sprintf("Left %s chat", p.provider.Name()))
+		}
+
+		return p, nil
+	case providerSendResultMessage:
+		if msg.tabID != p.id || msg.err == nil {
+			return p, nil
+		}
+
+		p.notice(fmt.Sprintf("Failed to send message: %s", msg.err))
+		return p, nil
+	}
+
+	if !p.joined || p.chat == nil {
+		if p.err == nil {
+			p.spinner, cmd = p.spinner.Update(msg)
+			return p, cmd
+		}
+
+		return p, nil
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if !p.focused {
+			return p, nil
+		}
+
+		if cmd, handled := p.handleKey(keyMsg); handled {
+			return p, cmd
+		}
+
+		if p.state == insertMode {
+			p.messageInput, cmd = p.messageInput.Update(msg)
+			return p, cmd
+		}
+	}
+
+	p.chatWindow, cmd = p.chatWindow.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return p, tea.Batch(cmds...)
+}
+
+// handleKey handles the keys of the tab, keys which are not handled are passed to the message input or chat window
+func (p *providerTab) handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, p.deps.Keymap.InsertMode) && p.state == inChatWindow && p.chatWindow.state == viewChatWindowState && !p.chat.ReadOnly():
+		p.state = insertMode
+		p.chatWindow.Blur()
+		p.messageInput.Focus()
+		return p.messageInput.InputModel.Cursor.BlinkCmd(), true
+	case key.Matches(msg, p.deps.Keymap.Escape) && p.state == insertMode:
+		p.state = inChatWindow
+		p.messageInput.Blur()
+		p.chatWindow.Focus()
+		return nil, true
+	case key.Matches(msg, p.deps.Keymap.Confirm) && p.state == insertMode:
+		text := strings.TrimSpace(p.messageInput.Value())
+		if text == "" {
+			return nil, true
+		}
+
+		p.messageInput.SetValue("")
+		p.chatWindow.moveToBottom()
+
+		tabID, ctx, chat := p.id, p.ctx, p.chat
+		return func() tea.Msg {
+			ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+			defer cancel()
+
+			return providerSendResultMessage{tabID: tabID, err: chat.Send(ctx, text)}
+		}, true
+	}
+
+	return nil, false
+}
+
+// isProviderTabKind reports whether tabs of the kind show chats of another platform than Twitch
+func isProviderTabKind(kind tabKind) bool {
+	return kind == youTubeTabKind || kind == kickTabKind
+}
+
+// providerChatEvent wraps a message of a provider chat, badges are shown by their name
+func providerChatEvent(tabID, channel string, message twitchirc.IRCer) chatEventMessage {
+	event := chatEventMessage{
+		isFakeEvent: true,
+		channel:     channel,
+		tabID:       tabID,
+		message:     message,
+		displayModifier: messageContentModifier{
+			wordReplacements: wordReplacement{},
+			badgeReplacement: wordReplacement{},
+		},
+	}
+
+	if privMsg, ok := message.(*twitchirc.PrivateMessage); ok {
+		for _, badge := range privMsg.Badges {
+			event.displayModifier.badgeReplacement[badge.Name] = badge.Name
+		}
+	}
+
+	return event
+}
+
+func (p *providerTab) notice(text string) {
+	p.chatWindow.handleMessage(chatEventMessage{
+		isFakeEvent: true,
+		tabID:       p.id,
+		message: &twitchirc.Notice{
+			FakeTimestamp: time.Now(),
+			MsgID:         twitchirc.MsgID(uuid.NewString()),
+			Message:       text,
+		},
+	})
+}
+
+// close leaves the chat
+func (p *providerTab) close() {
+	p.cancel()
+}
+
+func (p *providerTab) View() string {
+	style := lipgloss.NewStyle().
+		Width(p.width).
+		Height(p.height).
+		MaxWidth(p.width).
+		MaxHeight(p.height).
+		AlignHorizontal(lipgloss.Center).
+		AlignVertical(lipgloss.Center)
+
+	if p.err != nil {
+		return style.Render(p.err.Error())
+	}
+
+	if p.chat == nil {
+		return style.Render(spinnerView(p.spinner, p.deps.UserConfig.Settings) + " Joining " + p.provider.Name() + " chat")
+	}
+
+	b := strings.Builder{}
+	b.WriteString(p.titleView())
+	b.WriteString("\n")
+	b.WriteString(p.chatWindow.View())
+
+	if input := p.inputView(); input != "" {
+		b.WriteString("\n")
+		b.WriteString(input)
+	}
+
+	return b.String()
+}
+
+func (p *providerTab) titleView() string {
+	lines := strings.Split(wrapText(p.chat.Title(), p.width-10), "\n")
+	for i, line := range lines {
+		lines[i] = centerTextGraphemeAware(p.width, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// inputView renders the message input in a border, it is hidden for read only chats
+func (p *providerTab) inputView() string {
+	if p.chat == nil || p.chat.ReadOnly() {
+		return ""
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color(p.deps.UserConfig.Theme.BorderColor)).
+		Width(max(p.width-2, 0)).
+		Render(p.messageInput.View())
+}
+
+func (p *providerTab) ViewWithoutStatusBar() string {
+	return p.View() // provider tabs have no status bar
+}
+
+func (p *providerTab) StatusBarView() string {
+	return ""
+}
+
+func (p *providerTab) Focus() {
+	p.focused = true
+
+	if p.state == insertMode {
+		p.messageInput.Focus()
+		return
+	}
+
+	p.chatWindow.Focus()
+}
+
+func (p *providerTab) Blur() {
+	p.focused = false
+	p.chatWindow.Blur()
+	p.messageInput.Blur()
+}
+
+func (p *providerTab) AccountID() string {
+	return ""
+}
+
+func (p *providerTab) Channel() string {
+	return p.channel
+}
+
+func (p *providerTab) State() broadcastTabState {
+	return p.state
+}
+
+func (p *providerTab) IsTyping() bool {
+	return p.state == insertMode || p.chatWindow.state != viewChatWindowState
+}
+
+func (p *providerTab) IsDataLoaded() bool {
+	return p.joined
+}
+
+func (p *providerTab) ID() string {
+	return p.id
+}
+
+func (p *providerTab) Focused() bool {
+	return p.focused
+}
+
+func (p *providerTab) ChannelID() string {
+	return ""
+}
+
+func (p *providerTab) HandleResize() {
+	p.messageInput.SetWidth(max(p.width-2, 0))
+
+	height := p.height
+	if p.chat != nil {
+		height -= lipgloss.Height(p.titleView())
+
+		if input := p.inputView(); input != "" {
+			height -= lipgloss.Height(input)
+		}
+	}
+
+	p.chatWindow.width = p.width
+	p.chatWindow.height = max(height, 0)
+	p.chatWindow.recalculateLines()
+}
+
+func (p *providerTab) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+func (p *providerTab) SetFullWidth(_ int) {
+	// No-op for provider tabs (no status bar)
+}
+
+func (p *providerTab) Kind() tabKind {
+	return p.kind
+}
@@ -0,0 +1,91 @@
+package mainui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	chat *fakeChat
+}
+
+func (f *fakeProvider) Name() string { return "Fake" }
+
+func (f *fakeProvider) Join(context.Context, string) (chatprovider.Chat, error) {
+	return f.chat, nil
+}
+
+type fakeChat struct {
+	messages chan twitchirc.IRCer
+	sent     []string
+	readOnly bool
+}
+
+func (f *fakeChat) Title() string                    { return "Streamer: Speedrun" }
+func (f *fakeChat) Messages() <-chan twitchirc.IRCer { return f.messages }
+func (f *fakeChat) Err() error                       { return nil }
+func (f *fakeChat) ReadOnly() bool                   { return f.readOnly }
+
+func (f *fakeChat) Send(_ context.Context, text string) error {
+	if f.readOnly {
+		return chatprovider.ErrReadOnly
+	}
+
+	f.sent = append(f.sent, text)
+	return nil
+}
+
+func TestProviderTab(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	chat := &fakeChat{messages: make(chan twitchirc.IRCer, 10)}
+	tab := newProviderTab("tab", 80, 20, youTubeTabKind, &fakeProvider{chat: chat}, "@streamer", deps)
+	tab.Focus()
+
+	// the join is the second command of the batch, the first starts the spinner
+	joined := tab.Init()().(tea.BatchMsg)[1]().(providerJoinedMessage)
+	_, wait := tab.Update(joined)
+	require.True(t, tab.IsDataLoaded())
+	require.Contains(t, tab.View(), "Streamer: Speedrun")
+
+	chat.messages <- &twitchirc.PrivateMessage{ID: "1", DisplayName: "@viewer", LoginName: "@viewer", Message: "hello", Badges: []twitchirc.Badge{{Name: "moderator"}}}
+	chat.messages <- &twitchirc.ClearMessage{TargetMsgID: "1"}
+
+	batch := wait().(providerMessagesMessage)
+	require.Len(t, batch.messages, 2)
+
+	_, cmd := tab.Update(batch)
+	require.NotNil(t, cmd, "messages are received until the chat is closed")
+
+	// join notice, the message and the deletion which marks the message
+	require.Len(t, tab.chatWindow.entries, 3)
+	require.True(t, tab.chatWindow.entries[1].IsDeleted)
+	require.Equal(t, wordReplacement{"moderator": "moderator"}, tab.chatWindow.entries[1].Event.displayModifier.badgeReplacement)
+
+	// messages are sent from insert mode
+	_, _ = tab.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	require.Equal(t, insertMode, tab.State())
+
+	for _, r := range "hi chat" {
+		_, _ = tab.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	_, send := tab.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, providerSendResultMessage{tabID: "tab"}, send())
+	require.Equal(t, []string{"hi chat"}, chat.sent)
+
+	close(chat.messages)
+	_, cmd = tab.Update(cmd().(providerMessagesMessage))
+	require.Nil(t, cmd)
+	require.Len(t, tab.chatWindow.entries, 4, "leave notice")
+
+	tab.close()
+	require.Error(t, tab.ctx.Err())
+}
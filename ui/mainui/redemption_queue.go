@@ -0,0 +1,452 @@
+package mainui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/twitch/eventsub"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+)
+
+type redemptionClient interface {
+	GetCustomRewards(ctx context.Context, broadcasterID string, onlyManageable bool) ([]twitchapi.CustomReward, error)
+	GetCustomRewardRedemptions(ctx context.Context, broadcasterID, rewardID, status string) ([]twitchapi.CustomRewardRedemption, error)
+	UpdateRedemptionStatus(ctx context.Context, broadcasterID, rewardID, redemptionID, status string) (twitchapi.CustomRewardRedemption, error)
+}
+
+// redemptionSorts are the orders the queue cycles through
+var redemptionSorts = []string{"oldest", "newest", "reward"}
+
+// redemptionQueue holds the unfulfilled redemptions of custom rewards in the channel of the broadcaster.
+// It is filled by EventSub in the tab and shared with the queue view, so new redemptions show up while it is open.
+type redemptionQueue struct {
+	redemptions []twitchapi.CustomRewardRedemption
+	manageable  map[string]bool // ids of rewards created by Chatuino, nil until the rewards were loaded
+}
+
+func (q *redemptionQueue) add(r twitchapi.CustomRewardRedemption) {
+	if slices.ContainsFunc(q.redemptions, func(e twitchapi.CustomRewardRedemption) bool { return e.ID == r.ID }) {
+		return
+	}
+
+	q.redemptions = append(q.redemptions, r)
+}
+
+func (q *redemptionQueue) remove(id string) {
+	q.redemptions = slices.DeleteFunc(q.redemptions, func(r twitchapi.CustomRewardRedemption) bool {
+		return r.ID == id
+	})
+}
+
+// sorted returns the redemptions in the order, ties are sorted oldest first
+func (q *redemptionQueue) sorted(order string) []twitchapi.CustomRewardRedemption {
+	sorted := slices.Clone(q.redemptions)
+
+	slices.SortStableFunc(sorted, func(a, b twitchapi.CustomRewardRedemption) int {
+		switch order {
+		case "newest":
+			return b.RedeemedAt.Compare(a.RedeemedAt)
+		case "reward":
+			return cmp.Or(strings.Compare(strings.ToLower(a.Reward.Title), strings.ToLower(b.Reward.Title)), a.RedeemedAt.Compare(b.RedeemedAt))
+		}
+
+		return a.RedeemedAt.Compare(b.RedeemedAt)
+	})
+
+	return sorted
+}
+
+// handleRedemptionEvent keeps the queue in sync with the redemptions of the channel,
+// redemptions fulfilled or canceled elsewhere, e.g. on the Twitch dashboard, are removed
+func (q *redemptionQueue) handleRedemptionEvent(subType string, e eventsub.Event) {
+	switch subType {
+	case "channel.channel_points_custom_reward_redemption.add":
+		// rewards skipping the queue are fulfilled right away
+		if !strings.EqualFold(e.Status, twitchapi.RedemptionStatusUnfulfilled) {
+			return
+		}
+
+		q.add(twitchapi.CustomRewardRedemption{
+			ID:               e.ID,
+			BroadcasterID:    e.BroadcasterUserID,
+			BroadcasterLogin: e.BroadcasterUserLogin,
+			UserID:           e.UserID,
+			UserLogin:        e.UserLogin,
+			UserName:         e.UserName,
+			UserInput:        e.UserInput,
+			Status:           twitchapi.RedemptionStatusUnfulfilled,
+			RedeemedAt:       e.RedeemedAt,
+			Reward: twitchapi.CustomReward{
+				ID:     e.Reward.ID,
+				Title:  e.Reward.Title,
+				Prompt: e.Reward.Prompt,
+				Cost:   e.Reward.Cost,
+			},
+		})
+	case "channel.channel_points_custom_reward_redemption.update":
+		q.remove(e.ID)
+	}
+}
+
+// openRedemptionQueueMessage is sent by /redemptions to open the redemption queue of the channel
+type openRedemptionQueueMessage struct {
+	accountID string
+	channelID string
+	channel   string
+	queue     *redemptionQueue
+}
+
+// redemptionsLoadedMessage contains the unfulfilled redemptions of the rewards Chatuino can manage
+type redemptionsLoadedMessage struct {
+	channelID   string
+	manageable  []twitchapi.CustomReward
+	redemptions []twitchapi.CustomRewardRedemption
+	err         error
+}
+
+// redemptionUpdatedMessage is the result of fulfilling or refunding a redemption
+type redemptionUpdatedMessage struct {
+	channelID  string
+	redemption twitchapi.CustomRewardRedemption
+	status     string
+	err        error
+}
+
+// redemptionQueueView lists the unfulfilled redemptions of the channel, they can be fulfilled or refunded
+type redemptionQueueView struct {
+	deps          *DependencyContainer
+	client        redemptionClient
+	width, height int
+	now           func() time.Time
+
+	accountID string
+	channelID string
+	channel   string
+	queue     *redemptionQueue
+
+	order   int    // index into redemptionSorts
+	cursor  int    // index of the selected redemption in the sorted queue
+	offset  int    // index of the first shown redemption
+	pending string // id of the redemption being updated
+	loading bool
+	err     string
+	status  string
+}
+
+func newRedemptionQueueView(width, height int, msg openRedemptionQueueMessage, client redemptionClient, deps *DependencyContainer) *redemptionQueueView {
+	v := &redemptionQueueView{
+		deps:      deps,
+		client:    client,
+		now:       time.Now,
+		accountID: msg.accountID,
+		channelID: msg.channelID,
+		channel:   msg.channel,
+		queue:     msg.queue,
+	}
+	v.handleResize(width, height)
+
+	return v
+}
+
+func (v *redemptionQueueView) handleResize(width, height int) {
+	// the view is shown as a modal, leave some space to the terminal border
+	v.width = max(min(width-4, 100), 30)
+	v.height = max(height-4, 10)
+	v.clampCursor()
+}
+
+// load fetches the unfulfilled redemptions of the rewards created by Chatuino,
+// redemptions of other rewards are only known once they are redeemed while the tab is open
+func (v *redemptionQueueView) load() tea.Cmd {
+	v.loading = true
+
+	client, channelID := v.client, v.channelID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+		defer cancel()
+
+		rewards, err := client.GetCustomRewards(ctx, channelID, true)
+		if err != nil {
+			return redemptionsLoadedMessage{channelID: channelID, err: err}
+		}
+
+		var redemptions []twitchapi.CustomRewardRedemption
+		for _, reward := range rewards {
+			r, err := client.GetCustomRewardRedemptions(ctx, channelID, reward.ID, twitchapi.RedemptionStatusUnfulfilled)
+			if err != nil {
+				return redemptionsLoadedMessage{channelID: channelID, err: err}
+			}
+
+			redemptions = append(redemptions, r...)
+		}
+
+		return redemptionsLoadedMessage{channelID: channelID, manageable: rewards, redemptions: redemptions}
+	}
+}
+
+func (v *redemptionQueueView) Update(msg tea.Msg) (*redemptionQueueView, tea.Cmd) {
+	switch msg := msg.(type) {
+	case redemptionsLoadedMessage:
+		if msg.channelID != v.channelID {
+			return v, nil
+		}
+
+		v.loading = false
+
+		if msg.err != nil {
+			v.err = redemptionErrorText("Failed to load redemptions", msg.err)
+			return v, nil
+		}
+
+		v.queue.manageable = map[string]bool{}
+		for _, reward := range msg.manageable {
+			v.queue.manageable[reward.ID] = true
+		}
+
+		for _, r := range msg.redemptions {
+			v.queue.add(r)
+		}
+
+		v.clampCursor()
+
+		return v, nil
+	case redemptionUpdatedMessage:
+		if msg.channelID != v.channelID {
+			return v, nil
+		}
+
+		v.pending = ""
+
+		if msg.err != nil {
+			v.err = redemptionErrorText("Failed to update redemption", msg.err)
+			return v, nil
+		}
+
+		v.queue.remove(msg.redemption.ID)
+		v.clampCursor()
+
+		action := "Fulfilled"
+		if msg.status == twitchapi.RedemptionStatusCanceled {
+			action = "Refunded"
+		}
+
+		v.status = fmt.Sprintf("%s %s of %s", action, msg.redemption.Reward.Title, msg.redemption.UserName)
+
+		return v, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, v.deps.Keymap.Up):
+			v.moveCursor(-1)
+		case key.Matches(msg, v.deps.Keymap.Down):
+			v.moveCursor(1)
+		case msg.String() == "s":
+			v.order = (v.order + 1) % len(redemptionSorts)
+			v.cursor, v.offset = 0, 0
+		case msg.String() == "f":
+			return v, v.updateSelected(twitchapi.RedemptionStatusFulfilled)
+		case msg.String() == "r":
+			return v, v.updateSelected(twitchapi.RedemptionStatusCanceled)
+		case msg.String() == "d":
+			// redemptions of rewards Chatuino can't manage are only removed from the queue
+			if r, ok := v.selected(); ok {
+				v.queue.remove(r.ID)
+				v.clampCursor()
+				v.err = ""
+				v.status = fmt.Sprintf("Dismissed %s of %s", r.Reward.Title, r.UserName)
+			}
+		}
+	}
+
+	return v, nil
+}
+
+func (v *redemptionQueueView) selected() (twitchapi.CustomRewardRedemption, bool) {
+	sorted := v.queue.sorted(redemptionSorts[v.order])
+	if v.cursor >= len(sorted) {
+		return twitchapi.CustomRewardRedemption{}, false
+	}
+
+	return sorted[v.cursor], true
+}
+
+func (v *redemptionQueueView) updateSelected(status string) tea.Cmd {
+	r, ok := v.selected()
+	if !ok || v.pending != "" {
+		return nil
+	}
+
+	if v.queue.manageable != nil && !v.queue.manageable[r.Reward.ID] {
+		v.err = "Twitch only allows the app which created a reward to fulfill or refund its redemptions, use the Twitch dashboard or dismiss it"
+		return nil
+	}
+
+	v.pending = r.ID
+	v.err = ""
+	v.status = ""
+
+	client, channelID := v.client, v.channelID
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		_, err := client.UpdateRedemptionStatus(ctx, channelID, r.Reward.ID, r.ID, status)
+		return redemptionUpdatedMessage{channelID: channelID, redemption: r, status: status, err: err}
+	}
+}
+
+func (v *redemptionQueueView) moveCursor(delta int) {
+	v.cursor += delta
+	v.err = ""
+	v.status = ""
+	v.clampCursor()
+}
+
+// clampCursor keeps the cursor on a redemption and scrolls it into view
+func (v *redemptionQueueView) clampCursor() {
+	v.cursor = min(max(v.cursor, 0), max(len(v.queue.redemptions)-1, 0))
+
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+
+	if v.cursor >= v.offset+v.listHeight() {
+		v.offset = v.cursor - v.listHeight() + 1
+	}
+}
+
+func (v *redemptionQueueView) listHeight() int {
+	// title, help, blank lines, status, borders and padding
+	return max(v.height-9, 1)
+}
+
+func (v *redemptionQueueView) View() string {
+	theme := v.deps.UserConfig.Theme
+	innerWidth := v.width - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ActiveLabelColor))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor))
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render(fmt.Sprintf("Redemption Queue of %s (%d)", v.channel, len(v.queue.redemptions))) + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(fmt.Sprintf("%s select · f fulfill · r refund · d dismiss · s sort: %s · %s close",
+		v.deps.Keymap.Up.Help().Key+"/"+v.deps.Keymap.Down.Help().Key,
+		redemptionSorts[v.order],
+		v.deps.Keymap.Escape.Help().Key,
+	)) + "\n\n")
+
+	sorted := v.queue.sorted(redemptionSorts[v.order])
+	lines := make([]string, 0, v.listHeight())
+
+	if len(sorted) == 0 {
+		lines = append(lines, dimmedStyle.Render("No unfulfilled redemptions, new redemptions are added while the tab is open"))
+	}
+
+	for i, r := range sorted[min(v.offset, len(sorted)):min(v.offset+v.listHeight(), len(sorted))] {
+		age := humanizeAge(v.now().Sub(r.RedeemedAt))
+		reward := fmt.Sprintf("%s (%d)", termtext.Sanitize(r.Reward.Title), r.Reward.Cost)
+
+		line := fmt.Sprintf("%-4s %s %s", age, reward, termtext.Sanitize(r.UserName))
+		if r.UserInput != "" {
+			line += ": " + termtext.Sanitize(r.UserInput)
+		}
+
+		switch {
+		case r.ID == v.pending:
+			line += " " + dimmedStyle.Render("updating…")
+		case v.queue.manageable != nil && !v.queue.manageable[r.Reward.ID]:
+			line += " " + dimmedStyle.Render("(not manageable)")
+		}
+
+		if v.offset+i == v.cursor {
+			line = selectedStyle.Render("> ") + line
+		} else {
+			line = "  " + line
+		}
+
+		lines = append(lines, ansi.Truncate(line, innerWidth, "…"))
+	}
+
+	// fill up the list, so the border doesn't jump
+	for len(lines) < v.listHeight() {
+		lines = append(lines, "")
+	}
+
+	_, _ = b.WriteString(strings.Join(lines, "\n") + "\n\n")
+
+	switch {
+	case v.err != "":
+		_, _ = b.WriteString(errorStyle.Width(innerWidth).Render(v.err))
+	case v.status != "":
+		_, _ = b.WriteString(dimmedStyle.Render(termtext.Sanitize(v.status)))
+	case v.loading:
+		_, _ = b.WriteString(dimmedStyle.Render("Loading redemptions…"))
+	}
+
+	return lipgloss.NewStyle().
+		Width(v.width).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
+
+// humanizeAge returns a short age like 45s, 12m or 3h
+func humanizeAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(int(d.Seconds()), 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < time.Hour*24:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func redemptionErrorText(prefix string, err error) string {
+	var apiErr twitchapi.APIError
+	if errors.As(err, &apiErr) && (apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden) {
+		return prefix + ", the account needs the channel:manage:redemptions scope, add the account again to grant it: " + apiErr.Message
+	}
+
+	return prefix + ": " + err.Error()
+}
+
+// handleRedemptionsCommand opens the redemption queue, it is only available in the channel of the account
+func (t *broadcastTab) handleRedemptionsCommand() tea.Cmd {
+	_, ok := t.deps.APIUserClients[t.account.ID].(redemptionClient)
+	if !ok || t.redemptions == nil {
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice("The redemption queue is only available in your own channel")
+		}
+	}
+
+	msg := openRedemptionQueueMessage{
+		accountID: t.account.ID,
+		channelID: t.channelID,
+		channel:   t.channelLogin,
+		queue:     t.redemptions,
+	}
+
+	return func() tea.Msg {
+		return msg
+	}
+}
@@ -0,0 +1,162 @@
+package mainui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/twitch/eventsub"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRedemptionClient struct {
+	rewards     []twitchapi.CustomReward
+	redemptions map[string][]twitchapi.CustomRewardRedemption
+	updated     []string
+}
+
+func (f *fakeRedemptionClient) GetCustomRewards(context.Context, string, bool) ([]twitchapi.CustomReward, error) {
+	return f.rewards, nil
+}
+
+func (f *fakeRedemptionClient) GetCustomRewardRedemptions(_ context.Context, _, rewardID, _ string) ([]twitchapi.CustomRewardRedemption, error) {
+	return f.redemptions[rewardID], nil
+}
+
+func (f *fakeRedemptionClient) UpdateRedemptionStatus(_ context.Context, _, _, redemptionID, status string) (twitchapi.CustomRewardRedemption, error) {
+	f.updated = append(f.updated, redemptionID+"="+status)
+	return twitchapi.CustomRewardRedemption{ID: redemptionID, Status: status}, nil
+}
+
+func testRedemption(id, reward, user string, redeemedAt time.Time) twitchapi.CustomRewardRedemption {
+	return twitchapi.CustomRewardRedemption{
+		ID:         id,
+		UserName:   user,
+		Status:     twitchapi.RedemptionStatusUnfulfilled,
+		RedeemedAt: redeemedAt,
+		Reward:     twitchapi.CustomReward{ID: reward, Title: reward, Cost: 100},
+	}
+}
+
+func Test_redemptionQueue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	q := &redemptionQueue{}
+	q.add(testRedemption("1", "Hydrate", "alice", now.Add(-time.Minute)))
+	q.add(testRedemption("2", "Song Request", "bob", now.Add(-time.Hour)))
+	q.add(testRedemption("3", "hydrate", "carol", now.Add(-time.Second)))
+	q.add(testRedemption("1", "Hydrate", "alice", now.Add(-time.Minute)))
+
+	ids := func(order string) []string {
+		var ids []string
+		for _, r := range q.sorted(order) {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	require.Equal(t, []string{"2", "1", "3"}, ids("oldest"))
+	require.Equal(t, []string{"3", "1", "2"}, ids("newest"))
+	require.Equal(t, []string{"1", "3", "2"}, ids("reward"))
+
+	// redemptions are added by EventSub and removed once they were fulfilled or canceled
+	q.handleRedemptionEvent("channel.channel_points_custom_reward_redemption.add", eventsub.Event{
+		ID:         "4",
+		UserName:   "dave",
+		UserInput:  "never gonna give you up",
+		Status:     "unfulfilled",
+		RedeemedAt: now,
+		Reward:     eventsub.Reward{ID: "Song Request", Title: "Song Request", Cost: 500},
+	})
+	q.handleRedemptionEvent("channel.channel_points_custom_reward_redemption.add", eventsub.Event{ID: "5", Status: "fulfilled"})
+	q.handleRedemptionEvent("channel.channel_points_custom_reward_redemption.update", eventsub.Event{ID: "2", Status: "canceled"})
+
+	require.Equal(t, []string{"1", "3", "4"}, ids("oldest"))
+	require.Equal(t, "never gonna give you up", q.sorted("newest")[0].UserInput)
+}
+
+func Test_redemptionQueueView(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	client := &fakeRedemptionClient{
+		rewards: []twitchapi.CustomReward{{ID: "Hydrate", Title: "Hydrate"}},
+		redemptions: map[string][]twitchapi.CustomRewardRedemption{
+			"Hydrate": {testRedemption("1", "Hydrate", "alice", now.Add(-time.Minute*5))},
+		},
+	}
+
+	deps := newTestDeps(t)
+
+	queue := &redemptionQueue{}
+	queue.add(testRedemption("2", "Emote Only", "bob", now.Add(-time.Hour*2)))
+
+	v := newRedemptionQueueView(100, 30, openRedemptionQueueMessage{channelID: "1", channel: "julezdev", queue: queue}, client, deps)
+	v.now = func() time.Time { return now }
+
+	v.Update(v.load()())
+	require.False(t, v.loading)
+
+	view := ansi.Strip(v.View())
+	require.Contains(t, view, "Redemption Queue of julezdev (2)")
+	require.Contains(t, view, "> 2h   Emote Only (100) bob (not manageable)")
+	require.Contains(t, view, "5m   Hydrate (100) alice")
+
+	// rewards of other apps can't be fulfilled
+	_, cmd := v.Update(runeKey('f'))
+	require.Nil(t, cmd)
+	require.Contains(t, v.err, "Twitch only allows the app which created a reward")
+
+	v.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = v.Update(runeKey('f'))
+	require.Equal(t, "1", v.pending)
+
+	v.Update(cmd())
+	require.Equal(t, []string{"1=FULFILLED"}, client.updated)
+	require.Equal(t, "Fulfilled Hydrate of alice", v.status)
+	require.Len(t, queue.redemptions, 1)
+	require.Equal(t, 0, v.cursor)
+
+	v.Update(runeKey('d'))
+	require.Empty(t, queue.redemptions)
+	require.Contains(t, ansi.Strip(v.View()), "No unfulfilled redemptions")
+}
+
+func Test_humanizeAge(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "0s", humanizeAge(-time.Second))
+	require.Equal(t, "45s", humanizeAge(time.Second*45))
+	require.Equal(t, "12m", humanizeAge(time.Minute*12))
+	require.Equal(t, "3h", humanizeAge(time.Hour*3+time.Minute*59))
+	require.Equal(t, "2d", humanizeAge(time.Hour*50))
+}
+
+func Test_redemptionQueueView_sanitizes(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	deps := newTestDeps(t)
+
+	redemption := testRedemption("1", "Hydrate", "alice\x1b[2J", now)
+	redemption.UserInput = "drink\x1b]0;title\x07 water"
+
+	queue := &redemptionQueue{}
+	queue.add(redemption)
+
+	v := newRedemptionQueueView(100, 30, openRedemptionQueueMessage{channelID: "1", channel: "julezdev", queue: queue}, &fakeRedemptionClient{}, deps)
+	v.now = func() time.Time { return now }
+	v.Update(v.load()())
+
+	view := v.View()
+	require.NotContains(t, view, "\x1b[2J")
+	require.NotContains(t, view, "\x1b]0;")
+	require.Contains(t, ansi.Strip(view), "Hydrate (100) alice: drink water")
+}
@@ -0,0 +1,486 @@
+package mainui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/julez-dev/chatuino/replay"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// maxReplayBatch is the number of messages sent to the render pipeline in one step, when they were sent at the same time
+// or are replayed at max speed. Seeking shows this many messages before the position.
+const maxReplayBatch = 100
+
+const replaySyncInterval = time.Millisecond * 500
+
+// Replay is a recorded chat log, replayed by the replay and vod commands in a read only tab
+type Replay struct {
+	Name     string // shown in the tab header, like the name of the log file
+	Messages []replay.Message
+	Speed    replay.Speed
+	Start    time.Time     // time of the position zero, the first message if zero
+	Offset   time.Duration // position the replay starts at, ignored when synced with a player
+	Player   Player        // optional, the replay follows the playback position of the player instead of the speed
+}
+
+// Player reports the playback position of a video player the replay is synced with, like mpv
+type Player interface {
+	Name() string
+	Position(ctx context.Context) (time.Duration, error)
+}
+
+type replayLoadedMessage struct {
+	tabID  string
+	roomID string
+	err    error // emotes or badges of the channel could not be loaded, the replay starts anyway
+}
+
+type replayStepMessage struct {
+	tabID string
+}
+
+type replaySyncMessage struct {
+	tabID    string
+	position time.Duration
+	err      error
+}
+
+// replayTab replays the messages of a recorded chat log at the pace they were sent.
+// Messages are passed through the same pipeline as live messages, so emotes, badges and scripts are applied.
+type replayTab struct {
+	id     string
+	deps   *DependencyContainer
+	replay *Replay
+
+	focused       bool
+	width, height int
+
+	loaded   bool
+	next     int           // index of the next replayed message
+	position time.Duration // position of the last replayed message or the player
+	paused   bool          // steps are not scheduled while paused
+	stalled  bool          // a step arrived while paused, it is taken once resumed
+	syncErr  error         // the position of the player could not be read
+
+	spinner    spinner.Model
+	chatWindow *chatWindow
+}
+
+func newReplayTab(id string, width, height int, replay *Replay, deps *DependencyContainer) *replayTab {
+	return &replayTab{
+		id:         id,
+		deps:       deps,
+		replay:     replay,
+		width:      width,
+		height:     height,
+		spinner:    spinner.New(spinner.WithSpinner(customEllipsisSpinner)),
+		chatWindow: newChatWindow(width, height, deps),
+	}
+}
+
+func (r *replayTab) Init() tea.Cmd {
+	tabID, deps := r.id, r.deps
+	channel, roomID := replayChannel(r.replay.Messages)
+
+	return tea.Batch(r.spinner.Tick, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		// JSON exports don't contain the channel ID, which is needed for the emotes and badges of the channel
+		if roomID == "" && channel != "" && deps.ServerAPI != nil {
+			resp, err := deps.ServerAPI.GetUsers(ctx, []string{channel}, nil)
+			if err != nil {
+				return replayLoadedMessage{tabID: tabID, err: fmt.Errorf("could not find channel %s: %w", channel, err)}
+			}
+
+			if len(resp.Data) > 0 {
+				roomID = resp.Data[0].ID
+			}
+		}
+
+		if roomID == "" {
+			return replayLoadedMessage{tabID: tabID}
+		}
+
+		var errEmotes, errBadges error
+		if err := deps.EmoteCache.RefreshLocal(ctx, roomID); err != nil {
+			errEmotes = fmt.Errorf("could not refresh emote cache for %s: %w", channel, err)
+		}
+
+		if err := deps.BadgeCache.RefreshChannel(ctx, roomID); err != nil {
+			errBadges = fmt.Errorf("could not refresh badge cache for %s: %w", channel, err)
+		}
+
+		return replayLoadedMessage{tabID: tabID, roomID: roomID, err: errors.Join(errEmotes, errBadges)}
+	})
+}
+
+func (r *replayTab) InitWithUserData(twitchapi.UserData) tea.Cmd {
+	return r.Init()
+}
+
+// replayChannel returns the channel of the first message and the first known channel ID
+func replayChannel(messages []replay.Message) (string, string) {
+	var channel, roomID string
+
+	for _, m := range messages {
+		if msg, ok := m.Message.(*twitchirc.PrivateMessage); ok {
+			channel = cmp.Or(channel, msg.ChannelUserName)
+			roomID = cmp.Or(roomID, msg.RoomID)
+		}
+
+		if channel != "" && roomID != "" {
+			break
+		}
+	}
+
+	return channel, roomID
+}
+
+func (r *replayTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case replayLoadedMessage:
+		if msg.tabID != r.id {
+			return r, nil
+		}
+
+		r.loaded = true
+		r.chatWindow.Focus()
+		r.HandleResize()
+
+		// messages of exports have no channel ID, it is needed to replace the emotes of the channel
+		if msg.roomID != "" {
+			for _, m := range r.replay.Messages {
+				if privMsg, ok := m.Message.(*twitchirc.PrivateMessage); ok && privMsg.RoomID == "" {
+					privMsg.RoomID = msg.roomID
+				}
+			}
+		}
+
+		if msg.err != nil {
+			r.notice(msg.err.Error())
+		}
+
+		if r.replay.Player != nil {
+			r.notice(fmt.Sprintf("Replaying %d messages of %s in sync with %s", len(r.replay.Messages), r.replay.Name, r.replay.Player.Name()))
+			return r, r.sync()
+		}
+
+		r.notice(fmt.Sprintf("Replaying %d messages of %s at %s speed", len(r.replay.Messages), r.replay.Name, r.replay.Speed))
+
+		return r, tea.Batch(r.seek(r.replay.Offset), r.scheduleStep(r.start().Add(r.replay.Offset)))
+	case replayStepMessage:
+		if msg.tabID != r.id {
+			return r, nil
+		}
+
+		if r.paused {
+			r.stalled = true
+			return r, nil
+		}
+
+		return r, r.step()
+	case replaySyncMessage:
+		if msg.tabID != r.id {
+			return r, nil
+		}
+
+		r.syncErr = msg.err
+		if msg.err != nil {
+			return r, r.sync()
+		}
+
+		return r, tea.Batch(r.seek(msg.position), r.sync())
+	case chatEventMessage:
+		if msg.tabID != r.id {
+			return r, nil
+		}
+
+		r.chatWindow.handleMessage(msg)
+
+		// the last message of the log was rendered
+		if r.next == len(r.replay.Messages) && msg.message == r.replay.Messages[r.next-1].Message {
+			r.notice("Replay finished")
+		}
+
+		return r, nil
+	}
+
+	if !r.loaded {
+		r.spinner, cmd = r.spinner.Update(msg)
+		return r, cmd
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if !r.focused {
+			return r, nil
+		}
+
+		// synced replays are paused with the player
+		if key.Matches(keyMsg, r.deps.Keymap.Confirm) && r.chatWindow.state == viewChatWindowState && r.replay.Player == nil {
+			return r, r.togglePause()
+		}
+	}
+
+	r.chatWindow, cmd = r.chatWindow.Update(msg)
+
+	return r, cmd
+}
+
+// start is the time of the position zero
+func (r *replayTab) start() time.Time {
+	if !r.replay.Start.IsZero() {
+		return r.replay.Start
+	}
+
+	return r.replay.Messages[0].SentAt
+}
+
+// step sends the due messages to the render pipeline and schedules the next step, when messages are left
+func (r *replayTab) step() tea.Cmd {
+	messages := r.replay.Messages
+	if r.next >= len(messages) {
+		return nil
+	}
+
+	batch := []twitchirc.IRCer{messages[r.next].Message}
+	r.next++
+
+	for r.next < len(messages) && len(batch) < maxReplayBatch && r.replay.Speed.Delay(messages[r.next-1].SentAt, messages[r.next].SentAt) == 0 {
+		batch = append(batch, messages[r.next].Message)
+		r.next++
+	}
+
+	r.position = messages[r.next-1].SentAt.Sub(r.start())
+
+	return tea.Batch(r.handleBatch(batch), r.scheduleStep(messages[r.next-1].SentAt))
+}
+
+// scheduleStep schedules the step of the next message, after the time between from and the time it was sent
+func (r *replayTab) scheduleStep(from time.Time) tea.Cmd {
+	if r.next >= len(r.replay.Messages) {
+		return nil
+	}
+
+	tabID := r.id
+	delay := r.replay.Speed.Delay(from, r.replay.Messages[r.next].SentAt)
+
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return replayStepMessage{tabID: tabID}
+	})
+}
+
+// seek replays the messages sent until the position at once, only the last messages are shown when the position is far ahead.
+// Seeking back starts over with an empty chat.
+func (r *replayTab) seek(position time.Duration) tea.Cmd {
+	messages := r.replay.Messages
+	at := r.start().Add(position)
+
+	// index of the first message sent after the position
+	due, _ := slices.BinarySearchFunc(messages, at, func(m replay.Message, at time.Time) int {
+		if m.SentAt.After(at) {
+			return 1
+		}
+
+		return -1
+	})
+
+	r.position = position
+
+	if due < r.next {
+		r.chatWindow = newChatWindow(r.width, r.height, r.deps)
+		if r.focused {
+			r.chatWindow.Focus()
+		}
+
+		r.HandleResize()
+		r.next = 0
+	}
+
+	r.next = max(r.next, due-maxReplayBatch)
+	if r.next >= due {
+		return nil
+	}
+
+	batch := make([]twitchirc.IRCer, 0, due-r.next)
+	for _, m := range messages[r.next:due] {
+		batch = append(batch, m.Message)
+	}
+
+	r.next = due
+
+	return r.handleBatch(batch)
+}
+
+// sync reads the position of the player after the sync interval
+func (r *replayTab) sync() tea.Cmd {
+	tabID, player := r.id, r.replay.Player
+
+	return tea.Tick(replaySyncInterval, func(time.Time) tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+		defer cancel()
+
+		position, err := player.Position(ctx)
+		return replaySyncMessage{tabID: tabID, position: position, err: err}
+	})
+}
+
+// handleBatch passes the messages through the render pipeline of Root, which sends them back as chat events of the tab
+func (r *replayTab) handleBatch(batch []twitchirc.IRCer) tea.Cmd {
+	tabID := r.id
+
+	return func() tea.Msg {
+		return requestLocalMessageHandleBatchMessage{tabID: tabID, messages: batch}
+	}
+}
+
+func (r *replayTab) togglePause() tea.Cmd {
+	r.paused = !r.paused
+
+	if r.paused || !r.stalled {
+		return nil
+	}
+
+	r.stalled = false
+	return r.step()
+}
+
+func (r *replayTab) notice(text string) {
+	r.chatWindow.handleMessage(chatEventMessage{
+		isFakeEvent: true,
+		tabID:       r.id,
+		message: &twitchirc.Notice{
+			FakeTimestamp: time.Now(),
+			MsgID:         twitchirc.MsgID(uuid.NewString()),
+			Message:       text,
+		},
+	})
+}
+
+func (r *replayTab) View() string {
+	if !r.loaded {
+		return lipgloss.NewStyle().
+			Width(r.width).
+			Height(r.height).
+			AlignHorizontal(lipgloss.Center).
+			AlignVertical(lipgloss.Center).
+			Render(spinnerView(r.spinner, r.deps.UserConfig.Settings) + " Loading replay")
+	}
+
+	return r.statusView() + "\n" + r.chatWindow.View()
+}
+
+// statusView shows the progress of the replay and how to pause it
+func (r *replayTab) statusView() string {
+	progress := fmt.Sprintf("%s · %d/%d", formatReplayPosition(r.position), r.next, len(r.replay.Messages))
+
+	var status string
+	switch {
+	case r.replay.Player != nil && r.syncErr != nil:
+		status = fmt.Sprintf("Replay %s · %s · %s", r.replay.Name, progress, r.syncErr)
+	case r.replay.Player != nil:
+		status = fmt.Sprintf("Replay %s · synced with %s · %s", r.replay.Name, r.replay.Player.Name(), progress)
+	default:
+		state := "playing"
+		switch {
+		case r.paused:
+			state = "paused"
+		case r.next == len(r.replay.Messages):
+			state = "finished"
+		}
+
+		status = fmt.Sprintf("Replay %s · %s · %s · %s %s", r.replay.Name, r.replay.Speed, progress, r.deps.Keymap.Confirm.Help().Key, state)
+	}
+
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(r.deps.UserConfig.Theme.DimmedTextColor)).
+		Render(centerTextGraphemeAware(r.width, status))
+}
+
+// formatReplayPosition formats a position like 1:02:03
+func formatReplayPosition(d time.Duration) string {
+	d = max(d, 0).Truncate(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+func (r *replayTab) ViewWithoutStatusBar() string {
+	return r.View() // the replay tab has no status bar
+}
+
+func (r *replayTab) StatusBarView() string {
+	return ""
+}
+
+func (r *replayTab) Focus() {
+	r.focused = true
+	r.chatWindow.Focus()
+}
+
+func (r *replayTab) Blur() {
+	r.focused = false
+	r.chatWindow.Blur()
+}
+
+func (r *replayTab) AccountID() string {
+	return ""
+}
+
+func (r *replayTab) Channel() string {
+	return ""
+}
+
+func (r *replayTab) State() broadcastTabState {
+	return inChatWindow
+}
+
+func (r *replayTab) IsTyping() bool {
+	return r.chatWindow.state != viewChatWindowState
+}
+
+func (r *replayTab) IsDataLoaded() bool {
+	return r.loaded
+}
+
+func (r *replayTab) ID() string {
+	return r.id
+}
+
+func (r *replayTab) Focused() bool {
+	return r.focused
+}
+
+func (r *replayTab) ChannelID() string {
+	return ""
+}
+
+func (r *replayTab) HandleResize() {
+	r.chatWindow.width = r.width
+	r.chatWindow.height = max(r.height-1, 0) // status line
+	r.chatWindow.recalculateLines()
+}
+
+func (r *replayTab) SetSize(width, height int) {
+	r.width = width
+	r.height = height
+}
+
+func (r *replayTab) SetFullWidth(_ int) {
+	// No-op for the replay tab (no status bar)
+}
+
+func (r *replayTab) Kind() tabKind {
+	return replayTabKind
+}
@@ -0,0 +1,106 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/replay"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayTab(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	messages := []replay.Message{
+		{SentAt: start, Message: &twitchirc.PrivateMessage{ID: "1", ChannelUserName: "lirik", Message: "first"}},
+		{SentAt: start, Message: &twitchirc.PrivateMessage{ID: "2", ChannelUserName: "lirik", Message: "second"}},
+		{SentAt: start.Add(time.Second), Message: &twitchirc.PrivateMessage{ID: "3", ChannelUserName: "lirik", Message: "third"}},
+	}
+
+	channel, roomID := replayChannel(messages)
+	require.Equal(t, "lirik", channel)
+	require.Empty(t, roomID)
+
+	tab := newReplayTab("tab", 80, 20, &Replay{Name: "lirik.json", Messages: messages, Speed: 1}, deps)
+	tab.Focus()
+
+	// messages sent at the same time are replayed together, the next step is scheduled after the delay
+	_, cmd := tab.Update(replayLoadedMessage{tabID: "tab", roomID: "22484632"})
+	batch := cmd().(tea.BatchMsg)
+	require.Len(t, batch, 2)
+	require.Equal(t, requestLocalMessageHandleBatchMessage{tabID: "tab", messages: []twitchirc.IRCer{messages[0].Message, messages[1].Message}}, batch[0]())
+	require.Equal(t, "22484632", messages[2].Message.(*twitchirc.PrivateMessage).RoomID, "the channel ID is set on messages of exports")
+	require.Contains(t, tab.View(), "2/3")
+
+	// steps arriving while paused are taken once resumed
+	_, _ = tab.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = tab.Update(replayStepMessage{tabID: "tab"})
+	require.Nil(t, cmd)
+	require.Contains(t, tab.View(), "paused")
+
+	_, cmd = tab.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, requestLocalMessageHandleBatchMessage{tabID: "tab", messages: []twitchirc.IRCer{messages[2].Message}}, cmd())
+
+	// the last message finishes the replay
+	entries := len(tab.chatWindow.entries)
+	_, _ = tab.Update(chatEventMessage{tabID: "tab", message: messages[2].Message})
+	require.Len(t, tab.chatWindow.entries, entries+2, "message and finish notice")
+	require.Contains(t, tab.View(), "finished")
+
+	_, cmd = tab.Update(replayStepMessage{tabID: "tab"})
+	require.Nil(t, cmd)
+}
+
+type fakePlayer struct{}
+
+func (fakePlayer) Name() string { return "mpv" }
+
+func (fakePlayer) Position(context.Context) (time.Duration, error) { return 0, nil }
+
+func TestReplayTab_sync(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	var messages []replay.Message
+	for i := range 300 {
+		messages = append(messages, replay.Message{SentAt: start.Add(time.Duration(i) * time.Second), Message: &twitchirc.PrivateMessage{ID: strconv.Itoa(i)}})
+	}
+
+	tab := newReplayTab("tab", 80, 20, &Replay{Name: "VOD 1", Messages: messages, Start: start, Player: fakePlayer{}}, deps)
+	_, _ = tab.Update(replayLoadedMessage{tabID: "tab"})
+
+	syncTo := func(position time.Duration) []twitchirc.IRCer {
+		_, cmd := tab.Update(replaySyncMessage{tabID: "tab", position: position})
+		// the replayed messages are the first command, followed by the next sync tick
+		return cmd().(tea.BatchMsg)[0]().(requestLocalMessageHandleBatchMessage).messages
+	}
+
+	// jumping ahead only shows the last messages before the position
+	batch := syncTo(250 * time.Second)
+	require.Len(t, batch, maxReplayBatch)
+	require.Equal(t, "250", batch[len(batch)-1].(*twitchirc.PrivateMessage).ID)
+	require.Contains(t, tab.View(), "0:04:10")
+
+	require.Equal(t, []twitchirc.IRCer{messages[251].Message, messages[252].Message}, syncTo(252*time.Second+time.Millisecond*500))
+
+	// seeking back starts over with an empty chat
+	tab.chatWindow.handleMessage(chatEventMessage{tabID: "tab", message: messages[252].Message})
+	batch = syncTo(10 * time.Second)
+	require.Len(t, batch, 11)
+	require.Empty(t, tab.chatWindow.entries)
+
+	_, cmd := tab.Update(replaySyncMessage{tabID: "tab", err: errors.New("mpv: property unavailable")})
+	require.NotNil(t, cmd, "the position is read again")
+	require.Contains(t, tab.View(), "property unavailable")
+}
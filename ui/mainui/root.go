@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
+	"runtime/debug"
 	"slices"
 	"strings"
 	"sync"
@@ -16,10 +18,16 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/blocklist"
+	"github.com/julez-dev/chatuino/chatprovider"
 	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/hook"
+	"github.com/julez-dev/chatuino/metrics"
 	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/selfupdate"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/ui/component"
 	"github.com/julez-dev/chatuino/wspool"
 	overlay "github.com/rmhubbert/bubbletea-overlay"
 	"github.com/rs/zerolog/log"
@@ -32,6 +40,10 @@ const (
 	broadcastTabKind tabKind = iota
 	mentionTabKind
 	liveNotificationTabKind
+	youTubeTabKind
+	kickTabKind
+	ircInspectorTabKind
+	replayTabKind
 )
 
 func (t tabKind) String() string {
@@ -42,6 +54,14 @@ func (t tabKind) String() string {
 		return "Mention"
 	case liveNotificationTabKind:
 		return "Live Notifications"
+	case youTubeTabKind:
+		return "YouTube Live (experimental)"
+	case kickTabKind:
+		return "Kick (experimental, read only)"
+	case ircInspectorTabKind:
+		return "IRC Inspector (debug)"
+	case replayTabKind:
+		return "Replay"
 	}
 
 	return "<not implemented>"
@@ -67,6 +87,7 @@ type tab interface {
 	SetSize(width, height int)
 	SetFullWidth(width int) // for status bar in vertical tab mode
 	Kind() tabKind
+	IsTyping() bool // key presses are used as text, like in insert or search mode
 }
 
 type header interface {
@@ -78,6 +99,7 @@ type header interface {
 	SelectTab(id string)
 	Resize(width, height int)
 	MinWidth() int
+	TabAt(x, y int) (string, bool) // id of the tab shown at the cell, relative to the top left corner of the header
 }
 
 type activeScreen int
@@ -86,6 +108,13 @@ const (
 	mainScreen activeScreen = iota
 	inputScreen
 	helpScreen
+	settingsScreen
+	debugLogScreen
+	chatSettingsScreen
+	threadScreen
+	redemptionQueueScreen
+	pollConfirmScreen
+	chatStatsScreen
 )
 
 type ircConnectionError struct {
@@ -122,9 +151,47 @@ type Root struct {
 	header    header
 	joinInput *join
 	help      *help
+	sidebar   *followedSidebar
+
+	settingsEditor *settingsEditor // only set while the settings screen is open
+	stats          *statsOverlay
+	debugLog       *debugLogViewer // only set while the debug log is open
+	debugLogOpened int             // number of times the debug log was opened, used to ignore ticks of closed viewers
+
+	chatSettings *chatSettingsEditor // only set while the chat settings of a channel are open
+
+	thread       *threadViewer // only set while a reply thread is shown
+	threadOpened int           // number of times a thread was opened, used to ignore chat log results of closed viewers
+
+	redemptionQueue *redemptionQueueView // only set while the redemption queue of a channel is open
+	pollConfirm     *pollConfirm         // only set while a poll or prediction waits for confirmation
+	chatStats       *chatStatsView       // only set while the chat stats of a channel are shown
 
 	tabCursor int
 	tabs      []tab
+
+	// sent message history of all tabs, only used if shared input history is enabled
+	sharedInputHistory *component.InputHistory
+
+	// saved tabs which were not restored, their drafts and input histories are applied when the channel is opened again
+	unopenedTabs []save.TabState
+
+	keySequencer *keySequencer
+
+	idle              idleTracker
+	watchtimeTracked  time.Time // time the watchtime was last added to the open channels
+	streamInfoSkipped bool      // a stream info refresh was skipped while idle
+
+	throttle *chatThrottle
+	spamFade *spamFade
+
+	updateNotice *selfupdate.Release // newer release announced above the tabs, nil if there is none or it was dismissed
+	goLive       *goLiveWatcher      // notifications about followed channels going live
+	players      []*exec.Cmd         // running players started from go live notifications, players of tabs are kept by the tab
+	tray         *notificationTray   // whispers, mentions in background tabs and go live notifications until they are dismissed
+
+	windowTitle  string // last name of the tmux window, empty until it was renamed
+	announcedTab string // id of the tab announced last with the screen reader mode
 }
 
 func NewUI(
@@ -138,6 +205,10 @@ func NewUI(
 		header = newHorizontalTabHeader(10, dependencies)
 	}
 
+	tray := newNotificationTray(dependencies)
+	goLive := newGoLiveWatcher(dependencies)
+	goLive.tray = tray
+
 	return &Root{
 		dependencies:      dependencies,
 		width:             10,
@@ -152,19 +223,46 @@ func NewUI(
 		header:    header,
 		help:      newHelp(10, 10, dependencies),
 		joinInput: newJoin(10, dependencies),
-
-		messageLoggerChan: messageLoggerChan,
+		sidebar:   newFollowedSidebar(dependencies),
+		stats:     newStatsOverlay(dependencies),
+		goLive:    goLive,
+		tray:      tray,
+
+		messageLoggerChan:  messageLoggerChan,
+		sharedInputHistory: component.NewInputHistory(dependencies.UserConfig.Settings.Session.InputHistorySize, nil),
+		keySequencer:       newKeySequencer(dependencies.Keymap, dependencies.ChannelKeymaps),
+		idle:               idleTracker{lastInput: time.Now()},
+		throttle:           newChatThrottle(),
+		spamFade:           newSpamFade(),
 	}
 }
 
 func (r *Root) Init() tea.Cmd {
+	var sidebarCmd tea.Cmd
+	if r.dependencies.UserConfig.Settings.FollowedSidebar.ShowOnStartup {
+		sidebarCmd = r.sidebar.show()
+	}
+
 	return tea.Batch(
 		tea.SetWindowTitle("Chatuino"),
+		sidebarCmd,
+		r.checkForUpdate(),
+		r.goLive.start(),
+		r.startWatchtime(),
 		func() tea.Msg {
-			state, err := r.dependencies.AppStateManager.LoadAppState()
-			if err != nil {
-				return persistedDataLoadedMessage{
-					err: fmt.Errorf("failed to load save state: %w", err),
+			var (
+				state save.AppState
+				err   error
+			)
+
+			// the state is loaded even if the tabs are not restored, it also keeps the input history and drafts.
+			// Replays never restore the session, only the replay tab is opened.
+			if r.dependencies.Replay == nil {
+				state, err = r.dependencies.AppStateManager.LoadAppState()
+				if err != nil {
+					return persistedDataLoadedMessage{
+						err: fmt.Errorf("failed to load save state: %w", err),
+					}
 				}
 			}
 
@@ -194,6 +292,35 @@ func (r *Root) Init() tea.Cmd {
 				return nil
 			})
 
+			if r.dependencies.Bots != nil {
+				wg.Go(func() error {
+					ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+					defer cancel()
+
+					if err := r.dependencies.Bots.RefreshGlobal(ctx); err != nil {
+						log.Logger.Error().Err(err).Msg("could not fetch global bots")
+					}
+
+					return nil
+				})
+			}
+
+			// fetch the users blocked by all accounts, accounts added before blocking was supported lack the scope
+			for _, acc := range r.dependencies.Accounts {
+				client, ok := r.dependencies.APIUserClients[acc.ID].(blocklist.Client)
+				if acc.IsAnonymous || !ok || r.dependencies.Blocks == nil {
+					continue
+				}
+
+				wg.Go(func() error {
+					if err := r.dependencies.Blocks.Refresh(ctx, client, acc.ID); err != nil {
+						log.Logger.Error().Str("user_id", acc.ID).Err(err).Msg("could not fetch blocked users")
+					}
+
+					return nil
+				})
+			}
+
 			// fetch usable emotes for all users
 			for _, acc := range r.dependencies.Accounts {
 				if acc.IsAnonymous {
@@ -289,65 +416,103 @@ func (r *Root) Init() tea.Cmd {
 			}
 		},
 		r.tickPollStreamInfos(),
+		r.tickEmoteUpdates(),
 		r.imageCleanUpCommand(),
+		relativeTimestampTickCommand(r.dependencies.UserConfig.Settings.Timestamps),
+		configReloadTickCommand(r.dependencies.ConfigSource),
+		idleCheckTickCommand(),
 	)
 }
 
 func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer r.handlePanic()
+
+	var resumeCmd tea.Cmd
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		if r.idle.input(time.Now()) {
+			resumeCmd = r.handleIdleChanged(false)
+		}
+	}
+
+	model, cmd := r.update(msg)
+
+	return model, tea.Batch(resumeCmd, cmd, r.updateWindowTitle(), r.announceFocusedTab())
+}
+
+func (r *Root) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var (
 		cmd  tea.Cmd
 		cmds []tea.Cmd
 	)
 
+	// collect key presses of multi key bindings, the completed sequence is handled like a single key press
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && r.isKeySequenceEnabled() {
+		seqMsg, pass, cmd := r.keySequencer.handle(keyMsg)
+		if !pass {
+			return r, cmd
+		}
+
+		msg = seqMsg
+		cmds = append(cmds, cmd)
+	}
+
+	if replayed, ok := msg.(replayedKeyMessage); ok {
+		msg = replayed.KeyMsg
+	}
+
 	switch msg := msg.(type) {
+	case keySequenceTimeoutMessage:
+		return r, r.keySequencer.timeout(msg.id)
 	case persistedDataLoadedMessage:
 		return r, r.handlePersistedDataLoaded(msg)
 	case imageCleanupTickMessage:
 		io.WriteString(os.Stdout, msg.deletionCommand)
 		return r, r.imageCleanUpCommand()
-	case joinChannelMessage:
-		r.screenType = mainScreen
-
-		nTab, cmd := r.createTab(msg.account, msg.channel, msg.tabKind)
-		nTab.Focus()
-
-		r.tabs = append(r.tabs, nTab)
-
-		r.tabCursor = len(r.tabs) - 1 // set index to the newest tab
-		r.header.SelectTab(nTab.ID())
-
-		r.joinInput.blur()
-		r.joinInput.input.SetSuggestions(nil) // free up some memory
-
-		r.handleResize()
+	case terminalSequenceMessage:
+		_, _ = io.WriteString(os.Stdout, msg.sequence)
+		return r, nil
+	case relativeTimestampTickMessage:
+		// schedule the next tick, the message itself is passed to all tabs to re-render their timestamps
+		cmds = append(cmds, relativeTimestampTickCommand(r.dependencies.UserConfig.Settings.Timestamps))
 
-		return r, tea.Batch(nTab.Init(), cmd)
-	case wspool.IRCEvent:
-		// Handle IRC events from the connection pool
-		if msg.Error != nil {
-			// Connection error - display as notice in all tabs for this account
-			errEvt := r.buildChatEventMessage(msg.AccountID, "", ircConnectionError{err: msg.Error}, false)
-			for i := range r.tabs {
-				r.tabs[i], cmd = r.tabs[i].Update(errEvt)
-				cmds = append(cmds, cmd)
-			}
+		// the timestamps are re-rendered once the user is back
+		if r.idle.idle {
 			return r, tea.Batch(cmds...)
 		}
+	case idleCheckTickMessage:
+		if r.idle.check(time.Now(), r.dependencies.UserConfig.Settings.Idle.Timeout) {
+			cmds = append(cmds, r.handleIdleChanged(true))
+		}
 
-		// Log private messages
-		if privateMsg, ok := msg.Message.(*twitchirc.PrivateMessage); ok {
-			r.messageLoggerChan <- privateMsg.Clone()
+		return r, tea.Batch(append(cmds, idleCheckTickCommand())...)
+	case chatThrottleFlushMessage:
+		if summary := r.throttle.flush(msg.accountID, msg.channel, msg.second, time.Now()); summary != nil {
+			return r, r.forwardChatEvent(r.buildChatEventMessage(msg.accountID, "", summary, false))
 		}
 
-		// Build and forward event to tabs
-		evt := r.buildChatEventMessage(msg.AccountID, "", msg.Message, false)
-		for i := range r.tabs {
-			r.tabs[i], cmd = r.tabs[i].Update(evt)
-			cmds = append(cmds, cmd)
+		return r, nil
+	case joinChannelMessage:
+		return r, r.openTab(msg.account, msg.channel, msg.tabKind)
+	case wspool.IRCEvent:
+		return r, r.handleIRCEvent(msg)
+	case wspool.IRCEventBatch:
+		// all events of the batch are applied in one update, so the UI is only rendered once
+		for _, event := range msg {
+			cmds = append(cmds, r.handleIRCEvent(event))
 		}
+
 		return r, tea.Batch(cmds...)
+	case ipcRequestMessage:
+		resp, cmd := r.handleIPCRequest(msg.request)
+		msg.reply <- resp
+		return r, cmd
 	case chatEventMessage:
 		// Handle locally-generated chat events (e.g., from recent messages)
+		if msg.hidden {
+			return r, nil
+		}
+
 		for i := range r.tabs {
 			if msg.tabID != "" && msg.tabID != r.tabs[i].ID() {
 				continue
@@ -357,6 +522,19 @@ func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 		return r, tea.Batch(cmds...)
+	case setThemeMessage:
+		return r, r.handleSetTheme(msg)
+	case configReloadTickMessage:
+		return r, configReloadTickCommand(r.dependencies.ConfigSource)
+	case configReloadedMessage:
+		return r, tea.Batch(r.handleConfigReloaded(msg), configReloadTickCommand(r.dependencies.ConfigSource))
+	case settingWrittenMessage:
+		if r.settingsEditor == nil {
+			return r, nil
+		}
+
+		r.settingsEditor, cmd = r.settingsEditor.Update(msg)
+		return r, cmd
 	case requestLocalMessageHandleMessage:
 		return r, func() tea.Msg {
 			return r.buildChatEventMessage(msg.accountID, msg.tabID, msg.message, true)
@@ -374,8 +552,117 @@ func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return r, tea.Batch(cmds...)
 	case polledStreamInfoMessage:
 		return r, r.handlePolledStreamInfo(msg)
+	case emoteUpdateTickMessage:
+		return r, r.reloadEmotes()
+	case emoteUpdatesMessage:
+		return r, r.handleEmoteUpdates(msg)
+	case goLiveStreamsMessage, goLiveRefreshMessage, goLiveToastExpiredMessage:
+		return r, r.goLive.Update(msg)
+	case trayCycleMessage:
+		return r, r.tray.Update(msg)
+	case followedSidebarDataMessage, followedSidebarRefreshMessage:
+		r.sidebar, cmd = r.sidebar.Update(msg)
+		return r, cmd
 	case appStateSaveMessage:
 		return r, r.tickSaveAppState()
+	case obsStateChangedMessage:
+		// the status bar shows the new state on the next render
+		return r, nil
+	case statsTickMessage:
+		return r, r.stats.Update(msg)
+	case watchtimeTickMessage:
+		return r, r.trackWatchtime(time.Now())
+	case userBlockChangedMessage:
+		if r.settingsEditor != nil {
+			r.settingsEditor, cmd = r.settingsEditor.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+
+		for i := range r.tabs {
+			r.tabs[i], cmd = r.tabs[i].Update(msg)
+			cmds = append(cmds, cmd)
+		}
+
+		return r, tea.Batch(cmds...)
+	case notesChangedMessage:
+		for i := range r.tabs {
+			r.tabs[i], cmd = r.tabs[i].Update(msg)
+			cmds = append(cmds, cmd)
+		}
+
+		return r, tea.Batch(cmds...)
+	case openChatSettingsMessage:
+		if r.screenType != mainScreen {
+			return r, nil
+		}
+
+		return r, r.openChatSettings(msg)
+	case chatSettingsLoadedMessage:
+		if r.chatSettings == nil {
+			return r, nil
+		}
+
+		r.chatSettings, cmd = r.chatSettings.Update(msg)
+		return r, cmd
+	case openThreadMessage:
+		if r.screenType != mainScreen {
+			return r, nil
+		}
+
+		return r, r.openThread(msg)
+	case threadHistoryLoadedMessage:
+		if r.thread == nil {
+			return r, nil
+		}
+
+		r.thread, cmd = r.thread.Update(msg)
+		return r, cmd
+	case openRedemptionQueueMessage:
+		if r.screenType != mainScreen {
+			return r, nil
+		}
+
+		return r, r.openRedemptionQueue(msg)
+	case redemptionsLoadedMessage, redemptionUpdatedMessage:
+		// redemptions fulfilled after the queue was closed are removed by EventSub
+		if r.redemptionQueue == nil {
+			return r, nil
+		}
+
+		r.redemptionQueue, cmd = r.redemptionQueue.Update(msg)
+		return r, cmd
+	case openChatStatsMessage:
+		if r.screenType != mainScreen {
+			return r, nil
+		}
+
+		r.openChatStats(msg)
+		return r, nil
+	case openPollConfirmMessage:
+		if r.screenType != mainScreen {
+			return r, nil
+		}
+
+		r.openPollConfirm(msg)
+		return r, nil
+	case updateAvailableMessage:
+		r.updateNotice = &msg.release
+		r.handleResize()
+		return r, nil
+	case streamPlayerExitedMessage:
+		if msg.tabID == "" {
+			r.players = slices.DeleteFunc(r.players, func(p *exec.Cmd) bool { return p == msg.player })
+			return r, nil
+		}
+	case debugLogTickMessage:
+		if r.debugLog == nil {
+			return r, nil
+		}
+
+		r.debugLog, cmd = r.debugLog.Update(msg)
+		return r, cmd
+	case tea.MouseMsg:
+		return r, r.handleMouse(msg)
 	case tea.WindowSizeMsg:
 		r.width = msg.Width
 		r.height = msg.Height
@@ -390,6 +677,158 @@ func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return r, tea.Batch(cmds...)
 		}
 
+		if r.screenType == mainScreen && key.Matches(msg, r.dependencies.Keymap.ToggleFollowedSidebar) {
+			isInsertMode := len(r.tabs) > r.tabCursor && (r.tabs[r.tabCursor].State() == insertMode || r.tabs[r.tabCursor].State() == userInspectInsertMode)
+			if !isInsertMode {
+				return r, r.toggleFollowedSidebar()
+			}
+		}
+
+		if r.screenType == settingsScreen {
+			// escape closes the editor, unless a value is being edited
+			if key.Matches(msg, r.dependencies.Keymap.Escape) && !r.settingsEditor.editing {
+				r.closeSettingsEditor()
+				return r, nil
+			}
+
+			r.settingsEditor, cmd = r.settingsEditor.Update(msg)
+			return r, cmd
+		}
+
+		if r.screenType == debugLogScreen {
+			if key.Matches(msg, r.dependencies.Keymap.Escape) || key.Matches(msg, r.dependencies.Keymap.DebugLog) {
+				r.closeDebugLog()
+				return r, nil
+			}
+
+			r.debugLog, cmd = r.debugLog.Update(msg)
+			return r, cmd
+		}
+
+		if r.screenType == chatSettingsScreen {
+			// escape closes the editor, unless a value is being edited
+			if key.Matches(msg, r.dependencies.Keymap.Escape) && !r.chatSettings.editing {
+				r.closeChatSettings()
+				return r, nil
+			}
+
+			r.chatSettings, cmd = r.chatSettings.Update(msg)
+			return r, cmd
+		}
+
+		if r.screenType == threadScreen {
+			if key.Matches(msg, r.dependencies.Keymap.Escape) || key.Matches(msg, r.dependencies.Keymap.OpenThread) {
+				r.closeThread()
+				return r, nil
+			}
+
+			r.thread, cmd = r.thread.Update(msg)
+			return r, cmd
+		}
+
+		if r.screenType == pollConfirmScreen {
+			switch {
+			case key.Matches(msg, r.dependencies.Keymap.Confirm):
+				cmd = r.pollConfirm.create()
+				r.closePollConfirm()
+				return r, cmd
+			case key.Matches(msg, r.dependencies.Keymap.Escape):
+				r.closePollConfirm()
+			}
+
+			return r, nil
+		}
+
+		if r.screenType == chatStatsScreen {
+			if key.Matches(msg, r.dependencies.Keymap.Escape) {
+				r.closeChatStats()
+			}
+
+			return r, nil
+		}
+
+		if r.screenType == redemptionQueueScreen {
+			if key.Matches(msg, r.dependencies.Keymap.Escape) {
+				r.closeRedemptionQueue()
+				return r, nil
+			}
+
+			r.redemptionQueue, cmd = r.redemptionQueue.Update(msg)
+			return r, cmd
+		}
+
+		if r.screenType == mainScreen && key.Matches(msg, r.dependencies.Keymap.DebugLog) {
+			isInsertMode := len(r.tabs) > r.tabCursor && r.tabs[r.tabCursor].IsTyping()
+			if !isInsertMode && !r.sidebar.focused {
+				return r, r.openDebugLog()
+			}
+		}
+
+		if r.screenType == mainScreen && r.dependencies.Sounds != nil && key.Matches(msg, r.dependencies.Keymap.ToggleSounds) && !r.sidebar.focused {
+			return r, r.toggleSounds()
+		}
+
+		if r.screenType == mainScreen && key.Matches(msg, r.dependencies.Keymap.ToggleStats) {
+			isInsertMode := len(r.tabs) > r.tabCursor && r.tabs[r.tabCursor].IsTyping()
+			if !isInsertMode && !r.sidebar.focused {
+				return r, r.stats.toggle()
+			}
+		}
+
+		if r.screenType == mainScreen && len(r.goLive.toasts) > 0 && !r.sidebar.focused {
+			isInsertMode := len(r.tabs) > r.tabCursor && r.tabs[r.tabCursor].IsTyping()
+
+			switch {
+			case isInsertMode:
+			case key.Matches(msg, r.dependencies.Keymap.OpenLiveChannel):
+				return r, r.openLiveChannel()
+			case key.Matches(msg, r.dependencies.Keymap.WatchLiveChannel):
+				return r, r.watchLiveChannel()
+			}
+		}
+
+		if r.screenType == mainScreen && len(r.tray.notifications) > 0 && !r.sidebar.focused {
+			isInsertMode := len(r.tabs) > r.tabCursor && r.tabs[r.tabCursor].IsTyping()
+
+			switch {
+			case isInsertMode:
+			case key.Matches(msg, r.dependencies.Keymap.JumpToNotification):
+				return r, r.jumpToNotification()
+			case key.Matches(msg, r.dependencies.Keymap.DismissNotification):
+				r.dismissNotification()
+				return r, nil
+			}
+		}
+
+		if r.screenType == mainScreen && r.updateNotice != nil && key.Matches(msg, r.dependencies.Keymap.DismissNotice) {
+			isInsertMode := len(r.tabs) > r.tabCursor && r.tabs[r.tabCursor].IsTyping()
+			if !isInsertMode && !r.sidebar.focused {
+				return r, r.dismissUpdateNotice()
+			}
+		}
+
+		if r.screenType == mainScreen && key.Matches(msg, r.dependencies.Keymap.Settings) {
+			isInsertMode := len(r.tabs) > r.tabCursor && r.tabs[r.tabCursor].IsTyping()
+			if !isInsertMode && !r.sidebar.focused {
+				r.openSettingsEditor()
+				return r, nil
+			}
+		}
+
+		// while the sidebar is focused, it receives all key presses
+		if r.screenType == mainScreen && r.sidebar.focused {
+			if key.Matches(msg, r.dependencies.Keymap.Escape) {
+				r.sidebar.blur()
+				if len(r.tabs) > r.tabCursor {
+					r.tabs[r.tabCursor].Focus()
+				}
+				return r, nil
+			}
+
+			r.sidebar, cmd = r.sidebar.Update(msg)
+			return r, cmd
+		}
+
 		if key.Matches(msg, r.dependencies.Keymap.Help) {
 			var isInsertMode bool
 			if len(r.tabs) > r.tabCursor {
@@ -466,6 +905,20 @@ func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					validTabKinds = append(validTabKinds, liveNotificationTabKind)
 				}
 
+				for _, kind := range []tabKind{youTubeTabKind, kickTabKind} {
+					if r.providerFor(kind) != nil {
+						validTabKinds = append(validTabKinds, kind)
+					}
+				}
+
+				hasInspectorTab := slices.ContainsFunc(r.tabs, func(t tab) bool {
+					return t.Kind() == ircInspectorTabKind
+				})
+
+				if !hasInspectorTab && r.dependencies.Traffic != nil {
+					validTabKinds = append(validTabKinds, ircInspectorTabKind)
+				}
+
 				r.joinInput.setTabOptions(validTabKinds...)
 				r.joinInput.focus()
 				return r, r.joinInput.Init()
@@ -501,54 +954,19 @@ func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				r.prevTab()
 			}
 
-			if key.Matches(msg, r.dependencies.Keymap.CloseTab) {
-				if len(r.tabs) > r.tabCursor && !(r.tabs[r.tabCursor].State() == insertMode || r.tabs[r.tabCursor].State() == userInspectInsertMode) {
-					currentTab := r.tabs[r.tabCursor]
-					r.closeTab()
-
-					// if tab was connected to IRC, disconnect it
-					if currentTab.IsDataLoaded() && currentTab.Kind() == broadcastTabKind {
-						cmds := make([]tea.Cmd, 0, 2)
-
-						// if there is another tab for the same channel and the same account
-						hasTabsSameAccountAndChannel := slices.ContainsFunc(r.tabs, func(t tab) bool {
-							return t.ID() != currentTab.ID() &&
-								t.AccountID() == currentTab.AccountID() &&
-								t.ChannelID() == currentTab.ChannelID()
-						})
-
-						hasTabsSameChannel := slices.ContainsFunc(r.tabs, func(t tab) bool {
-							return t.ID() != currentTab.ID() &&
-								t.ChannelID() == currentTab.ChannelID()
-						})
-
-						if !hasTabsSameAccountAndChannel {
-							// send part message
-							log.Logger.Info().Str("channel", currentTab.Channel()).Str("id", currentTab.AccountID()).Msg("sending part message")
-							accountID := currentTab.AccountID()
-							channel := currentTab.Channel()
-							cmds = append(cmds, func() tea.Msg {
-								r.dependencies.Pool.SendIRC(accountID, twitchirc.PartMessage{Channel: channel})
-								return nil
-							})
-						}
-
-						if !hasTabsSameChannel {
-							log.Logger.Info().Str("channel", currentTab.Channel()).Str("channel-id", currentTab.ChannelID()).Msg("removing emote cache entry for channel")
-							r.dependencies.EmoteCache.RemoveEmoteSetForChannel(currentTab.ChannelID())
-						}
-
-						// Disconnect IRC for this account
-						accountID := currentTab.AccountID()
-						cmds = append(cmds, func() tea.Msg {
-							r.dependencies.Pool.DisconnectIRC(accountID)
-							return nil
-						})
+			if key.Matches(msg, r.dependencies.Keymap.GoToTab) {
+				if len(r.tabs) > r.tabCursor && (r.tabs[r.tabCursor].State() == insertMode || r.tabs[r.tabCursor].State() == userInspectInsertMode) {
+					r.tabs[r.tabCursor], cmd = r.tabs[r.tabCursor].Update(msg)
+					return r, cmd
+				}
 
-						return r, tea.Sequence(cmds...)
-					}
+				// the n-th key of the binding selects the n-th tab
+				r.goToTab(slices.Index(r.dependencies.Keymap.GoToTab.Keys(), msg.String()))
+			}
 
-					return r, nil
+			if key.Matches(msg, r.dependencies.Keymap.CloseTab) {
+				if len(r.tabs) > r.tabCursor && !(r.tabs[r.tabCursor].State() == insertMode || r.tabs[r.tabCursor].State() == userInspectInsertMode) {
+					return r, r.closeTabAt(r.tabCursor)
 				}
 			}
 		}
@@ -576,70 +994,350 @@ func (r *Root) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (r *Root) View() string {
+	defer r.handlePanic()
+
 	if !r.hasLoadedSession {
 		return r.splash.ViewLoading()
 	}
 
 	switch r.screenType {
 	case mainScreen:
-		if len(r.tabs) == 0 {
-			return r.splash.View()
+		view := r.mainView()
+
+		if r.stats.visible {
+			view = overlay.Composite(r.stats.View(), view, overlay.Right, overlay.Top, 0, 0)
 		}
 
-		if r.dependencies.UserConfig.Settings.VerticalTabList {
-			// In vertical mode, render status bar separately at full width
-			mainContent := lipgloss.JoinHorizontal(lipgloss.Left, r.header.View(), r.tabs[r.tabCursor].ViewWithoutStatusBar())
-			statusBar := r.tabs[r.tabCursor].StatusBarView()
-			if statusBar != "" {
-				return mainContent + "\n" + statusBar
-			}
-			return mainContent
+		// go live notifications are shown above the status bar and the notification tray
+		if toasts := r.goLive.View(r.width); toasts != "" {
+			view = overlay.Composite(toasts, view, overlay.Right, overlay.Bottom, 0, -1-r.tray.height())
 		}
 
-		return r.header.View() + "\n" + r.tabs[r.tabCursor].View()
+		return view
 	case inputScreen:
 		// Composite join modal over the current active tab
-		var background string
-		if len(r.tabs) > 0 && r.tabCursor < len(r.tabs) {
-			if r.dependencies.UserConfig.Settings.VerticalTabList {
-				mainContent := lipgloss.JoinHorizontal(lipgloss.Left, r.header.View(), r.tabs[r.tabCursor].ViewWithoutStatusBar())
-				statusBar := r.tabs[r.tabCursor].StatusBarView()
-				if statusBar != "" {
-					background = mainContent + "\n" + statusBar
-				} else {
-					background = mainContent
-				}
-			} else {
-				background = r.header.View() + "\n" + r.tabs[r.tabCursor].View()
+		background := r.mainView()
+
+		// Dim the background for modal effect
+		dimmedBackground := lipgloss.NewStyle().
+			Faint(true).
+			Render(background)
+
+		foreground := r.joinInput.View()
+
+		return overlay.Composite(
+			foreground,
+			dimmedBackground,
+			overlay.Center,
+			overlay.Center,
+			0,
+			0,
+		)
+	case helpScreen:
+		return r.help.View()
+	case settingsScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.settingsEditor.View(), background, overlay.Center, overlay.Center, 0, 0)
+	case debugLogScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.debugLog.View(), background, overlay.Center, overlay.Center, 0, 0)
+	case chatSettingsScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.chatSettings.View(), background, overlay.Center, overlay.Center, 0, 0)
+	case threadScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.thread.View(), background, overlay.Center, overlay.Center, 0, 0)
+	case redemptionQueueScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.redemptionQueue.View(), background, overlay.Center, overlay.Center, 0, 0)
+	case pollConfirmScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.pollConfirm.View(), background, overlay.Center, overlay.Center, 0, 0)
+	case chatStatsScreen:
+		background := lipgloss.NewStyle().Faint(true).Render(r.mainView())
+		return overlay.Composite(r.chatStats.View(), background, overlay.Center, overlay.Center, 0, 0)
+	}
+
+	return ""
+}
+
+// tabsView renders the tab header together with the active tab, or the splash screen when no tab is open
+func (r *Root) tabsView() string {
+	if len(r.tabs) == 0 || r.tabCursor >= len(r.tabs) {
+		return r.splash.View()
+	}
+
+	if r.dependencies.UserConfig.Settings.VerticalTabList {
+		// In vertical mode, render status bar separately at full width
+		mainContent := lipgloss.JoinHorizontal(lipgloss.Left, r.header.View(), r.tabs[r.tabCursor].ViewWithoutStatusBar())
+		statusBar := r.tabs[r.tabCursor].StatusBarView()
+		if statusBar != "" {
+			return mainContent + "\n" + statusBar
+		}
+		return mainContent
+	}
+
+	return r.header.View() + "\n" + r.tabs[r.tabCursor].View()
+}
+
+// mainView renders the tabs with the sidebar, below the update notice if a newer release is available
+// and above the notification tray if it is enabled
+func (r *Root) mainView() string {
+	content := r.withSidebarView(r.tabsView())
+	if r.tray.height() > 0 {
+		content += "\n" + r.tray.View(r.width)
+	}
+
+	if r.updateNotice == nil {
+		return content
+	}
+
+	return r.updateNoticeView() + "\n" + content
+}
+
+func (r *Root) withSidebarView(content string) string {
+	if !r.sidebar.visible {
+		return content
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, r.sidebar.View(), content)
+}
+
+// toggleFollowedSidebar cycles through showing and focusing the sidebar, focusing an already visible sidebar and hiding it
+func (r *Root) openSettingsEditor() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.settingsEditor = newSettingsEditor(r.width, r.height, r.dependencies)
+	r.screenType = settingsScreen
+}
+
+func (r *Root) closeSettingsEditor() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.settingsEditor = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) openDebugLog() tea.Cmd {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.debugLogOpened++
+	r.debugLog = newDebugLogViewer(r.width, r.height, r.debugLogOpened, r.dependencies)
+	r.screenType = debugLogScreen
+
+	return r.debugLog.tick()
+}
+
+func (r *Root) closeDebugLog() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.debugLog = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) openChatSettings(msg openChatSettingsMessage) tea.Cmd {
+	client, ok := r.dependencies.APIUserClients[msg.accountID].(chatSettingsClient)
+	if !ok {
+		return nil
+	}
+
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.chatSettings = newChatSettingsEditor(r.width, r.height, msg, client, r.dependencies)
+	r.screenType = chatSettingsScreen
+
+	return r.chatSettings.load()
+}
+
+func (r *Root) closeChatSettings() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.chatSettings = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) openThread(msg openThreadMessage) tea.Cmd {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.threadOpened++
+	r.thread = newThreadViewer(r.width, r.height, r.threadOpened, msg, r.dependencies)
+	r.screenType = threadScreen
+
+	return r.thread.loadHistory()
+}
+
+func (r *Root) closeThread() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.thread = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) openRedemptionQueue(msg openRedemptionQueueMessage) tea.Cmd {
+	client, ok := r.dependencies.APIUserClients[msg.accountID].(redemptionClient)
+	if !ok {
+		return nil
+	}
+
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.redemptionQueue = newRedemptionQueueView(r.width, r.height, msg, client, r.dependencies)
+	r.screenType = redemptionQueueScreen
+
+	return r.redemptionQueue.load()
+}
+
+func (r *Root) closeRedemptionQueue() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.redemptionQueue = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) openChatStats(msg openChatStatsMessage) {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.chatStats = newChatStatsView(r.width, msg, r.dependencies)
+	r.screenType = chatStatsScreen
+}
+
+func (r *Root) closeChatStats() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.chatStats = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) openPollConfirm(msg openPollConfirmMessage) {
+	client, ok := r.dependencies.APIUserClients[msg.accountID].(pollCreationClient)
+	if !ok {
+		return
+	}
+
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.pollConfirm = newPollConfirm(r.width, msg, client, r.dependencies)
+	r.screenType = pollConfirmScreen
+}
+
+func (r *Root) closePollConfirm() {
+	if len(r.tabs) > r.tabCursor {
+		r.tabs[r.tabCursor].Focus()
+	}
+
+	r.pollConfirm = nil
+	r.screenType = mainScreen
+}
+
+func (r *Root) toggleFollowedSidebar() tea.Cmd {
+	var cmd tea.Cmd
+
+	switch {
+	case !r.sidebar.visible:
+		cmd = r.sidebar.show()
+		r.sidebar.focus()
+	case !r.sidebar.focused:
+		r.sidebar.focus()
+	default:
+		r.sidebar.hide()
+	}
+
+	if len(r.tabs) > r.tabCursor {
+		if r.sidebar.focused {
+			r.tabs[r.tabCursor].Blur()
+		} else {
+			r.tabs[r.tabCursor].Focus()
+		}
+	}
+
+	r.handleResize()
+
+	return cmd
+}
+
+// handlePanic passes a panic of Update or View to the panic handler, Bubble Tea only prints its stack
+func (r *Root) handlePanic() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	if r.dependencies.OnPanic != nil {
+		r.dependencies.OnPanic(v, debug.Stack())
+	}
+
+	panic(v)
+}
+
+func (r *Root) HasSessionLoaded() bool {
+	return r.hasLoadedSession
+}
+
+func (r *Root) handleSetTheme(msg setThemeMessage) tea.Cmd {
+	themes := r.dependencies.UserConfig.Themes
+
+	theme, ok := themes.Themes[msg.name]
+	if !ok {
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     msg.tabID,
+				accountID: msg.accountID,
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       fmt.Sprintf("Unknown theme %q, available themes: %s", msg.name, strings.Join(themes.Names(), ", ")),
+				},
 			}
-		} else {
-			background = r.splash.View()
 		}
+	}
 
-		// Dim the background for modal effect
-		dimmedBackground := lipgloss.NewStyle().
-			Faint(true).
-			Render(background)
+	r.dependencies.UserConfig.Theme = theme
+	r.dependencies.UserConfig.Themes.Active = msg.name
 
-		foreground := r.joinInput.View()
+	return func() tea.Msg {
+		return themeChangedMessage{}
+	}
+}
 
-		return overlay.Composite(
-			foreground,
-			dimmedBackground,
-			overlay.Center,
-			overlay.Center,
-			0,
-			0,
-		)
-	case helpScreen:
-		return r.help.View()
+// StopPlayers stops the stream players started by the tabs and from go live notifications, called once the UI exited
+func (r *Root) StopPlayers() {
+	for _, p := range r.players {
+		stopPlayer(p)
 	}
 
-	return ""
-}
+	r.players = nil
 
-func (r *Root) HasSessionLoaded() bool {
-	return r.hasLoadedSession
+	for _, t := range r.tabs {
+		if t, ok := t.(*broadcastTab); ok && t.player != nil {
+			stopPlayer(t.player)
+			t.player = nil
+		}
+	}
 }
 
 func (r *Root) TakeStateSnapshot() save.AppState {
@@ -656,11 +1354,25 @@ func (r *Root) TakeStateSnapshot() save.AppState {
 		if t.Kind() == broadcastTabKind {
 			tabState.IsLocalUnique = t.(*broadcastTab).isUniqueOnlyChat
 			tabState.IsLocalSub = t.(*broadcastTab).isLocalSub
+
+			tabState.Draft = t.(*broadcastTab).Draft()
+			tabState.LastReadID = t.(*broadcastTab).lastReadMessageID()
+			tabState.HangulInput = t.(*broadcastTab).hangulInput
+
+			if !r.dependencies.UserConfig.Settings.Session.SharedInputHistory {
+				tabState.InputHistory = t.(*broadcastTab).inputHistory.Entries()
+			}
 		}
 
 		appState.Tabs = append(appState.Tabs, tabState)
 	}
 
+	appState.Tabs = append(appState.Tabs, r.unopenedTabs...)
+
+	if r.dependencies.UserConfig.Settings.Session.SharedInputHistory {
+		appState.InputHistory = r.sharedInputHistory.Entries()
+	}
+
 	return appState
 }
 
@@ -679,6 +1391,43 @@ func (r *Root) tickSaveAppState() tea.Cmd {
 }
 
 func (r *Root) tickPollStreamInfos() tea.Cmd {
+	// offline the stream infos can't be fetched
+	if r.dependencies.Offline {
+		return nil
+	}
+
+	interval := r.dependencies.UserConfig.Settings.StreamInfo.RefreshInterval
+
+	// the stream infos are refreshed once the user is back
+	if r.idle.idle {
+		r.streamInfoSkipped = true
+
+		return tea.Tick(interval, func(_ time.Time) tea.Msg {
+			return polledStreamInfoMessage{}
+		})
+	}
+
+	fetch := r.fetchStreamInfos()
+
+	return tea.Tick(interval, func(_ time.Time) tea.Msg {
+		return fetch()
+	})
+}
+
+// refreshStreamInfos fetches the stream infos right away, without scheduling the next refresh
+func (r *Root) refreshStreamInfos() tea.Cmd {
+	fetch := r.fetchStreamInfos()
+
+	return func() tea.Msg {
+		polled := fetch()
+		polled.once = true
+
+		return polled
+	}
+}
+
+// fetchStreamInfos returns a function fetching the stream infos of all open channels, it runs outside of the update loop
+func (r *Root) fetchStreamInfos() func() polledStreamInfoMessage {
 	clients := maps.Clone(r.dependencies.APIUserClients)
 
 	// collect all open broadcasters
@@ -694,9 +1443,9 @@ func (r *Root) tickPollStreamInfos() tea.Cmd {
 	}
 
 	if len(openBroadcasts) == 0 {
-		return tea.Tick(time.Second*90, func(_ time.Time) tea.Msg {
+		return func() polledStreamInfoMessage {
 			return polledStreamInfoMessage{}
-		})
+		}
 	}
 
 	broadcastIDs := []string{}
@@ -704,7 +1453,7 @@ func (r *Root) tickPollStreamInfos() tea.Cmd {
 		broadcastIDs = append(broadcastIDs, broadcast)
 	}
 
-	return tea.Tick(time.Second*90, func(_ time.Time) tea.Msg {
+	return func() polledStreamInfoMessage {
 		accounts, err := r.dependencies.AccountProvider.GetAllAccounts()
 		if err != nil {
 			return polledStreamInfoMessage{}
@@ -757,13 +1506,14 @@ func (r *Root) tickPollStreamInfos() tea.Cmd {
 				info.title = resp.Data[id].Title
 				info.game = resp.Data[id].GameName
 				info.isLive = !resp.Data[id].StartedAt.IsZero()
+				info.startedAt = resp.Data[id].StartedAt
 			}
 
 			polled.streamInfos = append(polled.streamInfos, info)
 		}
 
 		return polled
-	})
+	}
 }
 
 func (r *Root) handlePolledStreamInfo(polled polledStreamInfoMessage) tea.Cmd {
@@ -781,7 +1531,11 @@ func (r *Root) handlePolledStreamInfo(polled polledStreamInfoMessage) tea.Cmd {
 		}
 	}
 
-	cmds = append(cmds, r.tickPollStreamInfos())
+	// a refresh after the user was idle does not schedule another refresh
+	if !polled.once {
+		cmds = append(cmds, r.tickPollStreamInfos())
+	}
+
 	return tea.Batch(cmds...)
 }
 
@@ -798,17 +1552,38 @@ func (r *Root) createTab(account save.Account, channel string, kind tabKind) (ta
 
 		headerHeight := r.getHeaderHeight()
 
-		nTab := newBroadcastTab(id, r.width, r.height-headerHeight, account, channel, r.dependencies)
+		nTab := newBroadcastTab(id, r.width, r.contentHeight()-headerHeight, account, channel, r.dependencies)
+		if r.dependencies.UserConfig.Settings.Session.SharedInputHistory {
+			nTab.inputHistory = r.sharedInputHistory
+		}
+
+		r.applyUnopenedTab(nTab)
 		return nTab, cmd
 	case mentionTabKind:
 		id, cmd := r.header.AddTab("mentioned", "all")
 		headerHeight := r.getHeaderHeight()
-		nTab := newMentionTab(id, r.width, r.height-headerHeight, r.dependencies)
+		nTab := newMentionTab(id, r.width, r.contentHeight()-headerHeight, r.dependencies)
 		return nTab, cmd
 	case liveNotificationTabKind:
 		id, cmd := r.header.AddTab("live notifications", "all")
 		headerHeight := r.getHeaderHeight()
-		nTab := newLiveNotificationTab(id, r.width, r.height-headerHeight, r.dependencies)
+		nTab := newLiveNotificationTab(id, r.width, r.contentHeight()-headerHeight, r.dependencies)
+		return nTab, cmd
+	case ircInspectorTabKind:
+		id, cmd := r.header.AddTab("irc inspector", "all")
+		headerHeight := r.getHeaderHeight()
+		nTab := newIRCInspectorTab(id, r.width, r.contentHeight()-headerHeight, r.dependencies)
+		return nTab, cmd
+	case replayTabKind:
+		id, cmd := r.header.AddTab(r.dependencies.Replay.Name, "replay")
+		headerHeight := r.getHeaderHeight()
+		nTab := newReplayTab(id, r.width, r.contentHeight()-headerHeight, r.dependencies.Replay, r.dependencies)
+		return nTab, cmd
+	case youTubeTabKind, kickTabKind:
+		provider := r.providerFor(kind)
+		id, cmd := r.header.AddTab(channel, provider.Name())
+		headerHeight := r.getHeaderHeight()
+		nTab := newProviderTab(id, r.width, r.contentHeight()-headerHeight, kind, provider, channel, r.dependencies)
 		return nTab, cmd
 	}
 
@@ -817,15 +1592,81 @@ func (r *Root) createTab(account save.Account, channel string, kind tabKind) (ta
 	return nil, nil
 }
 
+// providerFor returns the provider of the chats of a tab kind of another platform, nil if the platform is not configured
+func (r *Root) providerFor(kind tabKind) chatprovider.Provider {
+	switch {
+	case kind == youTubeTabKind && r.dependencies.YouTube != nil:
+		return r.dependencies.YouTube
+	case kind == kickTabKind && r.dependencies.Kick != nil:
+		return r.dependencies.Kick
+	}
+
+	return nil
+}
+
+// applyUnopenedTab applies the draft and input history of a saved tab of the same channel and account, which was not restored
+func (r *Root) applyUnopenedTab(t *broadcastTab) {
+	i := slices.IndexFunc(r.unopenedTabs, func(s save.TabState) bool {
+		return s.IdentityID == t.AccountID() && s.Channel == t.Channel()
+	})
+
+	if i == -1 {
+		return
+	}
+
+	state := r.unopenedTabs[i]
+	r.unopenedTabs = slices.Delete(r.unopenedTabs, i, i+1)
+
+	t.draft = state.Draft
+	if !r.dependencies.UserConfig.Settings.Session.SharedInputHistory {
+		t.inputHistory = component.NewInputHistory(r.dependencies.UserConfig.Settings.Session.InputHistorySize, state.InputHistory)
+	}
+}
+
+// openTab creates a new tab and focuses it
+func (r *Root) openTab(account save.Account, channel string, kind tabKind) tea.Cmd {
+	r.screenType = mainScreen
+
+	nTab, cmd := r.createTab(account, channel, kind)
+	nTab.Focus()
+
+	r.tabs = append(r.tabs, nTab)
+
+	r.tabCursor = len(r.tabs) - 1 // set index to the newest tab
+	r.header.SelectTab(nTab.ID())
+
+	r.joinInput.blur()
+	r.joinInput.input.SetSuggestions(nil) // free up some memory
+
+	r.handleResize()
+
+	return tea.Batch(nTab.Init(), cmd)
+}
+
 func (r *Root) getHeaderHeight() int {
 	headerView := r.header.View()
 	return lipgloss.Height(headerView)
 }
 
+// contentHeight is the height available to the tabs, the sidebar and the splash screen
+func (r *Root) contentHeight() int {
+	return max(1, r.height-r.updateNoticeHeight()-r.tray.height())
+}
+
 func (r *Root) handleResize() {
+	// the update notice takes the first line, the notification tray the last line
+	height := r.contentHeight()
+
+	// followed sidebar takes a fixed width on the left side
+	width := r.width
+	if r.sidebar.visible {
+		r.sidebar.setHeight(height)
+		width = max(1, r.width-r.sidebar.width)
+	}
+
 	// splash screen
-	r.splash.width = r.width
-	r.splash.height = r.height
+	r.splash.width = width
+	r.splash.height = height
 
 	// channel join input
 	r.joinInput.handleResize(r.width, r.height)
@@ -833,30 +1674,58 @@ func (r *Root) handleResize() {
 	// help
 	r.help.handleResize(r.width, r.height)
 
+	if r.settingsEditor != nil {
+		r.settingsEditor.handleResize(r.width, r.height)
+	}
+
+	if r.debugLog != nil {
+		r.debugLog.handleResize(r.width, r.height)
+	}
+
+	if r.chatSettings != nil {
+		r.chatSettings.handleResize(r.width, r.height)
+	}
+
+	if r.thread != nil {
+		r.thread.handleResize(r.width, r.height)
+	}
+
+	if r.redemptionQueue != nil {
+		r.redemptionQueue.handleResize(r.width, r.height)
+	}
+
+	if r.pollConfirm != nil {
+		r.pollConfirm.handleResize(r.width)
+	}
+
+	if r.chatStats != nil {
+		r.chatStats.handleResize(r.width)
+	}
+
 	if r.dependencies.UserConfig.Settings.VerticalTabList {
 		minWidth := r.header.MinWidth()
-		r.header.Resize(minWidth, r.height)
+		r.header.Resize(minWidth, height)
 
 		headerWidth := lipgloss.Width(r.header.View())
 		headerHeight := lipgloss.Height(r.header.View())
 
 		for i := range r.tabs {
 			// Tab height matches header height (status bar is rendered separately below both)
-			r.tabs[i].SetSize(r.width-headerWidth, headerHeight)
-			r.tabs[i].SetFullWidth(r.width) // for status bar to span full width
+			r.tabs[i].SetSize(width-headerWidth, headerHeight)
+			r.tabs[i].SetFullWidth(width) // for status bar to span full width
 			r.tabs[i].HandleResize()
 		}
 
 		return
 	} else {
-		r.header.Resize(r.width-3, 0) // one placeholder space foreach side
+		r.header.Resize(width-3, 0) // one placeholder space foreach side
 	}
 
 	// tab
 	headerHeight := r.getHeaderHeight()
 
 	for i := range r.tabs {
-		r.tabs[i].SetSize(r.width, r.height-headerHeight)
+		r.tabs[i].SetSize(width, height-headerHeight)
 		r.tabs[i].HandleResize()
 	}
 }
@@ -903,6 +1772,29 @@ func (r *Root) prevTab() {
 	}
 }
 
+// isKeySequenceEnabled reports if key presses may start a key sequence, they must not while typing text
+func (r *Root) isKeySequenceEnabled() bool {
+	if !r.keySequencer.enabled() || !r.hasLoadedSession || r.screenType != mainScreen || r.sidebar.focused {
+		return false
+	}
+
+	return len(r.tabs) <= r.tabCursor || !r.tabs[r.tabCursor].IsTyping()
+}
+
+func (r *Root) goToTab(index int) {
+	if index < 0 || index >= len(r.tabs) || index == r.tabCursor {
+		return
+	}
+
+	if len(r.tabs) > r.tabCursor && r.tabCursor > -1 {
+		r.tabs[r.tabCursor].Blur()
+	}
+
+	r.tabCursor = index
+	r.header.SelectTab(r.tabs[r.tabCursor].ID())
+	r.tabs[r.tabCursor].Focus()
+}
+
 func (r *Root) handlePersistedDataLoaded(msg persistedDataLoadedMessage) tea.Cmd {
 	cmds := make([]tea.Cmd, 0, len(msg.state.Tabs))
 	r.hasLoadedSession = true
@@ -912,9 +1804,33 @@ func (r *Root) handlePersistedDataLoaded(msg persistedDataLoadedMessage) tea.Cmd
 		return nil
 	}
 
+	sessionSettings := r.dependencies.UserConfig.Settings.Session
+	if sessionSettings.SharedInputHistory {
+		// fall back to the histories of all tabs, when the shared history was previously disabled
+		entries := msg.state.InputHistory
+		if len(entries) == 0 {
+			for _, t := range msg.state.Tabs {
+				entries = append(entries, t.InputHistory...)
+			}
+		}
+
+		r.sharedInputHistory = component.NewInputHistory(sessionSettings.InputHistorySize, entries)
+	}
+
 	// restore tabs
 	var hasActiveTab bool
 	for _, t := range msg.state.Tabs {
+		// tabs which are not restored keep their drafts and input histories for the next time the channel is opened
+		if !sessionSettings.RestoreTabs || t.Unopened {
+			if tabKind(t.Kind) == broadcastTabKind && (t.Draft != "" || len(t.InputHistory) > 0) {
+				t.IsFocused = false
+				t.Unopened = true
+				r.unopenedTabs = append(r.unopenedTabs, t)
+			}
+
+			continue
+		}
+
 		r.screenType = mainScreen
 
 		var (
@@ -938,6 +1854,13 @@ func (r *Root) handlePersistedDataLoaded(msg persistedDataLoadedMessage) tea.Cmd
 			newTab, cmd = r.createTab(account, t.Channel, broadcastTabKind)
 			newTab.(*broadcastTab).isUniqueOnlyChat = t.IsLocalUnique
 			newTab.(*broadcastTab).isLocalSub = t.IsLocalSub
+			newTab.(*broadcastTab).draft = t.Draft
+			newTab.(*broadcastTab).restoredLastReadID = t.LastReadID
+			newTab.(*broadcastTab).hangulInput = t.HangulInput
+
+			if !sessionSettings.SharedInputHistory {
+				newTab.(*broadcastTab).inputHistory = component.NewInputHistory(sessionSettings.InputHistorySize, t.InputHistory)
+			}
 		case mentionTabKind:
 			// don't load mention tab, when there are no longer any non-anonymous accounts
 			hasNormalAccount := slices.ContainsFunc(r.dependencies.Accounts, func(e save.Account) bool {
@@ -951,6 +1874,21 @@ func (r *Root) handlePersistedDataLoaded(msg persistedDataLoadedMessage) tea.Cmd
 			newTab, cmd = r.createTab(save.Account{}, "", mentionTabKind)
 		case liveNotificationTabKind:
 			newTab, cmd = r.createTab(save.Account{}, "", liveNotificationTabKind)
+		case ircInspectorTabKind:
+			if r.dependencies.Traffic == nil {
+				continue
+			}
+
+			newTab, cmd = r.createTab(save.Account{}, "", ircInspectorTabKind)
+		case youTubeTabKind, kickTabKind:
+			// don't load tabs of platforms which are no longer configured
+			if r.providerFor(tabKind(t.Kind)) == nil || t.Channel == "" {
+				continue
+			}
+
+			newTab, cmd = r.createTab(save.Account{}, t.Channel, tabKind(t.Kind))
+		default:
+			continue
 		}
 
 		cmds = append(cmds, cmd)
@@ -980,12 +1918,128 @@ func (r *Root) handlePersistedDataLoaded(msg persistedDataLoadedMessage) tea.Cmd
 
 	r.handleResize()
 
+	for _, warning := range r.dependencies.Warnings {
+		cmds = append(cmds, r.focusedTabNotice(warning))
+	}
+
+	// the session is not saved while replaying, so the tabs of the previous session are kept
+	if r.dependencies.Replay != nil {
+		cmds = append(cmds, r.openTab(save.Account{}, "", replayTabKind))
+		return tea.Batch(cmds...)
+	}
+
 	// initial app state tick
 	cmds = append(cmds, r.tickSaveAppState())
 
 	return tea.Batch(cmds...)
 }
 
+// handleIRCEvent logs the message, fires the hooks and forwards the message to the tabs
+func (r *Root) handleIRCEvent(msg wspool.IRCEvent) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	if msg.Error != nil {
+		// Connection error - display as notice in all tabs for this account
+		errEvt := r.buildChatEventMessage(msg.AccountID, "", ircConnectionError{err: msg.Error}, false)
+		for i := range r.tabs {
+			r.tabs[i], cmd = r.tabs[i].Update(errEvt)
+			cmds = append(cmds, cmd)
+		}
+		return tea.Batch(cmds...)
+	}
+
+	// joins and parts only change the chatters of small channels, they are no chat messages
+	switch msg.Message.(type) {
+	case *twitchirc.Membership, *twitchirc.Names:
+		return r.forwardPresence(msg.AccountID, msg.Message)
+	}
+
+	// Log private messages
+	privateMsg, isPrivateMsg := msg.Message.(*twitchirc.PrivateMessage)
+	if isPrivateMsg {
+		metrics.ChatMessages.With(privateMsg.ChannelUserName).Inc()
+		r.messageLoggerChan <- privateMsg.Clone()
+	}
+
+	if r.dependencies.Hooks != nil || r.dependencies.Sounds != nil {
+		events := hook.EventsFromIRC(msg.Message, r.mentionNames())
+		for i, e := range events {
+			events[i].Bot = r.isBot(e.ChannelID, e.UserID, e.User)
+		}
+
+		if r.dependencies.Hooks != nil {
+			r.dependencies.Hooks.Fire(events...)
+		}
+
+		// messages of the own accounts don't play sounds, they would mention the account itself
+		if r.dependencies.Sounds != nil && !r.isOwnEvent(events) {
+			r.dependencies.Sounds.Play(events...)
+		}
+	}
+
+	// skip messages of busy chats, they are still logged and passed to hooks
+	if isPrivateMsg {
+		now := time.Now()
+		show, summary := r.throttle.add(msg.AccountID, privateMsg.ChannelUserName, r.dependencies.UserConfig.Settings.Chat.MaxMessagesPerSecond, r.isThrottleExempt(msg.AccountID, privateMsg), now)
+		if summary != nil {
+			cmds = append(cmds, r.forwardChatEvent(r.buildChatEventMessage(msg.AccountID, "", summary, false)))
+		}
+
+		if !show {
+			return tea.Batch(append(cmds, r.throttle.flushCommand(msg.AccountID, privateMsg.ChannelUserName, now))...)
+		}
+	}
+
+	// Build and forward event to tabs
+	evt := r.buildChatEventMessage(msg.AccountID, "", msg.Message, false)
+	if isPrivateMsg && !r.isThrottleExempt(msg.AccountID, privateMsg) {
+		fade := r.dependencies.UserConfig.Settings.Chat.SpamFade
+		evt.displayModifier.faded = r.spamFade.add(msg.AccountID, privateMsg.ChannelUserName, privateMsg.UserID, fade.Messages, fade.Window, time.Now())
+	}
+
+	if !evt.hidden {
+		cmds = append(cmds, r.collectTrayNotification(msg.AccountID, msg.Message))
+	}
+
+	cmds = append(cmds, r.forwardChatEvent(evt))
+
+	return tea.Batch(cmds...)
+}
+
+func (r *Root) forwardChatEvent(evt chatEventMessage) tea.Cmd {
+	if evt.hidden {
+		return nil
+	}
+
+	cmds := make([]tea.Cmd, 0, len(r.tabs))
+	for i := range r.tabs {
+		var cmd tea.Cmd
+		r.tabs[i], cmd = r.tabs[i].Update(evt)
+		cmds = append(cmds, cmd)
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// isThrottleExempt reports if the message is always shown in busy chats, which are own messages and mentions of the account
+func (r *Root) isThrottleExempt(accountID string, msg *twitchirc.PrivateMessage) bool {
+	return msg.UserID == accountID || r.mentionsAccount(accountID, msg)
+}
+
+// mentionsAccount reports if the message contains the name of the account
+func (r *Root) mentionsAccount(accountID string, msg *twitchirc.PrivateMessage) bool {
+	for _, account := range r.dependencies.Accounts {
+		if account.ID == accountID && !account.IsAnonymous {
+			return messageContainsCaseInsensitive(msg, account.DisplayName)
+		}
+	}
+
+	return false
+}
+
 func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitchirc.IRCer, isFakeEvent bool) chatEventMessage {
 	var (
 		channel                 string
@@ -997,6 +2051,7 @@ func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitc
 		channelGuestID          string
 		channelGuestDisplayName string
 		loginName               string
+		userID                  string
 	)
 
 	// Check when currently in shared session.
@@ -1030,6 +2085,7 @@ func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitc
 		channel = ircMessage.ChannelUserName
 		message = ircMessage.Message
 		loginName = ircMessage.LoginName
+		userID = ircMessage.UserID
 
 		// if is shared display emotes from guest channel, when message is from guest
 		emoteSourceRoom = channelID
@@ -1106,6 +2162,19 @@ func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitc
 		},
 	}
 
+	event.displayModifier.bot = r.isBot(channelID, userID, loginName)
+
+	// bots are often moderators, so they are hidden independent of their role
+	if event.displayModifier.bot && r.dependencies.UserConfig.Settings.BlockSettings.Bots {
+		event.hidden = true
+		return event
+	}
+
+	r.applyScripts(&event)
+	if event.hidden {
+		return event
+	}
+
 	var replaceCommand string
 
 	if len(message) > 0 {
@@ -1118,6 +2187,12 @@ func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitc
 			event.displayModifier.wordReplacements[k] = v
 		}
 
+		if compression := r.dependencies.UserConfig.Settings.Chat.EmoteCompression; compression.EnabledFor(channel) {
+			if _, ok := ircer.(*twitchirc.PrivateMessage); ok {
+				event.displayModifier.emoteRepeat = findEmoteRepeat(message, replacement, compression.MinRepeats)
+			}
+		}
+
 		replaceCommand += p
 	}
 
@@ -1139,6 +2214,19 @@ func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitc
 		replaceCommand += p
 	}
 
+	if r.dependencies.Cosmetics != nil && userID != "" {
+		cosmetics := r.dependencies.Cosmetics.Lookup(userID)
+		event.displayModifier.namePaint = cosmetics.Paint
+
+		if cosmetics.Badge != nil {
+			p, err := r.dependencies.BadgeReplacer.InjectSevenTVBadge(*cosmetics.Badge, event.displayModifier.badgeReplacement)
+			if err != nil {
+				log.Logger.Info().Err(err).Str("user-id", userID).Msg("failed to inject 7TV badge")
+			}
+			replaceCommand += p
+		}
+	}
+
 	if replaceCommand != "" {
 		_, _ = io.WriteString(os.Stdout, replaceCommand)
 	}
@@ -1179,6 +2267,15 @@ func (r *Root) buildChatEventMessage(accountID string, tabID string, ircer twitc
 	return event
 }
 
+// isBot reports if the user is a known bot of FFZ or BTTV
+func (r *Root) isBot(channelID, userID, login string) bool {
+	if r.dependencies.Bots == nil || (userID == "" && login == "") {
+		return false
+	}
+
+	return r.dependencies.Bots.IsBot(channelID, userID, login)
+}
+
 // imageCleanUpCommand returns a command that ticks after 1 minute and
 // clean all images that were not used in the last 10 minutes
 func (r *Root) imageCleanUpCommand() tea.Cmd {
@@ -1198,19 +2295,113 @@ func (r *Root) imageCleanUpCommand() tea.Cmd {
 	})
 }
 
-func (r *Root) closeTab() {
-	if len(r.tabs) > r.tabCursor {
-		tabID := r.tabs[r.tabCursor].ID()
-		if r.tabs[r.tabCursor].Kind() == broadcastTabKind {
-			r.tabs[r.tabCursor].(*broadcastTab).close()
-		}
-		r.header.RemoveTab(tabID)
-		r.tabs = slices.DeleteFunc(r.tabs, func(t tab) bool {
-			return t.ID() == tabID
-		})
+// closeTabAt closes the tab at index and leaves the channel if no other tab uses it.
+// The focus stays on the focused tab, unless it was closed.
+func (r *Root) closeTabAt(index int) tea.Cmd {
+	if index < 0 || index >= len(r.tabs) {
+		return nil
+	}
+
+	currentTab := r.tabs[index]
+	switch t := currentTab.(type) {
+	case *broadcastTab:
+		t.close()
+	case *providerTab:
+		t.close()
+	}
+
+	r.header.RemoveTab(currentTab.ID())
+	r.tabs = slices.Delete(r.tabs, index, index+1)
 
+	switch {
+	case index == r.tabCursor:
 		r.tabCursor--
 		r.nextTab()
-		r.handleResize()
+	case index < r.tabCursor:
+		r.tabCursor--
+	}
+
+	r.handleResize()
+
+	// if tab was connected to IRC, disconnect it
+	if !currentTab.IsDataLoaded() || currentTab.Kind() != broadcastTabKind {
+		return nil
+	}
+
+	cmds := make([]tea.Cmd, 0, 2)
+
+	// if there is another tab for the same channel and the same account
+	hasTabsSameAccountAndChannel := slices.ContainsFunc(r.tabs, func(t tab) bool {
+		return t.ID() != currentTab.ID() &&
+			t.AccountID() == currentTab.AccountID() &&
+			t.ChannelID() == currentTab.ChannelID()
+	})
+
+	hasTabsSameChannel := slices.ContainsFunc(r.tabs, func(t tab) bool {
+		return t.ID() != currentTab.ID() &&
+			t.ChannelID() == currentTab.ChannelID()
+	})
+
+	if !hasTabsSameAccountAndChannel {
+		// send part message
+		log.Logger.Info().Str("channel", currentTab.Channel()).Str("id", currentTab.AccountID()).Msg("sending part message")
+		accountID := currentTab.AccountID()
+		channel := currentTab.Channel()
+		cmds = append(cmds, func() tea.Msg {
+			r.dependencies.Pool.SendIRC(accountID, twitchirc.PartMessage{Channel: channel})
+			return nil
+		})
+	}
+
+	if !hasTabsSameChannel {
+		log.Logger.Info().Str("channel", currentTab.Channel()).Str("channel-id", currentTab.ChannelID()).Msg("removing emote cache entry for channel")
+		r.dependencies.EmoteCache.RemoveEmoteSetForChannel(currentTab.ChannelID())
+	}
+
+	// Disconnect IRC for this account
+	accountID := currentTab.AccountID()
+	cmds = append(cmds, func() tea.Msg {
+		r.dependencies.Pool.DisconnectIRC(accountID)
+		return nil
+	})
+
+	return tea.Sequence(cmds...)
+}
+
+// mentionNames returns the names of all accounts, messages containing one of them are mentions
+// isOwnEvent reports if the events were caused by one of the logged in accounts
+func (r *Root) isOwnEvent(events []hook.Event) bool {
+	for _, e := range events {
+		for _, name := range r.mentionNames() {
+			if strings.EqualFold(e.User, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// toggleSounds mutes or unmutes the sounds of chat events
+func (r *Root) toggleSounds() tea.Cmd {
+	if !r.dependencies.UserConfig.Settings.Sounds.Enabled {
+		return r.focusedTabNotice("Sounds are disabled, set sounds.enabled in the settings to play sounds")
 	}
+
+	if r.dependencies.Sounds.ToggleMuted() {
+		return r.focusedTabNotice("Sounds muted")
+	}
+
+	return r.focusedTabNotice("Sounds unmuted")
+}
+
+func (r *Root) mentionNames() []string {
+	names := make([]string, 0, len(r.dependencies.Accounts))
+	for _, account := range r.dependencies.Accounts {
+		if !account.IsAnonymous {
+			names = append(names, account.DisplayName)
+		}
+	}
+
+	return names
 }
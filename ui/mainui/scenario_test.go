@@ -0,0 +1,344 @@
+package mainui
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/badge"
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/internal/testkit"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/server"
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"github.com/julez-dev/chatuino/twitch/recentmessage"
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/julez-dev/chatuino/wspool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type scenarioAccounts struct {
+	accounts []save.Account
+}
+
+func (s scenarioAccounts) GetAllAccounts() ([]save.Account, error) {
+	return s.accounts, nil
+}
+
+func (s scenarioAccounts) GetAccountBy(id string) (save.Account, error) {
+	for _, acc := range s.accounts {
+		if acc.ID == id {
+			return acc, nil
+		}
+	}
+
+	return save.Account{}, save.ErrAccountNotFound
+}
+
+func (s scenarioAccounts) UpdateTokensFor(string, string, string) error {
+	return nil
+}
+
+// scenarioState keeps the app state of the previous session in memory
+type scenarioState struct {
+	state save.AppState
+}
+
+func (s scenarioState) LoadAppState() (save.AppState, error) {
+	return s.state, nil
+}
+
+func (s scenarioState) SaveAppState(save.AppState) error {
+	return nil
+}
+
+// scenario is the UI wired to the fake Twitch services of the testkit, like main wires it to Twitch
+type scenario struct {
+	api     *testkit.APIServer
+	irc     *testkit.IRCServer
+	program *testkit.Program
+	account save.Account
+}
+
+// newScenario starts the UI with an anonymous account, setup adds the channels and emotes to the fake API before the UI starts
+func newScenario(t *testing.T, setup func(api *testkit.APIServer)) *scenario {
+	t.Helper()
+
+	return newScenarioWithState(t, save.AppState{}, setup)
+}
+
+// newScenarioWithState starts the UI like newScenario with the app state of a previous session, whose tabs are not restored
+func newScenarioWithState(t *testing.T, state save.AppState, setup func(api *testkit.APIServer)) *scenario {
+	t.Helper()
+
+	api := testkit.NewAPIServer(t)
+	irc := testkit.NewIRCServer(t)
+
+	if setup != nil {
+		setup(api)
+	}
+
+	account := save.Account{ID: "anonymous-account", IsAnonymous: true, DisplayName: "justinfan123123", AccessToken: "oauth:123123123"}
+	accounts := scenarioAccounts{accounts: []save.Account{account}}
+
+	settings := save.BuildDefaultSettings()
+	settings.Session.RestoreTabs = false
+	theme := save.BuildDefaultTheme()
+
+	client := api.Client()
+	serverAPI := server.NewClient(testkit.ServerURL, api.Client())
+	emoteCache := emote.NewCache(zerolog.Nop(), serverAPI, seventv.NewAPI(client), bttv.NewAPI(client), ffz.NewAPI(client))
+	badgeCache := badge.NewCache(serverAPI)
+
+	pool := wspool.NewPool(accounts, zerolog.Nop())
+	pool.SetIRCURL(irc.URL())
+	t.Cleanup(func() { _ = pool.Close() })
+
+	// the message log is not under test, its messages are dropped
+	messageLogs := make(chan *twitchirc.PrivateMessage)
+	go func() {
+		for range messageLogs {
+		}
+	}()
+	t.Cleanup(func() { close(messageLogs) })
+
+	root := NewUI(messageLogs, &DependencyContainer{
+		UserConfig: UserConfiguration{
+			Settings:       settings,
+			Theme:          theme,
+			DarkBackground: true,
+		},
+		Keymap:               save.BuildDefaultKeyMap(),
+		Accounts:             accounts.accounts,
+		ServerAPI:            serverAPI,
+		APIUserClients:       map[string]APIClient{account.ID: serverAPI},
+		AccountProvider:      accounts,
+		EmoteCache:           emoteCache,
+		BadgeCache:           badgeCache,
+		EmoteReplacer:        emote.NewReplacer(client, emoteCache, false, theme, nil),
+		BadgeReplacer:        badge.NewReplacer(client, badgeCache, false, theme, settings.Chat.Badges, nil),
+		RecentMessageService: recentmessage.NewAPI(client),
+		Pool:                 pool,
+		HTTPClient:           client,
+		AppStateManager:      scenarioState{state: state},
+	})
+
+	program := testkit.NewProgram(t, root, 120, 40)
+	pool.SetSend(program.Send)
+
+	return &scenario{
+		api:     api,
+		irc:     irc,
+		program: program,
+		account: account,
+	}
+}
+
+// join opens a tab of the channel and waits until it joined the channel on IRC
+func (s *scenario) join(channel string) {
+	s.program.Update(joinChannelMessage{tabKind: broadcastTabKind, channel: channel, account: s.account})
+
+	// the tab connects once the channel data was fetched, which needs the UI to keep running
+	s.program.WaitFor(func(string) bool {
+		return slices.Contains(s.irc.Received(), "JOIN #"+channel)
+	})
+}
+
+func TestScenario_keepsDraftsOfTabsNotRestored(t *testing.T) {
+	t.Parallel()
+
+	state := save.AppState{Tabs: []save.TabState{
+		{Channel: "lirik", IdentityID: "anonymous-account", Kind: int(broadcastTabKind), Draft: "unsent message", InputHistory: []string{"first", "second"}, IsFocused: true},
+		{Channel: "forsen", IdentityID: "anonymous-account", Kind: int(broadcastTabKind), Draft: "other draft", InputHistory: []string{"hello"}},
+	}}
+
+	s := newScenarioWithState(t, state, func(api *testkit.APIServer) {
+		api.AddChannel(twitchapi.UserData{Login: "lirik", DisplayName: "LIRIK"})
+	})
+
+	// the tabs are not restored, the draft is applied once the channel is opened again
+	s.program.WaitFor(func(string) bool {
+		return s.program.Model().(*Root).HasSessionLoaded()
+	})
+	require.Empty(t, s.program.Model().(*Root).tabs)
+
+	s.join("lirik")
+
+	root := s.program.Model().(*Root)
+	tab := root.tabs[0].(*broadcastTab)
+	require.Equal(t, "unsent message", tab.Draft())
+	require.Equal(t, []string{"first", "second"}, tab.inputHistory.Entries())
+
+	snapshot := root.TakeStateSnapshot()
+	require.Len(t, snapshot.Tabs, 2)
+	require.Equal(t, "lirik", snapshot.Tabs[0].Channel)
+	require.False(t, snapshot.Tabs[0].Unopened)
+	require.Equal(t, []string{"first", "second"}, snapshot.Tabs[0].InputHistory)
+	require.Equal(t, save.TabState{Channel: "forsen", IdentityID: "anonymous-account", Kind: int(broadcastTabKind), Draft: "other draft", InputHistory: []string{"hello"}, Unopened: true}, snapshot.Tabs[1])
+}
+
+func TestScenario_chatMessages(t *testing.T) {
+	t.Parallel()
+
+	s := newScenario(t, func(api *testkit.APIServer) {
+		api.AddChannel(twitchapi.UserData{Login: "lirik", DisplayName: "LIRIK"})
+		api.SetRecentMessages("lirik", testkit.PrivateMessage("lirik", "early_viewer", "message from before the tab was opened"))
+	})
+
+	s.join("lirik")
+	s.program.WaitForText("message from before the tab was opened")
+
+	s.irc.Send(testkit.PrivateMessage("lirik", "viewer", "hello chat"))
+	s.program.WaitForText("hello chat")
+
+	require.Contains(t, s.program.View(), "viewer")
+	require.Empty(t, s.api.Unhandled(), "all requests are answered by the fake API")
+}
+
+func TestScenario_streamInfo(t *testing.T) {
+	t.Parallel()
+
+	s := newScenario(t, func(api *testkit.APIServer) {
+		user := api.AddChannel(twitchapi.UserData{Login: "sodapoppin"})
+		api.SetStream(twitchapi.StreamData{
+			UserID:      user.ID,
+			UserLogin:   user.Login,
+			UserName:    user.DisplayName,
+			GameName:    "Just Chatting",
+			Title:       "testing the integration harness",
+			ViewerCount: 1234,
+		})
+	})
+
+	s.join("sodapoppin")
+
+	s.program.WaitFor(func(view string) bool {
+		return strings.Contains(view, "testing the integration harness") && strings.Contains(view, "Just Chatting")
+	})
+}
+
+func TestScenario_mouseSwitchesTab(t *testing.T) {
+	t.Parallel()
+
+	s := newScenario(t, func(api *testkit.APIServer) {
+		api.AddChannel(twitchapi.UserData{Login: "lirik"})
+		api.AddChannel(twitchapi.UserData{Login: "xqc"})
+	})
+
+	s.join("lirik")
+	s.join("xqc")
+
+	root := s.program.Model().(*Root)
+	require.Equal(t, "xqc", root.tabs[root.tabCursor].Channel())
+
+	// the tabs are shown in the second line of the header
+	line := strings.Split(s.program.View(), "\n")[1]
+	x := ansi.StringWidth(line[:strings.Index(line, "lirik")])
+
+	// mouse support is disabled by default, clicks are ignored until it is enabled
+	s.program.Update(tea.MouseMsg{X: x, Y: 1, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	require.Equal(t, "xqc", root.tabs[root.tabCursor].Channel())
+
+	root.dependencies.UserConfig.Settings.Mouse.Enabled = true
+	s.program.Update(tea.MouseMsg{X: x, Y: 1, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	require.Equal(t, "lirik", root.tabs[root.tabCursor].Channel())
+}
+
+func TestScenario_notificationTrayJumpsToChannel(t *testing.T) {
+	t.Parallel()
+
+	s := newScenario(t, func(api *testkit.APIServer) {
+		api.AddChannel(twitchapi.UserData{Login: "lirik"})
+		api.AddChannel(twitchapi.UserData{Login: "xqc"})
+	})
+
+	root := s.program.Model().(*Root)
+	root.dependencies.UserConfig.Settings.NotificationTray.Enabled = true
+	s.program.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	s.join("lirik")
+	s.join("xqc")
+	require.Equal(t, "xqc", root.tabs[root.tabCursor].Channel())
+
+	root.tray.add(trayNotification{kind: trayGoLive, accountID: s.account.ID, channel: "lirik", user: "LIRIK", text: "variety"})
+
+	lines := strings.Split(s.program.View(), "\n")
+	require.Len(t, lines, 40)
+	require.Equal(t, " [1/1] LIRIK is live: variety · alt+j jump · alt+x dismiss", lines[39])
+
+	s.program.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j"), Alt: true})
+	require.Equal(t, "lirik", root.tabs[root.tabCursor].Channel())
+	require.Empty(t, root.tray.notifications)
+}
+
+type fakeTmuxWindow struct {
+	m     sync.Mutex
+	names []string
+}
+
+func (w *fakeTmuxWindow) RenameWindow(name string) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.names = append(w.names, name)
+	return nil
+}
+
+func (w *fakeTmuxWindow) renamed() []string {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return slices.Clone(w.names)
+}
+
+func TestScenario_screenReaderAnnouncesTabs(t *testing.T) {
+	t.Parallel()
+
+	s := newScenario(t, func(api *testkit.APIServer) {
+		api.AddChannel(twitchapi.UserData{Login: "lirik"})
+		api.AddChannel(twitchapi.UserData{Login: "xqc"})
+	})
+
+	root := s.program.Model().(*Root)
+	root.dependencies.UserConfig.Settings.Accessibility.ScreenReader = true
+
+	s.join("lirik")
+	s.program.WaitForText("Notice: Switched to lirik")
+
+	s.join("xqc")
+	s.program.WaitForText("Notice: Switched to xqc")
+	require.Equal(t, root.tabs[root.tabCursor].ID(), root.announcedTab)
+}
+
+func TestScenario_tmuxWindowTitle(t *testing.T) {
+	t.Parallel()
+
+	s := newScenario(t, func(api *testkit.APIServer) {
+		user := api.AddChannel(twitchapi.UserData{Login: "lirik"})
+		api.SetStream(twitchapi.StreamData{UserID: user.ID, UserLogin: user.Login, GameName: "Just Chatting", StartedAt: time.Now().Add(-time.Hour)})
+		api.AddChannel(twitchapi.UserData{Login: "xqc"})
+	})
+
+	window := &fakeTmuxWindow{}
+	root := s.program.Model().(*Root)
+	root.dependencies.TmuxWindow = window
+
+	s.join("lirik")
+	s.program.WaitForText("Just Chatting")
+	require.Equal(t, "● lirik", root.windowTitle)
+
+	s.join("xqc")
+	require.Equal(t, "xqc", root.windowTitle)
+
+	require.Eventually(t, func() bool {
+		return slices.Equal([]string{"lirik", "● lirik", "xqc"}, window.renamed())
+	}, testkit.WaitTimeout, 10*time.Millisecond, "the window is only renamed when the title changes")
+}
@@ -0,0 +1,27 @@
+package mainui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// announceFocusedTab posts a notice in the chat of a newly focused tab with the screen reader mode, screen readers read the new line
+func (r *Root) announceFocusedTab() tea.Cmd {
+	if !r.dependencies.UserConfig.Settings.Accessibility.ScreenReader || r.screenType != mainScreen || len(r.tabs) <= r.tabCursor {
+		return nil
+	}
+
+	// the chat drops notices until it is loaded, the tab is announced once it is
+	focused := r.tabs[r.tabCursor]
+	if focused.ID() == r.announcedTab || !focused.IsDataLoaded() {
+		return nil
+	}
+
+	r.announcedTab = focused.ID()
+
+	name := focused.Channel()
+	if name == "" {
+		name = focused.Kind().String()
+	}
+
+	return r.focusedTabNotice("Switched to " + name)
+}
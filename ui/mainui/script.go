@@ -0,0 +1,143 @@
+package mainui
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/script"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+// scriptCommandResultMessage contains the result of a slash command registered by a script
+type scriptCommandResultMessage struct {
+	targetID string
+	command  string
+	result   script.CommandResult
+	err      error
+}
+
+// applyScripts runs the message handlers of the user scripts for chat messages.
+// The emote replacements are built from the original message, so emotes in the changed text are still replaced.
+func (r *Root) applyScripts(event *chatEventMessage) {
+	msg, ok := event.message.(*twitchirc.PrivateMessage)
+	if !ok || r.dependencies.Scripts == nil {
+		return
+	}
+
+	result := r.dependencies.Scripts.Transform(script.Message{
+		ID:           msg.ID,
+		Channel:      msg.ChannelUserName,
+		User:         msg.LoginName,
+		DisplayName:  msg.DisplayName,
+		Text:         msg.Message,
+		Mod:          msg.Mod,
+		VIP:          msg.VIP,
+		Subscriber:   msg.Subscriber,
+		FirstMessage: msg.FirstMsg,
+		Bot:          event.displayModifier.bot,
+	})
+
+	event.hidden = result.Hide
+
+	if result.Text != msg.Message {
+		changed := msg.Clone()
+		changed.Message = result.Text
+		event.message = changed
+	}
+
+	if len(result.Highlight) == 0 {
+		return
+	}
+
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(r.dependencies.UserConfig.Theme.MentionColor)).Bold(true)
+
+	for word := range strings.FieldsSeq(result.Text) {
+		stripped := stripDisplayNameEdges(word)
+
+		for _, highlight := range result.Highlight {
+			if strings.EqualFold(word, highlight) || strings.EqualFold(stripped, highlight) {
+				event.displayModifier.wordReplacements[word] = style.Render(word)
+				break
+			}
+		}
+	}
+}
+
+// customSuggestions returns the custom commands of the settings and the commands registered by scripts
+func (t *broadcastTab) customSuggestions() map[string]string {
+	suggestions := t.deps.UserConfig.Settings.BuildCustomSuggestionMap()
+
+	if t.deps.Scripts == nil {
+		return suggestions
+	}
+
+	commands := make(map[string]string)
+	for _, c := range t.deps.Scripts.Commands() {
+		// show the help of the command in the suggestion, but only insert the command
+		commands[strings.TrimSpace("/"+c.Name+" "+c.Help)] = "/" + c.Name
+	}
+
+	// custom commands of the settings take precedence
+	maps.Copy(commands, suggestions)
+
+	return commands
+}
+
+// handleScriptCommand runs a slash command registered by a script outside of the update loop
+func (t *broadcastTab) handleScriptCommand(name, args string) tea.Cmd {
+	scripts := t.deps.Scripts
+	targetID := t.id
+	ctx := script.CommandContext{
+		Channel:   t.channelLogin,
+		ChannelID: t.channelID,
+		Account:   t.account.DisplayName,
+		Args:      args,
+	}
+
+	return func() tea.Msg {
+		result, err := scripts.RunCommand(name, ctx)
+		return scriptCommandResultMessage{
+			targetID: targetID,
+			command:  name,
+			result:   result,
+			err:      err,
+		}
+	}
+}
+
+func (t *broadcastTab) handleScriptCommandResult(msg scriptCommandResultMessage) tea.Cmd {
+	notice := func(text string) tea.Cmd {
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     t.id,
+				accountID: t.AccountID(),
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       text,
+				},
+			}
+		}
+	}
+
+	if msg.err != nil {
+		log.Logger.Err(msg.err).Str("command", msg.command).Msg("script command failed")
+		return notice(fmt.Sprintf("/%s failed: %s", msg.command, msg.err))
+	}
+
+	var cmds []tea.Cmd
+
+	if msg.result.Notice != "" {
+		cmds = append(cmds, notice(msg.result.Notice))
+	}
+
+	if msg.result.Send != "" {
+		cmds = append(cmds, t.sendMessage(msg.result.Send))
+	}
+
+	return tea.Batch(cmds...)
+}
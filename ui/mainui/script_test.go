@@ -0,0 +1,49 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/julez-dev/chatuino/script"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeScripts struct {
+	ScriptEngine
+	result script.TransformResult
+}
+
+func (f fakeScripts) Transform(_ script.Message) script.TransformResult {
+	return f.result
+}
+
+func TestRoot_applyScripts(t *testing.T) {
+	t.Parallel()
+
+	original := &twitchirc.PrivateMessage{LoginName: "viewer", Message: "hello pog"}
+
+	deps := newTestDeps(t)
+	deps.Scripts = fakeScripts{result: script.TransformResult{Text: "hello POG, pog!", Highlight: []string{"pog"}}}
+
+	r := &Root{dependencies: deps}
+
+	event := chatEventMessage{
+		message:         original,
+		displayModifier: messageContentModifier{wordReplacements: make(wordReplacement)},
+	}
+
+	r.applyScripts(&event)
+
+	require.False(t, event.hidden)
+	require.Equal(t, "hello POG, pog!", event.message.(*twitchirc.PrivateMessage).Message)
+	require.Equal(t, "hello pog", original.Message, "the original message is not changed")
+	require.Contains(t, event.displayModifier.wordReplacements, "POG,")
+	require.Contains(t, event.displayModifier.wordReplacements, "pog!")
+	require.NotContains(t, event.displayModifier.wordReplacements, "hello")
+
+	r.dependencies.Scripts = fakeScripts{result: script.TransformResult{Text: "hello pog", Hide: true}}
+	event = chatEventMessage{message: original}
+	r.applyScripts(&event)
+	require.True(t, event.hidden)
+	require.Same(t, original, event.message)
+}
@@ -0,0 +1,368 @@
+package mainui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// sendState is the delivery state of a message sent from a tab
+type sendState int
+
+const (
+	sendPending   sendState = iota // sent, but not yet accepted by Twitch
+	sendConfirmed                  // accepted by Twitch, replaced once the message is received through IRC
+	sendFailed
+)
+
+// sentMessage tracks a message sent from a tab. It is shown right away and replaced by the message received
+// through IRC, which includes badges and emotes.
+type sentMessage struct {
+	nonce     string
+	text      string
+	state     sendState
+	messageID string // set once Twitch accepted the message
+	failure   string // reason shown for failed messages
+	viaIRC    bool   // sent as IRC PRIVMSG, Twitch answers with a USERSTATE or a NOTICE instead of the Helix response
+}
+
+// offlineSendNotice is shown instead of sending messages in offline mode
+const offlineSendNotice = "Messages can't be sent in offline mode"
+
+// messageSendResultMessage reports if Twitch accepted a sent message
+type messageSendResultMessage struct {
+	tabID     string
+	nonce     string
+	messageID string
+	failure   string // empty if the message was sent
+	viaIRC    bool   // written to the IRC connection, the result is only known once Twitch answers
+}
+
+// sendParts shows the messages as pending and sends them one after another. After a failed message the remaining parts are not sent.
+// Messages are sent through the Helix API, which reports why Twitch dropped a message, unless the account is configured to use IRC.
+// If the Helix request itself fails the message is sent through IRC instead.
+func (t *broadcastTab) sendParts(messages []string) tea.Cmd {
+	const delay = time.Second
+
+	// offline the accounts use the API of Chatuino's server, which can't send messages
+	client, ok := t.deps.APIUserClients[t.account.ID].(userAuthenticatedAPIClient)
+	if !ok || t.deps.Offline {
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice(offlineSendNotice)
+		}
+	}
+
+	lastSent := t.lastMessageSentAt
+	pool := t.deps.Pool
+	method := t.deps.UserConfig.Settings.Chat.SendMethodFor(t.account.DisplayName)
+	broadcasterID := t.channelID
+	channel := t.channelLogin
+	userID := t.account.ID
+	tabID := t.id
+
+	sendIRC := func(message string) error {
+		return pool.SendIRC(userID, &twitchirc.PrivateMessage{ChannelUserName: channel, Message: message})
+	}
+
+	var failed bool
+
+	cmds := make([]tea.Cmd, 0, len(messages))
+	for _, message := range messages {
+		nonce := t.addPendingMessage(message)
+
+		cmds = append(cmds, func() tea.Msg {
+			result := messageSendResultMessage{tabID: tabID, nonce: nonce}
+
+			// the commands run in sequence, so the state of the previous parts is known
+			if failed {
+				result.failure = "previous part was not sent"
+				return result
+			}
+
+			diff := time.Since(lastSent)
+			if diff < delay {
+				time.Sleep(delay - diff)
+			}
+
+			defer func() {
+				lastSent = time.Now()
+				failed = result.failure != ""
+			}()
+
+			if method == save.SendMethodIRC {
+				if err := sendIRC(message); err != nil {
+					result.failure = err.Error()
+				} else {
+					result.viaIRC = true
+				}
+
+				return result
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+
+			resp, err := client.SendChatMessage(ctx, twitchapi.SendChatMessageRequest{
+				BroadcasterID: broadcasterID,
+				SenderID:      userID,
+				Message:       message,
+			})
+
+			switch {
+			case err != nil && shouldFallbackToIRC(err):
+				if ircErr := sendIRC(message); ircErr != nil {
+					result.failure = sendFailureReason(err, twitchapi.DropReason{})
+				} else {
+					result.viaIRC = true
+				}
+			case err != nil:
+				result.failure = sendFailureReason(err, twitchapi.DropReason{})
+			case len(resp.Data) == 0:
+				result.failure = "no response from Twitch"
+			case !resp.Data[0].IsSent:
+				result.failure = sendFailureReason(nil, resp.Data[0].DropReason)
+			default:
+				result.messageID = resp.Data[0].MessageID
+			}
+
+			return result
+		})
+	}
+
+	// account for the delay between split messages, so the next message does not get rate limited
+	t.lastMessageSentAt = time.Now().Add(delay * time.Duration(len(messages)-1))
+
+	return tea.Sequence(cmds...)
+}
+
+// addPendingMessage shows the message in the chat until Twitch confirms it, returns the nonce to identify it
+func (t *broadcastTab) addPendingMessage(text string) string {
+	t.sentMessages++
+	nonce := t.id + "-" + strconv.Itoa(t.sentMessages)
+
+	t.chatWindow.handleMessage(chatEventMessage{
+		isFakeEvent: true,
+		accountID:   t.account.ID,
+		channel:     t.channelLogin,
+		channelID:   t.channelID,
+		tabID:       t.id,
+		message: &twitchirc.PrivateMessage{
+			UserID:      t.account.ID,
+			LoginName:   strings.ToLower(t.account.DisplayName),
+			DisplayName: t.account.DisplayName,
+			Message:     text,
+			TMISentTS:   time.Now(),
+		},
+		send: &sentMessage{nonce: nonce, text: text},
+	})
+
+	return nonce
+}
+
+// handleRetryMessage sends the selected failed message again
+func (t *broadcastTab) handleRetryMessage() tea.Cmd {
+	_, entry := t.chatWindow.entryForCurrentCursor()
+	if entry == nil || entry.Event.send == nil || entry.Event.send.state != sendFailed {
+		return nil
+	}
+
+	text := entry.Event.send.text
+	t.chatWindow.removeSentMessage(entry.Event.send.nonce)
+
+	return t.sendParts([]string{text})
+}
+
+// handleSendResult marks the sent message as confirmed or failed
+func (c *chatWindow) handleSendResult(msg messageSendResultMessage) {
+	for i, e := range c.entries {
+		if e.Event.send == nil || e.Event.send.nonce != msg.nonce {
+			continue
+		}
+
+		if msg.viaIRC {
+			e.Event.send.viaIRC = true
+			return
+		}
+
+		if msg.failure != "" {
+			e.Event.send.state = sendFailed
+			e.Event.send.failure = msg.failure
+			e.rendered = nil
+			c.recalculateLines()
+			return
+		}
+
+		// the message was already received through IRC
+		for _, other := range c.entries[i+1:] {
+			if priv, ok := other.Event.message.(*twitchirc.PrivateMessage); ok && priv.ID == msg.messageID {
+				c.removeSentMessage(msg.nonce)
+				return
+			}
+		}
+
+		e.Event.send.state = sendConfirmed
+		e.Event.send.messageID = msg.messageID
+		e.rendered = nil
+		c.recalculateLines()
+		return
+	}
+}
+
+// replaceSentMessage replaces the sent message with the same message received through IRC.
+// Reports false if the message was not sent from this window.
+func (c *chatWindow) replaceSentMessage(msg chatEventMessage) bool {
+	priv, ok := msg.message.(*twitchirc.PrivateMessage)
+	if !ok || msg.send != nil || c.accountID == "" || priv.UserID != c.accountID {
+		return false
+	}
+
+	for _, e := range c.entries {
+		send := e.Event.send
+		if send == nil || send.state == sendFailed {
+			continue
+		}
+
+		// messages received before Twitch answered the request are matched by their text
+		if send.messageID == priv.ID || send.state == sendPending && strings.TrimSpace(send.text) == strings.TrimSpace(priv.Message) {
+			e.Event = msg
+			e.rendered = nil
+			c.recalculateLines()
+			return true
+		}
+	}
+
+	return false
+}
+
+// handleIRCSendReply resolves the oldest pending message sent through IRC. Twitch does not echo messages sent through IRC,
+// it answers with a USERSTATE including the message ID or with a NOTICE if the message was not sent.
+// Reports true if the reply belonged to a sent message and should not be shown.
+func (c *chatWindow) handleIRCSendReply(msg twitchirc.IRCer) bool {
+	var (
+		userState, isUserState = msg.(*twitchirc.UserState)
+		notice, isNotice       = msg.(*twitchirc.Notice)
+	)
+
+	switch {
+	case isUserState && userState.ID != "":
+	case isNotice && strings.HasPrefix(string(notice.MsgID), "msg_"): // all msg_ notices report a message which was not sent
+	default:
+		return false
+	}
+
+	for _, e := range c.entries {
+		send := e.Event.send
+		if send == nil || !send.viaIRC || send.state != sendPending {
+			continue
+		}
+
+		if isUserState {
+			send.state = sendConfirmed
+			send.messageID = userState.ID
+		} else {
+			send.state = sendFailed
+			send.failure = sendFailureReason(nil, twitchapi.DropReason{Code: string(notice.MsgID), Message: notice.Message})
+		}
+
+		e.rendered = nil
+		c.recalculateLines()
+		return true
+	}
+
+	return false
+}
+
+func (c *chatWindow) removeSentMessage(nonce string) {
+	for i, e := range c.entries {
+		if e.Event.send != nil && e.Event.send.nonce == nonce {
+			c.entries = append(c.entries[:i], c.entries[i+1:]...)
+			c.recalculateLines()
+			return
+		}
+	}
+}
+
+// sendStateSuffix is appended to own messages which were not received through IRC yet
+func (c *chatWindow) sendStateSuffix(send *sentMessage) string {
+	if send == nil {
+		return ""
+	}
+
+	switch send.state {
+	case sendPending:
+		return c.timestampStyle.Render(" (sending…)")
+	case sendFailed:
+		return c.errorAlertStyle.Render(fmt.Sprintf(" (not sent: %s, %s to retry)", send.failure, c.keymap().RetryMessage.Help().Key))
+	}
+
+	return ""
+}
+
+// shouldFallbackToIRC reports if a message should be sent through IRC after the Helix request failed.
+// Rate limits and rejected requests would fail the same way through IRC.
+func shouldFallbackToIRC(err error) bool {
+	var apiErr twitchapi.APIError
+	if !errors.As(err, &apiErr) {
+		return true // the request did not reach Twitch
+	}
+
+	switch {
+	case apiErr.Status >= http.StatusInternalServerError:
+		return true
+	case apiErr.Status == http.StatusUnauthorized, apiErr.Status == http.StatusForbidden: // e.g. the account was added before the user:write:chat scope was requested
+		return true
+	}
+
+	return false
+}
+
+// sendFailureReason explains why Twitch did not send a message.
+// The drop reason codes are the same as the msg-id of IRC notices.
+func sendFailureReason(err error, drop twitchapi.DropReason) string {
+	if err != nil {
+		var apiErr twitchapi.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.Status {
+			case http.StatusTooManyRequests:
+				return "rate limited"
+			case http.StatusUnauthorized, http.StatusForbidden:
+				return "not allowed, " + apiErr.Message
+			}
+		}
+
+		return err.Error()
+	}
+
+	switch drop.Code {
+	case "msg_banned":
+		return "banned from the channel"
+	case "msg_timedout":
+		return "timed out"
+	case "msg_ratelimit":
+		return "rate limited"
+	case "msg_duplicate":
+		return "duplicate message"
+	case "msg_rejected", "msg_rejected_mandatory", "automod_held":
+		return "held by AutoMod"
+	}
+
+	if drop.Message != "" {
+		return drop.Message
+	}
+
+	if drop.Code != "" {
+		return drop.Code
+	}
+
+	return "dropped by Twitch"
+}
@@ -0,0 +1,91 @@
+package mainui
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type sendDelayTickMessage struct {
+	tabID string
+	id    int
+}
+
+// pendingSend is a message held back by chat.send_delay, it is sent once the countdown ends unless canceled
+type pendingSend struct {
+	id        int
+	text      string
+	send      func() tea.Cmd
+	remaining int // seconds until the message is sent
+}
+
+// delaySend holds the message back for chat.send_delay, send is only called once the delay passed
+func (t *broadcastTab) delaySend(text string, send func() tea.Cmd) tea.Cmd {
+	delay := t.deps.UserConfig.Settings.Chat.SendDelay
+	if delay <= 0 {
+		return send()
+	}
+
+	var cmd tea.Cmd
+
+	// a message sent while another one is pending sends the pending one right away, so the order is kept
+	if t.pendingSend != nil {
+		cmd = t.pendingSend.send()
+	}
+
+	t.pendingSendID++
+	t.pendingSend = &pendingSend{
+		id:        t.pendingSendID,
+		text:      text,
+		send:      send,
+		remaining: int(math.Ceil(delay.Seconds())),
+	}
+
+	return tea.Batch(cmd, sendDelayTickCommand(t.id, t.pendingSendID))
+}
+
+func sendDelayTickCommand(tabID string, id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return sendDelayTickMessage{tabID: tabID, id: id}
+	})
+}
+
+func (t *broadcastTab) handleSendDelayTick(msg sendDelayTickMessage) tea.Cmd {
+	// ticks of canceled or already sent messages are ignored
+	if t.pendingSend == nil || t.pendingSend.id != msg.id {
+		return nil
+	}
+
+	t.pendingSend.remaining--
+	if t.pendingSend.remaining > 0 {
+		return sendDelayTickCommand(t.id, msg.id)
+	}
+
+	send := t.pendingSend.send
+	t.pendingSend = nil
+
+	return send()
+}
+
+// cancelPendingSend drops the pending message and puts it back into the message input, if the input is empty
+func (t *broadcastTab) cancelPendingSend() tea.Cmd {
+	text := t.pendingSend.text
+	t.pendingSend = nil
+
+	notice := t.localNotice()
+
+	// the input is not overwritten, the message is shown in the notice instead
+	if t.messageInput.Value() != "" {
+		return func() tea.Msg {
+			return notice("Message not sent: " + text)
+		}
+	}
+
+	t.messageInput.SetValue(text)
+	t.HandleResize()
+
+	return tea.Batch(t.updateDraftIndicator(), func() tea.Msg {
+		return notice("Message not sent, it was put back into the input")
+	})
+}
@@ -0,0 +1,61 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/ui/component"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_broadcastTab_delaySend(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Chat.SendDelay = time.Millisecond * 1500
+
+	tab := &broadcastTab{
+		id:           "tab",
+		deps:         deps,
+		messageInput: component.NewSuggestionTextInput(nil, nil),
+	}
+
+	var sent []string
+	send := func(text string) func() tea.Cmd {
+		return func() tea.Cmd {
+			sent = append(sent, text)
+			return nil
+		}
+	}
+
+	// the delay is rounded up to full seconds
+	require.NotNil(t, tab.delaySend("first", send("first")))
+	require.Equal(t, 2, tab.pendingSend.remaining)
+
+	require.NotNil(t, tab.handleSendDelayTick(sendDelayTickMessage{tabID: "tab", id: 1}))
+	require.Empty(t, sent)
+
+	// a second message sends the pending one right away
+	tab.delaySend("second", send("second"))
+	require.Equal(t, []string{"first"}, sent)
+	require.Nil(t, tab.handleSendDelayTick(sendDelayTickMessage{tabID: "tab", id: 1}), "ticks of the first message are ignored")
+
+	tab.handleSendDelayTick(sendDelayTickMessage{tabID: "tab", id: 2})
+	tab.handleSendDelayTick(sendDelayTickMessage{tabID: "tab", id: 2})
+	require.Equal(t, []string{"first", "second"}, sent)
+	require.Nil(t, tab.pendingSend)
+
+	// canceled messages are put back into the input
+	tab.delaySend("third", send("third"))
+	msg := tab.cancelPendingSend()().(tea.BatchMsg)
+	require.Len(t, msg, 2)
+	require.Equal(t, "third", tab.messageInput.Value())
+	require.Nil(t, tab.handleSendDelayTick(sendDelayTickMessage{tabID: "tab", id: 3}))
+	require.Equal(t, []string{"first", "second"}, sent)
+
+	// without delay messages are sent right away
+	tab.deps.UserConfig.Settings.Chat.SendDelay = 0
+	tab.delaySend("fourth", send("fourth"))
+	require.Equal(t, []string{"first", "second", "fourth"}, sent)
+}
@@ -0,0 +1,154 @@
+package mainui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// minShortenLength is the length of links worth shortening when the message is too long, short links are about as long
+const minShortenLength = 30
+
+// sendPrompt asks before sending a message with long links or a message too long for a single message,
+// if the links should be shortened first
+type sendPrompt struct {
+	text       string
+	quickSend  bool
+	links      []string // links offered to be shortened
+	parts      int      // number of messages the text is split into
+	shortening bool
+}
+
+type linksShortenedMessage struct {
+	tabID string
+	text  string // text with the links replaced, only set if all links were shortened
+	err   error
+}
+
+// sendPromptFor returns the prompt shown before sending text, nil if the text is sent right away
+func (t *broadcastTab) sendPromptFor(text string, quickSend bool) *sendPrompt {
+	if t.deps.Shortener == nil || strings.HasPrefix(text, "/") {
+		return nil
+	}
+
+	// the user chose to send the message as it is
+	if t.promptSkip != "" && t.promptSkip == text {
+		t.promptSkip = ""
+		return nil
+	}
+
+	parts := 1
+	if utf8.RuneCountInString(text) > messageCharLimit {
+		parts = len(splitMessage(text, messageCharLimit))
+	}
+
+	minLength := t.deps.UserConfig.Settings.Links.ShortenLength
+	if parts > 1 {
+		minLength = minShortenLength
+	}
+
+	var links []string
+	for _, link := range urlStartRegex.FindAllString(text, -1) {
+		if utf8.RuneCountInString(link) > minLength {
+			links = append(links, link)
+		}
+	}
+
+	if len(links) == 0 {
+		return nil
+	}
+
+	return &sendPrompt{text: text, quickSend: quickSend, links: links, parts: parts}
+}
+
+// handleSendPromptKey shortens the links, sends the message as it is or goes back to editing it
+func (t *broadcastTab) handleSendPromptKey(msg tea.KeyMsg) tea.Cmd {
+	prompt := t.sendPrompt
+	if prompt.shortening {
+		return nil
+	}
+
+	switch {
+	case msg.String() == "s":
+		prompt.shortening = true
+		return t.shortenLinks(prompt)
+	case key.Matches(msg, t.keymap().Confirm):
+		t.sendPrompt = nil
+		t.promptSkip = prompt.text
+		t.messageInput.Focus()
+
+		return tea.Batch(t.handleMessageSent(prompt.quickSend), t.updateDraftIndicator())
+	case key.Matches(msg, t.keymap().Escape):
+		t.sendPrompt = nil
+		t.messageInput.Focus()
+		t.HandleResize()
+	}
+
+	return nil
+}
+
+func (t *broadcastTab) shortenLinks(prompt *sendPrompt) tea.Cmd {
+	shortener, tabID := t.deps.Shortener, t.id
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		text := prompt.text
+		for _, link := range prompt.links {
+			short, err := shortener.Shorten(ctx, link)
+			if err != nil {
+				return linksShortenedMessage{tabID: tabID, err: err}
+			}
+
+			text = strings.Replace(text, link, short, 1)
+		}
+
+		return linksShortenedMessage{tabID: tabID, text: text}
+	}
+}
+
+// handleLinksShortened puts the message with the short links back into the input, so it can be checked before sending
+func (t *broadcastTab) handleLinksShortened(msg linksShortenedMessage) tea.Cmd {
+	if t.sendPrompt == nil {
+		return nil
+	}
+
+	t.sendPrompt = nil
+	t.messageInput.Focus()
+
+	if msg.err != nil {
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice("Failed to shorten links: " + msg.err.Error())
+		}
+	}
+
+	t.messageInput.SetValue(msg.text)
+	t.messageInput.InputModel.CursorEnd()
+	t.HandleResize()
+
+	return t.updateDraftIndicator()
+}
+
+// view returns the line shown in place of the suggestions while the prompt is open
+func (p *sendPrompt) view(confirmKey, escapeKey string) string {
+	if p.shortening {
+		return " Shortening links..."
+	}
+
+	reason := "Long links"
+	send := confirmKey + " send"
+
+	if p.parts > 1 {
+		reason = fmt.Sprintf("%d characters", utf8.RuneCountInString(p.text))
+		send = fmt.Sprintf("%s split into %d messages", confirmKey, p.parts)
+	}
+
+	return fmt.Sprintf(" %s: s shorten links, %s, %s edit", reason, send, escapeKey)
+}
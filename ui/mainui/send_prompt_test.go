@@ -0,0 +1,68 @@
+package mainui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/ui/component"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeShortener struct{}
+
+func (fakeShortener) Shorten(_ context.Context, link string) (string, error) {
+	return "https://is.gd/" + link[len(link)-3:], nil
+}
+
+func Test_broadcastTab_sendPrompt(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.Shortener = fakeShortener{}
+
+	tab := &broadcastTab{
+		id:           "tab",
+		deps:         deps,
+		messageInput: component.NewSuggestionTextInput(nil, nil),
+	}
+
+	longLink := "https://example.com/" + strings.Repeat("a", 60) + "abc"
+
+	require.Nil(t, tab.sendPromptFor("short https://example.com/abc", false))
+	require.Nil(t, tab.sendPromptFor("/ban "+longLink, false), "commands are not checked")
+
+	prompt := tab.sendPromptFor("look "+longLink, false)
+	require.Equal(t, []string{longLink}, prompt.links)
+	require.Equal(t, 1, prompt.parts)
+	require.Equal(t, " Long links: s shorten links, enter send, esc edit", prompt.view("enter", "esc"))
+
+	// messages too long for a single message offer shorter links as well
+	prompt = tab.sendPromptFor(strings.Repeat("a ", 250)+"https://example.com/1234567890xyz", false)
+	require.Len(t, prompt.links, 1)
+	require.Equal(t, 2, prompt.parts)
+	require.Contains(t, prompt.view("enter", "esc"), "split into 2 messages")
+
+	// shortened links are put back into the input
+	tab.sendPrompt = tab.sendPromptFor("look "+longLink, false)
+	tab.messageInput.SetValue("look " + longLink)
+
+	cmd := tab.handleSendPromptKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	require.True(t, tab.sendPrompt.shortening)
+	require.Nil(t, tab.handleSendPromptKey(tea.KeyMsg{Type: tea.KeyEsc}), "keys are ignored while shortening")
+
+	tab.handleLinksShortened(cmd().(linksShortenedMessage))
+	require.Nil(t, tab.sendPrompt)
+	require.Equal(t, "look https://is.gd/abc", tab.messageInput.Value())
+
+	// escape goes back to editing
+	tab.sendPrompt = tab.sendPromptFor("look "+longLink, false)
+	tab.handleSendPromptKey(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Nil(t, tab.sendPrompt)
+
+	// the message is sent as it is once
+	tab.promptSkip = "look " + longLink
+	require.Nil(t, tab.sendPromptFor("look "+longLink, false))
+	require.NotNil(t, tab.sendPromptFor("look "+longLink, false))
+}
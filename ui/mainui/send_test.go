@@ -0,0 +1,185 @@
+package mainui
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sendFailureReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		drop twitchapi.DropReason
+		want string
+	}{
+		{name: "rate limited request", err: twitchapi.APIError{Status: http.StatusTooManyRequests}, want: "rate limited"},
+		{name: "missing scope", err: twitchapi.APIError{Status: http.StatusUnauthorized, Message: "Missing scope: user:write:chat"}, want: "not allowed, Missing scope: user:write:chat"},
+		{name: "request failed", err: errors.New("connection refused"), want: "connection refused"},
+		{name: "banned", drop: twitchapi.DropReason{Code: "msg_banned", Message: "You are permanently banned from talking in this channel."}, want: "banned from the channel"},
+		{name: "automod", drop: twitchapi.DropReason{Code: "msg_rejected", Message: "Your message is being checked by mods and has not been sent."}, want: "held by AutoMod"},
+		{name: "unknown code", drop: twitchapi.DropReason{Code: "msg_slowmode", Message: "This room is in slow mode."}, want: "This room is in slow mode."},
+		{name: "empty drop reason", want: "dropped by Twitch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, sendFailureReason(tt.err, tt.drop))
+		})
+	}
+}
+
+func Test_shouldFallbackToIRC(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "request failed", err: errors.New("connection refused"), want: true},
+		{name: "server error", err: twitchapi.APIError{Status: http.StatusBadGateway}, want: true},
+		{name: "missing scope", err: twitchapi.APIError{Status: http.StatusUnauthorized, Message: "Missing scope: user:write:chat"}, want: true},
+		{name: "rate limited", err: twitchapi.APIError{Status: http.StatusTooManyRequests}},
+		{name: "bad request", err: twitchapi.APIError{Status: http.StatusBadRequest}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, shouldFallbackToIRC(tt.err))
+		})
+	}
+}
+
+func Test_chatWindow_sentMessages(t *testing.T) {
+	t.Parallel()
+
+	newWindow := func() *chatWindow {
+		deps := newTestDeps(t)
+
+		c := newChatWindow(80, 20, deps)
+		c.setAccount(save.Account{ID: "1", DisplayName: "me"})
+
+		return c
+	}
+
+	pending := func(nonce, text string) chatEventMessage {
+		return chatEventMessage{
+			message: &twitchirc.PrivateMessage{UserID: "1", DisplayName: "me", Message: text},
+			send:    &sentMessage{nonce: nonce, text: text},
+		}
+	}
+
+	received := func(id, text string) chatEventMessage {
+		return chatEventMessage{message: &twitchirc.PrivateMessage{ID: id, UserID: "1", DisplayName: "me", Message: text}}
+	}
+
+	t.Run("confirmed then received", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.handleMessage(pending("a", "hello"))
+		c.handleSendResult(messageSendResultMessage{nonce: "a", messageID: "m1"})
+		require.Equal(t, sendConfirmed, c.entries[0].Event.send.state)
+
+		c.handleMessage(received("m1", "hello"))
+		require.Len(t, c.entries, 1)
+		require.Nil(t, c.entries[0].Event.send)
+	})
+
+	t.Run("received before confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.handleMessage(pending("a", "hello "))
+		c.handleMessage(received("m1", "hello"))
+		require.Len(t, c.entries, 1)
+		require.Nil(t, c.entries[0].Event.send)
+
+		// the result only arrives after the message was replaced
+		c.handleSendResult(messageSendResultMessage{nonce: "a", messageID: "m1"})
+		require.Len(t, c.entries, 1)
+	})
+
+	t.Run("received with changed text before confirmation", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.handleMessage(pending("a", "hello"))
+		c.handleMessage(received("m1", "hello!"))
+		require.Len(t, c.entries, 2)
+
+		c.handleSendResult(messageSendResultMessage{nonce: "a", messageID: "m1"})
+		require.Len(t, c.entries, 1)
+		require.Nil(t, c.entries[0].Event.send)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.handleMessage(pending("a", "hello"))
+		c.handleSendResult(messageSendResultMessage{nonce: "a", failure: "held by AutoMod"})
+		require.Equal(t, sendFailed, c.entries[0].Event.send.state)
+		require.Contains(t, c.lines[len(c.lines)-1], "not sent: held by AutoMod, alt+r to retry")
+
+		// failed messages are never replaced
+		c.handleMessage(received("m1", "hello"))
+		require.Len(t, c.entries, 2)
+
+		c.removeSentMessage("a")
+		require.Len(t, c.entries, 1)
+		require.Nil(t, c.entries[0].Event.send)
+	})
+
+	t.Run("confirmed through IRC", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.handleMessage(pending("a", "hello"))
+		c.handleMessage(pending("b", "world"))
+		c.handleSendResult(messageSendResultMessage{nonce: "a", viaIRC: true})
+		c.handleSendResult(messageSendResultMessage{nonce: "b", viaIRC: true})
+		require.Equal(t, sendPending, c.entries[0].Event.send.state)
+
+		c.handleMessage(chatEventMessage{message: &twitchirc.UserState{ID: "m1"}})
+		require.Equal(t, sendConfirmed, c.entries[0].Event.send.state)
+		require.Equal(t, "m1", c.entries[0].Event.send.messageID)
+		require.Equal(t, sendPending, c.entries[1].Event.send.state)
+
+		c.handleMessage(chatEventMessage{message: &twitchirc.Notice{MsgID: "msg_slowmode", Message: "This room is in slow mode."}})
+		require.Len(t, c.entries, 2)
+		require.Equal(t, sendFailed, c.entries[1].Event.send.state)
+		require.Equal(t, "This room is in slow mode.", c.entries[1].Event.send.failure)
+
+		// replies without a pending message are shown as usual
+		c.handleMessage(chatEventMessage{message: &twitchirc.Notice{MsgID: "msg_slowmode", Message: "This room is in slow mode."}})
+		require.Len(t, c.entries, 3)
+	})
+}
+
+func Test_broadcastTab_sendParts_offline(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.Offline = true
+
+	tab := &broadcastTab{
+		id:      "tab",
+		account: save.Account{ID: "1"},
+		deps:    deps,
+	}
+
+	msg, ok := tab.sendParts([]string{"hello"})().(requestLocalMessageHandleMessage)
+	require.True(t, ok, "the message is not sent")
+	require.Equal(t, offlineSendNotice, msg.message.(*twitchirc.Notice).Message)
+}
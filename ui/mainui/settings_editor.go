@@ -0,0 +1,506 @@
+package mainui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/blocklist"
+	"github.com/julez-dev/chatuino/save"
+)
+
+// settingWrittenMessage is sent after a changed setting was written to the settings file
+type settingWrittenMessage struct {
+	path string
+	err  error
+}
+
+// settingsEditorRow is either a section heading, an option, a snippet or a blocked user of the settings editor
+type settingsEditorRow struct {
+	section string
+	option  int // index into options, followed by the snippets and the blocked users, -1 for section headings
+}
+
+// blockedUserEntry is a user blocked on Twitch by one of the accounts, listed below the options so it can be unblocked
+type blockedUserEntry struct {
+	accountID string
+	account   string
+	user      blocklist.User
+}
+
+// settingsEditor lists the settings which can be changed at runtime. Every confirmed change is validated,
+// written to the settings file and applied like a reload of the config files.
+type settingsEditor struct {
+	deps          *DependencyContainer
+	width, height int
+
+	options  []save.SettingOption
+	blocked  []blockedUserEntry
+	rows     []settingsEditorRow
+	settings save.Settings // includes changes, which may not be applied yet
+
+	cursor  int // index into options, followed by the snippets and the blocked users
+	offset  int // first visible row
+	editing bool
+	input   textinput.Model
+	err     string
+	status  string
+}
+
+func newSettingsEditor(width, height int, deps *DependencyContainer) *settingsEditor {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(deps.UserConfig.Theme.InputPromptColor))
+
+	e := &settingsEditor{
+		deps:     deps,
+		options:  save.SettingOptions(),
+		settings: deps.UserConfig.Settings,
+		input:    input,
+	}
+	e.buildRows()
+	e.handleResize(width, height)
+
+	return e
+}
+
+// buildRows lists the options grouped by section, followed by the blocked users of each account
+func (e *settingsEditor) buildRows() {
+	e.blocked = nil
+	if e.deps.Blocks != nil {
+		for _, acc := range e.deps.Accounts {
+			if acc.IsAnonymous {
+				continue
+			}
+
+			for _, user := range e.deps.Blocks.Users(acc.ID) {
+				e.blocked = append(e.blocked, blockedUserEntry{accountID: acc.ID, account: acc.DisplayName, user: user})
+			}
+		}
+	}
+
+	e.rows = nil
+	for i, o := range e.options {
+		if i == 0 || e.options[i-1].Section != o.Section {
+			e.rows = append(e.rows, settingsEditorRow{section: o.Section, option: -1})
+		}
+		e.rows = append(e.rows, settingsEditorRow{section: o.Section, option: i})
+	}
+
+	// the last snippet row adds a new snippet
+	e.rows = append(e.rows, settingsEditorRow{section: "Snippets", option: -1})
+	for i := range e.snippetRows() {
+		e.rows = append(e.rows, settingsEditorRow{section: "Snippets", option: len(e.options) + i})
+	}
+
+	firstBlocked := len(e.options) + e.snippetRows()
+	for i, b := range e.blocked {
+		if i == 0 || e.blocked[i-1].accountID != b.accountID {
+			e.rows = append(e.rows, settingsEditorRow{section: "Blocked Users of " + b.account, option: -1})
+		}
+		e.rows = append(e.rows, settingsEditorRow{section: "Blocked Users of " + b.account, option: firstBlocked + i})
+	}
+
+	e.cursor = min(e.cursor, e.selectableRows()-1)
+}
+
+// snippetRows is the number of snippet rows, the snippets and a row to add a new one
+func (e *settingsEditor) snippetRows() int {
+	return len(e.settings.Snippets) + 1
+}
+
+// selectableRows is the number of rows the cursor can be moved to
+func (e *settingsEditor) selectableRows() int {
+	return len(e.options) + e.snippetRows() + len(e.blocked)
+}
+
+// selectedSnippet returns the index of the snippet at the cursor, len(snippets) for the row adding a new snippet
+func (e *settingsEditor) selectedSnippet() (int, bool) {
+	if e.cursor < len(e.options) || e.cursor >= len(e.options)+e.snippetRows() {
+		return 0, false
+	}
+
+	return e.cursor - len(e.options), true
+}
+
+// selectedBlockedUser returns the blocked user at the cursor, if the cursor is below the options and snippets
+func (e *settingsEditor) selectedBlockedUser() (blockedUserEntry, bool) {
+	if e.cursor < len(e.options)+e.snippetRows() {
+		return blockedUserEntry{}, false
+	}
+
+	return e.blocked[e.cursor-len(e.options)-e.snippetRows()], true
+}
+
+func (e *settingsEditor) handleResize(width, height int) {
+	// the editor is shown as a modal, leave some space to the terminal border
+	e.width = max(min(width-4, 100), 20)
+	e.height = max(height-4, 8)
+	e.input.Width = e.width - 8
+	e.scrollToCursor()
+}
+
+func (e *settingsEditor) Update(msg tea.Msg) (*settingsEditor, tea.Cmd) {
+	switch msg := msg.(type) {
+	case settingWrittenMessage:
+		if msg.err != nil {
+			// show the settings which are still active
+			e.settings = e.deps.UserConfig.Settings
+			e.buildRows()
+			e.scrollToCursor()
+			e.err = fmt.Sprintf("Failed to save %s: %s", msg.path, msg.err)
+			e.status = ""
+			return e, nil
+		}
+
+		e.status = fmt.Sprintf("Saved %s", msg.path)
+		return e, e.reloadConfig()
+	case userBlockChangedMessage:
+		if msg.tabID != "" {
+			e.buildRows()
+			e.scrollToCursor()
+			return e, nil
+		}
+
+		if msg.err != nil {
+			e.err = fmt.Sprintf("Failed to unblock %s: %s", msg.user.DisplayName, blockErrorText(msg.err))
+			e.status = ""
+			return e, nil
+		}
+
+		e.buildRows()
+		e.scrollToCursor()
+		e.status = fmt.Sprintf("Unblocked %s", msg.user.DisplayName)
+		return e, nil
+	case tea.KeyMsg:
+		if e.editing {
+			return e, e.handleEditKey(msg)
+		}
+
+		switch {
+		case key.Matches(msg, e.deps.Keymap.Up):
+			e.moveCursor(-1)
+		case key.Matches(msg, e.deps.Keymap.Down):
+			e.moveCursor(1)
+		case key.Matches(msg, e.deps.Keymap.Confirm):
+			return e, e.activateOption()
+		}
+	}
+
+	return e, nil
+}
+
+func (e *settingsEditor) handleEditKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, e.deps.Keymap.Escape):
+		e.editing = false
+		e.input.Blur()
+		e.err = ""
+		return nil
+	case key.Matches(msg, e.deps.Keymap.Confirm):
+		set := e.setValue
+		if _, ok := e.selectedSnippet(); ok {
+			set = e.setSnippet
+		}
+
+		if cmd := set(e.input.Value()); cmd != nil {
+			e.editing = false
+			e.input.Blur()
+			return cmd
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+func (e *settingsEditor) moveCursor(delta int) {
+	e.cursor = min(max(e.cursor+delta, 0), e.selectableRows()-1)
+	e.err = ""
+	e.status = ""
+	e.scrollToCursor()
+}
+
+// activateOption toggles booleans, cycles through choices and starts editing all other options and snippets.
+// Blocked users are unblocked.
+func (e *settingsEditor) activateOption() tea.Cmd {
+	if blocked, ok := e.selectedBlockedUser(); ok {
+		e.err = ""
+		e.status = fmt.Sprintf("Unblocking %s…", blocked.user.DisplayName)
+		return setUserBlocked(e.deps, "", blocked.accountID, blocked.user, false)
+	}
+
+	var current string
+	if i, ok := e.selectedSnippet(); ok {
+		// snippets are edited as the trigger followed by the text
+		if i < len(e.settings.Snippets) {
+			current = e.settings.Snippets[i].Trigger + " " + e.settings.Snippets[i].Text
+		}
+	} else {
+		option := e.options[e.cursor]
+		current, _ = e.settings.SettingValue(option.Path)
+
+		switch option.Kind() {
+		case save.SettingBool:
+			return e.setValue(fmt.Sprint(current != "true"))
+		case save.SettingChoice:
+			next := (slices.Index(option.Choices, current) + 1) % len(option.Choices)
+			return e.setValue(option.Choices[next])
+		}
+	}
+
+	e.editing = true
+	e.err = ""
+	e.status = ""
+	e.input.SetValue(current)
+	e.input.CursorEnd()
+
+	return e.input.Focus()
+}
+
+// setValue validates the value of the selected option and writes it to the settings file.
+// Returns nil if the value is invalid, the error is shown in the editor.
+func (e *settingsEditor) setValue(value string) tea.Cmd {
+	option := e.options[e.cursor]
+
+	updated := e.settings
+	if err := updated.SetSettingValue(option.Path, value); err != nil {
+		e.err = err.Error()
+		return nil
+	}
+
+	e.settings = updated
+	e.err = ""
+
+	// write the normalized value, e.g. 2m instead of 120s
+	value, _ = updated.SettingValue(option.Path)
+	path := option.Path
+
+	return func() tea.Msg {
+		_, err := save.WriteSettingValues(map[string]string{path: value})
+		return settingWrittenMessage{path: path, err: err}
+	}
+}
+
+// setSnippet validates the snippet at the cursor, written as the trigger followed by the text, and writes all snippets
+// to the settings file. An empty value removes the snippet. Returns nil if the snippet is invalid, the error is shown in the editor.
+func (e *settingsEditor) setSnippet(value string) tea.Cmd {
+	i, _ := e.selectedSnippet()
+	snippets := slices.Clone(e.settings.Snippets)
+
+	trigger, text, _ := strings.Cut(strings.TrimSpace(value), " ")
+	snippet := save.Snippet{Trigger: trigger, Text: strings.TrimSpace(text)}
+
+	switch {
+	case trigger == "" && i == len(snippets):
+		return nil
+	case trigger == "":
+		snippets = slices.Delete(snippets, i, i+1)
+	case i == len(snippets):
+		snippets = append(snippets, snippet)
+	default:
+		snippets[i] = snippet
+	}
+
+	updated := e.settings
+	if err := updated.SetSnippets(snippets); err != nil {
+		e.err = err.Error()
+		return nil
+	}
+
+	e.settings = updated
+	e.err = ""
+	e.buildRows()
+	e.scrollToCursor()
+
+	return func() tea.Msg {
+		_, err := save.WriteSnippets(snippets)
+		return settingWrittenMessage{path: "snippets", err: err}
+	}
+}
+
+// reloadConfig applies the written settings like a change of the config files
+func (e *settingsEditor) reloadConfig() tea.Cmd {
+	source := e.deps.ConfigSource
+	if source == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		source.Sync()
+		config, err := source.Load()
+		return configReloadedMessage{config: config, err: err}
+	}
+}
+
+func (e *settingsEditor) selectedRow() int {
+	return slices.IndexFunc(e.rows, func(r settingsEditorRow) bool {
+		return r.option == e.cursor
+	})
+}
+
+// listHeight is the number of rows available for the option list
+func (e *settingsEditor) listHeight() int {
+	// border, padding, title, hint, blank lines and the footer with description, input and messages
+	return max(e.height-12, 3)
+}
+
+func (e *settingsEditor) scrollToCursor() {
+	row := e.selectedRow()
+
+	// keep the section heading of the first option visible
+	if row > 0 && e.rows[row-1].option == -1 {
+		row--
+	}
+
+	if row < e.offset {
+		e.offset = row
+	}
+
+	if end := e.selectedRow(); end >= e.offset+e.listHeight() {
+		e.offset = end - e.listHeight() + 1
+	}
+}
+
+func (e *settingsEditor) View() string {
+	theme := e.deps.UserConfig.Theme
+	innerWidth := e.width - 4
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ListLabelColor))
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.ActiveLabelColor))
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatErrorColor))
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render("Settings") + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(fmt.Sprintf("%s select · %s toggle/edit · %s close",
+		e.deps.Keymap.Up.Help().Key+"/"+e.deps.Keymap.Down.Help().Key,
+		e.deps.Keymap.Confirm.Help().Key,
+		e.deps.Keymap.Escape.Help().Key,
+	)) + "\n\n")
+
+	end := min(e.offset+e.listHeight(), len(e.rows))
+	for _, row := range e.rows[e.offset:end] {
+		if row.option == -1 {
+			_, _ = b.WriteString(sectionStyle.Render(row.section) + "\n")
+			continue
+		}
+
+		if row.option >= len(e.options) && row.option < len(e.options)+e.snippetRows() {
+			_, _ = b.WriteString(e.viewSnippetRow(row.option-len(e.options), innerWidth, selectedStyle, dimmedStyle) + "\n")
+			continue
+		}
+
+		if row.option >= len(e.options) {
+			blocked := e.blocked[row.option-len(e.options)-e.snippetRows()]
+
+			name := "  " + blocked.user.DisplayName
+			if row.option == e.cursor {
+				name = selectedStyle.Render("> " + blocked.user.DisplayName)
+			}
+
+			_, _ = b.WriteString(name + "\n")
+			continue
+		}
+
+		option := e.options[row.option]
+		value, _ := e.settings.SettingValue(option.Path)
+		if value == "" {
+			value = dimmedStyle.Render("<empty>")
+		}
+
+		name := "  " + option.Path
+		if row.option == e.cursor {
+			name = selectedStyle.Render("> " + option.Path)
+		}
+
+		if option.Restart {
+			name += dimmedStyle.Render(" (restart)")
+		}
+
+		value = ansi.Truncate(value, max(innerWidth-lipgloss.Width(name)-2, 1), "…")
+		gap := max(innerWidth-lipgloss.Width(name)-lipgloss.Width(value), 1)
+
+		_, _ = b.WriteString(name + strings.Repeat(" ", gap) + value + "\n")
+	}
+
+	// fill up the list, so the footer doesn't jump while scrolling
+	for range e.listHeight() - (end - e.offset) {
+		_, _ = b.WriteString("\n")
+	}
+
+	if blocked, ok := e.selectedBlockedUser(); ok {
+		description := fmt.Sprintf("%s (%s) is blocked on Twitch by %s, their messages are hidden. Press %s to unblock.",
+			blocked.user.DisplayName, blocked.user.Login, blocked.account, e.deps.Keymap.Confirm.Help().Key)
+		_, _ = b.WriteString("\n" + lipgloss.NewStyle().Width(innerWidth).Render(description) + "\n\n")
+	} else if _, ok := e.selectedSnippet(); ok {
+		description := fmt.Sprintf("Typing the trigger followed by a space in the message input inserts the text. Enter the trigger followed by the text, like ;gg gg wp everyone. Placeholders: {%s}. An empty value removes the snippet.",
+			strings.Join(save.SnippetPlaceholders, "}, {"))
+		_, _ = b.WriteString("\n" + lipgloss.NewStyle().Width(innerWidth).Render(description) + "\n")
+
+		if e.editing {
+			_, _ = b.WriteString(e.input.View())
+		}
+		_, _ = b.WriteString("\n")
+	} else {
+		option := e.options[e.cursor]
+		_, _ = b.WriteString("\n" + lipgloss.NewStyle().Width(innerWidth).Render(option.Description) + "\n")
+
+		switch {
+		case e.editing:
+			_, _ = b.WriteString(e.input.View() + "\n")
+		case option.Kind() == save.SettingChoice:
+			_, _ = b.WriteString(dimmedStyle.Render("One of "+strings.Join(option.Choices, ", ")) + "\n")
+		default:
+			_, _ = b.WriteString("\n")
+		}
+	}
+
+	switch {
+	case e.err != "":
+		_, _ = b.WriteString(errorStyle.Width(innerWidth).Render(e.err))
+	case e.status != "":
+		_, _ = b.WriteString(dimmedStyle.Render(e.status))
+	}
+
+	return lipgloss.NewStyle().
+		Width(e.width).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
+
+// viewSnippetRow renders the snippet with the index i, or the row adding a new snippet
+func (e *settingsEditor) viewSnippetRow(i, width int, selectedStyle, dimmedStyle lipgloss.Style) string {
+	selected := len(e.options)+i == e.cursor
+
+	if i == len(e.settings.Snippets) {
+		if selected {
+			return selectedStyle.Render("> + add snippet")
+		}
+
+		return dimmedStyle.Render("  + add snippet")
+	}
+
+	snippet := e.settings.Snippets[i]
+
+	name := "  " + snippet.Trigger
+	if selected {
+		name = selectedStyle.Render("> " + snippet.Trigger)
+	}
+
+	value := ansi.Truncate(snippet.Text, max(width-lipgloss.Width(name)-2, 1), "…")
+	gap := max(width-lipgloss.Width(name)-lipgloss.Width(value), 1)
+
+	return name + strings.Repeat(" ", gap) + value
+}
@@ -0,0 +1,143 @@
+package mainui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/badge"
+	"github.com/julez-dev/chatuino/emote"
+	"github.com/julez-dev/chatuino/internal/testkit"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/server"
+	"github.com/julez-dev/chatuino/twitch/bttv"
+	"github.com/julez-dev/chatuino/twitch/ffz"
+	"github.com/julez-dev/chatuino/twitch/seventv"
+	"github.com/julez-dev/chatuino/twitch/twitchapi"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotRoomID is the channel of the scripted messages, its badges and emotes are served by the fake API
+const snapshotRoomID = "71092938"
+
+// snapshotStart is the time of the first scripted message. It is in the local time zone, so timestamps render the same everywhere.
+var snapshotStart = time.Date(2025, time.January, 6, 14, 30, 0, 0, time.Local)
+
+// snapshotLine builds a PRIVMSG of the scripted chat, sent seconds after snapshotStart
+func snapshotLine(seconds int, tags, login, text string) string {
+	if tags != "" {
+		tags += ";"
+	}
+
+	return fmt.Sprintf("@%sdisplay-name=%s;id=msg-%d;room-id=%s;tmi-sent-ts=%d;user-id=%d :%s!%s@%s.tmi.twitch.tv PRIVMSG #xqc :%s",
+		tags, login, seconds, snapshotRoomID, snapshotStart.Add(time.Duration(seconds)*time.Second).UnixMilli(), len(login), login, login, login, text)
+}
+
+// snapshotChat is a scripted chat covering wrapping, badges, emotes and moderation messages
+var snapshotChat = []string{
+	snapshotLine(0, "", "viewer", "hello chat"),
+	snapshotLine(1, "badges=moderator/1", "a_moderator", "please keep the chat in english, thank you"),
+	snapshotLine(2, "badges=subscriber/12,vip/1", "long_time_sub", "this message is long enough to be wrapped onto multiple lines at every width the snapshots are rendered with, so wrapping is covered"),
+	snapshotLine(3, "", "emote_user", "catJAM catJAM catJAM"),
+	snapshotLine(4, "", "wide_chars", "日本語のメッセージも正しく折り返されるべきです、全角文字は二つのセルを使います"),
+	snapshotLine(5, "", "link_poster", "https://example.com/a/very/long/link/without/any/spaces/that/has/to/be/cut/somewhere"),
+	snapshotLine(6, "", "spammer", "buy followers"),
+	fmt.Sprintf("@login=spammer;room-id=%s;target-msg-id=msg-6;tmi-sent-ts=%d :tmi.twitch.tv CLEARMSG #xqc :buy followers", snapshotRoomID, snapshotStart.Add(7*time.Second).UnixMilli()),
+	fmt.Sprintf("@ban-duration=600;room-id=%s;target-user-id=7;tmi-sent-ts=%d :tmi.twitch.tv CLEARCHAT #xqc :spammer", snapshotRoomID, snapshotStart.Add(8*time.Second).UnixMilli()),
+}
+
+// renderSnapshot renders the scripted chat in text mode with the settings changed by configure
+func renderSnapshot(t *testing.T, width int, configure func(settings *save.Settings)) string {
+	t.Helper()
+
+	api := testkit.NewAPIServer(t)
+	api.SetBadges("",
+		twitchapi.BadgeSet{ID: "moderator", Versions: []twitchapi.BadgeVersion{{ID: "1", Title: "Moderator"}}},
+		twitchapi.BadgeSet{ID: "vip", Versions: []twitchapi.BadgeVersion{{ID: "1", Title: "VIP"}}},
+	)
+	api.SetBadges(snapshotRoomID, twitchapi.BadgeSet{ID: "subscriber", Versions: []twitchapi.BadgeVersion{{ID: "12", Title: "1-Year Subscriber"}}})
+	api.SetBTTVEmotes(snapshotRoomID, bttv.Emote{ID: "5f1b0186cf6d2144653d2970", Code: "catJAM", ImageType: "gif", Animated: true})
+
+	settings := save.BuildDefaultSettings()
+	if configure != nil {
+		configure(&settings)
+	}
+
+	theme := save.BuildDefaultTheme()
+	client := api.Client()
+	serverAPI := server.NewClient(testkit.ServerURL, api.Client())
+
+	emoteCache := emote.NewCache(zerolog.Nop(), serverAPI, seventv.NewAPI(client), bttv.NewAPI(client), ffz.NewAPI(client))
+	require.NoError(t, emoteCache.RefreshLocal(t.Context(), snapshotRoomID))
+
+	badgeCache := badge.NewCache(serverAPI)
+	require.NoError(t, badgeCache.RefreshGlobal(t.Context()))
+	require.NoError(t, badgeCache.RefreshChannel(t.Context(), snapshotRoomID))
+
+	deps := &DependencyContainer{
+		UserConfig:    UserConfiguration{Settings: settings, Theme: theme, DarkBackground: true},
+		Keymap:        save.BuildDefaultKeyMap(),
+		ServerAPI:     serverAPI,
+		EmoteCache:    emoteCache,
+		BadgeCache:    badgeCache,
+		EmoteReplacer: emote.NewReplacer(client, emoteCache, false, theme, nil),
+		BadgeReplacer: badge.NewReplacer(client, badgeCache, false, theme, settings.Chat.Badges, nil),
+	}
+
+	r := NewUI(nil, deps)
+	c := newChatWindow(width, 200, deps)
+
+	for _, line := range snapshotChat {
+		msg, err := twitchirc.ParseIRC(line)
+		require.NoError(t, err)
+
+		c.handleMessage(r.buildChatEventMessage("account", "tab", msg, false))
+	}
+
+	// trailing spaces and the empty lines below the messages don't matter for the layout
+	lines := strings.Split(ansi.Strip(c.View()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+func TestChatSnapshots(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]func(settings *save.Settings){
+		"standard": nil,
+		"compact": func(settings *save.Settings) {
+			settings.Chat.Layout = save.ChatLayoutCompact
+		},
+		"cozy": func(settings *save.Settings) {
+			settings.Chat.Layout = save.ChatLayoutCozy
+		},
+		"badge_glyphs": func(settings *save.Settings) {
+			settings.Chat.Badges.Glyphs = true
+		},
+		"timestamps_12h_minutes": func(settings *save.Settings) {
+			settings.Timestamps.Format = save.TimestampFormatMinutes
+			settings.Timestamps.Clock = save.TimestampClock12h
+		},
+		"timestamps_off": func(settings *save.Settings) {
+			settings.Timestamps.Format = save.TimestampFormatOff
+		},
+	}
+
+	for name, configure := range cases {
+		for _, width := range []int{40, 80, 120} {
+			t.Run(fmt.Sprintf("%s_%d", name, width), func(t *testing.T) {
+				t.Parallel()
+
+				got := renderSnapshot(t, width, configure)
+				testkit.RequireGolden(t, fmt.Sprintf("chat/%s_%d", name, width), got)
+			})
+		}
+	}
+}
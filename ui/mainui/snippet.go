@@ -0,0 +1,44 @@
+package mainui
+
+import (
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// expandSnippet returns the text of the snippet with the trigger, with the placeholders replaced by their current value
+func (t *broadcastTab) expandSnippet(trigger string) (string, bool) {
+	snippet, ok := t.deps.UserConfig.Settings.SnippetFor(trigger)
+	if !ok {
+		return "", false
+	}
+
+	return snippet.Expand(map[string]string{
+		"channel":           t.channelLogin,
+		"user-under-cursor": t.userUnderCursor(),
+		"account":           t.account.DisplayName,
+	}), true
+}
+
+// userUnderCursor returns the display name of the author of the selected message, the message input is
+// used from the chat or the user inspect window
+func (t *broadcastTab) userUnderCursor() string {
+	cw := t.chatWindow
+	if (t.state == userInspectMode || t.state == userInspectInsertMode) && t.userInspect != nil {
+		cw = t.userInspect.chatWindow
+	}
+
+	_, entry := cw.entryForCurrentCursor()
+	if entry == nil {
+		return ""
+	}
+
+	switch msg := entry.Event.message.(type) {
+	case *twitchirc.PrivateMessage:
+		return msg.DisplayName
+	case *twitchirc.SubMessage:
+		return msg.DisplayName
+	case *twitchirc.SubGiftMessage:
+		return msg.DisplayName
+	}
+
+	return ""
+}
@@ -0,0 +1,50 @@
+package mainui
+
+import (
+	"time"
+)
+
+// maxTrackedSpamUsers is the number of users after which users without recent messages are forgotten
+const maxTrackedSpamUsers = 5000
+
+// spamFade tracks how many messages users sent recently, so messages of users writing faster than the limit can be faded
+type spamFade struct {
+	users map[string][]time.Time // account ID, channel and user ID to the times of their messages within the window
+}
+
+func newSpamFade() *spamFade {
+	return &spamFade{users: map[string][]time.Time{}}
+}
+
+// add records a message of the user and reports if it should be faded, because the user sent more than limit messages within window.
+// Once the user slows down their messages are shown as usual again. A limit of 0 never fades messages.
+func (s *spamFade) add(accountID, channel, userID string, limit int, window time.Duration, now time.Time) bool {
+	if limit <= 0 {
+		return false
+	}
+
+	if len(s.users) >= maxTrackedSpamUsers {
+		s.forgetInactive(window, now)
+	}
+
+	key := accountID + "/" + channel + "/" + userID
+
+	times := s.users[key]
+	for len(times) > 0 && now.Sub(times[0]) >= window {
+		times = times[1:]
+	}
+
+	times = append(times, now)
+	s.users[key] = times
+
+	return len(times) > limit
+}
+
+// forgetInactive removes users without messages in the window
+func (s *spamFade) forgetInactive(window time.Duration, now time.Time) {
+	for key, times := range s.users {
+		if now.Sub(times[len(times)-1]) >= window {
+			delete(s.users, key)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_spamFade_add(t *testing.T) {
+	t.Parallel()
+
+	fade := newSpamFade()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	window := time.Second * 10
+
+	for i := range 3 {
+		require.False(t, fade.add("1", "lirik", "10", 3, window, now.Add(time.Duration(i)*time.Second)))
+	}
+
+	require.True(t, fade.add("1", "lirik", "10", 3, window, now.Add(3*time.Second)))
+
+	// other users, channels and accounts are counted separately
+	require.False(t, fade.add("1", "lirik", "11", 3, window, now.Add(3*time.Second)))
+	require.False(t, fade.add("1", "sodapoppin", "10", 3, window, now.Add(3*time.Second)))
+	require.False(t, fade.add("2", "lirik", "10", 3, window, now.Add(3*time.Second)))
+
+	// messages older than the window are no longer counted once the user slows down
+	require.False(t, fade.add("1", "lirik", "10", 3, window, now.Add(13*time.Second)))
+	require.False(t, fade.add("1", "lirik", "10", 0, window, now.Add(13*time.Second)), "a limit of 0 never fades")
+}
+
+func Test_chatWindow_fadedMessages(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Chat.SpamFade.Collapse = true
+
+	c := newChatWindow(40, 20, deps)
+	text := strings.Repeat("spam ", 20)
+
+	faded := chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "a", DisplayName: "a", Message: text}}
+	faded.displayModifier.faded = true
+	c.handleMessage(faded)
+	require.Len(t, c.entries[0].rendered, 1)
+	require.True(t, strings.HasSuffix(stripAnsi(c.lines[0]), "…"))
+
+	c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{LoginName: "a", DisplayName: "a", Message: text}})
+	require.Greater(t, len(c.entries[1].rendered), 1)
+}
@@ -0,0 +1,38 @@
+package mainui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+// handleAddToDictionary adds the misspelled word at the cursor of the message input to the custom dictionary
+func (t *broadcastTab) handleAddToDictionary() tea.Cmd {
+	word, ok := t.messageInput.MisspelledWord()
+	if !ok {
+		return nil
+	}
+
+	checker := t.deps.SpellChecker
+
+	return func() tea.Msg {
+		notice := &twitchirc.Notice{
+			FakeTimestamp: time.Now(),
+			Message:       fmt.Sprintf("Added %s to your dictionary", word.Text),
+		}
+
+		if err := checker.Add(word.Text); err != nil {
+			log.Logger.Err(err).Str("word", word.Text).Msg("failed to add word to dictionary")
+			notice.Message = fmt.Sprintf("Failed to add %s to your dictionary: %s", word.Text, err)
+		}
+
+		return requestLocalMessageHandleMessage{
+			tabID:     t.id,
+			accountID: t.AccountID(),
+			message:   notice,
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package mainui
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/kittyimg"
+	"github.com/julez-dev/chatuino/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	statsInterval    = time.Second
+	statsMaxChannels = 5 // busiest channels listed in the overlay
+)
+
+// statsTickMessage refreshes the stats overlay, ticks of a previously opened overlay are ignored
+type statsTickMessage struct {
+	generation int
+}
+
+// channelRate is the number of messages per second received in a channel
+type channelRate struct {
+	channel string
+	rate    float64
+}
+
+// statsOverlay shows the internal metrics on top of the main screen, it is refreshed every second while visible
+type statsOverlay struct {
+	deps       *DependencyContainer
+	visible    bool
+	generation int
+
+	// message counters of the previous sample, used to calculate the rates
+	lastMessages map[string]uint64
+	lastSample   time.Time
+
+	rates []channelRate // sorted by rate, then channel
+	total float64
+
+	watchtime []channelWatchtime // channels with the most watchtime, sorted by open time
+}
+
+func newStatsOverlay(deps *DependencyContainer) *statsOverlay {
+	return &statsOverlay{deps: deps}
+}
+
+// toggle shows or hides the overlay, the returned command starts the refresh ticks
+func (s *statsOverlay) toggle() tea.Cmd {
+	s.visible = !s.visible
+	s.generation++
+
+	if !s.visible {
+		return nil
+	}
+
+	s.rates, s.total = nil, 0
+	s.sample(metrics.ChatMessages.Values(), time.Now())
+	s.sampleWatchtime()
+
+	return s.tick()
+}
+
+func (s *statsOverlay) tick() tea.Cmd {
+	generation := s.generation
+	return tea.Tick(statsInterval, func(time.Time) tea.Msg {
+		return statsTickMessage{generation: generation}
+	})
+}
+
+func (s *statsOverlay) Update(msg statsTickMessage) tea.Cmd {
+	if !s.visible || msg.generation != s.generation {
+		return nil
+	}
+
+	s.sample(metrics.ChatMessages.Values(), time.Now())
+	s.sampleWatchtime()
+
+	return s.tick()
+}
+
+// sampleWatchtime reads the channels with the most watchtime from the store, the store only reads the file once
+func (s *statsOverlay) sampleWatchtime() {
+	if s.deps.Watchtime == nil {
+		return
+	}
+
+	totals, err := s.deps.Watchtime.Watchtime()
+	if err != nil {
+		log.Logger.Err(err).Msg("failed to read watchtime")
+		return
+	}
+
+	s.watchtime = topWatchtime(totals, watchtimeMaxChannels)
+}
+
+// sample calculates the message rates since the previous sample
+func (s *statsOverlay) sample(messages map[string]uint64, now time.Time) {
+	if s.lastMessages != nil {
+		elapsed := now.Sub(s.lastSample).Seconds()
+
+		s.rates, s.total = s.rates[:0], 0
+		for channel, count := range messages {
+			if elapsed <= 0 || count <= s.lastMessages[channel] {
+				continue
+			}
+
+			rate := float64(count-s.lastMessages[channel]) / elapsed
+			s.rates = append(s.rates, channelRate{channel: channel, rate: rate})
+			s.total += rate
+		}
+
+		slices.SortFunc(s.rates, func(a, b channelRate) int {
+			return cmp.Or(cmp.Compare(b.rate, a.rate), strings.Compare(a.channel, b.channel))
+		})
+	}
+
+	s.lastMessages = maps.Clone(messages)
+	s.lastSample = now
+}
+
+func (s *statsOverlay) View() string {
+	theme := s.deps.UserConfig.Theme
+
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ListLabelColor)).Width(14)
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	b := &strings.Builder{}
+	row := func(label, value string) {
+		_, _ = b.WriteString("\n" + labelStyle.Render(label) + value)
+	}
+
+	_, _ = b.WriteString(titleStyle.Render("Stats") + dimmedStyle.Render(" · "+s.deps.Keymap.ToggleStats.Help().Key+" close"))
+
+	row("Messages/s", fmt.Sprintf("%.1f", s.total))
+	for _, r := range s.rates[:min(len(s.rates), statsMaxChannels)] {
+		row("  "+r.channel, fmt.Sprintf("%.1f", r.rate))
+	}
+
+	sessionHits := metrics.ImageCacheHits.With("session").Value()
+	diskHits := metrics.ImageCacheHits.With("disk").Value()
+	misses := metrics.ImageCacheMisses.Value()
+
+	hitRate := "-"
+	if total := sessionHits + diskHits + misses; total > 0 {
+		hitRate = fmt.Sprintf("%.0f%%", float64(sessionHits+diskHits)/float64(total)*100)
+	}
+
+	if len(s.watchtime) > 0 {
+		row("Watchtime", "")
+		for _, w := range s.watchtime {
+			row("  "+w.channel, formatWatchtime(w.Open)+dimmedStyle.Render(" ("+formatWatchtime(w.Focused)+" focused)"))
+		}
+	}
+
+	row("Image cache", fmt.Sprintf("%s hits", hitRate)+dimmedStyle.Render(fmt.Sprintf(" (%d session, %d disk, %d downloaded)", sessionHits, diskHits, misses)))
+	row("Image encode", fmt.Sprintf("%s avg", metrics.ImageEncode.Mean().Round(time.Microsecond*100))+dimmedStyle.Render(fmt.Sprintf(" (%d images)", metrics.ImageEncode.Count())))
+
+	images, size := kittyimg.PlacedImagesStats()
+	row("Placed images", fmt.Sprintf("%d, %s", images, formatBytes(uint64(size))))
+
+	row("Reconnects", fmt.Sprintf("IRC %d, EventSub %d", metrics.IRCReconnects.Value(), metrics.EventSubReconnects.Value()))
+
+	mem := metrics.ReadMemStats()
+	row("Memory", fmt.Sprintf("%s heap, %s total", formatBytes(mem.HeapAlloc), formatBytes(mem.Sys)))
+	row("Goroutines", fmt.Sprintf("%d", runtime.NumGoroutine()))
+
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
+
+// formatBytes formats a size with binary units, e.g. 1.5 MiB
+func formatBytes(size uint64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
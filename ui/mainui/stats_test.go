@@ -0,0 +1,51 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsOverlay_sample(t *testing.T) {
+	t.Parallel()
+
+	s := &statsOverlay{}
+	now := time.Now()
+
+	// the first sample has nothing to compare to
+	s.sample(map[string]uint64{"lirik": 10, "quin69": 4}, now)
+	require.Empty(t, s.rates)
+	require.Zero(t, s.total)
+
+	s.sample(map[string]uint64{"lirik": 30, "quin69": 4, "xqc": 10, "forsen": 10}, now.Add(time.Second*2))
+	require.Equal(t, []channelRate{{channel: "lirik", rate: 10}, {channel: "forsen", rate: 5}, {channel: "xqc", rate: 5}}, s.rates)
+	require.Equal(t, 20.0, s.total)
+
+	// quiet channels are not listed
+	s.sample(map[string]uint64{"lirik": 30, "quin69": 4, "xqc": 10, "forsen": 10}, now.Add(time.Second*3))
+	require.Empty(t, s.rates)
+	require.Zero(t, s.total)
+}
+
+func Test_formatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size uint64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 1023, want: "1023 B"},
+		{size: 1024, want: "1.0 KiB"},
+		{size: 1536 * 1024, want: "1.5 MiB"},
+		{size: 3 << 30, want: "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, formatBytes(tt.size))
+		})
+	}
+}
@@ -3,11 +3,13 @@ package mainui
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/save"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 )
 
@@ -76,11 +78,11 @@ type streamStatus struct {
 	tab           *broadcastTab
 	deps          *DependencyContainer
 
-	userConfig UserConfiguration
-
 	settings      twitchapi.ChatSettingData
 	err           error
 	isDataFetched bool
+
+	pendingKeys string // keys of a started key sequence
 }
 
 func newStreamStatus(width, height int, tab *broadcastTab, accountID, channelID string, deps *DependencyContainer) *streamStatus {
@@ -134,6 +136,9 @@ func (s *streamStatus) Update(msg tea.Msg) (*streamStatus, tea.Cmd) {
 
 		s.isDataFetched = true
 
+		return s, nil
+	case pendingKeysMessage:
+		s.pendingKeys = msg.keys
 		return s, nil
 	}
 
@@ -151,6 +156,105 @@ func (s *streamStatus) View() string {
 		return padded(s.err.Error())
 	}
 
+	sections, err := save.ParseStatusBarTemplate(s.deps.UserConfig.Settings.StatusBar.Template)
+	if err != nil {
+		// settings are validated when loaded, this only guards against an invalid template set at runtime
+		sections, _ = save.ParseStatusBarTemplate(save.DefaultStatusBarTemplate)
+	}
+
+	values := s.placeholderValues()
+
+	return padded(layoutStatusBar(
+		s.width,
+		renderStatusBarSection(sections[0], values),
+		renderStatusBarSection(sections[1], values),
+		renderStatusBarSection(sections[2], values),
+	))
+}
+
+// placeholderValues returns the functions resolving the placeholders of the status bar template, empty values hide their part
+func (s *streamStatus) placeholderValues() map[string]func() string {
+	highlight := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(s.deps.UserConfig.Theme.StatusColor))
+
+	return map[string]func() string{
+		"mode": s.modeView,
+		"keys": func() string {
+			if s.pendingKeys == "" {
+				return ""
+			}
+
+			return highlight.Render(s.pendingKeys)
+		},
+		"channel": func() string {
+			return s.tab.channelLogin
+		},
+		"uptime": func() string {
+			if s.tab.streamInfo == nil || s.tab.streamInfo.startedAt.IsZero() {
+				return ""
+			}
+
+			return formatUptime(time.Since(s.tab.streamInfo.startedAt))
+		},
+		"viewers": func() string {
+			if s.tab.streamInfo == nil || s.tab.streamInfo.startedAt.IsZero() {
+				return ""
+			}
+
+			return s.tab.streamInfo.printer.Sprintf("%d", s.tab.streamInfo.viewer)
+		},
+		"present": func() string {
+			if s.tab.presence == nil || !s.tab.presenceTracked() {
+				return ""
+			}
+
+			return strconv.Itoa(len(s.tab.presence.chatters))
+		},
+		"latency": func() string {
+			health, _ := s.tab.connectionHealth()
+			return formatLatency(health)
+		},
+		"time": func() string {
+			layout := "15:04"
+			if s.deps.UserConfig.Settings.Timestamps.Clock == save.TimestampClock12h {
+				layout = "03:04 PM"
+			}
+
+			return time.Now().Format(layout)
+		},
+		"unread": func() string {
+			if n := s.tab.chatWindow.entriesBelowSelection(); n > 0 {
+				return strconv.Itoa(n)
+			}
+
+			return ""
+		},
+		"chat_modes": s.chatModesView,
+		"obs": func() string {
+			if s.deps.OBS == nil {
+				return ""
+			}
+
+			return obsStatusView(s.deps.OBS.State(), s.deps.UserConfig.Theme)
+		},
+		"connection": func() string {
+			health, ok := s.tab.connectionHealth()
+			if !ok {
+				return ""
+			}
+
+			return connectionHealthView(health, s.deps.UserConfig.Theme, time.Now())
+		},
+		"degraded": func() string {
+			if !s.tab.degraded {
+				return ""
+			}
+
+			return degradedView(s.deps.EmoteCache.Degraded(s.channelID), s.deps.UserConfig.Theme)
+		},
+	}
+}
+
+func (s *streamStatus) modeView() string {
 	state := s.tab.state.String()
 	if s.tab.chatWindow.state == searchChatWindowState {
 		state = "Search"
@@ -160,61 +264,107 @@ func (s *streamStatus) View() string {
 		state = "Inspect / Search"
 	}
 
-	stateStr := fmt.Sprintf("-- %s --", state)
+	if cw := s.tab.activeChatWindow(); cw != nil && cw.state == visualChatWindowState {
+		state = "Visual"
+		if s.tab.state == userInspectMode {
+			state = "Inspect / Visual"
+		}
+	}
+
+	return fmt.Sprintf("-- %s --", state)
+}
+
+// chatModesView lists the active chat modes of the channel, like slow mode or sub only
+func (s *streamStatus) chatModesView() string {
+	highlight := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(s.deps.UserConfig.Theme.StatusColor))
 
-	settingsBuilder := strings.Builder{}
+	var modes []string
 
 	if s.settings.SlowMode {
 		dur := humanizeDuration(time.Duration(s.settings.SlowModeWaitTime) * time.Second)
-		settingsBuilder.WriteString("Slow Mode: ")
-		settingsBuilder.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(s.userConfig.Theme.StatusColor)).Render(dur))
+		modes = append(modes, "Slow Mode: "+highlight.Render(dur))
 	}
 
 	if s.settings.FollowerMode {
-		if settingsBuilder.Len() > 0 {
-			settingsBuilder.WriteString(" | ")
-		}
-
 		dur := humanizeDuration(time.Duration(s.settings.FollowerModeDuration) * time.Minute)
-		settingsBuilder.WriteString("Follow Only: ")
-		settingsBuilder.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(s.userConfig.Theme.StatusColor)).Render(dur))
+		modes = append(modes, "Follow Only: "+highlight.Render(dur))
 	}
 
 	if s.settings.SubscriberMode {
-		if settingsBuilder.Len() > 0 {
-			settingsBuilder.WriteString(" | ")
-		}
-
-		settingsBuilder.WriteString("Sub Only")
+		modes = append(modes, "Sub Only")
 	}
 
 	if s.tab.isLocalSub {
-		if settingsBuilder.Len() > 0 {
-			settingsBuilder.WriteString(" | ")
-		}
-		settingsBuilder.WriteString("Local Sub Only")
+		modes = append(modes, "Local Sub Only")
 	}
 
 	if s.tab.isUniqueOnlyChat {
-		if settingsBuilder.Len() > 0 {
-			settingsBuilder.WriteString(" | ")
-		}
-		settingsBuilder.WriteString("Unique Only")
+		modes = append(modes, "Unique Only")
 	}
 
 	if s.settings.EmoteMode {
-		if settingsBuilder.Len() > 0 {
-			settingsBuilder.WriteString(" | ")
-		}
-		settingsBuilder.WriteString("Emote Only")
+		modes = append(modes, "Emote Only")
 	}
 
 	if s.settings.UniqueChatMode {
-		if settingsBuilder.Len() > 0 {
-			settingsBuilder.WriteString(" | ")
+		modes = append(modes, "Unique Only")
+	}
+
+	return strings.Join(modes, " | ")
+}
+
+// renderStatusBarSection replaces the placeholders of a template section. Parts of the section are separated by " | ",
+// a part is hidden when all its placeholders are empty, like "{viewers} viewers" while the channel is offline.
+func renderStatusBarSection(section string, values map[string]func() string) string {
+	var rendered []string
+
+	for part := range strings.SplitSeq(section, " | ") {
+		var hasPlaceholder, hasValue bool
+
+		for name, value := range values {
+			placeholder := "{" + name + "}"
+			if !strings.Contains(part, placeholder) {
+				continue
+			}
+
+			v := value()
+			hasPlaceholder = true
+			hasValue = hasValue || v != ""
+			part = strings.ReplaceAll(part, placeholder, v)
+		}
+
+		part = strings.TrimSpace(part)
+		if part == "" || hasPlaceholder && !hasValue {
+			continue
 		}
-		settingsBuilder.WriteString("Unique Only")
+
+		rendered = append(rendered, part)
+	}
+
+	return strings.Join(rendered, " | ")
+}
+
+// layoutStatusBar aligns the left, center and right section in a line of width.
+// The center section is centered in the whole line if there is room, otherwise it follows the left section.
+func layoutStatusBar(width int, left, center, right string) string {
+	line := left
+
+	if center != "" {
+		line += statusBarGap((width-lipgloss.Width(center))/2-lipgloss.Width(line), line != "") + center
+	}
+
+	if right != "" {
+		line += statusBarGap(width-lipgloss.Width(line)-lipgloss.Width(right), line != "") + right
+	}
+
+	return line
+}
+
+// statusBarGap returns n spaces, at least one if separate is set
+func statusBarGap(n int, separate bool) string {
+	if separate {
+		n = max(n, 1)
 	}
 
-	return padded(stateStr + lipgloss.NewStyle().AlignHorizontal(lipgloss.Right).Width(s.width-lipgloss.Width(stateStr)).Render(settingsBuilder.String()))
+	return strings.Repeat(" ", max(n, 0))
 }
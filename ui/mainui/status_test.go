@@ -3,6 +3,8 @@ package mainui
 import (
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func Test_humanizeDuration(t *testing.T) {
@@ -43,3 +45,55 @@ func Test_humanizeDuration(t *testing.T) {
 		})
 	}
 }
+
+func Test_renderStatusBarSection(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]func() string{
+		"channel": func() string { return "julez" },
+		"viewers": func() string { return "" },
+		"unread":  func() string { return "3" },
+	}
+
+	tests := []struct {
+		name    string
+		section string
+		want    string
+	}{
+		{name: "placeholders", section: "#{channel} ({unread} new)", want: "#julez (3 new)"},
+		{name: "empty part is hidden", section: "#{channel} | {viewers} viewers | {unread} new", want: "#julez | 3 new"},
+		{name: "text only part is kept", section: "chatuino | {viewers} viewers", want: "chatuino"},
+		{name: "empty section", section: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, renderStatusBarSection(tt.section, values))
+		})
+	}
+}
+
+func Test_layoutStatusBar(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		width               int
+		left, center, right string
+		want                string
+	}{
+		{name: "left and right", width: 12, left: "ab", right: "cd", want: "ab        cd"},
+		{name: "centered", width: 12, left: "ab", center: "xy", right: "cd", want: "ab   xy   cd"},
+		{name: "center after long left", width: 12, left: "abcdefgh", center: "xy", want: "abcdefgh xy"},
+		{name: "too narrow", width: 4, left: "ab", right: "cd", want: "ab cd"},
+		{name: "right only", width: 6, right: "cd", want: "    cd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, layoutStatusBar(tt.width, tt.left, tt.center, tt.right))
+		})
+	}
+}
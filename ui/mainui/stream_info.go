@@ -2,6 +2,7 @@ package mainui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -9,21 +10,34 @@ import (
 	"golang.org/x/text/message"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/julez-dev/reflow/wordwrap"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
 )
 
+// channelUpdateGrace is how long the title and category of a channel.update event are preferred over refreshed ones
+const channelUpdateGrace = time.Minute * 3
+
 type streamInfo struct {
 	channelID string
 	ttvAPI    APIClient
 	printer   *message.Printer
 
-	width  int
-	loaded bool
+	width    int
+	loaded   bool
+	notes    []save.Note // notes of the channel, the latest is shown below the stream info
+	timers   string      // state of the timers of the channel, empty without timers
+	activity string      // chat activity graph, empty if disabled or without recent messages
 
 	// data
-	viewer int
-	title  string
-	game   string
+	viewer    int
+	title     string
+	game      string
+	uptime    time.Duration
+	startedAt time.Time // zero while offline, used by the status bar to show the current uptime
+
+	// title and category from the latest refresh or channel.update event, also known while offline
+	knownTitle, knownGame string
+	channelUpdatedAt      time.Time // time of the latest channel.update event
 }
 
 func newStreamInfo(channelID string, ttvAPI APIClient, width int) *streamInfo {
@@ -48,26 +62,101 @@ func (s *streamInfo) Update(msg tea.Msg) (*streamInfo, tea.Cmd) {
 			return s, nil
 		}
 		s.loaded = true
-		s.game = msg.game
-		s.title = msg.title
+		s.game = termtext.Sanitize(msg.game)
+		s.title = termtext.Sanitize(msg.title)
 		s.viewer = msg.viewer
 
+		// the API may still return the previous title and category shortly after a channel.update event
+		if msg.isLive && time.Since(s.channelUpdatedAt) < channelUpdateGrace {
+			s.game, s.title = s.knownGame, s.knownTitle
+		}
+
+		if msg.isLive {
+			s.knownGame, s.knownTitle = s.game, s.title
+		}
+
+		// uptime is only updated with each refresh, so the height of the info does not change between renders
+		s.uptime = 0
+		s.startedAt = time.Time{}
+		if msg.isLive {
+			s.uptime = time.Since(msg.startedAt)
+			s.startedAt = msg.startedAt
+		}
+
 		return s, nil
 	}
 	return s, nil
 }
 
+// applyChannelUpdate shows the title and category of a channel.update event without waiting for the next refresh
+// and returns a notice describing the change, empty if nothing changed
+func (s *streamInfo) applyChannelUpdate(title, game string, at time.Time) string {
+	title, game = termtext.Sanitize(title), termtext.Sanitize(game)
+
+	notice := channelUpdateNotice(s.knownTitle, s.knownGame, title, game)
+
+	s.knownTitle, s.knownGame = title, game
+	s.channelUpdatedAt = at
+
+	// offline channels show no stream info
+	if !s.startedAt.IsZero() {
+		s.title, s.game = title, game
+	}
+
+	return notice
+}
+
+// channelUpdateNotice describes a change of the title or category
+func channelUpdateNotice(prevTitle, prevGame, title, game string) string {
+	switch {
+	case prevGame != game && prevTitle != title:
+		return fmt.Sprintf("Category changed to %s, title changed to %q", game, title)
+	case prevGame != game:
+		return fmt.Sprintf("Category changed to %s", game)
+	case prevTitle != title:
+		return fmt.Sprintf("Title changed to %q", title)
+	}
+
+	return ""
+}
+
 func (s *streamInfo) View() string {
-	if !s.loaded {
-		return ""
-		//return centerTextGraphemeAware(s.width, "loading stream info\n")
+	var text string
+
+	if s.loaded && (s.game != "" || s.viewer != 0 || s.title != "") {
+		details := s.printer.Sprintf("%d Viewer", s.viewer)
+		if s.uptime > 0 {
+			details += ", Uptime: " + formatUptime(s.uptime)
+		}
+
+		text = s.printer.Sprintf("%s - %s (%s)", s.game, s.title, details)
+		if s.activity != "" {
+			text += " " + s.activity
+		}
+
+		text += "\n"
+	} else if s.activity != "" {
+		text = "Chat " + s.activity + "\n"
+	}
+
+	if len(s.notes) > 0 {
+		text += "Note " + formatNote(s.notes[len(s.notes)-1])
+		if len(s.notes) > 1 {
+			text += fmt.Sprintf(" (+%d more, /notes)", len(s.notes)-1)
+		}
+
+		text += "\n"
+	}
+
+	if s.timers != "" {
+		text += "Timers: " + s.timers + "\n"
 	}
 
-	if s.game == "" && s.viewer == 0 && s.title == "" {
+	if text == "" {
 		return ""
 	}
 
-	info := wordwrap.String(s.printer.Sprintf("%s - %s (%d Viewer)\n", s.game, s.title, s.viewer), s.width-10)
+	info := wrapText(text, s.width-10)
 	infoSplit := strings.Split(info, "\n")
 
 	for i, v := range infoSplit {
@@ -93,11 +182,26 @@ func (s *streamInfo) refreshStreamInfo() tea.Msg {
 	}
 
 	return setStreamInfoMessage{
-		target:   s.channelID,
-		viewer:   info.Data[0].ViewerCount,
-		title:    info.Data[0].Title,
-		game:     info.Data[0].GameName,
-		username: info.Data[0].UserName,
-		isLive:   !info.Data[0].StartedAt.IsZero(),
+		target:    s.channelID,
+		viewer:    info.Data[0].ViewerCount,
+		title:     info.Data[0].Title,
+		game:      info.Data[0].GameName,
+		username:  info.Data[0].UserName,
+		isLive:    !info.Data[0].StartedAt.IsZero(),
+		startedAt: info.Data[0].StartedAt,
 	}
 }
+
+// formatUptime formats the duration a stream is live like "2h 05m"
+func formatUptime(d time.Duration) string {
+	d = max(d, 0).Truncate(time.Minute)
+
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+
+	return fmt.Sprintf("%dh %02dm", hours, minutes)
+}
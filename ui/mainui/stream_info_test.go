@@ -0,0 +1,61 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatUptime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     string
+	}{
+		{name: "negative", duration: -time.Minute, want: "0m"},
+		{name: "zero", duration: 0, want: "0m"},
+		{name: "seconds truncated", duration: 59 * time.Second, want: "0m"},
+		{name: "minutes", duration: 42 * time.Minute, want: "42m"},
+		{name: "one hour", duration: time.Hour, want: "1h 00m"},
+		{name: "hours and minutes", duration: 2*time.Hour + 5*time.Minute + 30*time.Second, want: "2h 05m"},
+		{name: "more than a day", duration: 26*time.Hour + 15*time.Minute, want: "26h 15m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := formatUptime(tt.duration); got != tt.want {
+				t.Errorf("formatUptime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_streamInfo_applyChannelUpdate(t *testing.T) {
+	t.Parallel()
+
+	s := newStreamInfo("1", nil, 80)
+	startedAt := time.Now().Add(-time.Hour)
+
+	s, _ = s.Update(setStreamInfoMessage{target: "1", title: "chill stream", game: "Just Chatting", isLive: true, startedAt: startedAt})
+
+	require.Equal(t, `Category changed to Minecraft, title changed to "building"`, s.applyChannelUpdate("building", "Minecraft", time.Now()))
+	require.Equal(t, "Minecraft", s.game, "shown without waiting for the next refresh")
+	require.Equal(t, "building", s.title)
+
+	// a refresh right after the event may still return the previous title and category
+	s, _ = s.Update(setStreamInfoMessage{target: "1", title: "chill stream", game: "Just Chatting", isLive: true, startedAt: startedAt})
+	require.Equal(t, "Minecraft", s.game)
+	require.Equal(t, "building", s.title)
+
+	require.Equal(t, `Title changed to "building a castle"`, s.applyChannelUpdate("building a castle", "Minecraft", time.Now()))
+	require.Empty(t, s.applyChannelUpdate("building a castle", "Minecraft", time.Now()), "nothing changed")
+
+	// offline channels show no stream info, but the change is still announced
+	s, _ = s.Update(setStreamInfoMessage{target: "1"})
+	require.Equal(t, "Category changed to Art", s.applyChannelUpdate("building a castle", "Art", time.Now()))
+	require.Empty(t, s.game)
+}
@@ -10,6 +10,8 @@ type tabHeaderEntry struct {
 	identity        string
 	selected        bool
 	hasNotification bool
+	hasDraft        bool // unsent text in the message input
+	unread          int  // messages received while the tab was not selected
 }
 
 func (t tabHeaderEntry) FilterValue() string {
@@ -19,6 +21,16 @@ func (t tabHeaderEntry) FilterValue() string {
 func (t tabHeaderEntry) render() string {
 	base := fmt.Sprintf("%s (%s)", t.name, t.identity)
 
+	if t.hasDraft {
+		base += "[✎]"
+	}
+
+	if t.unread > 99 {
+		base += "[99+]"
+	} else if t.unread > 0 {
+		base += fmt.Sprintf("[%d]", t.unread)
+	}
+
 	if t.hasNotification {
 		return base + "[!]"
 	}
@@ -0,0 +1,457 @@
+package mainui
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// maxThreadDepth limits the indentation of nested replies, deeper replies are shown at this depth
+const maxThreadDepth = 6
+
+// openThreadMessage is sent by a tab to show the reply thread of the selected message
+type openThreadMessage struct {
+	channel  string // login of the channel, used to read the chat logs
+	rootID   string
+	selected string                      // id of the message the thread was opened on
+	messages []*twitchirc.PrivateMessage // messages of the thread found in the chat buffer
+}
+
+// threadHistoryLoadedMessage carries the thread including the messages found in the chat logs, results of a previously opened view are ignored
+type threadHistoryLoadedMessage struct {
+	generation int
+	messages   []*twitchirc.PrivateMessage
+	err        error
+}
+
+// threadRootID returns the id of the first message of the thread the message belongs to
+func threadRootID(msg *twitchirc.PrivateMessage) string {
+	switch {
+	case msg.ThreadParentMsgID != "":
+		return msg.ThreadParentMsgID
+	case msg.ParentMsgID != "":
+		return msg.ParentMsgID
+	}
+
+	return msg.ID
+}
+
+// collectThread returns the messages of the thread starting at rootID, oldest first.
+// Replies without thread tags only reference the message they reply to, so replies to replies are followed as well.
+func collectThread(rootID string, candidates []*twitchirc.PrivateMessage) []*twitchirc.PrivateMessage {
+	thread := map[string]*twitchirc.PrivateMessage{}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, m := range candidates {
+			if _, ok := thread[m.ID]; ok {
+				continue
+			}
+
+			_, parentInThread := thread[m.ParentMsgID]
+			if m.ID == rootID || m.ThreadParentMsgID == rootID || m.ParentMsgID == rootID || m.ParentMsgID != "" && parentInThread {
+				thread[m.ID] = m
+				changed = true
+			}
+		}
+	}
+
+	messages := make([]*twitchirc.PrivateMessage, 0, len(thread))
+	for _, m := range thread {
+		messages = append(messages, m)
+	}
+
+	slices.SortStableFunc(messages, func(a, b *twitchirc.PrivateMessage) int {
+		return cmp.Or(a.TMISentTS.Compare(b.TMISentTS), strings.Compare(a.ID, b.ID))
+	})
+
+	return messages
+}
+
+// threadLine is a message of the thread at its depth in the reply tree
+type threadLine struct {
+	message     *twitchirc.PrivateMessage
+	depth       int
+	placeholder bool // the message is not in the buffer or logs, it is built from the reply tags of a reply to it
+}
+
+// buildThreadTree orders the messages depth first, replies follow the message they reply to.
+// Messages which are only known from the reply tags of their replies are added as placeholders.
+func buildThreadTree(rootID string, messages []*twitchirc.PrivateMessage) []threadLine {
+	byID := make(map[string]*twitchirc.PrivateMessage, len(messages))
+	for _, m := range messages {
+		byID[m.ID] = m
+	}
+
+	placeholders := map[string]bool{}
+	all := slices.Clone(messages)
+
+	for _, m := range messages {
+		if m.ParentMsgID == "" || byID[m.ParentMsgID] != nil {
+			continue
+		}
+
+		parent := &twitchirc.PrivateMessage{
+			ID:          m.ParentMsgID,
+			UserID:      m.ParentUserID,
+			LoginName:   m.ParentUserLogin,
+			DisplayName: m.ParentDisplayName,
+			Message:     m.ParentMsgBody,
+			TMISentTS:   m.TMISentTS,
+		}
+
+		byID[parent.ID] = parent
+		placeholders[parent.ID] = true
+		all = append(all, parent)
+	}
+
+	// replies to replies may reference the thread without any reply to the first message being known
+	if byID[rootID] == nil && len(messages) > 0 {
+		root := &twitchirc.PrivateMessage{ID: rootID, LoginName: messages[0].ThreadParentUserLogin, DisplayName: messages[0].ThreadParentUserLogin}
+		byID[rootID] = root
+		placeholders[rootID] = true
+		all = append(all, root)
+	}
+
+	children := map[string][]*twitchirc.PrivateMessage{}
+	for _, m := range all {
+		if m.ID == rootID {
+			continue
+		}
+
+		parent := rootID
+		if m.ParentMsgID != "" && m.ParentMsgID != m.ID && byID[m.ParentMsgID] != nil {
+			parent = m.ParentMsgID
+		}
+
+		children[parent] = append(children[parent], m)
+	}
+
+	for _, c := range children {
+		slices.SortStableFunc(c, func(a, b *twitchirc.PrivateMessage) int {
+			return a.TMISentTS.Compare(b.TMISentTS)
+		})
+	}
+
+	lines := make([]threadLine, 0, len(all))
+	visited := map[string]bool{}
+
+	var walk func(m *twitchirc.PrivateMessage, depth int)
+	walk = func(m *twitchirc.PrivateMessage, depth int) {
+		if visited[m.ID] {
+			return
+		}
+
+		visited[m.ID] = true
+		lines = append(lines, threadLine{message: m, depth: depth, placeholder: placeholders[m.ID]})
+
+		for _, c := range children[m.ID] {
+			walk(c, depth+1)
+		}
+	}
+
+	if root := byID[rootID]; root != nil {
+		walk(root, 0)
+	}
+
+	return lines
+}
+
+// handleOpenThread opens the thread view for the selected message, if it is part of a reply thread
+func (t *broadcastTab) handleOpenThread() tea.Cmd {
+	cw := t.activeChatWindow()
+	if cw == nil {
+		return nil
+	}
+
+	_, entry := cw.entryForCurrentCursor()
+	if entry == nil {
+		return nil
+	}
+
+	selected, ok := entry.Event.message.(*twitchirc.PrivateMessage)
+	if !ok {
+		return nil
+	}
+
+	var buffered []*twitchirc.PrivateMessage
+	for _, e := range t.chatWindow.entries {
+		if m, ok := e.Event.message.(*twitchirc.PrivateMessage); ok && m.ID != "" {
+			buffered = append(buffered, m)
+		}
+	}
+
+	rootID := threadRootID(selected)
+	messages := collectThread(rootID, buffered)
+
+	// messages without reply tags are only a thread if somebody replied to them
+	if selected.ParentMsgID == "" && len(messages) < 2 {
+		notice := t.localNotice()
+		return func() tea.Msg {
+			return notice("The message is not part of a reply thread")
+		}
+	}
+
+	msg := openThreadMessage{
+		channel:  t.channelLogin,
+		rootID:   rootID,
+		selected: selected.ID,
+		messages: messages,
+	}
+
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// threadViewer shows a reply thread as a tree, the chat logs are searched for more messages of the thread while it is open
+type threadViewer struct {
+	deps          *DependencyContainer
+	width, height int
+	generation    int
+
+	channel  string
+	rootID   string
+	selected string
+	messages []*twitchirc.PrivateMessage
+
+	loading    bool  // the chat logs are being read
+	historyErr error // reading the chat logs failed
+	scroll     int   // number of lines scrolled down
+}
+
+func newThreadViewer(width, height int, generation int, msg openThreadMessage, deps *DependencyContainer) *threadViewer {
+	v := &threadViewer{
+		deps:       deps,
+		generation: generation,
+		channel:    msg.channel,
+		rootID:     msg.rootID,
+		selected:   msg.selected,
+		messages:   msg.messages,
+	}
+	v.handleResize(width, height)
+
+	return v
+}
+
+func (v *threadViewer) handleResize(width, height int) {
+	// the viewer is shown as a modal, leave some space to the terminal border
+	v.width = max(width-4, 20)
+	v.height = max(height-4, 8)
+	v.scroll = min(v.scroll, v.maxScroll())
+}
+
+// loadHistory searches the chat logs for messages of the thread, if chat logging is enabled
+func (v *threadViewer) loadHistory() tea.Cmd {
+	logger := v.deps.MessageLogger
+	if logger == nil {
+		return nil
+	}
+
+	v.loading = true
+	generation, channel, rootID, buffered := v.generation, v.channel, v.rootID, v.messages
+
+	return func() tea.Msg {
+		entries, err := logger.MessagesInChannel(channel)
+		if err != nil {
+			return threadHistoryLoadedMessage{generation: generation, err: err}
+		}
+
+		// messages of the buffer come first, so they are kept over their logged copies
+		candidates := slices.Clone(buffered)
+		for _, e := range entries {
+			candidates = append(candidates, e.PrivateMessage)
+		}
+
+		return threadHistoryLoadedMessage{generation: generation, messages: collectThread(rootID, candidates)}
+	}
+}
+
+func (v *threadViewer) Update(msg tea.Msg) (*threadViewer, tea.Cmd) {
+	switch msg := msg.(type) {
+	case threadHistoryLoadedMessage:
+		if msg.generation != v.generation {
+			return v, nil
+		}
+
+		v.loading = false
+		if msg.err != nil {
+			v.historyErr = msg.err
+			return v, nil
+		}
+
+		v.messages = msg.messages
+		v.scroll = min(v.scroll, v.maxScroll())
+
+		return v, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, v.deps.Keymap.Up):
+			v.scroll = max(v.scroll-1, 0)
+		case key.Matches(msg, v.deps.Keymap.Down):
+			v.scroll = min(v.scroll+1, v.maxScroll())
+		case key.Matches(msg, v.deps.Keymap.GoToTop):
+			v.scroll = 0
+		case key.Matches(msg, v.deps.Keymap.GoToBottom):
+			v.scroll = v.maxScroll()
+		}
+	}
+
+	return v, nil
+}
+
+func (v *threadViewer) innerWidth() int {
+	return v.width - 4
+}
+
+func (v *threadViewer) listHeight() int {
+	// title, help, status, blank line, borders and padding
+	return max(v.height-8, 1)
+}
+
+func (v *threadViewer) maxScroll() int {
+	return max(len(v.lines())-v.listHeight(), 0)
+}
+
+// lines renders the thread, long messages are wrapped below their author
+func (v *threadViewer) lines() []string {
+	theme := v.deps.UserConfig.Theme
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ListLabelColor)).Bold(true)
+
+	var lines []string
+
+	for _, l := range buildThreadTree(v.rootID, v.messages) {
+		m := l.message
+		indent := strings.Repeat("  ", min(l.depth, maxThreadDepth))
+		if l.depth > 0 {
+			indent = indent[:len(indent)-2] + "↳ "
+		}
+
+		name := m.DisplayName
+		if name == "" {
+			name = m.LoginName
+		}
+
+		// like in chat, aliases replace the name and the message is sanitized and masked
+		if alias := v.deps.UserConfig.Settings.Chat.AliasFor(m.LoginName); alias != "" {
+			name = alias
+		}
+
+		name = termtext.Sanitize(name)
+
+		if color := m.Color; color != "" {
+			if v.deps.UserConfig.Settings.Accessibility.UsernameColors == save.UsernameColorsColorblind {
+				color = colorblindUserColor(color)
+			}
+
+			name = lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(name)
+		}
+
+		var text string
+		switch {
+		case l.placeholder && m.Message == "":
+			text = name + ": " + dimmedStyle.Render("message not available")
+		case l.placeholder:
+			text = name + ": " + v.messageText(m.Message) + " " + dimmedStyle.Render("(not in chat)")
+		default:
+			text = dimmedStyle.Render(m.TMISentTS.Local().Format("15:04:05")) + " " + name + ": " + v.messageText(trimReplyMention(m))
+		}
+
+		if m.ID == v.selected {
+			text = selectedStyle.Render("▶ ") + text
+		}
+
+		padding := strings.Repeat(" ", ansi.StringWidth(indent))
+		for i, wrapped := range strings.Split(ansi.Wrap(text, max(v.innerWidth()-len(padding), 10), ""), "\n") {
+			if i == 0 {
+				lines = append(lines, indent+wrapped)
+				continue
+			}
+
+			lines = append(lines, padding+wrapped)
+		}
+	}
+
+	return lines
+}
+
+// messageText returns the text of a message like the chat shows it, without control sequences and with profanity masked
+func (v *threadViewer) messageText(text string) string {
+	return termtext.Sanitize(maskProfanity(v.deps, v.channel, text))
+}
+
+// trimReplyMention removes the mention of the parent author, which Twitch puts in front of every reply
+func trimReplyMention(m *twitchirc.PrivateMessage) string {
+	if m.ParentMsgID == "" {
+		return m.Message
+	}
+
+	mention, rest, ok := strings.Cut(m.Message, " ")
+	if !ok || !strings.HasPrefix(mention, "@") {
+		return m.Message
+	}
+
+	if name := mention[1:]; strings.EqualFold(name, m.ParentUserLogin) || strings.EqualFold(name, m.ParentDisplayName) {
+		return rest
+	}
+
+	return m.Message
+}
+
+func (v *threadViewer) View() string {
+	theme := v.deps.UserConfig.Theme
+	innerWidth := v.innerWidth()
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Width(innerWidth).AlignHorizontal(lipgloss.Center)
+	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor))
+
+	b := &strings.Builder{}
+
+	_, _ = b.WriteString(titleStyle.Render("Reply Thread") + "\n")
+	_, _ = b.WriteString(titleStyle.Inherit(dimmedStyle).Bold(false).Render(
+		v.deps.Keymap.Up.Help().Key+"/"+v.deps.Keymap.Down.Help().Key+" scroll · "+
+			v.deps.Keymap.Escape.Help().Key+" close",
+	) + "\n")
+
+	status := strconv.Itoa(len(v.messages)) + " messages"
+	switch {
+	case v.loading:
+		status += " · searching chat logs…"
+	case v.historyErr != nil:
+		status += " · chat logs could not be read: " + v.historyErr.Error()
+	case v.deps.MessageLogger == nil:
+		status += " from the chat buffer"
+	default:
+		status += " from the chat buffer and logs"
+	}
+
+	_, _ = b.WriteString(ansi.Truncate(dimmedStyle.Render(status), innerWidth, "…") + "\n\n")
+
+	all := v.lines()
+	lines := all[min(v.scroll, len(all)):min(v.scroll+v.listHeight(), len(all))]
+
+	// fill up the list, so the border doesn't jump
+	for len(lines) < v.listHeight() {
+		lines = append(lines, "")
+	}
+
+	_, _ = b.WriteString(strings.Join(lines, "\n"))
+
+	return lipgloss.NewStyle().
+		Width(v.width).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.ListLabelColor)).
+		Render(b.String())
+}
@@ -0,0 +1,192 @@
+package mainui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/profanity"
+	"github.com/julez-dev/chatuino/save/messagelog"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeThreadLogger struct {
+	MessageLogger
+	entries []messagelog.LogEntry
+	err     error
+}
+
+func (f fakeThreadLogger) MessagesInChannel(string) ([]messagelog.LogEntry, error) {
+	return f.entries, f.err
+}
+
+// threadMessage builds a message, replies carry the reply tags of parent and the thread tags of root, if set
+func threadMessage(id, name, text string, sec int, parent *twitchirc.PrivateMessage, root string) *twitchirc.PrivateMessage {
+	m := &twitchirc.PrivateMessage{
+		ID:          id,
+		LoginName:   name,
+		DisplayName: name,
+		Message:     text,
+		TMISentTS:   time.Date(2026, 1, 1, 12, 0, sec, 0, time.UTC),
+	}
+
+	if parent != nil {
+		m.ParentMsgID = parent.ID
+		m.ParentUserLogin = parent.LoginName
+		m.ParentDisplayName = parent.DisplayName
+		m.ParentMsgBody = parent.Message
+		m.ThreadParentMsgID = root
+		m.Message = "@" + parent.DisplayName + " " + text
+	}
+
+	return m
+}
+
+func Test_collectThread(t *testing.T) {
+	t.Parallel()
+
+	root := threadMessage("1", "alice", "who plays tonight?", 1, nil, "")
+	reply := threadMessage("2", "bob", "team liquid", 2, root, "1")
+	other := threadMessage("3", "carol", "hello chat", 3, nil, "")
+	nested := threadMessage("4", "alice", "thanks", 4, reply, "1")
+	// replies without thread tags are found through the message they reply to
+	untagged := threadMessage("5", "dave", "same", 5, nested, "")
+
+	require.Equal(t, "1", threadRootID(nested))
+	require.Equal(t, "4", threadRootID(untagged))
+	require.Equal(t, "3", threadRootID(other))
+
+	thread := collectThread("1", []*twitchirc.PrivateMessage{untagged, other, nested, reply, root})
+	require.Equal(t, []*twitchirc.PrivateMessage{root, reply, nested, untagged}, thread)
+
+	require.Equal(t, []*twitchirc.PrivateMessage{other}, collectThread("3", []*twitchirc.PrivateMessage{root, other, reply}))
+}
+
+func Test_buildThreadTree(t *testing.T) {
+	t.Parallel()
+
+	root := threadMessage("1", "alice", "who plays tonight?", 1, nil, "")
+	first := threadMessage("2", "bob", "team liquid", 2, root, "1")
+	second := threadMessage("3", "carol", "no idea", 3, root, "1")
+	nested := threadMessage("4", "alice", "thanks", 4, first, "1")
+
+	lines := buildThreadTree("1", []*twitchirc.PrivateMessage{root, first, second, nested})
+
+	var got []string
+	for _, l := range lines {
+		got = append(got, strings.Repeat(">", l.depth)+l.message.ID)
+	}
+
+	require.Equal(t, []string{"1", ">2", ">>4", ">3"}, got)
+
+	// the first message is built from the reply tags if it was sent before the buffer starts
+	lines = buildThreadTree("1", []*twitchirc.PrivateMessage{first, nested})
+	require.Len(t, lines, 3)
+	require.True(t, lines[0].placeholder)
+	require.Equal(t, "who plays tonight?", lines[0].message.Message)
+	require.Equal(t, "alice", lines[0].message.DisplayName)
+	require.Equal(t, 2, lines[2].depth)
+}
+
+func Test_trimReplyMention(t *testing.T) {
+	t.Parallel()
+
+	root := threadMessage("1", "alice", "who plays tonight?", 1, nil, "")
+	reply := threadMessage("2", "bob", "team liquid", 2, root, "1")
+
+	require.Equal(t, "team liquid", trimReplyMention(reply))
+	require.Equal(t, "who plays tonight?", trimReplyMention(root))
+
+	reply.Message = "@ALICE team liquid"
+	require.Equal(t, "team liquid", trimReplyMention(reply), "mentions are matched ignoring case")
+
+	reply.Message = "@carol team liquid"
+	require.Equal(t, "@carol team liquid", trimReplyMention(reply))
+}
+
+func Test_threadViewer(t *testing.T) {
+	t.Parallel()
+
+	root := threadMessage("1", "alice", "who plays tonight?", 1, nil, "")
+	reply := threadMessage("2", "bob", "team liquid", 2, root, "1")
+	logged := threadMessage("3", "carol", "they won yesterday", 3, reply, "1")
+
+	deps := newTestDeps(t)
+	deps.MessageLogger = fakeThreadLogger{entries: []messagelog.LogEntry{
+		{PrivateMessage: root},
+		{PrivateMessage: threadMessage("9", "dave", "hello", 4, nil, "")},
+		{PrivateMessage: logged},
+	}}
+
+	msg := openThreadMessage{channel: "esl", rootID: "1", selected: "2", messages: []*twitchirc.PrivateMessage{reply}}
+	v := newThreadViewer(80, 30, 1, msg, deps)
+
+	cmd := v.loadHistory()
+	require.True(t, v.loading)
+	require.Contains(t, ansi.Strip(v.View()), "searching chat logs")
+
+	loaded := cmd().(threadHistoryLoadedMessage)
+
+	// results of a previously opened viewer are ignored
+	v.Update(threadHistoryLoadedMessage{generation: 0})
+	require.True(t, v.loading)
+
+	v.Update(loaded)
+	require.False(t, v.loading)
+	require.Equal(t, []*twitchirc.PrivateMessage{root, reply, logged}, v.messages)
+
+	view := ansi.Strip(v.View())
+	require.Contains(t, view, "3 messages from the chat buffer and logs")
+	require.Contains(t, view, "alice: who plays tonight?")
+	require.Contains(t, view, "▶ "+reply.TMISentTS.Local().Format("15:04:05")+" bob: team liquid")
+	require.Contains(t, view, "↳ "+logged.TMISentTS.Local().Format("15:04:05")+" carol: they won yesterday")
+	require.NotContains(t, view, "dave")
+
+	// the chat buffer is still shown if the logs can't be read
+	deps.MessageLogger = fakeThreadLogger{err: errors.New("database is locked")}
+	v = newThreadViewer(80, 30, 2, msg, deps)
+	v.Update(v.loadHistory()())
+	require.Equal(t, []*twitchirc.PrivateMessage{reply}, v.messages)
+	require.Contains(t, ansi.Strip(v.View()), "chat logs could not be read: database is locked")
+
+	// long threads are scrolled
+	var messages []*twitchirc.PrivateMessage
+	for i := range 40 {
+		messages = append(messages, threadMessage(string(rune('a'+i)), "bob", "reply", i, root, "1"))
+	}
+
+	deps.MessageLogger = nil
+	v = newThreadViewer(80, 30, 3, openThreadMessage{rootID: "1", messages: append(messages, root)}, deps)
+	require.Nil(t, v.loadHistory())
+	require.Equal(t, 23, v.maxScroll(), "41 lines with room for 18")
+
+	v.Update(tea.KeyMsg{Type: tea.KeyDown})
+	require.Equal(t, 1, v.scroll)
+	v.Update(tea.KeyMsg{Type: tea.KeyUp})
+	v.Update(tea.KeyMsg{Type: tea.KeyUp})
+	require.Equal(t, 0, v.scroll)
+}
+
+func Test_threadViewer_formatsMessages(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.UserConfig.Settings.Profanity.Mask = true
+	deps.UserConfig.Settings.Chat.UserAliases = map[string]string{"bob": "Bobby"}
+	deps.Profanity = profanity.New([]string{"heck"})
+
+	root := threadMessage("1", "alice\x1b[2J", "what the heck\x1b]0;title\x07", 1, nil, "")
+	reply := threadMessage("2", "bob", "no idea", 2, root, "1")
+
+	v := newThreadViewer(80, 30, 1, openThreadMessage{channel: "esl", rootID: "1", messages: []*twitchirc.PrivateMessage{root, reply}}, deps)
+	view := v.View()
+
+	require.NotContains(t, view, "\x1b[2J")
+	require.NotContains(t, view, "\x1b]0;")
+	require.Contains(t, ansi.Strip(view), "alice: what the ****")
+	require.Contains(t, ansi.Strip(view), "Bobby: no idea")
+}
@@ -0,0 +1,100 @@
+package mainui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// chatThrottleFlushMessage is sent at the end of a second in which messages of the channel were skipped
+type chatThrottleFlushMessage struct {
+	accountID string
+	channel   string
+	second    time.Time
+}
+
+// chatThrottle limits the messages shown per channel and second during raids and big events
+type chatThrottle struct {
+	channels map[string]*throttleWindow // account ID and channel to the messages of the current second
+}
+
+type throttleWindow struct {
+	second  time.Time
+	count   int // messages received in second
+	skipped int // messages not shown in second
+}
+
+func newChatThrottle() *chatThrottle {
+	return &chatThrottle{channels: map[string]*throttleWindow{}}
+}
+
+// add counts a message of the channel and reports if it should be shown. A limit of 0 shows all messages, exempt messages are always shown.
+// If messages of the previous second were skipped, a notice summarizing them is returned, it should be shown before the message.
+func (t *chatThrottle) add(accountID, channel string, limit int, exempt bool, now time.Time) (bool, *twitchirc.Notice) {
+	key := accountID + "/" + channel
+	second := now.Truncate(time.Second)
+
+	window, ok := t.channels[key]
+	if !ok {
+		window = &throttleWindow{second: second}
+		t.channels[key] = window
+	}
+
+	var summary *twitchirc.Notice
+	if !window.second.Equal(second) {
+		summary = window.summary(channel, now)
+		*window = throttleWindow{second: second}
+	}
+
+	window.count++
+
+	if limit > 0 && window.count > limit && !exempt {
+		window.skipped++
+		return false, summary
+	}
+
+	return true, summary
+}
+
+// flushCommand waits for the end of the current second of the channel, so the skipped messages are summarized
+// even if no message of a later second arrives. Only the first skipped message of a second starts the wait.
+func (t *chatThrottle) flushCommand(accountID, channel string, now time.Time) tea.Cmd {
+	window, ok := t.channels[accountID+"/"+channel]
+	if !ok || window.skipped != 1 {
+		return nil
+	}
+
+	second := window.second
+
+	return tea.Tick(second.Add(time.Second).Sub(now), func(time.Time) tea.Msg {
+		return chatThrottleFlushMessage{accountID: accountID, channel: channel, second: second}
+	})
+}
+
+// flush returns the notice summarizing the skipped messages of second, nil if a message of a later second already returned it
+func (t *chatThrottle) flush(accountID, channel string, second, now time.Time) *twitchirc.Notice {
+	window, ok := t.channels[accountID+"/"+channel]
+	if !ok || !window.second.Equal(second) {
+		return nil
+	}
+
+	summary := window.summary(channel, now)
+	*window = throttleWindow{second: second.Add(time.Second)}
+
+	return summary
+}
+
+// summary returns a notice about the skipped messages, nil if no message was skipped
+func (w *throttleWindow) summary(channel string, now time.Time) *twitchirc.Notice {
+	if w.skipped == 0 {
+		return nil
+	}
+
+	return &twitchirc.Notice{
+		ChannelUserName: channel,
+		FakeTimestamp:   now,
+		Message:         fmt.Sprintf("Skipped %d of %d messages received in one second", w.skipped, w.count),
+	}
+}
@@ -0,0 +1,76 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatThrottle_add(t *testing.T) {
+	t.Parallel()
+
+	throttle := newChatThrottle()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for range 3 {
+		show, summary := throttle.add("1", "lirik", 3, false, now)
+		require.True(t, show)
+		require.Nil(t, summary)
+	}
+
+	show, _ := throttle.add("1", "lirik", 3, false, now.Add(100*time.Millisecond))
+	require.False(t, show)
+
+	show, _ = throttle.add("1", "lirik", 3, true, now.Add(200*time.Millisecond))
+	require.True(t, show, "exempt messages are always shown")
+
+	// other channels and accounts are counted separately
+	show, _ = throttle.add("2", "lirik", 3, false, now)
+	require.True(t, show)
+
+	show, summary := throttle.add("1", "lirik", 3, false, now.Add(time.Second))
+	require.True(t, show)
+	require.NotNil(t, summary)
+	require.Equal(t, "lirik", summary.ChannelUserName)
+	require.Equal(t, "Skipped 1 of 5 messages received in one second", summary.Message)
+
+	show, summary = throttle.add("1", "lirik", 0, false, now.Add(2*time.Second))
+	require.True(t, show, "a limit of 0 shows all messages")
+	require.Nil(t, summary)
+}
+
+func Test_chatThrottle_flush(t *testing.T) {
+	t.Parallel()
+
+	throttle := newChatThrottle()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	show, _ := throttle.add("1", "lirik", 1, false, now)
+	require.True(t, show)
+	require.Nil(t, throttle.flushCommand("1", "lirik", now), "nothing was skipped")
+
+	show, _ = throttle.add("1", "lirik", 1, false, now.Add(100*time.Millisecond))
+	require.False(t, show)
+	require.NotNil(t, throttle.flushCommand("1", "lirik", now.Add(100*time.Millisecond)))
+
+	// only the first skipped message of a second waits for its end
+	show, _ = throttle.add("1", "lirik", 1, false, now.Add(200*time.Millisecond))
+	require.False(t, show)
+	require.Nil(t, throttle.flushCommand("1", "lirik", now.Add(200*time.Millisecond)))
+
+	// the summary is shown at the end of the second, even if no further message arrives
+	summary := throttle.flush("1", "lirik", now, now.Add(time.Second))
+	require.NotNil(t, summary)
+	require.Equal(t, "Skipped 2 of 3 messages received in one second", summary.Message)
+
+	show, summary = throttle.add("1", "lirik", 1, false, now.Add(time.Second))
+	require.True(t, show)
+	require.Nil(t, summary, "the skipped messages were already summarized")
+
+	// flushes of a second summarized by a later message show nothing
+	throttle.add("1", "lirik", 1, false, now.Add(1100*time.Millisecond))
+	_, summary = throttle.add("1", "lirik", 1, false, now.Add(2*time.Second))
+	require.NotNil(t, summary)
+	require.Nil(t, throttle.flush("1", "lirik", now.Add(time.Second), now.Add(2*time.Second)))
+}
@@ -0,0 +1,200 @@
+package mainui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// timerCheckInterval is how often the timers of a tab are checked
+const timerCheckInterval = time.Second * 15
+
+type timerTickMessage struct {
+	tabID string
+}
+
+// channelTimer is a timer of the tab's channel with the state since its last message
+type channelTimer struct {
+	save.Timer
+	enabled  bool
+	lastSent time.Time // start of the interval, the time the timer was turned on if it didn't send yet
+	messages int       // chat messages of others since lastSent
+}
+
+// due reports whether the timer should send its message now
+func (c *channelTimer) due(now time.Time) bool {
+	return c.enabled && now.Sub(c.lastSent) >= c.Interval && c.messages >= c.MinMessages
+}
+
+// status describes the timer for the stream info, like "socials in 4m", "socials waiting for chat (2/5)" or "socials off"
+func (c *channelTimer) status(now time.Time) string {
+	if !c.enabled {
+		return c.Name + " off"
+	}
+
+	if left := c.Interval - now.Sub(c.lastSent); left > 0 {
+		return c.Name + " in " + formatUptime(left+time.Minute-1)
+	}
+
+	// only the chat activity is missing
+	return fmt.Sprintf("%s waiting for chat (%d/%d)", c.Name, c.messages, c.MinMessages)
+}
+
+func timerTickCommand(tabID string) tea.Cmd {
+	return tea.Tick(timerCheckInterval, func(_ time.Time) tea.Msg {
+		return timerTickMessage{tabID: tabID}
+	})
+}
+
+// syncTimers applies the timers of the settings to the tab, timers which already exist keep their state.
+// Returns the command checking the timers, if they are not checked yet.
+func (t *broadcastTab) syncTimers(now time.Time) tea.Cmd {
+	var configured []save.Timer
+	if !t.account.IsAnonymous {
+		configured = t.deps.UserConfig.Settings.TimersFor(t.channelLogin, t.account.DisplayName, t.account.ID == t.channelID)
+	}
+
+	timers := make([]*channelTimer, 0, len(configured))
+	for _, timer := range configured {
+		i := slices.IndexFunc(t.timers, func(c *channelTimer) bool {
+			return strings.EqualFold(c.Name, timer.Name)
+		})
+
+		if i == -1 {
+			timers = append(timers, &channelTimer{Timer: timer, enabled: !timer.Disabled, lastSent: now})
+			continue
+		}
+
+		t.timers[i].Timer = timer
+		timers = append(timers, t.timers[i])
+	}
+
+	t.timers = timers
+	t.updateTimerStatus(now)
+
+	if len(t.timers) == 0 || t.timersTicking {
+		return nil
+	}
+
+	t.timersTicking = true
+
+	return timerTickCommand(t.id)
+}
+
+// handleTimerTick sends the messages of due timers
+func (t *broadcastTab) handleTimerTick(now time.Time) tea.Cmd {
+	if len(t.timers) == 0 {
+		t.timersTicking = false
+		return nil
+	}
+
+	cmds := []tea.Cmd{timerTickCommand(t.id)}
+	for _, timer := range t.timers {
+		if !timer.due(now) {
+			continue
+		}
+
+		timer.lastSent = now
+		timer.messages = 0
+		cmds = append(cmds, t.sendMessage(timer.Message))
+	}
+
+	t.updateTimerStatus(now)
+
+	return tea.Batch(cmds...)
+}
+
+// recordTimerActivity counts a chat message for the minimum chat activity of the timers
+func (t *broadcastTab) recordTimerActivity(msg *twitchirc.PrivateMessage) {
+	if msg.UserID == t.account.ID {
+		return
+	}
+
+	for _, timer := range t.timers {
+		timer.messages++
+	}
+}
+
+// updateTimerStatus shows the state of the timers in the stream info
+func (t *broadcastTab) updateTimerStatus(now time.Time) {
+	heightBefore := lipgloss.Height(t.streamInfo.View())
+
+	statuses := make([]string, 0, len(t.timers))
+	for _, timer := range t.timers {
+		statuses = append(statuses, timer.status(now))
+	}
+
+	t.streamInfo.timers = strings.Join(statuses, ", ")
+
+	if lipgloss.Height(t.streamInfo.View()) != heightBefore {
+		t.HandleResize()
+	}
+}
+
+// handleTimerCommand runs /timer <name|all> <on|off>, which turns timers of the channel on or off.
+// Without arguments the timers are listed.
+func (t *broadcastTab) handleTimerCommand(args []string) tea.Cmd {
+	notice := func(text string) tea.Cmd {
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     t.id,
+				accountID: t.AccountID(),
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       text,
+				},
+			}
+		}
+	}
+
+	if len(t.timers) == 0 {
+		return notice(fmt.Sprintf("There are no timers for %s with this account, add them to the timers setting", t.channelLogin))
+	}
+
+	now := time.Now()
+
+	if len(args) == 0 || args[0] == "" {
+		statuses := make([]string, 0, len(t.timers))
+		for _, timer := range t.timers {
+			statuses = append(statuses, timer.status(now))
+		}
+
+		return notice("Timers: " + strings.Join(statuses, ", "))
+	}
+
+	if len(args) != 2 || args[1] != "on" && args[1] != "off" {
+		return notice("Usage: /timer <name|all> <on|off>")
+	}
+
+	enabled := args[1] == "on"
+
+	var changed []string
+	for _, timer := range t.timers {
+		if args[0] != "all" && !strings.EqualFold(timer.Name, args[0]) {
+			continue
+		}
+
+		// a timer turned on waits a full interval before its first message
+		if enabled && !timer.enabled {
+			timer.lastSent = now
+			timer.messages = 0
+		}
+
+		timer.enabled = enabled
+		changed = append(changed, timer.Name)
+	}
+
+	if len(changed) == 0 {
+		return notice(fmt.Sprintf("There is no timer %s, use /timer to list the timers", args[0]))
+	}
+
+	t.updateTimerStatus(now)
+
+	return notice(fmt.Sprintf("Turned %s %s", args[1], strings.Join(changed, ", ")))
+}
@@ -0,0 +1,37 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_channelTimer(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	timer := &channelTimer{
+		Timer:    save.Timer{Name: "socials", Interval: 10 * time.Minute, MinMessages: 3},
+		enabled:  true,
+		lastSent: start,
+	}
+
+	require.False(t, timer.due(start.Add(5*time.Minute)))
+	require.Equal(t, "socials in 5m", timer.status(start.Add(5*time.Minute)))
+	require.Equal(t, "socials in 1m", timer.status(start.Add(9*time.Minute+30*time.Second)))
+
+	// the interval passed, but the chat was too quiet
+	timer.messages = 2
+	require.False(t, timer.due(start.Add(11*time.Minute)))
+	require.Equal(t, "socials waiting for chat (2/3)", timer.status(start.Add(11*time.Minute)))
+
+	timer.messages = 3
+	require.True(t, timer.due(start.Add(11*time.Minute)))
+
+	timer.enabled = false
+	require.False(t, timer.due(start.Add(11*time.Minute)))
+	require.Equal(t, "socials off", timer.status(start.Add(11*time.Minute)))
+}
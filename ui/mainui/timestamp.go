@@ -0,0 +1,150 @@
+package mainui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// relativeTimestampRefreshInterval is how often relative timestamps are re-rendered
+const relativeTimestampRefreshInterval = time.Second * 30
+
+type relativeTimestampTickMessage struct{}
+
+// formatTimestamp formats t according to the timestamp settings, an empty string means timestamps are disabled.
+// withDate prefixes the date, used by views which contain messages of multiple days like the user inspect history.
+func formatTimestamp(settings save.TimestampSettings, withDate bool, t, now time.Time) string {
+	t = t.Local()
+
+	var layout string
+
+	switch settings.Format {
+	case save.TimestampFormatOff:
+		return ""
+	case save.TimestampFormatRelative:
+		return formatRelativeTimestamp(now.Sub(t))
+	case save.TimestampFormatMinutes:
+		layout = "15:04"
+		if settings.Clock == save.TimestampClock12h {
+			layout = "03:04 PM"
+		}
+	default:
+		layout = "15:04:05"
+		if settings.Clock == save.TimestampClock12h {
+			layout = "03:04:05 PM"
+		}
+	}
+
+	if withDate {
+		layout = "2006-01-02 " + layout
+	}
+
+	return t.Format(layout)
+}
+
+// formatRelativeTimestamp formats the age of a message right aligned with a fixed width, so message prefixes stay aligned
+func formatRelativeTimestamp(age time.Duration) string {
+	age = max(age, 0)
+
+	var s string
+	switch {
+	case age < time.Minute:
+		s = fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		s = fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < time.Hour*24:
+		s = fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		s = fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+
+	return fmt.Sprintf("%4s", s)
+}
+
+// eventTimestamp returns the time an event was sent, if the event has one
+func eventTimestamp(message twitchirc.IRCer) (time.Time, bool) {
+	switch msg := message.(type) {
+	case *twitchirc.PrivateMessage:
+		return msg.TMISentTS, true
+	case *twitchirc.Notice:
+		return msg.FakeTimestamp, true
+	case *twitchirc.ClearChat:
+		return msg.TMISentTS, true
+	case *twitchirc.ClearMessage:
+		return msg.TMISentTS, true
+	case *twitchirc.SubMessage:
+		return msg.TMISentTS, true
+	case *twitchirc.SubGiftMessage:
+		return msg.TMISentTS, true
+	case *twitchirc.AnnouncementMessage:
+		return msg.TMISentTS, true
+	}
+
+	return time.Time{}, false
+}
+
+func isSameDay(a, b time.Time) bool {
+	a, b = a.Local(), b.Local()
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// timestampPrefix renders the timestamp followed by a space, or an empty string if timestamps are disabled
+func (c *chatWindow) timestampPrefix(t time.Time) string {
+	ts := c.timeFormatFunc(t)
+	if ts == "" {
+		return ""
+	}
+
+	return c.timestampStyle.Render(ts) + " "
+}
+
+// timestampWidth is the width of the timestamp prefix, used to align lines without a timestamp
+func (c *chatWindow) timestampWidth() int {
+	ts := c.timeFormatFunc(time.Now())
+	if ts == "" {
+		return 0
+	}
+
+	return len(ts) + 1
+}
+
+// withDateSeparator prepends a separator line to the lines of an entry, if the entry was sent on another day than the previous entry
+func (c *chatWindow) withDateSeparator(prev *chatEntry, event chatEventMessage, lines []string) []string {
+	if !c.deps.UserConfig.Settings.Timestamps.DateSeparators || prev == nil {
+		return lines
+	}
+
+	current, ok := eventTimestamp(event.message)
+	if !ok || current.IsZero() {
+		return lines
+	}
+
+	previous, ok := eventTimestamp(prev.Event.message)
+	if !ok || previous.IsZero() || isSameDay(previous, current) {
+		return lines
+	}
+
+	label := " " + current.Local().Format("Monday, 02 January 2006") + " "
+	if c.screenReader() {
+		return append([]string{"  " + c.timestampStyle.Render(strings.TrimSpace(label))}, lines...)
+	}
+
+	fill := max(c.width-c.indicatorWidth-2-len(label), 0)
+	separator := "  " + c.timestampStyle.Render(strings.Repeat("─", fill/2)+label+strings.Repeat("─", fill-fill/2))
+
+	return append([]string{separator}, lines...)
+}
+
+func relativeTimestampTickCommand(settings save.TimestampSettings) tea.Cmd {
+	if settings.Format != save.TimestampFormatRelative {
+		return nil
+	}
+
+	return tea.Tick(relativeTimestampRefreshInterval, func(_ time.Time) tea.Msg {
+		return relativeTimestampTickMessage{}
+	})
+}
@@ -0,0 +1,86 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2024, 3, 9, 14, 5, 7, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		settings save.TimestampSettings
+		withDate bool
+		now      time.Time
+		want     string
+	}{
+		{
+			name:     "seconds-24h",
+			settings: save.TimestampSettings{Format: save.TimestampFormatSeconds, Clock: save.TimestampClock24h},
+			want:     "14:05:07",
+		},
+		{
+			name:     "minutes-12h",
+			settings: save.TimestampSettings{Format: save.TimestampFormatMinutes, Clock: save.TimestampClock12h},
+			want:     "02:05 PM",
+		},
+		{
+			name:     "seconds-12h-with-date",
+			settings: save.TimestampSettings{Format: save.TimestampFormatSeconds, Clock: save.TimestampClock12h},
+			withDate: true,
+			want:     "2024-03-09 02:05:07 PM",
+		},
+		{
+			name:     "empty-settings-use-default",
+			settings: save.TimestampSettings{},
+			want:     "14:05:07",
+		},
+		{
+			name:     "off",
+			settings: save.TimestampSettings{Format: save.TimestampFormatOff},
+			withDate: true,
+			want:     "",
+		},
+		{
+			name:     "relative-minutes",
+			settings: save.TimestampSettings{Format: save.TimestampFormatRelative},
+			now:      ts.Add(time.Minute*12 + time.Second*30),
+			want:     " 12m",
+		},
+		{
+			name:     "relative-days",
+			settings: save.TimestampSettings{Format: save.TimestampFormatRelative},
+			now:      ts.Add(time.Hour * 50),
+			want:     "  2d",
+		},
+		{
+			name:     "relative-future-is-zero",
+			settings: save.TimestampSettings{Format: save.TimestampFormatRelative},
+			now:      ts.Add(-time.Second),
+			want:     "  0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, formatTimestamp(tt.settings, tt.withDate, ts, tt.now))
+		})
+	}
+}
+
+func TestIsSameDay(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2024, 3, 9, 23, 59, 0, 0, time.Local)
+
+	require.True(t, isSameDay(day, day.Add(-time.Hour*23)))
+	require.False(t, isSameDay(day, day.Add(time.Minute*2)))
+	require.False(t, isSameDay(day, day.AddDate(1, 0, 0)))
+}
@@ -0,0 +1,185 @@
+package mainui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	translationTimeout = 10 * time.Second
+	translationPending = "translating…"
+)
+
+// translationMessage carries the translation of a message, requested from the tab with tabID
+type translationMessage struct {
+	tabID       string
+	messageID   string
+	translation string
+	err         error
+}
+
+// formatTranslation prefixes the translated text with the detected language of the original, if known
+func formatTranslation(text, sourceLanguage string) string {
+	if sourceLanguage == "" {
+		return text
+	}
+
+	return "[" + sourceLanguage + "] " + text
+}
+
+// handleTranslateMessage translates the selected message, or hides its translation if it is already shown
+func (t *broadcastTab) handleTranslateMessage() tea.Cmd {
+	cw := t.activeChatWindow()
+	if cw == nil {
+		return nil
+	}
+
+	_, entry := cw.entryForCurrentCursor()
+	if entry == nil {
+		return nil
+	}
+
+	msg, ok := entry.Event.message.(*twitchirc.PrivateMessage)
+	if !ok {
+		return nil
+	}
+
+	notice := func(text string) tea.Cmd {
+		return func() tea.Msg {
+			return requestLocalMessageHandleMessage{
+				tabID:     t.id,
+				accountID: t.AccountID(),
+				message: &twitchirc.Notice{
+					FakeTimestamp: time.Now(),
+					Message:       text,
+				},
+			}
+		}
+	}
+
+	if t.deps.Translator == nil {
+		return notice("Translations are disabled, set translation.backend in the settings to translate messages")
+	}
+
+	// a second press hides the translation again
+	if entry.Event.displayModifier.translation != "" {
+		t.setTranslation(msg.ID, "")
+		return nil
+	}
+
+	t.setTranslation(msg.ID, translationPending)
+
+	translator := t.deps.Translator
+	tabID := t.id
+	text := strings.ReplaceAll(msg.Message, string(duplicateBypass), "")
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), translationTimeout)
+		defer cancel()
+
+		result, err := translator.Translate(ctx, text)
+		if err != nil {
+			log.Logger.Err(err).Str("message-id", msg.ID).Msg("failed to translate message")
+			return translationMessage{tabID: tabID, messageID: msg.ID, err: err}
+		}
+
+		return translationMessage{
+			tabID:       tabID,
+			messageID:   msg.ID,
+			translation: formatTranslation(result.Text, result.SourceLanguage),
+		}
+	}
+}
+
+func (t *broadcastTab) handleTranslation(msg translationMessage) tea.Cmd {
+	if msg.err == nil {
+		t.setTranslation(msg.messageID, msg.translation)
+		return nil
+	}
+
+	t.setTranslation(msg.messageID, "")
+
+	return func() tea.Msg {
+		return requestLocalMessageHandleMessage{
+			tabID:     t.id,
+			accountID: t.AccountID(),
+			message: &twitchirc.Notice{
+				FakeTimestamp: time.Now(),
+				Message:       fmt.Sprintf("Failed to translate message: %s", msg.err),
+			},
+		}
+	}
+}
+
+// setTranslation shows the translation below the message in the chat and user inspect window, an empty translation hides it
+func (t *broadcastTab) setTranslation(messageID, translation string) {
+	t.chatWindow.setTranslation(messageID, translation)
+
+	if t.userInspect != nil {
+		t.userInspect.chatWindow.setTranslation(messageID, translation)
+	}
+}
+
+func (c *chatWindow) setTranslation(messageID, translation string) {
+	var changed bool
+	for _, e := range c.entries {
+		privMsg, ok := e.Event.message.(*twitchirc.PrivateMessage)
+		if !ok || privMsg.ID != messageID || e.Event.displayModifier.translation == translation {
+			continue
+		}
+
+		changed = true
+		e.Event.displayModifier.translation = translation
+		e.rendered = nil
+	}
+
+	if changed {
+		c.recalculateLines()
+	}
+}
+
+// withTranslation adds the lines of the translation below the lines of the message
+func (c *chatWindow) withTranslation(lines []string, translation string) []string {
+	if translation == "" {
+		return lines
+	}
+
+	// the blank line separating cozy messages stays below the translation
+	var spacer []string
+	if c.layout == save.ChatLayoutCozy && len(lines) > 0 {
+		lines, spacer = lines[:len(lines)-1], []string{lines[len(lines)-1]}
+	}
+
+	prefix := strings.Repeat(" ", c.timestampWidth()+2) + c.fadedStyle.Render("↳ ")
+	if c.screenReader() {
+		prefix = "  " + c.fadedStyle.Render("Translation: ")
+	}
+
+	translation = c.fadedStyle.Render(termtext.Sanitize(translation))
+
+	if c.layout == save.ChatLayoutCompact {
+		return append(lines, c.truncateMessage(prefix, translation)...)
+	}
+
+	prefixWidth := lipgloss.Width(prefix)
+	for i, line := range strings.Split(wrapText(translation, c.width-c.indicatorWidth-prefixWidth), "\n") {
+		if i > 0 && c.screenReader() {
+			prefix = "  "
+		} else if i > 0 {
+			prefix = strings.Repeat(" ", prefixWidth)
+		}
+
+		lines = append(lines, prefix+line)
+	}
+
+	return append(lines, spacer...)
+}
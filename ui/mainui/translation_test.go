@@ -0,0 +1,66 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatWindow_setTranslation(t *testing.T) {
+	t.Parallel()
+
+	newWindow := func(layout string) *chatWindow {
+		deps := newTestDeps(t)
+
+		c := newChatWindow(80, 20, deps)
+		c.layout = layout
+		c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{ID: "m1", LoginName: "a", DisplayName: "a", Message: "hallo zusammen"}})
+		c.handleMessage(chatEventMessage{message: &twitchirc.PrivateMessage{ID: "m2", LoginName: "b", DisplayName: "b", Message: "hello"}})
+
+		return c
+	}
+
+	t.Run("shown below the message", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow(save.ChatLayoutStandard)
+		c.setTranslation("m1", formatTranslation("hello everyone", "de"))
+
+		require.Len(t, c.entries[0].rendered, 2)
+		require.Equal(t, "↳ [de] hello everyone", strings.TrimSpace(ansi.Strip(c.entries[0].rendered[1])))
+		require.Len(t, c.lines, 3)
+		require.Equal(t, 2, c.entries[1].Position.CursorStart)
+
+		c.setTranslation("m1", "")
+		require.Len(t, c.entries[0].rendered, 1)
+		require.Len(t, c.lines, 2)
+	})
+
+	t.Run("long translations are wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow(save.ChatLayoutStandard)
+		c.setTranslation("m2", strings.Repeat("word ", 30))
+
+		require.Greater(t, len(c.entries[1].rendered), 2)
+		for _, line := range c.entries[1].rendered {
+			require.LessOrEqual(t, ansi.StringWidth(line), c.width-c.indicatorWidth)
+		}
+	})
+
+	t.Run("cozy spacer stays below the translation", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow(save.ChatLayoutCozy)
+		c.setTranslation("m1", "hello everyone")
+
+		rendered := c.entries[0].rendered
+		require.Len(t, rendered, 3)
+		require.Contains(t, ansi.Strip(rendered[1]), "hello everyone")
+		require.Empty(t, rendered[2])
+	})
+}
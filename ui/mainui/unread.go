@@ -0,0 +1,110 @@
+package mainui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+// countUnread counts the messages received while the tab is not focused and reports the count to the tab header.
+// Recent messages loaded on start are only counted after the last read message of the previous session.
+func (t *broadcastTab) countUnread(msg chatEventMessage) tea.Cmd {
+	priv, ok := msg.message.(*twitchirc.PrivateMessage)
+	if !ok || t.focused || priv.UserID == t.account.ID {
+		return nil
+	}
+
+	if t.chatWindow.lastRead == nil {
+		if msg.isFakeEvent {
+			return nil
+		}
+
+		// the tab was never focused, everything before the first live message counts as read
+		t.chatWindow.markRead()
+		t.restoredLastReadID = ""
+	}
+
+	t.unread++
+	tabID, unread := t.id, t.unread
+
+	return func() tea.Msg {
+		return tabUnreadMessage{
+			tabID:  tabID,
+			unread: unread,
+		}
+	}
+}
+
+// placeRestoredReadMarker puts the last read marker behind the message read last in the previous session
+func (t *broadcastTab) placeRestoredReadMarker(msg chatEventMessage) {
+	priv, ok := msg.message.(*twitchirc.PrivateMessage)
+	if !ok || t.restoredLastReadID == "" || priv.ID != t.restoredLastReadID {
+		return
+	}
+
+	t.restoredLastReadID = ""
+	t.chatWindow.markRead()
+}
+
+// markRead moves the last read marker behind the newest message
+func (c *chatWindow) markRead() {
+	var newest *chatEntry
+	if len(c.entries) > 0 {
+		newest = c.entries[len(c.entries)-1]
+	}
+
+	if newest == c.lastRead {
+		return
+	}
+
+	c.lastRead = newest
+	c.recalculateLines()
+}
+
+// lastReadMessageID returns the ID of the newest message before the last read marker, or of the newest message if all messages were read
+func (c *chatWindow) lastReadMessageID(all bool) string {
+	var id string
+
+	for _, e := range c.entries {
+		if priv, ok := e.Event.message.(*twitchirc.PrivateMessage); ok && priv.ID != "" {
+			id = priv.ID
+		}
+
+		if !all && e == c.lastRead {
+			break
+		}
+	}
+
+	return id
+}
+
+// withReadMarker adds the new messages separator in front of the first message after the last read message
+func (c *chatWindow) withReadMarker(prev *chatEntry, lines []string) []string {
+	if prev == nil || prev != c.lastRead {
+		return lines
+	}
+
+	label := " new messages "
+	if c.screenReader() {
+		return append([]string{"  " + c.noticeAlertStyle.Render("New messages")}, lines...)
+	}
+
+	fill := max(c.width-c.indicatorWidth-2-len(label), 0)
+	marker := "  " + c.noticeAlertStyle.Render(strings.Repeat("─", fill/2)+label+strings.Repeat("─", fill-fill/2))
+
+	return append([]string{marker}, lines...)
+}
+
+// lastReadMessageID returns the ID of the message read last, stored with the session
+func (t *broadcastTab) lastReadMessageID() string {
+	if !t.channelDataLoaded {
+		return t.restoredLastReadID
+	}
+
+	if id := t.chatWindow.lastReadMessageID(t.focused || t.chatWindow.lastRead == nil); id != "" {
+		return id
+	}
+
+	return t.restoredLastReadID
+}
@@ -0,0 +1,59 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatWindow_readMarker(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+
+	c := newChatWindow(80, 20, deps)
+
+	message := func(id, text string) chatEventMessage {
+		return chatEventMessage{message: &twitchirc.PrivateMessage{ID: id, UserID: "2", DisplayName: "viewer", Message: text}}
+	}
+
+	markers := func() int {
+		var n int
+		for _, line := range c.lines {
+			if strings.Contains(line, "new messages") {
+				n++
+			}
+		}
+
+		return n
+	}
+
+	c.handleMessage(message("m1", "first"))
+	c.handleMessage(message("m2", "second"))
+	require.Zero(t, markers())
+	require.Equal(t, "m2", c.lastReadMessageID(true))
+
+	c.markRead()
+	require.Zero(t, markers(), "no marker without newer messages")
+
+	c.handleMessage(message("m3", "third"))
+	c.handleMessage(chatEventMessage{message: &twitchirc.Notice{Message: "notice"}})
+	require.Equal(t, 1, markers())
+	require.Equal(t, "m2", c.lastReadMessageID(false))
+	require.Equal(t, "m3", c.lastReadMessageID(true))
+
+	// the marker moves when the tab is left again
+	c.markRead()
+	require.Zero(t, markers())
+	require.Equal(t, "m3", c.lastReadMessageID(false))
+}
+
+func Test_tabHeaderEntry_render(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "lirik (julez)", tabHeaderEntry{name: "lirik", identity: "julez"}.render())
+	require.Equal(t, "lirik (julez)[12]", tabHeaderEntry{name: "lirik", identity: "julez", unread: 12}.render())
+	require.Equal(t, "lirik (julez)[99+][!]", tabHeaderEntry{name: "lirik", identity: "julez", unread: 150, hasNotification: true}.render())
+}
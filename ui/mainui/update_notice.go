@@ -0,0 +1,83 @@
+package mainui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/selfupdate"
+	"github.com/rs/zerolog/log"
+)
+
+// updateAvailableMessage is returned by the check on startup when a newer release exists
+type updateAvailableMessage struct {
+	release selfupdate.Release
+}
+
+// checkForUpdate asks for a newer release in the background, failures are only logged since the check is optional
+func (r *Root) checkForUpdate() tea.Cmd {
+	checker := r.dependencies.Updates
+	if checker == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+
+		release, newer, err := checker.Check(ctx)
+		if err != nil {
+			log.Logger.Warn().Err(err).Msg("could not check for a new release")
+			return nil
+		}
+
+		if !newer {
+			return nil
+		}
+
+		return updateAvailableMessage{release: release}
+	}
+}
+
+// dismissUpdateNotice hides the notice, it is shown again once a newer release than the dismissed one is published
+func (r *Root) dismissUpdateNotice() tea.Cmd {
+	version := r.updateNotice.Version()
+	r.updateNotice = nil
+	r.handleResize()
+
+	checker := r.dependencies.Updates
+	return func() tea.Msg {
+		if err := checker.Dismiss(version); err != nil {
+			log.Logger.Err(err).Msg("could not store dismissed update notice")
+		}
+
+		return nil
+	}
+}
+
+// updateNoticeHeight is the number of lines taken by the notice above the tabs
+func (r *Root) updateNoticeHeight() int {
+	if r.updateNotice == nil {
+		return 0
+	}
+
+	return 1
+}
+
+func (r *Root) updateNoticeView() string {
+	if r.updateNotice == nil {
+		return ""
+	}
+
+	theme := r.dependencies.UserConfig.Theme
+
+	text := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.ChatNoticeAlertColor)).
+		Render(fmt.Sprintf(" Chatuino %s is available, run chatuino update to install it", r.updateNotice.Version()))
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.DimmedTextColor)).
+		Render(" · " + r.dependencies.Keymap.DismissNotice.Help().Key + " dismiss")
+
+	return ansi.Truncate(text+hint, r.width, "…")
+}
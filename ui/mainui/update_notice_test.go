@@ -0,0 +1,73 @@
+package mainui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/julez-dev/chatuino/selfupdate"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUpdateChecker struct {
+	release   selfupdate.Release
+	newer     bool
+	dismissed chan string
+}
+
+func (f fakeUpdateChecker) Check(context.Context) (selfupdate.Release, bool, error) {
+	return f.release, f.newer, nil
+}
+
+func (f fakeUpdateChecker) Dismiss(version string) error {
+	f.dismissed <- version
+	return nil
+}
+
+func TestRoot_updateNotice(t *testing.T) {
+	t.Parallel()
+
+	checker := fakeUpdateChecker{
+		release:   selfupdate.Release{Tag: "v0.7.0"},
+		newer:     true,
+		dismissed: make(chan string, 1),
+	}
+
+	deps := newTestDeps(t)
+	deps.Updates = checker
+
+	r := NewUI(nil, deps)
+	r.hasLoadedSession = true
+	r.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+
+	msg := r.checkForUpdate()()
+	require.Equal(t, updateAvailableMessage{release: checker.release}, msg)
+
+	r.Update(msg)
+	require.Equal(t, 19, r.splash.height, "the notice takes the first line")
+
+	firstLine, _, _ := strings.Cut(ansi.Strip(r.View()), "\n")
+	require.Equal(t, " Chatuino 0.7.0 is available, run chatuino update to install it · ctrl+alt+x dismiss", firstLine)
+
+	r.dismissUpdateNotice()()
+
+	require.Equal(t, "0.7.0", <-checker.dismissed)
+	require.Nil(t, r.updateNotice)
+	require.Equal(t, 20, r.splash.height)
+	require.NotContains(t, ansi.Strip(r.View()), "is available")
+}
+
+func TestRoot_checkForUpdate_notNewer(t *testing.T) {
+	t.Parallel()
+
+	deps := newTestDeps(t)
+	deps.Updates = fakeUpdateChecker{release: selfupdate.Release{Tag: "v0.6.0"}}
+
+	r := &Root{dependencies: deps}
+	require.Nil(t, r.checkForUpdate()())
+
+	r.dependencies.Updates = nil
+	require.Nil(t, r.checkForUpdate(), "the check is disabled without a checker")
+}
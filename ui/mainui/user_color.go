@@ -0,0 +1,182 @@
+package mainui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// userColorSaturation and userColorLightness are used for colors assigned to users without a chat color
+const (
+	userColorSaturation = 0.65
+	userColorLightness  = 0.6
+)
+
+// colorblindPalette is the Okabe-Ito palette without black, its colors stay distinguishable with deuteranopia and protanopia
+var colorblindPalette = []string{
+	"#e69f00", // orange
+	"#56b4e9", // sky blue
+	"#009e73", // bluish green
+	"#f0e442", // yellow
+	"#0072b2", // blue
+	"#d55e00", // vermillion
+	"#cc79a7", // reddish purple
+}
+
+type rgbColor struct {
+	r, g, b float64 // 0-1
+}
+
+func parseHexColor(hex string) (rgbColor, bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return rgbColor{}, false
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgbColor{}, false
+	}
+
+	return rgbColor{
+		r: float64(v>>16&0xff) / 255,
+		g: float64(v>>8&0xff) / 255,
+		b: float64(v&0xff) / 255,
+	}, true
+}
+
+func (c rgbColor) hex() string {
+	channel := func(v float64) int {
+		return int(math.Round(min(max(v, 0), 1) * 255))
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", channel(c.r), channel(c.g), channel(c.b))
+}
+
+// relativeLuminance as defined by WCAG 2
+func (c rgbColor) relativeLuminance() float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linear(c.r) + 0.7152*linear(c.g) + 0.0722*linear(c.b)
+}
+
+// contrastRatio returns the WCAG 2 contrast ratio between two colors, ranging from 1 to 21
+func contrastRatio(a, b rgbColor) float64 {
+	la, lb := a.relativeLuminance(), b.relativeLuminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+
+	return (la + 0.05) / (lb + 0.05)
+}
+
+func (c rgbColor) mix(other rgbColor, t float64) rgbColor {
+	return rgbColor{
+		r: c.r + (other.r-c.r)*t,
+		g: c.g + (other.g-c.g)*t,
+		b: c.b + (other.b-c.b)*t,
+	}
+}
+
+// adjustColorContrast lightens (dark background) or darkens (light background) a hex color just enough
+// to reach the target contrast ratio against the background. The hue is kept as far as possible.
+// Colors which can't be parsed or already have enough contrast are returned unchanged.
+func adjustColorContrast(hex string, darkBackground bool, target float64) string {
+	color, ok := parseHexColor(hex)
+	if !ok || target <= 1 {
+		return hex
+	}
+
+	background, towards := rgbColor{1, 1, 1}, rgbColor{0, 0, 0}
+	if darkBackground {
+		background, towards = rgbColor{0, 0, 0}, rgbColor{1, 1, 1}
+	}
+
+	if contrastRatio(color, background) >= target {
+		return hex
+	}
+
+	// find the smallest mix factor reaching the target, contrast grows monotonically with the factor
+	low, high := 0.0, 1.0
+	for range 20 {
+		mid := (low + high) / 2
+		if contrastRatio(color.mix(towards, mid), background) >= target {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+
+	return color.mix(towards, high).hex()
+}
+
+// deterministicUserColor picks a color for users without a chat color from their login,
+// so a user keeps the same color across messages, tabs and restarts.
+func deterministicUserColor(login string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(login)))
+
+	hue := float64(h.Sum32()%360) / 360
+	return hslToRGB(hue, userColorSaturation, userColorLightness).hex()
+}
+
+// colorblindUserColor maps a hex color to the nearest color of the colorblind palette, so a user keeps the same color.
+// Colors which can't be parsed are returned unchanged.
+func colorblindUserColor(hex string) string {
+	color, ok := parseHexColor(hex)
+	if !ok {
+		return hex
+	}
+
+	nearest, nearestDistance := hex, math.Inf(1)
+	for _, candidate := range colorblindPalette {
+		c, _ := parseHexColor(candidate)
+
+		distance := (color.r-c.r)*(color.r-c.r) + (color.g-c.g)*(color.g-c.g) + (color.b-c.b)*(color.b-c.b)
+		if distance < nearestDistance {
+			nearest, nearestDistance = candidate, distance
+		}
+	}
+
+	return nearest
+}
+
+// hslToRGB converts a HSL color with all components in the range 0-1
+func hslToRGB(h, s, l float64) rgbColor {
+	if s == 0 {
+		return rgbColor{l, l, l}
+	}
+
+	q := l * (1 + s)
+	if l >= 0.5 {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+
+	hueToChannel := func(t float64) float64 {
+		t = t - math.Floor(t)
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 1.0/2:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		default:
+			return p
+		}
+	}
+
+	return rgbColor{
+		r: hueToChannel(h + 1.0/3),
+		g: hueToChannel(h),
+		b: hueToChannel(h - 1.0/3),
+	}
+}
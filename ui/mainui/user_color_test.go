@@ -0,0 +1,108 @@
+package mainui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjustColorContrast(t *testing.T) {
+	t.Parallel()
+
+	black := rgbColor{0, 0, 0}
+	white := rgbColor{1, 1, 1}
+
+	tests := []struct {
+		name           string
+		color          string
+		darkBackground bool
+		target         float64
+		want           string
+	}{
+		{
+			name:           "already-readable-on-dark",
+			color:          "#ffcc00",
+			darkBackground: true,
+			target:         4.5,
+			want:           "#ffcc00",
+		},
+		{
+			name:           "invalid-color-unchanged",
+			color:          "blue",
+			darkBackground: true,
+			target:         4.5,
+			want:           "blue",
+		},
+		{
+			name:           "disabled",
+			color:          "#0000ff",
+			darkBackground: true,
+			target:         0,
+			want:           "#0000ff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, adjustColorContrast(tt.color, tt.darkBackground, tt.target))
+		})
+	}
+
+	t.Run("dark-blue-lightened-on-dark", func(t *testing.T) {
+		t.Parallel()
+
+		adjusted := adjustColorContrast("#0000ff", true, 4.5)
+		c, ok := parseHexColor(adjusted)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, contrastRatio(c, black), 4.4)
+		require.Greater(t, c.b, c.r, "hue should be kept")
+	})
+
+	t.Run("yellow-darkened-on-light", func(t *testing.T) {
+		t.Parallel()
+
+		adjusted := adjustColorContrast("#ffff00", false, 4.5)
+		c, ok := parseHexColor(adjusted)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, contrastRatio(c, white), 4.4)
+	})
+}
+
+func TestContrastRatio(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, 21, contrastRatio(rgbColor{0, 0, 0}, rgbColor{1, 1, 1}), 0.01)
+	require.InDelta(t, 1, contrastRatio(rgbColor{0.5, 0.5, 0.5}, rgbColor{0.5, 0.5, 0.5}), 0.01)
+}
+
+func TestDeterministicUserColor(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, deterministicUserColor("julezdev"), deterministicUserColor("JulezDev"))
+	require.NotEqual(t, deterministicUserColor("julezdev"), deterministicUserColor("lirik"))
+
+	_, ok := parseHexColor(deterministicUserColor("julezdev"))
+	require.True(t, ok)
+}
+
+func TestColorblindUserColor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"#ff0000": "#d55e00",
+		"#00ff00": "#009e73",
+		"#0000ff": "#0072b2",
+		"#ffff00": "#f0e442",
+		"#ff69b4": "#cc79a7",
+		"#e69f00": "#e69f00",
+		"blue":    "blue",
+	}
+
+	for color, want := range tests {
+		t.Run(color, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, want, colorblindUserColor(color))
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"slices"
 	"strings"
@@ -12,6 +11,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/julez-dev/chatuino/internal/termtext"
+	"github.com/julez-dev/chatuino/save"
 	"github.com/julez-dev/chatuino/twitch/ivr"
 	"github.com/julez-dev/chatuino/twitch/twitchapi"
 	"github.com/julez-dev/chatuino/twitch/twitchirc"
@@ -22,6 +23,7 @@ type setUserInspectData struct {
 	err           error
 	ivrResp       ivr.SubAgeResponse
 	userData      twitchapi.UserData
+	notes         []save.Note
 	initialEvents []chatEventMessage
 }
 
@@ -38,6 +40,7 @@ type userInspect struct {
 	accountID       string // account id from chatuino user
 	badges          []twitchirc.Badge
 	formattedBadges wordReplacement
+	notes           []save.Note
 
 	ivr  *ivr.API
 	deps *DependencyContainer
@@ -47,8 +50,9 @@ type userInspect struct {
 
 func newUserInspect(tabID string, width, height int, user, channel string, accountID string, deps *DependencyContainer) *userInspect {
 	c := newChatWindow(width, height, deps)
+	c.channel = channel
 	c.timeFormatFunc = func(t time.Time) string {
-		return t.Local().Format("2006-01-02 15:04:05")
+		return formatTimestamp(deps.UserConfig.Settings.Timestamps, true, t, time.Now())
 	}
 
 	return &userInspect{
@@ -56,7 +60,7 @@ func newUserInspect(tabID string, width, height int, user, channel string, accou
 		channel:   channel,
 		accountID: accountID,
 		user:      user,
-		ivr:       ivr.NewAPI(http.DefaultClient),
+		ivr:       ivr.NewAPI(deps.httpClient()),
 		deps:      deps,
 		// start chat window in full size, will be resized once data is fetched
 		chatWindow: c,
@@ -175,6 +179,7 @@ func (u *userInspect) init(initialEvents []chatEventMessage) tea.Cmd {
 			err:           err,
 			ivrResp:       ivrResp,
 			userData:      ttvResp.Data[0],
+			notes:         loadNotes(u.deps, save.NoteUser, ivrResp.User.Login),
 			initialEvents: initialEvents,
 		}
 	})
@@ -196,6 +201,7 @@ func (u *userInspect) Update(msg tea.Msg) (*userInspect, tea.Cmd) {
 		u.err = msg.err
 		u.subAge = msg.ivrResp
 		u.userData = msg.userData
+		u.notes = msg.notes
 		u.isDataFetched = true
 
 		for event := range slices.Values(msg.initialEvents) {
@@ -283,6 +289,15 @@ func (u *userInspect) handleResize() {
 	u.chatWindow.recalculateLines()
 }
 
+// login is the login of the inspected user, the name used to open the user card until the data is fetched
+func (u *userInspect) login() string {
+	if u.subAge.User.Login != "" {
+		return u.subAge.User.Login
+	}
+
+	return strings.ToLower(u.user)
+}
+
 func (u *userInspect) renderUserInfo() string {
 	border := lipgloss.Border{
 		Top:    "+",
@@ -308,6 +323,13 @@ func (u *userInspect) renderUserInfo() string {
 
 	b := &strings.Builder{}
 	_, _ = fmt.Fprintf(b, "User %s (%s)", u.subAge.User.DisplayName, u.subAge.User.ID)
+	if alias := u.deps.UserConfig.Settings.Chat.AliasFor(u.subAge.User.Login); alias != "" {
+		_, _ = fmt.Fprintf(b, " - Alias: %s", termtext.Sanitize(alias))
+	}
+	if u.deps.Blocks != nil && u.deps.Blocks.IsBlocked(u.accountID, u.userData.ID) {
+		b.WriteString(" - Blocked")
+	}
+
 	if len(u.formattedBadges) > 0 {
 		_, _ = fmt.Fprintf(b, " - %s\n", formatBadgeReplacement(u.deps.UserConfig.Settings, u.formattedBadges))
 	} else {
@@ -330,5 +352,13 @@ func (u *userInspect) renderUserInfo() string {
 		_, _ = fmt.Fprintf(b, " - %d Month Sub Streak!", u.subAge.Streak.Months)
 	}
 
+	if len(u.notes) > 0 && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+
+	for _, note := range u.notes {
+		_, _ = fmt.Fprintf(b, "Note %s\n", formatNote(note))
+	}
+
 	return style.Render(strings.TrimSuffix(b.String(), "\n"))
 }
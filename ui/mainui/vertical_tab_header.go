@@ -126,9 +126,10 @@ func (v *verticalTabHeader) SelectTab(id string) {
 	for i, item := range v.list.Items() {
 		e := item.(tabHeaderEntry)
 		if e.id == id {
-			// reset notification flag on select
-			if e.hasNotification {
+			// reset notification flag and unread count on select
+			if e.hasNotification || e.unread > 0 {
 				e.hasNotification = false
+				e.unread = 0
 				v.list.SetItem(i, e)
 			}
 
@@ -151,6 +152,7 @@ func (v *verticalTabHeader) MinWidth() int {
 	for _, e := range v.list.Items() {
 		e := e.(tabHeaderEntry)
 		e.hasNotification = true
+		e.hasDraft = true
 		r := e.render()
 		if lipgloss.Width(r) > minWidth {
 			minWidth = lipgloss.Width(r)
@@ -161,6 +163,21 @@ func (v *verticalTabHeader) MinWidth() int {
 	return minWidth + 4
 }
 
+// TabAt returns the tab in the row, the rows between the borders show the tabs of the current list page
+func (v *verticalTabHeader) TabAt(x, y int) (string, bool) {
+	if x < 1 || x >= v.width-1 || y < 1 || y >= v.height-1 {
+		return "", false
+	}
+
+	index := v.list.Paginator.Page*v.list.Paginator.PerPage + y - 1
+	items := v.list.Items()
+	if index >= len(items) {
+		return "", false
+	}
+
+	return items[index].(tabHeaderEntry).id, true
+}
+
 func (v *verticalTabHeader) Resize(width, height int) {
 	v.width = width
 	v.height = height
@@ -194,11 +211,31 @@ func (v *verticalTabHeader) Update(msg tea.Msg) (header, tea.Cmd) {
 		}
 	}
 
+	if req, ok := msg.(tabUnreadMessage); ok {
+		for i, e := range v.list.Items() {
+			e := e.(tabHeaderEntry)
+			if e.id == req.tabID && v.list.Index() != i {
+				e.unread = req.unread
+				v.list.SetItem(i, e)
+			}
+		}
+	}
+
+	if req, ok := msg.(tabDraftMessage); ok {
+		for i, e := range v.list.Items() {
+			e := e.(tabHeaderEntry)
+			if e.id == req.tabID {
+				e.hasDraft = req.hasDraft
+				v.list.SetItem(i, e)
+			}
+		}
+	}
+
 	return v, tea.Batch(cmds...)
 }
 
 func (v *verticalTabHeader) View() string {
-	borderColor := lipgloss.Color(v.deps.UserConfig.Theme.InputPromptColor)
+	borderColor := lipgloss.Color(v.deps.UserConfig.Theme.BorderColor)
 	borderStyle := lipgloss.NewStyle().Foreground(borderColor)
 
 	innerWidth := v.width - 2   // -2 for left/right │
@@ -0,0 +1,142 @@
+package mainui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/chatexport"
+)
+
+// handleStartVisualMode starts selecting a range of messages at the selected message.
+// Returns false if no message is selected.
+func (c *chatWindow) handleStartVisualMode() bool {
+	_, e := c.entryForCurrentCursor()
+	if e == nil {
+		return false
+	}
+
+	c.visualAnchor = e
+	c.state = visualChatWindowState
+	c.markSelectedMessage()
+
+	return true
+}
+
+func (c *chatWindow) handleStopVisualMode() {
+	// remove the marks of the whole range, before only the selected message is marked again
+	c.unmarkLines(c.lines)
+
+	c.state = viewChatWindowState
+	c.visualAnchor = nil
+	c.markSelectedMessage()
+}
+
+// visualRange returns the indices of the first and last entry of the visual selection in active.
+// Both are -1 if the chat window is not in visual mode.
+func (c *chatWindow) visualRange(active []*chatEntry) (from, to int) {
+	if c.state != visualChatWindowState {
+		return -1, -1
+	}
+
+	anchor, cursor := -1, -1
+	for i, e := range active {
+		if e == c.visualAnchor {
+			anchor = i
+		}
+
+		if e.Selected {
+			cursor = i
+		}
+	}
+
+	// the anchor may be hidden by a search, then only the selected message is part of the selection
+	if anchor == -1 {
+		anchor = cursor
+	}
+
+	if cursor == -1 {
+		return -1, -1
+	}
+
+	return min(anchor, cursor), max(anchor, cursor)
+}
+
+// visualSelection returns the chat messages of the visual selection, oldest first
+func (c *chatWindow) visualSelection() []chatexport.Message {
+	active := c.activeEntries()
+
+	from, to := c.visualRange(active)
+	if from == -1 {
+		return nil
+	}
+
+	return exportMessages(active[from : to+1])
+}
+
+// handleVisualKey processes a key press while a range of messages is selected.
+// Navigation keys move the end of the selection, yanking or saving the selection ends the visual mode.
+func (t *broadcastTab) handleVisualKey(cw *chatWindow, msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, t.keymap().Escape, t.keymap().VisualMode):
+		cw.handleStopVisualMode()
+	case key.Matches(msg, t.keymap().CopyToClipboard):
+		messages := cw.visualSelection()
+		cw.handleStopVisualMode()
+		return t.handleYankSelection(messages)
+	case key.Matches(msg, t.keymap().SaveSelection):
+		messages := cw.visualSelection()
+		cw.handleStopVisualMode()
+		return t.handleSaveSelection(messages)
+	case key.Matches(msg, t.keymap().Up, t.keymap().Down, t.keymap().GoToTop, t.keymap().GoToBottom):
+		_, cmd := cw.Update(msg)
+		return cmd
+	}
+
+	return nil
+}
+
+// handleYankSelection copies the selected messages with their time and author to the system clipboard
+func (t *broadcastTab) handleYankSelection(messages []chatexport.Message) tea.Cmd {
+	notice := t.localNotice()
+
+	if len(messages) == 0 {
+		return func() tea.Msg {
+			return notice("Selection contains no chat messages to copy")
+		}
+	}
+
+	var b bytes.Buffer
+	if err := chatexport.Write(&b, chatexport.FormatText, messages); err != nil {
+		return func() tea.Msg {
+			return notice("Failed to copy selection to clipboard: " + err.Error())
+		}
+	}
+
+	return tea.Sequence(copyToClipboard(strings.TrimSuffix(b.String(), "\n")), func() tea.Msg {
+		return notice(fmt.Sprintf("Copied %d messages to clipboard", len(messages)))
+	})
+}
+
+// handleSaveSelection writes the selected messages to a text file in the working directory, like /export
+func (t *broadcastTab) handleSaveSelection(messages []chatexport.Message) tea.Cmd {
+	notice := t.localNotice()
+	channel := t.channelLogin
+
+	return func() tea.Msg {
+		if len(messages) == 0 {
+			return notice("Selection contains no chat messages to save")
+		}
+
+		name := fmt.Sprintf("%s_selection_%s.%s", channel, time.Now().Format("2006-01-02_15_04_05"), chatexport.FormatText.Extension())
+		name, err := writeExportFile(name, chatexport.FormatText, messages)
+		if err != nil {
+			return notice("Failed to save selection: " + err.Error())
+		}
+
+		return notice(fmt.Sprintf("Saved %d messages to %s", len(messages), name))
+	}
+}
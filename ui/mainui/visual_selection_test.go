@@ -0,0 +1,93 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_chatWindow_visualSelection(t *testing.T) {
+	t.Parallel()
+
+	newWindow := func() *chatWindow {
+		deps := newTestDeps(t)
+
+		c := newChatWindow(80, 20, deps)
+		c.handleMessage(chatEventMessage{channel: "julez", message: &twitchirc.PrivateMessage{LoginName: "a", DisplayName: "a", Message: "first"}})
+		c.handleMessage(chatEventMessage{channel: "julez", message: &twitchirc.Notice{Message: "notice"}})
+		c.handleMessage(chatEventMessage{channel: "julez", message: &twitchirc.PrivateMessage{LoginName: "b", DisplayName: "b", Message: "second"}})
+		c.handleMessage(chatEventMessage{channel: "julez", message: &twitchirc.PrivateMessage{LoginName: "c", DisplayName: "c", Message: "third"}})
+
+		return c
+	}
+
+	marked := func(c *chatWindow) int {
+		var n int
+		for _, l := range c.lines {
+			if strings.HasPrefix(l, c.indicator) {
+				n++
+			}
+		}
+
+		return n
+	}
+
+	t.Run("range up from the selected message", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		require.True(t, c.handleStartVisualMode())
+		c.messageUp(2)
+
+		require.Equal(t, 3, marked(c))
+
+		messages := c.visualSelection()
+		require.Len(t, messages, 2)
+		require.Equal(t, "second", messages[0].Text)
+		require.Equal(t, "third", messages[1].Text)
+		require.Equal(t, "julez", messages[0].Channel)
+	})
+
+	t.Run("range shrinks when moving back", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		c.moveToTop()
+		require.True(t, c.handleStartVisualMode())
+		c.messageDown(3)
+		c.messageUp(1)
+
+		messages := c.visualSelection()
+		require.Len(t, messages, 2)
+		require.Equal(t, "first", messages[0].Text)
+		require.Equal(t, "second", messages[1].Text)
+		require.Equal(t, 3, marked(c))
+	})
+
+	t.Run("new messages don't extend the selection", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		require.True(t, c.handleStartVisualMode())
+		c.handleMessage(chatEventMessage{channel: "julez", message: &twitchirc.PrivateMessage{LoginName: "d", DisplayName: "d", Message: "fourth"}})
+
+		messages := c.visualSelection()
+		require.Len(t, messages, 1)
+		require.Equal(t, "third", messages[0].Text)
+	})
+
+	t.Run("stop removes the range marks", func(t *testing.T) {
+		t.Parallel()
+
+		c := newWindow()
+		require.True(t, c.handleStartVisualMode())
+		c.moveToTop()
+		require.Equal(t, 4, marked(c))
+
+		c.handleStopVisualMode()
+		require.Equal(t, 1, marked(c))
+		require.Nil(t, c.visualSelection())
+	})
+}
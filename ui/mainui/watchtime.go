@@ -0,0 +1,120 @@
+package mainui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/save"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	watchtimeInterval    = time.Minute
+	watchtimeMaxChannels = 5 // channels with the most watchtime listed in the stats overlay
+)
+
+type watchtimeTickMessage struct{}
+
+// channelWatchtime is the total watchtime of a channel shown in the stats overlay
+type channelWatchtime struct {
+	channel string
+	save.Watchtime
+}
+
+func watchtimeTickCommand() tea.Cmd {
+	return tea.Tick(watchtimeInterval, func(time.Time) tea.Msg {
+		return watchtimeTickMessage{}
+	})
+}
+
+// startWatchtime starts tracking the watchtime, replays are not tracked
+func (r *Root) startWatchtime() tea.Cmd {
+	if r.dependencies.Watchtime == nil || r.dependencies.Replay != nil {
+		return nil
+	}
+
+	r.watchtimeTracked = time.Now()
+	return watchtimeTickCommand()
+}
+
+// trackWatchtime adds the time since the last tick to the channels of the open tabs and persists it
+func (r *Root) trackWatchtime(now time.Time) tea.Cmd {
+	elapsed := now.Sub(r.watchtimeTracked)
+	r.watchtimeTracked = now
+
+	// ticks are delayed while the computer sleeps, the time asleep is not counted
+	elapsed = min(elapsed, watchtimeInterval*2)
+
+	add := collectWatchtime(r.tabs, r.tabCursor, !r.idle.idle, elapsed)
+	store := r.dependencies.Watchtime
+
+	return tea.Batch(watchtimeTickCommand(), func() tea.Msg {
+		if err := store.AddWatchtime(add); err != nil {
+			log.Logger.Err(err).Msg("failed to save watchtime")
+		}
+
+		return nil
+	})
+}
+
+// collectWatchtime returns the watchtime of the channels of the open channel tabs. Channels open in multiple tabs are counted once,
+// the focused time is only counted if the user is active.
+func collectWatchtime(tabs []tab, focused int, active bool, elapsed time.Duration) map[string]save.Watchtime {
+	if elapsed <= 0 {
+		return nil
+	}
+
+	add := map[string]save.Watchtime{}
+
+	for i, t := range tabs {
+		if t.Kind() != broadcastTabKind || t.Channel() == "" {
+			continue
+		}
+
+		channel := strings.ToLower(t.Channel())
+		w := add[channel]
+		w.Open = elapsed
+
+		if i == focused && active {
+			w.Focused = elapsed
+		}
+
+		add[channel] = w
+	}
+
+	return add
+}
+
+// topWatchtime returns the channels with the most time open
+func topWatchtime(totals map[string]save.Watchtime, n int) []channelWatchtime {
+	top := make([]channelWatchtime, 0, len(totals))
+	for channel, w := range totals {
+		top = append(top, channelWatchtime{channel: channel, Watchtime: w})
+	}
+
+	slices.SortFunc(top, func(a, b channelWatchtime) int {
+		return cmp.Or(cmp.Compare(b.Open, a.Open), strings.Compare(a.channel, b.channel))
+	})
+
+	return top[:min(len(top), n)]
+}
+
+// formatWatchtime formats d with the two largest units, e.g. 2d 3h, 5h 12m or 45m
+func formatWatchtime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,70 @@
+package mainui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectWatchtime(t *testing.T) {
+	t.Parallel()
+
+	tabs := []tab{
+		&broadcastTab{channelLogin: "Lirik"},
+		&mentionTab{},
+		&broadcastTab{channelLogin: "xqc"},
+		&broadcastTab{channelLogin: "lirik"},
+	}
+
+	// channels open in multiple tabs are counted once
+	require.Equal(t, map[string]save.Watchtime{
+		"lirik": {Open: time.Minute, Focused: time.Minute},
+		"xqc":   {Open: time.Minute},
+	}, collectWatchtime(tabs, 3, true, time.Minute))
+
+	// idle users do not watch the focused tab
+	require.Equal(t, map[string]save.Watchtime{
+		"lirik": {Open: time.Minute},
+		"xqc":   {Open: time.Minute},
+	}, collectWatchtime(tabs, 2, false, time.Minute))
+
+	require.Empty(t, collectWatchtime(tabs, 0, true, 0))
+}
+
+func Test_topWatchtime(t *testing.T) {
+	t.Parallel()
+
+	top := topWatchtime(map[string]save.Watchtime{
+		"lirik":  {Open: time.Hour},
+		"xqc":    {Open: time.Hour * 2},
+		"forsen": {Open: time.Hour},
+	}, 2)
+
+	require.Equal(t, []channelWatchtime{
+		{channel: "xqc", Watchtime: save.Watchtime{Open: time.Hour * 2}},
+		{channel: "forsen", Watchtime: save.Watchtime{Open: time.Hour}},
+	}, top)
+}
+
+func Test_formatWatchtime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: time.Second * 30, want: "0m"},
+		{d: time.Minute * 45, want: "45m"},
+		{d: time.Hour*3 + time.Minute*12, want: "3h 12m"},
+		{d: time.Hour * 51, want: "2d 3h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, formatWatchtime(tt.d))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mainui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+// windowTitleFor returns the name of the tmux window: the focused channel, with a dot while it is live
+func (r *Root) windowTitleFor() string {
+	if r.screenType != mainScreen || len(r.tabs) <= r.tabCursor {
+		return "chatuino"
+	}
+
+	focused, ok := r.tabs[r.tabCursor].(*broadcastTab)
+	if !ok {
+		return "chatuino"
+	}
+
+	if focused.isLive() {
+		return "● " + focused.Channel()
+	}
+
+	return focused.Channel()
+}
+
+// updateWindowTitle renames the tmux window and sets the pane title when the focused channel or its live status changed
+func (r *Root) updateWindowTitle() tea.Cmd {
+	window := r.dependencies.TmuxWindow
+	if window == nil || !r.dependencies.UserConfig.Settings.Tmux.WindowTitle {
+		return nil
+	}
+
+	title := r.windowTitleFor()
+	if title == r.windowTitle {
+		return nil
+	}
+
+	r.windowTitle = title
+
+	return tea.Batch(
+		tea.SetWindowTitle(title),
+		func() tea.Msg {
+			if err := window.RenameWindow(title); err != nil {
+				log.Logger.Err(err).Str("title", title).Msg("failed to rename tmux window")
+			}
+
+			return nil
+		},
+	)
+}
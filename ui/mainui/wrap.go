@@ -0,0 +1,126 @@
+package mainui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+const nonBreakingSpace = '\u00a0'
+
+// wrapToken is a grapheme cluster or an ANSI escape sequence, which takes no cells
+type wrapToken struct {
+	text  string
+	width int
+}
+
+// wrapText wraps s to lines of at most limit cells. Lines are broken at spaces and after hyphens, words longer than a line
+// are broken between grapheme clusters. Widths are measured per grapheme cluster, so wide characters, emoji ZWJ sequences
+// and combining marks take the same cells as in the terminal and are never split across lines. ANSI escape sequences are kept.
+func wrapText(s string, limit int) string {
+	if limit < 1 {
+		return s
+	}
+
+	var (
+		b          strings.Builder
+		lineWidth  int
+		space      []wrapToken
+		spaceWidth int
+		word       []wrapToken
+		wordWidth  int
+	)
+
+	newline := func() {
+		b.WriteByte('\n')
+		lineWidth = 0
+		space, spaceWidth = nil, 0
+	}
+
+	write := func(tokens []wrapToken, width int) {
+		for _, t := range tokens {
+			b.WriteString(t.text)
+		}
+		lineWidth += width
+	}
+
+	addWord := func() {
+		if len(word) == 0 {
+			return
+		}
+
+		switch {
+		case lineWidth+spaceWidth+wordWidth <= limit:
+			write(space, spaceWidth)
+		case wordWidth <= limit:
+			newline()
+		case lineWidth+spaceWidth < limit:
+			// the word is broken anyway, so it starts on the current line
+			write(space, spaceWidth)
+		default:
+			newline()
+		}
+
+		for _, t := range word {
+			if lineWidth+t.width > limit {
+				newline()
+			}
+
+			write([]wrapToken{t}, t.width)
+		}
+
+		space, spaceWidth = nil, 0
+		word, wordWidth = nil, 0
+	}
+
+	for len(s) > 0 {
+		if s[0] == ansi.ESC {
+			_, _, n, _ := ansi.DecodeSequence(s, ansi.NormalState, nil)
+			word = append(word, wrapToken{text: s[:n]})
+			s = s[n:]
+			continue
+		}
+
+		// the text up to the next escape sequence is segmented as a whole, ASCII characters may be followed by combining marks
+		text := s
+		if i := strings.IndexByte(s, ansi.ESC); i != -1 {
+			text = s[:i]
+		}
+		s = s[len(text):]
+
+		state := -1
+		for len(text) > 0 {
+			var (
+				cluster string
+				width   int
+			)
+			cluster, text, width, state = uniseg.FirstGraphemeClusterInString(text, state)
+
+			r, _ := utf8.DecodeRuneInString(cluster)
+			switch {
+			case cluster == "\n" || cluster == "\r\n":
+				addWord()
+				newline()
+			case unicode.IsSpace(r) && r != nonBreakingSpace:
+				addWord()
+				space = append(space, wrapToken{text: cluster, width: width})
+				spaceWidth += width
+			default:
+				word = append(word, wrapToken{text: cluster, width: width})
+				wordWidth += width
+
+				// lines may be broken after a hyphen
+				if cluster == "-" {
+					addWord()
+				}
+			}
+		}
+	}
+
+	addWord()
+
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package mainui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_wrapText(t *testing.T) {
+	t.Parallel()
+
+	family := "👨‍👩‍👧"
+	emote := strings.Repeat("\U0010EEEE", 3)
+
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "words", in: "hello world this is a test", want: []string{"hello world this", "is a test"}},
+		{name: "spaces are kept inside a line", in: "two  spaces   here", want: []string{"two  spaces", "here"}},
+		{name: "hyphen", in: "a-b-c-d-e-f-g-h-i-j", want: []string{"a-b-c-d-e-f-g-h-", "i-j"}},
+		{name: "long word starts on the current line", in: "hi https://example.com/long/link", want: []string{"hi https://examp", "le.com/long/link"}},
+		{name: "newline", in: "line\nbreak", want: []string{"line", "break"}},
+		{name: "ansi sequences take no cells", in: "\x1b[31mred text that is long\x1b[0m", want: []string{"\x1b[31mred text that is", "long\x1b[0m"}},
+		{name: "east asian wide", in: "日本語のテキストですね", want: []string{"日本語のテキスト", "ですね"}},
+		{name: "emoji zwj sequence", in: strings.Repeat(family, 9), want: []string{strings.Repeat(family, 8), family}},
+		{name: "combining marks", in: strings.Repeat("e\u0301", 18), want: []string{strings.Repeat("e\u0301", 16), strings.Repeat("e\u0301", 2)}},
+		{name: "emote placeholders", in: "x " + emote + " emote " + emote + " end", want: []string{"x " + emote + " emote " + emote, "end"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, strings.Split(wrapText(tt.in, 16), "\n"))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/julez-dev/chatuino/save"
+	"github.com/julez-dev/chatuino/selfupdate"
+	"github.com/urfave/cli/v3"
+)
+
+var updateCMD = &cli.Command{
+	Name:        "update",
+	Usage:       "Update Chatuino to the latest release",
+	Description: "Download the latest release from GitHub, verify it against the published SHA-256 checksums and replace the running binary. Releases are not signed, the checksums only protect against corrupted downloads. Binaries installed by a package manager are never replaced.",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "check-only",
+			Usage: "Only check whether a newer release is available",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Install the latest release even if it is not newer, e.g. for dev builds",
+		},
+	},
+	Action: func(ctx context.Context, command *cli.Command) error {
+		settings, err := save.SettingsFromDisk()
+		if err != nil {
+			return fmt.Errorf("failed to read settings file: %w\nrun \"chatuino config validate\" for details", err)
+		}
+
+		httpClient, err := newHTTPClient(settings.Proxy, false)
+		if err != nil {
+			return err
+		}
+
+		updater := selfupdate.New(httpClient, Version)
+
+		release, err := updater.Latest(ctx)
+		if err != nil {
+			return err
+		}
+
+		newer := selfupdate.IsNewer(Version, release.Version())
+		if command.Bool("check-only") {
+			if newer {
+				fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Chatuino %s is available, running %s: %s", release.Version(), Version, release.URL)))
+				return nil
+			}
+
+			fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Chatuino %s is up to date, latest release is %s", Version, release.Version())))
+			return nil
+		}
+
+		if !newer && !command.Bool("force") {
+			fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Chatuino %s is up to date, latest release is %s, use --force to reinstall it", Version, release.Version())))
+			return nil
+		}
+
+		exePath, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the running binary: %w", err)
+		}
+
+		if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+			exePath = resolved
+		}
+
+		if manager := selfupdate.PackageManager(exePath); manager != "" {
+			return fmt.Errorf("%s is managed by %s, update Chatuino through it instead", exePath, manager)
+		}
+
+		binary, err := updater.Download(ctx, release, runtime.GOOS, runtime.GOARCH)
+		if err != nil {
+			return err
+		}
+
+		if err := selfupdate.Replace(exePath, binary); err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return fmt.Errorf("no permission to replace %s, run the update as the owner of the binary: %w", exePath, err)
+			}
+
+			return fmt.Errorf("failed to replace %s: %w", exePath, err)
+		}
+
+		fmt.Println(cacheSuccessStyle.Render("✓") + cacheTextStyle.Render(fmt.Sprintf(" Updated Chatuino %s to %s at %s", Version, release.Version(), exePath)))
+		return nil
+	},
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/julez-dev/chatuino/replay"
+	"github.com/julez-dev/chatuino/ui/mainui"
+	"github.com/julez-dev/chatuino/vodchat"
+	"github.com/urfave/cli/v3"
+)
+
+var vodCMD = &cli.Command{
+	Name:      "vod",
+	Usage:     "Watch the chat replay of a Twitch VOD (experimental)",
+	ArgsUsage: "<video ID or link>",
+	Description: "Download the chat replay of a VOD and replay it in a read only tab, from --start at --speed or in sync with mpv. " +
+		"Start mpv with --input-ipc-server=<socket> and pass the same socket with --mpv-socket. " +
+		"The chat is downloaded from the unofficial API of the Twitch website, which may break at any time.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "speed",
+			Usage: "Replay speed: 1x, 2x or max, any positive factor like 0.5x is accepted",
+			Value: "1x",
+		},
+		&cli.StringFlag{
+			Name:  "start",
+			Usage: "Position in the VOD the replay starts at, like 10m or 1:02:03",
+		},
+		&cli.StringFlag{
+			Name:  "mpv-socket",
+			Usage: "IPC socket of mpv, the chat follows the playback position of mpv",
+		},
+	},
+	Action: func(ctx context.Context, command *cli.Command) error {
+		videoID, err := vodchat.ParseVideoID(command.Args().First())
+		if err != nil {
+			return err
+		}
+
+		speed, err := replay.ParseSpeed(command.String("speed"))
+		if err != nil {
+			return err
+		}
+
+		var offset time.Duration
+		if start := command.String("start"); start != "" {
+			if offset, err = replay.ParsePosition(start); err != nil {
+				return fmt.Errorf("invalid --start: %w", err)
+			}
+		}
+
+		var player mainui.Player
+		if socket := command.String("mpv-socket"); socket != "" {
+			if command.String("start") != "" {
+				return fmt.Errorf("--start can't be used with --mpv-socket, the chat follows the position of mpv")
+			}
+
+			player = vodchat.NewMPV(socket)
+		}
+
+		video, err := vodchat.New(http.DefaultClient).Download(ctx, videoID, func(comments int) {
+			fmt.Fprintf(os.Stderr, "\rDownloading chat of VOD %s: %d messages", videoID, comments)
+		})
+		fmt.Fprintln(os.Stderr)
+
+		if err != nil {
+			return fmt.Errorf("failed to download chat: %w", err)
+		}
+
+		return runChatuino(ctx, command, &mainui.Replay{
+			Name:     "VOD " + video.ID,
+			Messages: video.Messages,
+			Speed:    speed,
+			Start:    video.Start,
+			Offset:   offset,
+			Player:   player,
+		})
+	},
+}
@@ -0,0 +1,68 @@
+package vodchat
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+// MPV reads the playback position of mpv through its JSON IPC socket, started with mpv --input-ipc-server=<socket>
+type MPV struct {
+	socket string
+}
+
+func NewMPV(socket string) *MPV {
+	return &MPV{socket: socket}
+}
+
+func (m *MPV) Name() string {
+	return "mpv"
+}
+
+// Position returns the playback position of the current video
+func (m *MPV) Position(ctx context.Context) (time.Duration, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", m.socket)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to mpv: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	const requestID = 1
+	if _, err := fmt.Fprintf(conn, `{"command": ["get_property", "playback-time"], "request_id": %d}`+"\n", requestID); err != nil {
+		return 0, fmt.Errorf("failed to query mpv: %w", err)
+	}
+
+	// events are sent on the same socket, the reply is the line with the request ID
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var reply struct {
+			RequestID int     `json:"request_id"`
+			Error     string  `json:"error"`
+			Data      float64 `json:"data"`
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil || reply.RequestID != requestID {
+			continue
+		}
+
+		if reply.Error != "success" {
+			return 0, fmt.Errorf("mpv: %s", reply.Error)
+		}
+
+		return time.Duration(reply.Data * float64(time.Second)), nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read mpv reply: %w", err)
+	}
+
+	return 0, fmt.Errorf("mpv closed the connection")
+}
@@ -0,0 +1,52 @@
+package vodchat
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPV_Position(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "mpv.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		var req struct {
+			Command []string `json:"command"`
+		}
+		_ = json.Unmarshal([]byte(line), &req)
+
+		// events are sent before the reply
+		_, _ = io.WriteString(conn, `{"event": "playback-restart"}`+"\n")
+		_, _ = fmt.Fprintf(conn, `{"data": 62.5, "error": "success", "request_id": 1, "command": %q}`+"\n", req.Command[1])
+	}()
+
+	position, err := NewMPV(socket).Position(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 62500*time.Millisecond, position)
+
+	_, err = NewMPV(filepath.Join(t.TempDir(), "missing.sock")).Position(t.Context())
+	require.Error(t, err)
+}
@@ -0,0 +1,282 @@
+// Package vodchat downloads the chat replay of Twitch VODs from the public GraphQL API used by the Twitch website,
+// and reads the playback position of mpv to replay the chat in sync with the video.
+package vodchat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/julez-dev/chatuino/replay"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const (
+	gqlURL = "https://gql.twitch.tv/gql"
+
+	// client ID and persisted query of the Twitch website, the API is not documented and may change
+	webClientID   = "kimne78kx3ncx6brgo4mv6wki5h1ko"
+	commentsQuery = "b70a3591ff0f4e0313d126c6a1502d79a1c02baebb288227c582044aa76adf6a"
+)
+
+var videoIDPattern = regexp.MustCompile(`^(?:(?:https?://)?(?:www\.|m\.)?twitch\.tv/videos/)?v?(\d+)(?:[/?#].*)?$`)
+
+// Video is a VOD with its chat replay
+type Video struct {
+	ID        string
+	ChannelID string
+	Start     time.Time        // time the VOD starts at, the messages were sent at Start plus their offset in the VOD
+	Messages  []replay.Message // oldest first
+}
+
+// Client downloads chat replays of VODs
+type Client struct {
+	client *http.Client
+	gqlURL string
+}
+
+func New(client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Client{client: client, gqlURL: gqlURL}
+}
+
+// ParseVideoID returns the ID of a VOD given as ID or link, like https://www.twitch.tv/videos/123456789
+func ParseVideoID(value string) (string, error) {
+	match := videoIDPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return "", fmt.Errorf("invalid VOD %q, expected a video ID or link", value)
+	}
+
+	return match[1], nil
+}
+
+type comment struct {
+	ID        string `json:"id"`
+	Commenter *struct {
+		ID          string `json:"id"`
+		Login       string `json:"login"`
+		DisplayName string `json:"displayName"`
+	} `json:"commenter"` // nil for deleted users
+	ContentOffsetSeconds int       `json:"contentOffsetSeconds"`
+	CreatedAt            time.Time `json:"createdAt"`
+	Message              struct {
+		Fragments []struct {
+			Text  string `json:"text"`
+			Emote *struct {
+				EmoteID string `json:"emoteID"`
+			} `json:"emote"`
+		} `json:"fragments"`
+		UserBadges []struct {
+			SetID   string `json:"setID"`
+			Version string `json:"version"`
+		} `json:"userBadges"`
+		UserColor string `json:"userColor"`
+	} `json:"message"`
+}
+
+type commentsResponse struct {
+	Data struct {
+		Video *struct {
+			ID      string `json:"id"`
+			Creator *struct {
+				ID string `json:"id"`
+			} `json:"creator"`
+			Comments *struct {
+				Edges []struct {
+					Cursor string  `json:"cursor"`
+					Node   comment `json:"node"`
+				} `json:"edges"`
+				PageInfo struct {
+					HasNextPage bool `json:"hasNextPage"`
+				} `json:"pageInfo"`
+			} `json:"comments"`
+		} `json:"video"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Download downloads the whole chat replay of a VOD, progress is called with the number of comments downloaded so far
+func (c *Client) Download(ctx context.Context, videoID string, progress func(comments int)) (Video, error) {
+	video := Video{ID: videoID}
+
+	var cursor string
+	for {
+		page, err := c.comments(ctx, videoID, cursor)
+		if err != nil {
+			return Video{}, err
+		}
+
+		if page.Data.Video == nil {
+			return Video{}, fmt.Errorf("VOD %s not found", videoID)
+		}
+
+		if page.Data.Video.Creator != nil {
+			video.ChannelID = page.Data.Video.Creator.ID
+		}
+
+		comments := page.Data.Video.Comments
+		if comments == nil {
+			break
+		}
+
+		for _, edge := range comments.Edges {
+			offset := time.Duration(edge.Node.ContentOffsetSeconds) * time.Second
+
+			// the start is derived from the first comment, as the offset is relative to it
+			if video.Start.IsZero() {
+				video.Start = edge.Node.CreatedAt.Add(-offset)
+			}
+
+			video.Messages = append(video.Messages, replay.Message{
+				SentAt:  video.Start.Add(offset),
+				Message: convert(video.ChannelID, edge.Node),
+			})
+
+			cursor = edge.Cursor
+		}
+
+		if progress != nil {
+			progress(len(video.Messages))
+		}
+
+		if !comments.PageInfo.HasNextPage || len(comments.Edges) == 0 {
+			break
+		}
+	}
+
+	if len(video.Messages) == 0 {
+		return Video{}, fmt.Errorf("VOD %s has no chat replay", videoID)
+	}
+
+	return video, nil
+}
+
+func (c *Client) comments(ctx context.Context, videoID, cursor string) (commentsResponse, error) {
+	variables := map[string]any{"videoID": videoID}
+	if cursor == "" {
+		variables["contentOffsetSeconds"] = 0
+	} else {
+		variables["cursor"] = cursor
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"operationName": "VideoCommentsByOffsetOrCursor",
+		"variables":     variables,
+		"extensions": map[string]any{
+			"persistedQuery": map[string]any{"version": 1, "sha256Hash": commentsQuery},
+		},
+	})
+	if err != nil {
+		return commentsResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.gqlURL, bytes.NewReader(body))
+	if err != nil {
+		return commentsResponse{}, err
+	}
+
+	req.Header.Set("Client-Id", webClientID)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return commentsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return commentsResponse{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var page commentsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return commentsResponse{}, fmt.Errorf("failed to decode comments: %w", err)
+	}
+
+	if len(page.Errors) > 0 {
+		return commentsResponse{}, fmt.Errorf("failed to fetch comments: %s", page.Errors[0].Message)
+	}
+
+	return page, nil
+}
+
+// convert maps a comment onto a chat message, emote positions count runes like the IRC emotes tag
+func convert(channelID string, c comment) *twitchirc.PrivateMessage {
+	msg := &twitchirc.PrivateMessage{
+		ID:        c.ID,
+		RoomID:    channelID,
+		Color:     c.Message.UserColor,
+		TMISentTS: c.CreatedAt,
+	}
+
+	if c.Commenter != nil {
+		msg.UserID = c.Commenter.ID
+		msg.LoginName = c.Commenter.Login
+		msg.DisplayName = c.Commenter.DisplayName
+	}
+
+	var (
+		text     strings.Builder
+		position int
+		emotes   = map[string]*twitchirc.Emote{}
+	)
+
+	for _, fragment := range c.Message.Fragments {
+		length := utf8.RuneCountInString(fragment.Text)
+
+		if fragment.Emote != nil && length > 0 {
+			e, ok := emotes[fragment.Emote.EmoteID]
+			if !ok {
+				e = &twitchirc.Emote{ID: fragment.Emote.EmoteID}
+				emotes[fragment.Emote.EmoteID] = e
+			}
+
+			e.Positions = append(e.Positions, twitchirc.EmotePosition{Start: position, End: position + length - 1})
+		}
+
+		text.WriteString(fragment.Text)
+		position += length
+	}
+
+	msg.Message = text.String()
+
+	// keep the order of the first use, like Twitch does
+	for _, fragment := range c.Message.Fragments {
+		if fragment.Emote == nil {
+			continue
+		}
+
+		if e, ok := emotes[fragment.Emote.EmoteID]; ok {
+			msg.Emotes = append(msg.Emotes, *e)
+			delete(emotes, fragment.Emote.EmoteID)
+		}
+	}
+
+	for _, badge := range c.Message.UserBadges {
+		msg.Badges = append(msg.Badges, twitchirc.Badge{Name: badge.SetID, Version: badge.Version})
+
+		switch badge.SetID {
+		case "moderator":
+			msg.Mod = true
+		case "vip":
+			msg.VIP = true
+		case "subscriber":
+			msg.Subscriber = true
+		}
+	}
+
+	return msg
+}
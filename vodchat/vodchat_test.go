@@ -0,0 +1,123 @@
+package vodchat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVideoID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		"id":          {in: "2012345678", want: "2012345678"},
+		"prefixed":    {in: "v2012345678", want: "2012345678"},
+		"link":        {in: "https://www.twitch.tv/videos/2012345678", want: "2012345678"},
+		"link-params": {in: "twitch.tv/videos/2012345678?t=1h2m3s", want: "2012345678"},
+		"channel":     {in: "https://www.twitch.tv/lirik", wantErr: true},
+		"empty":       {in: "", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseVideoID(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_convert(t *testing.T) {
+	t.Parallel()
+
+	var c comment
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "c1", "commenter": {"id": "10", "login": "viewer", "displayName": "Viewer"}, "contentOffsetSeconds": 62, "createdAt": "2026-03-10T12:01:02Z",
+		"message": {
+			"fragments": [{"text": "ä ", "emote": null}, {"text": "Kappa", "emote": {"emoteID": "25"}}, {"text": " hi ", "emote": null}, {"text": "Kappa", "emote": {"emoteID": "25"}}],
+			"userBadges": [{"setID": "moderator", "version": "1"}, {"setID": "subscriber", "version": "12"}],
+			"userColor": "#FF0000"
+		}
+	}`), &c))
+
+	msg := convert("22484632", c)
+	require.Equal(t, "ä Kappa hi Kappa", msg.Message)
+	require.Equal(t, []twitchirc.Emote{{ID: "25", Positions: []twitchirc.EmotePosition{{Start: 2, End: 6}, {Start: 11, End: 15}}}}, msg.Emotes)
+	require.Equal(t, "22484632", msg.RoomID)
+	require.Equal(t, "Viewer", msg.DisplayName)
+	require.Equal(t, "#FF0000", msg.Color)
+	require.True(t, msg.Mod)
+	require.True(t, msg.Subscriber)
+	require.Equal(t, []twitchirc.Badge{{Name: "moderator", Version: "1"}, {Name: "subscriber", Version: "12"}}, msg.Badges)
+}
+
+func TestClient_Download(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, webClientID, r.Header.Get("Client-Id"))
+
+		var req struct {
+			Variables struct {
+				VideoID string `json:"videoID"`
+				Cursor  string `json:"cursor"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		if req.Variables.VideoID == "404" {
+			_, _ = io.WriteString(w, `{"data": {"video": null}}`)
+			return
+		}
+
+		comment := `{"cursor": "%s", "node": {"id": "%s", "commenter": {"id": "10", "login": "viewer", "displayName": "Viewer"}, "contentOffsetSeconds": %d, "createdAt": "%s", "message": {"fragments": [{"text": "hi"}]}}}`
+
+		switch req.Variables.Cursor {
+		case "":
+			_, _ = fmt.Fprintf(w, `{"data": {"video": {"id": "1", "creator": {"id": "22484632"}, "comments": {"edges": [%s], "pageInfo": {"hasNextPage": true}}}}}`,
+				fmt.Sprintf(comment, "cursor-1", "c1", 10, "2026-03-10T12:00:10Z"))
+		case "cursor-1":
+			_, _ = fmt.Fprintf(w, `{"data": {"video": {"id": "1", "creator": {"id": "22484632"}, "comments": {"edges": [%s], "pageInfo": {"hasNextPage": false}}}}}`,
+				fmt.Sprintf(comment, "cursor-2", "c2", 75, "2026-03-10T12:01:15Z"))
+		default:
+			t.Errorf("unexpected cursor %q", req.Variables.Cursor)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := New(srv.Client())
+	client.gqlURL = srv.URL
+
+	_, err := client.Download(t.Context(), "404", nil)
+	require.ErrorContains(t, err, "not found")
+
+	var progress []int
+	video, err := client.Download(t.Context(), "1", func(comments int) {
+		progress = append(progress, comments)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, progress)
+	require.Equal(t, "22484632", video.ChannelID)
+	require.Equal(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), video.Start)
+	require.Len(t, video.Messages, 2)
+	require.Equal(t, 75*time.Second, video.Messages[1].SentAt.Sub(video.Start))
+	require.Equal(t, "22484632", video.Messages[1].Message.(*twitchirc.PrivateMessage).RoomID)
+}
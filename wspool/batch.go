@@ -0,0 +1,60 @@
+package wspool
+
+import (
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// IRCEventBatch contains the IRC events received during one batch interval, oldest first
+type IRCEventBatch []IRCEvent
+
+// Batcher collects IRC events and sends them to the UI at most once per interval as IRCEventBatch,
+// so busy chats don't update and re-render the UI for every single message. Other messages are sent right away.
+type Batcher struct {
+	send     func(tea.Msg)
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending IRCEventBatch
+}
+
+// NewBatcher creates a batcher sending to send. An interval of 0 disables batching.
+// Typically: pool.SetSend(wspool.NewBatcher(program.Send, interval).Send)
+func NewBatcher(send func(tea.Msg), interval time.Duration) *Batcher {
+	return &Batcher{
+		send:     send,
+		interval: interval,
+	}
+}
+
+// Send queues IRC events until the end of the current interval, other messages are passed through.
+func (b *Batcher) Send(msg tea.Msg) {
+	event, ok := msg.(IRCEvent)
+	if !ok || b.interval <= 0 {
+		b.send(msg)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, event)
+
+	// the first event of a batch starts the interval
+	if len(b.pending) == 1 {
+		time.AfterFunc(b.interval, b.flush)
+	}
+}
+
+func (b *Batcher) flush() {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(batch) > 0 {
+		b.send(batch)
+	}
+}
@@ -0,0 +1,61 @@
+package wspool
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		sent []tea.Msg
+	)
+
+	received := func() []tea.Msg {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]tea.Msg(nil), sent...)
+	}
+
+	b := NewBatcher(func(msg tea.Msg) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, msg)
+	}, 20*time.Millisecond)
+
+	first := IRCEvent{AccountID: "1", Message: &twitchirc.PrivateMessage{ID: "a"}}
+	second := IRCEvent{AccountID: "1", Message: &twitchirc.PrivateMessage{ID: "b"}}
+
+	b.Send(first)
+	b.Send(EventSubEvent{AccountID: "1"})
+	b.Send(second)
+
+	// other messages are not delayed
+	require.Equal(t, []tea.Msg{EventSubEvent{AccountID: "1"}}, received())
+
+	require.Eventually(t, func() bool { return len(received()) == 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, IRCEventBatch{first, second}, received()[1])
+
+	// a new batch is started after the flush
+	b.Send(first)
+	require.Eventually(t, func() bool { return len(received()) == 3 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, IRCEventBatch{first}, received()[2])
+}
+
+func TestBatcher_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var sent []tea.Msg
+	b := NewBatcher(func(msg tea.Msg) { sent = append(sent, msg) }, 0)
+
+	event := IRCEvent{AccountID: "1", Message: &twitchirc.PrivateMessage{ID: "a"}}
+	b.Send(event)
+	require.Equal(t, []tea.Msg{event}, sent)
+}
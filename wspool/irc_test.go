@@ -249,6 +249,50 @@ func TestIRCConn_Reconnect(t *testing.T) {
 	require.GreaterOrEqual(t, errorCount.Load(), int32(1), "should emit error on disconnect")
 }
 
+func TestIRCConn_RequestedReconnect(t *testing.T) {
+	t.Parallel()
+
+	var connectCount atomic.Int32
+
+	server := newTestIRCServer(t, func(ws *websocket.Conn) {
+		connectCount.Add(1)
+
+		// Read auth, then keep reading until the client closes the connection
+		for {
+			if _, _, err := ws.Read(context.Background()); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	accounts := &mockAccountProvider{
+		account: save.Account{ID: "123", DisplayName: "testuser", AccessToken: "token"},
+	}
+
+	var errorCount atomic.Int32
+	sendFn := func(msg tea.Msg) {
+		if evt, ok := msg.(IRCEvent); ok && evt.Error != nil {
+			errorCount.Add(1)
+		}
+	}
+
+	conn := newIRCConn("123", accounts, zerolog.Nop(), sendFn)
+	conn.WSURL = wsURL(server)
+
+	go conn.Run()
+	defer conn.Close()
+
+	require.Eventually(t, func() bool { return conn.Health().Connected }, time.Second, 10*time.Millisecond)
+	require.False(t, conn.Health().LastMessage.IsZero())
+
+	conn.Reconnect()
+
+	// reconnects without the 5s delay
+	require.Eventually(t, func() bool { return connectCount.Load() == 2 && conn.Health().Connected }, 2*time.Second, 10*time.Millisecond)
+	require.Zero(t, errorCount.Load(), "requested reconnect is not an error")
+}
+
 func TestIRCConn_ChannelRejoin(t *testing.T) {
 	t.Parallel()
 
@@ -36,6 +36,10 @@ type Pool struct {
 
 	closed bool
 
+	traffic    *twitchirc.Traffic // optional, records the raw lines of IRC connections
+	membership bool               // request the JOIN and PART messages of chatters
+	httpClient *http.Client       // optional, used to dial the connections
+
 	// For testing: override default WebSocket URLs
 	ircWSURL      string
 	eventSubWSURL string
@@ -61,6 +65,34 @@ func (p *Pool) SetSend(send func(tea.Msg)) {
 	p.send = send
 }
 
+// SetTraffic records the raw lines of IRC connections created afterwards in traffic
+func (p *Pool) SetTraffic(traffic *twitchirc.Traffic) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.traffic = traffic
+}
+
+// SetMembership requests the JOIN and PART messages of chatters on IRC connections created afterwards
+func (p *Pool) SetMembership(membership bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.membership = membership
+}
+
+// SetHTTPClient dials connections created afterwards with client, e.g. to connect through a proxy
+func (p *Pool) SetHTTPClient(client *http.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.httpClient = client
+}
+
+// SetIRCURL connects IRC connections created afterwards to url instead of Twitch, used by integration tests with a fake IRC server
+func (p *Pool) SetIRCURL(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ircWSURL = url
+}
+
 // ConnectIRC increments the reference count for an account's IRC connection.
 // Creates a new connection if one doesn't exist.
 func (p *Pool) ConnectIRC(accountID string) error {
@@ -87,6 +119,11 @@ func (p *Pool) ConnectIRC(accountID string) error {
 	if p.ircWSURL != "" {
 		conn.WSURL = p.ircWSURL
 	}
+	conn.Traffic = p.traffic
+	conn.Membership = p.membership
+	if p.httpClient != nil {
+		conn.Transport = p.httpClient.Transport
+	}
 	p.ircConns[accountID] = conn
 	_ = conn.incRef()
 
@@ -130,6 +167,33 @@ func (p *Pool) SendIRC(accountID string, msg twitchirc.IRCer) error {
 	return conn.Send(msg)
 }
 
+// IRCHealth returns the state of an account's IRC connection.
+func (p *Pool) IRCHealth(accountID string) (twitchirc.ConnHealth, bool) {
+	p.mu.RLock()
+	conn, exists := p.ircConns[accountID]
+	p.mu.RUnlock()
+
+	if !exists {
+		return twitchirc.ConnHealth{}, false
+	}
+
+	return conn.Health(), true
+}
+
+// ReconnectIRC drops an account's IRC connection and connects again immediately.
+func (p *Pool) ReconnectIRC(accountID string) error {
+	p.mu.RLock()
+	conn, exists := p.ircConns[accountID]
+	p.mu.RUnlock()
+
+	if !exists {
+		return errors.New("no IRC connection for account")
+	}
+
+	conn.Reconnect()
+	return nil
+}
+
 // JoinChannel joins an IRC channel and tracks it for rejoin on reconnect.
 func (p *Pool) JoinChannel(accountID, channel string) error {
 	p.mu.RLock()
@@ -160,7 +224,7 @@ func (p *Pool) SubscribeEventSub(accountID string, req twitchapi.CreateEventSubS
 
 	conn, exists := p.eventConns[accountID]
 	if !exists {
-		conn = newEventConn(accountID, p.logger, http.DefaultClient, p.send)
+		conn = newEventConn(accountID, p.logger, p.httpClient, p.send)
 		if p.eventSubWSURL != "" {
 			conn.WSURL = p.eventSubWSURL
 		}
@@ -0,0 +1,620 @@
+// Package youtube reads and sends messages of YouTube live chats through the YouTube Data API (experimental).
+// Reading requires an API key, sending requires an OAuth client and a refresh token of the account.
+// Every poll of a chat costs API quota, so chats are polled no faster than the API asks for.
+package youtube
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+)
+
+const (
+	apiURL   = "https://www.googleapis.com/youtube/v3"
+	tokenURL = "https://oauth2.googleapis.com/token"
+)
+
+const (
+	minPollInterval = time.Second * 2  // used when the API asks for faster polls
+	retryInterval   = time.Second * 10 // wait after a failed poll
+	maxPollFailures = 5                // failed polls in a row until the chat is given up
+)
+
+// ErrChatEnded is the reason the messages of a chat are closed when the stream ended
+var ErrChatEnded = errors.New("the live chat ended")
+
+var (
+	videoIDPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	channelIDPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+)
+
+type Config struct {
+	APIKey string
+
+	// the OAuth client and a refresh token with the youtube.force-ssl scope are required to send messages
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
+// APIError is an error response of the YouTube Data API
+type APIError struct {
+	Status  int
+	Reason  string // like liveChatEnded or quotaExceeded
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("YouTube: unexpected status %d: %s", e.Status, e.Message)
+	}
+
+	return fmt.Sprintf("YouTube: %s (%s)", e.Message, e.Reason)
+}
+
+// Client joins YouTube live chats, it implements chatprovider.Provider
+type Client struct {
+	client   *http.Client
+	apiURL   string
+	tokenURL string
+	cfg      Config
+
+	m           sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func New(client *http.Client, cfg Config) (*Client, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("YouTube requires an API key")
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Client{client: client, apiURL: apiURL, tokenURL: tokenURL, cfg: cfg}, nil
+}
+
+func (c *Client) Name() string {
+	return "YouTube"
+}
+
+// Join connects to the live chat of a video ID, a video or channel URL, a channel handle like @name or a channel ID.
+// Channels are looked up by their current live stream.
+func (c *Client) Join(ctx context.Context, channel string) (chatprovider.Chat, error) {
+	target, err := parseTarget(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	videoID := target.videoID
+	if videoID == "" {
+		videoID, err = c.liveVideo(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var resp struct {
+		Items []struct {
+			Snippet struct {
+				Title        string `json:"title"`
+				ChannelTitle string `json:"channelTitle"`
+			} `json:"snippet"`
+			LiveStreamingDetails struct {
+				ActiveLiveChatID string `json:"activeLiveChatId"`
+			} `json:"liveStreamingDetails"`
+		} `json:"items"`
+	}
+
+	if err := c.get(ctx, "/videos", url.Values{"part": {"snippet,liveStreamingDetails"}, "id": {videoID}}, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Items) == 0 {
+		return nil, fmt.Errorf("YouTube video %s not found", videoID)
+	}
+
+	video := resp.Items[0]
+	if video.LiveStreamingDetails.ActiveLiveChatID == "" {
+		return nil, fmt.Errorf("YouTube video %s has no active live chat", videoID)
+	}
+
+	chat := &liveChat{
+		client:   c,
+		id:       video.LiveStreamingDetails.ActiveLiveChatID,
+		channel:  channel,
+		title:    video.Snippet.ChannelTitle + ": " + video.Snippet.Title,
+		messages: make(chan twitchirc.IRCer, 256),
+	}
+
+	go chat.poll(ctx)
+
+	return chat, nil
+}
+
+// target is the video or channel a chat is joined by, only one of the fields is set
+type target struct {
+	videoID   string
+	channelID string
+	handle    string // with the @ prefix
+}
+
+func parseTarget(s string) (target, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "youtube.com/") || strings.HasPrefix(s, "www.youtube.com/") || strings.HasPrefix(s, "youtu.be/") {
+		s = "https://" + s
+	}
+
+	if !strings.Contains(s, "://") {
+		switch {
+		case strings.HasPrefix(s, "@") && len(s) > 1:
+			return target{handle: s}, nil
+		case channelIDPattern.MatchString(s):
+			return target{channelID: s}, nil
+		case videoIDPattern.MatchString(s):
+			return target{videoID: s}, nil
+		}
+
+		return target{}, fmt.Errorf("%q is not a YouTube video ID, URL, handle like @name or channel ID", s)
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return target{}, fmt.Errorf("invalid YouTube URL: %w", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case u.Host == "youtu.be" && videoIDPattern.MatchString(parts[0]):
+		return target{videoID: parts[0]}, nil
+	case parts[0] == "watch" && videoIDPattern.MatchString(u.Query().Get("v")):
+		return target{videoID: u.Query().Get("v")}, nil
+	case len(parts) > 1 && parts[0] == "live" && videoIDPattern.MatchString(parts[1]):
+		return target{videoID: parts[1]}, nil
+	case len(parts) > 1 && parts[0] == "channel" && channelIDPattern.MatchString(parts[1]):
+		return target{channelID: parts[1]}, nil
+	case strings.HasPrefix(parts[0], "@") && len(parts[0]) > 1:
+		return target{handle: parts[0]}, nil
+	}
+
+	return target{}, fmt.Errorf("%q is not a link to a YouTube video or channel", s)
+}
+
+// liveVideo returns the ID of the current live stream of a channel
+func (c *Client) liveVideo(ctx context.Context, t target) (string, error) {
+	channelID := t.channelID
+
+	if t.handle != "" {
+		var resp struct {
+			Items []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+
+		if err := c.get(ctx, "/channels", url.Values{"part": {"id"}, "forHandle": {t.handle}}, &resp); err != nil {
+			return "", err
+		}
+
+		if len(resp.Items) == 0 {
+			return "", fmt.Errorf("YouTube channel %s not found", t.handle)
+		}
+
+		channelID = resp.Items[0].ID
+	}
+
+	var resp struct {
+		Items []struct {
+			ID struct {
+				VideoID string `json:"videoId"`
+			} `json:"id"`
+		} `json:"items"`
+	}
+
+	query := url.Values{"part": {"id"}, "channelId": {channelID}, "eventType": {"live"}, "type": {"video"}}
+	if err := c.get(ctx, "/search", query, &resp); err != nil {
+		return "", err
+	}
+
+	if len(resp.Items) == 0 {
+		return "", fmt.Errorf("YouTube channel %s is not live", cmp.Or(t.handle, channelID))
+	}
+
+	return resp.Items[0].ID.VideoID, nil
+}
+
+// chatMessage is a liveChatMessage resource, https://developers.google.com/youtube/v3/live/docs/liveChatMessages
+type chatMessage struct {
+	ID      string `json:"id"`
+	Snippet struct {
+		Type           string    `json:"type"`
+		PublishedAt    time.Time `json:"publishedAt"`
+		DisplayMessage string    `json:"displayMessage"`
+
+		SuperChatDetails *struct {
+			AmountDisplayString string `json:"amountDisplayString"`
+		} `json:"superChatDetails"`
+		SuperStickerDetails *struct {
+			AmountDisplayString string `json:"amountDisplayString"`
+		} `json:"superStickerDetails"`
+		MessageDeletedDetails *struct {
+			DeletedMessageID string `json:"deletedMessageId"`
+		} `json:"messageDeletedDetails"`
+		UserBannedDetails *struct {
+			BannedUserDetails struct {
+				ChannelID   string `json:"channelId"`
+				DisplayName string `json:"displayName"`
+			} `json:"bannedUserDetails"`
+			BanType            string          `json:"banType"`
+			BanDurationSeconds json.RawMessage `json:"banDurationSeconds"` // uint64, encoded as string
+		} `json:"userBannedDetails"`
+	} `json:"snippet"`
+	AuthorDetails struct {
+		ChannelID       string `json:"channelId"`
+		DisplayName     string `json:"displayName"`
+		IsChatOwner     bool   `json:"isChatOwner"`
+		IsChatModerator bool   `json:"isChatModerator"`
+		IsChatSponsor   bool   `json:"isChatSponsor"`
+	} `json:"authorDetails"`
+}
+
+type messagePage struct {
+	NextPageToken         string        `json:"nextPageToken"`
+	PollingIntervalMillis int           `json:"pollingIntervalMillis"`
+	OfflineAt             string        `json:"offlineAt"`
+	Items                 []chatMessage `json:"items"`
+}
+
+func (c *Client) messages(ctx context.Context, chatID, pageToken string) (messagePage, error) {
+	query := url.Values{"part": {"snippet,authorDetails"}, "liveChatId": {chatID}, "maxResults": {"2000"}}
+	if pageToken != "" {
+		query.Set("pageToken", pageToken)
+	}
+
+	var page messagePage
+	if err := c.get(ctx, "/liveChat/messages", query, &page); err != nil {
+		return messagePage{}, err
+	}
+
+	return page, nil
+}
+
+// convert maps a chat message onto the twitchirc message types, nil if it is not shown
+func convert(channel string, m chatMessage) twitchirc.IRCer {
+	snippet := m.Snippet
+	author := m.AuthorDetails
+
+	switch snippet.Type {
+	case "textMessageEvent", "superChatEvent", "superStickerEvent":
+		text := snippet.DisplayMessage
+
+		switch {
+		case snippet.SuperChatDetails != nil:
+			text = strings.TrimSpace(fmt.Sprintf("[Super Chat %s] %s", snippet.SuperChatDetails.AmountDisplayString, text))
+		case snippet.SuperStickerDetails != nil:
+			text = fmt.Sprintf("[Super Sticker %s]", snippet.SuperStickerDetails.AmountDisplayString)
+		}
+
+		msg := &twitchirc.PrivateMessage{
+			ID:              m.ID,
+			ChannelUserName: channel,
+			DisplayName:     author.DisplayName,
+			LoginName:       author.DisplayName,
+			UserID:          author.ChannelID,
+			Mod:             author.IsChatModerator,
+			Subscriber:      author.IsChatSponsor,
+			TMISentTS:       snippet.PublishedAt,
+			Message:         text,
+		}
+
+		if author.IsChatOwner {
+			msg.Badges = append(msg.Badges, twitchirc.Badge{Name: "broadcaster", Version: "1"})
+		}
+
+		if author.IsChatModerator {
+			msg.Badges = append(msg.Badges, twitchirc.Badge{Name: "moderator", Version: "1"})
+		}
+
+		if author.IsChatSponsor {
+			msg.Badges = append(msg.Badges, twitchirc.Badge{Name: "member", Version: "1"})
+		}
+
+		return msg
+	case "messageDeletedEvent":
+		if snippet.MessageDeletedDetails == nil {
+			return nil
+		}
+
+		return &twitchirc.ClearMessage{
+			ChannelUserName: channel,
+			TargetMsgID:     snippet.MessageDeletedDetails.DeletedMessageID,
+			TMISentTS:       snippet.PublishedAt,
+		}
+	case "userBannedEvent":
+		if snippet.UserBannedDetails == nil {
+			return nil
+		}
+
+		banned := snippet.UserBannedDetails.BannedUserDetails
+		clear := &twitchirc.ClearChat{
+			ChannelUserName: channel,
+			TargetUserID:    &banned.ChannelID,
+			UserName:        &banned.DisplayName,
+			TMISentTS:       snippet.PublishedAt,
+		}
+
+		if snippet.UserBannedDetails.BanType == "temporary" {
+			seconds, err := strconv.Atoi(strings.Trim(string(snippet.UserBannedDetails.BanDurationSeconds), `"`))
+			if err == nil {
+				clear.BanDuration = &seconds
+			}
+		}
+
+		return clear
+	}
+
+	// memberships, polls and other events are shown with their text
+	if snippet.DisplayMessage == "" {
+		return nil
+	}
+
+	return &twitchirc.Notice{
+		ChannelUserName: channel,
+		Message:         snippet.DisplayMessage,
+		MsgID:           twitchirc.MsgID(m.ID),
+		FakeTimestamp:   snippet.PublishedAt,
+	}
+}
+
+// liveChat is a joined live chat, it implements chatprovider.Chat
+type liveChat struct {
+	client  *Client
+	id      string
+	channel string
+	title   string
+
+	messages chan twitchirc.IRCer
+	err      error // set before messages is closed
+}
+
+func (l *liveChat) Title() string {
+	return l.title
+}
+
+func (l *liveChat) Messages() <-chan twitchirc.IRCer {
+	return l.messages
+}
+
+func (l *liveChat) Err() error {
+	return l.err
+}
+
+func (l *liveChat) ReadOnly() bool {
+	return !l.client.canSend()
+}
+
+func (l *liveChat) Send(ctx context.Context, text string) error {
+	if !l.client.canSend() {
+		return chatprovider.ErrReadOnly
+	}
+
+	token, err := l.client.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]any{
+		"snippet": map[string]any{
+			"liveChatId": l.id,
+			"type":       "textMessageEvent",
+			"textMessageDetails": map[string]string{
+				"messageText": text,
+			},
+		},
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.client.apiURL+"/liveChat/messages?part=snippet", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	return l.client.do(req, nil)
+}
+
+// poll sends the messages of the chat until ctx is done or the chat ended
+func (l *liveChat) poll(ctx context.Context) {
+	defer close(l.messages)
+
+	var (
+		pageToken string
+		failures  int
+	)
+
+	for {
+		page, err := l.client.messages(ctx, l.id, pageToken)
+		if ctx.Err() != nil {
+			return
+		}
+
+		wait := retryInterval
+
+		if err != nil {
+			// requests with a client error fail again, like when the chat ended or the quota is exceeded
+			apiErr := &APIError{}
+			if errors.As(err, &apiErr) && apiErr.Status < 500 && apiErr.Status != http.StatusTooManyRequests {
+				if apiErr.Reason == "liveChatEnded" {
+					err = ErrChatEnded
+				}
+
+				l.err = err
+				return
+			}
+
+			failures++
+			if failures >= maxPollFailures {
+				l.err = err
+				return
+			}
+		} else {
+			failures = 0
+			pageToken = page.NextPageToken
+
+			for _, item := range page.Items {
+				if item.Snippet.Type == "chatEndedEvent" {
+					l.err = ErrChatEnded
+					return
+				}
+
+				msg := convert(l.channel, item)
+				if msg == nil {
+					continue
+				}
+
+				select {
+				case l.messages <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if page.OfflineAt != "" {
+				l.err = ErrChatEnded
+				return
+			}
+
+			wait = max(time.Duration(page.PollingIntervalMillis)*time.Millisecond, minPollInterval)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Client) canSend() bool {
+	return c.cfg.ClientID != "" && c.cfg.ClientSecret != "" && c.cfg.RefreshToken != ""
+}
+
+// token returns an access token of the account, refreshed with the refresh token when it expired
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.expiresAt) {
+		return c.accessToken, nil
+	}
+
+	form := url.Values{
+		"client_id":     {c.cfg.ClientID},
+		"client_secret": {c.cfg.ClientSecret},
+		"refresh_token": {c.cfg.RefreshToken},
+		"grant_type":    {"refresh_token"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err := c.do(req, &resp); err != nil {
+		return "", fmt.Errorf("failed to refresh YouTube access token: %w", err)
+	}
+
+	c.accessToken = resp.AccessToken
+	// refresh a minute early, so the token doesn't expire during a request
+	c.expiresAt = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - time.Minute)
+
+	return c.accessToken, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, data any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	// the key is sent as header instead of query parameter, so it isn't part of logged URLs
+	req.Header.Set("X-Goog-Api-Key", c.cfg.APIKey)
+
+	return c.do(req, data)
+}
+
+func (c *Client) do(req *http.Request, data any) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp.StatusCode, body)
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	return json.Unmarshal(body, data)
+}
+
+func apiError(status int, body []byte) *APIError {
+	var resp struct {
+		Error struct {
+			Message string `json:"message"`
+			Errors  []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error.Message == "" {
+		return &APIError{Status: status, Message: strings.TrimSpace(string(body))}
+	}
+
+	apiErr := &APIError{Status: status, Message: resp.Error.Message}
+	if len(resp.Error.Errors) > 0 {
+		apiErr.Reason = resp.Error.Errors[0].Reason
+	}
+
+	return apiErr
+}
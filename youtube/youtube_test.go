@@ -0,0 +1,197 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/julez-dev/chatuino/chatprovider"
+	"github.com/julez-dev/chatuino/twitch/twitchirc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseTarget(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in      string
+		want    target
+		wantErr bool
+	}{
+		"video-id":      {in: "dQw4w9WgXcQ", want: target{videoID: "dQw4w9WgXcQ"}},
+		"watch-url":     {in: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=10", want: target{videoID: "dQw4w9WgXcQ"}},
+		"short-url":     {in: "youtu.be/dQw4w9WgXcQ", want: target{videoID: "dQw4w9WgXcQ"}},
+		"live-url":      {in: "https://youtube.com/live/dQw4w9WgXcQ", want: target{videoID: "dQw4w9WgXcQ"}},
+		"handle":        {in: "@streamer", want: target{handle: "@streamer"}},
+		"handle-url":    {in: "https://www.youtube.com/@streamer/live", want: target{handle: "@streamer"}},
+		"channel-id":    {in: "UCuAXFkgsw1L7xaCfnd5JJOw", want: target{channelID: "UCuAXFkgsw1L7xaCfnd5JJOw"}},
+		"channel-url":   {in: "https://www.youtube.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw", want: target{channelID: "UCuAXFkgsw1L7xaCfnd5JJOw"}},
+		"twitch-name":   {in: "streamer", wantErr: true},
+		"other-url":     {in: "https://www.youtube.com/feed/subscriptions", wantErr: true},
+		"empty-handle":  {in: "@", wantErr: true},
+		"short-video":   {in: "youtu.be/abc", wantErr: true},
+		"watch-missing": {in: "https://www.youtube.com/watch", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseTarget(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_convert(t *testing.T) {
+	t.Parallel()
+
+	var messages []chatMessage
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": "1", "snippet": {"type": "textMessageEvent", "publishedAt": "2025-01-01T12:00:00Z", "displayMessage": "hello"}, "authorDetails": {"channelId": "UC1", "displayName": "@viewer", "isChatModerator": true}},
+		{"id": "2", "snippet": {"type": "superChatEvent", "displayMessage": "thanks", "superChatDetails": {"amountDisplayString": "$5.00"}}, "authorDetails": {"displayName": "@fan"}},
+		{"id": "3", "snippet": {"type": "messageDeletedEvent", "messageDeletedDetails": {"deletedMessageId": "1"}}},
+		{"id": "4", "snippet": {"type": "userBannedEvent", "userBannedDetails": {"bannedUserDetails": {"channelId": "UC1", "displayName": "@viewer"}, "banType": "temporary", "banDurationSeconds": "300"}}},
+		{"id": "5", "snippet": {"type": "newSponsorEvent", "displayMessage": "@fan joined as member"}},
+		{"id": "6", "snippet": {"type": "tombstone"}}
+	]`), &messages))
+
+	privMsg := convert("@streamer", messages[0]).(*twitchirc.PrivateMessage)
+	require.Equal(t, "hello", privMsg.Message)
+	require.Equal(t, "@viewer", privMsg.DisplayName)
+	require.Equal(t, "@streamer", privMsg.ChannelUserName)
+	require.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), privMsg.TMISentTS)
+	require.Equal(t, []twitchirc.Badge{{Name: "moderator", Version: "1"}}, privMsg.Badges)
+
+	require.Equal(t, "[Super Chat $5.00] thanks", convert("@streamer", messages[1]).(*twitchirc.PrivateMessage).Message)
+	require.Equal(t, "1", convert("@streamer", messages[2]).(*twitchirc.ClearMessage).TargetMsgID)
+
+	clearChat := convert("@streamer", messages[3]).(*twitchirc.ClearChat)
+	require.Equal(t, "@viewer", *clearChat.UserName)
+	require.Equal(t, 300, *clearChat.BanDuration)
+
+	require.Equal(t, "@fan joined as member", convert("@streamer", messages[4]).(*twitchirc.Notice).Message)
+	require.Nil(t, convert("@streamer", messages[5]))
+}
+
+func TestClient_Join(t *testing.T) {
+	t.Parallel()
+
+	var polls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "key", r.Header.Get("X-Goog-Api-Key"))
+		require.False(t, r.URL.Query().Has("key"))
+
+		switch r.URL.Path {
+		case "/channels":
+			require.Equal(t, "@streamer", r.URL.Query().Get("forHandle"))
+			_, _ = io.WriteString(w, `{"items": [{"id": "UC1"}]}`)
+		case "/search":
+			require.Equal(t, "UC1", r.URL.Query().Get("channelId"))
+			_, _ = io.WriteString(w, `{"items": [{"id": {"videoId": "dQw4w9WgXcQ"}}]}`)
+		case "/videos":
+			_, _ = io.WriteString(w, `{"items": [{"snippet": {"title": "Speedrun", "channelTitle": "Streamer"}, "liveStreamingDetails": {"activeLiveChatId": "chat"}}]}`)
+		case "/liveChat/messages":
+			require.Equal(t, "chat", r.URL.Query().Get("liveChatId"))
+			polls++
+
+			if polls == 1 {
+				_, _ = io.WriteString(w, `{"nextPageToken": "next", "pollingIntervalMillis": 0, "items": [{"id": "1", "snippet": {"type": "textMessageEvent", "displayMessage": "hello"}, "authorDetails": {"displayName": "@viewer"}}]}`)
+				return
+			}
+
+			require.Equal(t, "next", r.URL.Query().Get("pageToken"))
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"error": {"code": 403, "message": "The live chat is no longer live.", "errors": [{"reason": "liveChatEnded"}]}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := New(srv.Client(), Config{APIKey: "key"})
+	require.NoError(t, err)
+	client.apiURL = srv.URL
+
+	chat, err := client.Join(t.Context(), "@streamer")
+	require.NoError(t, err)
+	require.Equal(t, "Streamer: Speedrun", chat.Title())
+	require.True(t, chat.ReadOnly())
+	require.ErrorIs(t, chat.Send(t.Context(), "hi"), chatprovider.ErrReadOnly)
+
+	var received []twitchirc.IRCer
+	for msg := range chat.Messages() {
+		received = append(received, msg)
+	}
+
+	require.Len(t, received, 1)
+	require.Equal(t, "hello", received[0].(*twitchirc.PrivateMessage).Message)
+	require.ErrorIs(t, chat.Err(), ErrChatEnded)
+}
+
+func TestLiveChat_Send(t *testing.T) {
+	t.Parallel()
+
+	var tokenRequests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			require.Equal(t, url.Values{
+				"client_id":     {"client"},
+				"client_secret": {"secret"},
+				"refresh_token": {"refresh"},
+				"grant_type":    {"refresh_token"},
+			}, r.PostForm)
+
+			tokenRequests++
+			_, _ = io.WriteString(w, `{"access_token": "access", "expires_in": 3600}`)
+		case "/liveChat/messages":
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+
+			var body struct {
+				Snippet struct {
+					LiveChatID         string `json:"liveChatId"`
+					TextMessageDetails struct {
+						MessageText string `json:"messageText"`
+					} `json:"textMessageDetails"`
+				} `json:"snippet"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "chat", body.Snippet.LiveChatID)
+			require.Equal(t, "hello chat", body.Snippet.TextMessageDetails.MessageText)
+
+			_, _ = io.WriteString(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := New(srv.Client(), Config{APIKey: "key", ClientID: "client", ClientSecret: "secret", RefreshToken: "refresh"})
+	require.NoError(t, err)
+	client.apiURL = srv.URL
+	client.tokenURL = srv.URL + "/token"
+
+	chat := &liveChat{client: client, id: "chat"}
+	require.False(t, chat.ReadOnly())
+	require.NoError(t, chat.Send(context.Background(), "hello chat"))
+	require.NoError(t, chat.Send(context.Background(), "hello chat"))
+
+	// the access token is reused until it expires
+	require.Equal(t, 1, tokenRequests)
+}